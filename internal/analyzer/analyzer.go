@@ -1,73 +1,116 @@
 package analyzer
 
 import (
+	"context"
+	"math"
 	"sort"
 	"sync"
 	"time"
 
-	"codecompass/internal/config"
-	"codecompass/internal/git"
-	"codecompass/internal/types"
-	"codecompass/internal/utils"
+	"github.com/xeon-zolt/codecompass/internal/config"
+	"github.com/xeon-zolt/codecompass/internal/git"
+	"github.com/xeon-zolt/codecompass/internal/types"
+	"github.com/xeon-zolt/codecompass/internal/utils"
 )
 
 type Analyzer struct {
-	semaphore *utils.Semaphore
-	mu        *sync.Mutex
+	dir      string
+	blamer   *git.Blamer
+	mu       *sync.Mutex
+	warnings *utils.WarningCollector
+	now      time.Time // What issue ages are measured at
+
+	// firstCommits maps the authors given a grace period, by email, to the
+	// author date of their first commit, and graced has the issues they
+	// added in it
+	firstCommits map[string]time.Time
+	graced       map[string]*types.AuthorStats
 }
 
-func New(semaphore *utils.Semaphore, mu *sync.Mutex) *Analyzer {
+// New creates an analyzer for the repository in dir. File paths on issues are
+// relative to dir. Issues that cannot be attributed to an author are added to
+// warnings.
+func New(dir string, blamer *git.Blamer, mu *sync.Mutex, warnings *utils.WarningCollector) *Analyzer {
 	return &Analyzer{
-		semaphore: semaphore,
-		mu:        mu,
+		dir:      dir,
+		blamer:   blamer,
+		mu:       mu,
+		warnings: warnings,
+		now:      time.Now(),
 	}
 }
 
+// SetFirstCommits sets the author date of the first commit of the authors
+// given a grace period, by email. With cfg.GracePeriodDays set, issues on
+// lines they wrote within that many days of it are also counted in Graced.
+func (a *Analyzer) SetFirstCommits(firstCommits map[string]time.Time) {
+	a.firstCommits = firstCommits
+}
+
+// Graced returns the issues of each author, by email, that were added in
+// their grace period. They are counted in the author stats too, for the
+// author leaderboard to leave out.
+func (a *Analyzer) Graced() map[string]*types.AuthorStats {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.graced
+}
+
 func (a *Analyzer) ProcessIssue(
+	ctx context.Context,
 	issue types.Issue,
 	authorStats map[string]*types.AuthorStats,
 	fileStats map[string]*types.FileStats,
 	ruleStats map[string]*types.RuleStats,
-	warningLogs *[]string,
 ) error {
-	return a.processIssueInternal(issue, nil, authorStats, fileStats, ruleStats, warningLogs)
+	return a.processIssueInternal(ctx, issue, nil, authorStats, fileStats, ruleStats)
 }
 
 func (a *Analyzer) ProcessIssueWithConfig(
+	ctx context.Context,
 	issue types.Issue,
 	cfg *config.Config,
 	authorStats map[string]*types.AuthorStats,
 	fileStats map[string]*types.FileStats,
 	ruleStats map[string]*types.RuleStats,
-	warningLogs *[]string,
 ) error {
 	// Check if file should be ignored
-	if cfg != nil && cfg.ShouldIgnoreFile(issue.FilePath) {
+	if cfg != nil && cfg.ShouldIgnoreRepoFile(a.dir, issue.FilePath) {
 		return nil
 	}
 
-	// Check if rule should be ignored
-	if cfg != nil && cfg.ShouldIgnoreRule(issue.RuleID) {
-		return nil
+	// Drop ignored rules and apply the rule severity overrides
+	if cfg != nil {
+		severity, kept := cfg.RuleSeverity(issue.RuleID, issue.Severity)
+		if !kept {
+			return nil
+		}
+		issue.Severity = severity
 	}
 
-	return a.processIssueInternal(issue, cfg, authorStats, fileStats, ruleStats, warningLogs)
+	return a.processIssueInternal(ctx, issue, cfg, authorStats, fileStats, ruleStats)
 }
 
 func (a *Analyzer) processIssueInternal(
+	ctx context.Context,
 	issue types.Issue,
 	cfg *config.Config,
 	authorStats map[string]*types.AuthorStats,
 	fileStats map[string]*types.FileStats,
 	ruleStats map[string]*types.RuleStats,
-	warningLogs *[]string,
 ) error {
-	blameMap, err := git.BlameFile(issue.FilePath, warningLogs, a.mu, a.semaphore)
+	// Sources drop issues for rules that are off; this catches any left
+	if issue.Severity == types.SeverityOff {
+		return nil
+	}
+
+	blameMap, err := a.blamer.BlameFile(ctx, issue.FilePath)
 	if err != nil {
 		return err
 	}
 
 	if len(blameMap) == 0 {
+		a.warnings.Add(types.NewWarning("Issue not attributed", "file", issue.FilePath, "line", issue.Line, "rule", issue.RuleID))
 		return nil
 	}
 
@@ -102,46 +145,49 @@ func (a *Analyzer) processIssueInternal(
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
-	now := time.Now()
-
-	// Update author stats
-	if authorStats[blameInfo.Email] == nil {
-		authorStats[blameInfo.Email] = &types.AuthorStats{
-			Name:      blameInfo.Name,
-			Count:     0,
-			Rules:     make(map[string]int),
-			Files:     make(map[string]int),
-			FirstSeen: now,
-			LastSeen:  now,
+	// Update file stats
+	if fileStats[issue.FilePath] == nil {
+		fileStats[issue.FilePath] = &types.FileStats{
+			Path:       issue.FilePath,
+			Count:      0,
+			Rules:      make(map[string]int),
+			RuleErrors: make(map[string]int),
+			Authors:    make(map[string]int),
 		}
 	}
+	file := fileStats[issue.FilePath]
 
-	stats := authorStats[blameInfo.Email]
-	stats.Name = blameInfo.Name
-	stats.Count++
-	stats.Rules[issue.RuleID]++
-	stats.Files[issue.FilePath]++
-	if issue.Severity == 2 {
-		stats.Errors++
-	} else {
-		stats.Warnings++
+	// Issues past the cap are counted but blamed on no one, so a generated
+	// file cannot crown its last editor the worst author
+	if cfg != nil && cfg.MaxIssuesPerFile > 0 && file.Count >= cfg.MaxIssuesPerFile {
+		file.Overflow++
+		return nil
 	}
-	if now.After(stats.LastSeen) {
-		stats.LastSeen = now
+
+	weight := 0.0
+	if cfg != nil && cfg.DecayHalfLifeDays > 0 {
+		weight = decayWeight(a.now, blameInfo.Time, cfg.DecayHalfLifeDays)
 	}
 
-	// Update file stats
-	if fileStats[issue.FilePath] == nil {
-		fileStats[issue.FilePath] = &types.FileStats{
-			Path:    issue.FilePath,
-			Count:   0,
-			Rules:   make(map[string]int),
-			Authors: make(map[string]int),
+	file.Count++
+	file.Decayed += weight
+	if issue.Severity >= types.SeverityError {
+		file.Errors++
+		file.RuleErrors[issue.RuleID]++
+	} else {
+		file.Warnings++
+	}
+	file.Rules[issue.RuleID]++
+	file.Authors[blameInfo.Email]++
+
+	now := time.Now()
+	addAuthorIssue(authorStats, issue, blameInfo, weight, now)
+	if cfg != nil && a.inGracePeriod(blameInfo, cfg.GracePeriodDays) {
+		if a.graced == nil {
+			a.graced = make(map[string]*types.AuthorStats)
 		}
+		addAuthorIssue(a.graced, issue, blameInfo, weight, now)
 	}
-	fileStats[issue.FilePath].Count++
-	fileStats[issue.FilePath].Rules[issue.RuleID]++
-	fileStats[issue.FilePath].Authors[blameInfo.Email]++
 
 	// Update rule stats
 	if ruleStats[issue.RuleID] == nil {
@@ -153,8 +199,65 @@ func (a *Analyzer) processIssueInternal(
 		}
 	}
 	ruleStats[issue.RuleID].Count++
+	if issue.Fixable {
+		ruleStats[issue.RuleID].FixableCount++
+	}
 	ruleStats[issue.RuleID].Authors[blameInfo.Email]++
 	ruleStats[issue.RuleID].Files[issue.FilePath]++
 
 	return nil
 }
+
+// addAuthorIssue counts issue, on a line blameInfo attributes, in the stats
+// of its author.
+func addAuthorIssue(authorStats map[string]*types.AuthorStats, issue types.Issue, blameInfo types.BlameInfo, weight float64, now time.Time) {
+	if authorStats[blameInfo.Email] == nil {
+		authorStats[blameInfo.Email] = &types.AuthorStats{
+			Name:       blameInfo.Name,
+			Count:      0,
+			Rules:      make(map[string]int),
+			RuleErrors: make(map[string]int),
+			Files:      make(map[string]int),
+			FirstSeen:  now,
+			LastSeen:   now,
+		}
+	}
+
+	stats := authorStats[blameInfo.Email]
+	stats.Name = blameInfo.Name
+	stats.Count++
+	stats.Decayed += weight
+	stats.Rules[issue.RuleID]++
+	stats.Files[issue.FilePath]++
+	if issue.Severity >= types.SeverityError {
+		stats.Errors++
+		stats.RuleErrors[issue.RuleID]++
+	} else {
+		stats.Warnings++
+	}
+	if now.After(stats.LastSeen) {
+		stats.LastSeen = now
+	}
+}
+
+// inGracePeriod reports whether the line blameInfo attributes was written
+// within days of its author's first commit, by author date. Uncommitted
+// lines and authors without a known first commit get no grace period.
+func (a *Analyzer) inGracePeriod(blameInfo types.BlameInfo, days int) bool {
+	if days <= 0 || blameInfo.Time.IsZero() {
+		return false
+	}
+	first, known := a.firstCommits[blameInfo.Email]
+	return known && blameInfo.Time.Before(first.AddDate(0, 0, days))
+}
+
+// decayWeight is how much an issue on a line last changed at changed counts
+// at now: fully for a new line, and half as much every halfLifeDays days
+// after. Lines without a time, such as uncommitted ones, count fully.
+func decayWeight(now, changed time.Time, halfLifeDays float64) float64 {
+	age := now.Sub(changed).Hours() / 24
+	if changed.IsZero() || age <= 0 {
+		return 1
+	}
+	return math.Pow(0.5, age/halfLifeDays)
+}
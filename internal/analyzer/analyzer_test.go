@@ -1,55 +1,70 @@
 package analyzer
 
 import (
-	"os"
-	"os/exec"
+	"context"
 	"sync"
 	"testing"
+	"time"
 
-	"codecompass/internal/config"
-	"codecompass/internal/types"
-	"codecompass/internal/utils"
+	"github.com/xeon-zolt/codecompass/internal/config"
+	"github.com/xeon-zolt/codecompass/internal/git"
+	"github.com/xeon-zolt/codecompass/internal/leaderboard"
+	"github.com/xeon-zolt/codecompass/internal/logging"
+	"github.com/xeon-zolt/codecompass/internal/testutil"
+	"github.com/xeon-zolt/codecompass/internal/types"
+	"github.com/xeon-zolt/codecompass/internal/utils"
 )
 
-func TestProcessIssueWithConfig(t *testing.T) {
-	// Create a temporary directory
-	tmpdir, err := os.MkdirTemp("", "analyzer_test")
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer os.RemoveAll(tmpdir)
+func TestProcessIssueWithConfigCapsIssuesPerFile(t *testing.T) {
+	dir := testutil.NewRepo(t).
+		WithAuthor("Alice", "alice@example.com").
+		Commit("initial commit", map[string]string{"bundle.min.js": "minified\n", "app.js": "app\n"}).
+		Dir()
 
-	// Change to the temporary directory
-	oldwd, err := os.Getwd()
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer os.Chdir(oldwd)
-	os.Chdir(tmpdir)
+	analyzer := New(dir, git.NewBlamer(dir, utils.NewSemaphore(1), logging.Discard(), utils.NewWarningCollector()), &sync.Mutex{}, utils.NewWarningCollector())
 
-	// Initialize a git repository
-	if err := exec.Command("git", "init").Run(); err != nil {
-		t.Fatal(err)
-	}
+	cfg := config.NewConfig()
+	cfg.MaxIssuesPerFile = 3
 
-	// Create a file and commit it
-	content := []byte("test file")
-	if err := os.WriteFile("test.go", content, 0644); err != nil {
-		t.Fatal(err)
-	}
+	authorStats := make(map[string]*types.AuthorStats)
+	fileStats := make(map[string]*types.FileStats)
+	ruleStats := make(map[string]*types.RuleStats)
 
-	if err := exec.Command("git", "add", "test.go").Run(); err != nil {
+	for i := 0; i < 10; i++ {
+		issue := types.Issue{FilePath: "bundle.min.js", Line: 1, RuleID: "no-unused-vars", Severity: types.SeverityError}
+		if err := analyzer.ProcessIssueWithConfig(context.Background(), issue, cfg, authorStats, fileStats, ruleStats); err != nil {
+			t.Fatal(err)
+		}
+	}
+	issue := types.Issue{FilePath: "app.js", Line: 1, RuleID: "no-console", Severity: types.SeverityWarning}
+	if err := analyzer.ProcessIssueWithConfig(context.Background(), issue, cfg, authorStats, fileStats, ruleStats); err != nil {
 		t.Fatal(err)
 	}
 
-	if err := exec.Command("git", "commit", "-m", "initial commit").Run(); err != nil {
-		t.Fatal(err)
+	bundle := fileStats["bundle.min.js"]
+	if bundle.Count != 3 || bundle.Errors != 3 || bundle.Overflow != 7 || bundle.Authors["alice@example.com"] != 3 {
+		t.Errorf("Expected 3 attributed errors and 7 more in bundle.min.js, but got %+v", bundle)
 	}
+	if app := fileStats["app.js"]; app.Count != 1 || app.Warnings != 1 || app.Errors != 0 || app.Overflow != 0 {
+		t.Errorf("Expected the cap to apply per file, but got %+v", app)
+	}
+	if alice := authorStats["alice@example.com"]; alice.Count != 4 || alice.Errors != 3 {
+		t.Errorf("Expected Alice to be blamed for 4 issues, but got %+v", alice)
+	}
+	if rule := ruleStats["no-unused-vars"]; rule.Count != 3 {
+		t.Errorf("Expected 3 no-unused-vars violations to be counted, but got %d", rule.Count)
+	}
+}
+
+func TestProcessIssueWithConfig(t *testing.T) {
+	dir := testutil.NewRepo(t).
+		Commit("initial commit", map[string]string{"test.go": "test file"}).
+		Dir()
 
 	// Create a new analyzer
 	semaphore := utils.NewSemaphore(1)
 	mu := &sync.Mutex{}
-	analyzer := New(semaphore, mu)
+	analyzer := New(dir, git.NewBlamer(dir, semaphore, logging.Discard(), utils.NewWarningCollector()), mu, utils.NewWarningCollector())
 
 	// Create a new config
 	cfg := config.NewConfig()
@@ -66,10 +81,9 @@ func TestProcessIssueWithConfig(t *testing.T) {
 	authorStats := make(map[string]*types.AuthorStats)
 	fileStats := make(map[string]*types.FileStats)
 	ruleStats := make(map[string]*types.RuleStats)
-	warningLogs := make([]string, 0)
 
 	// Process the issue
-	err = analyzer.ProcessIssueWithConfig(issue, cfg, authorStats, fileStats, ruleStats, &warningLogs)
+	err := analyzer.ProcessIssueWithConfig(context.Background(), issue, cfg, authorStats, fileStats, ruleStats)
 	if err != nil {
 		t.Errorf("Error processing issue: %v", err)
 	}
@@ -87,3 +101,201 @@ func TestProcessIssueWithConfig(t *testing.T) {
 		t.Errorf("Expected 1 rule stat, but got %d", len(ruleStats))
 	}
 }
+
+func TestProcessIssueWithConfigCountsFixableIssues(t *testing.T) {
+	dir := testutil.NewRepo(t).
+		Commit("initial commit", map[string]string{"app.js": "app\n"}).
+		Dir()
+
+	analyzer := New(dir, git.NewBlamer(dir, utils.NewSemaphore(1), logging.Discard(), utils.NewWarningCollector()), &sync.Mutex{}, utils.NewWarningCollector())
+
+	authorStats := make(map[string]*types.AuthorStats)
+	fileStats := make(map[string]*types.FileStats)
+	ruleStats := make(map[string]*types.RuleStats)
+
+	for _, issue := range []types.Issue{
+		{FilePath: "app.js", Line: 1, RuleID: "semi", Severity: types.SeverityError, Fixable: true},
+		{FilePath: "app.js", Line: 1, RuleID: "semi", Severity: types.SeverityError, Fixable: true},
+		{FilePath: "app.js", Line: 1, RuleID: "semi", Severity: types.SeverityError},
+		{FilePath: "app.js", Line: 1, RuleID: "no-undef", Severity: types.SeverityError},
+	} {
+		if err := analyzer.ProcessIssueWithConfig(context.Background(), issue, config.NewConfig(), authorStats, fileStats, ruleStats); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if semi := ruleStats["semi"]; semi.Count != 3 || semi.FixableCount != 2 {
+		t.Errorf("Expected 2 of 3 semi issues to be fixable, but got %+v", semi)
+	}
+	if undef := ruleStats["no-undef"]; undef.Count != 1 || undef.FixableCount != 0 {
+		t.Errorf("Expected no fixable no-undef issues, but got %+v", undef)
+	}
+}
+
+func TestProcessIssueWithConfigOverridesRuleSeverities(t *testing.T) {
+	dir := testutil.NewRepo(t).
+		WithAuthor("Alice", "alice@example.com").
+		Commit("initial commit", map[string]string{"app.js": "app\n"}).
+		Dir()
+
+	analyzer := New(dir, git.NewBlamer(dir, utils.NewSemaphore(1), logging.Discard(), utils.NewWarningCollector()), &sync.Mutex{}, utils.NewWarningCollector())
+
+	cfg := config.NewConfig()
+	cfg.IgnoredRules = []string{"no-alert"}
+	cfg.RuleSeverityOverrides = []config.RuleSeverityOverride{
+		{Pattern: "no-console", Severity: "error"},
+		{Pattern: "no-alert", Severity: "error"},
+		{Pattern: "react/*", Severity: "warning"},
+		{Pattern: "import/*", Severity: "ignore"},
+	}
+
+	authorStats := make(map[string]*types.AuthorStats)
+	fileStats := make(map[string]*types.FileStats)
+	ruleStats := make(map[string]*types.RuleStats)
+
+	for _, issue := range []types.Issue{
+		{FilePath: "app.js", Line: 1, RuleID: "no-console", Severity: types.SeverityWarning},
+		{FilePath: "app.js", Line: 1, RuleID: "no-alert", Severity: types.SeverityWarning},
+		{FilePath: "app.js", Line: 1, RuleID: "react/jsx-key", Severity: types.SeverityError},
+		{FilePath: "app.js", Line: 1, RuleID: "import/order", Severity: types.SeverityError},
+		{FilePath: "app.js", Line: 1, RuleID: "eqeqeq", Severity: types.SeverityError},
+	} {
+		if err := analyzer.ProcessIssueWithConfig(context.Background(), issue, cfg, authorStats, fileStats, ruleStats); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// no-console is bumped to an error and react/jsx-key downgraded to a
+	// warning; no-alert is ignored despite its override, and import/order
+	// is dropped by its own
+	if alice := authorStats["alice@example.com"]; alice.Count != 3 || alice.Errors != 2 || alice.Warnings != 1 {
+		t.Errorf("Expected 2 errors and 1 warning for Alice, but got %+v", alice)
+	}
+	if app := fileStats["app.js"]; app.Errors != 2 || app.Warnings != 1 {
+		t.Errorf("Expected 2 errors and 1 warning in app.js, but got %+v", app)
+	}
+	for _, rule := range []string{"no-alert", "import/order"} {
+		if ruleStats[rule] != nil {
+			t.Errorf("Expected %s to be dropped, but got %+v", rule, ruleStats[rule])
+		}
+	}
+}
+
+func TestProcessIssueWithConfigDecaysOldIssues(t *testing.T) {
+	recent := testutil.StartDate.AddDate(2, 0, 0)
+	dir := testutil.NewRepo(t).
+		WithAuthor("Alice", "alice@example.com").
+		Commit("old code", map[string]string{"old.js": "old\n"}).
+		WithAuthor("Bob", "bob@example.com").
+		At(recent).
+		Commit("new code", map[string]string{"new.js": "new\n"}).
+		Dir()
+
+	analyzer := New(dir, git.NewBlamer(dir, utils.NewSemaphore(1), logging.Discard(), utils.NewWarningCollector()), &sync.Mutex{}, utils.NewWarningCollector())
+	analyzer.now = recent.Add(24 * time.Hour)
+
+	cfg := config.NewConfig()
+	cfg.DecayHalfLifeDays = 90
+
+	authorStats := make(map[string]*types.AuthorStats)
+	fileStats := make(map[string]*types.FileStats)
+	ruleStats := make(map[string]*types.RuleStats)
+
+	// Alice has more issues, but on lines two years old
+	for _, issue := range []types.Issue{
+		{FilePath: "old.js", Line: 1, RuleID: "eqeqeq", Severity: types.SeverityError},
+		{FilePath: "old.js", Line: 1, RuleID: "eqeqeq", Severity: types.SeverityError},
+		{FilePath: "old.js", Line: 1, RuleID: "eqeqeq", Severity: types.SeverityError},
+		{FilePath: "new.js", Line: 1, RuleID: "eqeqeq", Severity: types.SeverityError},
+		{FilePath: "new.js", Line: 1, RuleID: "eqeqeq", Severity: types.SeverityError},
+	} {
+		if err := analyzer.ProcessIssueWithConfig(context.Background(), issue, cfg, authorStats, fileStats, ruleStats); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	alice, bob := authorStats["alice@example.com"], authorStats["bob@example.com"]
+	if alice.Count != 3 || bob.Count != 2 {
+		t.Errorf("Expected raw counts of 3 and 2, but got %d and %d", alice.Count, bob.Count)
+	}
+	if alice.Decayed >= 0.05 || bob.Decayed < 1.9 || bob.Decayed > 2 {
+		t.Errorf("Expected Alice's issues to decay away and Bob's to stay, but got %.3f and %.3f", alice.Decayed, bob.Decayed)
+	}
+
+	entries := leaderboard.GenerateAuthorLeaderboard(authorStats, 10, nil)
+	if entries[0].Email != "bob@example.com" || entries[0].Count != 2 {
+		t.Errorf("Expected Bob to rank first by decayed score, but got %+v", entries)
+	}
+	files := leaderboard.GenerateFileLeaderboard(fileStats, 10, leaderboard.FileSortIssues, nil)
+	if files[0].Path != "new.js" {
+		t.Errorf("Expected new.js to rank first by decayed score, but got %+v", files)
+	}
+
+	// Without a half-life issues count the same whatever their age
+	cfg.DecayHalfLifeDays = 0
+	authorStats = make(map[string]*types.AuthorStats)
+	for _, issue := range []types.Issue{
+		{FilePath: "old.js", Line: 1, RuleID: "eqeqeq", Severity: types.SeverityError},
+		{FilePath: "old.js", Line: 1, RuleID: "eqeqeq", Severity: types.SeverityError},
+		{FilePath: "new.js", Line: 1, RuleID: "eqeqeq", Severity: types.SeverityError},
+	} {
+		if err := analyzer.ProcessIssueWithConfig(context.Background(), issue, cfg, authorStats, make(map[string]*types.FileStats), ruleStats); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if entries := leaderboard.GenerateAuthorLeaderboard(authorStats, 10, nil); entries[0].Email != "alice@example.com" || entries[0].DecayedScore != 0 {
+		t.Errorf("Expected Alice to rank first by raw count, but got %+v", entries)
+	}
+}
+
+func TestProcessIssueWithConfigGracePeriod(t *testing.T) {
+	dir := testutil.NewRepo(t).
+		WithAuthor("Alice", "alice@example.com").
+		Commit("first commit", map[string]string{"first.js": "first\n"}).
+		At(testutil.StartDate.AddDate(0, 2, 0)).
+		Commit("later commit", map[string]string{"later.js": "later\n"}).
+		WithAuthor("Bob", "bob@example.com").
+		Commit("bob's commit", map[string]string{"bob.js": "bob\n"}).
+		Dir()
+
+	analyzer := New(dir, git.NewBlamer(dir, utils.NewSemaphore(1), logging.Discard(), utils.NewWarningCollector()), &sync.Mutex{}, utils.NewWarningCollector())
+	analyzer.SetFirstCommits(map[string]time.Time{"alice@example.com": testutil.StartDate})
+
+	cfg := config.NewConfig()
+	cfg.GracePeriodDays = 30
+
+	authorStats := make(map[string]*types.AuthorStats)
+	for _, path := range []string{"first.js", "later.js", "bob.js"} {
+		issue := types.Issue{FilePath: path, Line: 1, RuleID: "eqeqeq", Severity: types.SeverityError}
+		if err := analyzer.ProcessIssueWithConfig(context.Background(), issue, cfg, authorStats, make(map[string]*types.FileStats), make(map[string]*types.RuleStats)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Only the line Alice wrote in her first 30 days is graced, and Bob
+	// has no grace period
+	graced := analyzer.Graced()
+	if len(graced) != 1 || graced["alice@example.com"] == nil || graced["alice@example.com"].Files["first.js"] != 1 || graced["alice@example.com"].Count != 1 {
+		t.Errorf("Expected Alice's issue in first.js graced, but got %+v", graced)
+	}
+	if authorStats["alice@example.com"].Count != 2 || authorStats["bob@example.com"].Count != 1 {
+		t.Errorf("Expected the author stats to count every issue, but got %+v", authorStats)
+	}
+
+	ranked := leaderboard.WithoutGracedIssues(authorStats, graced)
+	if alice := ranked["alice@example.com"]; alice.Count != 1 || alice.Files["first.js"] != 0 || alice.Files["later.js"] != 1 {
+		t.Errorf("Expected Alice ranked on later.js only, but got %+v", alice)
+	}
+
+	// Off by default
+	cfg.GracePeriodDays = 0
+	analyzer = New(dir, git.NewBlamer(dir, utils.NewSemaphore(1), logging.Discard(), utils.NewWarningCollector()), &sync.Mutex{}, utils.NewWarningCollector())
+	analyzer.SetFirstCommits(map[string]time.Time{"alice@example.com": testutil.StartDate})
+	issue := types.Issue{FilePath: "first.js", Line: 1, RuleID: "eqeqeq", Severity: types.SeverityError}
+	if err := analyzer.ProcessIssueWithConfig(context.Background(), issue, cfg, make(map[string]*types.AuthorStats), make(map[string]*types.FileStats), make(map[string]*types.RuleStats)); err != nil {
+		t.Fatal(err)
+	}
+	if graced := analyzer.Graced(); len(graced) != 0 {
+		t.Errorf("Expected nothing graced without a grace period, but got %+v", graced)
+	}
+}
@@ -0,0 +1,120 @@
+// Package cerrors defines the errors CodeCompass returns for problems a
+// caller can act on, so they can be told apart with errors.Is and errors.As.
+package cerrors
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrNotARepo is returned when a directory is not inside a git work tree.
+var ErrNotARepo = errors.New("not a git repository")
+
+// ErrNoCommits is returned for analyses that need git history when the
+// repository has no commits yet.
+var ErrNoCommits = errors.New("repository has no commits yet")
+
+// ErrNoTags is returned when a repository has no tag reachable from HEAD to
+// scope an analysis to the changes since.
+var ErrNoTags = errors.New("no tag reachable from HEAD")
+
+// ErrNotApplicable is returned by a lint source that has nothing to lint in
+// a repository, such as Ruff without Python files, so it is told apart from
+// a source that linted files and found no issues.
+var ErrNotApplicable = errors.New("lint source does not apply")
+
+// ErrToolNotFound is returned when an external command CodeCompass runs,
+// such as git, npx or ruff, is not installed.
+type ErrToolNotFound struct {
+	Tool string
+	Err  error
+}
+
+func (e *ErrToolNotFound) Error() string {
+	return fmt.Sprintf("%s not found: %v", e.Tool, e.Err)
+}
+
+func (e *ErrToolNotFound) Unwrap() error {
+	return e.Err
+}
+
+// ErrCoverageFormat is returned when a coverage file exists but cannot be
+// parsed.
+type ErrCoverageFormat struct {
+	Path string
+	Err  error
+}
+
+func (e *ErrCoverageFormat) Error() string {
+	return fmt.Sprintf("unable to parse coverage file %s: %v", e.Path, e.Err)
+}
+
+func (e *ErrCoverageFormat) Unwrap() error {
+	return e.Err
+}
+
+// ErrCoverageMissing is returned when coverage is required and there is no
+// coverage file: Path, the file named, does not exist, or none was found in
+// the common locations when Path is empty.
+type ErrCoverageMissing struct {
+	Path string
+}
+
+func (e *ErrCoverageMissing) Error() string {
+	if e.Path == "" {
+		return "no coverage file found in common locations"
+	}
+	return fmt.Sprintf("coverage file %s does not exist", e.Path)
+}
+
+// ErrConfigInvalid is returned when a configuration key has a value that
+// cannot be used. Reason, when set, explains what was expected.
+type ErrConfigInvalid struct {
+	Key    string
+	Value  string
+	Reason string
+}
+
+func (e *ErrConfigInvalid) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("invalid %s value: %s (%s)", e.Key, e.Value, e.Reason)
+	}
+	return fmt.Sprintf("invalid %s value: %s", e.Key, e.Value)
+}
+
+// ErrDirtyWorkTree is returned when a clean work tree is required and
+// Files, relative to the repository root, have uncommitted changes or are
+// untracked.
+type ErrDirtyWorkTree struct {
+	Files []string
+}
+
+func (e *ErrDirtyWorkTree) Error() string {
+	const shown = 3
+	if len(e.Files) > shown {
+		return fmt.Sprintf("work tree is not clean: %s and %d more", strings.Join(e.Files[:shown], ", "), len(e.Files)-shown)
+	}
+	return "work tree is not clean: " + strings.Join(e.Files, ", ")
+}
+
+// ErrMissingToken is returned when an API needs a token that is not set in
+// the environment variable Env.
+type ErrMissingToken struct {
+	Env string
+}
+
+func (e *ErrMissingToken) Error() string {
+	return fmt.Sprintf("%s is not set", e.Env)
+}
+
+// ErrRateLimited is returned when an API refuses requests until Reset.
+type ErrRateLimited struct {
+	API   string
+	Reset time.Time
+}
+
+func (e *ErrRateLimited) Error() string {
+	return fmt.Sprintf("%s rate limit exceeded until %s", e.API, e.Reset.Format(time.RFC3339))
+}
@@ -0,0 +1,113 @@
+package checkstyle
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"os"
+
+	"github.com/xeon-zolt/codecompass/internal/config"
+	"github.com/xeon-zolt/codecompass/internal/repopath"
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+// report is the checkstyle XML format many linters can write, such as
+// Checkstyle, PMD, PHP_CodeSniffer and ESLint's checkstyle formatter.
+type report struct {
+	Files []struct {
+		Name   string `xml:"name,attr"`
+		Errors []struct {
+			Line     int    `xml:"line,attr"`
+			Severity string `xml:"severity,attr"`
+			Message  string `xml:"message,attr"`
+			Source   string `xml:"source,attr"`
+		} `xml:"error"`
+	} `xml:"file"`
+}
+
+// severities maps checkstyle severities to ours. Unknown severities count
+// as warnings.
+var severities = map[string]int{
+	"error":   types.SeverityError,
+	"warning": types.SeverityWarning,
+	"info":    types.SeverityWarning,
+	"ignore":  types.SeverityOff,
+}
+
+// ParseCheckstyle reads the checkstyle XML report at path. The source of
+// each error becomes its rule ID, and file names are returned as written in
+// the report. Errors with severity ignore are dropped.
+func ParseCheckstyle(path string) ([]types.Issue, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkstyle report: %w", err)
+	}
+
+	var parsed report
+	if err := xml.Unmarshal(content, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse checkstyle report %s: %w", path, err)
+	}
+
+	var issues []types.Issue
+	for _, file := range parsed.Files {
+		for _, e := range file.Errors {
+			severity, ok := severities[e.Severity]
+			if !ok {
+				severity = types.SeverityWarning
+			}
+			if severity == types.SeverityOff {
+				continue
+			}
+
+			rule := e.Source
+			if rule == "" {
+				rule = "unknown"
+			}
+			issues = append(issues, types.Issue{
+				FilePath: file.Name,
+				Line:     e.Line,
+				RuleID:   rule,
+				Message:  e.Message,
+				Severity: severity,
+			})
+		}
+	}
+	return issues, nil
+}
+
+// Source is a lint source reading the checkstyle XML report at Path, written
+// beforehand by any tool that supports the format.
+type Source struct {
+	Path string
+}
+
+func (Source) Name() string {
+	return "checkstyle"
+}
+
+// Detect always succeeds; the report was asked for explicitly.
+func (Source) Detect(ctx context.Context, dir string) bool {
+	return true
+}
+
+// Run returns the issues of the report for the given files. File names in
+// the report may be absolute or relative to dir.
+func (s Source) Run(ctx context.Context, dir string, files map[string]bool, cfg *config.Config) ([]types.Issue, error) {
+	reported, err := ParseCheckstyle(s.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	resolver := repopath.New(dir, files)
+	var issues []types.Issue
+	for _, issue := range reported {
+		file := resolver.Resolve(issue.FilePath)
+		if !files[file] || (cfg != nil && cfg.ShouldIgnoreRule(issue.RuleID)) {
+			continue
+		}
+
+		issue.FilePath = file
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
@@ -0,0 +1,74 @@
+package checkstyle
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xeon-zolt/codecompass/internal/config"
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+func TestParseCheckstyle(t *testing.T) {
+	issues, err := ParseCheckstyle(filepath.Join("testdata", "checkstyle.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []types.Issue{
+		{FilePath: "src/Main.java", Line: 3, RuleID: "com.puppycrawl.tools.checkstyle.checks.javadoc.MissingJavadocMethodCheck", Message: "Missing a Javadoc comment.", Severity: types.SeverityWarning},
+		{FilePath: "src/Main.java", Line: 10, RuleID: "com.puppycrawl.tools.checkstyle.checks.sizes.LineLengthCheck", Message: "Line is longer than 100 characters.", Severity: types.SeverityError},
+		{FilePath: "/repo/lib/util.php", Line: 1, RuleID: "Generic.NamingConventions", Message: "Consider a shorter name.", Severity: types.SeverityWarning},
+		{FilePath: "/elsewhere/other.php", Line: 2, RuleID: "Generic.PHP.Syntax", Message: "Outside the repository.", Severity: types.SeverityError},
+	}
+	if !reflect.DeepEqual(issues, expected) {
+		t.Errorf("Expected issues\n%+v\nbut got\n%+v", expected, issues)
+	}
+}
+
+func TestParseCheckstyleErrors(t *testing.T) {
+	if _, err := ParseCheckstyle(filepath.Join("testdata", "missing.xml")); err == nil {
+		t.Error("Expected an error for a missing report")
+	}
+
+	malformed := filepath.Join(t.TempDir(), "checkstyle.xml")
+	if err := os.WriteFile(malformed, []byte("<checkstyle><file"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseCheckstyle(malformed); err == nil || !strings.Contains(err.Error(), malformed) {
+		t.Errorf("Expected an error naming the malformed report, but got %v", err)
+	}
+}
+
+func TestSourceRun(t *testing.T) {
+	source := Source{Path: filepath.Join("testdata", "checkstyle.xml")}
+	if source.Name() != "checkstyle" {
+		t.Errorf("Expected source name checkstyle, but got %s", source.Name())
+	}
+
+	cfg := config.NewConfig()
+	cfg.IgnoredRules = []string{"com.puppycrawl.tools.checkstyle.checks.sizes.LineLengthCheck"}
+	files := map[string]bool{"src/Main.java": true, "lib/util.php": true}
+
+	issues, err := source.Run(context.Background(), "/repo", files, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Absolute names are made relative to the repository; files outside it
+	// and ignored rules are dropped
+	var got []string
+	for _, issue := range issues {
+		got = append(got, issue.FilePath+":"+issue.RuleID)
+	}
+	expected := []string{
+		"src/Main.java:com.puppycrawl.tools.checkstyle.checks.javadoc.MissingJavadocMethodCheck",
+		"lib/util.php:Generic.NamingConventions",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected issues %v, but got %v", expected, got)
+	}
+}
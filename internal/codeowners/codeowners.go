@@ -0,0 +1,166 @@
+// Package codeowners reads CODEOWNERS files as GitHub and GitLab do, to find
+// the users and teams that own a path.
+package codeowners
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Locations are where a CODEOWNERS file is looked for, relative to the root
+// of the repository, in the order GitHub looks.
+var Locations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// rule is one line of a CODEOWNERS file.
+type rule struct {
+	pattern string
+	match   *regexp.Regexp
+	owners  []string
+}
+
+// File is a parsed CODEOWNERS file.
+type File struct {
+	rules []rule
+}
+
+// Load reads the first CODEOWNERS file in Locations under dir. It returns a
+// nil File and an empty path when the repository has none.
+func Load(dir string) (*File, string, error) {
+	for _, location := range Locations {
+		path := filepath.Join(dir, filepath.FromSlash(location))
+		file, err := os.Open(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		defer file.Close()
+
+		owners, err := Parse(file)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to parse %s: %w", location, err)
+		}
+		return owners, location, nil
+	}
+	return nil, "", nil
+}
+
+// Parse reads a CODEOWNERS file: a pattern per line followed by its owners,
+// @user, @org/team or an email address. Blank lines, comments and GitLab
+// [Section] headers are skipped.
+func Parse(r io.Reader) (*File, error) {
+	f := &File{}
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+		if i := commentStart(line); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "[") || strings.HasPrefix(fields[0], "^[") {
+			continue
+		}
+
+		pattern := strings.ReplaceAll(fields[0], `\#`, "#")
+		match, err := compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		f.rules = append(f.rules, rule{pattern: pattern, match: match, owners: fields[1:]})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// commentStart returns the index of the # starting a comment in line, or -1.
+// An escaped \# is part of a pattern.
+func commentStart(line string) int {
+	for i := 0; i < len(line); i++ {
+		if line[i] == '#' && (i == 0 || line[i-1] != '\\') {
+			return i
+		}
+	}
+	return -1
+}
+
+// compile turns a CODEOWNERS pattern into a regular expression over slash
+// separated paths relative to the root. As in .gitignore, a pattern with a
+// slash at the start or in the middle is anchored to the root, one without
+// matches at any depth, and one ending in a slash matches everything under
+// a directory. A pattern whose last part has no wildcard also matches
+// everything under a directory of that name, while docs/* only matches the
+// files directly in docs.
+func compile(pattern string) (*regexp.Regexp, error) {
+	if pattern == "*" {
+		return regexp.Compile(`.*`)
+	}
+
+	dirOnly := strings.HasSuffix(pattern, "/")
+	trimmed := strings.TrimSuffix(pattern, "/")
+	anchored := strings.HasPrefix(trimmed, "/") || strings.Contains(trimmed, "/")
+	trimmed = strings.TrimPrefix(trimmed, "/")
+	if trimmed == "" {
+		return nil, fmt.Errorf("invalid pattern %q", pattern)
+	}
+
+	var expr strings.Builder
+	expr.WriteString("^")
+	if !anchored {
+		expr.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(trimmed); i++ {
+		switch c := trimmed[i]; {
+		case strings.HasPrefix(trimmed[i:], "**/"):
+			expr.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(trimmed[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case c == '*':
+			expr.WriteString("[^/]*")
+		case c == '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	last := trimmed[strings.LastIndex(trimmed, "/")+1:]
+	switch {
+	case dirOnly:
+		expr.WriteString("/.*")
+	case !strings.ContainsAny(last, "*?"):
+		expr.WriteString("(?:/.*)?")
+	}
+	expr.WriteString("$")
+	return regexp.Compile(expr.String())
+}
+
+// Owners returns the owners of path, a slash separated path relative to the
+// root, from the last rule matching it, and the pattern of that rule. A rule
+// without owners leaves the paths it matches unowned, so both are empty then
+// and when no rule matches.
+func (f *File) Owners(path string) ([]string, string) {
+	path = strings.TrimPrefix(filepath.ToSlash(path), "/")
+	for i := len(f.rules) - 1; i >= 0; i-- {
+		if f.rules[i].match.MatchString(path) {
+			if len(f.rules[i].owners) == 0 {
+				return nil, ""
+			}
+			return f.rules[i].owners, f.rules[i].pattern
+		}
+	}
+	return nil, ""
+}
@@ -0,0 +1,106 @@
+package codeowners
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const sample = `# Default owners
+*                       @acme/core
+
+*.js                    @acme/frontend  # trailing comment
+/docs/                  docs@acme.dev
+docs/*                  @acme/writers
+apps/                   @acme/apps
+/src/billing/           @acme/billing @alice
+src/**/generated        @acme/codegen
+/build/logs             @acme/ops
+/vendor/
+\#weird                 @acme/odd
+
+[GitLab Section]
+/scripts/               @acme/ops
+`
+
+func TestOwners(t *testing.T) {
+	f, err := Parse(strings.NewReader(sample))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path    string
+		owners  []string
+		pattern string
+	}{
+		{"README.md", []string{"@acme/core"}, "*"},
+		{"web/app.js", []string{"@acme/frontend"}, "*.js"},
+		// Later rules win, so docs/* beats /docs/ for the files directly in docs
+		{"docs/index.md", []string{"@acme/writers"}, "docs/*"},
+		{"docs/guides/setup.md", []string{"docs@acme.dev"}, "/docs/"},
+		{"apps/web/main.go", []string{"@acme/apps"}, "apps/"},
+		{"services/apps/api/main.go", []string{"@acme/apps"}, "apps/"},
+		{"src/billing/invoice.ts", []string{"@acme/billing", "@alice"}, "/src/billing/"},
+		{"src/billing/invoice.js", []string{"@acme/billing", "@alice"}, "/src/billing/"},
+		{"lib/src/billing/invoice.ts", []string{"@acme/core"}, "*"},
+		{"src/generated/types.ts", []string{"@acme/codegen"}, "src/**/generated"},
+		{"src/api/v1/generated/types.ts", []string{"@acme/codegen"}, "src/**/generated"},
+		{"build/logs/today.txt", []string{"@acme/ops"}, "/build/logs"},
+		{"scripts/deploy.sh", []string{"@acme/ops"}, "/scripts/"},
+		{"#weird", []string{"@acme/odd"}, "#weird"},
+		// A rule without owners leaves its paths unowned
+		{"vendor/lib.go", nil, ""},
+	}
+	for _, tt := range tests {
+		owners, pattern := f.Owners(tt.path)
+		if !reflect.DeepEqual(owners, tt.owners) || pattern != tt.pattern {
+			t.Errorf("Owners(%q) = %v, %q; expected %v, %q", tt.path, owners, pattern, tt.owners, tt.pattern)
+		}
+	}
+}
+
+func TestOwnersWithoutMatch(t *testing.T) {
+	f, err := Parse(strings.NewReader("/src/ @acme/src\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if owners, pattern := f.Owners("README.md"); owners != nil || pattern != "" {
+		t.Errorf("Expected no owners, got %v from %q", owners, pattern)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	if f, path, err := Load(dir); f != nil || path != "" || err != nil {
+		t.Fatalf("Expected no CODEOWNERS, got %v, %q, %v", f, path, err)
+	}
+
+	write := func(location, content string) {
+		path := filepath.Join(dir, filepath.FromSlash(location))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("CODEOWNERS", "* @root\n")
+	write("docs/CODEOWNERS", "* @docs\n")
+	f, path, err := Load(dir)
+	if err != nil || path != "CODEOWNERS" {
+		t.Fatalf("Expected the root CODEOWNERS, got %q, %v", path, err)
+	}
+	if owners, _ := f.Owners("a.go"); !reflect.DeepEqual(owners, []string{"@root"}) {
+		t.Errorf("Expected @root, got %v", owners)
+	}
+
+	// .github/CODEOWNERS comes first
+	write(".github/CODEOWNERS", "* @github\n")
+	if _, path, _ := Load(dir); path != ".github/CODEOWNERS" {
+		t.Errorf("Expected .github/CODEOWNERS, got %q", path)
+	}
+}
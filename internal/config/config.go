@@ -2,21 +2,38 @@ package config
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
 	"strconv"
 	"strings"
+
+	"github.com/xeon-zolt/codecompass/internal/cerrors"
+	"github.com/xeon-zolt/codecompass/internal/utils"
 )
 
 type Config struct {
 	IgnoredFiles          []string
 	IgnoredAuthors        []string
 	IgnoredRules          []string
+	IgnoredRulePrefixes   []string // such as @typescript-eslint/ or D
 	IgnoredPaths          []string
 	MaxFileSize           int
+	MaxLineSize           int // in KB
 	MinCoverageThreshold  float64
+	CoverageMaxAge        int     // Days the coverage report may be older than the code before --fail-on coverage-age fails
+	DecayHalfLifeDays     float64 // 0 leaves issues unweighted by age
+	GracePeriodDays       int     // Days after their first commit a new contributor's issues stay off the author leaderboard, 0 for none
+	IssueWeights          IssueWeights
 	MaxConcurrentBlame    int
+	BlameFormat           string // incremental or line-porcelain
 	CacheResults          bool
 	EnableGitHooks        bool
 	CustomSettings        map[string]string
@@ -24,20 +41,131 @@ type Config struct {
 	SpellCheckEnabled     bool
 	SpellCheckExtensions  []string
 	SpellCheckIgnorePaths []string
+	DebtIgnorePaths       []string
+	LocIgnorePaths        []string
+	ChurnIgnorePaths      []string
+	TestFilePatterns      []string // what TestsShorthand stands for in the leaderboard ignore paths
+	SpellCheckMinWordLen  int
+	SpellCheckMaxFileSize int // in KB; 0 means MaxFileSize
 	RuffEnabled           bool
 	RuffRules             []string
 	RuffIgnorePaths       []string
+	ReportCardWeights     map[string]float64
+	ReportCardCutoffs     []float64
+	DateType              string
+	CoAuthorCredit        string // full, split or none
+	ESLintSeverities      map[int]int
+	GitLabBaseURL         string
+	MaxIssuesPerFile      int // 0 means no limit
+	TimezoneMinCommits    int
+	TimeSeriesMetrics     []string
+	OwnerResolution       []string // owner sources for --files-by-owner, in order
+	LongFunctionLines     int
+	MaxWarningGroups      int // 0 means no limit
+	ScoreWeights          map[string]float64
+	AttentionScore        float64 // Files scoring below it are written by --attention-out, 0 for none
+	AttentionComponent    float64 // As are files with a component scoring below it
+	RuleSeverityOverrides []RuleSeverityOverride
+	RuleGroups            []RuleGroup
+	RuleDocLinks          map[string]string // URL templates by plugin namespace
+	CustomRules           []CustomRule
+}
+
+// RuleSeverityOverride changes how the issues of the rules matching Pattern,
+// a rule ID or a glob such as react/*, count: as an error, as a warning, or
+// not at all.
+type RuleSeverityOverride struct {
+	Pattern  string
+	Severity string // error, warning or ignore
+}
+
+// IssueWeights are what each lint issue counts for when authors and files
+// are ranked with --weighted: Error or Warning by its severity, unless the
+// first of Rules matching its rule sets a weight of its own.
+type IssueWeights struct {
+	Error   float64
+	Warning float64
+	Rules   []RuleWeight
+}
+
+// RuleWeight is what the issues of the rules matching Pattern, a rule ID or
+// a glob such as security/*, count for, whatever their severity.
+type RuleWeight struct {
+	Pattern string
+	Weight  float64
+}
+
+// Weight returns what an issue of ruleID counts for, isError telling
+// whether it is an error rather than a warning.
+func (w IssueWeights) Weight(ruleID string, isError bool) float64 {
+	for _, rule := range w.Rules {
+		if matchRule(rule.Pattern, ruleID) {
+			return rule.Weight
+		}
+	}
+	if isError {
+		return w.Error
+	}
+	return w.Warning
+}
+
+// RuleGroup names a set of rules, given as rule IDs or globs, whose
+// violations the rule-groups leaderboard counts together.
+type RuleGroup struct {
+	Name     string
+	Patterns []string
+}
+
+// CustomRule is a rule of the custom lint source: every line of an analyzed
+// file matching Pattern, a regular expression, is an issue of the rule Name.
+type CustomRule struct {
+	Name    string
+	Pattern string
 }
 
+// overrideSeverities are the severities a rule can be overridden to, with
+// ignore dropping its issues.
+var overrideSeverities = map[string]int{"warning": 1, "error": 2}
+
+// ruleGroupPrefix starts the keys that define rule groups, such as
+// rule-group.correctness.
+const ruleGroupPrefix = "rule-group."
+
+// ruleDocLinkPrefix starts the keys that set the documentation URL template
+// of the rules of a plugin, such as rule-doc-link.@acme/lint.
+const ruleDocLinkPrefix = "rule-doc-link."
+
+// severityNames are the CodeCompass severities an ESLint severity can map
+// to, by their eslint-severity-map name.
+var severityNames = map[string]int{"off": 0, "warning": 1, "error": 2}
+
+// ReportCardCategories are the report card categories in display order.
+var ReportCardCategories = []string{"coverage", "issues", "debt", "spelling", "bus-factor"}
+
+// ScoreComponents are the components of the --score quality score in
+// display order.
+var ScoreComponents = []string{"issues", "coverage", "debt", "churn", "complexity"}
+
+// TimeSeriesMetrics are the metrics --timeseries-out can export.
+var TimeSeriesMetrics = []string{"summary", "author-issues", "coverage", "debt", "score"}
+
+// OwnerSources are where the owner of a file can come from: its CODEOWNERS
+// rule, or the author of most of its current lines.
+var OwnerSources = []string{"codeowners", "blame"}
+
 func NewConfig() *Config {
 	return &Config{
 		IgnoredFiles:          []string{},
 		IgnoredAuthors:        []string{},
 		IgnoredRules:          []string{},
+		IgnoredRulePrefixes:   []string{},
 		IgnoredPaths:          []string{},
 		MaxFileSize:           5000,
+		MaxLineSize:           1024,
 		MinCoverageThreshold:  80.0,
+		CoverageMaxAge:        7,
 		MaxConcurrentBlame:    4,
+		BlameFormat:           "incremental",
 		CacheResults:          true,
 		EnableGitHooks:        false,
 		CustomSettings:        make(map[string]string),
@@ -45,15 +173,57 @@ func NewConfig() *Config {
 		SpellCheckEnabled:     true,
 		SpellCheckExtensions:  []string{".js", ".ts", ".jsx", ".tsx", ".md", ".txt"},
 		SpellCheckIgnorePaths: []string{"node_modules", "dist", "build"},
+		TestFilePatterns:      []string{"*_test.*", "*.test.*", "*.spec.*", "test_*.py", "test/", "tests/", "__tests__/", "spec/"},
+		SpellCheckMinWordLen:  4,
 		RuffEnabled:           true,
 		RuffRules:             []string{},
 		RuffIgnorePaths:       []string{"node_modules", "dist", "build"},
+		ReportCardWeights: map[string]float64{
+			"coverage":   25,
+			"issues":     25,
+			"debt":       15,
+			"spelling":   10,
+			"bus-factor": 25,
+		},
+		ReportCardCutoffs:  []float64{90, 80, 70, 60},
+		IssueWeights:       IssueWeights{Error: 5, Warning: 1},
+		DateType:           "author",
+		CoAuthorCredit:     "full",
+		ESLintSeverities:   map[int]int{0: 0, 1: 1, 2: 2},
+		TimezoneMinCommits: 10,
+		TimeSeriesMetrics:  append([]string{}, TimeSeriesMetrics...),
+		OwnerResolution:    append([]string{}, OwnerSources...),
+		LongFunctionLines:  50,
+		MaxWarningGroups:   10,
+		AttentionScore:     60,
+		AttentionComponent: 25,
+		ScoreWeights: map[string]float64{
+			"issues":     30,
+			"coverage":   25,
+			"debt":       15,
+			"churn":      15,
+			"complexity": 15,
+		},
+		RuleDocLinks: map[string]string{},
 	}
 }
 
+// LoadConfig reads the config file found by FindConfigFile, or returns the
+// defaults when there is none.
 func LoadConfig() (*Config, error) {
 	config := NewConfig()
 
+	configFile := FindConfigFile()
+	if configFile == "" {
+		return config, nil // No config file found, return default config
+	}
+
+	return parseConfigFile(configFile, config)
+}
+
+// FindConfigFile returns the first config file found in the current
+// directory, or an empty string if there is none.
+func FindConfigFile() string {
 	// Look for config files in order of preference
 	configFiles := []string{
 		".codecompass.rc",
@@ -62,22 +232,17 @@ func LoadConfig() (*Config, error) {
 		".codecompass",
 	}
 
-	var configFile string
 	for _, file := range configFiles {
 		if _, err := os.Stat(file); err == nil {
-			configFile = file
-			break
+			return file
 		}
 	}
-
-	if configFile == "" {
-		return config, nil // No config file found, return default config
-	}
-
-	fmt.Printf("🧭 Using config file: %s\n", configFile)
-	return parseConfigFile(configFile, config)
+	return ""
 }
 
+// LoadConfigFromFile reads a config file. Invalid values do not stop the
+// file from loading: the other keys are still applied, and the returned
+// config comes with every invalid value joined into the error.
 func LoadConfigFromFile(filename string) (*Config, error) {
 	config := NewConfig()
 	if _, err := os.Stat(filename); err != nil {
@@ -93,6 +258,8 @@ func parseConfigFile(filename string, config *Config) (*Config, error) {
 	}
 	defer file.Close()
 
+	var errs []error
+
 	scanner := bufio.NewScanner(file)
 	lineNum := 0
 
@@ -112,71 +279,396 @@ func parseConfigFile(filename string, config *Config) (*Config, error) {
 				key := strings.TrimSpace(parts[0])
 				value := strings.TrimSpace(parts[1])
 
-				// Remove quotes if present
-				value = strings.Trim(value, `"'`)
+				value = unquote(value)
 
 				if err := config.parseKeyValue(key, value); err != nil {
-					fmt.Printf("Warning: Line %d: %v\n", lineNum, err)
+					errs = append(errs, fmt.Errorf("%s:%d: %w", filename, lineNum, err))
 				}
 			}
 		}
 	}
 
-	return config, scanner.Err()
+	if err := scanner.Err(); err != nil {
+		errs = append(errs, err)
+	}
+	return config, errors.Join(errs...)
+}
+
+// unquote removes the quotes around value. Double-quoted values written by
+// WriteEffective are unescaped; anything else has its quotes trimmed.
+func unquote(value string) string {
+	if strings.HasPrefix(value, `"`) {
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			return unquoted
+		}
+	}
+	return strings.Trim(value, `"'`)
 }
 
 func (c *Config) parseKeyValue(key, value string) error {
 	switch key {
 	case "ignore-files":
-		c.IgnoredFiles = append(c.IgnoredFiles, parseList(value)...)
+		c.IgnoredFiles = appendUnique(c.IgnoredFiles, parseList(value)...)
 	case "ignore-authors":
-		c.IgnoredAuthors = append(c.IgnoredAuthors, parseList(value)...)
+		c.IgnoredAuthors = appendUnique(c.IgnoredAuthors, parseList(value)...)
 	case "ignore-rules":
-		c.IgnoredRules = append(c.IgnoredRules, parseList(value)...)
+		c.IgnoredRules = appendUnique(c.IgnoredRules, parseList(value)...)
+	case "ignore-rule-prefixes":
+		c.IgnoredRulePrefixes = appendUnique(c.IgnoredRulePrefixes, parseList(value)...)
 	case "ignore-paths":
-		c.IgnoredPaths = append(c.IgnoredPaths, parseList(value)...)
+		c.IgnoredPaths = appendUnique(c.IgnoredPaths, parseList(value)...)
 	case "max-file-size":
 		if size, err := strconv.Atoi(value); err == nil {
 			c.MaxFileSize = size
 		} else {
-			return fmt.Errorf("invalid max-file-size value: %s", value)
+			return &cerrors.ErrConfigInvalid{Key: "max-file-size", Value: value}
+		}
+	case "max-line-size":
+		if size, err := strconv.Atoi(value); err == nil && size > 0 {
+			c.MaxLineSize = size
+		} else {
+			return &cerrors.ErrConfigInvalid{Key: "max-line-size", Value: value, Reason: "expected a size in KB above 0"}
 		}
 	case "min-coverage-threshold":
 		if threshold, err := strconv.ParseFloat(value, 64); err == nil {
 			c.MinCoverageThreshold = threshold
 		} else {
-			return fmt.Errorf("invalid min-coverage-threshold value: %s", value)
+			return &cerrors.ErrConfigInvalid{Key: "min-coverage-threshold", Value: value}
+		}
+	case "coverage-max-age":
+		if days, err := strconv.Atoi(value); err == nil && days >= 0 {
+			c.CoverageMaxAge = days
+		} else {
+			return &cerrors.ErrConfigInvalid{Key: "coverage-max-age", Value: value, Reason: "expected a number of days, 0 or more"}
+		}
+	case "decay-halflife-days":
+		if days, err := strconv.ParseFloat(value, 64); err == nil && days >= 0 {
+			c.DecayHalfLifeDays = days
+		} else {
+			return &cerrors.ErrConfigInvalid{Key: "decay-halflife-days", Value: value, Reason: "expected a number of days, or 0 to turn decay off"}
+		}
+	case "grace-period-days":
+		if days, err := strconv.Atoi(value); err == nil && days >= 0 {
+			c.GracePeriodDays = days
+		} else {
+			return &cerrors.ErrConfigInvalid{Key: "grace-period-days", Value: value, Reason: "expected a number of days, or 0 to turn the grace period off"}
+		}
+	case "weight-error", "weight-warning":
+		weight, err := strconv.ParseFloat(value, 64)
+		if err != nil || weight < 0 {
+			return &cerrors.ErrConfigInvalid{Key: key, Value: value, Reason: "expected a weight, 0 or more"}
+		}
+		if key == "weight-error" {
+			c.IssueWeights.Error = weight
+		} else {
+			c.IssueWeights.Warning = weight
+		}
+	case "rule-weights":
+		for _, item := range parseList(value) {
+			pattern, weightStr, found := strings.Cut(item, ":")
+			pattern = strings.TrimSpace(pattern)
+			weight, err := strconv.ParseFloat(strings.TrimSpace(weightStr), 64)
+			if !found || err != nil || weight < 0 || !validRulePattern(pattern) {
+				return &cerrors.ErrConfigInvalid{Key: key, Value: item, Reason: "expected rule:weight with a weight of 0 or more"}
+			}
+			c.IssueWeights.Rules = append(c.IssueWeights.Rules, RuleWeight{Pattern: pattern, Weight: weight})
+		}
+	case "max-issues-per-file":
+		if max, err := strconv.Atoi(value); err == nil && max >= 0 {
+			c.MaxIssuesPerFile = max
+		} else {
+			return &cerrors.ErrConfigInvalid{Key: "max-issues-per-file", Value: value}
+		}
+	case "timezone-min-commits":
+		if min, err := strconv.Atoi(value); err == nil && min >= 0 {
+			c.TimezoneMinCommits = min
+		} else {
+			return &cerrors.ErrConfigInvalid{Key: "timezone-min-commits", Value: value}
+		}
+	case "long-function-lines":
+		if lines, err := strconv.Atoi(value); err == nil && lines > 0 {
+			c.LongFunctionLines = lines
+		} else {
+			return &cerrors.ErrConfigInvalid{Key: "long-function-lines", Value: value}
+		}
+	case "max-warning-groups":
+		if groups, err := strconv.Atoi(value); err == nil && groups >= 0 {
+			c.MaxWarningGroups = groups
+		} else {
+			return &cerrors.ErrConfigInvalid{Key: "max-warning-groups", Value: value}
 		}
 	case "max-concurrent-blame":
-		if concurrent, err := strconv.Atoi(value); err == nil {
+		if value == "auto" {
+			c.MaxConcurrentBlame = 0
+		} else if concurrent, err := strconv.Atoi(value); err == nil && concurrent >= 0 {
 			c.MaxConcurrentBlame = concurrent
 		} else {
-			return fmt.Errorf("invalid max-concurrent-blame value: %s", value)
+			return &cerrors.ErrConfigInvalid{Key: "max-concurrent-blame", Value: value}
 		}
+	case "blame-format":
+		if value != "incremental" && value != "line-porcelain" {
+			return &cerrors.ErrConfigInvalid{Key: key, Value: value, Reason: "expected incremental or line-porcelain"}
+		}
+		c.BlameFormat = value
 	case "cache-results":
 		c.CacheResults = strings.ToLower(value) == "true"
 	case "enable-git-hooks":
 		c.EnableGitHooks = strings.ToLower(value) == "true"
 	case "custom-words":
-		c.CustomWords = append(c.CustomWords, parseList(value)...)
+		c.CustomWords = appendUnique(c.CustomWords, parseList(value)...)
 	case "spellcheck-enabled":
 		c.SpellCheckEnabled = strings.ToLower(value) == "true"
 	case "spellcheck-extensions":
 		c.SpellCheckExtensions = parseList(value)
 	case "spellcheck-ignore-paths":
-		c.SpellCheckIgnorePaths = parseList(value)
+		paths, err := parseIgnorePaths(key, value)
+		if err != nil {
+			return err
+		}
+		c.SpellCheckIgnorePaths = paths
+	case "debt-ignore-paths", "loc-ignore-paths", "churn-ignore-paths":
+		paths, err := parseIgnorePaths(key, value)
+		if err != nil {
+			return err
+		}
+		field := map[string]*[]string{
+			"debt-ignore-paths":  &c.DebtIgnorePaths,
+			"loc-ignore-paths":   &c.LocIgnorePaths,
+			"churn-ignore-paths": &c.ChurnIgnorePaths,
+		}[key]
+		*field = appendUnique(*field, paths...)
+	case "test-file-patterns":
+		c.TestFilePatterns = parseList(value)
+	case "spellcheck-max-file-size":
+		if size, err := strconv.Atoi(value); err == nil && size >= 0 {
+			c.SpellCheckMaxFileSize = size
+		} else {
+			return &cerrors.ErrConfigInvalid{Key: "spellcheck-max-file-size", Value: value}
+		}
+	case "spellcheck-min-word-length":
+		if length, err := strconv.Atoi(value); err == nil && length > 0 {
+			c.SpellCheckMinWordLen = length
+		} else {
+			return &cerrors.ErrConfigInvalid{Key: "spellcheck-min-word-length", Value: value}
+		}
 	case "ruff-enabled":
 		c.RuffEnabled = strings.ToLower(value) == "true"
 	case "ruff-rules":
-		c.RuffRules = append(c.RuffRules, parseList(value)...)
+		c.RuffRules = appendUnique(c.RuffRules, parseList(value)...)
 	case "ruff-ignore-paths":
-		c.RuffIgnorePaths = append(c.RuffIgnorePaths, parseList(value)...)
+		c.RuffIgnorePaths = appendUnique(c.RuffIgnorePaths, parseList(value)...)
+	case "report-card-weights":
+		for _, item := range parseList(value) {
+			category, weightStr, found := strings.Cut(item, ":")
+			weight, err := strconv.ParseFloat(strings.TrimSpace(weightStr), 64)
+			category = strings.TrimSpace(category)
+			if !found || err != nil || weight < 0 || !isReportCardCategory(category) {
+				return &cerrors.ErrConfigInvalid{Key: key, Value: item, Reason: "expected category:weight"}
+			}
+			c.ReportCardWeights[category] = weight
+		}
+	case "score-weights":
+		for _, item := range parseList(value) {
+			component, weightStr, found := strings.Cut(item, ":")
+			weight, err := strconv.ParseFloat(strings.TrimSpace(weightStr), 64)
+			component = strings.TrimSpace(component)
+			if !found || err != nil || weight < 0 || !isScoreComponent(component) {
+				return &cerrors.ErrConfigInvalid{Key: key, Value: item, Reason: "expected component:weight with a component of " + strings.Join(ScoreComponents, ", ")}
+			}
+			c.ScoreWeights[component] = weight
+		}
+	case "attention-score", "attention-component-score":
+		threshold, err := strconv.ParseFloat(value, 64)
+		if err != nil || threshold < 0 || threshold > 100 {
+			return &cerrors.ErrConfigInvalid{Key: key, Value: value, Reason: "expected a score from 0 to 100, or 0 to turn it off"}
+		}
+		if key == "attention-score" {
+			c.AttentionScore = threshold
+		} else {
+			c.AttentionComponent = threshold
+		}
+	case "report-card-cutoffs":
+		var cutoffs []float64
+		for _, item := range parseList(value) {
+			cutoff, err := strconv.ParseFloat(item, 64)
+			if err != nil || (len(cutoffs) > 0 && cutoff > cutoffs[len(cutoffs)-1]) {
+				return &cerrors.ErrConfigInvalid{Key: key, Value: value, Reason: "expected descending scores"}
+			}
+			cutoffs = append(cutoffs, cutoff)
+		}
+		if len(cutoffs) != 4 {
+			return &cerrors.ErrConfigInvalid{Key: key, Value: value, Reason: "expected 4 scores for A, B, C and D"}
+		}
+		c.ReportCardCutoffs = cutoffs
+	case "date-type":
+		if value != "author" && value != "commit" {
+			return &cerrors.ErrConfigInvalid{Key: key, Value: value, Reason: "expected author or commit"}
+		}
+		c.DateType = value
+	case "co-author-credit":
+		if value != "full" && value != "split" && value != "none" {
+			return &cerrors.ErrConfigInvalid{Key: key, Value: value, Reason: "expected full, split or none"}
+		}
+		c.CoAuthorCredit = value
+	case "gitlab-base-url":
+		if parsed, err := url.Parse(value); value != "" && (err != nil || parsed.Host == "" || (parsed.Scheme != "https" && parsed.Scheme != "http")) {
+			return &cerrors.ErrConfigInvalid{Key: key, Value: value, Reason: "expected an http or https URL"}
+		}
+		c.GitLabBaseURL = value
+	case "timeseries-metrics":
+		metrics := parseList(value)
+		for _, metric := range metrics {
+			if !isTimeSeriesMetric(metric) {
+				return &cerrors.ErrConfigInvalid{Key: key, Value: metric, Reason: "expected " + strings.Join(TimeSeriesMetrics, ", ")}
+			}
+		}
+		c.TimeSeriesMetrics = metrics
+	case "owner-resolution":
+		sources := parseList(value)
+		if len(sources) == 0 {
+			return &cerrors.ErrConfigInvalid{Key: key, Value: value, Reason: "expected at least one of " + strings.Join(OwnerSources, ", ")}
+		}
+		seen := make(map[string]bool)
+		for _, source := range sources {
+			if !isOwnerSource(source) || seen[source] {
+				return &cerrors.ErrConfigInvalid{Key: key, Value: source, Reason: "expected each of " + strings.Join(OwnerSources, ", ") + " at most once"}
+			}
+			seen[source] = true
+		}
+		c.OwnerResolution = sources
+	case "rule-severity-overrides":
+		for _, item := range parseList(value) {
+			pattern, severity, found := strings.Cut(item, ":")
+			pattern, severity = strings.TrimSpace(pattern), strings.TrimSpace(severity)
+			if _, known := overrideSeverities[severity]; !found || (!known && severity != "ignore") || !validRulePattern(pattern) {
+				return &cerrors.ErrConfigInvalid{Key: key, Value: item, Reason: "expected rule:error, rule:warning or rule:ignore"}
+			}
+			c.RuleSeverityOverrides = append(c.RuleSeverityOverrides, RuleSeverityOverride{Pattern: pattern, Severity: severity})
+		}
+	case "custom-rule":
+		return c.addCustomRule(key, value)
+	case "eslint-severity-map":
+		for _, item := range parseList(value) {
+			eslintStr, name, found := strings.Cut(item, ":")
+			eslintSeverity, err := strconv.Atoi(strings.TrimSpace(eslintStr))
+			severity, known := severityNames[strings.TrimSpace(name)]
+			if !found || err != nil || eslintSeverity < 0 || !known {
+				return &cerrors.ErrConfigInvalid{Key: key, Value: item, Reason: "expected severity:off, severity:warning or severity:error"}
+			}
+			c.ESLintSeverities[eslintSeverity] = severity
+		}
 	default:
+		if name, ok := strings.CutPrefix(key, ruleGroupPrefix); ok {
+			return c.addRuleGroup(key, name, value)
+		}
+		if plugin, ok := strings.CutPrefix(key, ruleDocLinkPrefix); ok {
+			if plugin == "" {
+				return &cerrors.ErrConfigInvalid{Key: key, Value: value, Reason: "expected a plugin namespace after " + ruleDocLinkPrefix}
+			}
+			if parsed, err := url.Parse(value); err != nil || parsed.Host == "" || (parsed.Scheme != "https" && parsed.Scheme != "http") {
+				return &cerrors.ErrConfigInvalid{Key: key, Value: value, Reason: "expected an http or https URL, with {rule} for the rule name"}
+			}
+			c.RuleDocLinks[plugin] = value
+			return nil
+		}
 		c.CustomSettings[key] = value
 	}
 	return nil
 }
 
+// addRuleGroup adds the patterns in value to the rule group name, creating
+// it after the groups defined so far.
+func (c *Config) addRuleGroup(key, name, value string) error {
+	if name == "" {
+		return &cerrors.ErrConfigInvalid{Key: key, Value: value, Reason: "expected a group name after " + ruleGroupPrefix}
+	}
+	patterns := parseList(value)
+	for _, pattern := range patterns {
+		if !validRulePattern(pattern) {
+			return &cerrors.ErrConfigInvalid{Key: key, Value: pattern, Reason: "expected a rule ID or glob"}
+		}
+	}
+	for i := range c.RuleGroups {
+		if c.RuleGroups[i].Name == name {
+			c.RuleGroups[i].Patterns = appendUnique(c.RuleGroups[i].Patterns, patterns...)
+			return nil
+		}
+	}
+	c.RuleGroups = append(c.RuleGroups, RuleGroup{Name: name, Patterns: appendUnique(nil, patterns...)})
+	return nil
+}
+
+// addCustomRule adds the custom rule in value, given as name:pattern. A rule
+// defined again takes the later pattern.
+func (c *Config) addCustomRule(key, value string) error {
+	name, pattern, found := strings.Cut(value, ":")
+	name = strings.TrimSpace(name)
+	if !found || name == "" || strings.ContainsAny(name, ", \t") || pattern == "" {
+		return &cerrors.ErrConfigInvalid{Key: key, Value: value, Reason: "expected name:pattern, such as no-print:console\\.log"}
+	}
+	if _, err := regexp.Compile(pattern); err != nil {
+		return &cerrors.ErrConfigInvalid{Key: key, Value: value, Reason: "invalid regular expression: " + err.Error()}
+	}
+	for i := range c.CustomRules {
+		if c.CustomRules[i].Name == name {
+			c.CustomRules[i].Pattern = pattern
+			return nil
+		}
+	}
+	c.CustomRules = append(c.CustomRules, CustomRule{Name: name, Pattern: pattern})
+	return nil
+}
+
+func validRulePattern(pattern string) bool {
+	_, err := path.Match(pattern, "")
+	return pattern != "" && err == nil
+}
+
+// matchRule reports whether ruleID matches pattern, a rule ID or a glob.
+func matchRule(pattern, ruleID string) bool {
+	if pattern == ruleID {
+		return true
+	}
+	matched, _ := path.Match(pattern, ruleID)
+	return matched
+}
+
+func isReportCardCategory(category string) bool {
+	for _, known := range ReportCardCategories {
+		if category == known {
+			return true
+		}
+	}
+	return false
+}
+
+func isScoreComponent(component string) bool {
+	for _, known := range ScoreComponents {
+		if component == known {
+			return true
+		}
+	}
+	return false
+}
+
+func isTimeSeriesMetric(metric string) bool {
+	for _, known := range TimeSeriesMetrics {
+		if metric == known {
+			return true
+		}
+	}
+	return false
+}
+
+func isOwnerSource(source string) bool {
+	for _, known := range OwnerSources {
+		if source == known {
+			return true
+		}
+	}
+	return false
+}
+
 func parseList(value string) []string {
 	// Split by comma and clean up
 	items := strings.Split(value, ",")
@@ -192,16 +684,42 @@ func parseList(value string) []string {
 	return result
 }
 
-func (c *Config) ShouldIgnoreFile(filePath string) bool {
-	// Check if file is too large
-	if c.MaxFileSize > 0 {
-		if info, err := os.Stat(filePath); err == nil {
-			if info.Size() > int64(c.MaxFileSize*1024) { // MaxFileSize is in KB
-				return true
+// appendUnique appends the items not already in list, so list keys that
+// extend the defaults can be repeated without duplicating entries.
+func appendUnique(list []string, items ...string) []string {
+	for _, item := range items {
+		found := false
+		for _, existing := range list {
+			if existing == item {
+				found = true
+				break
 			}
 		}
+		if !found {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+func (c *Config) ShouldIgnoreFile(filePath string) bool {
+	return c.ShouldIgnoreRepoFile("", filePath)
+}
+
+// ShouldIgnoreRepoFile reports whether filePath, relative to the repository
+// root in dir, should be skipped, because it is larger than max-file-size or
+// matches ShouldIgnorePath.
+func (c *Config) ShouldIgnoreRepoFile(dir, filePath string) bool {
+	if !utils.NewSizeGuard(c.MaxFileSize).Allows(filepath.Join(dir, filePath)) {
+		return true
 	}
+	return c.ShouldIgnorePath(filePath)
+}
 
+// ShouldIgnorePath reports whether filePath, relative to the repository
+// root, matches the ignore-files or ignore-paths patterns. Its size is not
+// checked.
+func (c *Config) ShouldIgnorePath(filePath string) bool {
 	// Check against ignored file patterns
 	for _, pattern := range c.IgnoredFiles {
 		if matched, _ := filepath.Match(pattern, filepath.Base(filePath)); matched {
@@ -226,6 +744,76 @@ func (c *Config) ShouldIgnoreFile(filePath string) bool {
 	return false
 }
 
+// TestsShorthand stands for the test-file-patterns in the ignore paths of a
+// leaderboard, such as debt-ignore-paths = "@tests".
+const TestsShorthand = "@tests"
+
+// parseIgnorePaths parses the ignore paths of a leaderboard, which are path
+// patterns or TestsShorthand.
+func parseIgnorePaths(key, value string) ([]string, error) {
+	paths := parseList(value)
+	for _, item := range paths {
+		if strings.HasPrefix(item, "@") && item != TestsShorthand {
+			return nil, &cerrors.ErrConfigInvalid{Key: key, Value: item, Reason: "expected " + TestsShorthand + " or a path pattern"}
+		}
+	}
+	return paths, nil
+}
+
+// LeaderboardIgnorePaths returns the ignore paths of the debt, loc, churn
+// or spellcheck leaderboard, such as debt-ignore-paths for debt, with
+// TestsShorthand expanded to the test-file-patterns. Other leaderboards
+// have none.
+func (c *Config) LeaderboardIgnorePaths(leaderboard string) []string {
+	paths := map[string][]string{
+		"debt":       c.DebtIgnorePaths,
+		"loc":        c.LocIgnorePaths,
+		"churn":      c.ChurnIgnorePaths,
+		"spellcheck": c.SpellCheckIgnorePaths,
+	}[leaderboard]
+
+	var expanded []string
+	for _, pattern := range paths {
+		if pattern == TestsShorthand {
+			expanded = appendUnique(expanded, c.TestFilePatterns...)
+		} else {
+			expanded = appendUnique(expanded, pattern)
+		}
+	}
+	return expanded
+}
+
+// ShouldIgnoreForLeaderboard reports whether filePath, relative to the
+// repository root, matches the ignore paths of leaderboard. They leave
+// files out of that leaderboard alone, on top of ignore-files and
+// ignore-paths, which leave files out of all of them.
+func (c *Config) ShouldIgnoreForLeaderboard(leaderboard, filePath string) bool {
+	filePath = filepath.ToSlash(filePath)
+	for _, pattern := range c.LeaderboardIgnorePaths(leaderboard) {
+		if matchPathPattern(pattern, filePath) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPathPattern reports whether the slash-separated filePath matches a
+// pattern of the leaderboard ignore paths: one ending in a slash, such as
+// tests/, matches the files under a directory of that name, one with a
+// wildcard, such as *.spec.*, is a glob of the file name or the path, and
+// any other matches a part of the path, as ignore-paths does.
+func matchPathPattern(pattern, filePath string) bool {
+	switch {
+	case strings.HasSuffix(pattern, "/"):
+		return strings.Contains("/"+filePath, "/"+pattern)
+	case strings.ContainsAny(pattern, "*?["):
+		name, _ := path.Match(pattern, path.Base(filePath))
+		full, _ := path.Match(pattern, filePath)
+		return name || full
+	}
+	return strings.Contains(filePath, pattern)
+}
+
 func (c *Config) ShouldIgnoreAuthor(email string, name string) bool {
 	for _, ignored := range c.IgnoredAuthors {
 		// Exact match
@@ -243,20 +831,81 @@ func (c *Config) ShouldIgnoreAuthor(email string, name string) bool {
 	return false
 }
 
+// ShouldIgnoreRule reports whether the issues of ruleID are dropped: it is
+// one of ignore-rules, or starts with one of ignore-rule-prefixes.
 func (c *Config) ShouldIgnoreRule(ruleID string) bool {
 	for _, ignored := range c.IgnoredRules {
 		if ignored == ruleID {
 			return true
 		}
 	}
+	for _, prefix := range c.IgnoredRulePrefixes {
+		if strings.HasPrefix(ruleID, prefix) {
+			return true
+		}
+	}
 	return false
 }
 
+// SpellCheckSizeGuard returns the size guard of the spell check:
+// spellcheck-max-file-size when set, since prose files can legitimately be
+// large, and max-file-size otherwise.
+func (c *Config) SpellCheckSizeGuard() utils.SizeGuard {
+	if c.SpellCheckMaxFileSize > 0 {
+		return utils.NewSizeGuard(c.SpellCheckMaxFileSize)
+	}
+	return utils.NewSizeGuard(c.MaxFileSize)
+}
+
+// RuleSeverity returns the severity an issue of ruleID counts with, given
+// the one its linter reported, and false when the issue is dropped. Ignored
+// rules are always dropped, whatever their override says; otherwise the
+// first rule-severity-overrides pattern that matches ruleID decides.
+func (c *Config) RuleSeverity(ruleID string, severity int) (int, bool) {
+	if c.ShouldIgnoreRule(ruleID) {
+		return severity, false
+	}
+	for _, override := range c.RuleSeverityOverrides {
+		if matchRule(override.Pattern, ruleID) {
+			overridden, known := overrideSeverities[override.Severity]
+			return overridden, known
+		}
+	}
+	return severity, true
+}
+
+// RuleGroup returns the name of the first rule group with a pattern that
+// matches ruleID, or an empty string when it is in none.
+func (c *Config) RuleGroup(ruleID string) string {
+	for _, group := range c.RuleGroups {
+		for _, pattern := range group.Patterns {
+			if matchRule(pattern, ruleID) {
+				return group.Name
+			}
+		}
+	}
+	return ""
+}
+
+// maxAutoConcurrency caps the auto concurrency, since each blame is a git
+// process reading the object store and more mostly contend for disk.
+const maxAutoConcurrency = 16
+
+// GetConcurrency returns how many git blame operations may run at once. A
+// MaxConcurrentBlame of 0, written as auto in the config file, uses one per
+// CPU up to maxAutoConcurrency.
 func (c *Config) GetConcurrency() int {
 	if c.MaxConcurrentBlame > 0 {
 		return c.MaxConcurrentBlame
 	}
-	return 2 // Default
+	return min(max(runtime.NumCPU(), 1), maxAutoConcurrency)
+}
+
+func formatConcurrency(concurrent int) string {
+	if concurrent == 0 {
+		return "auto"
+	}
+	return strconv.Itoa(concurrent)
 }
 
 func GenerateConfigFile(filename string) error {
@@ -280,15 +929,64 @@ ignore-authors = "bot@company.com,dependabot,renovate,github-actions"
 # Additional ESLint rules to ignore beyond command line
 ignore-rules = "prefer-const,no-console"
 
+# Ignore every rule starting with one of these, such as a whole ESLint plugin
+# or a Ruff rule family
+# ignore-rule-prefixes = "@typescript-eslint/,D"
+
 # Maximum file size to analyze (in KB, 0 = no limit)
 max-file-size = 5000
 
-# Minimum coverage threshold for warnings (percentage)
+# Maximum length of a single line (in KB); files with a longer line, such as
+# minified bundles, are skipped with a warning by the scans that read lines
+max-line-size = 1024
+
+# Minimum coverage (percentage); files below it are highlighted, and
+# --fail-on coverage fails the run when overall coverage is below it
 min-coverage-threshold = 80
 
-# Maximum concurrent git blame operations
+# Days the coverage report may be older than the latest commit, or the
+# newest covered file, before --fail-on coverage-age fails the run
+coverage-max-age = 7
+
+# Maximum issues attributed from a single file, so one generated or minified
+# file cannot dominate the author leaderboard (0 = no limit)
+max-issues-per-file = 0
+
+# Rank authors and files by issues weighted by the age of the line they are
+# on, as last changed according to git blame: an issue counts half as much
+# every this many days, so recent problems rank higher (0 = no decay)
+decay-halflife-days = 0
+
+# Leave the issues new contributors, whose first commit falls in the --since
+# window, added in this many days after it off the author leaderboard,
+# counting them as new contributor issues instead (0 = no grace period)
+grace-period-days = 0
+
+# What each issue counts for when --weighted ranks authors and files, and
+# the quality score counts issues: an error counts weight-error, a warning
+# weight-warning, and the issues of rule-weights rules, given as IDs or
+# globs, the weight of the first matching pattern whatever their severity
+weight-error = 5
+weight-warning = 1
+# rule-weights = "security/*:20,no-console:0.5"
+
+# Authors with fewer commits are left out of --timezones
+timezone-min-commits = 10
+
+# Functions with more lines than this are listed by --long-functions
+long-function-lines = 50
+
+# Kinds of warnings listed after the leaderboards; --verbose lists every
+# warning (0 = no limit)
+max-warning-groups = 10
+
+# Maximum concurrent git blame operations ("auto" = one per CPU, up to 16)
 max-concurrent-blame = 4
 
+# git blame output to parse: "incremental" prints each commit's author once,
+# "line-porcelain" repeats it on every line
+blame-format = "incremental"
+
 # Cache git blame results for better performance
 cache-results = true
 
@@ -308,6 +1006,88 @@ spellcheck-enabled = true
 custom-words = "api,url,auth,oauth,async,await,json,xml,css,html,dom,ui,ux"
 spellcheck-extensions = ".js,.ts,.jsx,.tsx,.md,.txt,.py,.java"
 spellcheck-ignore-paths = "node_modules,dist,build,coverage"
+spellcheck-min-word-length = 4
+
+# Leave files out of one leaderboard only, on top of ignore-files and
+# ignore-paths. "@tests" stands for the test-file-patterns, so tests still
+# count in the lines of code and coverage but not in the debt or spell check.
+# Patterns ending in / match directories and those with * are globs.
+# debt-ignore-paths = "@tests,fixtures/"
+# loc-ignore-paths = "migrations/"
+# churn-ignore-paths = "CHANGELOG.md"
+# test-file-patterns = "*_test.*,*.test.*,*.spec.*,test_*.py,test/,tests/,__tests__/,spec/"
+
+# Maximum file size to spell check (in KB, 0 = max-file-size), since
+# documentation can legitimately be larger than source files
+# spellcheck-max-file-size = 20000
+
+# Report card (--report-card) category weights and the minimum scores for
+# grades A, B, C and D
+report-card-weights = "coverage:25,issues:25,debt:15,spelling:10,bus-factor:25"
+report-card-cutoffs = "90,80,70,60"
+
+# Quality score (--score) component weights. Components that were not
+# measured, such as coverage without a coverage report, are left out and the
+# other weights scaled up to make up for them
+score-weights = "issues:30,coverage:25,debt:15,churn:15,complexity:15"
+
+# Files --attention-out lists: those whose quality score is below
+# attention-score, or with any component of it scoring below
+# attention-component-score (0 = off)
+attention-score = 60
+attention-component-score = 25
+
+# Commit timestamps: "author" (when a change was written) or "commit" (when
+# it was last rebased or cherry-picked onto a branch)
+date-type = "author"
+
+# How the commit counts credit the co-authors named by Co-authored-by
+# trailers: "full" counts the commit for each of them as for its author,
+# "split" shares one commit's credit between them, and "none" only counts it
+# for its author
+co-author-credit = "full"
+
+# How ESLint severities count: off drops the issue, warning and error pick
+# the bucket used by the leaderboards
+eslint-severity-map = "0:off,1:warning,2:error"
+
+# Count the issues of some rules differently from how their linter reports
+# them, whatever the source: error, warning, or ignore to drop them. Rules
+# are IDs or globs, and the first matching pattern wins; ignore-rules always
+# drops a rule, whatever its override
+# rule-severity-overrides = "no-console:error,react/*:warning,import/order:ignore"
+
+# Groups for the --group-rules leaderboard, one rule-group.NAME key per group.
+# A rule counts towards the first group with a matching pattern, and rules in
+# no group count as ungrouped
+# rule-group.correctness = "no-undef,eqeqeq,react-hooks/*"
+# rule-group.style = "indent,quotes,semi"
+
+# Rules of the custom lint source, one custom-rule key per rule: every line
+# of an analyzed file matching the regular expression after the colon is a
+# warning of the rule named before it, blamed like any other issue
+# custom-rule = "no-print:console\\.log"
+# custom-rule = "no-fixme:\\bFIXME\\b"
+
+# Documentation links of the rules of a plugin, shown in the rule
+# leaderboard with --verbose and in the report as doc_url. {rule} is replaced
+# with the rule name without its plugin namespace. ESLint core rules, Ruff
+# codes and well-known plugins such as @typescript-eslint are linked already;
+# use core for rules without a namespace
+# rule-doc-link.@acme/lint = "https://lint.acme.dev/rules/{rule}"
+
+# Series exported by --timeseries-out: summary, author-issues, coverage, debt
+# and score
+timeseries-metrics = "summary,author-issues,coverage,debt,score"
+
+# Where the owner of a file in --files-by-owner comes from, tried in order:
+# codeowners (its CODEOWNERS rule) and blame (the author of most of its
+# lines). Files no source resolves are unowned
+owner-resolution = "codeowners,blame"
+
+# Self-hosted GitLab instance for --github-stats, when its host name does not
+# start with gitlab. or it is served under a path
+# gitlab-base-url = "https://code.example.com"
 
 # Ruff (Python Linter) configuration
 ruff-enabled = true
@@ -319,15 +1099,170 @@ ruff-ignore-paths = "venv,.venv,migrations"
 	return os.WriteFile(filename, []byte(content), 0644)
 }
 
-func (c *Config) PrintSummary() {
-	fmt.Printf("🧭 Configuration Summary:\n")
-	fmt.Printf("  • Ignored files: %d patterns\n", len(c.IgnoredFiles))
-	fmt.Printf("  • Ignored authors: %d patterns\n", len(c.IgnoredAuthors))
-	fmt.Printf("  • Ignored rules: %d rules\n", len(c.IgnoredRules))
-	fmt.Printf("  • Max concurrent blame: %d\n", c.MaxConcurrentBlame)
-	fmt.Printf("  • Cache results: %t\n", c.CacheResults)
+// WriteEffective writes the fully resolved configuration to w in
+// .codecompass.rc format, so loading the output yields the same settings.
+func (c *Config) WriteEffective(w io.Writer) error {
+	settings := []struct {
+		key   string
+		value string
+	}{
+		{"ignore-files", formatList(c.IgnoredFiles)},
+		{"ignore-paths", formatList(c.IgnoredPaths)},
+		{"ignore-authors", formatList(c.IgnoredAuthors)},
+		{"ignore-rules", formatList(c.IgnoredRules)},
+		{"ignore-rule-prefixes", formatList(c.IgnoredRulePrefixes)},
+		{"max-file-size", strconv.Itoa(c.MaxFileSize)},
+		{"max-line-size", strconv.Itoa(c.MaxLineSize)},
+		{"min-coverage-threshold", strconv.FormatFloat(c.MinCoverageThreshold, 'g', -1, 64)},
+		{"coverage-max-age", strconv.Itoa(c.CoverageMaxAge)},
+		{"max-issues-per-file", strconv.Itoa(c.MaxIssuesPerFile)},
+		{"decay-halflife-days", strconv.FormatFloat(c.DecayHalfLifeDays, 'g', -1, 64)},
+		{"grace-period-days", strconv.Itoa(c.GracePeriodDays)},
+		{"weight-error", strconv.FormatFloat(c.IssueWeights.Error, 'g', -1, 64)},
+		{"weight-warning", strconv.FormatFloat(c.IssueWeights.Warning, 'g', -1, 64)},
+		{"rule-weights", formatRuleWeights(c.IssueWeights.Rules)},
+		{"timezone-min-commits", strconv.Itoa(c.TimezoneMinCommits)},
+		{"long-function-lines", strconv.Itoa(c.LongFunctionLines)},
+		{"max-warning-groups", strconv.Itoa(c.MaxWarningGroups)},
+		{"max-concurrent-blame", formatConcurrency(c.MaxConcurrentBlame)},
+		{"blame-format", c.BlameFormat},
+		{"cache-results", strconv.FormatBool(c.CacheResults)},
+		{"enable-git-hooks", strconv.FormatBool(c.EnableGitHooks)},
+		{"custom-words", formatList(c.CustomWords)},
+		{"spellcheck-enabled", strconv.FormatBool(c.SpellCheckEnabled)},
+		{"spellcheck-extensions", formatList(c.SpellCheckExtensions)},
+		{"spellcheck-ignore-paths", formatList(c.SpellCheckIgnorePaths)},
+		{"debt-ignore-paths", formatList(c.DebtIgnorePaths)},
+		{"loc-ignore-paths", formatList(c.LocIgnorePaths)},
+		{"churn-ignore-paths", formatList(c.ChurnIgnorePaths)},
+		{"test-file-patterns", formatList(c.TestFilePatterns)},
+		{"spellcheck-min-word-length", strconv.Itoa(c.SpellCheckMinWordLen)},
+		{"spellcheck-max-file-size", strconv.Itoa(c.SpellCheckMaxFileSize)},
+		{"ruff-enabled", strconv.FormatBool(c.RuffEnabled)},
+		{"ruff-rules", formatList(c.RuffRules)},
+		{"ruff-ignore-paths", formatList(c.RuffIgnorePaths)},
+		{"report-card-weights", formatWeights(c.ReportCardWeights, ReportCardCategories)},
+		{"report-card-cutoffs", formatFloats(c.ReportCardCutoffs)},
+		{"score-weights", formatWeights(c.ScoreWeights, ScoreComponents)},
+		{"attention-score", strconv.FormatFloat(c.AttentionScore, 'g', -1, 64)},
+		{"attention-component-score", strconv.FormatFloat(c.AttentionComponent, 'g', -1, 64)},
+		{"date-type", c.DateType},
+		{"co-author-credit", c.CoAuthorCredit},
+		{"eslint-severity-map", formatSeverities(c.ESLintSeverities)},
+		{"rule-severity-overrides", formatOverrides(c.RuleSeverityOverrides)},
+		{"gitlab-base-url", strconv.Quote(c.GitLabBaseURL)},
+		{"timeseries-metrics", formatList(c.TimeSeriesMetrics)},
+		{"owner-resolution", formatList(c.OwnerResolution)},
+	}
+
+	var b strings.Builder
+	b.WriteString("# CodeCompass effective configuration 🧭\n")
+	for _, setting := range settings {
+		fmt.Fprintf(&b, "%s = %s\n", setting.key, setting.value)
+	}
+	for _, group := range c.RuleGroups {
+		fmt.Fprintf(&b, "%s%s = %s\n", ruleGroupPrefix, group.Name, formatList(group.Patterns))
+	}
+	for _, rule := range c.CustomRules {
+		fmt.Fprintf(&b, "custom-rule = %s\n", strconv.Quote(rule.Name+":"+rule.Pattern))
+	}
+	plugins := make([]string, 0, len(c.RuleDocLinks))
+	for plugin := range c.RuleDocLinks {
+		plugins = append(plugins, plugin)
+	}
+	sort.Strings(plugins)
+	for _, plugin := range plugins {
+		fmt.Fprintf(&b, "%s%s = %s\n", ruleDocLinkPrefix, plugin, strconv.Quote(c.RuleDocLinks[plugin]))
+	}
+
+	if len(c.CustomSettings) > 0 {
+		keys := make([]string, 0, len(c.CustomSettings))
+		for key := range c.CustomSettings {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		b.WriteString("\n# Custom project-specific settings\n")
+		for _, key := range keys {
+			fmt.Fprintf(&b, "%s = %s\n", key, strconv.Quote(c.CustomSettings[key]))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func formatList(items []string) string {
+	return strconv.Quote(strings.Join(items, ","))
+}
+
+func formatFloats(values []float64) string {
+	items := make([]string, len(values))
+	for i, value := range values {
+		items[i] = strconv.FormatFloat(value, 'g', -1, 64)
+	}
+	return formatList(items)
+}
+
+// formatWeights lists weights in the order of names.
+func formatWeights(weights map[string]float64, names []string) string {
+	var items []string
+	for _, name := range names {
+		if weight, exists := weights[name]; exists {
+			items = append(items, name+":"+strconv.FormatFloat(weight, 'g', -1, 64))
+		}
+	}
+	return formatList(items)
+}
+
+func formatOverrides(overrides []RuleSeverityOverride) string {
+	items := make([]string, len(overrides))
+	for i, override := range overrides {
+		items[i] = override.Pattern + ":" + override.Severity
+	}
+	return formatList(items)
+}
+
+func formatRuleWeights(weights []RuleWeight) string {
+	items := make([]string, len(weights))
+	for i, weight := range weights {
+		items[i] = weight.Pattern + ":" + strconv.FormatFloat(weight.Weight, 'g', -1, 64)
+	}
+	return formatList(items)
+}
+
+func formatSeverities(severities map[int]int) string {
+	eslintSeverities := make([]int, 0, len(severities))
+	for severity := range severities {
+		eslintSeverities = append(eslintSeverities, severity)
+	}
+	sort.Ints(eslintSeverities)
+
+	var items []string
+	for _, eslintSeverity := range eslintSeverities {
+		for name, severity := range severityNames {
+			if severity == severities[eslintSeverity] {
+				items = append(items, strconv.Itoa(eslintSeverity)+":"+name)
+			}
+		}
+	}
+	return formatList(items)
+}
+
+// PrintSummary writes a short overview of the configuration to w.
+func (c *Config) PrintSummary(w io.Writer) {
+	fmt.Fprintf(w, "🧭 Configuration Summary:\n")
+	fmt.Fprintf(w, "  • Ignored files: %d patterns\n", len(c.IgnoredFiles))
+	fmt.Fprintf(w, "  • Ignored authors: %d patterns\n", len(c.IgnoredAuthors))
+	fmt.Fprintf(w, "  • Ignored rules: %d rules, %d prefixes\n", len(c.IgnoredRules), len(c.IgnoredRulePrefixes))
+	if c.MaxConcurrentBlame == 0 {
+		fmt.Fprintf(w, "  • Max concurrent blame: auto (%d)\n", c.GetConcurrency())
+	} else {
+		fmt.Fprintf(w, "  • Max concurrent blame: %d\n", c.MaxConcurrentBlame)
+	}
+	fmt.Fprintf(w, "  • Cache results: %t\n", c.CacheResults)
 
 	if c.MaxFileSize > 0 {
-		fmt.Printf("  • Max file size: %d KB\n", c.MaxFileSize)
+		fmt.Fprintf(w, "  • Max file size: %d KB\n", c.MaxFileSize)
 	}
 }
@@ -1,8 +1,16 @@
 package config
 
 import (
+	"bytes"
+	"errors"
 	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
 	"testing"
+
+	"github.com/xeon-zolt/codecompass/internal/cerrors"
 )
 
 func TestNewConfig(t *testing.T) {
@@ -84,6 +92,53 @@ func TestShouldIgnoreFile(t *testing.T) {
 	}
 }
 
+func TestShouldIgnoreForLeaderboard(t *testing.T) {
+	c := NewConfig()
+	for key, value := range map[string]string{
+		"debt-ignore-paths":  "@tests,fixtures/",
+		"churn-ignore-paths": "CHANGELOG.md",
+	} {
+		if err := c.parseKeyValue(key, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, tc := range []struct {
+		leaderboard, path string
+		expected          bool
+	}{
+		{"debt", "pkg/app_test.go", true},
+		{"debt", "src/button.spec.tsx", true},
+		{"debt", "tests/test_api.py", true},
+		{"debt", "src/__tests__/app.js", true},
+		{"debt", "fixtures/data.json", true},
+		{"debt", "src/latest/app.go", false},
+		{"debt", "src/contest.go", false},
+		{"debt", "pkg/app.go", false},
+		{"churn", "CHANGELOG.md", true},
+		{"churn", "pkg/app_test.go", false},
+		{"loc", "pkg/app_test.go", false},
+		{"spellcheck", "node_modules/x/readme.md", true},
+		{"authors", "pkg/app_test.go", false},
+	} {
+		if got := c.ShouldIgnoreForLeaderboard(tc.leaderboard, tc.path); got != tc.expected {
+			t.Errorf("ShouldIgnoreForLeaderboard(%q, %q) = %v, expected %v", tc.leaderboard, tc.path, got, tc.expected)
+		}
+	}
+
+	// The shorthand follows test-file-patterns
+	if err := c.parseKeyValue("test-file-patterns", "*_check.go"); err != nil {
+		t.Fatal(err)
+	}
+	if !c.ShouldIgnoreForLeaderboard("debt", "pkg/app_check.go") || c.ShouldIgnoreForLeaderboard("debt", "pkg/app_test.go") {
+		t.Errorf("Expected @tests to stand for test-file-patterns, but got %v", c.LeaderboardIgnorePaths("debt"))
+	}
+
+	if err := c.parseKeyValue("loc-ignore-paths", "@test"); err == nil {
+		t.Error("Expected an error for an unknown shorthand")
+	}
+}
+
 func TestShouldIgnoreAuthor(t *testing.T) {
 	c := NewConfig()
 	c.IgnoredAuthors = []string{"test@example.com", "Test User"}
@@ -113,3 +168,417 @@ func TestShouldIgnoreRule(t *testing.T) {
 		t.Errorf("Expected not to ignore no-alert")
 	}
 }
+
+func TestShouldIgnoreRulePrefix(t *testing.T) {
+	c := NewConfig()
+	if err := c.parseKeyValue("ignore-rule-prefixes", "@typescript-eslint/, D1"); err != nil {
+		t.Fatal(err)
+	}
+
+	for rule, ignored := range map[string]bool{
+		"@typescript-eslint/no-explicit-any": true,
+		"@typescript-eslint/ban-types":       true,
+		"D100":                               true,
+		"D103":                               true,
+		"D200":                               false,
+		"react/jsx-key":                      false,
+		"@typescript":                        false,
+		"no-console":                         false,
+	} {
+		if got := c.ShouldIgnoreRule(rule); got != ignored {
+			t.Errorf("ShouldIgnoreRule(%q) = %t; expected %t", rule, got, ignored)
+		}
+	}
+
+	// Ignored prefixes win over severity overrides like ignored rules
+	if err := c.parseKeyValue("rule-severity-overrides", "@typescript-eslint/*:error"); err != nil {
+		t.Fatal(err)
+	}
+	if _, kept := c.RuleSeverity("@typescript-eslint/ban-types", 1); kept {
+		t.Errorf("Expected issues of an ignored prefix to be dropped")
+	}
+}
+
+func TestRuleSeverity(t *testing.T) {
+	c := NewConfig()
+	c.IgnoredRules = []string{"no-console"}
+	for _, value := range []string{"no-console:error,react/jsx-key:error", "react/*:warning,import/*:ignore,eqeqeq:error"} {
+		if err := c.parseKeyValue("rule-severity-overrides", value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		rule     string
+		severity int
+		expected int
+		kept     bool
+	}{
+		// ignore-rules wins over any override
+		{"no-console", 1, 0, false},
+		// The first matching pattern wins over a later glob
+		{"react/jsx-key", 1, 2, true},
+		{"react/no-danger", 2, 1, true},
+		{"import/order", 2, 0, false},
+		{"eqeqeq", 1, 2, true},
+		// Globs do not match across a slash, and other rules are untouched
+		{"@typescript-eslint/react/x", 2, 2, true},
+		{"no-undef", 1, 1, true},
+	}
+	for _, tt := range tests {
+		severity, kept := c.RuleSeverity(tt.rule, tt.severity)
+		if kept != tt.kept || (kept && severity != tt.expected) {
+			t.Errorf("RuleSeverity(%q, %d) = %d, %v; expected %d, %v", tt.rule, tt.severity, severity, kept, tt.expected, tt.kept)
+		}
+	}
+
+	for _, value := range []string{"no-console", "no-console:fatal", ":error", "[:error"} {
+		if err := NewConfig().parseKeyValue("rule-severity-overrides", value); err == nil {
+			t.Errorf("Expected an error for rule-severity-overrides = %q", value)
+		}
+	}
+}
+
+func TestIssueWeights(t *testing.T) {
+	c := NewConfig()
+	for key, value := range map[string]string{"weight-warning": "0.5", "rule-weights": "security/detect-eval:50,security/*:20,no-console:0"} {
+		if err := c.parseKeyValue(key, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		rule     string
+		isError  bool
+		expected float64
+	}{
+		{"eqeqeq", true, 5},
+		{"eqeqeq", false, 0.5},
+		// The first matching pattern wins, whatever the severity
+		{"security/detect-eval", false, 50},
+		{"security/detect-object-injection", true, 20},
+		{"no-console", true, 0},
+	}
+	for _, tt := range tests {
+		if weight := c.IssueWeights.Weight(tt.rule, tt.isError); weight != tt.expected {
+			t.Errorf("Weight(%q, %v) = %v; expected %v", tt.rule, tt.isError, weight, tt.expected)
+		}
+	}
+
+	for key, value := range map[string]string{"weight-error": "-1", "weight-warning": "heavy", "rule-weights": "no-console"} {
+		if err := NewConfig().parseKeyValue(key, value); err == nil {
+			t.Errorf("Expected an error for %s = %q", key, value)
+		}
+	}
+	if err := NewConfig().parseKeyValue("rule-weights", "[:1"); err == nil {
+		t.Error("Expected an error for an invalid rule-weights glob")
+	}
+}
+
+func TestRuleGroup(t *testing.T) {
+	c := NewConfig()
+	for key, value := range map[string]string{
+		"rule-group.correctness": "no-undef,eqeqeq,react-hooks/*",
+		"rule-group.style":       "indent,quotes",
+	} {
+		if err := c.parseKeyValue(key, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Repeating a group adds to it
+	if err := c.parseKeyValue("rule-group.style", "semi,quotes"); err != nil {
+		t.Fatal(err)
+	}
+
+	for rule, expected := range map[string]string{
+		"no-undef":                       "correctness",
+		"react-hooks/exhaustive-deps":    "correctness",
+		"semi":                           "style",
+		"quotes":                         "style",
+		"@typescript-eslint/no-explicit": "",
+	} {
+		if group := c.RuleGroup(rule); group != expected {
+			t.Errorf("Expected %s in group %q, but got %q", rule, expected, group)
+		}
+	}
+	if _, exists := c.CustomSettings["rule-group.style"]; exists {
+		t.Errorf("Expected rule groups not to be custom settings")
+	}
+
+	if err := NewConfig().parseKeyValue("rule-group.", "semi"); err == nil {
+		t.Errorf("Expected an error for a rule group without a name")
+	}
+}
+
+func TestRuleDocLink(t *testing.T) {
+	c := NewConfig()
+	if err := c.parseKeyValue("rule-doc-link.@acme/lint", "https://lint.acme.dev/rules/{rule}"); err != nil {
+		t.Fatal(err)
+	}
+	if link := c.RuleDocLinks["@acme/lint"]; link != "https://lint.acme.dev/rules/{rule}" {
+		t.Errorf("Expected the @acme/lint template, but got %q", link)
+	}
+	if _, exists := c.CustomSettings["rule-doc-link.@acme/lint"]; exists {
+		t.Errorf("Expected rule doc links not to be custom settings")
+	}
+
+	for key, value := range map[string]string{
+		"rule-doc-link.":     "https://lint.acme.dev/{rule}",
+		"rule-doc-link.team": "wiki/{rule}",
+	} {
+		var configErr *cerrors.ErrConfigInvalid
+		if err := NewConfig().parseKeyValue(key, value); !errors.As(err, &configErr) {
+			t.Errorf("Expected ErrConfigInvalid for %s = %q, but got %v", key, value, err)
+		}
+	}
+}
+
+func TestCustomRule(t *testing.T) {
+	c := NewConfig()
+	for _, value := range []string{`no-print:console\.log`, "no-fixme:FIXME: ", `no-print:print\(`} {
+		if err := c.parseKeyValue("custom-rule", value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected := []CustomRule{{Name: "no-print", Pattern: `print\(`}, {Name: "no-fixme", Pattern: "FIXME: "}}
+	if !reflect.DeepEqual(c.CustomRules, expected) {
+		t.Errorf("Expected %+v, but got %+v", expected, c.CustomRules)
+	}
+
+	for _, value := range []string{"console.log", ":console", "no-print:", "no print:x", "no-print:(unclosed", "no-todo:TODO(?!x)"} {
+		var configErr *cerrors.ErrConfigInvalid
+		if err := NewConfig().parseKeyValue("custom-rule", value); !errors.As(err, &configErr) {
+			t.Errorf("Expected ErrConfigInvalid for %q, but got %v", value, err)
+		}
+	}
+}
+
+func TestParseSpellCheckMinWordLength(t *testing.T) {
+	c := NewConfig()
+	if c.SpellCheckMinWordLen != 4 {
+		t.Errorf("Expected default SpellCheckMinWordLen to be 4, but got %d", c.SpellCheckMinWordLen)
+	}
+
+	if err := c.parseKeyValue("spellcheck-min-word-length", "3"); err != nil {
+		t.Fatal(err)
+	}
+	if c.SpellCheckMinWordLen != 3 {
+		t.Errorf("Expected SpellCheckMinWordLen to be 3, but got %d", c.SpellCheckMinWordLen)
+	}
+
+	if err := c.parseKeyValue("spellcheck-min-word-length", "0"); err == nil {
+		t.Errorf("Expected an error for a non-positive spellcheck-min-word-length")
+	}
+}
+
+func TestGetConcurrencyAuto(t *testing.T) {
+	c := NewConfig()
+	if c.GetConcurrency() != 4 {
+		t.Errorf("Expected the default concurrency to be 4, but got %d", c.GetConcurrency())
+	}
+
+	if err := c.parseKeyValue("max-concurrent-blame", "auto"); err != nil {
+		t.Fatal(err)
+	}
+	expected := min(runtime.NumCPU(), maxAutoConcurrency)
+	if got := c.GetConcurrency(); got != expected || got < 1 || got > maxAutoConcurrency {
+		t.Errorf("Expected auto concurrency to be %d, but got %d", expected, got)
+	}
+
+	if err := c.parseKeyValue("max-concurrent-blame", "-1"); err == nil {
+		t.Errorf("Expected an error for a negative max-concurrent-blame")
+	}
+}
+
+func TestParseReportCardSettings(t *testing.T) {
+	c := NewConfig()
+	if err := c.parseKeyValue("report-card-weights", "coverage:40, debt:0"); err != nil {
+		t.Fatal(err)
+	}
+	if c.ReportCardWeights["coverage"] != 40 || c.ReportCardWeights["debt"] != 0 {
+		t.Errorf("Expected coverage and debt weights to be updated, but got %v", c.ReportCardWeights)
+	}
+	if c.ReportCardWeights["issues"] != 25 {
+		t.Errorf("Expected unlisted weights to keep their defaults, but got %v", c.ReportCardWeights)
+	}
+
+	for _, value := range []string{"style:10", "coverage:-1", "coverage"} {
+		if err := c.parseKeyValue("report-card-weights", value); err == nil {
+			t.Errorf("Expected an error for report-card-weights %q", value)
+		}
+	}
+
+	if err := c.parseKeyValue("report-card-cutoffs", "95,85,75,65"); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(c.ReportCardCutoffs, []float64{95, 85, 75, 65}) {
+		t.Errorf("Expected cutoffs [95 85 75 65], but got %v", c.ReportCardCutoffs)
+	}
+
+	for _, value := range []string{"90,80,70", "60,70,80,90", "90,80,x,60"} {
+		if err := c.parseKeyValue("report-card-cutoffs", value); err == nil {
+			t.Errorf("Expected an error for report-card-cutoffs %q", value)
+		}
+	}
+}
+
+func TestParseScoreWeights(t *testing.T) {
+	c := NewConfig()
+	if err := c.parseKeyValue("score-weights", "churn:0, complexity:5.5"); err != nil {
+		t.Fatal(err)
+	}
+	if c.ScoreWeights["churn"] != 0 || c.ScoreWeights["complexity"] != 5.5 || c.ScoreWeights["issues"] != 30 {
+		t.Errorf("Expected churn and complexity weights to be updated, but got %v", c.ScoreWeights)
+	}
+	for _, value := range []string{"spelling:10", "issues:-1", "issues"} {
+		if err := c.parseKeyValue("score-weights", value); err == nil {
+			t.Errorf("Expected an error for score-weights %q", value)
+		}
+	}
+}
+
+func TestParseESLintSeverityMap(t *testing.T) {
+	c := NewConfig()
+	if !reflect.DeepEqual(c.ESLintSeverities, map[int]int{0: 0, 1: 1, 2: 2}) {
+		t.Errorf("Expected ESLint severities to map to themselves by default, but got %v", c.ESLintSeverities)
+	}
+
+	if err := c.parseKeyValue("eslint-severity-map", "1:error, 0:warning"); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(c.ESLintSeverities, map[int]int{0: 1, 1: 2, 2: 2}) {
+		t.Errorf("Expected 1 to map to error and 0 to warning, but got %v", c.ESLintSeverities)
+	}
+
+	for _, value := range []string{"1:fatal", "x:error", "-1:off", "1"} {
+		if err := c.parseKeyValue("eslint-severity-map", value); err == nil {
+			t.Errorf("Expected an error for eslint-severity-map %q", value)
+		}
+	}
+}
+
+func TestParseOwnerResolution(t *testing.T) {
+	c := NewConfig()
+	if !reflect.DeepEqual(c.OwnerResolution, []string{"codeowners", "blame"}) {
+		t.Errorf("Expected CODEOWNERS before blame by default, but got %v", c.OwnerResolution)
+	}
+
+	if err := c.parseKeyValue("owner-resolution", "blame"); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(c.OwnerResolution, []string{"blame"}) {
+		t.Errorf("Expected blame only, but got %v", c.OwnerResolution)
+	}
+
+	for _, value := range []string{"", "git", "blame,blame"} {
+		if err := c.parseKeyValue("owner-resolution", value); err == nil {
+			t.Errorf("Expected an error for owner-resolution %q", value)
+		}
+	}
+}
+
+func TestWriteEffectiveRoundTrip(t *testing.T) {
+	c := NewConfig()
+	for key, value := range map[string]string{
+		"ignore-files":               "*.log,*.tmp",
+		"ignore-paths":               "vendor",
+		"ignore-authors":             "dependabot",
+		"ignore-rules":               "no-console,prefer-const",
+		"ignore-rule-prefixes":       "@typescript-eslint/,D",
+		"max-file-size":              "100",
+		"max-line-size":              "4096",
+		"max-issues-per-file":        "200",
+		"decay-halflife-days":        "90",
+		"grace-period-days":          "30",
+		"weight-error":               "10",
+		"weight-warning":             "0.5",
+		"rule-weights":               "security/*:20,no-console:0",
+		"max-concurrent-blame":       "auto",
+		"blame-format":               "line-porcelain",
+		"timezone-min-commits":       "3",
+		"long-function-lines":        "80",
+		"max-warning-groups":         "0",
+		"min-coverage-threshold":     "72.5",
+		"coverage-max-age":           "14",
+		"cache-results":              "false",
+		"custom-words":               "oauth,kubectl",
+		"spellcheck-extensions":      ".go,.md",
+		"spellcheck-min-word-length": "3",
+		"spellcheck-max-file-size":   "20000",
+		"ruff-rules":                 "E501",
+		"report-card-weights":        "coverage:40,spelling:2.5",
+		"report-card-cutoffs":        "95,85,75,65",
+		"score-weights":              "coverage:40,churn:0",
+		"attention-score":            "55.5",
+		"attention-component-score":  "0",
+		"date-type":                  "commit",
+		"co-author-credit":           "split",
+		"eslint-severity-map":        "1:error,3:warning",
+		"rule-severity-overrides":    "no-console:error,react/*:ignore",
+		"rule-group.correctness":     "no-undef,react-hooks/*",
+		"rule-group.style":           "indent,quotes",
+		"rule-doc-link.@acme/lint":   "https://lint.acme.dev/rules/{rule}?ref=x",
+		"rule-doc-link.team":         "https://wiki.example.com/{rule}",
+		"custom-rule":                `no-print:console\.(log|debug)\("`,
+		"gitlab-base-url":            "https://code.example.com/gitlab",
+		"timeseries-metrics":         "coverage,debt",
+		"owner-resolution":           "blame,codeowners",
+		"ruff-ignore-paths":          "venv",
+		"debt-ignore-paths":          "@tests,fixtures/",
+		"loc-ignore-paths":           "migrations/",
+		"churn-ignore-paths":         "CHANGELOG.md",
+		"test-file-patterns":         "*_test.go,testdata/",
+		"project-name":               "My Project",
+		"team-name":                  `The "Core" Team \ Ops`,
+	} {
+		if err := c.parseKeyValue(key, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := c.WriteEffective(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	tmpfile := filepath.Join(t.TempDir(), ".codecompass.rc")
+	if err := os.WriteFile(tmpfile, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadConfigFromFile(tmpfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(c, loaded) {
+		t.Errorf("Expected reloaded config to match the original\noriginal: %+v\nreloaded: %+v\ndump:\n%s", c, loaded, buf.String())
+	}
+}
+
+func TestLoadConfigFromFileInvalidValue(t *testing.T) {
+	tmpfile := filepath.Join(t.TempDir(), ".codecompass.rc")
+	if err := os.WriteFile(tmpfile, []byte("ignore-rules=no-console\nmax-file-size=big\ndate-type=later\nmax-concurrent-blame=8\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadConfigFromFile(tmpfile)
+	var configErr *cerrors.ErrConfigInvalid
+	if !errors.As(err, &configErr) {
+		t.Fatalf("Expected ErrConfigInvalid, but got %v", err)
+	}
+	if configErr.Key != "max-file-size" || configErr.Value != "big" {
+		t.Errorf("Expected max-file-size=big to be reported, but got %+v", configErr)
+	}
+	if !strings.Contains(err.Error(), ":2:") || !strings.Contains(err.Error(), ":3:") {
+		t.Errorf("Expected the error to name lines 2 and 3, but got %q", err)
+	}
+
+	// Valid keys around the invalid ones are still applied
+	if cfg == nil || !cfg.ShouldIgnoreRule("no-console") || cfg.MaxConcurrentBlame != 8 {
+		t.Errorf("Expected the valid keys to be applied, but got %+v", cfg)
+	}
+	if cfg != nil && (cfg.MaxFileSize != 5000 || cfg.DateType != "author") {
+		t.Errorf("Expected invalid keys to keep their defaults, but got %+v", cfg)
+	}
+}
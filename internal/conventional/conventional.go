@@ -0,0 +1,122 @@
+// Package conventional parses commit messages written to the Conventional
+// Commits 1.0.0 specification, as read by changelog generators.
+package conventional
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// ErrNotConventional is returned for a message whose header does not follow
+// the specification.
+var ErrNotConventional = errors.New("not a conventional commit")
+
+// Commit is a parsed commit message.
+type Commit struct {
+	Type        string // Lowercased, such as feat or fix
+	Scope       string
+	Description string
+	Body        string
+	Footers     []Footer
+
+	// Breaking is set by a ! before the colon of the header or by a
+	// BREAKING CHANGE footer
+	Breaking bool
+}
+
+// Footer is a git trailer style footer. Its value may span several lines and
+// paragraphs.
+type Footer struct {
+	Token string
+	Value string
+}
+
+// BreakingNote returns the value of the BREAKING CHANGE footer, or the
+// description for a commit marked breaking by ! alone.
+func (c Commit) BreakingNote() string {
+	for _, footer := range c.Footers {
+		if isBreakingToken(footer.Token) {
+			return footer.Value
+		}
+	}
+	if c.Breaking {
+		return c.Description
+	}
+	return ""
+}
+
+// header matches the first line: type, optional scope, optional ! and the
+// description after a colon and a space.
+var header = regexp.MustCompile(`^([A-Za-z]+)(?:\(([^()\r\n]*)\))?(!)?: (\S.*)$`)
+
+// footerStart matches the first line of a footer: a token, where words are
+// joined by hyphens except in BREAKING CHANGE, then ": " or " #".
+var footerStart = regexp.MustCompile(`^(BREAKING CHANGE|[A-Za-z][A-Za-z0-9-]*)(?:: | #)(.*)$`)
+
+// Parse parses message. The body runs from the paragraph after the header to
+// the first paragraph that starts with a footer; everything from there on
+// belongs to the footers, so a footer value can hold blank lines.
+func Parse(message string) (Commit, error) {
+	message = strings.ReplaceAll(message, "\r\n", "\n")
+	lines := strings.Split(strings.TrimRight(message, "\n"), "\n")
+
+	match := header.FindStringSubmatch(strings.TrimRight(lines[0], " \t"))
+	if match == nil {
+		return Commit{}, ErrNotConventional
+	}
+	commit := Commit{
+		Type:        strings.ToLower(match[1]),
+		Scope:       strings.TrimSpace(match[2]),
+		Breaking:    match[3] == "!",
+		Description: strings.TrimSpace(match[4]),
+	}
+
+	// The body must be separated from the header by a blank line, but be
+	// lenient with messages that leave it out
+	rest := lines[1:]
+	if len(rest) > 0 && strings.TrimSpace(rest[0]) == "" {
+		rest = rest[1:]
+	}
+
+	footersAt := len(rest)
+	for i, line := range rest {
+		if (i == 0 || strings.TrimSpace(rest[i-1]) == "") && footerStart.MatchString(line) {
+			footersAt = i
+			break
+		}
+	}
+	commit.Body = strings.TrimSpace(strings.Join(rest[:footersAt], "\n"))
+
+	var value []string
+	flush := func() {
+		if n := len(commit.Footers); n > 0 {
+			commit.Footers[n-1].Value = strings.TrimSpace(strings.Join(value, "\n"))
+		}
+		value = nil
+	}
+	for _, line := range rest[footersAt:] {
+		if match := footerStart.FindStringSubmatch(line); match != nil {
+			flush()
+			commit.Footers = append(commit.Footers, Footer{Token: match[1]})
+			value = []string{match[2]}
+			continue
+		}
+		value = append(value, line)
+	}
+	flush()
+
+	for _, footer := range commit.Footers {
+		if isBreakingToken(footer.Token) {
+			commit.Breaking = true
+		}
+	}
+
+	return commit, nil
+}
+
+// isBreakingToken reports whether token is BREAKING CHANGE or its synonym
+// BREAKING-CHANGE. Both must be uppercase.
+func isBreakingToken(token string) bool {
+	return token == "BREAKING CHANGE" || token == "BREAKING-CHANGE"
+}
@@ -0,0 +1,118 @@
+package conventional
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  string
+		expected Commit
+	}{
+		{
+			name:     "type only",
+			message:  "fix: handle empty config files",
+			expected: Commit{Type: "fix", Description: "handle empty config files"},
+		},
+		{
+			name:     "scope and uppercase type",
+			message:  "Feat(api): add pagination\n",
+			expected: Commit{Type: "feat", Scope: "api", Description: "add pagination"},
+		},
+		{
+			name:     "breaking marker",
+			message:  "refactor(core)!: drop the v1 config format",
+			expected: Commit{Type: "refactor", Scope: "core", Breaking: true, Description: "drop the v1 config format"},
+		},
+		{
+			name:    "body and footers",
+			message: "fix: retry failed uploads\n\nUploads now retry three times.\nThe delay doubles each time.\n\nReviewed-by: Alice\nRefs #123\n",
+			expected: Commit{
+				Type: "fix", Description: "retry failed uploads",
+				Body:    "Uploads now retry three times.\nThe delay doubles each time.",
+				Footers: []Footer{{Token: "Reviewed-by", Value: "Alice"}, {Token: "Refs", Value: "123"}},
+			},
+		},
+		{
+			name:    "multi-paragraph breaking change footer",
+			message: "feat(cli): rename --out to --output\r\n\r\nBREAKING CHANGE: --out is gone.\r\n\r\nScripts must pass --output instead.\r\nAcked-by: Bob\r\n",
+			expected: Commit{
+				Type: "feat", Scope: "cli", Breaking: true, Description: "rename --out to --output",
+				Footers: []Footer{
+					{Token: "BREAKING CHANGE", Value: "--out is gone.\n\nScripts must pass --output instead."},
+					{Token: "Acked-by", Value: "Bob"},
+				},
+			},
+		},
+		{
+			name:    "hyphenated breaking token",
+			message: "chore: bump node\n\nBREAKING-CHANGE: node 18 is required",
+			expected: Commit{
+				Type: "chore", Breaking: true, Description: "bump node",
+				Footers: []Footer{{Token: "BREAKING-CHANGE", Value: "node 18 is required"}},
+			},
+		},
+		{
+			name:    "footer-like line inside the body",
+			message: "docs: explain setup\n\nSee the notes below\nNote: this line is body text\n\nCloses: #9",
+			expected: Commit{
+				Type: "docs", Description: "explain setup",
+				Body:    "See the notes below\nNote: this line is body text",
+				Footers: []Footer{{Token: "Closes", Value: "#9"}},
+			},
+		},
+		{
+			name:     "lowercase breaking change is not breaking",
+			message:  "fix: typo\n\nbreaking change: none",
+			expected: Commit{Type: "fix", Description: "typo", Body: "breaking change: none"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commit, err := Parse(tt.message)
+			if err != nil {
+				t.Fatalf("Parse(%q) failed: %v", tt.message, err)
+			}
+			if !reflect.DeepEqual(commit, tt.expected) {
+				t.Errorf("Parse(%q)\n got: %+v\nwant: %+v", tt.message, commit, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseNotConventional(t *testing.T) {
+	for _, message := range []string{
+		"Update README",
+		"Merge pull request #12 from alice/feature",
+		"fix:missing space",
+		"fix : space before colon",
+		"feat(api: unclosed scope",
+		"feat: ",
+		"",
+	} {
+		if _, err := Parse(message); !errors.Is(err, ErrNotConventional) {
+			t.Errorf("Parse(%q) = %v, expected ErrNotConventional", message, err)
+		}
+	}
+}
+
+func TestBreakingNote(t *testing.T) {
+	commit, _ := Parse("feat!: remove the legacy API")
+	if note := commit.BreakingNote(); note != "remove the legacy API" {
+		t.Errorf("Expected the description as the note, but got %q", note)
+	}
+
+	commit, _ = Parse("feat!: remove the legacy API\n\nBREAKING CHANGE: use /v2 instead")
+	if note := commit.BreakingNote(); note != "use /v2 instead" {
+		t.Errorf("Expected the footer as the note, but got %q", note)
+	}
+
+	commit, _ = Parse("fix: typo")
+	if note := commit.BreakingNote(); note != "" {
+		t.Errorf("Expected no note, but got %q", note)
+	}
+}
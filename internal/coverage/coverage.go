@@ -2,17 +2,29 @@ package coverage
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io/fs"
+	"math"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
-	"codecompass/internal/types"
+	"github.com/xeon-zolt/codecompass/internal/cerrors"
+	"github.com/xeon-zolt/codecompass/internal/repopath"
+	"github.com/xeon-zolt/codecompass/internal/types"
 )
 
+// ErrNotFound is returned when no coverage file is given and none is found in
+// the common locations.
+var ErrNotFound = errors.New("no coverage file found in common locations")
+
 // DetectCoverageFile attempts to find coverage files in common locations
-func DetectCoverageFile() (string, error) {
+// under dir
+func DetectCoverageFile(dir string) (string, error) {
 	commonPaths := []string{
 		"coverage/lcov.info",
 		"coverage/coverage.info",
@@ -25,23 +37,36 @@ func DetectCoverageFile() (string, error) {
 	}
 
 	for _, path := range commonPaths {
+		path = filepath.Join(dir, path)
 		if _, err := os.Stat(path); err == nil {
 			absPath, _ := filepath.Abs(path)
 			return absPath, nil
 		}
 	}
 
-	return "", fmt.Errorf("no coverage file found in common locations")
+	return "", ErrNotFound
 }
 
-// ParseCoverageFile parses different types of coverage files
-func ParseCoverageFile(filePath string) (*types.CoverageData, error) {
+// ResolveCoverageFile returns the path of the coverage file ParseCoverageFile
+// reads for filePath: filePath resolved against dir, or the file detected
+// under dir when filePath is empty.
+func ResolveCoverageFile(dir, filePath string) (string, error) {
 	if filePath == "" {
-		detectedPath, err := DetectCoverageFile()
-		if err != nil {
-			return nil, err
-		}
-		filePath = detectedPath
+		return DetectCoverageFile(dir)
+	}
+	if !filepath.IsAbs(filePath) {
+		filePath = filepath.Join(dir, filePath)
+	}
+	return filePath, nil
+}
+
+// ParseCoverageFile parses different types of coverage files. Relative paths
+// are resolved against dir. Files that cannot be parsed return
+// cerrors.ErrCoverageFormat.
+func ParseCoverageFile(dir, filePath string) (*types.CoverageData, error) {
+	filePath, err := ResolveCoverageFile(dir, filePath)
+	if err != nil {
+		return nil, err
 	}
 
 	// Determine file type by extension
@@ -119,14 +144,17 @@ func parseLcovFile(filePath string) (*types.CoverageData, error) {
 		}
 	}
 
-	return coverage, scanner.Err()
+	if err := scanner.Err(); err != nil {
+		return nil, &cerrors.ErrCoverageFormat{Path: filePath, Err: err}
+	}
+	return coverage, nil
 }
 
 // parseJsonCoverageFile parses JSON coverage files (NYC/Istanbul format)
 func parseJsonCoverageFile(filePath string) (*types.CoverageData, error) {
 	// This would implement JSON parsing for NYC/Istanbul coverage
 	// For now, return an error to indicate it's not implemented
-	return nil, fmt.Errorf("JSON coverage file parsing not yet implemented")
+	return nil, &cerrors.ErrCoverageFormat{Path: filePath, Err: fmt.Errorf("JSON coverage file parsing not yet implemented")}
 }
 
 // parseAutoDetect attempts to auto-detect file format
@@ -152,26 +180,32 @@ func parseAutoDetect(filePath string) (*types.CoverageData, error) {
 		}
 	}
 
-	return nil, fmt.Errorf("unable to detect coverage file format")
+	return nil, &cerrors.ErrCoverageFormat{Path: filePath, Err: fmt.Errorf("unknown format, expected LCOV")}
 }
 
-// GetCoverageStats calculates coverage statistics
-func GetCoverageStats(coverage *types.CoverageData, trackedFiles map[string]bool) []types.CoverageEntry {
+// GetCoverageStats calculates coverage statistics for the repository in dir.
+// A file the report names under several spellings, such as through a
+// symlink, counts once, under the first spelling in sorted order.
+func GetCoverageStats(dir string, coverage *types.CoverageData, trackedFiles map[string]bool) []types.CoverageEntry {
 	var entries []types.CoverageEntry
 
-	for filePath, fileCoverage := range coverage.Files {
-		// Convert absolute path to relative if needed
-		relPath := filePath
-		if filepath.IsAbs(filePath) {
-			if rel, err := filepath.Rel(".", filePath); err == nil {
-				relPath = rel
-			}
-		}
+	filePaths := make([]string, 0, len(coverage.Files))
+	for filePath := range coverage.Files {
+		filePaths = append(filePaths, filePath)
+	}
+	sort.Strings(filePaths)
+
+	resolver := repopath.New(dir, trackedFiles)
+	seen := make(map[string]bool)
+	for _, filePath := range filePaths {
+		fileCoverage := coverage.Files[filePath]
+		relPath := resolver.Resolve(filePath)
 
 		// Only include tracked files
-		if !trackedFiles[relPath] && !trackedFiles[filePath] {
+		if !trackedFiles[relPath] || seen[relPath] {
 			continue
 		}
+		seen[relPath] = true
 
 		var coveragePercent float64
 		if fileCoverage.LinesTotal > 0 {
@@ -192,3 +226,75 @@ func GetCoverageStats(coverage *types.CoverageData, trackedFiles map[string]bool
 
 	return entries
 }
+
+// staleAfter is how much older than the code a report must be to count as
+// stale, so a report from tests run just before the last commit is not.
+const staleAfter = 24 * time.Hour
+
+// Freshness compares the coverage report at reportPath, parsed into
+// coverage, with lastCommit, the commit date of HEAD, and with the covered
+// files of the repository in dir.
+func Freshness(dir, reportPath string, coverage *types.CoverageData, lastCommit time.Time) (*types.CoverageFreshness, error) {
+	info, err := os.Stat(reportPath)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		root = dir
+	}
+	resolver := repopath.New(root, nil)
+	freshness := &types.CoverageFreshness{
+		Report:     filepath.FromSlash(resolver.Resolve(reportPath)),
+		ModifiedAt: info.ModTime(),
+		LastCommit: lastCommit,
+	}
+	if !filepath.IsLocal(freshness.Report) {
+		freshness.Report = reportPath
+	}
+
+	for filePath := range coverage.Files {
+		// Paths outside the repository, such as those of another checkout,
+		// cannot be told missing
+		relPath := filepath.FromSlash(resolver.Resolve(filePath))
+		if !filepath.IsLocal(relPath) {
+			continue
+		}
+		source, err := os.Stat(filepath.Join(root, relPath))
+		if errors.Is(err, fs.ErrNotExist) {
+			freshness.MissingFiles++
+			continue
+		}
+		if err == nil && source.ModTime().After(freshness.NewestSource) {
+			freshness.NewestSource = source.ModTime()
+		}
+	}
+
+	latest := lastCommit
+	if freshness.NewestSource.After(latest) {
+		latest = freshness.NewestSource
+	}
+	if behind := latest.Sub(freshness.ModifiedAt); behind >= staleAfter {
+		freshness.StaleDays = behind.Hours() / 24
+	}
+	return freshness, nil
+}
+
+// StaleMessage says how much older than the code the report of freshness
+// is, such as "coverage report is 23 days older than the latest commit", or
+// returns "" when it is not stale.
+func StaleMessage(freshness *types.CoverageFreshness) string {
+	if freshness == nil || freshness.StaleDays == 0 {
+		return ""
+	}
+	than := "the latest commit"
+	if freshness.NewestSource.After(freshness.LastCommit) {
+		than = "the newest covered file"
+	}
+	days := int(math.Round(freshness.StaleDays))
+	if days == 1 {
+		return fmt.Sprintf("coverage report is 1 day older than %s", than)
+	}
+	return fmt.Sprintf("coverage report is %d days older than %s", days, than)
+}
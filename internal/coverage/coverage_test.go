@@ -0,0 +1,146 @@
+package coverage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/xeon-zolt/codecompass/internal/cerrors"
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+func TestParseCoverageFile(t *testing.T) {
+	dir := t.TempDir()
+	lcov := "TN:\nSF:src/a.js\nLF:10\nLH:8\nend_of_record\n"
+	if err := os.WriteFile(filepath.Join(dir, "lcov.info"), []byte(lcov), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ParseCoverageFile(dir, "")
+	if err != nil {
+		t.Fatalf("ParseCoverageFile failed: %v", err)
+	}
+	if file := data.Files["src/a.js"]; file.LinesTotal != 10 || file.LinesCovered != 8 {
+		t.Errorf("Unexpected coverage for src/a.js: %+v", file)
+	}
+}
+
+func TestParseCoverageFileErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, err := ParseCoverageFile(dir, ""); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound without a coverage file, but got %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "coverage.txt"), []byte("mode: set\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := ParseCoverageFile(dir, "coverage.txt")
+	var formatErr *cerrors.ErrCoverageFormat
+	if !errors.As(err, &formatErr) {
+		t.Fatalf("Expected ErrCoverageFormat, but got %v", err)
+	}
+	if formatErr.Path != filepath.Join(dir, "coverage.txt") {
+		t.Errorf("Expected the error to name coverage.txt, but got %s", formatErr.Path)
+	}
+}
+
+func TestFreshness(t *testing.T) {
+	dir := t.TempDir()
+	lcov := "SF:src/a.js\nLF:10\nLH:8\nend_of_record\nSF:src/gone.js\nLF:4\nLH:4\nend_of_record\n"
+	for name, content := range map[string]string{"lcov.info": lcov, "src/a.js": "a()\n"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	lastCommit := time.Now().Add(-time.Hour).Truncate(time.Second)
+	setModTime := func(name string, modTime time.Time) {
+		t.Helper()
+		if err := os.Chtimes(filepath.Join(dir, name), modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	freshness := func() *types.CoverageFreshness {
+		t.Helper()
+		data, err := ParseCoverageFile(dir, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		path, err := ResolveCoverageFile(dir, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		f, err := Freshness(dir, path, data, lastCommit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return f
+	}
+
+	// A report written after the last commit and the sources is fresh
+	setModTime("src/a.js", lastCommit.Add(-48*time.Hour))
+	setModTime("lcov.info", lastCommit.Add(time.Minute))
+	f := freshness()
+	if f.StaleDays != 0 || StaleMessage(f) != "" {
+		t.Errorf("Expected a fresh report, but got %+v (%q)", f, StaleMessage(f))
+	}
+	if f.Report != "lcov.info" || f.MissingFiles != 1 {
+		t.Errorf("Expected lcov.info with 1 missing file, but got %+v", f)
+	}
+
+	// Tests run shortly before the last commit are not stale
+	setModTime("lcov.info", lastCommit.Add(-2*time.Hour))
+	if f := freshness(); f.StaleDays != 0 {
+		t.Errorf("Expected a report 2 hours old not to be stale, but got %+v", f)
+	}
+
+	setModTime("lcov.info", lastCommit.AddDate(0, 0, -23))
+	f = freshness()
+	if got, expected := StaleMessage(f), "coverage report is 23 days older than the latest commit"; got != expected {
+		t.Errorf("StaleMessage = %q, expected %q", got, expected)
+	}
+
+	// A source edited after the last commit counts too
+	setModTime("src/a.js", lastCommit.AddDate(0, 0, 2))
+	f = freshness()
+	if got, expected := StaleMessage(f), "coverage report is 25 days older than the newest covered file"; got != expected {
+		t.Errorf("StaleMessage = %q, expected %q", got, expected)
+	}
+}
+
+func TestGetCoverageStatsResolvesSymlinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks need privileges on Windows")
+	}
+
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "src"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "src", "a.js"), []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("src", filepath.Join(dir, "lib")); err != nil {
+		t.Fatal(err)
+	}
+
+	// The report names src/a.js through the symlink, and absolutely
+	data := &types.CoverageData{Files: map[string]types.FileCoverage{
+		"lib/a.js":                           {LinesTotal: 10, LinesCovered: 5},
+		filepath.Join(dir, "src", "a.js"):    {LinesTotal: 10, LinesCovered: 5},
+		filepath.Join(dir, "lib", "gone.js"): {LinesTotal: 4, LinesCovered: 4},
+	}}
+	entries := GetCoverageStats(dir, data, map[string]bool{"src/a.js": true})
+	if len(entries) != 1 || entries[0].Path != "src/a.js" || entries[0].CoveragePercent != 50 {
+		t.Errorf("Expected one entry for src/a.js, but got %+v", entries)
+	}
+}
@@ -0,0 +1,129 @@
+// Package customrules is the lint source for the custom-rule settings:
+// grep-style checks that match a regular expression against every line of
+// the analyzed files, so a team can flag its own patterns without writing a
+// linter. Their issues are blamed and counted like any other.
+package customrules
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/xeon-zolt/codecompass/internal/config"
+	"github.com/xeon-zolt/codecompass/internal/types"
+	"github.com/xeon-zolt/codecompass/internal/utils"
+)
+
+// SourceName is the name of the custom rules source in output and timings.
+const SourceName = "custom"
+
+// severity is the severity of custom rule issues, a warning;
+// rule-severity-overrides can count them as errors.
+const severity = 1
+
+// Rule is a compiled custom rule.
+type Rule struct {
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+// Compile compiles the patterns of rules.
+func Compile(rules []config.CustomRule) ([]Rule, error) {
+	compiled := make([]Rule, 0, len(rules))
+	for _, rule := range rules {
+		pattern, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("custom rule %s: %w", rule.Name, err)
+		}
+		compiled = append(compiled, Rule{Name: rule.Name, Pattern: pattern})
+	}
+	return compiled, nil
+}
+
+// Scan returns an issue for every line of file, relative to dir, matching
+// one of rules, in line order; a line matching several rules is an issue of
+// each. Binary files, which have a NUL byte, have no issues. Lines longer
+// than maxLineKB kilobytes fail the scan.
+func Scan(dir, file string, rules []Rule, maxLineKB int) ([]types.Issue, error) {
+	f, err := utils.OpenRegular(filepath.Join(dir, file))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var issues []types.Issue
+	scanner := utils.NewLineScanner(f, maxLineKB)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := scanner.Bytes()
+		if bytes.IndexByte(line, 0) >= 0 {
+			return nil, nil
+		}
+		for _, rule := range rules {
+			if rule.Pattern.Match(line) {
+				issues = append(issues, types.Issue{
+					FilePath: filepath.ToSlash(file),
+					Line:     lineNum,
+					RuleID:   rule.Name,
+					Message:  "Line matches " + rule.Pattern.String(),
+					Severity: severity,
+				})
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	return issues, nil
+}
+
+// Source is the lint source of the custom rules of the config.
+type Source struct{}
+
+func (Source) Name() string {
+	return SourceName
+}
+
+// Detect always succeeds; Run does nothing without custom rules.
+func (Source) Detect(ctx context.Context, dir string) bool {
+	return true
+}
+
+// Run scans files for the custom rules of cfg, leaving out ignored rules.
+// Files that cannot be read are skipped rather than failing the source.
+func (Source) Run(ctx context.Context, dir string, files map[string]bool, cfg *config.Config) ([]types.Issue, error) {
+	var configured []config.CustomRule
+	for _, rule := range cfg.CustomRules {
+		if !cfg.ShouldIgnoreRule(rule.Name) {
+			configured = append(configured, rule)
+		}
+	}
+	if len(configured) == 0 {
+		return nil, nil
+	}
+	rules, err := Compile(configured)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(files))
+	for file := range files {
+		paths = append(paths, file)
+	}
+	sort.Strings(paths)
+
+	var issues []types.Issue
+	for _, file := range paths {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		fileIssues, err := Scan(dir, file, rules, cfg.MaxLineSize)
+		if err != nil {
+			continue
+		}
+		issues = append(issues, fileIssues...)
+	}
+	return issues, nil
+}
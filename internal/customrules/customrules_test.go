@@ -0,0 +1,97 @@
+package customrules
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/xeon-zolt/codecompass/internal/config"
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+// writeFiles writes files, by path relative to dir, to dir.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestScan(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"src/app.js": "const x = 1;\nconsole.log(x); // FIXME\n\nconsole.debug(x);\n",
+	})
+
+	rules, err := Compile([]config.CustomRule{
+		{Name: "no-print", Pattern: `console\.(log|debug)`},
+		{Name: "no-fixme", Pattern: `\bFIXME\b`},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issues, err := Scan(dir, filepath.Join("src", "app.js"), rules, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []types.Issue{
+		{FilePath: "src/app.js", Line: 2, RuleID: "no-print", Message: `Line matches console\.(log|debug)`, Severity: 1},
+		{FilePath: "src/app.js", Line: 2, RuleID: "no-fixme", Message: `Line matches \bFIXME\b`, Severity: 1},
+		{FilePath: "src/app.js", Line: 4, RuleID: "no-print", Message: `Line matches console\.(log|debug)`, Severity: 1},
+	}
+	if !reflect.DeepEqual(issues, expected) {
+		t.Errorf("Expected %+v, but got %+v", expected, issues)
+	}
+}
+
+func TestCompileInvalidPattern(t *testing.T) {
+	if _, err := Compile([]config.CustomRule{{Name: "broken", Pattern: "(unclosed"}}); err == nil {
+		t.Error("Expected an error for an invalid pattern")
+	}
+}
+
+func TestSourceRun(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"a.py":       "print('a')\n",
+		"b.py":       "def b():\n    print('b')  # TODO\n",
+		"image.png":  "print\x00\x01",
+		"skipped.py": "print('not analyzed')\n",
+	})
+	files := map[string]bool{"a.py": true, "b.py": true, "image.png": true, "missing.py": true}
+
+	cfg := config.NewConfig()
+	if issues, err := (Source{}).Run(context.Background(), dir, files, cfg); err != nil || issues != nil {
+		t.Errorf("Expected no issues without custom rules, but got %+v and %v", issues, err)
+	}
+
+	cfg.CustomRules = []config.CustomRule{{Name: "no-print", Pattern: `\bprint\(`}, {Name: "no-todo", Pattern: "TODO"}}
+	cfg.IgnoredRules = []string{"no-todo"}
+	issues, err := (Source{}).Run(context.Background(), dir, files, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []types.Issue{
+		{FilePath: "a.py", Line: 1, RuleID: "no-print", Message: `Line matches \bprint\(`, Severity: 1},
+		{FilePath: "b.py", Line: 2, RuleID: "no-print", Message: `Line matches \bprint\(`, Severity: 1},
+	}
+	if !reflect.DeepEqual(issues, expected) {
+		t.Errorf("Expected %+v, but got %+v", expected, issues)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (Source{}).Run(ctx, dir, files, cfg); err == nil {
+		t.Error("Expected a cancelled context to stop the scan")
+	}
+}
@@ -0,0 +1,293 @@
+// Package deps reads the dependencies a repository declares from its npm
+// lockfiles and pip requirements files, offline, to tell how many there are
+// and how many are pinned to one version.
+package deps
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Ecosystems of the files read, as vulnerabilities name them.
+const (
+	EcosystemNPM  = "npm"
+	EcosystemPyPI = "pypi"
+)
+
+// Pinning is how a dependency constrains its version.
+type Pinning int
+
+const (
+	// Ranged dependencies accept a range of versions, or any version
+	Ranged Pinning = iota
+	// Pinned dependencies accept a single version
+	Pinned
+	// Unversioned dependencies come from a git repository, a URL or a
+	// path, which a version range does not apply to
+	Unversioned
+)
+
+// Manifest is what one lockfile or requirements file tells about the
+// dependencies of a project.
+type Manifest struct {
+	Ecosystem string
+
+	// Direct maps the dependencies the project declares, development ones
+	// included, to their version specifier, such as ^4.17.1 or ==2.31.0.
+	// It is nil when the file does not tell them.
+	Direct map[string]string
+
+	// Locked counts the packages locked, transitive ones included. It is 0
+	// for requirements files, which lock nothing.
+	Locked int
+}
+
+// IsManifest reports whether path is a file Read understands:
+// package-lock.json, yarn.lock or a requirements file such as
+// requirements.txt or requirements-dev.txt. Files of installed packages,
+// under node_modules, are not the project's.
+func IsManifest(file string) bool {
+	if strings.HasPrefix(file, "node_modules/") || strings.Contains(file, "/node_modules/") {
+		return false
+	}
+	name := path.Base(file)
+	if name == "package-lock.json" || name == "yarn.lock" {
+		return true
+	}
+	return strings.HasPrefix(name, "requirements") && strings.HasSuffix(name, ".txt")
+}
+
+// Read reads the manifest file in dir, a path IsManifest accepts. The
+// dependencies of a yarn.lock, and of a package-lock.json from before npm 7,
+// are read from the package.json beside it, when there is one.
+func Read(dir, file string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(file)))
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest *Manifest
+	switch path.Base(file) {
+	case "package-lock.json":
+		manifest, err = ParsePackageLock(data)
+	case "yarn.lock":
+		manifest, err = ParseYarnLock(data)
+	default:
+		return ParseRequirements(data)
+	}
+	if err != nil || manifest.Direct != nil {
+		return manifest, err
+	}
+
+	packageJSON, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(path.Join(path.Dir(file), "package.json"))))
+	if errors.Is(err, fs.ErrNotExist) {
+		return manifest, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if manifest.Direct, err = ParsePackageJSON(packageJSON); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// packageDependencies are the dependency fields of a package.json, and of
+// the root package of a package-lock.json.
+type packageDependencies struct {
+	Dependencies         map[string]string `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+}
+
+// merge returns the dependencies, development and optional ones included.
+// It is never nil.
+func (p packageDependencies) merge() map[string]string {
+	direct := make(map[string]string)
+	for _, dependencies := range []map[string]string{p.Dependencies, p.DevDependencies, p.OptionalDependencies} {
+		for name, spec := range dependencies {
+			direct[name] = spec
+		}
+	}
+	return direct
+}
+
+// ParsePackageJSON returns the dependencies a package.json declares.
+func ParsePackageJSON(data []byte) (map[string]string, error) {
+	var manifest packageDependencies
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse package.json: %w", err)
+	}
+	return manifest.merge(), nil
+}
+
+// lockedDependency is a package of a package-lock.json from before npm 7,
+// with the packages nested under it.
+type lockedDependency struct {
+	Dependencies map[string]lockedDependency `json:"dependencies"`
+}
+
+func countLocked(dependencies map[string]lockedDependency) int {
+	count := len(dependencies)
+	for _, dependency := range dependencies {
+		count += countLocked(dependency.Dependencies)
+	}
+	return count
+}
+
+// ParsePackageLock parses a package-lock.json. Lockfiles of npm 7 and later
+// list the root package's dependencies; older ones leave Direct nil.
+func ParsePackageLock(data []byte) (*Manifest, error) {
+	var lock struct {
+		Packages     map[string]json.RawMessage  `json:"packages"`
+		Dependencies map[string]lockedDependency `json:"dependencies"`
+	}
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("failed to parse package-lock.json: %w", err)
+	}
+
+	manifest := &Manifest{Ecosystem: EcosystemNPM}
+	if lock.Packages == nil {
+		manifest.Locked = countLocked(lock.Dependencies)
+		return manifest, nil
+	}
+
+	for key, raw := range lock.Packages {
+		if key == "" {
+			var root packageDependencies
+			if err := json.Unmarshal(raw, &root); err != nil {
+				return nil, fmt.Errorf("failed to parse package-lock.json: %w", err)
+			}
+			manifest.Direct = root.merge()
+			continue
+		}
+		// Workspaces are listed by their path as well as linked
+		if strings.HasPrefix(key, "node_modules/") || strings.Contains(key, "/node_modules/") {
+			manifest.Locked++
+		}
+	}
+	return manifest, nil
+}
+
+// ParseYarnLock parses a yarn.lock of Yarn 1 or later, which does not tell
+// the direct dependencies.
+func ParseYarnLock(data []byte) (*Manifest, error) {
+	manifest := &Manifest{Ecosystem: EcosystemNPM}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		// Entries start unindented, such as `lodash@^4.17.11:` or
+		// `"lodash@npm:^4.17.11", "lodash@npm:^4.17.21":`
+		if line == "" || line[0] == ' ' || line[0] == '#' || !strings.HasSuffix(line, ":") {
+			continue
+		}
+		if strings.HasPrefix(line, "__metadata") {
+			continue
+		}
+		manifest.Locked++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to parse yarn.lock: %w", err)
+	}
+	return manifest, nil
+}
+
+// requirementLine matches a requirement: the project name, its extras, and
+// the rest, such as a version specifier and environment markers.
+var requirementLine = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)\s*(?:\[[^\]]*\])?\s*(.*)$`)
+
+// projectSeparators are the runs of characters that are the same in
+// project names, as PEP 503 normalizes them.
+var projectSeparators = regexp.MustCompile(`[-_.]+`)
+
+// ParseRequirements parses a pip requirements file. Options, such as -r,
+// -e or --hash, are skipped, so requirements of included files count
+// towards those files.
+func ParseRequirements(data []byte) (*Manifest, error) {
+	manifest := &Manifest{Ecosystem: EcosystemPyPI, Direct: make(map[string]string)}
+	// Lines ending with a backslash continue on the next one
+	text := strings.ReplaceAll(strings.ReplaceAll(string(data), "\r\n", "\n"), "\\\n", " ")
+	for _, line := range strings.Split(text, "\n") {
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "-") {
+			continue
+		}
+		match := requirementLine.FindStringSubmatch(line)
+		if match == nil {
+			// Such as a URL or a path to an archive
+			continue
+		}
+		spec, _, _ := strings.Cut(match[2], ";")
+		// Per-requirement options, such as --hash, follow the specifier
+		if i := strings.Index(spec, " -"); i >= 0 {
+			spec = spec[:i]
+		}
+		name := strings.ToLower(projectSeparators.ReplaceAllString(match[1], "-"))
+		manifest.Direct[name] = strings.TrimSpace(spec)
+	}
+	return manifest, nil
+}
+
+var (
+	// npmPinned matches an exact npm version, such as 4.17.1 or
+	// =1.0.0-beta.2
+	npmPinned = regexp.MustCompile(`^=?v?[0-9]+\.[0-9]+\.[0-9]+(-[0-9A-Za-z.-]+)?(\+[0-9A-Za-z.-]+)?$`)
+
+	// pipPinned matches an exact pip version specifier, such as ==2.31.0
+	// or ===1.0, but not a prefix match such as ==2.*
+	pipPinned = regexp.MustCompile(`^===?\s*[^\s,*<>=!~]+$`)
+)
+
+// npmUnversioned are the prefixes of npm specifiers that name a source
+// rather than a version.
+var npmUnversioned = []string{"git:", "git+", "github:", "gitlab:", "bitbucket:", "gist:", "file:", "link:", "portal:", "workspace:", "http:", "https:"}
+
+// Classify returns how spec, the version specifier of a dependency of the
+// ecosystem, constrains its version. A missing specifier is Ranged, as any
+// version will do.
+func Classify(ecosystem, spec string) Pinning {
+	spec = strings.TrimSpace(spec)
+	if ecosystem == EcosystemPyPI {
+		switch {
+		case strings.HasPrefix(spec, "@"):
+			return Unversioned
+		case pipPinned.MatchString(spec):
+			return Pinned
+		}
+		return Ranged
+	}
+
+	// Aliases, such as npm:lodash@^4.17.21, constrain the aliased package
+	if alias, ok := strings.CutPrefix(spec, "npm:"); ok {
+		if i := strings.LastIndex(alias, "@"); i > 0 {
+			return Classify(ecosystem, alias[i+1:])
+		}
+		return Ranged
+	}
+	for _, prefix := range npmUnversioned {
+		if strings.HasPrefix(spec, prefix) {
+			return Unversioned
+		}
+	}
+	switch {
+	case npmPinned.MatchString(spec):
+		return Pinned
+	case strings.Contains(spec, "/") && !strings.ContainsAny(spec, " <>=^~"):
+		// GitHub shorthands, such as expressjs/express#4.17.1
+		return Unversioned
+	}
+	return Ranged
+}
@@ -0,0 +1,169 @@
+package deps
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIsManifest(t *testing.T) {
+	tests := []struct {
+		file string
+		want bool
+	}{
+		{"package-lock.json", true},
+		{"web/yarn.lock", true},
+		{"requirements.txt", true},
+		{"requirements-dev.txt", true},
+		{"requirements/base.txt", false},
+		{"package.json", false},
+		{"pnpm-lock.yaml", false},
+		{"node_modules/left-pad/package-lock.json", false},
+		{"web/node_modules/yarn.lock", false},
+	}
+	for _, tt := range tests {
+		if got := IsManifest(tt.file); got != tt.want {
+			t.Errorf("IsManifest(%q) = %v, want %v", tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestReadPackageLock(t *testing.T) {
+	manifest, err := Read("testdata", "npm/package-lock.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &Manifest{
+		Ecosystem: EcosystemNPM,
+		Direct: map[string]string{
+			"express":    "^4.17.1",
+			"lodash":     "4.17.21",
+			"ui":         "workspace:*",
+			"jest":       "~29.7.0",
+			"typescript": "5.4.5",
+		},
+		// The workspace is counted once, by its link
+		Locked: 6,
+	}
+	if !reflect.DeepEqual(manifest, want) {
+		t.Errorf("Read() = %+v, want %+v", manifest, want)
+	}
+}
+
+func TestParsePackageLockV1(t *testing.T) {
+	manifest, err := ParsePackageLock([]byte(`{
+		"lockfileVersion": 1,
+		"dependencies": {
+			"express": {"version": "4.17.1", "dependencies": {"qs": {"version": "6.7.0"}}},
+			"lodash": {"version": "4.17.21"}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manifest.Direct != nil || manifest.Locked != 3 {
+		t.Errorf("ParsePackageLock() = %+v, want no direct dependencies and 3 locked", manifest)
+	}
+
+	if _, err := ParsePackageLock([]byte("{")); err == nil {
+		t.Error("ParsePackageLock() of invalid JSON succeeded")
+	}
+}
+
+func TestReadYarnLock(t *testing.T) {
+	manifest, err := Read("testdata", "yarn/yarn.lock")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &Manifest{
+		Ecosystem: EcosystemNPM,
+		Direct: map[string]string{
+			"react":       "18.3.1",
+			"left-pad":    "github:stevemao/left-pad",
+			"@babel/core": "^7.12.3",
+		},
+		Locked: 3,
+	}
+	if !reflect.DeepEqual(manifest, want) {
+		t.Errorf("Read() = %+v, want %+v", manifest, want)
+	}
+}
+
+func TestParseYarnLockBerry(t *testing.T) {
+	manifest, err := ParseYarnLock([]byte(`__metadata:
+  version: 8
+
+"lodash@npm:^4.17.11, lodash@npm:^4.17.21":
+  version: 4.17.21
+
+"react@npm:18.3.1":
+  version: 18.3.1
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manifest.Direct != nil || manifest.Locked != 2 {
+		t.Errorf("ParseYarnLock() = %+v, want no direct dependencies and 2 locked", manifest)
+	}
+}
+
+func TestReadRequirements(t *testing.T) {
+	manifest, err := Read("testdata", "pip/requirements.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &Manifest{
+		Ecosystem: EcosystemPyPI,
+		Direct: map[string]string{
+			"django":      "==4.2.11",
+			"requests":    ">= 2.31, < 3",
+			"flask-login": "",
+			"numpy":       "==1.26.*",
+			"urllib3":     "==2.2.1",
+			"my-lib":      "@ git+https://github.com/acme/my-lib@v1.2.0",
+		},
+	}
+	if !reflect.DeepEqual(manifest, want) {
+		t.Errorf("Read() = %+v, want %+v", manifest, want)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		ecosystem string
+		spec      string
+		want      Pinning
+	}{
+		{EcosystemNPM, "4.17.21", Pinned},
+		{EcosystemNPM, "=1.0.0-beta.2", Pinned},
+		{EcosystemNPM, "v2.0.0", Pinned},
+		{EcosystemNPM, "^4.17.1", Ranged},
+		{EcosystemNPM, "~29.7.0", Ranged},
+		{EcosystemNPM, ">=1.0.0 <2.0.0", Ranged},
+		{EcosystemNPM, "1.x", Ranged},
+		{EcosystemNPM, "*", Ranged},
+		{EcosystemNPM, "latest", Ranged},
+		{EcosystemNPM, "", Ranged},
+		{EcosystemNPM, "npm:lodash@4.17.21", Pinned},
+		{EcosystemNPM, "npm:@scope/lodash@^4.17.21", Ranged},
+		{EcosystemNPM, "github:stevemao/left-pad", Unversioned},
+		{EcosystemNPM, "expressjs/express#4.17.1", Unversioned},
+		{EcosystemNPM, "git+https://github.com/acme/lib.git", Unversioned},
+		{EcosystemNPM, "file:../lib", Unversioned},
+		{EcosystemNPM, "workspace:*", Unversioned},
+		{EcosystemPyPI, "==4.2.11", Pinned},
+		{EcosystemPyPI, "=== 1.0", Pinned},
+		{EcosystemPyPI, "==1.26.*", Ranged},
+		{EcosystemPyPI, ">=2.31,<3", Ranged},
+		{EcosystemPyPI, "~=2.2", Ranged},
+		{EcosystemPyPI, "", Ranged},
+		{EcosystemPyPI, "@ git+https://github.com/acme/my-lib", Unversioned},
+	}
+	for _, tt := range tests {
+		if got := Classify(tt.ecosystem, tt.spec); got != tt.want {
+			t.Errorf("Classify(%q, %q) = %v, want %v", tt.ecosystem, tt.spec, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+// Package detect classifies repository files the way GitHub Linguist does,
+// as vendored third-party code or as generated code, so leaderboards that
+// read file contents can leave them out.
+package detect
+
+import (
+	"bufio"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/xeon-zolt/codecompass/internal/utils"
+)
+
+// Kind is what a file was classified as. The empty Kind is first-party
+// source.
+type Kind string
+
+const (
+	Vendored  Kind = "vendored"
+	Generated Kind = "generated"
+)
+
+// Attributes are the .gitattributes that override detection: set or true
+// marks a file, unset or false clears it whatever its path or contents.
+var Attributes = []string{"linguist-vendored", "linguist-generated"}
+
+// vendoredPaths follow Linguist's vendor.yml.
+var vendoredPaths = regexp.MustCompile(strings.Join([]string{
+	`(^|/)vendors?/`,
+	`(^|/)third[-_]?party/`,
+	`(^|/)3rd[-_]?party/`,
+	`(^|/)extern(al)?/`,
+	`(^|/)node_modules/`,
+	`(^|/)bower_components/`,
+	`(^|/)Godeps/_workspace/`,
+	`(^|/)\.yarn/(releases|plugins|sdks|versions)/`,
+	`(\.|-)min\.(js|css)$`,
+	`(^|/)jquery([^.]*)\.js$`,
+	`(^|/)jquery-\d\.\d+(\.\d+)?\.js$`,
+	`(^|/)bootstrap([^/.]*)(\..*)?\.(js|css)$`,
+	`(^|/)d3(\.v\d+)?([^.]*)\.js$`,
+}, "|"))
+
+// generatedPaths follow Linguist's generated.rb.
+var generatedPaths = regexp.MustCompile(strings.Join([]string{
+	`\.pb\.go$`,
+	`_pb2(_grpc)?\.py$`,
+	`\.pb\.(cc|h)$`,
+	`\.(js|css)\.map$`,
+	`(^|/)package-lock\.json$`,
+	`(^|/)yarn\.lock$`,
+	`(^|/)pnpm-lock\.yaml$`,
+	`(^|/)go\.sum$`,
+	`(^|/)Cargo\.lock$`,
+	`(^|/)poetry\.lock$`,
+}, "|"))
+
+// generatedHeader matches the markers code generators put in the first lines
+// of their output.
+var generatedHeader = regexp.MustCompile(`(?i)code generated .* do not edit|@generated\b|auto-?generated|automatically generated|generated by .* do not edit`)
+
+// copyrightLine matches a dated copyright notice, capturing the holder after
+// the years. License texts mention "the copyright owner" without a date.
+var copyrightLine = regexp.MustCompile(`(?i)copyright\s+(?:\(c\)\s*|©\s*)?[0-9]{4}(?:\s*[-,]\s*(?:[0-9]{4}|present))*\s*,?\s*(.+)`)
+
+// licenseMarker matches the license names that show a header belongs to a
+// licensed project.
+var licenseMarker = regexp.MustCompile(`(?i)@license|licensed under|permission is hereby granted|mit license|apache license|bsd license|gnu (lesser )?general public license|mozilla public license`)
+
+// headerLines is how many lines of a file are searched for license headers,
+// and generatorLines for generator markers, which come first.
+const (
+	headerLines    = 30
+	generatorLines = 5
+)
+
+// minifiedLineLength is the average line length above which JavaScript and
+// CSS are taken to be minified, as Linguist does.
+const minifiedLineLength = 110
+
+// Detector classifies the files of one repository.
+type Detector struct {
+	dir        string
+	attributes map[string]map[string]string
+
+	// holder is the copyright holder in the repository's own license, so
+	// files carrying another project's license header can be told apart
+	holder string
+}
+
+// New returns a Detector for the repository in dir. attributes holds the
+// values of Attributes for each file, as returned by git.GetAttributes.
+func New(dir string, attributes map[string]map[string]string) *Detector {
+	d := &Detector{dir: dir, attributes: attributes}
+	for _, name := range []string{"LICENSE", "LICENSE.md", "LICENSE.txt", "COPYING"} {
+		if holder := readHolder(filepath.Join(dir, name)); holder != "" {
+			d.holder = holder
+			break
+		}
+	}
+	return d
+}
+
+// Classify returns what file, relative to the repository root, is. The
+// linguist attributes win; otherwise the path is matched against the
+// Linguist conventions before the start of the file is read.
+func (d *Detector) Classify(file string) Kind {
+	file = filepath.ToSlash(file)
+	vendored, vendoredSet := attribute(d.attributes[file]["linguist-vendored"])
+	generated, generatedSet := attribute(d.attributes[file]["linguist-generated"])
+	switch {
+	case vendoredSet && vendored:
+		return Vendored
+	case generatedSet && generated:
+		return Generated
+	case vendoredSet && generatedSet:
+		return ""
+	}
+
+	if !vendoredSet && vendoredPaths.MatchString(file) {
+		return Vendored
+	}
+	if !generatedSet && generatedPaths.MatchString(file) {
+		return Generated
+	}
+	return d.classifyContents(file, !vendoredSet, !generatedSet)
+}
+
+// classifyContents reads the start of file for a generator marker, a
+// minified layout or another project's license header.
+func (d *Detector) classifyContents(file string, checkVendored, checkGenerated bool) Kind {
+	f, err := utils.OpenRegular(filepath.Join(d.dir, file))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	var header []string
+	reader := bufio.NewReader(io.LimitReader(f, 64*1024))
+	lines, length := 0, 0
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			lines++
+			length += len(line)
+			if len(header) < headerLines {
+				header = append(header, line)
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+
+	if checkGenerated {
+		for _, line := range header[:min(len(header), generatorLines)] {
+			if generatedHeader.MatchString(line) {
+				return Generated
+			}
+		}
+		ext := strings.ToLower(filepath.Ext(file))
+		if (ext == ".js" || ext == ".mjs" || ext == ".css") && lines > 0 && length/lines > minifiedLineLength {
+			return Generated
+		}
+	}
+
+	if checkVendored && foreignLicense(header, d.holder) {
+		return Vendored
+	}
+	return ""
+}
+
+// foreignLicense reports whether the header lines of a file carry the
+// license header of a project other than the one whose copyright holder is
+// holder. Without a known holder only explicit @license tags count, since
+// many projects put their own license at the top of every file.
+func foreignLicense(header []string, holder string) bool {
+	text := strings.Join(header, "\n")
+	if holder == "" {
+		return strings.Contains(text, "@license")
+	}
+	if !licenseMarker.MatchString(text) {
+		return false
+	}
+	for _, line := range header {
+		if match := copyrightLine.FindStringSubmatch(line); match != nil {
+			// The capture runs to the end of the line, so it may go on
+			// past the holder
+			fileHolder := normalizeHolder(match[1])
+			if fileHolder != "" && !strings.HasPrefix(fileHolder, holder) && !strings.HasPrefix(holder, fileHolder) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// readHolder returns the normalized copyright holder of the license file at
+// path, or an empty string if it has none.
+func readHolder(path string) string {
+	f, err := utils.OpenRegular(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(io.LimitReader(f, 64*1024))
+	for i := 0; i < headerLines && scanner.Scan(); i++ {
+		if match := copyrightLine.FindStringSubmatch(scanner.Text()); match != nil {
+			if holder := normalizeHolder(match[1]); holder != "" {
+				return holder
+			}
+		}
+	}
+	return ""
+}
+
+// normalizeHolder lowercases a copyright holder and drops the punctuation
+// and comment markers around it, so the same holder compares equal.
+func normalizeHolder(holder string) string {
+	holder = strings.ToLower(holder)
+	for _, suffix := range []string{"*/", "-->", "all rights reserved."} {
+		holder = strings.ReplaceAll(holder, suffix, "")
+	}
+	return strings.Trim(strings.Join(strings.Fields(holder), " "), " .,;:*#/")
+}
+
+// attribute reads a linguist attribute value, reporting whether it is set at
+// all.
+func attribute(value string) (on bool, set bool) {
+	switch value {
+	case "set", "true":
+		return true, true
+	case "unset", "false":
+		return false, true
+	}
+	return false, false
+}
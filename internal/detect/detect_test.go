@@ -0,0 +1,91 @@
+package detect
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"LICENSE":                   "MIT License\n\nCopyright (c) 2023 Acme Corp\n\nPermission is hereby granted...\n",
+		"src/app.js":                "// Copyright 2024 Acme Corp. MIT license.\nconsole.log('app');\n",
+		"src/lodash.js":             "/**\n * Copyright 2012-2016 The Dojo Foundation\n * Released under MIT license\n */\nvar _ = {};\n",
+		"src/api.pb.ts":             "// Code generated by protoc-gen-ts. DO NOT EDIT.\nexport {};\n",
+		"src/late-marker.go":        "package main\n\n\n\n\n\n// Code generated by hand. DO NOT EDIT.\n",
+		"src/bundle.js":             "var a=" + strings.Repeat("1+", 200) + "1;\n",
+		"src/readable.js":           "var a = 1;\nvar b = 2;\n",
+		"src/vendor/lib.js":         "var lib = {};\n",
+		"third_party/zlib/zlib.h":   "#define ZLIB\n",
+		"static/jquery-3.7.1.js":    "(function(){})();\n",
+		"static/app.min.css":        "body{}\n",
+		"gen/types.pb.go":           "package gen\n",
+		"package-lock.json":         "{}\n",
+		"vendor/ours.js":            "var ours = {};\n",
+		"docs/schema.md":            "# Schema\n",
+		"internal/external/keep.go": "package external\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	detector := New(dir, map[string]map[string]string{
+		"vendor/ours.js":            {"linguist-vendored": "unset"},
+		"docs/schema.md":            {"linguist-generated": "true"},
+		"internal/external/keep.go": {"linguist-vendored": "false"},
+	})
+
+	tests := map[string]Kind{
+		"src/app.js":                "",
+		"src/lodash.js":             Vendored,
+		"src/api.pb.ts":             Generated,
+		"src/late-marker.go":        "",
+		"src/bundle.js":             Generated,
+		"src/readable.js":           "",
+		"src/vendor/lib.js":         Vendored,
+		"third_party/zlib/zlib.h":   Vendored,
+		"static/jquery-3.7.1.js":    Vendored,
+		"static/app.min.css":        Vendored,
+		"gen/types.pb.go":           Generated,
+		"package-lock.json":         Generated,
+		"vendor/ours.js":            "",
+		"docs/schema.md":            Generated,
+		"internal/external/keep.go": "",
+		"missing.js":                "",
+	}
+
+	for file, expected := range tests {
+		if got := detector.Classify(file); got != expected {
+			t.Errorf("Classify(%q) = %q, expected %q", file, got, expected)
+		}
+	}
+}
+
+func TestForeignLicense(t *testing.T) {
+	tests := []struct {
+		header   string
+		holder   string
+		expected bool
+	}{
+		{"// Copyright 2024 Acme Corp\n// Licensed under the Apache License", "acme corp", false},
+		{"/* Copyright (c) 2010-2020, Other Org. All rights reserved. */\n/* MIT License */", "acme corp", true},
+		{"// Copyright (c) 2014-present, Facebook, Inc.\n// @license MIT", "acme corp", true},
+		{"// Copyright 2020 Other Org\n// no license named here", "acme corp", false},
+		{"// Copyright 2020 Other Org\n// Licensed under the MIT license", "", false},
+		{"/*! @license jQuery v3.7.1 */", "", true},
+	}
+
+	for _, test := range tests {
+		if got := foreignLicense(strings.Split(test.header, "\n"), test.holder); got != test.expected {
+			t.Errorf("foreignLicense(%q, %q) = %t, expected %t", test.header, test.holder, got, test.expected)
+		}
+	}
+}
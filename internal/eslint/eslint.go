@@ -1,28 +1,104 @@
 package eslint
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 
-	"codecompass/internal/types"
+	"github.com/xeon-zolt/codecompass/internal/config"
+	"github.com/xeon-zolt/codecompass/internal/repopath"
+	"github.com/xeon-zolt/codecompass/internal/toolversion"
+	"github.com/xeon-zolt/codecompass/internal/types"
+	"github.com/xeon-zolt/codecompass/internal/utils"
 )
 
-func RunESLint(trackedFiles map[string]bool, ignoredRules []string) ([]types.Issue, error) {
-	cmd := exec.Command("npx", "eslint", ".", "--format", "json")
+// ParseErrorRuleID is the synthetic rule used for fatal messages and messages
+// ESLint reports without a rule ID, such as parse errors and plugin crashes.
+const ParseErrorRuleID = "eslint/parse-error"
+
+// configFiles are the files whose presence means ESLint can run in a
+// repository.
+var configFiles = []string{
+	"package.json",
+	"eslint.config.js",
+	"eslint.config.mjs",
+	"eslint.config.cjs",
+	".eslintrc",
+	".eslintrc.js",
+	".eslintrc.cjs",
+	".eslintrc.json",
+	".eslintrc.yml",
+	".eslintrc.yaml",
+}
+
+// Source is the ESLint lint source.
+type Source struct{}
+
+func (Source) Name() string {
+	return "eslint"
+}
+
+// Detect reports whether dir has a package.json or an ESLint config.
+func (Source) Detect(ctx context.Context, dir string) bool {
+	for _, file := range configFiles {
+		if _, err := os.Stat(filepath.Join(dir, file)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Versions returns the versions of ESLint and of the Node.js it runs on.
+// npx is not allowed to install ESLint just to tell its version.
+func (Source) Versions(ctx context.Context, dir string) map[string]string {
+	return map[string]string{
+		"node":   toolversion.Command(ctx, dir, "node", "--version"),
+		"eslint": toolversion.Command(ctx, dir, "npx", "--no-install", "eslint", "--version"),
+	}
+}
+
+func (Source) Run(ctx context.Context, dir string, files map[string]bool, cfg *config.Config) ([]types.Issue, error) {
+	return RunESLint(ctx, dir, files, cfg.IgnoredRules, cfg.ESLintSeverities)
+}
+
+// RunESLint lints the repository in dir and returns issues for tracked files.
+// An empty dir means the current working directory. ESLint is killed when
+// ctx is done. severities maps ESLint severities to issue severities, as
+// config.Config.ESLintSeverities does; unmapped severities are kept, and
+// issues that end up with severity 0 are dropped.
+func RunESLint(ctx context.Context, dir string, trackedFiles map[string]bool, ignoredRules []string, severities map[int]int) ([]types.Issue, error) {
+	if err := utils.RequireTool("npx"); err != nil {
+		return nil, err
+	}
+	cmd := exec.CommandContext(ctx, "npx", "eslint", ".", "--format", "json")
+	cmd.Dir = dir
 	output, err := cmd.Output()
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
 	if err != nil {
 		if exitError, ok := err.(*exec.ExitError); ok {
 			output = exitError.Stderr
 			if len(output) == 0 {
-				cmd = exec.Command("npx", "eslint", ".", "--format", "json")
+				cmd = exec.CommandContext(ctx, "npx", "eslint", ".", "--format", "json")
+				cmd.Dir = dir
 				output, _ = cmd.Output()
 			}
 		}
 	}
 
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		root, _ = os.Getwd()
+	}
+
+	return parseESLintOutput(output, root, trackedFiles, ignoredRules, severities)
+}
+
+func parseESLintOutput(output []byte, cwd string, trackedFiles map[string]bool, ignoredRules []string, severities map[int]int) ([]types.Issue, error) {
 	var results []types.ESLintResult
 	if err := json.Unmarshal(output, &results); err != nil {
 		return nil, fmt.Errorf("failed to parse ESLint output: %v", err)
@@ -34,31 +110,56 @@ func RunESLint(trackedFiles map[string]bool, ignoredRules []string) ([]types.Iss
 		ignoredRulesMap[rule] = true
 	}
 
-	cwd, _ := os.Getwd()
-
+	resolver := repopath.New(cwd, trackedFiles)
 	for _, result := range results {
-		relPath, err := filepath.Rel(cwd, result.FilePath)
-		if err != nil {
-			relPath = result.FilePath
-		}
-
+		relPath := resolver.Resolve(result.FilePath)
 		if !trackedFiles[relPath] {
 			continue
 		}
 
 		for _, message := range result.Messages {
-			if ignoredRulesMap[message.RuleID] {
+			ruleID := message.RuleID
+			severity := message.Severity
+			if mapped, exists := severities[severity]; exists {
+				severity = mapped
+			}
+
+			// A fatal message or a null ruleId means ESLint could not lint
+			// the file at all
+			if message.Fatal || ruleID == "" {
+				ruleID = ParseErrorRuleID
+				severity = types.SeverityError
+			}
+
+			if ignoredRulesMap[ruleID] || severity == types.SeverityOff {
 				continue
 			}
 
 			issues = append(issues, types.Issue{
 				FilePath: relPath,
 				Line:     message.Line,
-				RuleID:   message.RuleID,
-				Severity: message.Severity,
+				RuleID:   ruleID,
+				Message:  message.Message,
+				Severity: severity,
+				Fixable:  message.Fix != nil,
 			})
 		}
 	}
 
 	return issues, nil
 }
+
+// UnparseableFiles maps each file with a parse error issue to the first
+// parse error message reported for it.
+func UnparseableFiles(issues []types.Issue) map[string]string {
+	files := make(map[string]string)
+	for _, issue := range issues {
+		if issue.RuleID != ParseErrorRuleID {
+			continue
+		}
+		if _, exists := files[issue.FilePath]; !exists {
+			files[issue.FilePath] = issue.Message
+		}
+	}
+	return files
+}
@@ -1,12 +1,17 @@
 package eslint
 
 import (
+	"context"
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"testing"
+	"time"
 
-	
+	"github.com/xeon-zolt/codecompass/internal/cerrors"
+	"github.com/xeon-zolt/codecompass/internal/config"
+	"github.com/xeon-zolt/codecompass/internal/types"
 )
 
 func TestRunESLint(t *testing.T) {
@@ -50,7 +55,7 @@ func TestRunESLint(t *testing.T) {
 
 	// Run ESLint
 	trackedFiles := map[string]bool{"test.js": true}
-	issues, err := RunESLint(trackedFiles, []string{})
+	issues, err := RunESLint(context.Background(), "", trackedFiles, []string{}, nil)
 	if err != nil {
 		t.Fatalf("RunESLint failed: %v", err)
 	}
@@ -73,3 +78,180 @@ func TestRunESLint(t *testing.T) {
 		t.Errorf("Expected file path to be 'test.js', but got '%s'", issue.FilePath)
 	}
 }
+
+func TestParseESLintOutputParseErrors(t *testing.T) {
+	output := []byte(`[
+		{
+			"filePath": "/repo/good.js",
+			"messages": [
+				{"ruleId": "no-console", "severity": 1, "message": "Unexpected console statement.", "line": 3, "column": 1}
+			]
+		},
+		{
+			"filePath": "/repo/broken.js",
+			"messages": [
+				{"ruleId": null, "fatal": true, "severity": 1, "message": "Parsing error: Unexpected token }", "line": 7, "column": 2}
+			]
+		}
+	]`)
+	trackedFiles := map[string]bool{"good.js": true, "broken.js": true}
+
+	issues, err := parseESLintOutput(output, "/repo", trackedFiles, []string{}, nil)
+	if err != nil {
+		t.Fatalf("parseESLintOutput failed: %v", err)
+	}
+
+	if len(issues) != 2 {
+		t.Fatalf("Expected 2 issues, but got %d", len(issues))
+	}
+
+	parseError := issues[1]
+	if parseError.RuleID != ParseErrorRuleID {
+		t.Errorf("Expected rule ID to be '%s', but got '%s'", ParseErrorRuleID, parseError.RuleID)
+	}
+
+	if parseError.Severity != 2 {
+		t.Errorf("Expected parse error severity to be 2, but got %d", parseError.Severity)
+	}
+
+	if parseError.Message != "Parsing error: Unexpected token }" {
+		t.Errorf("Expected parse error message to be kept, but got '%s'", parseError.Message)
+	}
+
+	unparseable := UnparseableFiles(issues)
+	if len(unparseable) != 1 || unparseable["broken.js"] == "" {
+		t.Errorf("Expected broken.js to be the only unparseable file, but got %v", unparseable)
+	}
+
+	// Ignoring the synthetic rule drops parse errors like any other rule
+	issues, err = parseESLintOutput(output, "/repo", trackedFiles, []string{ParseErrorRuleID}, nil)
+	if err != nil {
+		t.Fatalf("parseESLintOutput failed: %v", err)
+	}
+
+	if len(issues) != 1 || issues[0].RuleID != "no-console" {
+		t.Errorf("Expected only the no-console issue to remain, but got %v", issues)
+	}
+}
+
+func TestParseESLintOutputFatalMessages(t *testing.T) {
+	// Some plugins report fatal messages with their own rule ID
+	output := []byte(`[
+		{
+			"filePath": "/repo/broken.ts",
+			"messages": [
+				{"ruleId": "ts/parser", "fatal": true, "severity": 1, "message": "Cannot read file tsconfig.json", "line": 1, "column": 1}
+			]
+		}
+	]`)
+
+	issues, err := parseESLintOutput(output, "/repo", map[string]bool{"broken.ts": true}, nil, nil)
+	if err != nil {
+		t.Fatalf("parseESLintOutput failed: %v", err)
+	}
+
+	if len(issues) != 1 || issues[0].RuleID != ParseErrorRuleID || issues[0].Severity != 2 {
+		t.Errorf("Expected one parse error with severity 2, but got %v", issues)
+	}
+}
+
+func TestParseESLintOutputFixable(t *testing.T) {
+	output := []byte(`[
+		{
+			"filePath": "/repo/app.js",
+			"messages": [
+				{"ruleId": "semi", "severity": 2, "message": "Missing semicolon.", "line": 1, "column": 10, "fix": {"range": [9, 9], "text": ";"}},
+				{"ruleId": "no-undef", "severity": 2, "message": "'foo' is not defined.", "line": 2, "column": 1}
+			]
+		}
+	]`)
+
+	issues, err := parseESLintOutput(output, "/repo", map[string]bool{"app.js": true}, nil, nil)
+	if err != nil {
+		t.Fatalf("parseESLintOutput failed: %v", err)
+	}
+
+	if len(issues) != 2 || !issues[0].Fixable || issues[1].Fixable {
+		t.Errorf("Expected only the semi issue to be fixable, but got %+v", issues)
+	}
+}
+
+func TestParseESLintOutputSeverityMapping(t *testing.T) {
+	output := []byte(`[
+		{
+			"filePath": "/repo/app.js",
+			"messages": [
+				{"ruleId": "no-unused-vars", "severity": 0, "message": "Off", "line": 1, "column": 1},
+				{"ruleId": "no-console", "severity": 1, "message": "Unexpected console statement.", "line": 2, "column": 1},
+				{"ruleId": "eqeqeq", "severity": 2, "message": "Expected '==='.", "line": 3, "column": 1}
+			]
+		}
+	]`)
+	trackedFiles := map[string]bool{"app.js": true}
+
+	tests := []struct {
+		name       string
+		severities map[int]int
+		expected   map[string]int
+	}{
+		{"default", config.NewConfig().ESLintSeverities, map[string]int{"no-console": types.SeverityWarning, "eqeqeq": types.SeverityError}},
+		{"no mapping", nil, map[string]int{"no-console": types.SeverityWarning, "eqeqeq": types.SeverityError}},
+		{"warnings as errors", map[int]int{1: types.SeverityError}, map[string]int{"no-console": types.SeverityError, "eqeqeq": types.SeverityError}},
+		{"warnings off", map[int]int{1: types.SeverityOff}, map[string]int{"eqeqeq": types.SeverityError}},
+		{"off as warnings", map[int]int{0: types.SeverityWarning}, map[string]int{"no-unused-vars": types.SeverityWarning, "no-console": types.SeverityWarning, "eqeqeq": types.SeverityError}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues, err := parseESLintOutput(output, "/repo", trackedFiles, nil, tt.severities)
+			if err != nil {
+				t.Fatalf("parseESLintOutput failed: %v", err)
+			}
+
+			got := make(map[string]int)
+			for _, issue := range issues {
+				got[issue.RuleID] = issue.Severity
+			}
+			if len(got) != len(tt.expected) {
+				t.Fatalf("Expected severities %v, but got %v", tt.expected, got)
+			}
+			for rule, severity := range tt.expected {
+				if got[rule] != severity {
+					t.Errorf("Expected %s to have severity %d, but got %d", rule, severity, got[rule])
+				}
+			}
+		})
+	}
+}
+
+func TestRunESLintCancelled(t *testing.T) {
+	// Replace npx with a command that never finishes on its own
+	bin := t.TempDir()
+	script := "#!/bin/sh\nexec sleep 30\n"
+	if err := os.WriteFile(filepath.Join(bin, "npx"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := RunESLint(ctx, t.TempDir(), map[string]bool{}, nil, nil)
+	if err != context.Canceled {
+		t.Errorf("Expected context.Canceled, but got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected cancellation to stop ESLint promptly, but it took %v", elapsed)
+	}
+}
+
+func TestRunESLintWithoutNpx(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	_, err := RunESLint(context.Background(), t.TempDir(), map[string]bool{}, nil, nil)
+	var toolErr *cerrors.ErrToolNotFound
+	if !errors.As(err, &toolErr) || toolErr.Tool != "npx" {
+		t.Errorf("Expected ErrToolNotFound for npx, but got %v", err)
+	}
+}
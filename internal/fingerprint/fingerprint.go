@@ -0,0 +1,145 @@
+// Package fingerprint gives lint issues an identity that survives the edits
+// around them, so the issues of two runs can be matched even after lines
+// were added or removed above them.
+package fingerprint
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/xeon-zolt/codecompass/internal/types"
+	"github.com/xeon-zolt/codecompass/internal/utils"
+)
+
+// Normalize returns line with all whitespace removed, so reindenting or
+// reformatting a line keeps the fingerprints of its issues. Identifiers are
+// kept as they are: renaming a variable changes the code the issue is about,
+// and collapsing them would make most lines of a file look alike.
+func Normalize(line string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, line)
+}
+
+// Compute returns the fingerprint of each of issues, in the same order. A
+// fingerprint hashes the path of the file, the rule and the normalized
+// source line of the issue, but not its line number. Issues that would hash
+// alike, such as two of the same rule on one line or on identical lines of
+// a file, are told apart by the order of their lines, so each fingerprint
+// of a run is unique.
+//
+// Files are read from dir, with lines of at most maxLineKB kilobytes. An
+// issue whose line cannot be read, because the file is gone or the line is
+// too long, is fingerprinted with an empty line.
+func Compute(dir string, issues []types.Issue, maxLineKB int) []string {
+	order := make([]int, len(issues))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := issues[order[i]], issues[order[j]]
+		if a.FilePath != b.FilePath {
+			return a.FilePath < b.FilePath
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		if a.RuleID != b.RuleID {
+			return a.RuleID < b.RuleID
+		}
+		return a.Message < b.Message
+	})
+
+	fingerprints := make([]string, len(issues))
+	files := make(map[string][]string)
+	counter := make(Counter)
+	for _, i := range order {
+		issue := issues[i]
+		path := filepath.ToSlash(issue.FilePath)
+		lines, ok := files[path]
+		if !ok {
+			lines = readLines(filepath.Join(dir, issue.FilePath), maxLineKB)
+			files[path] = lines
+		}
+		text := ""
+		if issue.Line >= 1 && issue.Line <= len(lines) {
+			text = lines[issue.Line-1]
+		}
+
+		fingerprints[i] = counter.Next(path, issue.RuleID, text)
+	}
+	return fingerprints
+}
+
+// Counter fingerprints findings other than lint issues, such as debt
+// markers, the way Compute does: findings that would hash alike are told
+// apart by the order they are given in, which should be that of their
+// lines.
+type Counter map[string]int
+
+// Next returns the fingerprint of a finding of rule, or of a kind of
+// finding, on line of the file at path, a slash-separated path.
+func (c Counter) Next(path, rule, line string) string {
+	key := path + "\x00" + rule + "\x00" + Normalize(line)
+	fingerprint := hash(key, c[key])
+	c[key]++
+	return fingerprint
+}
+
+func hash(key string, occurrence int) string {
+	sum := sha256.Sum256([]byte(key + "\x00" + strconv.Itoa(occurrence)))
+	return hex.EncodeToString(sum[:16])
+}
+
+// readLines returns the lines of the file at path, or none when it cannot
+// be read in full.
+func readLines(path string, maxLineKB int) []string {
+	file, err := utils.OpenRegular(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := utils.NewLineScanner(file, maxLineKB)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if scanner.Err() != nil {
+		return nil
+	}
+	return lines
+}
+
+// Classify compares the fingerprints of a run with those of a reference,
+// such as an accepted baseline, counting the issues that are new, those
+// that already existed, and the reference issues that are gone.
+func Classify(fingerprints, reference []string) (added, existing, fixed int) {
+	known := make(map[string]bool, len(reference))
+	for _, fingerprint := range reference {
+		known[fingerprint] = true
+	}
+	seen := make(map[string]bool, len(fingerprints))
+	for _, fingerprint := range fingerprints {
+		seen[fingerprint] = true
+		if known[fingerprint] {
+			existing++
+		} else {
+			added++
+		}
+	}
+	for fingerprint := range known {
+		if !seen[fingerprint] {
+			fixed++
+		}
+	}
+	return added, existing, fixed
+}
@@ -0,0 +1,123 @@
+package fingerprint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"  if (a == b) {":    "if(a==b){",
+		"\tif (a==b)  {  \r": "if(a==b){",
+		"var x = 1;":         "varx=1;",
+		"":                   "",
+	}
+	for line, expected := range tests {
+		if normalized := Normalize(line); normalized != expected {
+			t.Errorf("Normalize(%q) = %q; expected %q", line, normalized, expected)
+		}
+	}
+
+	// Identifiers are not collapsed
+	if Normalize("var x = 1;") == Normalize("var y = 1;") {
+		t.Error("Expected lines differing in an identifier to normalize differently")
+	}
+}
+
+func TestComputeSurvivesMovedAndReformattedLines(t *testing.T) {
+	before, after := t.TempDir(), t.TempDir()
+	writeFile(t, before, "src/app.js", "if (a == b) {\n  go();\n}\n")
+	// Two lines added above, and the line reindented
+	writeFile(t, after, "src/app.js", "// header\n\n    if (a==b)   {\n  go();\n}\n")
+
+	old := Compute(before, []types.Issue{{FilePath: "src/app.js", Line: 1, RuleID: "eqeqeq"}}, 1024)
+	moved := Compute(after, []types.Issue{{FilePath: "src/app.js", Line: 3, RuleID: "eqeqeq"}}, 1024)
+	if old[0] != moved[0] {
+		t.Errorf("Expected the fingerprint to survive the move, got %s and %s", old[0], moved[0])
+	}
+
+	// The rule and the path are part of the identity
+	others := Compute(after, []types.Issue{
+		{FilePath: "src/app.js", Line: 3, RuleID: "curly"},
+		{FilePath: "src/other.js", Line: 3, RuleID: "eqeqeq"},
+	}, 1024)
+	for _, fingerprint := range others {
+		if fingerprint == old[0] {
+			t.Errorf("Expected a different rule or path to change the fingerprint, got %v", others)
+		}
+	}
+}
+
+func TestComputeCollisions(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.js", "x = a == b && c == d;\nfoo();\nx = a == b && c == d;\n")
+
+	issues := []types.Issue{
+		{FilePath: "a.js", Line: 3, RuleID: "eqeqeq", Message: "second"},
+		{FilePath: "a.js", Line: 1, RuleID: "eqeqeq", Message: "second"},
+		{FilePath: "a.js", Line: 1, RuleID: "eqeqeq", Message: "first"},
+		{FilePath: "a.js", Line: 3, RuleID: "eqeqeq", Message: "first"},
+	}
+	fingerprints := Compute(dir, issues, 1024)
+
+	// Duplicates on one line and on identical lines all stay distinct
+	seen := make(map[string]bool)
+	for _, fingerprint := range fingerprints {
+		if seen[fingerprint] {
+			t.Fatalf("Expected unique fingerprints, got %v", fingerprints)
+		}
+		seen[fingerprint] = true
+	}
+
+	// The order the linter reported them in does not matter
+	reordered := Compute(dir, []types.Issue{issues[2], issues[1], issues[3], issues[0]}, 1024)
+	if reordered[0] != fingerprints[2] || reordered[1] != fingerprints[1] || reordered[2] != fingerprints[3] || reordered[3] != fingerprints[0] {
+		t.Errorf("Expected fingerprints independent of the issue order, got %v and %v", fingerprints, reordered)
+	}
+
+	// Fixing the first of two identical issues hands its fingerprint to the
+	// second: the count of pre-existing issues is what stays right
+	fixed := Compute(dir, []types.Issue{issues[0], issues[3]}, 1024)
+	added, existing, gone := Classify(fixed, fingerprints)
+	if added != 0 || existing != 2 || gone != 2 {
+		t.Errorf("Expected 0 new, 2 existing and 2 fixed issues, got %d, %d and %d", added, existing, gone)
+	}
+}
+
+func TestComputeUnreadableFile(t *testing.T) {
+	dir := t.TempDir()
+	issues := []types.Issue{
+		{FilePath: "gone.js", Line: 1, RuleID: "semi"},
+		{FilePath: "gone.js", Line: 7, RuleID: "semi"},
+	}
+	fingerprints := Compute(dir, issues, 1024)
+	if fingerprints[0] == "" || fingerprints[0] == fingerprints[1] {
+		t.Errorf("Expected distinct fingerprints for issues in a missing file, got %v", fingerprints)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	added, existing, fixed := Classify([]string{"a", "b", "c"}, []string{"b", "c", "d", "e"})
+	if added != 1 || existing != 2 || fixed != 2 {
+		t.Errorf("Expected 1 new, 2 existing and 2 fixed issues, got %d, %d and %d", added, existing, fixed)
+	}
+
+	// Against an empty baseline, every issue is new
+	if added, existing, fixed := Classify([]string{"a"}, []string{}); added != 1 || existing != 0 || fixed != 0 {
+		t.Errorf("Expected every issue to be new, got %d, %d and %d", added, existing, fixed)
+	}
+}
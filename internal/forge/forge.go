@@ -0,0 +1,191 @@
+// Package forge fetches pull request and review activity from code forges
+// such as GitHub and GitLab. Every forge implements Forge, and Stats turns what it
+// returns into the same leaderboards, so the printer and CSV writers do not
+// depend on where the data came from.
+package forge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+// ErrUnsupportedForge is returned by Detect for remotes on hosts no Forge
+// implementation supports.
+var ErrUnsupportedForge = errors.New("unsupported forge")
+
+// Forge is a code hosting service CodeCompass can fetch pull requests from.
+type Forge interface {
+	// Name identifies the forge, such as "github".
+	Name() string
+
+	// Repo is the repository slug, such as owner/name.
+	Repo() string
+
+	// MergedPullRequests returns the pull requests merged at or after
+	// since, with their reviews.
+	MergedPullRequests(ctx context.Context, since time.Time) ([]PullRequest, error)
+}
+
+// PullRequest is a merged pull request.
+type PullRequest struct {
+	Number    int
+	Author    string
+	Additions int
+	Deletions int
+	CreatedAt time.Time
+	MergedAt  time.Time
+	Reviews   []Review
+}
+
+// Review is one review submitted on a pull request.
+type Review struct {
+	Reviewer string
+	State    string // "APPROVED", "CHANGES_REQUESTED" or "COMMENTED"
+}
+
+// ReviewApproved is the state of a review that approved the pull request.
+const ReviewApproved = "APPROVED"
+
+// Detect returns the forge hosting the repository with the given remote URL.
+// Tokens are read through getenv, typically os.Getenv. Remotes on gitlab.com,
+// on hosts named gitlab.*, or on the host of gitlabBaseURL are GitLab
+// projects; gitlabBaseURL is only needed for self-hosted instances with
+// another name or that are not served from the root of the host.
+func Detect(remoteURL string, getenv func(string) string, cache *Cache, gitlabBaseURL string) (Forge, error) {
+	host, slug, err := ParseRemote(remoteURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if gitlabBaseURL != "" {
+		if parsed, err := url.Parse(gitlabBaseURL); err == nil && strings.EqualFold(parsed.Hostname(), host) {
+			// HTTPS remotes of an instance under a path include the path
+			prefix := strings.Trim(parsed.Path, "/") + "/"
+			return NewGitLab(gitlabBaseURL, strings.TrimPrefix(slug, prefix), getenv("GITLAB_TOKEN"), cache)
+		}
+	}
+
+	switch {
+	case host == "github.com":
+		return NewGitHub(slug, getenv("GITHUB_TOKEN"), cache)
+	case host == "gitlab.com":
+		return NewGitLab(gitlabURL, slug, getenv("GITLAB_TOKEN"), cache)
+	case strings.HasPrefix(host, "gitlab."):
+		return NewGitLab("https://"+host, slug, getenv("GITLAB_TOKEN"), cache)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedForge, host)
+	}
+}
+
+// ParseRemote splits a git remote URL into its host and repository slug. It
+// understands scp-like SSH remotes (git@host:owner/repo.git) as well as
+// ssh://, https:// and http:// URLs.
+func ParseRemote(remoteURL string) (host, slug string, err error) {
+	remoteURL = strings.TrimSpace(remoteURL)
+
+	if !strings.Contains(remoteURL, "://") {
+		// scp-like syntax: [user@]host:path
+		at := strings.Index(remoteURL, "@")
+		colon := strings.Index(remoteURL, ":")
+		if colon > at+1 {
+			host = remoteURL[at+1 : colon]
+			slug = remoteURL[colon+1:]
+		}
+	} else if parsed, parseErr := url.Parse(remoteURL); parseErr == nil {
+		host = parsed.Hostname()
+		slug = parsed.Path
+	}
+
+	slug = strings.TrimSuffix(strings.Trim(slug, "/"), ".git")
+	if host == "" || strings.Count(slug, "/") < 1 {
+		return "", "", fmt.Errorf("unrecognized remote URL %q", remoteURL)
+	}
+
+	return strings.ToLower(host), slug, nil
+}
+
+// Stats builds the pull request and review leaderboards from the pull
+// requests a forge returned. Reviews of one's own pull request are not
+// counted.
+func Stats(f Forge, pullRequests []PullRequest, since time.Time) types.ForgeStats {
+	type authorTotals struct {
+		merged      int
+		size        int
+		timeToMerge time.Duration
+	}
+	authors := make(map[string]*authorTotals)
+	reviewers := make(map[string]*types.ReviewerEntry)
+
+	for _, pr := range pullRequests {
+		author := authors[pr.Author]
+		if author == nil {
+			author = &authorTotals{}
+			authors[pr.Author] = author
+		}
+		author.merged++
+		author.size += pr.Additions + pr.Deletions
+		author.timeToMerge += pr.MergedAt.Sub(pr.CreatedAt)
+
+		reviewed := make(map[string]bool)
+		for _, review := range pr.Reviews {
+			if review.Reviewer == pr.Author {
+				continue
+			}
+
+			reviewer := reviewers[review.Reviewer]
+			if reviewer == nil {
+				reviewer = &types.ReviewerEntry{Login: review.Reviewer}
+				reviewers[review.Reviewer] = reviewer
+			}
+			reviewer.Reviews++
+			if review.State == ReviewApproved {
+				reviewer.Approvals++
+			}
+			if !reviewed[review.Reviewer] {
+				reviewed[review.Reviewer] = true
+				reviewer.PullRequests++
+			}
+		}
+	}
+
+	stats := types.ForgeStats{
+		Forge:        f.Name(),
+		Repo:         f.Repo(),
+		Since:        since,
+		PullRequests: len(pullRequests),
+	}
+
+	for login, totals := range authors {
+		stats.Authors = append(stats.Authors, types.PullRequestAuthorEntry{
+			Login:          login,
+			Merged:         totals.merged,
+			AvgSize:        float64(totals.size) / float64(totals.merged),
+			AvgTimeToMerge: totals.timeToMerge / time.Duration(totals.merged),
+		})
+	}
+	sort.SliceStable(stats.Authors, func(i, j int) bool {
+		if stats.Authors[i].Merged != stats.Authors[j].Merged {
+			return stats.Authors[i].Merged > stats.Authors[j].Merged
+		}
+		return stats.Authors[i].Login < stats.Authors[j].Login
+	})
+
+	for _, reviewer := range reviewers {
+		stats.Reviewers = append(stats.Reviewers, *reviewer)
+	}
+	sort.SliceStable(stats.Reviewers, func(i, j int) bool {
+		if stats.Reviewers[i].Reviews != stats.Reviewers[j].Reviews {
+			return stats.Reviewers[i].Reviews > stats.Reviewers[j].Reviews
+		}
+		return stats.Reviewers[i].Login < stats.Reviewers[j].Login
+	})
+
+	return stats
+}
@@ -0,0 +1,137 @@
+package forge
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/xeon-zolt/codecompass/internal/cerrors"
+)
+
+func TestParseRemote(t *testing.T) {
+	tests := []struct {
+		remote string
+		host   string
+		slug   string
+	}{
+		{"git@github.com:xeon-zolt/codecompass.git", "github.com", "xeon-zolt/codecompass"},
+		{"https://github.com/xeon-zolt/codecompass.git", "github.com", "xeon-zolt/codecompass"},
+		{"https://github.com/xeon-zolt/codecompass", "github.com", "xeon-zolt/codecompass"},
+		{"https://token@GitHub.com/xeon-zolt/codecompass/", "github.com", "xeon-zolt/codecompass"},
+		{"ssh://git@github.com:22/xeon-zolt/codecompass.git", "github.com", "xeon-zolt/codecompass"},
+		{"git@gitlab.example.com:group/sub/project.git", "gitlab.example.com", "group/sub/project"},
+	}
+
+	for _, tt := range tests {
+		host, slug, err := ParseRemote(tt.remote)
+		if err != nil || host != tt.host || slug != tt.slug {
+			t.Errorf("ParseRemote(%q) = %q, %q, %v; expected %q, %q", tt.remote, host, slug, err, tt.host, tt.slug)
+		}
+	}
+
+	for _, remote := range []string{"", "/srv/git/project.git", "https://github.com/only-owner"} {
+		if _, _, err := ParseRemote(remote); err == nil {
+			t.Errorf("Expected ParseRemote(%q) to fail", remote)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	env := map[string]string{"GITHUB_TOKEN": "secret"}
+	getenv := func(key string) string { return env[key] }
+
+	f, err := Detect("git@github.com:xeon-zolt/codecompass.git", getenv, nil, "")
+	if err != nil {
+		t.Fatalf("Detect failed: %v", err)
+	}
+	if f.Name() != "github" || f.Repo() != "xeon-zolt/codecompass" {
+		t.Errorf("Expected the GitHub forge for xeon-zolt/codecompass, but got %s %s", f.Name(), f.Repo())
+	}
+
+	if _, err := Detect("git@bitbucket.org:team/repo.git", getenv, nil, ""); !errors.Is(err, ErrUnsupportedForge) {
+		t.Errorf("Expected ErrUnsupportedForge, but got %v", err)
+	}
+
+	env["GITLAB_TOKEN"] = "secret"
+	for _, tt := range []struct {
+		remote  string
+		baseURL string
+		api     string
+		slug    string
+	}{
+		{"git@gitlab.com:group/sub/project.git", "", "https://gitlab.com/api/v4", "group/sub/project"},
+		{"https://gitlab.example.com/group/project.git", "", "https://gitlab.example.com/api/v4", "group/project"},
+		{"git@code.example.com:group/project.git", "https://code.example.com", "https://code.example.com/api/v4", "group/project"},
+		{"https://code.example.com/gitlab/group/project.git", "https://code.example.com/gitlab/", "https://code.example.com/gitlab/api/v4", "group/project"},
+	} {
+		f, err := Detect(tt.remote, getenv, nil, tt.baseURL)
+		if err != nil {
+			t.Errorf("Detect(%q) failed: %v", tt.remote, err)
+			continue
+		}
+		gitlab, ok := f.(*GitLab)
+		if !ok || gitlab.baseURL != tt.api || gitlab.Repo() != tt.slug {
+			t.Errorf("Detect(%q) = %+v; expected GitLab %s at %s", tt.remote, f, tt.slug, tt.api)
+		}
+	}
+
+	var tokenErr *cerrors.ErrMissingToken
+	if _, err := Detect("git@github.com:xeon-zolt/codecompass.git", func(string) string { return "" }, nil, ""); !errors.As(err, &tokenErr) {
+		t.Errorf("Expected ErrMissingToken, but got %v", err)
+	}
+}
+
+type fakeForge struct{}
+
+func (fakeForge) Name() string { return "fake" }
+func (fakeForge) Repo() string { return "owner/repo" }
+func (fakeForge) MergedPullRequests(ctx context.Context, since time.Time) ([]PullRequest, error) {
+	return nil, nil
+}
+
+func TestStats(t *testing.T) {
+	opened := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	pullRequests := []PullRequest{
+		{Number: 1, Author: "alice", Additions: 100, Deletions: 20, CreatedAt: opened, MergedAt: opened.Add(2 * time.Hour), Reviews: []Review{
+			{Reviewer: "bob", State: "CHANGES_REQUESTED"},
+			{Reviewer: "bob", State: ReviewApproved},
+			{Reviewer: "alice", State: "COMMENTED"},
+		}},
+		{Number: 2, Author: "alice", Additions: 30, Deletions: 10, CreatedAt: opened, MergedAt: opened.Add(4 * time.Hour), Reviews: []Review{
+			{Reviewer: "carol", State: ReviewApproved},
+		}},
+		{Number: 3, Author: "bob", Additions: 5, CreatedAt: opened, MergedAt: opened.Add(time.Hour), Reviews: []Review{
+			{Reviewer: "alice", State: ReviewApproved},
+		}},
+	}
+
+	stats := Stats(fakeForge{}, pullRequests, opened)
+
+	if stats.Forge != "fake" || stats.Repo != "owner/repo" || stats.PullRequests != 3 || !stats.Since.Equal(opened) {
+		t.Errorf("Unexpected stats header: %+v", stats)
+	}
+
+	if len(stats.Authors) != 2 {
+		t.Fatalf("Expected 2 authors, but got %+v", stats.Authors)
+	}
+	alice := stats.Authors[0]
+	if alice.Login != "alice" || alice.Merged != 2 || alice.AvgSize != 80 || alice.AvgTimeToMerge != 3*time.Hour {
+		t.Errorf("Expected alice to have merged 2 PRs of 80 lines in 3h on average, but got %+v", alice)
+	}
+
+	if len(stats.Reviewers) != 3 {
+		t.Fatalf("Expected 3 reviewers, but got %+v", stats.Reviewers)
+	}
+	bob := stats.Reviewers[0]
+	if bob.Login != "bob" || bob.Reviews != 2 || bob.Approvals != 1 || bob.PullRequests != 1 {
+		t.Errorf("Expected bob to have given 2 reviews with 1 approval on 1 PR, but got %+v", bob)
+	}
+
+	// Alice's comment on her own pull request is not a review
+	for _, reviewer := range stats.Reviewers {
+		if reviewer.Login == "alice" && reviewer.Reviews != 1 {
+			t.Errorf("Expected alice's self-review to be ignored, but got %+v", reviewer)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/xeon-zolt/codecompass/internal/cerrors"
+)
+
+// githubAPI is the base URL of the GitHub REST API.
+const githubAPI = "https://api.github.com"
+
+// GitHub fetches pull requests through the GitHub REST API.
+type GitHub struct {
+	slug    string
+	baseURL string
+	rest    *restClient
+}
+
+// NewGitHub returns a GitHub forge for the repository slug (owner/name). It
+// returns cerrors.ErrMissingToken when token is empty.
+func NewGitHub(slug, token string, cache *Cache) (*GitHub, error) {
+	if token == "" {
+		return nil, &cerrors.ErrMissingToken{Env: "GITHUB_TOKEN"}
+	}
+
+	header := http.Header{}
+	header.Set("Accept", "application/vnd.github+json")
+	header.Set("Authorization", "Bearer "+token)
+	header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	return &GitHub{
+		slug:    slug,
+		baseURL: githubAPI,
+		rest:    newRESTClient("GitHub", header, cache),
+	}, nil
+}
+
+func (g *GitHub) Name() string {
+	return "github"
+}
+
+func (g *GitHub) Repo() string {
+	return g.slug
+}
+
+type githubUser struct {
+	Login string `json:"login"`
+}
+
+type githubPull struct {
+	Number    int        `json:"number"`
+	User      githubUser `json:"user"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	MergedAt  *time.Time `json:"merged_at"`
+	Additions int        `json:"additions"`
+	Deletions int        `json:"deletions"`
+}
+
+type githubReview struct {
+	User  githubUser `json:"user"`
+	State string     `json:"state"`
+}
+
+// MergedPullRequests pages through closed pull requests, most recently
+// updated first, and stops at the first one last updated before since. A
+// pull request merged after since cannot have been updated before it.
+func (g *GitHub) MergedPullRequests(ctx context.Context, since time.Time) ([]PullRequest, error) {
+	var pullRequests []PullRequest
+
+	next := fmt.Sprintf("%s/repos/%s/pulls?state=closed&sort=updated&direction=desc&per_page=100", g.baseURL, g.slug)
+	for next != "" {
+		var page []githubPull
+		link, err := g.rest.get(ctx, next, &page)
+		if err != nil {
+			return nil, err
+		}
+		next = link
+
+		for _, pull := range page {
+			if pull.UpdatedAt.Before(since) {
+				next = ""
+				break
+			}
+			if pull.MergedAt == nil || pull.MergedAt.Before(since) {
+				continue
+			}
+
+			pr, err := g.pullRequest(ctx, pull.Number)
+			if err != nil {
+				return nil, err
+			}
+			pullRequests = append(pullRequests, pr)
+		}
+	}
+
+	return pullRequests, nil
+}
+
+// pullRequest fetches the size and reviews of one pull request. The list
+// endpoint leaves out additions and deletions.
+func (g *GitHub) pullRequest(ctx context.Context, number int) (PullRequest, error) {
+	var pull githubPull
+	if _, err := g.rest.get(ctx, fmt.Sprintf("%s/repos/%s/pulls/%d", g.baseURL, g.slug, number), &pull); err != nil {
+		return PullRequest{}, err
+	}
+
+	pr := PullRequest{
+		Number:    pull.Number,
+		Author:    pull.User.Login,
+		Additions: pull.Additions,
+		Deletions: pull.Deletions,
+		CreatedAt: pull.CreatedAt,
+	}
+	if pull.MergedAt != nil {
+		pr.MergedAt = *pull.MergedAt
+	}
+
+	next := fmt.Sprintf("%s/repos/%s/pulls/%d/reviews?per_page=100", g.baseURL, g.slug, number)
+	for next != "" {
+		var reviews []githubReview
+		link, err := g.rest.get(ctx, next, &reviews)
+		if err != nil {
+			return PullRequest{}, err
+		}
+		next = link
+
+		for _, review := range reviews {
+			// Pending reviews have not been submitted yet
+			if review.State == "PENDING" {
+				continue
+			}
+			pr.Reviews = append(pr.Reviews, Review{Reviewer: review.User.Login, State: review.State})
+		}
+	}
+
+	return pr, nil
+}
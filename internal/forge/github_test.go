@@ -0,0 +1,162 @@
+package forge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xeon-zolt/codecompass/internal/cerrors"
+)
+
+// fakeGitHub serves a repository with pull requests split over two pages and
+// answers conditional requests with 304 Not Modified.
+type fakeGitHub struct {
+	server *httptest.Server
+
+	mu          sync.Mutex
+	full        int // responses with a body
+	revalidated int // 304 responses
+	limitHit    bool
+}
+
+func newFakeGitHub(t *testing.T) *fakeGitHub {
+	t.Helper()
+
+	f := &fakeGitHub{}
+	since := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	at := func(hours int) string { return since.Add(time.Duration(hours) * time.Hour).Format(time.RFC3339) }
+
+	responses := map[string]string{
+		"/repos/owner/repo/pulls?state=closed&sort=updated&direction=desc&per_page=100": fmt.Sprintf(`[
+			{"number": 3, "user": {"login": "alice"}, "created_at": %q, "updated_at": %q, "merged_at": %q},
+			{"number": 2, "user": {"login": "bob"}, "created_at": %q, "updated_at": %q, "merged_at": null}
+		]`, at(10), at(30), at(20), at(5), at(8)),
+		"/repos/owner/repo/pulls?page=2": fmt.Sprintf(`[
+			{"number": 1, "user": {"login": "bob"}, "created_at": %q, "updated_at": %q, "merged_at": %q},
+			{"number": 0, "user": {"login": "carol"}, "created_at": %q, "updated_at": %q, "merged_at": %q}
+		]`, at(1), at(4), at(3), at(-50), at(-10), at(-20)),
+		"/repos/owner/repo/pulls/3":                      fmt.Sprintf(`{"number": 3, "user": {"login": "alice"}, "created_at": %q, "merged_at": %q, "additions": 40, "deletions": 10}`, at(10), at(20)),
+		"/repos/owner/repo/pulls/1":                      fmt.Sprintf(`{"number": 1, "user": {"login": "bob"}, "created_at": %q, "merged_at": %q, "additions": 5, "deletions": 5}`, at(1), at(3)),
+		"/repos/owner/repo/pulls/3/reviews?per_page=100": `[{"user": {"login": "bob"}, "state": "APPROVED"}, {"user": {"login": "carol"}, "state": "PENDING"}]`,
+		"/repos/owner/repo/pulls/1/reviews?per_page=100": `[{"user": {"login": "alice"}, "state": "COMMENTED"}]`,
+	}
+
+	f.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if f.limitHit {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", "1717243200")
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		body, ok := responses[r.URL.RequestURI()]
+		if !ok {
+			t.Errorf("Unexpected request %s", r.URL.RequestURI())
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		etag := fmt.Sprintf(`"%x"`, len(body))
+		if r.Header.Get("If-None-Match") == etag {
+			f.revalidated++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		if r.URL.Path == "/repos/owner/repo/pulls" && r.URL.Query().Get("page") == "" {
+			w.Header().Set("Link", fmt.Sprintf(`<%s/repos/owner/repo/pulls?page=2>; rel="next", <%s/repos/owner/repo/pulls?page=2>; rel="last"`, f.server.URL, f.server.URL))
+		}
+		w.Header().Set("ETag", etag)
+		f.full++
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(f.server.Close)
+
+	return f
+}
+
+func (f *fakeGitHub) client(t *testing.T, cache *Cache) *GitHub {
+	t.Helper()
+
+	g, err := NewGitHub("owner/repo", "secret", cache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.baseURL = f.server.URL
+	return g
+}
+
+func TestGitHubMergedPullRequests(t *testing.T) {
+	server := newFakeGitHub(t)
+	since := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	g := server.client(t, NewCache(t.TempDir()))
+
+	pullRequests, err := g.MergedPullRequests(context.Background(), since)
+	if err != nil {
+		t.Fatalf("MergedPullRequests failed: %v", err)
+	}
+
+	// #2 was closed without merging and #0 was merged before since
+	if len(pullRequests) != 2 || pullRequests[0].Number != 3 || pullRequests[1].Number != 1 {
+		t.Fatalf("Expected pull requests #3 and #1, but got %+v", pullRequests)
+	}
+
+	alice := pullRequests[0]
+	if alice.Author != "alice" || alice.Additions != 40 || alice.Deletions != 10 || alice.MergedAt.Sub(alice.CreatedAt) != 10*time.Hour {
+		t.Errorf("Unexpected details for #3: %+v", alice)
+	}
+	if len(alice.Reviews) != 1 || alice.Reviews[0].Reviewer != "bob" || alice.Reviews[0].State != ReviewApproved {
+		t.Errorf("Expected one submitted approval from bob on #3, but got %+v", alice.Reviews)
+	}
+}
+
+func TestGitHubRevalidatesCachedResponses(t *testing.T) {
+	server := newFakeGitHub(t)
+	since := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	cache := NewCache(t.TempDir())
+
+	first, err := server.client(t, cache).MergedPullRequests(context.Background(), since)
+	if err != nil {
+		t.Fatalf("First run failed: %v", err)
+	}
+	fetched := server.full
+
+	second, err := server.client(t, cache).MergedPullRequests(context.Background(), since)
+	if err != nil {
+		t.Fatalf("Second run failed: %v", err)
+	}
+
+	if server.full != fetched || server.revalidated != fetched {
+		t.Errorf("Expected the second run to revalidate all %d responses, but got %d full and %d not modified", fetched, server.full-fetched, server.revalidated)
+	}
+	if fmt.Sprint(first) != fmt.Sprint(second) {
+		t.Errorf("Expected cached results to match\nfirst:  %+v\nsecond: %+v", first, second)
+	}
+}
+
+func TestGitHubRateLimited(t *testing.T) {
+	server := newFakeGitHub(t)
+	server.limitHit = true
+
+	_, err := server.client(t, nil).MergedPullRequests(context.Background(), time.Time{})
+
+	var limitErr *cerrors.ErrRateLimited
+	if !errors.As(err, &limitErr) {
+		t.Fatalf("Expected ErrRateLimited, but got %v", err)
+	}
+	if !limitErr.Reset.Equal(time.Unix(1717243200, 0)) {
+		t.Errorf("Expected the reset time from X-RateLimit-Reset, but got %v", limitErr.Reset)
+	}
+}
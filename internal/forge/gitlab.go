@@ -0,0 +1,161 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/xeon-zolt/codecompass/internal/cerrors"
+)
+
+// gitlabURL is the base URL of GitLab.com. Self-hosted instances have their
+// own.
+const gitlabURL = "https://gitlab.com"
+
+// GitLab fetches merge requests through the GitLab REST API. Merge requests
+// are reported as pull requests and approvals as approving reviews; GitLab
+// has no other review states.
+type GitLab struct {
+	slug    string
+	baseURL string // API root, such as https://gitlab.com/api/v4
+	rest    *restClient
+}
+
+// NewGitLab returns a GitLab forge for the project slug (group/name, groups
+// may be nested) on the instance at baseURL, such as https://gitlab.com. It
+// returns cerrors.ErrMissingToken when token is empty.
+func NewGitLab(baseURL, slug, token string, cache *Cache) (*GitLab, error) {
+	if token == "" {
+		return nil, &cerrors.ErrMissingToken{Env: "GITLAB_TOKEN"}
+	}
+
+	header := http.Header{}
+	header.Set("Accept", "application/json")
+	header.Set("PRIVATE-TOKEN", token)
+
+	return &GitLab{
+		slug:    slug,
+		baseURL: strings.TrimSuffix(baseURL, "/") + "/api/v4",
+		rest:    newRESTClient("GitLab", header, cache),
+	}, nil
+}
+
+func (g *GitLab) Name() string {
+	return "gitlab"
+}
+
+func (g *GitLab) Repo() string {
+	return g.slug
+}
+
+type gitlabUser struct {
+	Username string `json:"username"`
+}
+
+type gitlabMergeRequest struct {
+	IID       int        `json:"iid"`
+	Author    gitlabUser `json:"author"`
+	CreatedAt time.Time  `json:"created_at"`
+	MergedAt  *time.Time `json:"merged_at"`
+}
+
+type gitlabDiff struct {
+	Diff string `json:"diff"`
+}
+
+type gitlabApprovals struct {
+	ApprovedBy []struct {
+		User gitlabUser `json:"user"`
+	} `json:"approved_by"`
+}
+
+// project is the URL-encoded project path the API accepts in place of an ID.
+func (g *GitLab) project() string {
+	return url.PathEscape(g.slug)
+}
+
+// MergedPullRequests pages through merge requests merged and last updated at
+// or after since. A merge request merged after since cannot have been
+// updated before it.
+func (g *GitLab) MergedPullRequests(ctx context.Context, since time.Time) ([]PullRequest, error) {
+	var pullRequests []PullRequest
+
+	next := fmt.Sprintf("%s/projects/%s/merge_requests?state=merged&updated_after=%s&order_by=updated_at&sort=desc&per_page=100",
+		g.baseURL, g.project(), url.QueryEscape(since.UTC().Format(time.RFC3339)))
+	for next != "" {
+		var page []gitlabMergeRequest
+		link, err := g.rest.get(ctx, next, &page)
+		if err != nil {
+			return nil, err
+		}
+		next = link
+
+		for _, mr := range page {
+			if mr.MergedAt == nil || mr.MergedAt.Before(since) {
+				continue
+			}
+
+			pr, err := g.mergeRequest(ctx, mr)
+			if err != nil {
+				return nil, err
+			}
+			pullRequests = append(pullRequests, pr)
+		}
+	}
+
+	return pullRequests, nil
+}
+
+// mergeRequest fetches the size and approvals of one merge request. The list
+// endpoint has neither.
+func (g *GitLab) mergeRequest(ctx context.Context, mr gitlabMergeRequest) (PullRequest, error) {
+	pr := PullRequest{
+		Number:    mr.IID,
+		Author:    mr.Author.Username,
+		CreatedAt: mr.CreatedAt,
+		MergedAt:  *mr.MergedAt,
+	}
+
+	next := fmt.Sprintf("%s/projects/%s/merge_requests/%d/diffs?per_page=100", g.baseURL, g.project(), mr.IID)
+	for next != "" {
+		var diffs []gitlabDiff
+		link, err := g.rest.get(ctx, next, &diffs)
+		if err != nil {
+			return PullRequest{}, err
+		}
+		next = link
+
+		for _, diff := range diffs {
+			additions, deletions := countDiffLines(diff.Diff)
+			pr.Additions += additions
+			pr.Deletions += deletions
+		}
+	}
+
+	var approvals gitlabApprovals
+	if _, err := g.rest.get(ctx, fmt.Sprintf("%s/projects/%s/merge_requests/%d/approvals", g.baseURL, g.project(), mr.IID), &approvals); err != nil {
+		return PullRequest{}, err
+	}
+	for _, approval := range approvals.ApprovedBy {
+		pr.Reviews = append(pr.Reviews, Review{Reviewer: approval.User.Username, State: ReviewApproved})
+	}
+
+	return pr, nil
+}
+
+// countDiffLines counts the added and deleted lines of a unified diff
+// without file headers, as GitLab returns it.
+func countDiffLines(diff string) (additions, deletions int) {
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+"):
+			additions++
+		case strings.HasPrefix(line, "-"):
+			deletions++
+		}
+	}
+	return additions, deletions
+}
@@ -0,0 +1,165 @@
+package forge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xeon-zolt/codecompass/internal/cerrors"
+)
+
+// fakeGitLab serves a project in a nested group with merge requests split
+// over two pages and answers conditional requests with 304 Not Modified.
+type fakeGitLab struct {
+	server *httptest.Server
+
+	mu          sync.Mutex
+	full        int
+	revalidated int
+	limitHit    bool
+}
+
+func newFakeGitLab(t *testing.T) *fakeGitLab {
+	t.Helper()
+
+	f := &fakeGitLab{}
+	since := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	at := func(hours int) string { return since.Add(time.Duration(hours) * time.Hour).Format(time.RFC3339) }
+
+	project := "/api/v4/projects/group%2Fsub%2Fproject"
+	responses := map[string]string{
+		project + "/merge_requests?state=merged&updated_after=2024-06-01T00%3A00%3A00Z&order_by=updated_at&sort=desc&per_page=100": fmt.Sprintf(`[
+			{"iid": 3, "author": {"username": "alice"}, "created_at": %q, "merged_at": %q}
+		]`, at(10), at(20)),
+		project + "/merge_requests?page=2": fmt.Sprintf(`[
+			{"iid": 1, "author": {"username": "bob"}, "created_at": %q, "merged_at": %q},
+			{"iid": 0, "author": {"username": "carol"}, "created_at": %q, "merged_at": %q}
+		]`, at(1), at(3), at(-50), at(-20)),
+		project + "/merge_requests/3/diffs?per_page=100": `[{"diff": "@@ -1,2 +1,3 @@\n-old\n+new\n+more\n context\n"}]`,
+		project + "/merge_requests/1/diffs?per_page=100": `[{"diff": "@@ -1 +1 @@\n-a\n+b\n"}, {"diff": "@@ -0,0 +1 @@\n+c\n"}]`,
+		project + "/merge_requests/3/approvals":          `{"approved_by": [{"user": {"username": "bob"}}, {"user": {"username": "carol"}}]}`,
+		project + "/merge_requests/1/approvals":          `{"approved_by": []}`,
+	}
+
+	f.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		if r.Header.Get("PRIVATE-TOKEN") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if f.limitHit {
+			w.Header().Set("RateLimit-Remaining", "0")
+			w.Header().Set("RateLimit-Reset", "1717243200")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		body, ok := responses[r.URL.RequestURI()]
+		if !ok {
+			t.Errorf("Unexpected request %s", r.URL.RequestURI())
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		etag := fmt.Sprintf(`W/"%x"`, len(body))
+		if r.Header.Get("If-None-Match") == etag {
+			f.revalidated++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		if r.URL.Query().Get("state") == "merged" {
+			w.Header().Set("Link", fmt.Sprintf(`<%s%s/merge_requests?page=2>; rel="next", <%s%s/merge_requests?page=2>; rel="last"`, f.server.URL, project, f.server.URL, project))
+		}
+		w.Header().Set("ETag", etag)
+		f.full++
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(f.server.Close)
+
+	return f
+}
+
+func (f *fakeGitLab) client(t *testing.T, cache *Cache) *GitLab {
+	t.Helper()
+
+	g, err := NewGitLab(f.server.URL, "group/sub/project", "secret", cache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestGitLabMergedPullRequests(t *testing.T) {
+	server := newFakeGitLab(t)
+	since := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	pullRequests, err := server.client(t, NewCache(t.TempDir())).MergedPullRequests(context.Background(), since)
+	if err != nil {
+		t.Fatalf("MergedPullRequests failed: %v", err)
+	}
+
+	// !0 was merged before since
+	if len(pullRequests) != 2 || pullRequests[0].Number != 3 || pullRequests[1].Number != 1 {
+		t.Fatalf("Expected merge requests !3 and !1, but got %+v", pullRequests)
+	}
+
+	alice := pullRequests[0]
+	if alice.Author != "alice" || alice.Additions != 2 || alice.Deletions != 1 || alice.MergedAt.Sub(alice.CreatedAt) != 10*time.Hour {
+		t.Errorf("Unexpected details for !3: %+v", alice)
+	}
+	if len(alice.Reviews) != 2 || alice.Reviews[0] != (Review{Reviewer: "bob", State: ReviewApproved}) {
+		t.Errorf("Expected approvals from bob and carol on !3, but got %+v", alice.Reviews)
+	}
+
+	bob := pullRequests[1]
+	if bob.Additions != 2 || bob.Deletions != 1 || len(bob.Reviews) != 0 {
+		t.Errorf("Expected !1 to add 2 lines over two files without approvals, but got %+v", bob)
+	}
+
+	stats := Stats(server.client(t, nil), pullRequests, since)
+	if stats.Forge != "gitlab" || len(stats.Authors) != 2 || len(stats.Reviewers) != 2 {
+		t.Errorf("Expected GitLab stats for 2 authors and 2 reviewers, but got %+v", stats)
+	}
+}
+
+func TestGitLabRevalidatesCachedResponses(t *testing.T) {
+	server := newFakeGitLab(t)
+	since := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	cache := NewCache(t.TempDir())
+
+	if _, err := server.client(t, cache).MergedPullRequests(context.Background(), since); err != nil {
+		t.Fatalf("First run failed: %v", err)
+	}
+	fetched := server.full
+
+	if _, err := server.client(t, cache).MergedPullRequests(context.Background(), since); err != nil {
+		t.Fatalf("Second run failed: %v", err)
+	}
+
+	if server.full != fetched || server.revalidated != fetched {
+		t.Errorf("Expected the second run to revalidate all %d responses, but got %d full and %d not modified", fetched, server.full-fetched, server.revalidated)
+	}
+}
+
+func TestGitLabRateLimited(t *testing.T) {
+	server := newFakeGitLab(t)
+	server.limitHit = true
+
+	_, err := server.client(t, nil).MergedPullRequests(context.Background(), time.Time{})
+
+	var limitErr *cerrors.ErrRateLimited
+	if !errors.As(err, &limitErr) || limitErr.API != "GitLab" {
+		t.Fatalf("Expected a GitLab ErrRateLimited, but got %v", err)
+	}
+	if !limitErr.Reset.Equal(time.Unix(1717243200, 0)) {
+		t.Errorf("Expected the reset time from RateLimit-Reset, but got %v", limitErr.Reset)
+	}
+}
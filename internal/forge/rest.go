@@ -0,0 +1,119 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/xeon-zolt/codecompass/internal/cerrors"
+)
+
+// restClient fetches JSON from a forge REST API. Responses are cached with
+// their ETags and revalidated with If-None-Match, and pages are followed
+// through the Link header, which GitHub and GitLab both send.
+type restClient struct {
+	api    string // Name used in errors, such as "GitHub"
+	header http.Header
+	client *http.Client
+	cache  *Cache
+}
+
+// newRESTClient returns a client that sends header, typically the token,
+// with every request.
+func newRESTClient(api string, header http.Header, cache *Cache) *restClient {
+	return &restClient{
+		api:    api,
+		header: header,
+		client: &http.Client{Timeout: 30 * time.Second},
+		cache:  cache,
+	}
+}
+
+// get fetches url into v and returns the URL of the next page, if any.
+func (c *restClient) get(ctx context.Context, url string, v interface{}) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create %s request: %w", c.api, err)
+	}
+	for key, values := range c.header {
+		req.Header[key] = values
+	}
+
+	cached, hasCached := c.cache.get(url)
+	if hasCached {
+		req.Header.Set("If-None-Match", cached.ETag)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to query %s: %w", c.api, err)
+	}
+	defer resp.Body.Close()
+
+	var entry cacheEntry
+	switch {
+	case resp.StatusCode == http.StatusNotModified && hasCached:
+		entry = *cached
+	case resp.StatusCode == http.StatusOK:
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s response: %w", c.api, err)
+		}
+		entry = cacheEntry{ETag: resp.Header.Get("ETag"), Link: resp.Header.Get("Link"), Body: body}
+		// A cache that cannot be written only costs rate limit next time
+		_ = c.cache.put(url, entry)
+	case rateLimited(resp):
+		return "", &cerrors.ErrRateLimited{API: c.api, Reset: rateLimitReset(resp.Header)}
+	default:
+		return "", fmt.Errorf("%s API returned %s for %s", c.api, resp.Status, url)
+	}
+
+	if err := json.Unmarshal(entry.Body, v); err != nil {
+		return "", fmt.Errorf("failed to decode %s response: %w", c.api, err)
+	}
+
+	return nextLink(entry.Link), nil
+}
+
+// rateLimited reports whether resp refused the request for exceeding the
+// rate limit. GitHub answers 403 with X-RateLimit-Remaining: 0 and GitLab
+// answers 429.
+func rateLimited(resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusForbidden:
+		return resp.Header.Get("X-RateLimit-Remaining") == "0" || resp.Header.Get("RateLimit-Remaining") == "0"
+	default:
+		return false
+	}
+}
+
+var nextLinkRegex = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// nextLink returns the rel="next" URL of a Link header.
+func nextLink(header string) string {
+	if match := nextLinkRegex.FindStringSubmatch(header); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+// rateLimitReset returns when the rate limit resets, from X-RateLimit-Reset
+// (GitHub) or RateLimit-Reset (GitLab).
+func rateLimitReset(header http.Header) time.Time {
+	value := header.Get("X-RateLimit-Reset")
+	if value == "" {
+		value = header.Get("RateLimit-Reset")
+	}
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(seconds, 0)
+}
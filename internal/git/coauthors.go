@@ -0,0 +1,78 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// CoAuthor is a person named by a Co-authored-by trailer of a commit, as
+// written for pair and mob programming.
+type CoAuthor struct {
+	Name  string
+	Email string
+}
+
+// CoAuthorCredit is how the commit counts credit the co-authors of a
+// commit.
+type CoAuthorCredit string
+
+const (
+	// FullCredit counts the commit once for the author and once for each
+	// co-author.
+	FullCredit CoAuthorCredit = "full"
+	// SplitCredit also counts the commit for everyone, but splits one
+	// commit's credit evenly between them.
+	SplitCredit CoAuthorCredit = "split"
+	// NoCredit counts the commit for its author alone.
+	NoCredit CoAuthorCredit = "none"
+)
+
+// ParseCoAuthorCredit parses "full", "split" or "none".
+func ParseCoAuthorCredit(value string) (CoAuthorCredit, error) {
+	switch CoAuthorCredit(value) {
+	case FullCredit, SplitCredit, NoCredit:
+		return CoAuthorCredit(value), nil
+	default:
+		return "", fmt.Errorf("invalid co-author credit %q, expected full, split or none", value)
+	}
+}
+
+// coAuthorTrailer matches a Co-authored-by trailer, in any case, capturing
+// the name and email.
+var coAuthorTrailer = regexp.MustCompile(`(?im)^co-authored-by:[ \t]*(.*?)[ \t]*<([^<>\s]+)>[ \t]*$`)
+
+// ParseCoAuthors returns the co-authors named in the Co-authored-by
+// trailers of a commit body, in order. Repeats, and trailers naming the
+// commit's own author by email, are left out.
+func ParseCoAuthors(body, authorEmail string) []CoAuthor {
+	var coAuthors []CoAuthor
+	seen := map[string]bool{strings.ToLower(authorEmail): true}
+	for _, match := range coAuthorTrailer.FindAllStringSubmatch(body, -1) {
+		key := strings.ToLower(match[2])
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		coAuthors = append(coAuthors, CoAuthor{Name: match[1], Email: match[2]})
+	}
+	return coAuthors
+}
+
+// GetCoAuthors returns the co-authors of the commits on all branches that
+// have any, by commit hash.
+func GetCoAuthors(ctx context.Context, dir string) (map[string][]CoAuthor, error) {
+	commits, err := GetCommitHistory(ctx, dir, AuthorDate)
+	if err != nil {
+		return nil, err
+	}
+
+	coAuthors := make(map[string][]CoAuthor)
+	for _, commit := range commits {
+		if names := ParseCoAuthors(commit.Body, commit.Email); len(names) > 0 {
+			coAuthors[commit.Hash] = names
+		}
+	}
+	return coAuthors, nil
+}
@@ -3,7 +3,9 @@ package git
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os/exec"
 	"regexp"
 	"strconv"
@@ -11,40 +13,294 @@ import (
 	"sync"
 	"time"
 
-	"codecompass/internal/types"
-	"codecompass/internal/utils"
+	"github.com/xeon-zolt/codecompass/internal/cerrors"
+	"github.com/xeon-zolt/codecompass/internal/types"
+	"github.com/xeon-zolt/codecompass/internal/utils"
 )
 
-var (
-	blameCache    = make(map[string]map[int]types.BlameInfo)
-	blameFailures = make(map[string]bool)
-	cacheMutex    sync.Mutex
+// Blamer runs git blame for files in a single repository and caches the
+// results, including failures, so each file is blamed at most once.
+type Blamer struct {
+	dir        string
+	semaphore  *utils.Semaphore
+	logger     *slog.Logger
+	warnings   *utils.WarningCollector
+	cache      map[string]map[int]types.BlameInfo
+	failures   map[string]bool
+	dirty      map[string]bool
+	format     BlameFormat
+	cacheMutex sync.Mutex
+}
+
+// BlameFormat is the output format git blame is asked for. Both are parsed
+// into the same blame information.
+type BlameFormat string
+
+const (
+	// BlameIncremental prints the author of each commit once, the first time
+	// one of its line ranges comes up, so output grows with the number of
+	// commits rather than with the number of lines.
+	BlameIncremental BlameFormat = "incremental"
+	// BlameLinePorcelain repeats the author of every line next to its
+	// content.
+	BlameLinePorcelain BlameFormat = "line-porcelain"
 )
 
-func ValidateRepository() error {
-	_, err := exec.Command("git", "rev-parse", "--git-dir").Output()
-	return err
+// ParseBlameFormat parses "incremental" or "line-porcelain".
+func ParseBlameFormat(value string) (BlameFormat, error) {
+	switch BlameFormat(value) {
+	case BlameIncremental, BlameLinePorcelain:
+		return BlameFormat(value), nil
+	default:
+		return "", fmt.Errorf("invalid blame format %q, expected incremental or line-porcelain", value)
+	}
+}
+
+// NewBlamer creates a Blamer for the repository in dir. Failed blames are
+// added to warnings and logged on logger, which should not also feed
+// warnings.
+func NewBlamer(dir string, semaphore *utils.Semaphore, logger *slog.Logger, warnings *utils.WarningCollector) *Blamer {
+	return &Blamer{
+		dir:       dir,
+		semaphore: semaphore,
+		logger:    logger,
+		warnings:  warnings,
+		cache:     make(map[string]map[int]types.BlameInfo),
+		failures:  make(map[string]bool),
+		dirty:     make(map[string]bool),
+		format:    BlameIncremental,
+	}
+}
+
+// SetFormat sets the output format BlameFile asks git blame for.
+func (b *Blamer) SetFormat(format BlameFormat) {
+	b.format = format
+}
+
+// command builds a git command that runs in dir and is killed when ctx is
+// done. An empty dir means the current working directory. Paths with
+// non-ASCII characters are printed as they are rather than quoted, so they
+// match the names linters and coverage reports use.
+func command(ctx context.Context, dir string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-c", "core.quotePath=false"}, args...)...)
+	cmd.Dir = dir
+	return cmd
+}
+
+// ValidateRepository checks that dir is inside a git work tree. It returns
+// cerrors.ErrNotARepo when it is not, and cerrors.ErrToolNotFound when git
+// is not installed.
+func ValidateRepository(ctx context.Context, dir string) error {
+	if err := utils.RequireTool("git"); err != nil {
+		return err
+	}
+	if _, err := command(ctx, dir, "rev-parse", "--git-dir").Output(); err != nil {
+		return fmt.Errorf("%w: %s", cerrors.ErrNotARepo, dir)
+	}
+	return nil
+}
+
+// HasCommits reports whether HEAD points at a commit, which it does not in a
+// repository that was just initialized.
+func HasCommits(ctx context.Context, dir string) (bool, error) {
+	err := command(ctx, dir, "rev-parse", "--verify", "--quiet", "HEAD").Run()
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve HEAD: %w", err)
+	}
+	return true, nil
+}
+
+// GetHead returns the commit HEAD points at.
+func GetHead(ctx context.Context, dir string) (string, error) {
+	output, err := command(ctx, dir, "rev-parse", "HEAD").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve HEAD: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetRemoteURL returns the URL of the named remote, such as origin.
+func GetRemoteURL(ctx context.Context, dir, remote string) (string, error) {
+	output, err := command(ctx, dir, "remote", "get-url", remote).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get URL of remote %s: %w", remote, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetWorkTreeState returns the HEAD commit and the uncommitted changes to
+// tracked files, which together identify the contents being analyzed.
+// Untracked files are not included.
+func GetWorkTreeState(ctx context.Context, dir string) (head string, diff []byte, err error) {
+	if head, err = GetHead(ctx, dir); err != nil {
+		return "", nil, err
+	}
+
+	diff, err = command(ctx, dir, "diff", "--binary", "HEAD").Output()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to diff the work tree: %w", err)
+	}
+	return head, diff, nil
+}
+
+// GetLatestTag returns the most recent tag reachable from HEAD, as git
+// describe finds it, such as v1.4.0. It returns cerrors.ErrNoTags when
+// there is none.
+func GetLatestTag(ctx context.Context, dir string) (string, error) {
+	output, err := command(ctx, dir, "describe", "--tags", "--abbrev=0").Output()
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return "", cerrors.ErrNoTags
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to describe HEAD: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// ResolveCommit returns the hash of the commit ref names, such as a tag.
+func ResolveCommit(ctx context.Context, dir, ref string) (string, error) {
+	// A ref starting with - would be read as an option
+	if strings.HasPrefix(ref, "-") {
+		return "", fmt.Errorf("unknown revision %q", ref)
+	}
+	output, err := command(ctx, dir, "rev-parse", "--verify", "--quiet", ref+"^{commit}").Output()
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+	if err != nil {
+		return "", fmt.Errorf("unknown revision %q", ref)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetChangedFiles returns the files changed in the work tree since the
+// commit ref names: added, modified or renamed to, including uncommitted
+// changes to tracked files. Deleted files are left out.
+func GetChangedFiles(ctx context.Context, dir, ref string) (map[string]bool, error) {
+	commit, err := ResolveCommit(ctx, dir, ref)
+	if err != nil {
+		return nil, err
+	}
+	output, err := command(ctx, dir, "diff", "-z", "--name-only", "--diff-filter=d", commit, "--").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff the work tree with %s: %w", ref, err)
+	}
+
+	files := make(map[string]bool)
+	for _, file := range strings.Split(string(output), "\x00") {
+		if file != "" {
+			files[file] = true
+		}
+	}
+	return files, nil
+}
+
+// AddWorktree checks out ref, detached, in a new linked worktree at path,
+// so its contents can be read without touching the work tree in dir. Remove
+// it with RemoveWorktree.
+func AddWorktree(ctx context.Context, dir, ref, path string) error {
+	// A ref starting with - would be read as an option
+	if strings.HasPrefix(ref, "-") || command(ctx, dir, "rev-parse", "--verify", "--quiet", ref+"^{commit}").Run() != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("unknown revision %q", ref)
+	}
+
+	output, err := command(ctx, dir, "worktree", "add", "--detach", "--quiet", path, ref).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to check out %s: %w: %s", ref, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// scpURL matches the scp-like syntax of clone URLs, such as
+// git@github.com:org/repo.git.
+var scpURL = regexp.MustCompile(`^[A-Za-z0-9._-]+@[A-Za-z0-9.-]+:`)
+
+// IsCloneURL reports whether repo is a URL to clone, such as
+// https://github.com/org/repo.git or git@github.com:org/repo.git, rather
+// than a local path.
+func IsCloneURL(repo string) bool {
+	return strings.Contains(repo, "://") || scpURL.MatchString(repo)
+}
+
+// Clone clones the repository at url into path with its full history, which
+// the leaderboards built from git history need.
+func Clone(ctx context.Context, url, path string) error {
+	// A URL starting with - would be read as an option
+	if strings.HasPrefix(url, "-") {
+		return fmt.Errorf("invalid clone URL %q", url)
+	}
+	output, err := command(ctx, "", "clone", "--quiet", url, path).CombinedOutput()
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("failed to clone %s: %w: %s", url, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// RemoveWorktree deletes the linked worktree at path, including changes
+// made in it.
+func RemoveWorktree(ctx context.Context, dir, path string) error {
+	output, err := command(ctx, dir, "worktree", "remove", "--force", path).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to remove worktree %s: %w: %s", path, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+func GetTrackedFiles(ctx context.Context, dir string) (map[string]bool, error) {
+	return listFiles(ctx, dir)
 }
 
-func GetTrackedFiles() (map[string]bool, error) {
-	cmd := exec.Command("git", "ls-files")
+// GetWorkTreeFiles returns the files in the work tree that are tracked, or
+// untracked but not ignored. It stands in for GetTrackedFiles before the
+// first commit, when files may not have been added yet.
+func GetWorkTreeFiles(ctx context.Context, dir string) (map[string]bool, error) {
+	return listFiles(ctx, dir, "--cached", "--others", "--exclude-standard")
+}
+
+// GetUntrackedFiles returns the files in the work tree that are neither
+// tracked nor ignored.
+func GetUntrackedFiles(ctx context.Context, dir string) (map[string]bool, error) {
+	return listFiles(ctx, dir, "--others", "--exclude-standard")
+}
+
+// listFiles lists files with git ls-files. Paths are NUL-terminated, so
+// names with spaces or non-ASCII characters come back as they are rather
+// than quoted, the way linters and coverage reports name them.
+func listFiles(ctx context.Context, dir string, args ...string) (map[string]bool, error) {
+	cmd := command(ctx, dir, append([]string{"ls-files", "-z"}, args...)...)
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, err
 	}
 
 	files := make(map[string]bool)
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	for _, line := range lines {
-		if line != "" {
-			files[line] = true
+	for _, file := range strings.Split(string(output), "\x00") {
+		if file != "" {
+			files[file] = true
 		}
 	}
 	return files, nil
 }
 
-func GetFileLineCount(filePath string) (int, error) {
-	cmd := exec.Command("wc", "-l", filePath)
+func GetFileLineCount(ctx context.Context, filePath string) (int, error) {
+	cmd := exec.CommandContext(ctx, "wc", "-l", filePath)
 	output, err := cmd.Output()
 	if err != nil {
 		return 0, err
@@ -58,84 +314,321 @@ func GetFileLineCount(filePath string) (int, error) {
 	return strconv.Atoi(parts[0])
 }
 
-func GetCommitHistory() ([]types.CommitInfo, error) {
-	cmd := exec.Command("git", "log", "--pretty=format:%H|%an|%ae|%at|%s", "--all")
+// DateType selects which git timestamp commit dates come from. The author
+// date is when a change was first written and survives rebases and
+// cherry-picks; the commit date is when it was last applied to a branch.
+type DateType string
+
+const (
+	AuthorDate DateType = "author"
+	CommitDate DateType = "commit"
+)
+
+// ParseDateType parses "author" or "commit".
+func ParseDateType(value string) (DateType, error) {
+	switch DateType(value) {
+	case AuthorDate, CommitDate:
+		return DateType(value), nil
+	default:
+		return "", fmt.Errorf("invalid date type %q, expected author or commit", value)
+	}
+}
+
+// timestampFormat returns the git log placeholder for the Unix timestamp of
+// dateType. An empty dateType means the author date.
+func (d DateType) timestampFormat() string {
+	if d == CommitDate {
+		return "%ct"
+	}
+	return "%at"
+}
+
+// GetHeadTime returns the commit date of HEAD.
+func GetHeadTime(ctx context.Context, dir string) (time.Time, error) {
+	return GetCommitTime(ctx, dir, "HEAD")
+}
+
+// GetCommitTime returns the commit date of the commit ref names.
+func GetCommitTime(ctx context.Context, dir, ref string) (time.Time, error) {
+	output, err := command(ctx, dir, "log", "-1", "--format=%ct", ref, "--").Output()
+	if err != nil {
+		return time.Time{}, err
+	}
+	seconds, err := strconv.ParseInt(strings.TrimSpace(string(output)), 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid commit date %q", output)
+	}
+	return time.Unix(seconds, 0), nil
+}
+
+// GetFileCreationDates returns when each file in the history of HEAD was
+// added, following renames, so a renamed file keeps the date it was added
+// under its old path. A file deleted and added again dates from when it
+// was added again.
+func GetFileCreationDates(ctx context.Context, dir string, dateType DateType) (map[string]time.Time, error) {
+	// Oldest first, so a rename carries the date of a file already seen.
+	// Dates are on lines of their own, marked by the record separator.
+	cmd := command(ctx, dir, "log", "--reverse", "-M", "--diff-filter=AR", "--name-status", "--pretty=format:%x1e"+dateType.timestampFormat())
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	created := make(map[string]time.Time)
+	var date time.Time
+	for _, line := range strings.Split(string(output), "\n") {
+		if timestamp, ok := strings.CutPrefix(line, "\x1e"); ok {
+			seconds, err := strconv.ParseInt(timestamp, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid commit date %q", timestamp)
+			}
+			date = time.Unix(seconds, 0)
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		switch {
+		case len(fields) == 2 && fields[0] == "A":
+			created[fields[1]] = date
+		case len(fields) == 3 && strings.HasPrefix(fields[0], "R"):
+			if first, ok := created[fields[1]]; ok {
+				created[fields[2]] = first
+			} else {
+				created[fields[2]] = date
+			}
+		}
+	}
+	return created, nil
+}
+
+// GetCommitHistory returns the commits of revs, such as v1.4.0..HEAD, or
+// on all branches without any, newest first, with their subjects and
+// bodies.
+func GetCommitHistory(ctx context.Context, dir string, dateType DateType, revs ...string) ([]types.CommitInfo, error) {
+	if len(revs) == 0 {
+		revs = []string{"--all"}
+	}
+	// Fields are separated by the ASCII unit separator and commits by the
+	// record separator, since names, subjects and bodies may hold | and
+	// newlines
+	args := append([]string{"log", "--pretty=format:%H%x1f%P%x1f%an%x1f%ae%x1f" + dateType.timestampFormat() + "%x1f%s%x1f%b%x1e"}, revs...)
+	cmd := command(ctx, dir, append(args, "--")...)
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, err
 	}
 
 	var commits []types.CommitInfo
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
 
-	for _, line := range lines {
-		if line == "" {
+	for _, record := range strings.Split(string(output), "\x1e") {
+		record = strings.TrimLeft(record, "\n")
+		if record == "" {
 			continue
 		}
 
-		parts := strings.Split(line, "|")
-		if len(parts) < 5 {
+		parts := strings.Split(record, "\x1f")
+		if len(parts) < 7 {
 			continue
 		}
 
-		timestamp, err := strconv.ParseInt(parts[3], 10, 64)
+		timestamp, err := strconv.ParseInt(parts[4], 10, 64)
 		if err != nil {
 			continue
 		}
 
 		commits = append(commits, types.CommitInfo{
 			Hash:    parts[0],
-			Author:  parts[1],
-			Email:   parts[2],
+			Author:  parts[2],
+			Email:   parts[3],
 			Date:    time.Unix(timestamp, 0),
-			Message: parts[4],
+			Message: parts[5],
+			Body:    strings.TrimRight(parts[6], "\n"),
+			Merge:   len(strings.Fields(parts[1])) > 1,
 		})
 	}
 
 	return commits, nil
 }
 
-func GetAuthorCommitCounts() (map[string]types.CommitCountEntry, error) {
-	commits, err := GetCommitHistory()
+// GetAuthorDates returns the non-merge commits on all branches with their
+// author dates in the author's own UTC offset. Merges are left out because
+// those made through a forge's web interface carry the forge's offset.
+func GetAuthorDates(ctx context.Context, dir string) ([]types.CommitInfo, error) {
+	output, err := command(ctx, dir, "log", "--all", "--no-merges", "--date=iso-strict", "--pretty=format:%H|%an|%ae|%ad").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read author dates: %w", err)
+	}
+
+	var commits []types.CommitInfo
+	for _, line := range strings.Split(string(output), "\n") {
+		// Names may contain |, but hashes, emails and dates do not
+		hash, rest, ok := strings.Cut(line, "|")
+		nameAndEmail, date, found := cutLast(rest, "|")
+		name, email, split := cutLast(nameAndEmail, "|")
+		if !ok || !found || !split {
+			continue
+		}
+
+		authored, err := time.Parse(time.RFC3339, date)
+		if err != nil {
+			continue
+		}
+		commits = append(commits, types.CommitInfo{Hash: hash, Author: name, Email: email, Date: authored})
+	}
+	return commits, nil
+}
+
+// GetCommitLineChanges returns the non-merge commits on HEAD committed at
+// or after since, or all of them when since is zero, newest first, with the lines they changed in each file.
+// Renames are detected, so a file moved without changes has no lines.
+func GetCommitLineChanges(ctx context.Context, dir string, dateType DateType, since time.Time) ([]types.CommitInfo, error) {
+	// Paths are NUL-terminated, as they may hold tabs, and renames give the
+	// old and new path in two fields of their own
+	args := []string{"log", "--no-merges", "-M", "--numstat", "-z", "--pretty=format:%H%x1f%an%x1f%ae%x1f" + dateType.timestampFormat() + "%x1f%s"}
+	if !since.IsZero() {
+		args = append(args, "--since="+since.Format(time.RFC3339))
+	}
+	output, err := command(ctx, dir, args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read line changes: %w", err)
+	}
+	return parseLineChanges(string(output)), nil
+}
+
+// parseLineChanges parses the output of git log --numstat -z. A commit's
+// header runs up to a newline, followed by its first file.
+func parseLineChanges(output string) []types.CommitInfo {
+	var commits []types.CommitInfo
+	// current is the index of the commit the files are of, -1 while in one
+	// whose header could not be read
+	current := -1
+	fields := strings.Split(output, "\x00")
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+		if strings.Contains(field, "\x1f") {
+			header, first, _ := strings.Cut(field, "\n")
+			field, current = first, -1
+			parts := strings.Split(header, "\x1f")
+			if len(parts) != 5 {
+				continue
+			}
+			timestamp, err := strconv.ParseInt(parts[3], 10, 64)
+			if err != nil {
+				continue
+			}
+			commits = append(commits, types.CommitInfo{Hash: parts[0], Author: parts[1], Email: parts[2], Date: time.Unix(timestamp, 0), Message: parts[4]})
+			current = len(commits) - 1
+		}
+
+		counts := strings.SplitN(field, "\t", 3)
+		if len(counts) != 3 {
+			continue
+		}
+		change := types.FileLineChange{Path: counts[2], Binary: counts[0] == "-"}
+		change.Added, _ = strconv.Atoi(counts[0])
+		change.Deleted, _ = strconv.Atoi(counts[1])
+		if change.Path == "" && i+2 < len(fields) {
+			change.OldPath, change.Path = fields[i+1], fields[i+2]
+			i += 2
+		}
+		if current >= 0 {
+			commits[current].Files = append(commits[current].Files, change)
+		}
+	}
+	return commits
+}
+
+// cutLast slices s around the last instance of sep.
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
+
+// GetAuthorCommitCounts counts the commits of each author on all branches,
+// or in revs when given, by email. credit says how the co-authors of a commit, named by its
+// Co-authored-by trailers, are credited: a co-author counts the commit as an
+// author does, and with SplitCredit, Credit has each person's share of it.
+// Co-authors are matched to authors by email whatever its case.
+func GetAuthorCommitCounts(ctx context.Context, dir string, dateType DateType, credit CoAuthorCredit, revs ...string) (map[string]types.CommitCountEntry, error) {
+	commits, err := GetCommitHistory(ctx, dir, dateType, revs...)
 	if err != nil {
 		return nil, err
 	}
 
 	authorStats := make(map[string]types.CommitCountEntry)
-
-	for _, commit := range commits {
-		entry, exists := authorStats[commit.Email]
+	count := func(email, name string, date time.Time, share float64) types.CommitCountEntry {
+		entry, exists := authorStats[email]
 		if !exists {
 			entry = types.CommitCountEntry{
-				Name:        commit.Author,
-				Email:       commit.Email,
+				Name:        name,
+				Email:       email,
 				Commits:     0,
-				FirstCommit: commit.Date,
-				LastCommit:  commit.Date,
+				FirstCommit: date,
+				LastCommit:  date,
 			}
 		}
 
 		entry.Commits++
+		if credit == SplitCredit {
+			entry.Credit += share
+		}
 
-		if commit.Date.Before(entry.FirstCommit) {
-			entry.FirstCommit = commit.Date
+		if date.Before(entry.FirstCommit) {
+			entry.FirstCommit = date
 		}
-		if commit.Date.After(entry.LastCommit) {
-			entry.LastCommit = commit.Date
+		if date.After(entry.LastCommit) {
+			entry.LastCommit = date
 		}
+		return entry
+	}
 
+	for _, commit := range commits {
+		share := 1.0
+		if credit == SplitCredit {
+			share /= float64(len(ParseCoAuthors(commit.Body, commit.Email)) + 1)
+		}
+		entry := count(commit.Email, commit.Author, commit.Date, share)
 		entry.Name = commit.Author // Update to latest name
 		authorStats[commit.Email] = entry
 	}
 
+	if credit == NoCredit {
+		return authorStats, nil
+	}
+
+	// Co-authors are credited once every author is known, so they join
+	// the entry of their own commits
+	emails := make(map[string]string)
+	for email := range authorStats {
+		emails[strings.ToLower(email)] = email
+	}
+	for _, commit := range commits {
+		coAuthors := ParseCoAuthors(commit.Body, commit.Email)
+		for _, coAuthor := range coAuthors {
+			email, known := emails[strings.ToLower(coAuthor.Email)]
+			if !known {
+				email = coAuthor.Email
+				emails[strings.ToLower(email)] = email
+			}
+			entry := count(email, coAuthor.Name, commit.Date, 1/float64(len(coAuthors)+1))
+			entry.CoAuthored++
+			authorStats[email] = entry
+		}
+	}
+
 	return authorStats, nil
 }
 
-func GetRecentContributors(days int) (map[string]types.RecentContributorEntry, error) {
+// GetRecentContributors counts commits from the last days days. git selects
+// commits by commit date, so with AuthorDate a rebased commit is included
+// even when it was written earlier.
+func GetRecentContributors(ctx context.Context, dir string, days int, dateType DateType) (map[string]types.RecentContributorEntry, error) {
 	since := time.Now().AddDate(0, 0, -days)
 	sinceStr := since.Format("2006-01-02")
 
-	cmd := exec.Command("git", "log", "--since="+sinceStr, "--pretty=format:%an|%ae|%at")
+	cmd := command(ctx, dir, "log", "--since="+sinceStr, "--pretty=format:%an|%ae|"+dateType.timestampFormat())
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, err
@@ -185,86 +678,211 @@ func GetRecentContributors(days int) (map[string]types.RecentContributorEntry, e
 	return authorStats, nil
 }
 
-func BlameFile(filePath string, warningLogs *[]string, mu *sync.Mutex, semaphore *utils.Semaphore) (map[int]types.BlameInfo, error) {
-	cacheMutex.Lock()
-	if blameMap, exists := blameCache[filePath]; exists {
-		cacheMutex.Unlock()
+// GetMainlineMerges returns the merge commits on the first-parent history
+// of HEAD committed at or after since, newest first, with the branch each
+// one merged. The branch is every commit reachable from the second parent
+// but not the first (git rev-list merge^2 --not merge^1). Merges that
+// brought in no new commits are skipped.
+func GetMainlineMerges(ctx context.Context, dir string, since time.Time) ([]types.MergeInfo, error) {
+	args := []string{"log", "--first-parent", "--merges", "--pretty=format:%H|%P|%ct"}
+	if !since.IsZero() {
+		args = append(args, fmt.Sprintf("--since=@%d", since.Unix()))
+	}
+	output, err := command(ctx, dir, append(args, "HEAD")...).Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var merges []types.MergeInfo
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		parts := strings.Split(line, "|")
+		if len(parts) < 3 {
+			continue
+		}
+		parents := strings.Fields(parts[1])
+		timestamp, err := strconv.ParseInt(parts[2], 10, 64)
+		if len(parents) < 2 || err != nil {
+			continue
+		}
+
+		merge := types.MergeInfo{Hash: parts[0], MergedAt: time.Unix(timestamp, 0)}
+		if err := branchCommits(ctx, dir, parents[0], parents[1], &merge); err != nil {
+			return nil, err
+		}
+		if merge.Commits > 0 {
+			merges = append(merges, merge)
+		}
+	}
+
+	return merges, nil
+}
+
+// branchCommits fills in the commits reachable from branch but not from
+// mainline, attributing merge to the author of the oldest one.
+func branchCommits(ctx context.Context, dir, mainline, branch string, merge *types.MergeInfo) error {
+	output, err := command(ctx, dir, "log", "--pretty=format:%at|%an|%ae", branch, "--not", mainline).Output()
+	if err != nil {
+		return err
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		parts := strings.SplitN(line, "|", 3)
+		if len(parts) < 3 {
+			continue
+		}
+		timestamp, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		authored := time.Unix(timestamp, 0)
+		merge.Commits++
+		if merge.Commits == 1 || authored.Before(merge.FirstCommitAt) {
+			merge.FirstCommitAt = authored
+			merge.Author = parts[1]
+			merge.Email = parts[2]
+		}
+	}
+	return nil
+}
+
+// Skip makes BlameFile return no blame information for paths without
+// running git blame, for files git has no history of.
+func (b *Blamer) Skip(paths ...string) {
+	b.cacheMutex.Lock()
+	defer b.cacheMutex.Unlock()
+	for _, path := range paths {
+		b.cache[path] = make(map[int]types.BlameInfo)
+	}
+}
+
+// MarkDirty makes BlameFile blame the lines of paths that differ from HEAD
+// on UncommittedAuthor, for files with uncommitted changes.
+func (b *Blamer) MarkDirty(paths ...string) {
+	b.cacheMutex.Lock()
+	defer b.cacheMutex.Unlock()
+	for _, path := range paths {
+		b.dirty[path] = true
+	}
+}
+
+func (b *Blamer) BlameFile(ctx context.Context, filePath string) (map[int]types.BlameInfo, error) {
+	b.cacheMutex.Lock()
+	if blameMap, exists := b.cache[filePath]; exists {
+		b.cacheMutex.Unlock()
 		return blameMap, nil
 	}
-	if blameFailures[filePath] {
-		cacheMutex.Unlock()
+	if b.failures[filePath] {
+		b.cacheMutex.Unlock()
 		return make(map[int]types.BlameInfo), fmt.Errorf("file already failed")
 	}
-	cacheMutex.Unlock()
+	dirty := b.dirty[filePath]
+	b.cacheMutex.Unlock()
 
-	semaphore.Acquire()
-	defer semaphore.Release()
+	b.semaphore.Acquire()
+	defer b.semaphore.Release()
 
 	normalizedPath := strings.ReplaceAll(filePath, "\\", "/")
 	time.Sleep(50 * time.Millisecond)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
-	cmd := exec.CommandContext(ctx, "git", "blame", "--line-porcelain", "--", normalizedPath)
+	cmd := command(ctx, b.dir, "blame", "--"+string(b.format), "--", normalizedPath)
 	output, err := cmd.Output()
 	if err != nil {
-		cacheMutex.Lock()
-		blameFailures[filePath] = true
-		cacheMutex.Unlock()
+		b.cacheMutex.Lock()
+		b.failures[filePath] = true
+		b.cacheMutex.Unlock()
 
-		mu.Lock()
-		*warningLogs = append(*warningLogs, fmt.Sprintf("⚠️ Blame failed for %s: %v", filePath, err))
-		mu.Unlock()
+		b.warnings.Add(types.NewWarning("Blame failed", "file", filePath, "error", err))
+		b.logger.Warn("Blame failed", "phase", "blame", "file", filePath, "error", err)
 
 		return make(map[int]types.BlameInfo), err
 	}
 
 	blameMap := parseBlameOutput(string(output))
 
-	cacheMutex.Lock()
-	blameCache[filePath] = blameMap
-	cacheMutex.Unlock()
+	// Whoever committed the lines an uncommitted change replaced did not
+	// write what the linters saw
+	if dirty {
+		uncommitted, err := GetUncommittedLines(ctx, b.dir, normalizedPath)
+		if err != nil {
+			b.warnings.Add(types.NewWarning("Diff failed", "file", filePath, "error", err))
+			b.logger.Warn("Diff failed", "phase", "blame", "file", filePath, "error", err)
+		}
+		for line := range uncommitted {
+			blameMap[line] = UncommittedAuthor
+		}
+	}
+
+	b.cacheMutex.Lock()
+	b.cache[filePath] = blameMap
+	b.cacheMutex.Unlock()
 
 	return blameMap, nil
 }
 
+// parseBlameOutput parses the output of git blame --incremental, --porcelain
+// or --line-porcelain. Each starts a range of lines with the commit that
+// last changed them, its line in that commit, its line in the file and, at
+// least the first time, how many lines follow, and ends the first header of
+// each range with the filename. Commit details such as the author are only
+// printed the first time a commit comes up, except with --line-porcelain, so
+// they are kept per commit.
 func parseBlameOutput(output string) map[int]types.BlameInfo {
 	blameMap := make(map[int]types.BlameInfo)
 	scanner := bufio.NewScanner(strings.NewReader(output))
+	// A line of blame output is never longer than the output itself
+	scanner.Buffer(nil, max(len(output)+1, bufio.MaxScanTokenSize))
 
-	var currentEmail, currentName string
-	var currentLine int
-	commitRegex := regexp.MustCompile(`^[0-9a-f]{40} `)
+	commits := make(map[string]*types.BlameInfo)
+	var current *types.BlameInfo
+	var start, count int
 
 	for scanner.Scan() {
 		line := scanner.Text()
 
-		if commitRegex.MatchString(line) {
+		if blameHeader.MatchString(line) {
 			parts := strings.Fields(line)
-			if len(parts) >= 3 {
-				if lineNum, err := strconv.Atoi(parts[2]); err == nil {
-					currentLine = lineNum
+			current = commits[parts[0]]
+			if current == nil {
+				current = &types.BlameInfo{}
+				commits[parts[0]] = current
+			}
+			start, count = 0, 1
+			if lineNum, err := strconv.Atoi(parts[2]); err == nil {
+				start = lineNum
+			}
+			if len(parts) > 3 {
+				if lines, err := strconv.Atoi(parts[3]); err == nil {
+					count = lines
 				}
 			}
+		} else if current == nil || strings.HasPrefix(line, "\t") {
+			// The content of a line, printed by the porcelain formats only
+			continue
 		} else if strings.HasPrefix(line, "author ") {
-			currentName = strings.TrimSpace(strings.TrimPrefix(line, "author "))
+			current.Name = strings.TrimSpace(strings.TrimPrefix(line, "author "))
 		} else if strings.HasPrefix(line, "author-mail ") {
 			email := strings.TrimPrefix(line, "author-mail ")
 			email = strings.Trim(email, "<>")
-			currentEmail = strings.TrimSpace(email)
-		} else if strings.HasPrefix(line, "\t") {
-			if currentEmail != "" && currentLine > 0 {
-				blameMap[currentLine] = types.BlameInfo{
-					Email: currentEmail,
-					Name:  currentName,
+			current.Email = strings.TrimSpace(email)
+		} else if strings.HasPrefix(line, "author-time ") {
+			if seconds, err := strconv.ParseInt(strings.TrimPrefix(line, "author-time "), 10, 64); err == nil {
+				current.Time = time.Unix(seconds, 0)
+			}
+		} else if strings.HasPrefix(line, "filename ") {
+			if current.Email != "" && start > 0 {
+				for lineNum := start; lineNum < start+count; lineNum++ {
+					blameMap[lineNum] = *current
 				}
 			}
-			currentEmail = ""
-			currentName = ""
-			currentLine = 0
 		}
 	}
 
 	return blameMap
 }
+
+// blameHeader matches the header of a range of lines in git blame output.
+var blameHeader = regexp.MustCompile(`^[0-9a-f]{40,64} \d+ \d+`)
@@ -1,111 +1,786 @@
 package git
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"os"
-	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
-	
+	"time"
+
+	"github.com/xeon-zolt/codecompass/internal/cerrors"
+	"github.com/xeon-zolt/codecompass/internal/logging"
+	"github.com/xeon-zolt/codecompass/internal/testutil"
+	"github.com/xeon-zolt/codecompass/internal/types"
+	"github.com/xeon-zolt/codecompass/internal/utils"
 )
 
 func TestGetTrackedFiles(t *testing.T) {
-	// Create a temporary directory
-	tmpdir, err := os.MkdirTemp("", "git_test")
+	dir := testutil.NewRepo(t).
+		Commit("initial commit", map[string]string{"test.go": "test file"}).
+		Dir()
+
+	files, err := GetTrackedFiles(context.Background(), dir)
 	if err != nil {
 		t.Fatal(err)
 	}
-	defer os.RemoveAll(tmpdir)
 
-	// Change to the temporary directory
-	oldwd, err := os.Getwd()
+	if len(files) != 1 {
+		t.Fatalf("Expected 1 tracked file, but got %d", len(files))
+	}
+
+	if !files["test.go"] {
+		t.Errorf("Expected test.go to be a tracked file")
+	}
+}
+
+func TestGetTrackedFilesSpecialNames(t *testing.T) {
+	names := []string{"docs/café.md", "src/my file.js", " leading.txt"}
+	files := make(map[string]string)
+	for _, name := range names {
+		files[name] = "content"
+	}
+	dir := testutil.NewRepo(t).Commit("initial commit", files).Dir()
+
+	tracked, err := GetTrackedFiles(context.Background(), dir)
 	if err != nil {
 		t.Fatal(err)
 	}
-	defer os.Chdir(oldwd)
-	os.Chdir(tmpdir)
+	// Names come back as they are, not quoted or trimmed
+	for _, name := range names {
+		if !tracked[name] {
+			t.Errorf("Expected %q to be tracked, but got %v", name, tracked)
+		}
+	}
+}
 
-	// Initialize a git repository
-	if err := exec.Command("git", "init").Run(); err != nil {
-		t.Fatal(err)
+func TestGetLatestTag(t *testing.T) {
+	repo := testutil.NewRepo(t).Commit("initial commit", map[string]string{"a.go": "package a\n"})
+	if _, err := GetLatestTag(context.Background(), repo.Dir()); !errors.Is(err, cerrors.ErrNoTags) {
+		t.Errorf("Expected ErrNoTags without tags, but got %v", err)
 	}
 
-	// Create a file and commit it
-	content := []byte("test file")
-	if err := os.WriteFile("test.go", content, 0644); err != nil {
+	repo.Git("tag", "v1.0.0")
+	repo.Commit("second commit", map[string]string{"b.go": "package b\n"})
+	repo.Git("tag", "-a", "-m", "release", "v1.1.0")
+	repo.Commit("third commit", map[string]string{"c.go": "package c\n"})
+
+	tag, err := GetLatestTag(context.Background(), repo.Dir())
+	if err != nil {
 		t.Fatal(err)
 	}
+	if tag != "v1.1.0" {
+		t.Errorf("Expected v1.1.0, but got %q", tag)
+	}
+}
 
-	if err := exec.Command("git", "add", "test.go").Run(); err != nil {
+func TestGetChangedFiles(t *testing.T) {
+	repo := testutil.NewRepo(t).
+		Commit("initial commit", map[string]string{"a.go": "package a\n", "b.go": "package b\n", "old.go": "package old\n"})
+	repo.Git("tag", "v1.0.0")
+	repo.Commit("after the tag", map[string]string{"b.go": "package b\n\n// changed\n", "c.go": "package c\n"})
+	repo.Git("rm", "--quiet", "old.go")
+	repo.Commit("remove old.go", nil)
+	repo.Write(map[string]string{"a.go": "package a\n\n// uncommitted\n"})
+
+	files, err := GetChangedFiles(context.Background(), repo.Dir(), "v1.0.0")
+	if err != nil {
 		t.Fatal(err)
 	}
+	// Deleted files are left out, uncommitted changes count
+	expected := map[string]bool{"a.go": true, "b.go": true, "c.go": true}
+	if !reflect.DeepEqual(files, expected) {
+		t.Errorf("Expected %v, but got %v", expected, files)
+	}
+
+	if _, err := GetChangedFiles(context.Background(), repo.Dir(), "--output=x"); err == nil {
+		t.Error("Expected an option-like ref to be rejected")
+	}
+	if _, err := GetChangedFiles(context.Background(), repo.Dir(), "v9.9.9"); err == nil {
+		t.Error("Expected an unknown ref to fail")
+	}
+}
+
+func TestRepositoryWithoutCommits(t *testing.T) {
+	repo := testutil.NewRepo(t).Write(map[string]string{"main.go": "package main\n", ".gitignore": "build/\n", "build/out": "binary"})
+	repo.Git("add", "main.go")
+
+	if has, err := HasCommits(context.Background(), repo.Dir()); err != nil || has {
+		t.Errorf("Expected a new repository to have no commits, but got %v (error %v)", has, err)
+	}
 
-	if err := exec.Command("git", "commit", "-m", "initial commit").Run(); err != nil {
+	tracked, err := GetTrackedFiles(context.Background(), repo.Dir())
+	if err != nil {
 		t.Fatal(err)
 	}
+	if len(tracked) != 1 || !tracked["main.go"] {
+		t.Errorf("Expected only the staged file to be tracked, but got %v", tracked)
+	}
 
-	// Get the tracked files
-	files, err := GetTrackedFiles()
+	// Untracked files count before the first commit, ignored ones do not
+	files, err := GetWorkTreeFiles(context.Background(), repo.Dir())
 	if err != nil {
 		t.Fatal(err)
 	}
+	if len(files) != 2 || !files["main.go"] || !files[".gitignore"] {
+		t.Errorf("Expected main.go and .gitignore in the work tree, but got %v", files)
+	}
 
-	// Check the results
-	if len(files) != 1 {
-		t.Fatalf("Expected 1 tracked file, but got %d", len(files))
+	repo.Commit("initial commit", nil)
+	if has, err := HasCommits(context.Background(), repo.Dir()); err != nil || !has {
+		t.Errorf("Expected the repository to have a commit, but got %v (error %v)", has, err)
 	}
+}
 
-	if !files["test.go"] {
-		t.Errorf("Expected test.go to be a tracked file")
+func TestGetUntrackedFiles(t *testing.T) {
+	repo := testutil.NewRepo(t).
+		Commit("initial commit", map[string]string{"main.go": "package main\n", ".gitignore": "build/\n"}).
+		Write(map[string]string{"draft.go": "package main\n", "build/out": "binary", "main.go": "package main\n\n// changed\n"})
+
+	files, err := GetUntrackedFiles(context.Background(), repo.Dir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Modified tracked files and ignored files are not untracked
+	if len(files) != 1 || !files["draft.go"] {
+		t.Errorf("Expected only draft.go to be untracked, but got %v", files)
 	}
 }
 
 func TestGetCommitHistory(t *testing.T) {
-	// Create a temporary directory
-	tmpdir, err := os.MkdirTemp("", "git_test")
+	dir := testutil.NewRepo(t).
+		WithAuthor("Alice", "alice@example.com").
+		Commit("initial commit", map[string]string{"test.go": "test file"}).
+		WithAuthor("Bob | Ops", "bob@example.com").
+		Commit("fix: second | commit\n\nFirst paragraph.\n\nRefs: #12", map[string]string{"test.go": "changed"}).
+		Dir()
+
+	commits, err := GetCommitHistory(context.Background(), dir, AuthorDate)
 	if err != nil {
 		t.Fatal(err)
 	}
-	defer os.RemoveAll(tmpdir)
 
-	// Change to the temporary directory
-	oldwd, err := os.Getwd()
+	if len(commits) != 2 {
+		t.Fatalf("Expected 2 commits, but got %d", len(commits))
+	}
+
+	// Newest first
+	if commits[0].Message != "fix: second | commit" || commits[0].Author != "Bob | Ops" {
+		t.Errorf("Expected Bob's second commit first, but got %+v", commits[0])
+	}
+	if commits[0].Body != "First paragraph.\n\nRefs: #12" {
+		t.Errorf("Expected the body of the second commit, but got %q", commits[0].Body)
+	}
+	if commits[1].Body != "" || commits[1].Merge {
+		t.Errorf("Expected the first commit to have no body and one parent, but got %+v", commits[1])
+	}
+	if !commits[1].Date.Equal(testutil.StartDate) {
+		t.Errorf("Expected the first commit at %v, but got %v", testutil.StartDate, commits[1].Date)
+	}
+}
+
+func TestGetCommitHistoryMerges(t *testing.T) {
+	dir := testutil.NewRepo(t).
+		Commit("initial commit", map[string]string{"a.go": "a"}).
+		Branch("feature").
+		Commit("feature commit", map[string]string{"b.go": "b"}).
+		Checkout("main").
+		Merge("feature", "Merge branch 'feature'").
+		Dir()
+
+	commits, err := GetCommitHistory(context.Background(), dir, AuthorDate)
 	if err != nil {
 		t.Fatal(err)
 	}
-	defer os.Chdir(oldwd)
-	os.Chdir(tmpdir)
 
-	// Initialize a git repository
-	if err := exec.Command("git", "init").Run(); err != nil {
+	merges := 0
+	for _, commit := range commits {
+		if commit.Merge {
+			merges++
+			if commit.Message != "Merge branch 'feature'" {
+				t.Errorf("Expected only the merge commit to be a merge, but got %+v", commit)
+			}
+		}
+	}
+	if len(commits) != 3 || merges != 1 {
+		t.Errorf("Expected 3 commits with 1 merge, but got %+v", commits)
+	}
+}
+
+func TestGetAuthorDates(t *testing.T) {
+	india := time.FixedZone("IST", 5*3600+1800)
+	repo := testutil.NewRepo(t).
+		WithAuthor("Priya | Ops", "priya@example.com").
+		At(time.Date(2024, 1, 1, 9, 30, 0, 0, india)).
+		Commit("initial commit", map[string]string{"test.go": "test file"}).
+		Branch("feature").
+		Commit("feature", map[string]string{"feature.go": "feature"}).
+		Checkout("main").
+		Merge("feature", "merge feature")
+
+	commits, err := GetAuthorDates(context.Background(), repo.Dir())
+	if err != nil {
 		t.Fatal(err)
 	}
 
-	// Create a file and commit it
-	content := []byte("test file")
-	if err := os.WriteFile("test.go", content, 0644); err != nil {
+	// The merge is left out
+	if len(commits) != 2 {
+		t.Fatalf("Expected 2 non-merge commits, but got %+v", commits)
+	}
+	first := commits[1]
+	if first.Author != "Priya | Ops" || first.Email != "priya@example.com" || first.Hash == "" {
+		t.Errorf("Expected Priya's first commit, but got %+v", first)
+	}
+	if _, offset := first.Date.Zone(); offset != 5*3600+1800 || first.Date.Hour() != 9 || first.Date.Minute() != 30 {
+		t.Errorf("Expected 09:30 in UTC+05:30, but got %v", first.Date)
+	}
+}
+
+func TestGetCommitLineChanges(t *testing.T) {
+	repo := testutil.NewRepo(t).
+		WithAuthor("Alice", "alice@example.com").
+		At(time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)).
+		Commit("add", map[string]string{"a.go": "1\n2\n3\n4\n", "logo.png": "\x00\x01"}).
+		Commit("empty", nil)
+	repo.Git("mv", "a.go", "a\tmoved.go")
+	repo.Commit("move", nil).
+		Commit("trim", map[string]string{"a\tmoved.go": "1\n"})
+
+	commits, err := GetCommitLineChanges(context.Background(), repo.Dir(), CommitDate, time.Date(2024, 1, 1, 9, 30, 0, 0, time.UTC))
+	if err != nil {
 		t.Fatal(err)
 	}
 
-	if err := exec.Command("git", "add", "test.go").Run(); err != nil {
+	// The first commit is before the window
+	if len(commits) != 3 {
+		t.Fatalf("Expected 3 commits, but got %+v", commits)
+	}
+	if trim := commits[0]; trim.Message != "trim" || trim.Author != "Alice" || trim.Email != "alice@example.com" ||
+		!reflect.DeepEqual(trim.Files, []types.FileLineChange{{Path: "a\tmoved.go", Deleted: 3}}) {
+		t.Errorf("Expected the trim commit to delete 3 lines, but got %+v", trim)
+	}
+	if move := commits[1]; !reflect.DeepEqual(move.Files, []types.FileLineChange{{Path: "a\tmoved.go", OldPath: "a.go"}}) {
+		t.Errorf("Expected the move commit to rename a.go without changing lines, but got %+v", move)
+	}
+	if empty := commits[2]; empty.Message != "empty" || len(empty.Files) != 0 {
+		t.Errorf("Expected the empty commit without files, but got %+v", empty)
+	}
+
+	commits, err = GetCommitLineChanges(context.Background(), repo.Dir(), CommitDate, time.Time{})
+	if err != nil {
 		t.Fatal(err)
 	}
+	if add := commits[len(commits)-1]; len(add.Files) != 2 || add.Files[0].Added != 4 || !add.Files[1].Binary {
+		t.Errorf("Expected the add commit with 4 lines and a binary file, but got %+v", add)
+	}
+}
+
+func TestGetAuthorCommitCounts(t *testing.T) {
+	dir := testutil.NewRepo(t).
+		WithAuthor("Alice", "alice@example.com").
+		Commit("one", map[string]string{"a.go": "1"}).
+		Branch("feature").
+		Commit("two", map[string]string{"a.go": "2"}).
+		Checkout("main").
+		WithAuthor("Bob", "bob@example.com").
+		Merge("feature", "merge feature").
+		Dir()
 
-	if err := exec.Command("git", "commit", "-m", "initial commit").Run(); err != nil {
+	counts, err := GetAuthorCommitCounts(context.Background(), dir, AuthorDate, FullCredit)
+	if err != nil {
 		t.Fatal(err)
 	}
 
-	// Get the commit history
-	commits, err := GetCommitHistory()
+	alice := counts["alice@example.com"]
+	if alice.Commits != 2 || !alice.FirstCommit.Equal(testutil.StartDate) || !alice.LastCommit.Equal(testutil.StartDate.Add(time.Hour)) {
+		t.Errorf("Unexpected commit counts for Alice: %+v", alice)
+	}
+	if counts["bob@example.com"].Commits != 1 {
+		t.Errorf("Expected Bob's merge commit to be counted, but got %+v", counts["bob@example.com"])
+	}
+}
+
+func TestGetAuthorCommitCountsCoAuthors(t *testing.T) {
+	dir := testutil.NewRepo(t).
+		WithAuthor("Alice", "alice@example.com").
+		Commit("solo", map[string]string{"a.go": "1"}).
+		Commit("pair\n\nMobbed on the parser.\n\nCo-authored-by: Bob <BOB@example.com>\nco-authored-by: Carol Ops <carol@example.com>\nCo-authored-by: Alice <alice@example.com>",
+			map[string]string{"a.go": "2"}).
+		WithAuthor("Bob", "bob@example.com").
+		Commit("bob's own", map[string]string{"b.go": "1"}).
+		Dir()
+
+	coAuthors, err := GetCoAuthors(context.Background(), dir)
 	if err != nil {
 		t.Fatal(err)
 	}
+	if len(coAuthors) != 1 {
+		t.Fatalf("Expected one commit with co-authors, but got %v", coAuthors)
+	}
+	for _, names := range coAuthors {
+		expected := []CoAuthor{{Name: "Bob", Email: "BOB@example.com"}, {Name: "Carol Ops", Email: "carol@example.com"}}
+		if !reflect.DeepEqual(names, expected) {
+			t.Errorf("Expected co-authors %v without the author, but got %v", expected, names)
+		}
+	}
+
+	tests := []struct {
+		credit   CoAuthorCredit
+		expected map[string][3]float64 // Commits, CoAuthored, Credit
+	}{
+		{FullCredit, map[string][3]float64{"alice@example.com": {2, 0, 0}, "bob@example.com": {2, 1, 0}, "carol@example.com": {1, 1, 0}}},
+		{SplitCredit, map[string][3]float64{"alice@example.com": {2, 0, 1 + 1.0/3}, "bob@example.com": {2, 1, 1 + 1.0/3}, "carol@example.com": {1, 1, 1.0 / 3}}},
+		{NoCredit, map[string][3]float64{"alice@example.com": {2, 0, 0}, "bob@example.com": {1, 0, 0}}},
+	}
+	for _, tt := range tests {
+		counts, err := GetAuthorCommitCounts(context.Background(), dir, AuthorDate, tt.credit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := make(map[string][3]float64)
+		for email, entry := range counts {
+			// Thirds do not add up exactly
+			credit := float64(int(entry.Credit*1000+0.5)) / 1000
+			got[email] = [3]float64{float64(entry.Commits), float64(entry.CoAuthored), credit}
+		}
+		for email, counts := range tt.expected {
+			counts[2] = float64(int(counts[2]*1000+0.5)) / 1000
+			tt.expected[email] = counts
+		}
+		if !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("With %s credit, expected %v, but got %v", tt.credit, tt.expected, got)
+		}
+	}
 
-	// Check the results
-	if len(commits) != 1 {
-		t.Fatalf("Expected 1 commit, but got %d", len(commits))
+	if _, err := ParseCoAuthorCredit("half"); err == nil {
+		t.Error("Expected an error for an unknown co-author credit")
 	}
+}
+
+// mergeFixture builds a mainline with three feature branch merges. The last
+// branch merged main back in before it was merged itself.
+func mergeFixture(t *testing.T) string {
+	return testutil.NewRepo(t).
+		WithAuthor("Alice", "alice@example.com").
+		Commit("initial", map[string]string{"a.go": "0"}).
+		Branch("feature-a").
+		WithAuthor("Bob", "bob@example.com").
+		Commit("a1", map[string]string{"b.go": "1"}).
+		Commit("a2", map[string]string{"b.go": "2"}).
+		Checkout("main").
+		WithAuthor("Alice", "alice@example.com").
+		Commit("main work", map[string]string{"a.go": "1"}).
+		Merge("feature-a", "merge feature-a").
+		Branch("feature-b").
+		WithAuthor("Carol", "carol@example.com").
+		Commit("b1", map[string]string{"c.go": "1"}).
+		Checkout("main").
+		WithAuthor("Alice", "alice@example.com").
+		Merge("feature-b", "merge feature-b").
+		Branch("feature-c").
+		WithAuthor("Dave", "dave@example.com").
+		Commit("c1", map[string]string{"d.go": "1"}).
+		Checkout("main").
+		WithAuthor("Alice", "alice@example.com").
+		Commit("more main work", map[string]string{"a.go": "2"}).
+		Checkout("feature-c").
+		WithAuthor("Dave", "dave@example.com").
+		Merge("main", "merge main into feature-c").
+		Checkout("main").
+		WithAuthor("Alice", "alice@example.com").
+		Merge("feature-c", "merge feature-c").
+		Dir()
+}
+
+func TestGetMainlineMerges(t *testing.T) {
+	dir := mergeFixture(t)
+	at := func(hours int) time.Time { return testutil.StartDate.Add(time.Duration(hours) * time.Hour) }
+
+	merges, err := GetMainlineMerges(context.Background(), dir, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The merge of main into feature-c is not on the mainline, and the main
+	// commit it brought in is not part of feature-c
+	expected := []types.MergeInfo{
+		{MergedAt: at(10), FirstCommitAt: at(7), Author: "Dave", Email: "dave@example.com", Commits: 2},
+		{MergedAt: at(6), FirstCommitAt: at(5), Author: "Carol", Email: "carol@example.com", Commits: 1},
+		{MergedAt: at(4), FirstCommitAt: at(1), Author: "Bob", Email: "bob@example.com", Commits: 2},
+	}
+	if len(merges) != len(expected) {
+		t.Fatalf("Expected %d mainline merges, but got %+v", len(expected), merges)
+	}
+	for i, merge := range merges {
+		if merge.Hash == "" {
+			t.Errorf("Expected merge %d to have a hash", i)
+		}
+		merge.Hash = ""
+		if !merge.MergedAt.Equal(expected[i].MergedAt) || !merge.FirstCommitAt.Equal(expected[i].FirstCommitAt) ||
+			merge.Author != expected[i].Author || merge.Email != expected[i].Email || merge.Commits != expected[i].Commits {
+			t.Errorf("Expected merge %d to be %+v, but got %+v", i, expected[i], merge)
+		}
+	}
+
+	recent, err := GetMainlineMerges(context.Background(), dir, at(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recent) != 2 || recent[1].Author != "Carol" {
+		t.Errorf("Expected the merges of feature-b and feature-c since the window start, but got %+v", recent)
+	}
+}
+
+func TestDateType(t *testing.T) {
+	authorDate := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	commitDate := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	// --date only overrides the author date
+	repo := testutil.NewRepo(t).At(commitDate)
+	repo.Git("commit", "--allow-empty", "-m", "rebased", "--date", authorDate.Format(time.RFC3339))
+	dir := repo.Dir()
+
+	for _, tt := range []struct {
+		dateType DateType
+		expected time.Time
+	}{
+		{AuthorDate, authorDate},
+		{CommitDate, commitDate},
+	} {
+		commits, err := GetCommitHistory(context.Background(), dir, tt.dateType)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(commits) != 1 || !commits[0].Date.Equal(tt.expected) {
+			t.Errorf("Expected %s date %v, but got %+v", tt.dateType, tt.expected, commits)
+		}
+
+		// git selects recent commits by commit date either way
+		recent, err := GetRecentContributors(context.Background(), dir, 30, tt.dateType)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if entry := recent[testutil.DefaultAuthorEmail]; !entry.LastCommit.Equal(tt.expected) {
+			t.Errorf("Expected recent %s date %v, but got %v", tt.dateType, tt.expected, entry.LastCommit)
+		}
+	}
+
+	if _, err := ParseDateType("committer"); err == nil {
+		t.Errorf("Expected an error for an unknown date type")
+	}
+}
+
+// installFakeCommand puts an executable script named name that sleeps for a
+// long time at the front of PATH.
+func installFakeCommand(t *testing.T, name string) {
+	t.Helper()
+
+	bin := t.TempDir()
+	script := "#!/bin/sh\nexec sleep 30\n"
+	if err := os.WriteFile(filepath.Join(bin, name), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestGetTrackedFilesCancelled(t *testing.T) {
+	installFakeCommand(t, "git")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := GetTrackedFiles(ctx, t.TempDir())
+	if err == nil {
+		t.Fatalf("Expected an error after cancelling the context")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected cancellation to stop git promptly, but it took %v", elapsed)
+	}
+}
+
+func TestValidateRepositoryErrors(t *testing.T) {
+	err := ValidateRepository(context.Background(), t.TempDir())
+	if !errors.Is(err, cerrors.ErrNotARepo) {
+		t.Errorf("Expected ErrNotARepo for a plain directory, but got %v", err)
+	}
+
+	t.Setenv("PATH", t.TempDir())
+	err = ValidateRepository(context.Background(), t.TempDir())
+	var toolErr *cerrors.ErrToolNotFound
+	if !errors.As(err, &toolErr) || toolErr.Tool != "git" {
+		t.Errorf("Expected ErrToolNotFound for git, but got %v", err)
+	}
+}
+
+func TestGetRemoteURL(t *testing.T) {
+	repo := testutil.NewRepo(t).Commit("initial commit", map[string]string{"main.go": "package main\n"})
+	repo.Git("remote", "add", "origin", "git@github.com:xeon-zolt/codecompass.git")
+
+	url, err := GetRemoteURL(context.Background(), repo.Dir(), "origin")
+	if err != nil {
+		t.Fatalf("GetRemoteURL failed: %v", err)
+	}
+	if url != "git@github.com:xeon-zolt/codecompass.git" {
+		t.Errorf("Expected the origin URL, but got %q", url)
+	}
+
+	if _, err := GetRemoteURL(context.Background(), repo.Dir(), "upstream"); err == nil {
+		t.Errorf("Expected an error for a missing remote")
+	}
+}
+
+func TestIsCloneURL(t *testing.T) {
+	tests := map[string]bool{
+		"https://github.com/xeon-zolt/codecompass.git": true,
+		"ssh://git@example.com/repo.git":               true,
+		"file:///srv/git/repo.git":                     true,
+		"git@github.com:xeon-zolt/codecompass.git":     true,
+		"../services/api":                              false,
+		"/srv/repos/web":                               false,
+		"C:/repos/web":                                 false,
+	}
+	for repo, expected := range tests {
+		if got := IsCloneURL(repo); got != expected {
+			t.Errorf("IsCloneURL(%q) = %v; expected %v", repo, got, expected)
+		}
+	}
+}
+
+func TestClone(t *testing.T) {
+	repo := testutil.NewRepo(t).
+		Commit("initial commit", map[string]string{"main.go": "package main\n"}).
+		Commit("second commit", map[string]string{"util.go": "package main\n"})
+
+	path := filepath.Join(t.TempDir(), "clone")
+	if err := Clone(context.Background(), "file://"+repo.Dir(), path); err != nil {
+		t.Fatalf("Clone failed: %v", err)
+	}
+	// The full history is cloned
+	if count, err := command(context.Background(), path, "rev-list", "--count", "HEAD").Output(); err != nil || strings.TrimSpace(string(count)) != "2" {
+		t.Errorf("Expected 2 commits in the clone, got %q, %v", count, err)
+	}
+
+	if err := Clone(context.Background(), "file://"+filepath.Join(t.TempDir(), "missing"), filepath.Join(t.TempDir(), "clone")); err == nil {
+		t.Errorf("Expected an error cloning a missing repository")
+	}
+}
+
+func TestGetAttributes(t *testing.T) {
+	repo := testutil.NewRepo(t).Commit("initial commit", map[string]string{
+		".gitattributes":  "*.js linguist-vendored\nown.js -linguist-vendored\n*.pb.go linguist-generated=true\n",
+		"lib.js":          "var lib;\n",
+		"own.js":          "var own;\n",
+		"api/types.pb.go": "package api\n",
+		"docs/readme.md":  "# Docs\n",
+	})
+
+	attributes, err := GetAttributes(context.Background(), repo.Dir(), []string{"lib.js", "own.js", "api/types.pb.go", "docs/readme.md"}, "linguist-vendored", "linguist-generated")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attributes["lib.js"]["linguist-vendored"] != "set" || attributes["own.js"]["linguist-vendored"] != "unset" {
+		t.Errorf("Expected lib.js set and own.js unset as vendored, but got %v", attributes)
+	}
+	if attributes["api/types.pb.go"]["linguist-generated"] != "true" {
+		t.Errorf("Expected api/types.pb.go to be generated, but got %v", attributes)
+	}
+	// Unspecified attributes are left out
+	if _, found := attributes["docs/readme.md"]; found {
+		t.Errorf("Expected no attributes for docs/readme.md, but got %v", attributes["docs/readme.md"])
+	}
+}
+
+func TestIsLFSPointer(t *testing.T) {
+	pointer := "version https://git-lfs.github.com/spec/v1\noid sha256:4d7a214614ab2935c943f9e0ff69d22eadbb8f32b1258daaa5e2ca24d17e2393\nsize 12345\n"
+
+	if !IsLFSPointer([]byte(pointer)) {
+		t.Error("Expected an LFS pointer to be recognized")
+	}
+	if IsLFSPointer([]byte("\x89PNG\r\n\x1a\n")) {
+		t.Error("Expected a PNG not to be an LFS pointer")
+	}
+	if IsLFSPointer([]byte("version https://git-lfs.github.com/spec/v1\n")) {
+		t.Error("Expected a pointer without an oid not to be an LFS pointer")
+	}
+}
+
+func TestParseLFSFiles(t *testing.T) {
+	output := "4d7a214614 * assets/logo.png (1.2 MB)\n" +
+		"a1b2c3d4e5 - docs/diagram with spaces.psd (512 B)\n" +
+		"not a listing\n"
+
+	sizes := parseLFSFiles(output)
+
+	expected := map[string]int64{"assets/logo.png": 1200000, "docs/diagram with spaces.psd": 512}
+	if len(sizes) != len(expected) {
+		t.Fatalf("Expected %v, but got %v", expected, sizes)
+	}
+	for path, size := range expected {
+		if sizes[path] != size {
+			t.Errorf("Expected %s to be %d bytes, but got %d", path, size, sizes[path])
+		}
+	}
+}
+
+func TestParseStatusOutput(t *testing.T) {
+	output := " M src/app.js\x00M  src/staged.js\x00R  src/new.js\x00src/old.js\x00?? draft.js\x00!! build/out\x00"
+	status := parseStatusOutput([]byte(output))
+
+	// The original path of a rename is not in the work tree anymore
+	if len(status.Modified) != 3 || !status.Modified["src/app.js"] || !status.Modified["src/staged.js"] || !status.Modified["src/new.js"] {
+		t.Errorf("Expected app.js, staged.js and new.js to be modified, but got %v", status.Modified)
+	}
+	if len(status.Untracked) != 1 || !status.Untracked["draft.js"] {
+		t.Errorf("Expected only draft.js to be untracked, but got %v", status.Untracked)
+	}
+	if !parseStatusOutput(nil).Clean() {
+		t.Errorf("Expected no status output to be a clean work tree")
+	}
+}
+
+func TestParseDiffLines(t *testing.T) {
+	output := "diff --git a/a.js b/a.js\n--- a/a.js\n+++ b/a.js\n" +
+		"@@ -2 +2 @@\n-old\n+new\n" +
+		"@@ -5,0 +6,2 @@\n+added\n+added\n" +
+		"@@ -9,2 +10,0 @@\n-removed\n-removed\n"
+	lines := parseDiffLines([]byte(output))
+
+	if len(lines) != 3 || !lines[2] || !lines[6] || !lines[7] {
+		t.Errorf("Expected lines 2, 6 and 7 to be uncommitted, but got %v", lines)
+	}
+}
+
+func TestBlameUncommittedChanges(t *testing.T) {
+	repo := testutil.NewRepo(t).
+		WithAuthor("Alice", "alice@example.com").
+		Commit("initial commit", map[string]string{"staged.js": "a\nb\nc\n", "unstaged.js": "a\nb\nc\n", "clean.js": "a\n"}).
+		Write(map[string]string{"staged.js": "a\nB\nc\n", "unstaged.js": "a\nb\nc\nd\n", "draft.js": "x\n"})
+	repo.Git("add", "staged.js")
+
+	status, err := GetWorkTreeStatus(context.Background(), repo.Dir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Clean() || len(status.Modified) != 2 || !status.Modified["staged.js"] || !status.Modified["unstaged.js"] {
+		t.Errorf("Expected staged.js and unstaged.js to be modified, but got %v", status.Modified)
+	}
+	if len(status.Untracked) != 1 || !status.Untracked["draft.js"] {
+		t.Errorf("Expected draft.js to be untracked, but got %v", status.Untracked)
+	}
+
+	blamer := NewBlamer(repo.Dir(), utils.NewSemaphore(1), logging.Discard(), utils.NewWarningCollector())
+	blamer.MarkDirty("staged.js", "unstaged.js")
+	tests := []struct {
+		file        string
+		uncommitted int
+	}{
+		{"staged.js", 2},
+		{"unstaged.js", 4},
+	}
+	for _, tt := range tests {
+		blameMap, err := blamer.BlameFile(context.Background(), tt.file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for line, info := range blameMap {
+			expected := "alice@example.com"
+			if line == tt.uncommitted {
+				expected = UncommittedAuthor.Email
+			}
+			if info.Email != expected {
+				t.Errorf("Expected line %d of %s to be blamed on %s, but got %s", line, tt.file, expected, info.Email)
+			}
+		}
+	}
+}
+
+func TestParseBlameOutputIncremental(t *testing.T) {
+	alice := strings.Repeat("a", 40)
+	bob := strings.Repeat("b", 40)
+	// Alice's details are only printed for her first range of lines
+	output := alice + " 1 1 2\n" +
+		"author Alice\nauthor-mail <alice@example.com>\nauthor-time 1700000000\nauthor-tz +0000\n" +
+		"summary initial commit\nboundary\nfilename main.js\n" +
+		bob + " 3 3 1\n" +
+		"author Bob\nauthor-mail <bob@example.com>\nsummary fix\nprevious " + alice + " main.js\nfilename main.js\n" +
+		alice + " 3 4 2\nfilename main.js\n"
+
+	blameMap := parseBlameOutput(output)
+	aliceTime := time.Unix(1700000000, 0)
+	expected := map[int]types.BlameInfo{
+		1: {Name: "Alice", Email: "alice@example.com", Time: aliceTime},
+		2: {Name: "Alice", Email: "alice@example.com", Time: aliceTime},
+		3: {Name: "Bob", Email: "bob@example.com"},
+		4: {Name: "Alice", Email: "alice@example.com", Time: aliceTime},
+		5: {Name: "Alice", Email: "alice@example.com", Time: aliceTime},
+	}
+	if !reflect.DeepEqual(blameMap, expected) {
+		t.Errorf("Expected %v, but got %v", expected, blameMap)
+	}
+}
+
+func TestBlameFormats(t *testing.T) {
+	dir := newBlameRepo(t, 20).Dir()
+
+	var blameMaps []map[int]types.BlameInfo
+	for _, format := range []BlameFormat{BlameIncremental, BlameLinePorcelain} {
+		blamer := NewBlamer(dir, utils.NewSemaphore(1), logging.Discard(), utils.NewWarningCollector())
+		blamer.SetFormat(format)
+		blameMap, err := blamer.BlameFile(context.Background(), "big.js")
+		if err != nil {
+			t.Fatal(err)
+		}
+		blameMaps = append(blameMaps, blameMap)
+	}
+
+	if len(blameMaps[0]) != 20 || !reflect.DeepEqual(blameMaps[0], blameMaps[1]) {
+		t.Errorf("Expected both formats to blame the same 20 lines, but got %v and %v", blameMaps[0], blameMaps[1])
+	}
+	if _, err := ParseBlameFormat("porcelain"); err == nil {
+		t.Errorf("Expected an error for an unsupported blame format")
+	}
+}
+
+// newBlameRepo creates a repository with a file of lines lines, every
+// fifth one last changed by one of four authors in turn.
+func newBlameRepo(tb testing.TB, lines int) *testutil.RepoBuilder {
+	tb.Helper()
+
+	repo := testutil.NewRepo(tb)
+	content := make([]string, lines)
+	for i := range content {
+		content[i] = fmt.Sprintf("var line%d = %d;", i, i)
+	}
+	repo.Commit("initial commit", map[string]string{"big.js": strings.Join(content, "\n") + "\n"})
+	for author := 0; author < 4; author++ {
+		for i := author; i < lines; i += 5 {
+			content[i] = fmt.Sprintf("var line%d = %d; // changed", i, author)
+		}
+		repo.WithAuthor(fmt.Sprintf("Author %d", author), fmt.Sprintf("author%d@example.com", author)).
+			Commit(fmt.Sprintf("change %d", author), map[string]string{"big.js": strings.Join(content, "\n") + "\n"})
+	}
+	return repo
+}
 
-	commit := commits[0]
-	if commit.Message != "initial commit" {
-		t.Errorf("Expected commit message to be 'initial commit', but got '%s'", commit.Message)
+// BenchmarkParseBlameOutput parses the blame of a 20,000 line file in each
+// format, reporting the size of the output git blame printed.
+func BenchmarkParseBlameOutput(b *testing.B) {
+	dir := newBlameRepo(b, 20000).Dir()
+	for _, format := range []BlameFormat{BlameIncremental, BlameLinePorcelain} {
+		output, err := command(context.Background(), dir, "blame", "--"+string(format), "--", "big.js").Output()
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(string(format), func(b *testing.B) {
+			b.ReportAllocs()
+			b.ReportMetric(float64(len(output)), "output-bytes")
+			for i := 0; i < b.N; i++ {
+				parseBlameOutput(string(output))
+			}
+		})
 	}
 }
@@ -0,0 +1,221 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xeon-zolt/codecompass/internal/cerrors"
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+// Blob is a file in a commit's tree.
+type Blob struct {
+	Path string
+	Hash string
+	Size int64
+}
+
+// GetHeadBlobs returns every file at HEAD with its size in bytes.
+// Submodules and symlinks are left out.
+func GetHeadBlobs(ctx context.Context, dir string) ([]Blob, error) {
+	output, err := command(ctx, dir, "ls-tree", "-r", "-l", "-z", "HEAD").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files at HEAD: %w", err)
+	}
+
+	var blobs []Blob
+	for _, record := range strings.Split(string(output), "\x00") {
+		// <mode> SP <type> SP <object> SP <size> TAB <path>
+		meta, path, ok := strings.Cut(record, "\t")
+		fields := strings.Fields(meta)
+		if !ok || len(fields) != 4 || fields[1] != "blob" || fields[0] == "120000" {
+			continue
+		}
+		size, err := strconv.ParseInt(fields[3], 10, 64)
+		if err != nil {
+			continue
+		}
+		blobs = append(blobs, Blob{Path: path, Hash: fields[2], Size: size})
+	}
+	return blobs, nil
+}
+
+// GetLFSTrackedPaths returns the paths git would store with the LFS filter,
+// going by every .gitattributes file in the work tree.
+func GetLFSTrackedPaths(ctx context.Context, dir string, paths []string) (map[string]bool, error) {
+	tracked := make(map[string]bool)
+	if len(paths) == 0 {
+		return tracked, nil
+	}
+
+	cmd := command(ctx, dir, "check-attr", "-z", "--stdin", "filter")
+	cmd.Stdin = strings.NewReader(strings.Join(paths, "\x00") + "\x00")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check attributes: %w", err)
+	}
+
+	// Each result is <path> NUL <attribute> NUL <value> NUL
+	fields := strings.Split(string(output), "\x00")
+	for i := 0; i+2 < len(fields); i += 3 {
+		if fields[i+2] == "lfs" {
+			tracked[fields[i]] = true
+		}
+	}
+	return tracked, nil
+}
+
+// GetAttributes returns the values of the named attributes for paths, going
+// by every .gitattributes file in the work tree. Attributes that are not
+// specified for a path are left out; set and unset attributes have the
+// values "set" and "unset".
+func GetAttributes(ctx context.Context, dir string, paths []string, attributes ...string) (map[string]map[string]string, error) {
+	values := make(map[string]map[string]string)
+	if len(paths) == 0 {
+		return values, nil
+	}
+
+	cmd := command(ctx, dir, append([]string{"check-attr", "-z", "--stdin"}, attributes...)...)
+	cmd.Stdin = strings.NewReader(strings.Join(paths, "\x00") + "\x00")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check attributes: %w", err)
+	}
+
+	// Each result is <path> NUL <attribute> NUL <value> NUL
+	fields := strings.Split(string(output), "\x00")
+	for i := 0; i+2 < len(fields); i += 3 {
+		if fields[i+2] == "unspecified" {
+			continue
+		}
+		if values[fields[i]] == nil {
+			values[fields[i]] = make(map[string]string)
+		}
+		values[fields[i]][fields[i+1]] = fields[i+2]
+	}
+	return values, nil
+}
+
+// ReadBlobs returns the contents of the blobs with the given hashes.
+func ReadBlobs(ctx context.Context, dir string, hashes []string) (map[string][]byte, error) {
+	contents := make(map[string][]byte)
+	if len(hashes) == 0 {
+		return contents, nil
+	}
+
+	cmd := command(ctx, dir, "cat-file", "--batch")
+	cmd.Stdin = strings.NewReader(strings.Join(hashes, "\n") + "\n")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read blobs: %w", err)
+	}
+
+	reader := bufio.NewReader(bytes.NewReader(output))
+	for {
+		// <object> SP <type> SP <size> LF <contents> LF, or <object> SP missing LF
+		header, err := reader.ReadString('\n')
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		fields := strings.Fields(header)
+		if len(fields) != 3 {
+			continue
+		}
+		size, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("unexpected cat-file header %q", header)
+		}
+
+		content := make([]byte, size+1)
+		if _, err := io.ReadFull(reader, content); err != nil {
+			return nil, fmt.Errorf("failed to read blob %s: %w", fields[0], err)
+		}
+		contents[fields[0]] = content[:size]
+	}
+	return contents, nil
+}
+
+// LFSPointerMaxSize is the largest an LFS pointer file can be.
+const LFSPointerMaxSize = 1024
+
+// IsLFSPointer reports whether content is a Git LFS pointer file rather
+// than the object itself.
+func IsLFSPointer(content []byte) bool {
+	if len(content) >= LFSPointerMaxSize {
+		return false
+	}
+	// Pointers written before Git LFS 1.0 use the hawser spec URL
+	return (bytes.HasPrefix(content, []byte("version https://git-lfs.github.com/spec/")) ||
+		bytes.HasPrefix(content, []byte("version https://hawser.github.com/spec/"))) &&
+		bytes.Contains(content, []byte("\noid sha256:"))
+}
+
+// GetLastChange returns the most recent commit that changed path, which is
+// the one that introduced its current contents.
+func GetLastChange(ctx context.Context, dir, path string) (types.CommitInfo, error) {
+	output, err := command(ctx, dir, "log", "-1", "--format=%H|%an|%ae|%at", "--", path).Output()
+	if err != nil {
+		return types.CommitInfo{}, fmt.Errorf("failed to find the commit that changed %s: %w", path, err)
+	}
+
+	parts := strings.SplitN(strings.TrimSpace(string(output)), "|", 4)
+	if len(parts) < 4 {
+		return types.CommitInfo{}, fmt.Errorf("no commit changed %s", path)
+	}
+	timestamp, _ := strconv.ParseInt(parts[3], 10, 64)
+
+	return types.CommitInfo{Hash: parts[0], Author: parts[1], Email: parts[2], Date: time.Unix(timestamp, 0)}, nil
+}
+
+// lfsFileLine matches a line of git lfs ls-files -s output, such as
+// "4d7a214614 * assets/logo.png (1.2 MB)".
+var lfsFileLine = regexp.MustCompile(`^([0-9a-f]+) [*-] (.+) \(([0-9.]+) ([KMGTP]?B)\)$`)
+
+// lfsUnits are the byte multiples git lfs prints sizes in.
+var lfsUnits = map[string]float64{"B": 1, "KB": 1e3, "MB": 1e6, "GB": 1e9, "TB": 1e12, "PB": 1e15}
+
+// GetLFSObjectSizes returns the size of each LFS object at HEAD by path,
+// as reported by git lfs ls-files. git lfs rounds sizes for display, so they
+// are approximate. It returns cerrors.ErrToolNotFound when git lfs is not
+// installed.
+func GetLFSObjectSizes(ctx context.Context, dir string) (map[string]int64, error) {
+	// Without git-lfs, git itself fails with "'lfs' is not a git command"
+	if _, err := command(ctx, dir, "lfs", "version").Output(); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, &cerrors.ErrToolNotFound{Tool: "git-lfs", Err: err}
+	}
+
+	output, err := command(ctx, dir, "lfs", "ls-files", "-s").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list LFS files: %w", err)
+	}
+	return parseLFSFiles(string(output)), nil
+}
+
+func parseLFSFiles(output string) map[string]int64 {
+	sizes := make(map[string]int64)
+	for _, line := range strings.Split(output, "\n") {
+		match := lfsFileLine.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			continue
+		}
+		value, err := strconv.ParseFloat(match[3], 64)
+		if err != nil {
+			continue
+		}
+		sizes[match[2]] = int64(value * lfsUnits[match[4]])
+	}
+	return sizes
+}
@@ -0,0 +1,105 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+// UncommittedAuthor is who the lines of a file that differ from HEAD are
+// blamed on, rather than whoever last committed the line they replaced.
+var UncommittedAuthor = types.BlameInfo{Name: "uncommitted", Email: "uncommitted"}
+
+// WorkTreeStatus is what git status reports as changed since HEAD.
+type WorkTreeStatus struct {
+	// Modified are the tracked files with staged or unstaged changes,
+	// including added, renamed and deleted ones.
+	Modified map[string]bool
+	// Untracked are the files that are neither tracked nor ignored.
+	Untracked map[string]bool
+}
+
+// Clean reports whether the work tree matches HEAD.
+func (s WorkTreeStatus) Clean() bool {
+	return len(s.Modified) == 0 && len(s.Untracked) == 0
+}
+
+// GetWorkTreeStatus runs git status to find the files that differ from
+// HEAD.
+func GetWorkTreeStatus(ctx context.Context, dir string) (WorkTreeStatus, error) {
+	output, err := command(ctx, dir, "status", "--porcelain", "-z", "--untracked-files=all").Output()
+	if err != nil {
+		return WorkTreeStatus{}, fmt.Errorf("failed to read the work tree status: %w", err)
+	}
+	return parseStatusOutput(output), nil
+}
+
+// parseStatusOutput parses the output of git status --porcelain -z: an XY
+// status, a space and a path per NUL-terminated entry, with a rename or
+// copy followed by an entry holding its original path.
+func parseStatusOutput(output []byte) WorkTreeStatus {
+	status := WorkTreeStatus{Modified: make(map[string]bool), Untracked: make(map[string]bool)}
+	entries := strings.Split(string(output), "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+		code, path := entry[:2], entry[3:]
+		switch {
+		case code == "??":
+			status.Untracked[path] = true
+		case code == "!!":
+		default:
+			status.Modified[path] = true
+			if code[0] == 'R' || code[0] == 'C' {
+				i++ // the original path
+			}
+		}
+	}
+	return status
+}
+
+// hunkHeader matches the header of a hunk of unified diff output, capturing
+// where its lines start in the new file and, if not one, how many there are.
+var hunkHeader = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,(\d+))? @@`)
+
+// GetUncommittedLines returns the lines of filePath in the work tree that
+// are added or changed since HEAD, staged or not.
+func GetUncommittedLines(ctx context.Context, dir, filePath string) (map[int]bool, error) {
+	output, err := command(ctx, dir, "diff", "-U0", "--no-color", "--no-ext-diff", "HEAD", "--", filePath).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff %s: %w", filePath, err)
+	}
+	return parseDiffLines(output), nil
+}
+
+// parseDiffLines returns the lines of the new file the hunks of unified
+// diff output with no context add.
+func parseDiffLines(output []byte) map[int]bool {
+	lines := make(map[int]bool)
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(nil, max(len(output)+1, bufio.MaxScanTokenSize))
+	for scanner.Scan() {
+		match := hunkHeader.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			continue
+		}
+		start, _ := strconv.Atoi(match[1])
+		count := 1
+		if match[2] != "" {
+			count, _ = strconv.Atoi(match[2])
+		}
+		// A hunk of removed lines only has a count of zero
+		for line := start; line < start+count; line++ {
+			lines[line] = true
+		}
+	}
+	return lines
+}
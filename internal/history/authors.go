@@ -0,0 +1,152 @@
+package history
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+// AuthorSnapshot is what one logged run recorded about each author, by
+// email. A nil map means the run did not log that leaderboard, while an
+// author missing from a logged one had nothing there.
+type AuthorSnapshot struct {
+	Time    time.Time
+	Issues  map[string]int
+	Commits map[string]int
+
+	// ToolVersions are the versions of the tools the run used, nil for
+	// runs logged before they were recorded.
+	ToolVersions map[string]string
+}
+
+// ReadAuthorSnapshot returns the author and commit counts of the last run
+// logged in dir before the given time, which leaves out the files of a run
+// logged at that time. It returns nil when dir has no such run, or does not
+// exist.
+func ReadAuthorSnapshot(dir string, before time.Time) (*AuthorSnapshot, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history directory %s: %w", dir, err)
+	}
+
+	// Stamps only have seconds, so a run logged in the same second as
+	// before is its own
+	before = before.Truncate(time.Second)
+
+	var latest time.Time
+	files := make(map[string]string)
+	runFiles := make(map[string]string) // By stamp
+	for _, entry := range entries {
+		match := historyFile.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		if match[1] == "run" {
+			runFiles[match[2]] = filepath.Join(dir, entry.Name())
+			continue
+		}
+		if match[1] != "author_leaderboard" && match[1] != "commit_count_leaderboard" {
+			continue
+		}
+		at, err := time.ParseInLocation(stampLayout, match[2], time.Local)
+		if err != nil || !at.Before(before) || at.Before(latest) {
+			continue
+		}
+		if at.After(latest) {
+			latest = at
+			files = make(map[string]string)
+		}
+		files[match[1]] = filepath.Join(dir, entry.Name())
+	}
+	if len(files) == 0 {
+		return nil, nil
+	}
+
+	snapshot := &AuthorSnapshot{Time: latest}
+	if path, ok := files["author_leaderboard"]; ok {
+		if snapshot.Issues, err = readAuthorCounts(path, "Issues"); err != nil {
+			return nil, err
+		}
+	}
+	if path, ok := files["commit_count_leaderboard"]; ok {
+		if snapshot.Commits, err = readAuthorCounts(path, "Commits"); err != nil {
+			return nil, err
+		}
+	}
+	// The versions only qualify the deltas, so unreadable run metadata
+	// leaves them out
+	if path, ok := runFiles[latest.Format(stampLayout)]; ok {
+		if t, err := readTable(path); err == nil && len(t.rows) > 0 {
+			if value, ok := t.value(t.rows[0], "ToolVersions"); ok {
+				snapshot.ToolVersions = parseToolVersions(value)
+			}
+		}
+	}
+	return snapshot, nil
+}
+
+// readAuthorCounts reads the named count of each email of a leaderboard
+// file.
+func readAuthorCounts(path, column string) (map[string]int, error) {
+	t, err := readTable(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	counts := make(map[string]int)
+	for _, row := range t.rows {
+		email, hasEmail := t.value(row, "Email")
+		count, hasCount := t.number(row, column)
+		if hasEmail && hasCount {
+			counts[email] += int(count)
+		}
+	}
+	return counts, nil
+}
+
+// ReadAuthorLeaderboardCSV reads back the author leaderboard
+// WriteAuthorLeaderboardCSV wrote to path. Names and emails with commas,
+// quotes or line breaks are read as written, and the CSV sanitizing is
+// undone. Columns are looked up by name, so files of other versions can be
+// read, with the columns they lack left zero.
+func ReadAuthorLeaderboardCSV(path string) ([]types.LeaderboardEntry, error) {
+	t, err := readTable(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	entries := make([]types.LeaderboardEntry, 0, len(t.rows))
+	for _, row := range t.rows {
+		var entry types.LeaderboardEntry
+		entry.Name, _ = t.value(row, "Name")
+		entry.Email, _ = t.value(row, "Email")
+		entry.TopRule, _ = t.value(row, "TopRule")
+		counts := []struct {
+			column string
+			value  *int
+		}{
+			{"Rank", &entry.Rank},
+			{"Issues", &entry.Count},
+			{"Errors", &entry.Errors},
+			{"Warnings", &entry.Warnings},
+			{"Files", &entry.Files},
+			{"TopRuleCount", &entry.TopCount},
+		}
+		for _, count := range counts {
+			if number, ok := t.number(row, count.column); ok {
+				*count.value = int(number)
+			}
+		}
+		entry.DecayedScore, _ = t.number(row, "DecayedScore")
+		entry.WeightedScore, _ = t.number(row, "WeightedScore")
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
@@ -0,0 +1,148 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+func TestReadAuthorSnapshot(t *testing.T) {
+	dir := t.TempDir()
+
+	// An older run, and one that only logged commits
+	files := map[string]string{
+		"author_leaderboard_20260105_093000.csv":       "Rank,Name,Email,Issues\n1,Alice,alice@example.com,9\n",
+		"author_leaderboard_20260110_093000.csv":       "Rank,Name,Email,Issues\n1,Alice,alice@example.com,5\n2,Bob,'=bob@example.com,3\n",
+		"commit_count_leaderboard_20260110_093000.csv": "Rank,Name,Email,Commits\n1,Alice,alice@example.com,40\n",
+		"run_20260110_093000.csv":                      "GeneratedAt,ToolVersions\n2026-01-10T09:30:00Z,eslint=8.57.0;git=2.43.0;bad\n",
+		"run_20260115_093000.csv":                      "GeneratedAt,ToolVersions\n2026-01-15T09:30:00Z,eslint=9.1.0\n",
+		"file_leaderboard_20260120_093000.csv":         "Rank,Path,Issues\n1,a.go,4\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The current run is logged before the packets are written
+	report := types.NewReport("/src/app")
+	report.GeneratedAt = time.Date(2026, 2, 1, 12, 0, 0, 500, time.Local)
+	report.Leaderboards = []string{"authors"}
+	report.Authors = []types.LeaderboardEntry{{Name: "Alice", Email: "alice@example.com", Count: 2}}
+	if err := NewWriter(dir).WriteReport(&report); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot, err := ReadAuthorSnapshot(dir, report.GeneratedAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &AuthorSnapshot{
+		Time:    time.Date(2026, 1, 10, 9, 30, 0, 0, time.Local),
+		Issues:  map[string]int{"alice@example.com": 5, "=bob@example.com": 3},
+		Commits: map[string]int{"alice@example.com": 40},
+
+		ToolVersions: map[string]string{"eslint": "8.57.0", "git": "2.43.0"},
+	}
+	if !reflect.DeepEqual(snapshot, expected) {
+		t.Errorf("ReadAuthorSnapshot = %+v; expected %+v", snapshot, expected)
+	}
+
+	snapshot, err = ReadAuthorSnapshot(dir, time.Date(2026, 1, 6, 0, 0, 0, 0, time.Local))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot == nil || snapshot.Issues["alice@example.com"] != 9 || snapshot.Commits != nil || snapshot.ToolVersions != nil {
+		t.Errorf("ReadAuthorSnapshot before the commits were logged = %+v; expected 9 issues and no commits or tool versions", snapshot)
+	}
+
+	for _, missing := range []string{dir, filepath.Join(dir, "missing")} {
+		snapshot, err := ReadAuthorSnapshot(missing, time.Date(2026, 1, 1, 0, 0, 0, 0, time.Local))
+		if err != nil || snapshot != nil {
+			t.Errorf("ReadAuthorSnapshot(%s) without earlier runs = %+v, %v; expected nil", missing, snapshot, err)
+		}
+	}
+}
+
+func TestReadAuthorLeaderboardCSV(t *testing.T) {
+	dir := t.TempDir()
+	entries := []types.LeaderboardEntry{
+		{Rank: 1, Name: "Doe, John", Email: "john@example.com", Count: 12, Errors: 4, Warnings: 8, Files: 3,
+			TopRule: "no-console", TopCount: 5, DecayedScore: 6.5, WeightedScore: 20.25},
+		{Rank: 2, Name: `Jane "JJ" Roe`, Email: `"jane, jr"@example.com`, Count: 2, Warnings: 2, Files: 1,
+			TopRule: "eqeqeq", TopCount: 2},
+		// Sanitized when written, and read back as it was
+		{Rank: 3, Name: "=SUM(A1:A2)", Email: "-@example.com", Count: 1, Errors: 1, Files: 1},
+		{Rank: 4, Name: "Line\nBreak", Email: "", Count: 1, Warnings: 1, Files: 1},
+	}
+	writer := NewWriter(dir)
+	writer.Time = time.Date(2026, 1, 10, 9, 30, 0, 0, time.Local)
+	if err := writer.WriteAuthorLeaderboardCSV(entries); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "author_leaderboard_20260110_093000.csv")
+	read, err := ReadAuthorLeaderboardCSV(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(read, entries) {
+		t.Errorf("Expected %+v, but got %+v", entries, read)
+	}
+
+	// The counts read for --trend are keyed by the same emails
+	snapshot, err := ReadAuthorSnapshot(dir, time.Date(2026, 2, 1, 0, 0, 0, 0, time.Local))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.Issues[`"jane, jr"@example.com`] != 2 || snapshot.Issues["-@example.com"] != 1 {
+		t.Errorf("Expected the issues of the quoted emails, but got %v", snapshot.Issues)
+	}
+
+	// Files of older versions lack the later columns
+	old := filepath.Join(dir, "old.csv")
+	if err := os.WriteFile(old, []byte("Rank,Name,Email,Issues\n1,\"Doe, John\",john@example.com,9\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	read, err = ReadAuthorLeaderboardCSV(old)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []types.LeaderboardEntry{{Rank: 1, Name: "Doe, John", Email: "john@example.com", Count: 9}}
+	if !reflect.DeepEqual(read, expected) {
+		t.Errorf("Expected %+v, but got %+v", expected, read)
+	}
+}
+
+func TestReadAuthorLeaderboardCSVRoundTrips(t *testing.T) {
+	// Leading quotes sanitizing does not add are kept in both modes
+	entries := []types.LeaderboardEntry{
+		{Rank: 1, Name: "=SUM(A1:A2)", Email: "-@example.com", Count: 1},
+		{Rank: 2, Name: "'=quoted", Email: "'-5@example.com", Count: 1},
+		{Rank: 3, Name: "O'Neil", Email: "'neil@example.com", Count: 1},
+		{Rank: 4, Name: "'-5", Email: "''@example.com", Count: 1},
+	}
+
+	for _, sanitize := range []bool{true, false} {
+		dir := t.TempDir()
+		writer := &Writer{Dir: dir, Sanitize: sanitize, Time: time.Date(2026, 1, 10, 9, 30, 0, 0, time.Local)}
+		if err := writer.WriteRunCSV(&types.Report{GeneratedAt: writer.Time}); err != nil {
+			t.Fatal(err)
+		}
+		if err := writer.WriteAuthorLeaderboardCSV(entries); err != nil {
+			t.Fatal(err)
+		}
+
+		read, err := ReadAuthorLeaderboardCSV(filepath.Join(dir, "author_leaderboard_20260110_093000.csv"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(read, entries) {
+			t.Errorf("With Sanitize %t, expected %+v, but got %+v", sanitize, entries, read)
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package history
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+// Run is one run logged in a history directory.
+type Run struct {
+	Stamp string    // The stamp of its file names, such as 20260110_093000
+	Time  time.Time // When it was generated
+
+	// Head is the commit the run analyzed and ToolVersions the versions of
+	// the tools it used, both empty for runs logged before they were
+	// recorded.
+	Head         string
+	ToolVersions map[string]string
+
+	// files maps the leaderboards it logged, such as file_leaderboard, to
+	// their files
+	files map[string]string
+}
+
+// diffFields maps the leaderboards DiffRuns compares to their history file,
+// the columns their entries are keyed by and the column compared. A count
+// of issues or lines going up is for the worse. Leaderboards of activity,
+// such as commits, have no better or worse and are left out.
+var diffFields = []struct {
+	leaderboard    string
+	file           string
+	keys           []string
+	value          string
+	higherIsBetter bool
+}{
+	{"authors", "author_leaderboard", []string{"Email"}, "Issues", false},
+	{"files", "file_leaderboard", []string{"Path"}, "Issues", false},
+	{"rules", "rule_leaderboard", []string{"Rule"}, "Violations", false},
+	{"rule-plugins", "rule_plugin_leaderboard", []string{"Plugin"}, "Violations", false},
+	{"rule-groups", "rule_group_leaderboard", []string{"Group"}, "Violations", false},
+	{"rule-author-matrix", "rule_author_matrix", []string{"Rule", "Email"}, "Violations", false},
+	{"ruff", "ruff_rule_leaderboard", []string{"Rule"}, "Violations", false},
+	{"loc", "loc_leaderboard", []string{"Path"}, "Lines", false},
+	{"coverage", "coverage_leaderboard", []string{"Path"}, "CoveragePercent", true},
+	{"churn", "churn_leaderboard", []string{"Path"}, "Changes", false},
+	{"hotspots", "hotspot_leaderboard", []string{"Path"}, "HotspotScore", false},
+	{"bugs", "bug_density_leaderboard", []string{"Path"}, "BugRatio", false},
+	{"debt", "technical_debt_leaderboard", []string{"Path"}, "TotalDebt", false},
+	{"spellcheck", "spell_check_leaderboard", []string{"Path"}, "MisspelledWords", false},
+	{"format-drift", "format_drift_leaderboard", []string{"Path"}, "Lines", false},
+	{"long-functions", "long_functions_leaderboard", []string{"Path", "FunctionName"}, "Lines", false},
+	{"by-workspace", "workspace_leaderboard", []string{"Path"}, "Issues", false},
+	{"vulns", "vulnerability_counts", []string{"Severity"}, "Count", false},
+	{"deps", "dependency_leaderboard", []string{"Path"}, "Ranged", false},
+	{"score", "score_leaderboard", []string{"Path"}, "Score", true},
+}
+
+// DiffLeaderboards returns the leaderboards DiffRuns can compare.
+func DiffLeaderboards() []string {
+	names := make([]string, len(diffFields))
+	for i, fields := range diffFields {
+		names[i] = fields.leaderboard
+	}
+	return names
+}
+
+// ReadRuns returns the runs logged in dir, oldest first. Runs logged before
+// run metadata existed are told by the stamp of their files alone.
+func ReadRuns(dir string) ([]Run, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history directory %s: %w", dir, err)
+	}
+
+	byStamp := make(map[string]*Run)
+	for _, entry := range entries {
+		match := historyFile.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		at, err := time.ParseInLocation(stampLayout, match[2], time.Local)
+		if err != nil {
+			continue
+		}
+		run := byStamp[match[2]]
+		if run == nil {
+			run = &Run{Stamp: match[2], Time: at, files: make(map[string]string)}
+			byStamp[match[2]] = run
+		}
+		run.files[match[1]] = filepath.Join(dir, entry.Name())
+	}
+
+	runs := make([]Run, 0, len(byStamp))
+	for _, run := range byStamp {
+		if path, ok := run.files["run"]; ok {
+			if t, err := readTable(path); err == nil && len(t.rows) > 0 {
+				row := t.rows[0]
+				if generated, ok := t.value(row, "GeneratedAt"); ok {
+					if at, err := time.Parse(time.RFC3339, generated); err == nil {
+						run.Time = at
+					}
+				}
+				run.Head, _ = t.value(row, "Head")
+				if value, ok := t.value(row, "ToolVersions"); ok {
+					run.ToolVersions = parseToolVersions(value)
+				}
+			}
+		}
+		runs = append(runs, *run)
+	}
+	sort.Slice(runs, func(i, j int) bool { return runs[i].Stamp < runs[j].Stamp })
+	return runs, nil
+}
+
+// FindRun returns the run id names: the one whose stamp starts with id,
+// written as 20260110_0930 or 2026-01-10T09:30, or whose commit starts
+// with it, given at least 4 characters. An id matching several runs is an
+// error listing them.
+func FindRun(runs []Run, id string) (Run, error) {
+	stamp := strings.NewReplacer("-", "", ":", "", "T", "_", " ", "_").Replace(id)
+
+	var matches []Run
+	for _, run := range runs {
+		byStamp := stamp != "" && strings.HasPrefix(run.Stamp, stamp)
+		byHead := len(id) >= 4 && run.Head != "" && strings.HasPrefix(run.Head, strings.ToLower(id))
+		if byStamp || byHead {
+			matches = append(matches, run)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return Run{}, fmt.Errorf("no logged run matches %q", id)
+	case 1:
+		return matches[0], nil
+	}
+	stamps := make([]string, len(matches))
+	for i, run := range matches {
+		stamps[i] = run.Stamp
+	}
+	return Run{}, fmt.Errorf("%q matches %d runs: %s", id, len(matches), strings.Join(stamps, ", "))
+}
+
+// DiffRuns compares the named leaderboards from run a to run b, in the
+// order DiffLeaderboards lists them, or without names, every one both runs
+// logged. A named leaderboard that either run did not log is an
+// error. Entries logged twice in a run, such as functions of the same name
+// in one file, are added up.
+func DiffRuns(a, b Run, leaderboards []string) ([]types.SnapshotDiff, error) {
+	known := make(map[string]bool, len(diffFields))
+	for _, fields := range diffFields {
+		known[fields.leaderboard] = true
+	}
+	selected := make(map[string]bool)
+	var unknown []string
+	for _, name := range leaderboards {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+		selected[name] = true
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("cannot compare %s: expected one of %s", strings.Join(unknown, ", "), strings.Join(DiffLeaderboards(), ", "))
+	}
+
+	var diffs []types.SnapshotDiff
+	for _, fields := range diffFields {
+		if len(selected) > 0 && !selected[fields.leaderboard] {
+			continue
+		}
+		pathA, okA := a.files[fields.file]
+		pathB, okB := b.files[fields.file]
+		if !okA || !okB {
+			if len(selected) == 0 {
+				continue
+			}
+			run := a
+			if okA {
+				run = b
+			}
+			return nil, fmt.Errorf("run %s did not log the %s leaderboard", run.Stamp, fields.leaderboard)
+		}
+
+		valuesA, err := readSnapshot(pathA, fields.keys, fields.value)
+		if err != nil {
+			return nil, err
+		}
+		valuesB, err := readSnapshot(pathB, fields.keys, fields.value)
+		if err != nil {
+			return nil, err
+		}
+
+		diff := types.SnapshotDiff{Leaderboard: fields.leaderboard, Metric: fields.value, HigherIsBetter: fields.higherIsBetter}
+		for key, valueA := range valuesA {
+			valueB, ok := valuesB[key]
+			change := types.SnapshotChange{Key: key, A: valueA, B: valueB, Delta: valueB - valueA}
+			switch {
+			case !ok:
+				diff.Disappeared = append(diff.Disappeared, change)
+			case change.Delta == 0:
+			case (change.Delta > 0) == fields.higherIsBetter:
+				diff.Improved = append(diff.Improved, change)
+			default:
+				diff.Worsened = append(diff.Worsened, change)
+			}
+		}
+		for key, valueB := range valuesB {
+			if _, ok := valuesA[key]; !ok {
+				diff.Appeared = append(diff.Appeared, types.SnapshotChange{Key: key, B: valueB, Delta: valueB})
+			}
+		}
+		for _, changes := range [][]types.SnapshotChange{diff.Worsened, diff.Improved, diff.Appeared, diff.Disappeared} {
+			sortChanges(changes)
+		}
+		diffs = append(diffs, diff)
+	}
+	return diffs, nil
+}
+
+// readSnapshot reads the value of each entry of a leaderboard file, by its
+// key columns joined with " / ". Rows without a number in the value column
+// are skipped.
+func readSnapshot(path string, keys []string, value string) (map[string]float64, error) {
+	t, err := readTable(path)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]float64)
+	for _, row := range t.rows {
+		number, ok := t.number(row, value)
+		if !ok {
+			continue
+		}
+		parts := make([]string, len(keys))
+		for i, key := range keys {
+			parts[i], _ = t.value(row, key)
+		}
+		values[strings.Join(parts, " / ")] += number
+	}
+	return values, nil
+}
+
+// sortChanges sorts changes by the size of the change, largest first, then
+// by key.
+func sortChanges(changes []types.SnapshotChange) {
+	sort.Slice(changes, func(i, j int) bool {
+		if a, b := math.Abs(changes[i].Delta), math.Abs(changes[j].Delta); a != b {
+			return a > b
+		}
+		return changes[i].Key < changes[j].Key
+	})
+}
@@ -0,0 +1,133 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+// writeHistory writes files, by name, to a new history directory.
+func writeHistory(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestReadRunsAndFindRun(t *testing.T) {
+	dir := writeHistory(t, map[string]string{
+		// Logged before run metadata was recorded
+		"file_leaderboard_20260105_093000.csv": "Rank,Path,Issues\n1,a.go,3\n",
+		"run_20260110_093000.csv":              "GeneratedAt,ToolVersions,Head\n2026-01-10T09:30:00Z,eslint=8.57.0,1a2b3c4d5e6f\n",
+		"run_20260110_180000.csv":              "GeneratedAt,ToolVersions,Head\n2026-01-10T18:00:00Z,eslint=9.1.0,1a2bffffffff\n",
+		"fingerprints.txt":                     "# codecompass fingerprints\n",
+	})
+
+	runs, err := ReadRuns(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stamps []string
+	for _, run := range runs {
+		stamps = append(stamps, run.Stamp)
+	}
+	if strings.Join(stamps, ",") != "20260105_093000,20260110_093000,20260110_180000" {
+		t.Fatalf("Expected the three runs oldest first, but got %v", stamps)
+	}
+	if runs[1].Head != "1a2b3c4d5e6f" || runs[1].ToolVersions["eslint"] != "8.57.0" || !runs[1].Time.Equal(time.Date(2026, 1, 10, 9, 30, 0, 0, time.UTC)) {
+		t.Errorf("Expected the run metadata to be read, but got %+v", runs[1])
+	}
+
+	tests := []struct {
+		id    string
+		stamp string // Empty when the id is an error
+	}{
+		{"20260105", "20260105_093000"},
+		{"2026-01-10T18:00", "20260110_180000"},
+		{"1A2B3C", "20260110_093000"},
+		{"20260110", ""},
+		{"1a2b", ""},
+		{"1a2", ""},
+		{"20270101", ""},
+	}
+	for _, tt := range tests {
+		run, err := FindRun(runs, tt.id)
+		if tt.stamp == "" {
+			if err == nil {
+				t.Errorf("FindRun(%q) = %s, expected an error", tt.id, run.Stamp)
+			}
+			continue
+		}
+		if err != nil || run.Stamp != tt.stamp {
+			t.Errorf("FindRun(%q) = %s, %v; expected %s", tt.id, run.Stamp, err, tt.stamp)
+		}
+	}
+
+	if runs, err := ReadRuns(filepath.Join(dir, "missing")); runs != nil || err != nil {
+		t.Errorf("ReadRuns of a missing directory = %v, %v; expected nil", runs, err)
+	}
+}
+
+func TestDiffRuns(t *testing.T) {
+	dir := writeHistory(t, map[string]string{
+		"file_leaderboard_20260110_093000.csv":     "Rank,Path,Issues\n1,a.go,3\n2,b.go,5\n3,c.go,2\n4,d.go,1\n",
+		"file_leaderboard_20260117_093000.csv":     "Rank,Path,Issues,Errors\n1,a.go,8,1\n2,b.go,1,0\n3,d.go,1,0\n4,e.go,4,2\n",
+		"coverage_leaderboard_20260110_093000.csv": "Rank,Path,CoveragePercent\n1,a.go,50.00\n",
+		"coverage_leaderboard_20260117_093000.csv": "Rank,Path,CoveragePercent\n1,a.go,40.00\n",
+		"long_functions_leaderboard_20260110_093000.csv": "Rank,Path,FunctionName,StartLine,Lines\n" +
+			"1,a.go,init,1,60\n2,a.go,init,90,50\n",
+		"long_functions_leaderboard_20260117_093000.csv": "Rank,Path,FunctionName,StartLine,Lines\n1,a.go,init,1,60\n",
+		// Only logged by the later run
+		"technical_debt_leaderboard_20260117_093000.csv": "Rank,Path,TotalDebt\n1,a.go,2\n",
+	})
+	runs, err := ReadRuns(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diffs, err := DiffRuns(runs[0], runs[1], nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []types.SnapshotDiff{
+		{
+			Leaderboard: "files", Metric: "Issues",
+			Worsened:    []types.SnapshotChange{{Key: "a.go", A: 3, B: 8, Delta: 5}},
+			Improved:    []types.SnapshotChange{{Key: "b.go", A: 5, B: 1, Delta: -4}},
+			Appeared:    []types.SnapshotChange{{Key: "e.go", B: 4, Delta: 4}},
+			Disappeared: []types.SnapshotChange{{Key: "c.go", A: 2, Delta: -2}},
+		},
+		{
+			Leaderboard: "coverage", Metric: "CoveragePercent", HigherIsBetter: true,
+			Worsened: []types.SnapshotChange{{Key: "a.go", A: 50, B: 40, Delta: -10}},
+		},
+		// Functions logged twice are added up
+		{
+			Leaderboard: "long-functions", Metric: "Lines",
+			Improved: []types.SnapshotChange{{Key: "a.go / init", A: 110, B: 60, Delta: -50}},
+		},
+	}
+	if !reflect.DeepEqual(diffs, expected) {
+		t.Errorf("DiffRuns = %+v; expected %+v", diffs, expected)
+	}
+
+	if diffs, err := DiffRuns(runs[0], runs[1], []string{"coverage"}); err != nil || len(diffs) != 1 || diffs[0].Leaderboard != "coverage" {
+		t.Errorf("DiffRuns of coverage = %+v, %v; expected only coverage", diffs, err)
+	}
+	if _, err := DiffRuns(runs[0], runs[1], []string{"debt"}); err == nil || !strings.Contains(err.Error(), "20260110_093000 did not log the debt leaderboard") {
+		t.Errorf("Expected an error for a leaderboard the first run did not log, but got %v", err)
+	}
+	if _, err := DiffRuns(runs[0], runs[1], []string{"commits"}); err == nil || !strings.Contains(err.Error(), "cannot compare commits") {
+		t.Errorf("Expected an error for a leaderboard that cannot be compared, but got %v", err)
+	}
+}
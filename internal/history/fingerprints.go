@@ -0,0 +1,90 @@
+package history
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// The fingerprint files in a history directory. FingerprintsFile holds the
+// issues of the last logged run and is replaced by each one; BaselineFile
+// holds the issues accepted with --baseline write and takes precedence.
+const (
+	FingerprintsFile = "fingerprints.txt"
+	BaselineFile     = "baseline.txt"
+)
+
+// fingerprintsHeader starts every fingerprint file, so one can be told
+// apart from other text files.
+const fingerprintsHeader = "# codecompass issue fingerprints v1"
+
+// WriteFingerprints writes fingerprints to path, sorted, one per line.
+func WriteFingerprints(path string, fingerprints []string) error {
+	sorted := append([]string(nil), fingerprints...)
+	sort.Strings(sorted)
+
+	var content strings.Builder
+	content.WriteString(fingerprintsHeader + "\n")
+	for _, fingerprint := range sorted {
+		content.WriteString(fingerprint + "\n")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(content.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write fingerprints to %s: %w", path, err)
+	}
+	return nil
+}
+
+// ReadFingerprints reads a file written by WriteFingerprints. The result is
+// never nil, so an empty baseline can be told apart from a missing one.
+func ReadFingerprints(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	fingerprints := []string{}
+	scanner := bufio.NewScanner(file)
+	for first := true; scanner.Scan(); first = false {
+		line := strings.TrimSpace(scanner.Text())
+		if first && line != fingerprintsHeader {
+			return nil, fmt.Errorf("%s is not a fingerprint file", path)
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fingerprints = append(fingerprints, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read fingerprints from %s: %w", path, err)
+	}
+	return fingerprints, nil
+}
+
+// ReadReference returns the fingerprints new issues are told apart by: the
+// baseline in dir when there is one, otherwise those of the last logged
+// run. The path of the file read is returned with them. Both are empty when
+// dir has neither.
+func ReadReference(dir string) ([]string, string, error) {
+	for _, name := range []string{BaselineFile, FingerprintsFile} {
+		path := filepath.Join(dir, name)
+		fingerprints, err := ReadFingerprints(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		return fingerprints, path, nil
+	}
+	return nil, "", nil
+}
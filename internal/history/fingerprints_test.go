@@ -0,0 +1,61 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestWriteAndReadFingerprints(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history", FingerprintsFile)
+	if err := WriteFingerprints(path, []string{"b2", "a1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	fingerprints, err := ReadFingerprints(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"a1", "b2"}; !reflect.DeepEqual(fingerprints, expected) {
+		t.Errorf("Expected %v, got %v", expected, fingerprints)
+	}
+
+	// An empty set is not a missing one
+	if err := WriteFingerprints(path, nil); err != nil {
+		t.Fatal(err)
+	}
+	if fingerprints, err := ReadFingerprints(path); err != nil || fingerprints == nil || len(fingerprints) != 0 {
+		t.Errorf("Expected an empty, non-nil set, got %v, %v", fingerprints, err)
+	}
+
+	other := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(other, []byte("hello\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadFingerprints(other); err == nil {
+		t.Error("Expected an error for a file without the fingerprint header")
+	}
+}
+
+func TestReadReference(t *testing.T) {
+	dir := t.TempDir()
+	if fingerprints, path, err := ReadReference(dir); err != nil || fingerprints != nil || path != "" {
+		t.Fatalf("Expected no reference in an empty directory, got %v, %q, %v", fingerprints, path, err)
+	}
+
+	if err := WriteFingerprints(filepath.Join(dir, FingerprintsFile), []string{"run"}); err != nil {
+		t.Fatal(err)
+	}
+	if fingerprints, path, err := ReadReference(dir); err != nil || !reflect.DeepEqual(fingerprints, []string{"run"}) || filepath.Base(path) != FingerprintsFile {
+		t.Errorf("Expected the last run, got %v, %q, %v", fingerprints, path, err)
+	}
+
+	// The baseline takes precedence over the last run
+	if err := WriteFingerprints(filepath.Join(dir, BaselineFile), []string{"accepted"}); err != nil {
+		t.Fatal(err)
+	}
+	if fingerprints, path, err := ReadReference(dir); err != nil || !reflect.DeepEqual(fingerprints, []string{"accepted"}) || filepath.Base(path) != BaselineFile {
+		t.Errorf("Expected the baseline, got %v, %q, %v", fingerprints, path, err)
+	}
+}
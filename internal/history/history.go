@@ -2,16 +2,97 @@ package history
 
 import (
 	"encoding/csv"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
 	"time"
 
-	"codecompass/internal/types"
+	"github.com/xeon-zolt/codecompass/internal/config"
+	"github.com/xeon-zolt/codecompass/internal/types"
+	"github.com/xeon-zolt/codecompass/internal/vulns"
 )
 
+// plainNumber matches signed decimal numbers, which spreadsheets read as
+// values rather than formulas.
+var plainNumber = regexp.MustCompile(`^[+-]?[0-9]+(\.[0-9]+)?$`)
+
+// Writer writes leaderboard CSV files into a directory.
+type Writer struct {
+	// Dir is the directory CSV files are created in.
+	Dir string
+
+	// Sanitize defangs cells that spreadsheet applications would evaluate
+	// as formulas with a leading single quote. Author names and emails come
+	// straight from commits, so NewWriter turns it on.
+	Sanitize bool
+
+	// Time stamps the names of the files written. WriteReport uses the time
+	// the report was generated when it is zero, and the other methods the
+	// current time.
+	Time time.Time
+}
+
+// stampLayout is the timestamp suffix of every history file name. Files
+// written for the same report share it.
+const stampLayout = "20060102_150405"
+
+// filename returns the name of the CSV file for the named leaderboard.
+func (w *Writer) filename(name string) string {
+	stamp := w.Time
+	if stamp.IsZero() {
+		stamp = time.Now()
+	}
+	return fmt.Sprintf("%s_%s.csv", name, stamp.Local().Format(stampLayout))
+}
+
+// NewWriter returns a Writer for dir that sanitizes cells.
+func NewWriter(dir string) *Writer {
+	return &Writer{Dir: dir, Sanitize: true}
+}
+
+// sanitizeCell prefixes values starting with a formula trigger character with
+// a single quote. Plain numbers such as negative counts are left untouched.
+// Values that already look sanitized are quoted again, so unsanitizeCell
+// reads every value back as it was.
+func sanitizeCell(value string) string {
+	if formulaLike(value) || sanitized(value) {
+		return "'" + value
+	}
+	return value
+}
+
+// unsanitizeCell undoes sanitizeCell, leaving any other leading quote alone.
+func unsanitizeCell(cell string) string {
+	if sanitized(cell) {
+		return cell[1:]
+	}
+	return cell
+}
+
+// formulaLike reports whether value starts with a formula trigger character
+// and is not a plain number.
+func formulaLike(value string) bool {
+	return value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) && !plainNumber.MatchString(value)
+}
+
+// sanitized reports whether cell is a value sanitizeCell quoted.
+func sanitized(cell string) bool {
+	return len(cell) > 1 && cell[0] == '\'' && (formulaLike(cell[1:]) || sanitized(cell[1:]))
+}
+
+// normalizePath converts Windows path separators to forward slashes so history
+// files can be joined across platforms.
+func normalizePath(path string) string {
+	return strings.ReplaceAll(path, "\\", "/")
+}
+
 // WriteLeaderboardToCSV writes a generic leaderboard to a CSV file.
-func WriteLeaderboardToCSV(dir, filename string, header []string, data [][]string) error {
+func (w *Writer) WriteLeaderboardToCSV(filename string, header []string, data [][]string) error {
+	dir := w.Dir
 	if dir == "" {
 		return fmt.Errorf("log directory not specified")
 	}
@@ -35,7 +116,14 @@ func WriteLeaderboardToCSV(dir, filename string, header []string, data [][]strin
 	}
 
 	for _, row := range data {
-		if err := writer.Write(row); err != nil {
+		sanitized := make([]string, len(row))
+		for i, cell := range row {
+			if w.Sanitize {
+				cell = sanitizeCell(cell)
+			}
+			sanitized[i] = cell
+		}
+		if err := writer.Write(sanitized); err != nil {
 			return fmt.Errorf("failed to write CSV row: %w", err)
 		}
 	}
@@ -44,9 +132,9 @@ func WriteLeaderboardToCSV(dir, filename string, header []string, data [][]strin
 }
 
 // WriteAuthorLeaderboardCSV writes the author leaderboard to a CSV file.
-func WriteAuthorLeaderboardCSV(dir string, entries []types.LeaderboardEntry) error {
-	filename := fmt.Sprintf("author_leaderboard_%s.csv", time.Now().Format("20060102_150405"))
-	header := []string{"Rank", "Name", "Email", "Issues", "Errors", "Warnings", "Files", "TopRule", "TopRuleCount"}
+func (w *Writer) WriteAuthorLeaderboardCSV(entries []types.LeaderboardEntry) error {
+	filename := w.filename("author_leaderboard")
+	header := []string{"Rank", "Name", "Email", "Issues", "Errors", "Warnings", "Files", "TopRule", "TopRuleCount", "DecayedScore", "WeightedScore"}
 	data := make([][]string, len(entries))
 	for i, entry := range entries {
 		data[i] = []string{
@@ -59,66 +147,134 @@ func WriteAuthorLeaderboardCSV(dir string, entries []types.LeaderboardEntry) err
 			fmt.Sprintf("%d", entry.Files),
 			entry.TopRule,
 			fmt.Sprintf("%d", entry.TopCount),
+			fmt.Sprintf("%.2f", entry.DecayedScore),
+			fmt.Sprintf("%.2f", entry.WeightedScore),
 		}
 	}
-	return WriteLeaderboardToCSV(dir, filename, header, data)
+	return w.WriteLeaderboardToCSV(filename, header, data)
 }
 
 // WriteFileLeaderboardCSV writes the file leaderboard to a CSV file.
-func WriteFileLeaderboardCSV(dir string, entries []types.FileLeaderboardEntry) error {
-	filename := fmt.Sprintf("file_leaderboard_%s.csv", time.Now().Format("20060102_150405"))
-	header := []string{"Rank", "Path", "Issues", "Authors", "TopRule", "TopRuleCount"}
+func (w *Writer) WriteFileLeaderboardCSV(entries []types.FileLeaderboardEntry) error {
+	filename := w.filename("file_leaderboard")
+	header := []string{"Rank", "Path", "Issues", "Errors", "Warnings", "Authors", "TopRule", "TopRuleCount", "Overflow", "DecayedScore", "WeightedScore"}
 	data := make([][]string, len(entries))
 	for i, entry := range entries {
 		data[i] = []string{
 			fmt.Sprintf("%d", entry.Rank),
-			entry.Path,
+			normalizePath(entry.Path),
 			fmt.Sprintf("%d", entry.Count),
+			fmt.Sprintf("%d", entry.Errors),
+			fmt.Sprintf("%d", entry.Warnings),
 			fmt.Sprintf("%d", entry.Authors),
 			entry.TopRule,
 			fmt.Sprintf("%d", entry.TopCount),
+			fmt.Sprintf("%d", entry.Overflow),
+			fmt.Sprintf("%.2f", entry.DecayedScore),
+			fmt.Sprintf("%.2f", entry.WeightedScore),
 		}
 	}
-	return WriteLeaderboardToCSV(dir, filename, header, data)
+	return w.WriteLeaderboardToCSV(filename, header, data)
 }
 
 // WriteRuleLeaderboardCSV writes the rule leaderboard to a CSV file.
-func WriteRuleLeaderboardCSV(dir string, entries []types.RuleLeaderboardEntry) error {
-	filename := fmt.Sprintf("rule_leaderboard_%s.csv", time.Now().Format("20060102_150405"))
-	header := []string{"Rank", "Rule", "Violations", "Authors", "Files"}
+func (w *Writer) WriteRuleLeaderboardCSV(entries []types.RuleLeaderboardEntry) error {
+	return w.writeRuleLeaderboardCSV("rule_leaderboard", entries)
+}
+
+func (w *Writer) writeRuleLeaderboardCSV(name string, entries []types.RuleLeaderboardEntry) error {
+	filename := w.filename(name)
+	header := []string{"Rank", "Rule", "Violations", "Fixable", "Authors", "Files"}
 	data := make([][]string, len(entries))
 	for i, entry := range entries {
 		data[i] = []string{
 			fmt.Sprintf("%d", entry.Rank),
 			entry.Rule,
 			fmt.Sprintf("%d", entry.Count),
+			fmt.Sprintf("%d", entry.Fixable),
 			fmt.Sprintf("%d", entry.Authors),
 			fmt.Sprintf("%d", entry.Files),
 		}
 	}
-	return WriteLeaderboardToCSV(dir, filename, header, data)
+	return w.WriteLeaderboardToCSV(filename, header, data)
+}
+
+// WriteRulePluginLeaderboardCSV writes the rule leaderboard rolled up by
+// plugin to a CSV file.
+func (w *Writer) WriteRulePluginLeaderboardCSV(entries []types.RulePluginEntry) error {
+	filename := w.filename("rule_plugin_leaderboard")
+	header := []string{"Rank", "Plugin", "Violations", "DistinctRules"}
+	data := make([][]string, len(entries))
+	for i, entry := range entries {
+		data[i] = []string{
+			fmt.Sprintf("%d", entry.Rank),
+			entry.Plugin,
+			fmt.Sprintf("%d", entry.Count),
+			fmt.Sprintf("%d", entry.DistinctRules),
+		}
+	}
+	return w.WriteLeaderboardToCSV(filename, header, data)
+}
+
+// WriteRuleGroupLeaderboardCSV writes the rule leaderboard rolled up by
+// rule group to a CSV file.
+func (w *Writer) WriteRuleGroupLeaderboardCSV(entries []types.RuleGroupEntry) error {
+	filename := w.filename("rule_group_leaderboard")
+	header := []string{"Rank", "Group", "Violations", "DistinctRules"}
+	data := make([][]string, len(entries))
+	for i, entry := range entries {
+		data[i] = []string{
+			fmt.Sprintf("%d", entry.Rank),
+			entry.Group,
+			fmt.Sprintf("%d", entry.Count),
+			fmt.Sprintf("%d", entry.DistinctRules),
+		}
+	}
+	return w.WriteLeaderboardToCSV(filename, header, data)
+}
+
+// WriteRuleAuthorMatrixCSV writes the cells of the rule by author cross-tab
+// to a CSV file.
+func (w *Writer) WriteRuleAuthorMatrixCSV(cells []types.RuleAuthorCell) error {
+	filename := w.filename("rule_author_matrix")
+	header := []string{"Rule", "Author", "Email", "Violations"}
+	data := make([][]string, len(cells))
+	for i, c := range cells {
+		data[i] = []string{
+			c.Rule,
+			c.Name,
+			c.Email,
+			fmt.Sprintf("%d", c.Count),
+		}
+	}
+	return w.WriteLeaderboardToCSV(filename, header, data)
+}
+
+// WriteRuffRuleLeaderboardCSV writes the Ruff rule leaderboard to a CSV file.
+func (w *Writer) WriteRuffRuleLeaderboardCSV(entries []types.RuleLeaderboardEntry) error {
+	return w.writeRuleLeaderboardCSV("ruff_rule_leaderboard", entries)
 }
 
 // WriteLinesOfCodeLeaderboardCSV writes the lines of code leaderboard to a CSV file.
-func WriteLinesOfCodeLeaderboardCSV(dir string, entries []types.LinesOfCodeEntry) error {
-	filename := fmt.Sprintf("loc_leaderboard_%s.csv", time.Now().Format("20060102_150405"))
+func (w *Writer) WriteLinesOfCodeLeaderboardCSV(entries []types.LinesOfCodeEntry) error {
+	filename := w.filename("loc_leaderboard")
 	header := []string{"Rank", "Path", "Lines", "Size"}
 	data := make([][]string, len(entries))
 	for i, entry := range entries {
 		data[i] = []string{
 			fmt.Sprintf("%d", entry.Rank),
-			entry.Path,
+			normalizePath(entry.Path),
 			fmt.Sprintf("%d", entry.Lines),
 			fmt.Sprintf("%d", entry.Size),
 		}
 	}
-	return WriteLeaderboardToCSV(dir, filename, header, data)
+	return w.WriteLeaderboardToCSV(filename, header, data)
 }
 
 // WriteCommitCountLeaderboardCSV writes the commit count leaderboard to a CSV file.
-func WriteCommitCountLeaderboardCSV(dir string, entries []types.CommitCountEntry) error {
-	filename := fmt.Sprintf("commit_count_leaderboard_%s.csv", time.Now().Format("20060102_150405"))
-	header := []string{"Rank", "Name", "Email", "Commits", "FirstCommit", "LastCommit"}
+func (w *Writer) WriteCommitCountLeaderboardCSV(entries []types.CommitCountEntry) error {
+	filename := w.filename("commit_count_leaderboard")
+	header := []string{"Rank", "Name", "Email", "Commits", "FirstCommit", "LastCommit", "CoAuthored", "Credit"}
 	data := make([][]string, len(entries))
 	for i, entry := range entries {
 		data[i] = []string{
@@ -128,14 +284,16 @@ func WriteCommitCountLeaderboardCSV(dir string, entries []types.CommitCountEntry
 			fmt.Sprintf("%d", entry.Commits),
 			entry.FirstCommit.Format("2006-01-02"),
 			entry.LastCommit.Format("2006-01-02"),
+			fmt.Sprintf("%d", entry.CoAuthored),
+			fmt.Sprintf("%.2f", entry.Credit),
 		}
 	}
-	return WriteLeaderboardToCSV(dir, filename, header, data)
+	return w.WriteLeaderboardToCSV(filename, header, data)
 }
 
 // WriteRecentContributorsLeaderboardCSV writes the recent contributors leaderboard to a CSV file.
-func WriteRecentContributorsLeaderboardCSV(dir string, entries []types.RecentContributorEntry) error {
-	filename := fmt.Sprintf("recent_contributors_leaderboard_%s.csv", time.Now().Format("20060102_150405"))
+func (w *Writer) WriteRecentContributorsLeaderboardCSV(entries []types.RecentContributorEntry) error {
+	filename := w.filename("recent_contributors_leaderboard")
 	header := []string{"Rank", "Name", "Email", "RecentCommits", "LastCommit"}
 	data := make([][]string, len(entries))
 	for i, entry := range entries {
@@ -147,18 +305,38 @@ func WriteRecentContributorsLeaderboardCSV(dir string, entries []types.RecentCon
 			entry.LastCommit.Format("2006-01-02"),
 		}
 	}
-	return WriteLeaderboardToCSV(dir, filename, header, data)
+	return w.WriteLeaderboardToCSV(filename, header, data)
+}
+
+// WriteNewContributorLeaderboardCSV writes the authors whose first commit
+// falls in the window to a CSV file.
+func (w *Writer) WriteNewContributorLeaderboardCSV(entries []types.NewContributorEntry) error {
+	filename := w.filename("new_contributors_leaderboard")
+	header := []string{"Rank", "Name", "Email", "FirstCommit", "Commits", "FilesTouched", "GracedIssues"}
+	data := make([][]string, len(entries))
+	for i, entry := range entries {
+		data[i] = []string{
+			fmt.Sprintf("%d", entry.Rank),
+			entry.Name,
+			entry.Email,
+			entry.FirstCommit.Format("2006-01-02"),
+			fmt.Sprintf("%d", entry.Commits),
+			fmt.Sprintf("%d", entry.FilesTouched),
+			fmt.Sprintf("%d", entry.GracedIssues),
+		}
+	}
+	return w.WriteLeaderboardToCSV(filename, header, data)
 }
 
 // WriteCodeCoverageLeaderboardCSV writes the code coverage leaderboard to a CSV file.
-func WriteCodeCoverageLeaderboardCSV(dir string, entries []types.CoverageEntry) error {
-	filename := fmt.Sprintf("coverage_leaderboard_%s.csv", time.Now().Format("20060102_150405"))
+func (w *Writer) WriteCodeCoverageLeaderboardCSV(entries []types.CoverageEntry) error {
+	filename := w.filename("coverage_leaderboard")
 	header := []string{"Rank", "Path", "LinesCovered", "LinesTotal", "CoveragePercent", "FunctionsCovered", "FunctionsTotal", "BranchesCovered", "BranchesTotal"}
 	data := make([][]string, len(entries))
 	for i, entry := range entries {
 		data[i] = []string{
 			fmt.Sprintf("%d", entry.Rank),
-			entry.Path,
+			normalizePath(entry.Path),
 			fmt.Sprintf("%d", entry.LinesCovered),
 			fmt.Sprintf("%d", entry.LinesTotal),
 			fmt.Sprintf("%.2f", entry.CoveragePercent),
@@ -168,75 +346,679 @@ func WriteCodeCoverageLeaderboardCSV(dir string, entries []types.CoverageEntry)
 			fmt.Sprintf("%d", entry.BranchesTotal),
 		}
 	}
-	return WriteLeaderboardToCSV(dir, filename, header, data)
+	return w.WriteLeaderboardToCSV(filename, header, data)
 }
 
 // WriteCodeChurnLeaderboardCSV writes the code churn leaderboard to a CSV file.
-func WriteCodeChurnLeaderboardCSV(dir string, entries []types.ChurnEntry) error {
-	filename := fmt.Sprintf("churn_leaderboard_%s.csv", time.Now().Format("20060102_150405"))
-	header := []string{"Rank", "Path", "Changes", "AddedLines", "DeletedLines", "NetLines"}
+func (w *Writer) WriteCodeChurnLeaderboardCSV(entries []types.ChurnEntry) error {
+	filename := w.filename("churn_leaderboard")
+	header := []string{"Rank", "Path", "Changes", "AddedLines", "DeletedLines", "NetLines", "FirstCommit", "ChurnRate"}
 	data := make([][]string, len(entries))
 	for i, entry := range entries {
+		var firstCommit string
+		if !entry.FirstCommit.IsZero() {
+			firstCommit = entry.FirstCommit.Format("2006-01-02")
+		}
 		data[i] = []string{
 			fmt.Sprintf("%d", entry.Rank),
-			entry.Path,
+			normalizePath(entry.Path),
 			fmt.Sprintf("%d", entry.Changes),
 			fmt.Sprintf("%d", entry.AddedLines),
 			fmt.Sprintf("%d", entry.DeletedLines),
 			fmt.Sprintf("%d", entry.NetLines),
+			firstCommit,
+			fmt.Sprintf("%.2f", entry.ChurnRate),
 		}
 	}
-	return WriteLeaderboardToCSV(dir, filename, header, data)
+	return w.WriteLeaderboardToCSV(filename, header, data)
+}
+
+// WriteHotspotLeaderboardCSV writes the hotspot leaderboard to a CSV file.
+func (w *Writer) WriteHotspotLeaderboardCSV(entries []types.HotspotEntry) error {
+	filename := w.filename("hotspot_leaderboard")
+	header := []string{"Rank", "Path", "Changes", "Issues", "HotspotScore"}
+	data := make([][]string, len(entries))
+	for i, entry := range entries {
+		data[i] = []string{
+			fmt.Sprintf("%d", entry.Rank),
+			normalizePath(entry.Path),
+			fmt.Sprintf("%d", entry.Changes),
+			fmt.Sprintf("%d", entry.Issues),
+			fmt.Sprintf("%d", entry.HotspotScore),
+		}
+	}
+	return w.WriteLeaderboardToCSV(filename, header, data)
 }
 
 // WriteBugDensityLeaderboardCSV writes the bug density leaderboard to a CSV file.
-func WriteBugDensityLeaderboardCSV(dir string, entries []types.BugDensityEntry) error {
-	filename := fmt.Sprintf("bug_density_leaderboard_%s.csv", time.Now().Format("20060102_150405"))
+func (w *Writer) WriteBugDensityLeaderboardCSV(entries []types.BugDensityEntry) error {
+	filename := w.filename("bug_density_leaderboard")
 	header := []string{"Rank", "Path", "BugFixes", "TotalCommits", "BugRatio"}
 	data := make([][]string, len(entries))
 	for i, entry := range entries {
 		data[i] = []string{
 			fmt.Sprintf("%d", entry.Rank),
-			entry.Path,
+			normalizePath(entry.Path),
 			fmt.Sprintf("%d", entry.BugFixes),
 			fmt.Sprintf("%d", entry.TotalCommits),
 			fmt.Sprintf("%.4f", entry.BugRatio),
 		}
 	}
-	return WriteLeaderboardToCSV(dir, filename, header, data)
+	return w.WriteLeaderboardToCSV(filename, header, data)
 }
 
 // WriteTechnicalDebtLeaderboardCSV writes the technical debt leaderboard to a CSV file.
-func WriteTechnicalDebtLeaderboardCSV(dir string, entries []types.TechnicalDebtEntry) error {
-	filename := fmt.Sprintf("technical_debt_leaderboard_%s.csv", time.Now().Format("20060102_150405"))
+func (w *Writer) WriteTechnicalDebtLeaderboardCSV(entries []types.TechnicalDebtEntry) error {
+	filename := w.filename("technical_debt_leaderboard")
 	header := []string{"Rank", "Path", "TodoCount", "FixmeCount", "HackCount", "TotalDebt"}
 	data := make([][]string, len(entries))
 	for i, entry := range entries {
 		data[i] = []string{
 			fmt.Sprintf("%d", entry.Rank),
-			entry.Path,
+			normalizePath(entry.Path),
 			fmt.Sprintf("%d", entry.TodoCount),
 			fmt.Sprintf("%d", entry.FixmeCount),
 			fmt.Sprintf("%d", entry.HackCount),
 			fmt.Sprintf("%d", entry.TotalDebt),
 		}
 	}
-	return WriteLeaderboardToCSV(dir, filename, header, data)
+	return w.WriteLeaderboardToCSV(filename, header, data)
 }
 
 // WriteSpellCheckLeaderboardCSV writes the spell check leaderboard to a CSV file.
-func WriteSpellCheckLeaderboardCSV(dir string, entries []types.SpellCheckEntry) error {
-	filename := fmt.Sprintf("spell_check_leaderboard_%s.csv", time.Now().Format("20060102_150405"))
+func (w *Writer) WriteSpellCheckLeaderboardCSV(entries []types.SpellCheckEntry) error {
+	filename := w.filename("spell_check_leaderboard")
 	header := []string{"Rank", "Path", "MisspelledWords", "TotalWords", "ErrorRate"}
 	data := make([][]string, len(entries))
 	for i, entry := range entries {
 		data[i] = []string{
 			fmt.Sprintf("%d", entry.Rank),
-			entry.Path,
+			normalizePath(entry.Path),
 			fmt.Sprintf("%d", entry.MisspelledWords),
 			fmt.Sprintf("%d", entry.TotalWords),
 			fmt.Sprintf("%.4f", entry.ErrorRate),
 		}
 	}
-	return WriteLeaderboardToCSV(dir, filename, header, data)
+	return w.WriteLeaderboardToCSV(filename, header, data)
+}
+
+// WriteEncodingLeaderboardCSV writes the encoding leaderboard to a CSV file.
+func (w *Writer) WriteEncodingLeaderboardCSV(entries []types.EncodingEntry) error {
+	filename := w.filename("encoding_leaderboard")
+	header := []string{"Rank", "Path", "LineEnding", "HasBOM", "Encoding"}
+	data := make([][]string, len(entries))
+	for i, entry := range entries {
+		data[i] = []string{
+			fmt.Sprintf("%d", entry.Rank),
+			normalizePath(entry.Path),
+			entry.LineEnding,
+			fmt.Sprintf("%t", entry.HasBOM),
+			entry.Encoding,
+		}
+	}
+	return w.WriteLeaderboardToCSV(filename, header, data)
+}
+
+// WriteLongFunctionLeaderboardCSV writes the long function leaderboard to a
+// CSV file.
+func (w *Writer) WriteLongFunctionLeaderboardCSV(entries []types.LongFunctionEntry) error {
+	filename := w.filename("long_functions_leaderboard")
+	header := []string{"Rank", "Path", "FunctionName", "StartLine", "Lines"}
+	data := make([][]string, len(entries))
+	for i, entry := range entries {
+		data[i] = []string{
+			fmt.Sprintf("%d", entry.Rank),
+			normalizePath(entry.Path),
+			entry.FunctionName,
+			fmt.Sprintf("%d", entry.StartLine),
+			fmt.Sprintf("%d", entry.Lines),
+		}
+	}
+	return w.WriteLeaderboardToCSV(filename, header, data)
+}
+
+// WriteFormatDriftLeaderboardCSV writes the files ruff would rewrite to a
+// CSV file.
+func (w *Writer) WriteFormatDriftLeaderboardCSV(entries []types.FormatDriftEntry) error {
+	filename := w.filename("format_drift_leaderboard")
+	header := []string{"Rank", "Path", "Lines", "FormatLines", "ImportLines"}
+	data := make([][]string, len(entries))
+	for i, entry := range entries {
+		data[i] = []string{
+			fmt.Sprintf("%d", entry.Rank),
+			normalizePath(entry.Path),
+			fmt.Sprintf("%d", entry.Lines),
+			fmt.Sprintf("%d", entry.FormatLines),
+			fmt.Sprintf("%d", entry.ImportLines),
+		}
+	}
+	return w.WriteLeaderboardToCSV(filename, header, data)
+}
+
+// WriteFormatDriftAuthorsCSV writes the authors of the lines ruff would
+// change to a CSV file.
+func (w *Writer) WriteFormatDriftAuthorsCSV(entries []types.FormatDriftAuthorEntry) error {
+	filename := w.filename("format_drift_authors")
+	header := []string{"Rank", "Name", "Email", "Lines", "Files"}
+	data := make([][]string, len(entries))
+	for i, entry := range entries {
+		data[i] = []string{
+			fmt.Sprintf("%d", entry.Rank),
+			entry.Name,
+			entry.Email,
+			fmt.Sprintf("%d", entry.Lines),
+			fmt.Sprintf("%d", entry.Files),
+		}
+	}
+	return w.WriteLeaderboardToCSV(filename, header, data)
+}
+
+// WritePullRequestLeaderboardCSV writes the merged pull requests per author
+// to a CSV file.
+func (w *Writer) WritePullRequestLeaderboardCSV(entries []types.PullRequestAuthorEntry) error {
+	filename := w.filename("pull_request_leaderboard")
+	header := []string{"Rank", "Login", "Merged", "AvgSize", "AvgHoursToMerge"}
+	data := make([][]string, len(entries))
+	for i, entry := range entries {
+		data[i] = []string{
+			fmt.Sprintf("%d", entry.Rank),
+			entry.Login,
+			fmt.Sprintf("%d", entry.Merged),
+			fmt.Sprintf("%.1f", entry.AvgSize),
+			fmt.Sprintf("%.2f", entry.AvgTimeToMerge.Hours()),
+		}
+	}
+	return w.WriteLeaderboardToCSV(filename, header, data)
+}
+
+// WriteReviewerLeaderboardCSV writes the reviews given per reviewer to a CSV
+// file.
+func (w *Writer) WriteReviewerLeaderboardCSV(entries []types.ReviewerEntry) error {
+	filename := w.filename("reviewer_leaderboard")
+	header := []string{"Rank", "Login", "Reviews", "Approvals", "PullRequests"}
+	data := make([][]string, len(entries))
+	for i, entry := range entries {
+		data[i] = []string{
+			fmt.Sprintf("%d", entry.Rank),
+			entry.Login,
+			fmt.Sprintf("%d", entry.Reviews),
+			fmt.Sprintf("%d", entry.Approvals),
+			fmt.Sprintf("%d", entry.PullRequests),
+		}
+	}
+	return w.WriteLeaderboardToCSV(filename, header, data)
+}
+
+// WriteLeadTimeLeaderboardCSV writes the branch lead time per author to a
+// CSV file.
+func (w *Writer) WriteLeadTimeLeaderboardCSV(entries []types.LeadTimeAuthorEntry) error {
+	filename := w.filename("lead_time_leaderboard")
+	header := []string{"Rank", "Name", "Email", "Merges", "MedianHours", "P90Hours"}
+	data := make([][]string, len(entries))
+	for i, entry := range entries {
+		data[i] = []string{
+			fmt.Sprintf("%d", entry.Rank),
+			entry.Name,
+			entry.Email,
+			fmt.Sprintf("%d", entry.Merges),
+			fmt.Sprintf("%.2f", entry.Median.Hours()),
+			fmt.Sprintf("%.2f", entry.P90.Hours()),
+		}
+	}
+	return w.WriteLeaderboardToCSV(filename, header, data)
+}
+
+// WriteMergeCadenceCSV writes the merges per week to a CSV file.
+func (w *Writer) WriteMergeCadenceCSV(weeks []types.WeeklyMerges) error {
+	filename := w.filename("merge_cadence")
+	header := []string{"Week", "Merges"}
+	data := make([][]string, len(weeks))
+	for i, week := range weeks {
+		data[i] = []string{
+			week.Week.Format("2006-01-02"),
+			fmt.Sprintf("%d", week.Merges),
+		}
+	}
+	return w.WriteLeaderboardToCSV(filename, header, data)
+}
+
+// changelogColumns are the changelog types written as columns of the
+// changelog readiness CSV, with their column names.
+var changelogColumns = []struct {
+	kind   string
+	column string
+}{
+	{"feat", "Feat"}, {"fix", "Fix"}, {"chore", "Chore"}, {"docs", "Docs"}, {"refactor", "Refactor"}, {"other", "Other"},
+}
+
+// WriteChangelogLeaderboardCSV writes each author's commits by changelog
+// type to a CSV file.
+func (w *Writer) WriteChangelogLeaderboardCSV(entries []types.ChangelogAuthorEntry) error {
+	filename := w.filename("changelog_leaderboard")
+	header := []string{"Rank", "Name", "Email", "Commits"}
+	for _, column := range changelogColumns {
+		header = append(header, column.column)
+	}
+	header = append(header, "ConventionalPercent")
+	data := make([][]string, len(entries))
+	for i, entry := range entries {
+		row := []string{
+			fmt.Sprintf("%d", entry.Rank),
+			entry.Name,
+			entry.Email,
+			fmt.Sprintf("%d", entry.Commits),
+		}
+		for _, column := range changelogColumns {
+			row = append(row, fmt.Sprintf("%d", entry.Types[column.kind]))
+		}
+		data[i] = append(row, fmt.Sprintf("%.2f", entry.ConventionalPercent))
+	}
+	return w.WriteLeaderboardToCSV(filename, header, data)
+}
+
+// WriteChangelogCommitsCSV writes commits listed by the changelog readiness
+// report, the invisible or the breaking ones, to a CSV file.
+func (w *Writer) WriteChangelogCommitsCSV(name string, commits []types.ChangelogCommit) error {
+	filename := w.filename(name)
+	header := []string{"Hash", "Name", "Email", "Date", "Type", "Scope", "Subject", "Note"}
+	data := make([][]string, len(commits))
+	for i, commit := range commits {
+		data[i] = []string{
+			commit.Hash,
+			commit.Name,
+			commit.Email,
+			commit.Date.UTC().Format(time.RFC3339),
+			commit.Type,
+			commit.Scope,
+			commit.Subject,
+			commit.Note,
+		}
+	}
+	return w.WriteLeaderboardToCSV(filename, header, data)
+}
+
+// WriteTimezoneLeaderboardCSV writes each author's UTC offset and commit
+// time shares to a CSV file.
+func (w *Writer) WriteTimezoneLeaderboardCSV(entries []types.TimezoneAuthorEntry) error {
+	filename := w.filename("timezone_leaderboard")
+	header := []string{"Rank", "Name", "Email", "Commits", "UTCOffset", "WeekendShare", "OffHoursShare"}
+	data := make([][]string, len(entries))
+	for i, entry := range entries {
+		data[i] = []string{
+			fmt.Sprintf("%d", entry.Rank),
+			entry.Name,
+			entry.Email,
+			fmt.Sprintf("%d", entry.Commits),
+			time.Unix(0, 0).In(time.FixedZone("", entry.Offset)).Format("-07:00"),
+			fmt.Sprintf("%.1f", entry.WeekendShare),
+			fmt.Sprintf("%.1f", entry.OffHoursShare),
+		}
+	}
+	return w.WriteLeaderboardToCSV(filename, header, data)
+}
+
+// WriteDeletionLeaderboardCSV writes the authors who removed more lines
+// than they added to a CSV file.
+func (w *Writer) WriteDeletionLeaderboardCSV(entries []types.DeletionAuthorEntry) error {
+	filename := w.filename("deletion_leaderboard")
+	header := []string{"Rank", "Name", "Email", "Commits", "DeletedLines", "AddedLines", "NetRemoved"}
+	data := make([][]string, len(entries))
+	for i, entry := range entries {
+		data[i] = []string{
+			fmt.Sprintf("%d", entry.Rank),
+			entry.Name,
+			entry.Email,
+			fmt.Sprintf("%d", entry.Commits),
+			fmt.Sprintf("%d", entry.DeletedLines),
+			fmt.Sprintf("%d", entry.AddedLines),
+			fmt.Sprintf("%d", entry.NetRemoved),
+		}
+	}
+	return w.WriteLeaderboardToCSV(filename, header, data)
+}
+
+// WriteCleanupCommitsCSV writes the commits that removed more lines than
+// they added to a CSV file.
+func (w *Writer) WriteCleanupCommitsCSV(commits []types.CleanupCommit) error {
+	filename := w.filename("cleanup_commits")
+	header := []string{"Hash", "Name", "Email", "Date", "DeletedLines", "AddedLines", "NetRemoved", "Subject"}
+	data := make([][]string, len(commits))
+	for i, commit := range commits {
+		data[i] = []string{
+			commit.Hash,
+			commit.Name,
+			commit.Email,
+			commit.Date.UTC().Format(time.RFC3339),
+			fmt.Sprintf("%d", commit.DeletedLines),
+			fmt.Sprintf("%d", commit.AddedLines),
+			fmt.Sprintf("%d", commit.NetRemoved),
+			commit.Subject,
+		}
+	}
+	return w.WriteLeaderboardToCSV(filename, header, data)
+}
+
+// WriteWorkspaceLeaderboardCSV writes the per-package roll-up of the
+// file-based leaderboards to a CSV file.
+func (w *Writer) WriteWorkspaceLeaderboardCSV(entries []types.WorkspaceEntry) error {
+	filename := w.filename("workspace_leaderboard")
+	header := []string{"Name", "Path", "Kind", "Files", "LinesOfCode", "Issues", "Debt", "LinesCovered", "LinesTotal"}
+	data := make([][]string, len(entries))
+	for i, entry := range entries {
+		data[i] = []string{
+			entry.Name,
+			entry.Path,
+			entry.Kind,
+			fmt.Sprintf("%d", entry.Files),
+			fmt.Sprintf("%d", entry.LinesOfCode),
+			fmt.Sprintf("%d", entry.Issues),
+			fmt.Sprintf("%d", entry.Debt),
+			fmt.Sprintf("%d", entry.LinesCovered),
+			fmt.Sprintf("%d", entry.LinesTotal),
+		}
+	}
+	return w.WriteLeaderboardToCSV(filename, header, data)
+}
+
+// WriteLFSViolationsCSV writes the files committed as raw blobs where LFS
+// pointers were expected to a CSV file.
+func (w *Writer) WriteLFSViolationsCSV(entries []types.LFSViolation) error {
+	filename := w.filename("lfs_violations")
+	header := []string{"Path", "Size", "Commit", "Author", "Email", "Date"}
+	data := make([][]string, len(entries))
+	for i, entry := range entries {
+		data[i] = []string{
+			normalizePath(entry.Path),
+			fmt.Sprintf("%d", entry.Size),
+			entry.Commit,
+			entry.Author,
+			entry.Email,
+			entry.Date.UTC().Format(time.RFC3339),
+		}
+	}
+	return w.WriteLeaderboardToCSV(filename, header, data)
+}
+
+// WriteVulnerabilityLeaderboardCSV writes the known vulnerabilities in
+// dependencies to a CSV file.
+func (w *Writer) WriteVulnerabilityLeaderboardCSV(entries []types.VulnEntry) error {
+	filename := w.filename("vulnerability_leaderboard")
+	header := []string{"Ecosystem", "Package", "InstalledVersion", "Severity", "AdvisoryID", "FixedIn", "Direct"}
+	data := make([][]string, len(entries))
+	for i, entry := range entries {
+		data[i] = []string{
+			entry.Ecosystem,
+			entry.Package,
+			entry.InstalledVersion,
+			entry.Severity,
+			entry.AdvisoryID,
+			entry.FixedIn,
+			strings.Join(entry.Direct, ";"),
+		}
+	}
+	return w.WriteLeaderboardToCSV(filename, header, data)
+}
+
+// WriteVulnerabilityCountsCSV writes the number of vulnerabilities of each
+// severity to a CSV file, so they can be trended across runs.
+func (w *Writer) WriteVulnerabilityCountsCSV(entries []types.VulnEntry) error {
+	filename := w.filename("vulnerability_counts")
+	header := []string{"Severity", "Count"}
+	counts := vulns.Counts(entries)
+	data := make([][]string, len(vulns.Severities))
+	for i, severity := range vulns.Severities {
+		data[i] = []string{severity, fmt.Sprintf("%d", counts[severity])}
+	}
+	return w.WriteLeaderboardToCSV(filename, header, data)
+}
+
+// WriteDependencyLeaderboardCSV writes the dependency counts of each
+// lockfile and requirements file to a CSV file.
+func (w *Writer) WriteDependencyLeaderboardCSV(entries []types.DependencyEntry) error {
+	filename := w.filename("dependency_leaderboard")
+	header := []string{"Rank", "Path", "Ecosystem", "Direct", "Locked", "Pinned", "Ranged", "PinnedPercent", "RangedPackages"}
+	data := make([][]string, len(entries))
+	for i, entry := range entries {
+		data[i] = []string{
+			fmt.Sprintf("%d", entry.Rank),
+			normalizePath(entry.Path),
+			entry.Ecosystem,
+			fmt.Sprintf("%d", entry.Direct),
+			fmt.Sprintf("%d", entry.Locked),
+			fmt.Sprintf("%d", entry.Pinned),
+			fmt.Sprintf("%d", entry.Ranged),
+			fmt.Sprintf("%.2f", entry.PinnedPercent),
+			strings.Join(entry.RangedPackages, ";"),
+		}
+	}
+	return w.WriteLeaderboardToCSV(filename, header, data)
+}
+
+// WriteScoreLeaderboardCSV writes the quality score of each file, worst
+// first, to a CSV file. Components not measured for a file are left empty.
+func (w *Writer) WriteScoreLeaderboardCSV(entries []types.FileScore) error {
+	filename := w.filename("score_leaderboard")
+	header := append([]string{"Rank", "Path", "Score"}, config.ScoreComponents...)
+	data := make([][]string, len(entries))
+	for i, entry := range entries {
+		row := []string{
+			fmt.Sprintf("%d", entry.Rank),
+			normalizePath(entry.Path),
+			fmt.Sprintf("%.2f", entry.Score),
+		}
+		for _, component := range config.ScoreComponents {
+			cell := ""
+			if score, ok := entry.Components[component]; ok {
+				cell = fmt.Sprintf("%.2f", score)
+			}
+			row = append(row, cell)
+		}
+		data[i] = row
+	}
+	return w.WriteLeaderboardToCSV(filename, header, data)
+}
+
+// WriteScoreComponentsCSV writes the components of the repository quality
+// score to a CSV file, so they can be trended across runs.
+func (w *Writer) WriteScoreComponentsCSV(components []types.ScoreComponent) error {
+	filename := w.filename("score_components")
+	header := []string{"Component", "Score", "Weight", "Detail"}
+	data := make([][]string, len(components))
+	for i, component := range components {
+		data[i] = []string{
+			component.Component,
+			fmt.Sprintf("%.2f", component.Score),
+			fmt.Sprintf("%.2f", component.Weight),
+			component.Detail,
+		}
+	}
+	return w.WriteLeaderboardToCSV(filename, header, data)
+}
+
+// WriteRunCSV writes the run metadata of report to a CSV file: when it was
+// generated, the repository totals trended by ReadTimeSeries, the versions
+// of the tools the run used, the commit analyzed and whether the cells of
+// the run's files were sanitized. Totals that were not measured are left
+// empty.
+func (w *Writer) WriteRunCSV(report *types.Report) error {
+	filename := w.filename("run")
+	header := []string{"GeneratedAt", "SchemaVersion", "TotalIssues", "Errors", "Warnings", "Authors", "Files", "Rules", "OverallCoverage", "TotalDebt", "Score", "ToolVersions", "Head", "Sanitized"}
+	row := make([]string, len(header))
+	row[0] = report.GeneratedAt.UTC().Format(time.RFC3339)
+	row[1] = fmt.Sprintf("%d", report.SchemaVersion)
+	if summary := report.Summary; summary != nil {
+		row[2] = fmt.Sprintf("%d", summary.TotalIssues)
+		row[3] = fmt.Sprintf("%d", summary.Errors)
+		row[4] = fmt.Sprintf("%d", summary.Warnings)
+		row[5] = fmt.Sprintf("%d", summary.Authors)
+		row[6] = fmt.Sprintf("%d", summary.Files)
+		row[7] = fmt.Sprintf("%d", summary.Rules)
+	}
+	if len(report.Coverage) > 0 {
+		row[8] = fmt.Sprintf("%.2f", report.OverallCoverage)
+	}
+	if report.Requested("debt") || len(report.TechnicalDebt) > 0 {
+		debt := 0
+		for _, entry := range report.TechnicalDebt {
+			debt += entry.TotalDebt
+		}
+		row[9] = fmt.Sprintf("%d", debt)
+	}
+	if report.Score != nil && len(report.Score.Components) > 0 {
+		row[10] = fmt.Sprintf("%.2f", report.Score.Score)
+	}
+	row[11] = formatToolVersions(report.ToolVersions)
+	row[12] = report.Repo.Head
+	row[13] = fmt.Sprintf("%t", w.Sanitize)
+	return w.WriteLeaderboardToCSV(filename, header, [][]string{row})
+}
+
+// formatToolVersions formats versions as tool=version pairs sorted by tool
+// and separated by semicolons, such as "eslint=8.57.0;git=2.43.0".
+func formatToolVersions(versions map[string]string) string {
+	pairs := make([]string, 0, len(versions))
+	for tool, version := range versions {
+		pairs = append(pairs, tool+"="+version)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ";")
+}
+
+// parseToolVersions parses the versions formatToolVersions formatted,
+// skipping malformed pairs. It returns nil for an empty value.
+func parseToolVersions(value string) map[string]string {
+	var versions map[string]string
+	for _, pair := range strings.Split(value, ";") {
+		tool, version, ok := strings.Cut(pair, "=")
+		if !ok || tool == "" {
+			continue
+		}
+		if versions == nil {
+			versions = make(map[string]string)
+		}
+		versions[tool] = version
+	}
+	return versions
+}
+
+// WriteReport writes the run metadata and a CSV file for every requested
+// leaderboard in report that has entries. Leaderboards without a CSV format,
+// such as the summary, are skipped. Every file is attempted; the errors are
+// joined.
+func (w *Writer) WriteReport(report *types.Report) error {
+	// The files of one run share the time it was generated
+	if w.Time.IsZero() {
+		stamped := *w
+		stamped.Time = report.GeneratedAt
+		w = &stamped
+	}
+
+	var errs []error
+	if err := w.WriteRunCSV(report); err != nil {
+		errs = append(errs, fmt.Errorf("failed to log run metadata: %w", err))
+	}
+	for _, writer := range w.reportFiles(report) {
+		if writer.entries == 0 || !report.Requested(writer.leaderboard) {
+			continue
+		}
+		if err := writer.write(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to log %s leaderboard: %w", writer.leaderboard, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// reportFile is a CSV file WriteReport writes for a leaderboard, with the
+// number of entries in it.
+type reportFile struct {
+	leaderboard string
+	entries     int
+	write       func() error
+}
+
+// reportFiles returns the CSV files of the leaderboards in report, the main
+// file of each leaderboard first.
+func (w *Writer) reportFiles(report *types.Report) []reportFile {
+	var forge types.ForgeStats
+	if report.Forge != nil {
+		forge = *report.Forge
+	}
+	var lfs types.LFSStats
+	if report.LFS != nil {
+		lfs = *report.LFS
+	}
+	var formatDrift types.FormatDriftStats
+	if report.FormatDrift != nil {
+		formatDrift = *report.FormatDrift
+	}
+	var leadTime types.LeadTimeStats
+	if report.LeadTime != nil {
+		leadTime = *report.LeadTime
+	}
+	var changelog types.ChangelogStats
+	if report.Changelog != nil {
+		changelog = *report.Changelog
+	}
+	var timezones types.TimezoneStats
+	if report.Timezones != nil {
+		timezones = *report.Timezones
+	}
+	var deletions types.DeletionStats
+	if report.Deletions != nil {
+		deletions = *report.Deletions
+	}
+	var newContributors types.NewContributorStats
+	if report.NewContributors != nil {
+		newContributors = *report.NewContributors
+	}
+	var score types.ScoreStats
+	if report.Score != nil {
+		score = *report.Score
+	}
+
+	return []reportFile{
+		{"by-workspace", len(report.Workspaces), func() error { return w.WriteWorkspaceLeaderboardCSV(report.Workspaces) }},
+		{"authors", len(report.Authors), func() error { return w.WriteAuthorLeaderboardCSV(report.Authors) }},
+		{"files", len(report.Files), func() error { return w.WriteFileLeaderboardCSV(report.Files) }},
+		{"rules", len(report.Rules), func() error { return w.WriteRuleLeaderboardCSV(report.Rules) }},
+		{"rule-plugins", len(report.RulePlugins), func() error { return w.WriteRulePluginLeaderboardCSV(report.RulePlugins) }},
+		{"rule-groups", len(report.RuleGroups), func() error { return w.WriteRuleGroupLeaderboardCSV(report.RuleGroups) }},
+		{"rule-author-matrix", len(report.RuleAuthors), func() error { return w.WriteRuleAuthorMatrixCSV(report.RuleAuthors) }},
+		{"ruff", len(report.RuffRules), func() error { return w.WriteRuffRuleLeaderboardCSV(report.RuffRules) }},
+		{"loc", len(report.LinesOfCode), func() error { return w.WriteLinesOfCodeLeaderboardCSV(report.LinesOfCode) }},
+		{"commits", len(report.Commits), func() error { return w.WriteCommitCountLeaderboardCSV(report.Commits) }},
+		{"recent", len(report.Recent), func() error { return w.WriteRecentContributorsLeaderboardCSV(report.Recent) }},
+		{"new-contributors", len(newContributors.Authors), func() error { return w.WriteNewContributorLeaderboardCSV(newContributors.Authors) }},
+		{"coverage", len(report.Coverage), func() error { return w.WriteCodeCoverageLeaderboardCSV(report.Coverage) }},
+		{"churn", len(report.Churn), func() error { return w.WriteCodeChurnLeaderboardCSV(report.Churn) }},
+		{"hotspots", len(report.Hotspots), func() error { return w.WriteHotspotLeaderboardCSV(report.Hotspots) }},
+		{"bugs", len(report.BugDensity), func() error { return w.WriteBugDensityLeaderboardCSV(report.BugDensity) }},
+		{"debt", len(report.TechnicalDebt), func() error { return w.WriteTechnicalDebtLeaderboardCSV(report.TechnicalDebt) }},
+		{"spellcheck", len(report.SpellCheck), func() error { return w.WriteSpellCheckLeaderboardCSV(report.SpellCheck) }},
+		{"format-drift", len(formatDrift.Files), func() error { return w.WriteFormatDriftLeaderboardCSV(formatDrift.Files) }},
+		{"format-drift", len(formatDrift.Authors), func() error { return w.WriteFormatDriftAuthorsCSV(formatDrift.Authors) }},
+		{"encoding", len(report.Encoding), func() error { return w.WriteEncodingLeaderboardCSV(report.Encoding) }},
+		{"long-functions", len(report.LongFunctions), func() error { return w.WriteLongFunctionLeaderboardCSV(report.LongFunctions) }},
+		{"github-stats", len(forge.Authors), func() error { return w.WritePullRequestLeaderboardCSV(forge.Authors) }},
+		{"github-stats", len(forge.Reviewers), func() error { return w.WriteReviewerLeaderboardCSV(forge.Reviewers) }},
+		{"lead-time", len(leadTime.Authors), func() error { return w.WriteLeadTimeLeaderboardCSV(leadTime.Authors) }},
+		{"lead-time", len(leadTime.Weeks), func() error { return w.WriteMergeCadenceCSV(leadTime.Weeks) }},
+		{"changelog-readiness", len(changelog.Authors), func() error { return w.WriteChangelogLeaderboardCSV(changelog.Authors) }},
+		{"changelog-readiness", len(changelog.Invisible), func() error {
+			return w.WriteChangelogCommitsCSV("changelog_invisible", changelog.Invisible)
+		}},
+		{"changelog-readiness", len(changelog.Breaking), func() error {
+			return w.WriteChangelogCommitsCSV("changelog_breaking", changelog.Breaking)
+		}},
+		{"timezones", len(timezones.Authors), func() error { return w.WriteTimezoneLeaderboardCSV(timezones.Authors) }},
+		{"deletions", len(deletions.Authors), func() error { return w.WriteDeletionLeaderboardCSV(deletions.Authors) }},
+		{"deletions", len(deletions.Cleanups), func() error { return w.WriteCleanupCommitsCSV(deletions.Cleanups) }},
+		{"lfs", len(lfs.Violations), func() error { return w.WriteLFSViolationsCSV(lfs.Violations) }},
+		{"vulns", len(report.Vulnerabilities), func() error { return w.WriteVulnerabilityLeaderboardCSV(report.Vulnerabilities) }},
+		// Counts are written even when there are none, so trends reach zero
+		{"vulns", 1, func() error { return w.WriteVulnerabilityCountsCSV(report.Vulnerabilities) }},
+		{"deps", len(report.Dependencies), func() error { return w.WriteDependencyLeaderboardCSV(report.Dependencies) }},
+		{"score", len(score.Files), func() error { return w.WriteScoreLeaderboardCSV(score.Files) }},
+		{"score", len(score.Components), func() error { return w.WriteScoreComponentsCSV(score.Components) }},
+	}
 }
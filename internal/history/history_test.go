@@ -1,13 +1,14 @@
 package history
 
 import (
+	"encoding/csv"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
-	"codecompass/internal/types"
+	"github.com/xeon-zolt/codecompass/internal/types"
 )
 
 func TestWriteAuthorLeaderboardCSV(t *testing.T) {
@@ -45,7 +46,7 @@ func TestWriteAuthorLeaderboardCSV(t *testing.T) {
 	}
 
 	// Write to CSV
-	err = WriteAuthorLeaderboardCSV(tmpDir, entries)
+	err = NewWriter(tmpDir).WriteAuthorLeaderboardCSV(entries)
 	if err != nil {
 		t.Fatalf("WriteAuthorLeaderboardCSV failed: %v", err)
 	}
@@ -77,9 +78,9 @@ func TestWriteAuthorLeaderboardCSV(t *testing.T) {
 	}
 
 	// Expected CSV content (excluding the dynamic timestamp in filename)
-	expectedContent := "Rank,Name,Email,Issues,Errors,Warnings,Files,TopRule,TopRuleCount\n" +
-		"1,John Doe,john@example.com,100,50,50,10,no-unused-vars,20\n" +
-		"2,Jane Smith,jane@example.com,80,30,50,8,indent,15\n"
+	expectedContent := "Rank,Name,Email,Issues,Errors,Warnings,Files,TopRule,TopRuleCount,DecayedScore,WeightedScore\n" +
+		"1,John Doe,john@example.com,100,50,50,10,no-unused-vars,20,0.00,0.00\n" +
+		"2,Jane Smith,jane@example.com,80,30,50,8,indent,15,0.00,0.00\n"
 
 	if string(content) != expectedContent {
 		t.Errorf("CSV content mismatch:\nExpected:\n%s\nGot:\n%s", expectedContent, string(content))
@@ -88,15 +89,172 @@ func TestWriteAuthorLeaderboardCSV(t *testing.T) {
 
 func TestWriteLeaderboardToCSV_ErrorHandling(t *testing.T) {
 	// Test case: empty directory path
-	err := WriteLeaderboardToCSV("", "test.csv", []string{"Header"}, [][]string{{"Data"}})
+	err := NewWriter("").WriteLeaderboardToCSV("test.csv", []string{"Header"}, [][]string{{"Data"}})
 	if err == nil || !strings.Contains(err.Error(), "log directory not specified") {
 		t.Errorf("Expected 'log directory not specified' error, got: %v", err)
 	}
 
 	// Test case: invalid directory path (e.g., permissions issue, though hard to simulate reliably)
 	// For now, we'll just test a path that's clearly not writable or creatable in a typical setup
-	err = WriteLeaderboardToCSV("/root/nonexistent/path", "test.csv", []string{"Header"}, [][]string{{"Data"}})
+	err = NewWriter("/root/nonexistent/path").WriteLeaderboardToCSV("test.csv", []string{"Header"}, [][]string{{"Data"}})
 	if err == nil || !strings.Contains(err.Error(), "failed to create log directory") {
 		t.Errorf("Expected 'failed to create log directory' error, got: %v", err)
 	}
 }
+
+func TestWriteAuthorLeaderboardCSVSanitizesFormulas(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "test_history")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	entries := []types.LeaderboardEntry{
+		{
+			Rank:    1,
+			Name:    `=HYPERLINK("http://evil.example","click")`,
+			Email:   "+attacker@example.com",
+			Count:   3,
+			TopRule: "no-console",
+		},
+	}
+
+	if err := NewWriter(tmpDir).WriteAuthorLeaderboardCSV(entries); err != nil {
+		t.Fatalf("WriteAuthorLeaderboardCSV failed: %v", err)
+	}
+
+	files, err := ioutil.ReadDir(tmpDir)
+	if err != nil || len(files) != 1 {
+		t.Fatalf("Expected 1 file in temp dir, got %d (%v)", len(files), err)
+	}
+
+	file, err := os.Open(filepath.Join(tmpDir, files[0].Name()))
+	if err != nil {
+		t.Fatalf("Failed to open generated CSV file: %v", err)
+	}
+	defer file.Close()
+
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse generated CSV file: %v", err)
+	}
+
+	row := records[1]
+	if row[1] != `'=HYPERLINK("http://evil.example","click")` {
+		t.Errorf("Expected author name to be defanged, got %s", row[1])
+	}
+
+	if row[2] != "'+attacker@example.com" {
+		t.Errorf("Expected author email to be defanged, got %s", row[2])
+	}
+}
+
+func TestSanitizeCell(t *testing.T) {
+	tests := map[string]string{
+		"=1+1":        "'=1+1",
+		"@SUM(A1:A2)": "'@SUM(A1:A2)",
+		"-5":          "-5",
+		"+3.5":        "+3.5",
+		"+Inf":        "'+Inf",
+		"-0x1p-2":     "'-0x1p-2",
+		"John Doe":    "John Doe",
+		"":            "",
+		"'=1+1":       "''=1+1",
+		"'-5":         "'-5",
+		"O'Neil":      "O'Neil",
+	}
+
+	for input, expected := range tests {
+		if got := sanitizeCell(input); got != expected {
+			t.Errorf("sanitizeCell(%q) = %q, expected %q", input, got, expected)
+		}
+		if got := unsanitizeCell(expected); got != input {
+			t.Errorf("unsanitizeCell(%q) = %q, expected %q", expected, got, input)
+		}
+	}
+
+}
+
+func TestWriterWithoutSanitize(t *testing.T) {
+	writer := &Writer{Dir: t.TempDir()}
+	if err := writer.WriteLeaderboardToCSV("raw.csv", []string{"Name"}, [][]string{{"=1+1"}}); err != nil {
+		t.Fatalf("WriteLeaderboardToCSV failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(writer.Dir, "raw.csv"))
+	if err != nil {
+		t.Fatalf("Failed to read generated CSV file: %v", err)
+	}
+
+	if string(content) != "Name\n=1+1\n" {
+		t.Errorf("Expected cells to be written unchanged, got:\n%s", content)
+	}
+}
+
+func TestWriteFileLeaderboardCSVNormalizesPaths(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "test_history")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	entries := []types.FileLeaderboardEntry{
+		{Rank: 1, Path: `src\components\app.js`, Count: 3, Errors: 1, Warnings: 2, Authors: 1, TopRule: "semi", TopCount: 2},
+	}
+
+	if err := NewWriter(tmpDir).WriteFileLeaderboardCSV(entries); err != nil {
+		t.Fatalf("WriteFileLeaderboardCSV failed: %v", err)
+	}
+
+	files, _ := ioutil.ReadDir(tmpDir)
+	content, err := ioutil.ReadFile(filepath.Join(tmpDir, files[0].Name()))
+	if err != nil {
+		t.Fatalf("Failed to read generated CSV file: %v", err)
+	}
+
+	if !strings.Contains(string(content), "src/components/app.js") {
+		t.Errorf("Expected path to use forward slashes, got:\n%s", string(content))
+	}
+	if !strings.HasPrefix(string(content), "Rank,Path,Issues,Errors,Warnings,") || !strings.Contains(string(content), "app.js,3,1,2,1,semi,2") {
+		t.Errorf("Expected the errors and warnings of each file, got:\n%s", string(content))
+	}
+}
+
+func TestWriteReport(t *testing.T) {
+	dir := t.TempDir()
+
+	report := types.NewReport("/src/app")
+	report.Leaderboards = []string{"authors", "loc", "churn", "deletions", "summary"}
+	report.Authors = []types.LeaderboardEntry{{Name: "Alice", Email: "alice@example.com", Count: 2}}
+	report.LinesOfCode = []types.LinesOfCodeEntry{{Path: "main.go", Lines: 10}}
+	// Graded for the report card but not requested
+	report.Commits = []types.CommitCountEntry{{Name: "Alice", Email: "alice@example.com", Commits: 3}}
+	report.Summary = &types.SummaryStats{TotalIssues: 2}
+	report.Deletions = &types.DeletionStats{
+		Authors:  []types.DeletionAuthorEntry{{Rank: 1, Name: "Alice", Email: "alice@example.com", Commits: 1, DeletedLines: 40, NetRemoved: 40}},
+		Cleanups: []types.CleanupCommit{{Hash: "abc123", Name: "Alice", Email: "alice@example.com", DeletedLines: 40, NetRemoved: 40, Subject: "Remove the old parser"}},
+	}
+
+	if err := NewWriter(dir).WriteReport(&report); err != nil {
+		t.Fatalf("WriteReport failed: %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every file of the run carries the time the report was generated
+	stamp := report.GeneratedAt.Local().Format(stampLayout)
+	var prefixes []string
+	for _, entry := range entries {
+		prefix, found := strings.CutSuffix(entry.Name(), "_"+stamp+".csv")
+		if !found {
+			t.Errorf("Expected %s to be stamped %s", entry.Name(), stamp)
+		}
+		prefixes = append(prefixes, prefix)
+	}
+	if strings.Join(prefixes, ",") != "author_leaderboard,cleanup_commits,deletion_leaderboard,loc_leaderboard,run" {
+		t.Errorf("Expected the run and only the requested leaderboards with entries to be logged, but got %v", prefixes)
+	}
+}
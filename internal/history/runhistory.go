@@ -0,0 +1,86 @@
+package history
+
+import (
+	"math"
+	"time"
+
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+// ReadRunHistory returns the history of the last runs, at most runs of
+// them, logged in dir before the given time, which leaves out a run logged
+// at that time. It returns nil when dir has no such run, or does not exist.
+//
+// A run that did not log its metadata or a leaderboard, or whose file
+// cannot be parsed, leaves a gap in those series, so old and partial
+// history can be charted. So does a rule or file a logged leaderboard does
+// not list.
+func ReadRunHistory(dir string, before time.Time, runs int) (*types.RunHistory, error) {
+	logged, err := ReadRuns(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Stamps only have seconds, so a run logged in the same second as
+	// before is its own
+	before = before.Truncate(time.Second)
+	var selected []Run
+	for _, run := range logged {
+		at, err := time.ParseInLocation(stampLayout, run.Stamp, time.Local)
+		if err == nil && at.Before(before) {
+			selected = append(selected, run)
+		}
+	}
+	if len(selected) > runs {
+		selected = selected[len(selected)-runs:]
+	}
+	if len(selected) == 0 {
+		return nil, nil
+	}
+
+	h := &types.RunHistory{
+		Times:  make([]time.Time, len(selected)),
+		Totals: make(map[string][]float64),
+		Rules:  make(map[string][]float64),
+		Files:  make(map[string][]float64),
+	}
+	set := func(series map[string][]float64, key string, i int, value float64) {
+		values, ok := series[key]
+		if !ok {
+			values = make([]float64, len(selected))
+			for j := range values {
+				values[j] = math.NaN()
+			}
+			series[key] = values
+		}
+		values[i] = value
+	}
+
+	for i, run := range selected {
+		h.Times[i] = run.Time
+		if path, ok := run.files["run"]; ok {
+			if t, err := readTable(path); err == nil && len(t.rows) > 0 {
+				for _, column := range totalColumns {
+					if value, ok := t.number(t.rows[0], column.column); ok {
+						set(h.Totals, column.metric, i, value)
+					}
+				}
+			}
+		}
+		if path, ok := run.files["rule_leaderboard"]; ok {
+			if values, err := readSnapshot(path, []string{"Rule"}, "Violations"); err == nil {
+				for rule, value := range values {
+					set(h.Rules, rule, i, value)
+				}
+			}
+		}
+		if path, ok := run.files["file_leaderboard"]; ok {
+			if values, err := readSnapshot(path, []string{"Path"}, "Issues"); err == nil {
+				for file, value := range values {
+					set(h.Files, file, i, value)
+				}
+			}
+		}
+	}
+	return h, nil
+}
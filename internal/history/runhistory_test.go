@@ -0,0 +1,77 @@
+package history
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReadRunHistory(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"run_20260101_093000.csv":              "GeneratedAt,TotalIssues\n2026-01-01T09:30:00Z,30\n",
+		"rule_leaderboard_20260101_093000.csv": "Rank,Rule,Violations\n1,eqeqeq,20\n",
+		"run_20260105_093000.csv":              "GeneratedAt,TotalIssues,OverallCoverage\n2026-01-05T09:30:00Z,20,70.50\n",
+		"rule_leaderboard_20260105_093000.csv": "Rank,Rule,Violations\n1,eqeqeq,12\n2,curly,8\n",
+		"file_leaderboard_20260105_093000.csv": "Rank,Path,Issues\n1,\"src/a,b.js\",20\n",
+		// Did not log the rules, and its files cannot be parsed
+		"run_20260110_093000.csv":              "GeneratedAt,TotalIssues,OverallCoverage\n2026-01-10T09:30:00Z,15,72\n",
+		"file_leaderboard_20260110_093000.csv": "Rank,Path,Issues\n1,\"src/a,b.js,15\n",
+		// The run of the report itself
+		"run_20260120_093000.csv": "GeneratedAt,TotalIssues\n2026-01-20T09:30:00Z,9\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	h, err := ReadRunHistory(dir, time.Date(2026, 1, 20, 9, 30, 0, 900, time.Local), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The last 2 runs before the report's
+	if h == nil || len(h.Times) != 2 || !h.Times[0].Equal(time.Date(2026, 1, 5, 9, 30, 0, 0, time.UTC)) {
+		t.Fatalf("ReadRunHistory = %+v; expected the runs of January 5 and 10", h)
+	}
+	nan := math.NaN()
+	for name, series := range map[string]struct {
+		got, expected []float64
+	}{
+		"issues":     {h.Totals["issues"], []float64{20, 15}},
+		"coverage":   {h.Totals["coverage"], []float64{70.5, 72}},
+		"eqeqeq":     {h.Rules["eqeqeq"], []float64{12, nan}},
+		"curly":      {h.Rules["curly"], []float64{8, nan}},
+		"src/a,b.js": {h.Files["src/a,b.js"], []float64{20, nan}},
+	} {
+		if !sameSeries(series.got, series.expected) {
+			t.Errorf("%s = %v; expected %v", name, series.got, series.expected)
+		}
+	}
+	if _, ok := h.Totals["errors"]; ok {
+		t.Errorf("Expected no errors series, as no run logged them, got %v", h.Totals["errors"])
+	}
+
+	if h, err := ReadRunHistory(dir, time.Date(2026, 1, 1, 0, 0, 0, 0, time.Local), 20); h != nil || err != nil {
+		t.Errorf("ReadRunHistory before any run = %+v, %v; expected nil", h, err)
+	}
+	if h, err := ReadRunHistory(filepath.Join(dir, "missing"), time.Now(), 20); h != nil || err != nil {
+		t.Errorf("ReadRunHistory of a missing directory = %+v, %v; expected nil", h, err)
+	}
+}
+
+// sameSeries reports whether a and b are equal, NaN equal to NaN.
+func sameSeries(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] && !(math.IsNaN(a[i]) && math.IsNaN(b[i])) {
+			return false
+		}
+	}
+	return true
+}
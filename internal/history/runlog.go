@@ -0,0 +1,124 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+// RunLogFile is the name of the run log in the history directory.
+const RunLogFile = "runs.log"
+
+// RunRecord is what one run did, as a line of the run log: what it was
+// asked, what it analyzed, what it found and how it ended. The fields it
+// shares with the report and the run CSV files have their names.
+type RunRecord struct {
+	// Time is when the run started, and Duration how long it took.
+	Time     time.Time     `json:"time"`
+	Duration time.Duration `json:"duration"`
+
+	// Head is the commit analyzed, and Dirty is set when tracked files had
+	// uncommitted changes.
+	Head  string `json:"head,omitempty"`
+	Dirty bool   `json:"dirty"`
+
+	// Flags maps the command-line flags that were set to their values.
+	Flags map[string]string `json:"flags,omitempty"`
+
+	// ConfigFingerprint is a hash of the effective configuration, the
+	// same for runs with the same settings.
+	ConfigFingerprint string `json:"config_fingerprint,omitempty"`
+
+	SchemaVersion int               `json:"schema_version,omitempty"`
+	ToolVersions  map[string]string `json:"tool_versions,omitempty"`
+
+	// Leaderboards maps the leaderboards generated to their entries, and
+	// Failures those that failed to the error.
+	Leaderboards map[string]int    `json:"leaderboards,omitempty"`
+	Failures     map[string]string `json:"failures,omitempty"`
+	Warnings     int               `json:"warnings"`
+
+	// ExitStatus is the status the run exited with, and Error why it
+	// failed when it did.
+	ExitStatus int    `json:"exit_status"`
+	Error      string `json:"error,omitempty"`
+}
+
+// AddReport fills in what record shares with report, the result of the
+// analysis of the run.
+func (r *RunRecord) AddReport(report *types.Report) {
+	if r.Head == "" {
+		r.Head = report.Repo.Head
+	}
+	r.SchemaVersion = report.SchemaVersion
+	r.ToolVersions = report.ToolVersions
+	r.Failures = report.Failures
+	r.Warnings = len(report.Warnings)
+	r.Leaderboards = LeaderboardCounts(report)
+}
+
+// LeaderboardCounts maps the requested leaderboards of report that are
+// logged as CSV files to their entries. Leaderboards without a CSV format,
+// such as the summary, are left out.
+func LeaderboardCounts(report *types.Report) map[string]int {
+	counts := make(map[string]int)
+	for _, file := range (&Writer{}).reportFiles(report) {
+		if _, counted := counts[file.leaderboard]; counted || !report.Requested(file.leaderboard) {
+			continue
+		}
+		counts[file.leaderboard] = file.entries
+	}
+	return counts
+}
+
+// AppendRunLog appends record to the run log at path as a line of JSON,
+// creating the log and its directory if needed.
+func AppendRunLog(path string, record RunRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create run log directory: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// ReadRunLog reads the records of the run log at path, oldest first. A
+// missing log is an error wrapping fs.ErrNotExist.
+func ReadRunLog(path string) ([]RunRecord, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var records []RunRecord
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var record RunRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
@@ -0,0 +1,77 @@
+package history
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+func TestAppendAndReadRunLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history", RunLogFile)
+	if _, err := ReadRunLog(path); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("Expected a missing log to be fs.ErrNotExist, but got %v", err)
+	}
+
+	report := &types.Report{
+		SchemaVersion: types.ReportSchemaVersion,
+		Repo:          types.RepoInfo{Head: "abc123"},
+		Leaderboards:  []string{"debt", "format-drift", "summary", "churn"},
+		TechnicalDebt: []types.TechnicalDebtEntry{{Path: "a.js", TotalDebt: 1}, {Path: "b.js", TotalDebt: 2}},
+		FormatDrift: &types.FormatDriftStats{
+			Files:   []types.FormatDriftEntry{{Path: "a.py"}},
+			Authors: []types.FormatDriftAuthorEntry{{Email: "a@example.com"}, {Email: "b@example.com"}},
+		},
+		LinesOfCode:  []types.LinesOfCodeEntry{{Path: "a.js"}},
+		Failures:     map[string]string{"churn": "git log failed"},
+		ToolVersions: map[string]string{"git": "2.43.0"},
+		Warnings:     []types.Warning{types.NewWarning("Spell check skipped", "file", "a.md")},
+	}
+	passed := RunRecord{Time: time.Date(2026, 1, 5, 9, 30, 0, 0, time.UTC), Duration: 2 * time.Second, Flags: map[string]string{"debt": "true"}}
+	passed.AddReport(report)
+	failed := RunRecord{Time: time.Date(2026, 1, 6, 9, 30, 0, 0, time.UTC), Head: "def456", Dirty: true, ExitStatus: 5, Error: "Coverage is required"}
+
+	// Only requested leaderboards with a CSV format are counted, by their
+	// main file
+	expected := map[string]int{"debt": 2, "format-drift": 1, "churn": 0}
+	if !reflect.DeepEqual(passed.Leaderboards, expected) {
+		t.Errorf("Expected %v, but got %v", expected, passed.Leaderboards)
+	}
+	if passed.Head != "abc123" || passed.Warnings != 1 || passed.SchemaVersion != types.ReportSchemaVersion {
+		t.Errorf("Expected the report fields filled in, but got %+v", passed)
+	}
+
+	for _, record := range []RunRecord{passed, failed} {
+		if err := AppendRunLog(path, record); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 2 {
+		t.Errorf("Expected one line per run, but got:\n%s", data)
+	}
+
+	records, err := ReadRunLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(records, []RunRecord{passed, failed}) {
+		t.Errorf("Expected the records read back, but got %+v", records)
+	}
+
+	if err := os.WriteFile(path, append(data, "{not json\n"...), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadRunLog(path); err == nil || !strings.Contains(err.Error(), ":3:") {
+		t.Errorf("Expected the bad line named, but got %v", err)
+	}
+}
@@ -0,0 +1,341 @@
+package history
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xeon-zolt/codecompass/internal/toolversion"
+)
+
+// Series is one time series in the format of the Grafana JSON datasource.
+// Points are [milliseconds since the epoch, value] pairs in time order.
+type Series struct {
+	Metric string            `json:"metric"`
+	Labels map[string]string `json:"labels"`
+	Points [][2]float64      `json:"points"`
+}
+
+// historyFile matches the names of the files written by Writer, capturing
+// the leaderboard and the run stamp.
+var historyFile = regexp.MustCompile(`^(.+)_([0-9]{8}_[0-9]{6})\.csv$`)
+
+// summaryColumns maps the run metadata columns exported by the summary
+// metric to their series.
+var summaryColumns = []struct {
+	column string
+	metric string
+}{
+	{"TotalIssues", "issues_total"},
+	{"Errors", "issues_errors"},
+	{"Warnings", "issues_warnings"},
+	{"Authors", "authors_total"},
+	{"Files", "files_total"},
+	{"Rules", "rules_total"},
+}
+
+// table is a history CSV file with its columns looked up by name, so files
+// written by other versions, with columns added, dropped or reordered, can
+// still be read.
+type table struct {
+	columns map[string]int
+	rows    [][]string
+
+	// sanitized is set when the file was written with Writer.Sanitize, so
+	// value undoes it
+	sanitized bool
+}
+
+func readTable(path string) (*table, error) {
+	t, err := parseTable(path)
+	if err != nil {
+		return nil, err
+	}
+	t.sanitized = writtenSanitized(path, t)
+	return t, nil
+}
+
+// writtenSanitized reports whether the history file at path, read into t,
+// was written with Writer.Sanitize, as the run metadata logged with it
+// records. Files without run metadata, or logged before it recorded that,
+// were sanitized, as NewWriter does.
+func writtenSanitized(path string, t *table) bool {
+	run := t
+	if match := historyFile.FindStringSubmatch(filepath.Base(path)); match != nil && match[1] != "run" {
+		var err error
+		if run, err = parseTable(filepath.Join(filepath.Dir(path), "run_"+match[2]+".csv")); err != nil {
+			return true
+		}
+	}
+	i, ok := run.columns["Sanitized"]
+	return !ok || len(run.rows) == 0 || i >= len(run.rows[0]) || run.rows[0][i] != "false"
+}
+
+func parseTable(path string) (*table, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%s has no header", path)
+	}
+
+	t := &table{columns: make(map[string]int), rows: records[1:]}
+	for i, name := range records[0] {
+		t.columns[name] = i
+	}
+	return t, nil
+}
+
+// value returns the cell of row in the first of the named columns the table
+// has, undoing the CSV sanitizing of sanitized files. It reports false for
+// missing and empty cells.
+func (t *table) value(row []string, names ...string) (string, bool) {
+	for _, name := range names {
+		i, ok := t.columns[name]
+		if !ok {
+			continue
+		}
+		if i >= len(row) || row[i] == "" {
+			return "", false
+		}
+		cell := row[i]
+		if t.sanitized {
+			cell = unsanitizeCell(cell)
+		}
+		return cell, true
+	}
+	return "", false
+}
+
+func (t *table) number(row []string, names ...string) (float64, bool) {
+	cell, ok := t.value(row, names...)
+	if !ok {
+		return 0, false
+	}
+	number, err := strconv.ParseFloat(cell, 64)
+	return number, err == nil
+}
+
+// sum adds up the named column over all rows. It reports false when no row
+// has a number there.
+func (t *table) sum(names ...string) (float64, bool) {
+	total, found := 0.0, false
+	for _, row := range t.rows {
+		if number, ok := t.number(row, names...); ok {
+			total += number
+			found = true
+		}
+	}
+	return total, found
+}
+
+// ReadTimeSeries reads the history files in dir and returns the series of
+// the selected metrics: summary, author-issues, coverage and debt. Points
+// are stamped with the time recorded in each run's metadata, or for history
+// written before run metadata existed, the time in the file names. The
+// summary also has a tool_versions_changed series, which is 1 for runs that
+// used a tool at another version than the run before them, and 0 otherwise,
+// to flag changes of the totals that may come from the tools.
+//
+// Runs without data for a series leave a gap in it, and files that cannot be
+// parsed are skipped, so old and partial history can be exported. Totals
+// missing from the run metadata are recomputed from the leaderboards logged
+// in the same run. Series are sorted by metric and labels.
+func ReadTimeSeries(dir string, metrics []string) ([]Series, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history directory %s: %w", dir, err)
+	}
+
+	// Leaderboard files of each run, by run stamp
+	runs := make(map[string]map[string]string)
+	for _, entry := range entries {
+		match := historyFile.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		if runs[match[2]] == nil {
+			runs[match[2]] = make(map[string]string)
+		}
+		runs[match[2]][match[1]] = filepath.Join(dir, entry.Name())
+	}
+
+	selected := make(map[string]bool)
+	for _, metric := range metrics {
+		selected[metric] = true
+	}
+
+	series := make(map[string]*Series)
+	add := func(metric string, labels map[string]string, at time.Time, value float64) {
+		keys := make([]string, 0, len(labels))
+		for name, label := range labels {
+			keys = append(keys, name+"="+label)
+		}
+		sort.Strings(keys)
+		key := metric + "{" + strings.Join(keys, ",") + "}"
+
+		s := series[key]
+		if s == nil {
+			s = &Series{Metric: metric, Labels: labels}
+			series[key] = s
+		}
+		s.Points = append(s.Points, [2]float64{float64(at.UnixMilli()), value})
+	}
+
+	// The tool versions of each run, to compare runs once they are in order
+	type runTools struct {
+		at       time.Time
+		versions map[string]string
+	}
+	var tools []runTools
+
+	for stamp, files := range runs {
+		tables := make(map[string]*table)
+		for name, path := range files {
+			if t, err := readTable(path); err == nil {
+				tables[name] = t
+			}
+		}
+
+		run, runRow := tables["run"], []string(nil)
+		if run != nil && len(run.rows) > 0 {
+			runRow = run.rows[0]
+		} else {
+			run = &table{columns: map[string]int{}}
+		}
+		// runTotal returns a total from the run metadata, falling back to
+		// the sum of a column of a leaderboard logged in the same run
+		runTotal := func(column, leaderboard string, names ...string) (float64, bool) {
+			if value, ok := run.number(runRow, column); ok {
+				return value, true
+			}
+			if t := tables[leaderboard]; t != nil {
+				return t.sum(names...)
+			}
+			return 0, false
+		}
+
+		at, err := time.ParseInLocation(stampLayout, stamp, time.Local)
+		if generated, ok := run.value(runRow, "GeneratedAt"); ok {
+			if parsed, parseErr := time.Parse(time.RFC3339, generated); parseErr == nil {
+				at, err = parsed, nil
+			}
+		}
+		if err != nil {
+			continue
+		}
+
+		if selected["summary"] {
+			for _, column := range summaryColumns {
+				value, ok := run.number(runRow, column.column)
+				if column.column == "TotalIssues" {
+					// Every author with issues is logged, so they add up
+					value, ok = runTotal(column.column, "author_leaderboard", "Issues")
+				}
+				if ok {
+					add(column.metric, map[string]string{}, at, value)
+				}
+			}
+			if value, ok := run.value(runRow, "ToolVersions"); ok {
+				tools = append(tools, runTools{at, parseToolVersions(value)})
+			}
+		}
+
+		if authors := tables["author_leaderboard"]; selected["author-issues"] && authors != nil {
+			for _, row := range authors.rows {
+				email, hasEmail := authors.value(row, "Email")
+				issues, hasIssues := authors.number(row, "Issues")
+				if hasEmail && hasIssues {
+					add("author_issues", map[string]string{"email": email}, at, issues)
+				}
+			}
+		}
+
+		if selected["coverage"] {
+			if value, ok := run.number(runRow, "OverallCoverage"); ok {
+				add("coverage_percent", map[string]string{}, at, value)
+			} else if t := tables["coverage_leaderboard"]; t != nil {
+				covered, _ := t.sum("LinesCovered")
+				total, ok := t.sum("LinesTotal")
+				if ok && total > 0 {
+					add("coverage_percent", map[string]string{}, at, covered/total*100)
+				}
+			}
+		}
+
+		if selected["debt"] {
+			if value, ok := runTotal("TotalDebt", "technical_debt_leaderboard", "TotalDebt"); ok {
+				add("debt_total", map[string]string{}, at, value)
+			}
+		}
+
+		if selected["score"] {
+			if value, ok := run.number(runRow, "Score"); ok {
+				add("quality_score", map[string]string{}, at, value)
+			}
+			if components := tables["score_components"]; components != nil {
+				for _, row := range components.rows {
+					component, hasComponent := components.value(row, "Component")
+					value, hasScore := components.number(row, "Score")
+					if hasComponent && hasScore {
+						add("quality_score_component", map[string]string{"component": component}, at, value)
+					}
+				}
+			}
+		}
+	}
+
+	sort.Slice(tools, func(i, j int) bool { return tools[i].at.Before(tools[j].at) })
+	for i := 1; i < len(tools); i++ {
+		changed := 0.0
+		if len(toolversion.Diff(tools[i-1].versions, tools[i].versions)) > 0 {
+			changed = 1
+		}
+		add("tool_versions_changed", map[string]string{}, tools[i].at, changed)
+	}
+
+	keys := make([]string, 0, len(series))
+	for key := range series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := make([]Series, 0, len(keys))
+	for _, key := range keys {
+		s := series[key]
+		sort.SliceStable(s.Points, func(i, j int) bool { return s.Points[i][0] < s.Points[j][0] })
+		result = append(result, *s)
+	}
+	return result, nil
+}
+
+// WriteTimeSeries writes series to path as a JSON array.
+func WriteTimeSeries(path string, series []Series) error {
+	if series == nil {
+		series = []Series{}
+	}
+	data, err := json.MarshalIndent(series, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write time series to %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package history
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+func TestReadTimeSeries(t *testing.T) {
+	dir := t.TempDir()
+
+	// History from before run metadata was recorded, with a column since
+	// dropped, is stamped by its file names
+	legacy := time.Date(2026, 1, 5, 9, 30, 0, 0, time.Local)
+	files := map[string]string{
+		"author_leaderboard_20260105_093000.csv":         "Rank,Name,Email,Issues,Legacy\n1,Alice,alice@example.com,5,x\n2,Bob,'=bob@example.com,3,y\n",
+		"technical_debt_leaderboard_20260105_093000.csv": "Rank,Path,TotalDebt\n1,a.go,4\n2,b.go,1\n",
+		"coverage_leaderboard_20260105_093000.csv":       "Rank,Path,LinesCovered,LinesTotal\n1,a.go,30,40\n2,b.go,10,60\n",
+		"broken_leaderboard_20260105_093000.csv":         "\"unterminated\n",
+		"notes.txt":                                      "not history",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A later run without coverage
+	report := types.NewReport("/src/app")
+	report.GeneratedAt = time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC)
+	report.Leaderboards = []string{"authors", "debt", "summary", "score"}
+	report.Authors = []types.LeaderboardEntry{{Name: "Alice", Email: "alice@example.com", Count: 2}}
+	report.Summary = &types.SummaryStats{TotalIssues: 2, Errors: 1, Warnings: 1, Authors: 1, Files: 1, Rules: 1}
+	report.Score = &types.ScoreStats{
+		Score:      72.5,
+		Components: []types.ScoreComponent{{Component: "issues", Score: 72.5, Weight: 100}},
+		Files:      []types.FileScore{{Rank: 1, Path: "a.go", Score: 72.5, Components: map[string]float64{"issues": 72.5}}},
+	}
+	if err := NewWriter(dir).WriteReport(&report); err != nil {
+		t.Fatal(err)
+	}
+
+	series, err := ReadTimeSeries(dir, []string{"summary", "author-issues", "coverage", "debt", "score"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	byKey := make(map[string][][2]float64)
+	for _, s := range series {
+		byKey[s.Metric+s.Labels["email"]] = s.Points
+	}
+	first, second := float64(legacy.UnixMilli()), float64(report.GeneratedAt.UnixMilli())
+	expected := map[string][][2]float64{
+		"issues_total":                   {{first, 8}, {second, 2}},
+		"issues_errors":                  {{second, 1}},
+		"issues_warnings":                {{second, 1}},
+		"authors_total":                  {{second, 1}},
+		"files_total":                    {{second, 1}},
+		"rules_total":                    {{second, 1}},
+		"author_issuesalice@example.com": {{first, 5}, {second, 2}},
+		"author_issues=bob@example.com":  {{first, 3}},
+		"coverage_percent":               {{first, 40}},
+		"debt_total":                     {{first, 5}, {second, 0}},
+		"quality_score":                  {{second, 72.5}},
+		"quality_score_component":        {{second, 72.5}},
+	}
+	if !reflect.DeepEqual(byKey, expected) {
+		t.Errorf("Expected series\n%v\nbut got\n%v", expected, byKey)
+	}
+
+	// Only the selected metrics are exported
+	series, err = ReadTimeSeries(dir, []string{"debt"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(series) != 1 || series[0].Metric != "debt_total" {
+		t.Errorf("Expected only the debt series, but got %+v", series)
+	}
+}
+
+func TestReadTimeSeriesToolVersions(t *testing.T) {
+	dir := t.TempDir()
+
+	start := time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC)
+	runs := []map[string]string{
+		nil, // Logged before tool versions were recorded
+		{"eslint": "8.57.0", "git": "2.43.0"},
+		{"eslint": "8.57.0", "git": "2.43.0", "ruff": "0.4.4"},
+		{"eslint": "9.1.0", "git": "2.43.0", "ruff": "0.4.4"},
+	}
+	for i, versions := range runs {
+		report := types.NewReport("/src/app")
+		report.GeneratedAt = start.AddDate(0, 0, i)
+		report.Leaderboards = []string{"summary"}
+		report.Summary = &types.SummaryStats{TotalIssues: 10}
+		report.ToolVersions = versions
+		if err := NewWriter(dir).WriteReport(&report); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	series, err := ReadTimeSeries(dir, []string{"summary"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var changed [][2]float64
+	for _, s := range series {
+		if s.Metric == "tool_versions_changed" {
+			changed = s.Points
+		}
+	}
+	// Adding Ruff is not a change of version
+	day := func(i int) float64 { return float64(start.AddDate(0, 0, i).UnixMilli()) }
+	expected := [][2]float64{{day(2), 0}, {day(3), 1}}
+	if !reflect.DeepEqual(changed, expected) {
+		t.Errorf("Expected tool_versions_changed %v, but got %v", expected, changed)
+	}
+}
+
+func TestWriteTimeSeries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "series.json")
+	if err := WriteTimeSeries(path, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var series []Series
+	if err := json.Unmarshal(data, &series); err != nil || series == nil {
+		t.Errorf("Expected an empty JSON array, but got %q (%v)", data, err)
+	}
+}
@@ -0,0 +1,86 @@
+package history
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// RunTotals are the repository totals one logged run recorded in its run
+// metadata, by metric: issues, errors, warnings, coverage and debt. Totals
+// the run did not measure are missing.
+type RunTotals struct {
+	Time   time.Time
+	Values map[string]float64
+}
+
+// totalColumns maps the run metadata columns to the metrics of RunTotals.
+var totalColumns = []struct {
+	column string
+	metric string
+}{
+	{"TotalIssues", "issues"},
+	{"Errors", "errors"},
+	{"Warnings", "warnings"},
+	{"OverallCoverage", "coverage"},
+	{"TotalDebt", "debt"},
+}
+
+// ReadRunTotals returns the totals of the last run logged in dir before the
+// given time, which leaves out a run logged at that time. It returns nil
+// when dir has no such run, or does not exist. Runs logged before run
+// metadata existed are skipped.
+func ReadRunTotals(dir string, before time.Time) (*RunTotals, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history directory %s: %w", dir, err)
+	}
+
+	// Stamps only have seconds, so a run logged in the same second as
+	// before is its own
+	before = before.Truncate(time.Second)
+
+	var latest time.Time
+	var path string
+	for _, entry := range entries {
+		match := historyFile.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil || match[1] != "run" {
+			continue
+		}
+		at, err := time.ParseInLocation(stampLayout, match[2], time.Local)
+		if err != nil || !at.Before(before) || at.Before(latest) {
+			continue
+		}
+		latest, path = at, filepath.Join(dir, entry.Name())
+	}
+	if path == "" {
+		return nil, nil
+	}
+
+	t, err := readTable(path)
+	if err != nil {
+		return nil, err
+	}
+	totals := &RunTotals{Time: latest, Values: make(map[string]float64)}
+	if len(t.rows) == 0 {
+		return totals, nil
+	}
+	row := t.rows[0]
+	if generated, ok := t.value(row, "GeneratedAt"); ok {
+		if at, err := time.Parse(time.RFC3339, generated); err == nil {
+			totals.Time = at
+		}
+	}
+	for _, column := range totalColumns {
+		if value, ok := t.number(row, column.column); ok {
+			totals.Values[column.metric] = value
+		}
+	}
+	return totals, nil
+}
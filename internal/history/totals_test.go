@@ -0,0 +1,54 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestReadRunTotals(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"run_20260105_093000.csv": "GeneratedAt,TotalIssues,Errors,Warnings,OverallCoverage,TotalDebt\n2026-01-05T09:30:00Z,20,5,15,70.50,8\n",
+		"run_20260110_093000.csv": "GeneratedAt,TotalIssues,Errors,Warnings,OverallCoverage,TotalDebt\n2026-01-10T09:30:00Z,12,2,10,,3\n",
+		// Not run metadata
+		"author_leaderboard_20260112_093000.csv": "Rank,Name,Email,Issues\n1,Alice,alice@example.com,9\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	totals, err := ReadRunTotals(dir, time.Date(2026, 2, 1, 0, 0, 0, 0, time.Local))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Coverage was not measured by the last run
+	expected := &RunTotals{
+		Time:   time.Date(2026, 1, 10, 9, 30, 0, 0, time.UTC),
+		Values: map[string]float64{"issues": 12, "errors": 2, "warnings": 10, "debt": 3},
+	}
+	if !reflect.DeepEqual(totals, expected) {
+		t.Errorf("ReadRunTotals = %+v; expected %+v", totals, expected)
+	}
+
+	// A run logged at the given time is left out
+	totals, err = ReadRunTotals(dir, time.Date(2026, 1, 10, 9, 30, 0, 900, time.Local))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if totals == nil || totals.Values["issues"] != 20 || totals.Values["coverage"] != 70.5 {
+		t.Errorf("ReadRunTotals before the last run = %+v; expected the first run", totals)
+	}
+
+	if totals, err := ReadRunTotals(dir, time.Date(2026, 1, 1, 0, 0, 0, 0, time.Local)); totals != nil || err != nil {
+		t.Errorf("ReadRunTotals before any run = %+v, %v; expected nil", totals, err)
+	}
+	if totals, err := ReadRunTotals(filepath.Join(dir, "missing"), time.Now()); totals != nil || err != nil {
+		t.Errorf("ReadRunTotals of a missing directory = %+v, %v; expected nil", totals, err)
+	}
+}
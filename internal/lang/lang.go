@@ -0,0 +1,171 @@
+// Package lang tells the programming language of repository files from their
+// name, their extension or the interpreter on their shebang line, so
+// leaderboards can be restricted to some languages and the make-up of a
+// repository summarized.
+package lang
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/xeon-zolt/codecompass/internal/utils"
+)
+
+// Language is a language files are classified as.
+type Language struct {
+	Name string // As shown, such as "TypeScript"
+
+	// Aliases are the lowercase names --lang accepts, the first being the
+	// one listed in errors.
+	Aliases []string
+
+	Extensions   []string // With the dot, lowercase
+	Filenames    []string // Names that tell the language without an extension
+	Interpreters []string // Shebang interpreters, without version numbers
+}
+
+// Languages are the languages files are classified as, in the order they
+// are listed.
+var Languages = []Language{
+	{Name: "JavaScript", Aliases: []string{"js", "javascript"}, Extensions: []string{".js", ".jsx", ".mjs", ".cjs"}, Interpreters: []string{"node", "nodejs"}},
+	{Name: "TypeScript", Aliases: []string{"ts", "typescript"}, Extensions: []string{".ts", ".tsx", ".mts", ".cts"}, Interpreters: []string{"deno", "ts-node"}},
+	{Name: "Python", Aliases: []string{"python", "py"}, Extensions: []string{".py", ".pyi", ".pyw"}, Interpreters: []string{"python"}},
+	{Name: "Go", Aliases: []string{"go", "golang"}, Extensions: []string{".go"}},
+	{Name: "Ruby", Aliases: []string{"ruby", "rb"}, Extensions: []string{".rb", ".rake", ".gemspec"}, Filenames: []string{"Gemfile", "Rakefile"}, Interpreters: []string{"ruby"}},
+	{Name: "Java", Aliases: []string{"java"}, Extensions: []string{".java"}},
+	{Name: "Kotlin", Aliases: []string{"kotlin", "kt"}, Extensions: []string{".kt", ".kts"}},
+	{Name: "Scala", Aliases: []string{"scala"}, Extensions: []string{".scala", ".sc"}},
+	{Name: "C", Aliases: []string{"c"}, Extensions: []string{".c", ".h"}},
+	{Name: "C++", Aliases: []string{"cpp", "c++"}, Extensions: []string{".cpp", ".cc", ".cxx", ".hpp", ".hh", ".hxx"}},
+	{Name: "C#", Aliases: []string{"csharp", "cs"}, Extensions: []string{".cs"}},
+	{Name: "Rust", Aliases: []string{"rust", "rs"}, Extensions: []string{".rs"}},
+	{Name: "Swift", Aliases: []string{"swift"}, Extensions: []string{".swift"}},
+	{Name: "PHP", Aliases: []string{"php"}, Extensions: []string{".php"}, Interpreters: []string{"php"}},
+	{Name: "Shell", Aliases: []string{"shell", "sh", "bash"}, Extensions: []string{".sh", ".bash", ".zsh"}, Interpreters: []string{"sh", "bash", "zsh", "dash", "ksh"}},
+	{Name: "Perl", Aliases: []string{"perl", "pl"}, Extensions: []string{".pl", ".pm"}, Interpreters: []string{"perl"}},
+	{Name: "Lua", Aliases: []string{"lua"}, Extensions: []string{".lua"}, Interpreters: []string{"lua"}},
+	{Name: "HTML", Aliases: []string{"html"}, Extensions: []string{".html", ".htm"}},
+	{Name: "CSS", Aliases: []string{"css"}, Extensions: []string{".css", ".scss", ".sass", ".less"}},
+	{Name: "Vue", Aliases: []string{"vue"}, Extensions: []string{".vue"}},
+	{Name: "Svelte", Aliases: []string{"svelte"}, Extensions: []string{".svelte"}},
+	{Name: "SQL", Aliases: []string{"sql"}, Extensions: []string{".sql"}},
+	{Name: "Markdown", Aliases: []string{"markdown", "md"}, Extensions: []string{".md", ".markdown"}},
+	{Name: "Makefile", Aliases: []string{"make", "makefile"}, Extensions: []string{".mk"}, Filenames: []string{"Makefile", "GNUmakefile", "makefile"}, Interpreters: []string{"make"}},
+	{Name: "Dockerfile", Aliases: []string{"dockerfile", "docker"}, Extensions: []string{".dockerfile"}, Filenames: []string{"Dockerfile", "Containerfile"}},
+}
+
+// byExtension, byFilename, byInterpreter and byAlias index Languages.
+var (
+	byExtension   = make(map[string]*Language)
+	byFilename    = make(map[string]*Language)
+	byInterpreter = make(map[string]*Language)
+	byAlias       = make(map[string]*Language)
+)
+
+func init() {
+	for i := range Languages {
+		language := &Languages[i]
+		for _, ext := range language.Extensions {
+			byExtension[ext] = language
+		}
+		for _, name := range language.Filenames {
+			byFilename[name] = language
+		}
+		for _, interpreter := range language.Interpreters {
+			byInterpreter[interpreter] = language
+		}
+		for _, alias := range language.Aliases {
+			byAlias[alias] = language
+		}
+		byAlias[strings.ToLower(language.Name)] = language
+	}
+}
+
+// Parse returns the names of the languages named by the comma-separated
+// value, such as "js,python,go", matching aliases and names ignoring case.
+func Parse(value string) ([]string, error) {
+	var names []string
+	for _, alias := range strings.Split(value, ",") {
+		alias = strings.ToLower(strings.TrimSpace(alias))
+		if alias == "" {
+			continue
+		}
+		language := byAlias[alias]
+		if language == nil {
+			return nil, fmt.Errorf("unknown language %q, expected one of: %s", alias, strings.Join(supported(), ", "))
+		}
+		if !slices.Contains(names, language.Name) {
+			names = append(names, language.Name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no language in %q, expected one of: %s", value, strings.Join(supported(), ", "))
+	}
+	return names, nil
+}
+
+// supported returns the first alias of each language.
+func supported() []string {
+	aliases := make([]string, len(Languages))
+	for i, language := range Languages {
+		aliases[i] = language.Aliases[0]
+	}
+	return aliases
+}
+
+// Detect returns the name of the language of file, relative to dir, or ""
+// when it is none of Languages. The file name and extension win; a file
+// without an extension is read for a shebang line.
+func Detect(dir, file string) string {
+	base := filepath.Base(file)
+	if language := byFilename[base]; language != nil {
+		return language.Name
+	}
+	ext := strings.ToLower(filepath.Ext(base))
+	if language := byExtension[ext]; language != nil {
+		return language.Name
+	}
+	if ext != "" {
+		return ""
+	}
+	if language := byInterpreter[shebangInterpreter(filepath.Join(dir, file))]; language != nil {
+		return language.Name
+	}
+	return ""
+}
+
+// shebangInterpreter returns the interpreter named on the shebang line of
+// the file at path without its version, such as "python" for
+// "#!/usr/bin/env python3", or "" without one.
+func shebangInterpreter(path string) string {
+	f, err := utils.OpenRegular(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	line, _ := bufio.NewReader(io.LimitReader(f, 512)).ReadString('\n')
+	if !strings.HasPrefix(line, "#!") {
+		return ""
+	}
+	fields := strings.Fields(line[2:])
+	if len(fields) == 0 {
+		return ""
+	}
+	interpreter := filepath.Base(fields[0])
+	// env takes options before the interpreter, such as -S
+	if interpreter == "env" {
+		interpreter = ""
+		for _, field := range fields[1:] {
+			if !strings.HasPrefix(field, "-") && !strings.Contains(field, "=") {
+				interpreter = field
+				break
+			}
+		}
+	}
+	return strings.TrimRight(interpreter, "0123456789.")
+}
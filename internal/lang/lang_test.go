@@ -0,0 +1,66 @@
+package lang
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	names, err := Parse(" JS,python,go,javascript,, TypeScript")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"JavaScript", "Python", "Go", "TypeScript"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("Parse = %v; expected %v", names, expected)
+	}
+
+	for _, value := range []string{"js,cobol", ","} {
+		_, err := Parse(value)
+		if err == nil || !strings.Contains(err.Error(), "js, ts, python, go") {
+			t.Errorf("Parse(%q) = %v; expected an error listing the supported languages", value, err)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"bin/deploy":   "#!/usr/bin/env python3\nprint('deploy')\n",
+		"bin/setup":    "#!/bin/bash -e\necho setup\n",
+		"bin/serve":    "#!/usr/bin/env -S node --no-warnings\n",
+		"bin/data":     "not a script\n",
+		"script.txt":   "#!/bin/sh\n",
+		"src/App.TSX":  "",
+		"Makefile":     "all:\n",
+		"docs/read.md": "",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := map[string]string{
+		"bin/deploy":   "Python",
+		"bin/setup":    "Shell",
+		"bin/serve":    "JavaScript",
+		"bin/data":     "",
+		"script.txt":   "",
+		"src/App.TSX":  "TypeScript",
+		"Makefile":     "Makefile",
+		"docs/read.md": "Markdown",
+		"missing":      "",
+	}
+	for file, expected := range tests {
+		if got := Detect(dir, file); got != expected {
+			t.Errorf("Detect(%q) = %q; expected %q", file, got, expected)
+		}
+	}
+}
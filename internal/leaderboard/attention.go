@@ -0,0 +1,54 @@
+package leaderboard
+
+import (
+	"fmt"
+
+	"github.com/xeon-zolt/codecompass/internal/config"
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+// GenerateAttentionList lists the files of report whose quality score is
+// below the attention-score of cfg, or that have a component scoring below
+// its attention-component-score, worst first. A report without a score
+// lists no files.
+func GenerateAttentionList(report *types.Report, cfg *config.Config) types.AttentionList {
+	list := types.AttentionList{
+		SchemaVersion:       types.AttentionSchemaVersion,
+		ReportSchemaVersion: report.SchemaVersion,
+		GeneratedAt:         report.GeneratedAt,
+		Repo:                report.Repo.Path,
+		Thresholds:          types.AttentionThresholds{Score: cfg.AttentionScore, Component: cfg.AttentionComponent},
+		Files:               []types.AttentionFile{},
+	}
+	if report.Score == nil {
+		return list
+	}
+
+	for _, file := range report.Score.Files {
+		var reasons []string
+		if file.Score < cfg.AttentionScore {
+			reasons = append(reasons, fmt.Sprintf("score %.1f is below %g", file.Score, cfg.AttentionScore))
+		}
+		for _, component := range config.ScoreComponents {
+			score, ok := file.Components[component]
+			if !ok || score >= cfg.AttentionComponent {
+				continue
+			}
+			reason := fmt.Sprintf("%s scored %.1f, below %g", component, score, cfg.AttentionComponent)
+			if detail := file.Details[component]; detail != "" {
+				reason += ": " + detail
+			}
+			reasons = append(reasons, reason)
+		}
+		if len(reasons) == 0 {
+			continue
+		}
+		list.Files = append(list.Files, types.AttentionFile{
+			Path:       file.Path,
+			Score:      file.Score,
+			Components: file.Components,
+			Reasons:    reasons,
+		})
+	}
+	return list
+}
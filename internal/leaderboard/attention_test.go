@@ -0,0 +1,72 @@
+package leaderboard
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/xeon-zolt/codecompass/internal/config"
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+func TestGenerateAttentionList(t *testing.T) {
+	report := &types.Report{
+		SchemaVersion: 33,
+		GeneratedAt:   goldenNow,
+		Repo:          types.RepoInfo{Path: "/src/acme"},
+		Score: &types.ScoreStats{
+			Files: []types.FileScore{
+				{
+					Rank: 1, Path: "src/server.go", Score: 42.5,
+					Components: map[string]float64{"issues": 80, "debt": 50, "churn": 80, "complexity": 0},
+					Details: map[string]string{
+						"issues":     "4 issues, 1.00 per 100 lines",
+						"debt":       "10 debt markers, 2.50 per 100 lines",
+						"churn":      "12 changes",
+						"complexity": "100.0% of lines in long functions",
+					},
+				},
+				{
+					Rank: 2, Path: "scripts/build.py", Score: 73.3,
+					Components: map[string]float64{"issues": 20, "debt": 100, "churn": 100},
+					Details:    map[string]string{"issues": "9 issues, 4.00 per 100 lines"},
+				},
+				{Rank: 3, Path: "src/util.go", Score: 100, Components: map[string]float64{"issues": 100, "debt": 100}},
+			},
+		},
+	}
+	cfg := config.NewConfig()
+	cfg.AttentionScore = 60
+	cfg.AttentionComponent = 25
+
+	data, err := json.MarshalIndent(GenerateAttentionList(report, cfg), "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data = append(data, '\n')
+
+	golden := filepath.Join("testdata", "attention.golden")
+	if *update {
+		if err := os.WriteFile(golden, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("Failed to read %s (run go test -update to create it): %v", golden, err)
+	}
+	if string(data) != string(expected) {
+		t.Errorf("Output does not match %s\ngot:\n%s\nexpected:\n%s", golden, data, expected)
+	}
+}
+
+func TestGenerateAttentionListWithoutScore(t *testing.T) {
+	list := GenerateAttentionList(&types.Report{SchemaVersion: types.ReportSchemaVersion}, config.NewConfig())
+	if list.Files == nil || len(list.Files) != 0 {
+		t.Errorf("Files = %#v, expected an empty list", list.Files)
+	}
+	if list.SchemaVersion != types.AttentionSchemaVersion {
+		t.Errorf("SchemaVersion = %d, expected %d", list.SchemaVersion, types.AttentionSchemaVersion)
+	}
+}
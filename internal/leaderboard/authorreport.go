@@ -0,0 +1,272 @@
+package leaderboard
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/xeon-zolt/codecompass/internal/history"
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+// AuthorPacket is what one run found about one author, gathered for a
+// conversation with them. Each part is nil when its leaderboard was not
+// generated, and zero when it was but had nothing on the author.
+type AuthorPacket struct {
+	Name  string
+	Email string
+
+	Issues   *types.LeaderboardEntry
+	Rules    []PacketCount // Most issues first
+	Files    []PacketCount // Most issues first
+	Spelling *types.SpellCheckAuthorStats
+	Commits  *types.CommitCountEntry
+	Recent   *types.RecentContributorEntry
+
+	// Previous is the last run logged before this one, nil without one.
+	// Its counts set the deltas of the packet.
+	Previous *history.AuthorSnapshot
+
+	// ToolChanges are the tools this run used at other versions than
+	// Previous, which may account for part of the deltas.
+	ToolChanges []types.ToolVersionChange
+}
+
+// PacketCount is a rule, file or word of an AuthorPacket with how often it
+// came up.
+type PacketCount struct {
+	Name  string
+	Count int
+}
+
+// BuildAuthorPackets returns the packets of the authors with the given
+// emails, matched case-insensitively, in the order given. Without emails it
+// returns those of the topN authors with the most issues. stats has the
+// per-rule and per-file counts of the authors, by email.
+func BuildAuthorPackets(report *types.Report, stats map[string]*types.AuthorStats, emails []string, topN int) []AuthorPacket {
+	if len(emails) == 0 {
+		for _, entry := range report.Authors[:min(topN, len(report.Authors))] {
+			emails = append(emails, entry.Email)
+		}
+	}
+
+	var packets []AuthorPacket
+	seen := make(map[string]bool)
+	for _, email := range emails {
+		key := strings.ToLower(strings.TrimSpace(email))
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		packets = append(packets, buildAuthorPacket(report, stats, strings.TrimSpace(email)))
+	}
+	return packets
+}
+
+func buildAuthorPacket(report *types.Report, stats map[string]*types.AuthorStats, email string) AuthorPacket {
+	packet := AuthorPacket{Email: email}
+	named := func(name, email string) {
+		if packet.Name == "" {
+			packet.Name = name
+		}
+		packet.Email = email
+	}
+
+	// Without a lint source the author leaderboard is empty, not clean
+	if report.Requested("authors") && len(report.LintSources) > 0 {
+		packet.Issues = &types.LeaderboardEntry{Email: email}
+		for i := range report.Authors {
+			if strings.EqualFold(report.Authors[i].Email, email) {
+				packet.Issues = &report.Authors[i]
+				named(report.Authors[i].Name, report.Authors[i].Email)
+				break
+			}
+		}
+		for statsEmail, author := range stats {
+			if strings.EqualFold(statsEmail, email) {
+				packet.Rules = sortedCounts(author.Rules)
+				packet.Files = sortedCounts(author.Files)
+				break
+			}
+		}
+	}
+
+	if report.Requested("spellcheck") {
+		packet.Spelling = &types.SpellCheckAuthorStats{Email: email}
+		for authorEmail, author := range report.SpellCheckAuthors {
+			if strings.EqualFold(authorEmail, email) {
+				packet.Spelling = author
+				named(author.Name, authorEmail)
+				break
+			}
+		}
+	}
+
+	if report.Requested("commits") {
+		packet.Commits = &types.CommitCountEntry{Email: email}
+		for i := range report.Commits {
+			if strings.EqualFold(report.Commits[i].Email, email) {
+				packet.Commits = &report.Commits[i]
+				named(report.Commits[i].Name, report.Commits[i].Email)
+				break
+			}
+		}
+	}
+
+	if report.Requested("recent") {
+		packet.Recent = &types.RecentContributorEntry{Email: email}
+		for i := range report.Recent {
+			if strings.EqualFold(report.Recent[i].Email, email) {
+				packet.Recent = &report.Recent[i]
+				named(report.Recent[i].Name, report.Recent[i].Email)
+				break
+			}
+		}
+	}
+
+	return packet
+}
+
+// sortedCounts returns counts most first, ties broken by name.
+func sortedCounts(counts map[string]int) []PacketCount {
+	var sorted []PacketCount
+	for name, count := range counts {
+		sorted = append(sorted, PacketCount{Name: name, Count: count})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Count != sorted[j].Count {
+			return sorted[i].Count > sorted[j].Count
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+// AuthorPacketFilename returns the name of the Markdown file of the author
+// with email: the email lowercased, with "@" spelled out and every other
+// character that is not a letter, digit, dot, dash or underscore replaced,
+// so it is safe on any file system.
+func AuthorPacketFilename(email string) string {
+	var name strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(email)) {
+		switch {
+		case r == '@':
+			name.WriteString("_at_")
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			name.WriteRune(r)
+		default:
+			name.WriteRune('_')
+		}
+	}
+	base := strings.TrimLeft(name.String(), ".")
+	if base == "" {
+		base = "author"
+	}
+	return base + ".md"
+}
+
+// WriteAuthorPacketMarkdown writes packet as a Markdown document, listing
+// the topN rules, files and misspelled words of the author.
+func WriteAuthorPacketMarkdown(w io.Writer, packet AuthorPacket, generatedAt time.Time, topN int) error {
+	var b strings.Builder
+	title := packet.Email
+	if packet.Name != "" {
+		title = fmt.Sprintf("%s <%s>", packet.Name, packet.Email)
+	}
+	fmt.Fprintf(&b, "# %s\n\n", markdownEscape(title))
+	fmt.Fprintf(&b, "Generated %s", generatedAt.Local().Format("2006-01-02 15:04"))
+	if packet.Previous != nil {
+		fmt.Fprintf(&b, ", compared with the run of %s", packet.Previous.Time.Format("2006-01-02 15:04"))
+	}
+	b.WriteString(".\n")
+	if len(packet.ToolChanges) > 0 {
+		changes := make([]string, len(packet.ToolChanges))
+		for i, change := range packet.ToolChanges {
+			changes[i] = fmt.Sprintf("%s %s → %s", change.Tool, change.A, change.B)
+		}
+		fmt.Fprintf(&b, "\n> Tool versions changed since that run (%s), so part of the changes may come from the tools.\n", markdownEscape(strings.Join(changes, ", ")))
+	}
+
+	if issues := packet.Issues; issues != nil {
+		b.WriteString("\n## Lint Issues\n\n")
+		if issues.Count == 0 {
+			b.WriteString("No lint issues are attributed to this author")
+		} else {
+			fmt.Fprintf(&b, "Issues: %d (errors: %d, warnings: %d) in %d files", issues.Count, issues.Errors, issues.Warnings, issues.Files)
+		}
+		if packet.Previous != nil && packet.Previous.Issues != nil {
+			b.WriteString(", " + formatDelta(issues.Count-packet.Previous.Issues[packet.Email]))
+		}
+		b.WriteString(".\n")
+		writePacketCounts(&b, "By Rule", "Rule", packet.Rules, topN, false)
+		writePacketCounts(&b, "By File", "File", packet.Files, topN, true)
+	}
+
+	if spelling := packet.Spelling; spelling != nil {
+		b.WriteString("\n## Spelling\n\n")
+		if spelling.TotalErrors == 0 {
+			b.WriteString("No spelling mistakes are attributed to this author.\n")
+		} else {
+			fmt.Fprintf(&b, "Spelling mistakes: %d in %d files.\n", spelling.TotalErrors, len(spelling.Files))
+			writePacketCounts(&b, "Common Mistakes", "Word", sortedCounts(spelling.CommonMistakes), topN, false)
+			writePacketCounts(&b, "By File", "File", sortedCounts(spelling.Files), topN, true)
+		}
+	}
+
+	if packet.Commits != nil || packet.Recent != nil {
+		b.WriteString("\n## Activity\n\n")
+	}
+	if commits := packet.Commits; commits != nil {
+		if commits.Commits == 0 {
+			b.WriteString("- Commits: none")
+		} else {
+			fmt.Fprintf(&b, "- Commits: %d, from %s to %s", commits.Commits, commits.FirstCommit.Format("2006-01-02"), commits.LastCommit.Format("2006-01-02"))
+		}
+		if packet.Previous != nil && packet.Previous.Commits != nil {
+			b.WriteString(", " + formatDelta(commits.Commits-packet.Previous.Commits[packet.Email]))
+		}
+		b.WriteString("\n")
+	}
+	if recent := packet.Recent; recent != nil {
+		fmt.Fprintf(&b, "- Commits in the last 30 days: %d\n", recent.RecentCommits)
+		if !recent.LastCommit.IsZero() {
+			fmt.Fprintf(&b, "- Last commit: %s\n", recent.LastCommit.Format("2006-01-02"))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writePacketCounts writes the topN of counts as a Markdown table under
+// heading, or nothing without counts.
+func writePacketCounts(b *strings.Builder, heading, column string, counts []PacketCount, topN int, path bool) {
+	if len(counts) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n### %s\n\n", heading)
+	fmt.Fprintf(b, "| # | %s | Count |\n", column)
+	fmt.Fprintf(b, "|--:|%s|------:|\n", strings.Repeat("-", len(column)+2))
+	for i, count := range counts[:min(topN, len(counts))] {
+		name := markdownEscape(count.Name)
+		if path {
+			name = "`" + markdownEscape(filepath.ToSlash(count.Name)) + "`"
+		}
+		fmt.Fprintf(b, "| %d | %s | %d |\n", i+1, name, count.Count)
+	}
+}
+
+// formatDelta describes the change of a count since the previous run.
+func formatDelta(delta int) string {
+	switch {
+	case delta > 0:
+		return fmt.Sprintf("%d more than in the previous run", delta)
+	case delta < 0:
+		return fmt.Sprintf("%d fewer than in the previous run", -delta)
+	default:
+		return "as many as in the previous run"
+	}
+}
@@ -0,0 +1,108 @@
+package leaderboard
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/xeon-zolt/codecompass/internal/history"
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+func TestBuildAuthorPackets(t *testing.T) {
+	report := &types.Report{
+		Leaderboards: []string{"authors", "commits"},
+		LintSources:  []types.LintSourceResult{{Name: "eslint", Issues: 4}},
+		Authors: []types.LeaderboardEntry{
+			{Rank: 1, Name: "Alice", Email: "alice@example.com", Count: 3},
+			{Rank: 2, Name: "Bob", Email: "bob@example.com", Count: 1},
+		},
+		Commits: []types.CommitCountEntry{{Rank: 1, Name: "Carol", Email: "carol@example.com", Commits: 7}},
+	}
+	stats := map[string]*types.AuthorStats{
+		"alice@example.com": {Rules: map[string]int{"eqeqeq": 1, "no-console": 2}, Files: map[string]int{"a.js": 3}},
+	}
+
+	packets := BuildAuthorPackets(report, stats, nil, 1)
+	if len(packets) != 1 || packets[0].Email != "alice@example.com" || packets[0].Name != "Alice" {
+		t.Fatalf("BuildAuthorPackets without emails = %+v; expected the top author", packets)
+	}
+	expectedRules := []PacketCount{{"no-console", 2}, {"eqeqeq", 1}}
+	if rules := packets[0].Rules; len(rules) != 2 || rules[0] != expectedRules[0] || rules[1] != expectedRules[1] {
+		t.Errorf("Rules = %v; expected %v", rules, expectedRules)
+	}
+	if packets[0].Spelling != nil || packets[0].Recent != nil {
+		t.Errorf("Packet has parts of leaderboards that were not generated: %+v", packets[0])
+	}
+
+	packets = BuildAuthorPackets(report, stats, []string{" Carol@Example.com", "", "carol@example.com"}, 1)
+	if len(packets) != 1 {
+		t.Fatalf("BuildAuthorPackets kept %d packets; expected repeated and empty emails dropped", len(packets))
+	}
+	carol := packets[0]
+	if carol.Email != "carol@example.com" || carol.Name != "Carol" || carol.Commits.Commits != 7 {
+		t.Errorf("Packet = %+v; expected Carol's commits", carol)
+	}
+	if carol.Issues == nil || carol.Issues.Count != 0 {
+		t.Errorf("Issues = %+v; expected none for an author without issues", carol.Issues)
+	}
+}
+
+func TestAuthorPacketFilename(t *testing.T) {
+	tests := map[string]string{
+		"Alice@Example.com":       "alice_at_example.com.md",
+		"../../etc/passwd@x":      "_.._etc_passwd_at_x.md",
+		"bob+tag@example.com":     "bob_tag_at_example.com.md",
+		"...":                     "author.md",
+		"":                        "author.md",
+		"dev 1@example.com":       "dev_1_at_example.com.md",
+		"ünïcode@example.com":     "_n_code_at_example.com.md",
+		"carol_x-y@sub.example.q": "carol_x-y_at_sub.example.q.md",
+	}
+	for email, expected := range tests {
+		if got := AuthorPacketFilename(email); got != expected {
+			t.Errorf("AuthorPacketFilename(%q) = %q; expected %q", email, got, expected)
+		}
+	}
+}
+
+func TestWriteAuthorPacketMarkdown(t *testing.T) {
+	packet := AuthorPacket{
+		Name:     "Alice",
+		Email:    "alice@example.com",
+		Issues:   &types.LeaderboardEntry{Count: 3, Errors: 1, Warnings: 2, Files: 2},
+		Rules:    []PacketCount{{"no-console", 2}, {"eqeqeq", 1}},
+		Files:    []PacketCount{{"src/a|b.js", 2}, {"src/c.js", 1}},
+		Spelling: &types.SpellCheckAuthorStats{TotalErrors: 2, Files: map[string]int{"docs/guide.md": 2}, CommonMistakes: map[string]int{"recieve": 2}},
+		Commits:  &types.CommitCountEntry{Commits: 12, FirstCommit: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), LastCommit: time.Date(2026, 9, 30, 0, 0, 0, 0, time.UTC)},
+		Recent:   &types.RecentContributorEntry{RecentCommits: 4, LastCommit: time.Date(2026, 9, 30, 0, 0, 0, 0, time.UTC)},
+		Previous: &history.AuthorSnapshot{
+			Time:    time.Date(2026, 9, 1, 9, 0, 0, 0, time.Local),
+			Issues:  map[string]int{"alice@example.com": 5},
+			Commits: map[string]int{"alice@example.com": 12},
+		},
+		ToolChanges: []types.ToolVersionChange{{Tool: "eslint", A: "8.57.0", B: "9.1.0"}},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteAuthorPacketMarkdown(&buf, packet, time.Date(2026, 10, 1, 9, 0, 0, 0, time.Local), 1); err != nil {
+		t.Fatal(err)
+	}
+	expected := "# Alice <alice@example.com>\n\n" +
+		"Generated 2026-10-01 09:00, compared with the run of 2026-09-01 09:00.\n\n" +
+		"> Tool versions changed since that run (eslint 8.57.0 → 9.1.0), so part of the changes may come from the tools.\n\n" +
+		"## Lint Issues\n\n" +
+		"Issues: 3 (errors: 1, warnings: 2) in 2 files, 2 fewer than in the previous run.\n\n" +
+		"### By Rule\n\n| # | Rule | Count |\n|--:|------|------:|\n| 1 | no-console | 2 |\n\n" +
+		"### By File\n\n| # | File | Count |\n|--:|------|------:|\n| 1 | `src/a\\|b.js` | 2 |\n\n" +
+		"## Spelling\n\nSpelling mistakes: 2 in 1 files.\n\n" +
+		"### Common Mistakes\n\n| # | Word | Count |\n|--:|------|------:|\n| 1 | recieve | 2 |\n\n" +
+		"### By File\n\n| # | File | Count |\n|--:|------|------:|\n| 1 | `docs/guide.md` | 2 |\n\n" +
+		"## Activity\n\n" +
+		"- Commits: 12, from 2025-03-01 to 2026-09-30, as many as in the previous run\n" +
+		"- Commits in the last 30 days: 4\n" +
+		"- Last commit: 2026-09-30\n"
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
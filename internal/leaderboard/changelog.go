@@ -0,0 +1,246 @@
+package leaderboard
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/xeon-zolt/codecompass/internal/conventional"
+	"github.com/xeon-zolt/codecompass/internal/git"
+	"github.com/xeon-zolt/codecompass/internal/types"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ChangelogTypes are the types commits are classified as, in display order.
+// Conventional types without a changelog section of their own, such as ci
+// or test, count as chore; messages that are not Conventional Commits are
+// other and would be left out of a generated changelog.
+var ChangelogTypes = []string{"feat", "fix", "chore", "docs", "refactor", "other"}
+
+// GenerateChangelogReadiness classifies the commits made between since and
+// now by their Conventional Commit type.
+func GenerateChangelogReadiness(ctx context.Context, dir string, dateType git.DateType, since, now time.Time) (*types.ChangelogStats, error) {
+	commits, err := git.GetCommitHistory(ctx, dir, dateType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit history: %w", err)
+	}
+
+	stats := ChangelogStats(commits, since, now)
+	return &stats, nil
+}
+
+// ChangelogStats summarizes the non-merge commits made between since and
+// now.
+func ChangelogStats(commits []types.CommitInfo, since, now time.Time) types.ChangelogStats {
+	stats := types.ChangelogStats{Since: since, Types: newTypeCounts()}
+
+	authors := make(map[string]*types.ChangelogAuthorEntry)
+
+	for _, commit := range commits {
+		if commit.Merge || commit.Date.Before(since) || commit.Date.After(now) {
+			continue
+		}
+
+		message := commit.Message
+		if commit.Body != "" {
+			message += "\n\n" + commit.Body
+		}
+		parsed, err := conventional.Parse(message)
+		kind := changelogType(parsed.Type)
+		if err != nil {
+			kind = "other"
+		}
+
+		stats.Commits++
+		stats.Types[kind]++
+
+		author := authors[commit.Email]
+		if author == nil {
+			author = &types.ChangelogAuthorEntry{Email: commit.Email, Types: newTypeCounts()}
+			authors[commit.Email] = author
+		}
+		if author.Name == "" {
+			// Commits are newest first, so this is the latest name
+			author.Name = commit.Author
+		}
+		author.Commits++
+		author.Types[kind]++
+
+		listed := types.ChangelogCommit{
+			Hash:    commit.Hash,
+			Name:    commit.Author,
+			Email:   commit.Email,
+			Date:    commit.Date,
+			Subject: commit.Message,
+			Type:    parsed.Type,
+			Scope:   parsed.Scope,
+		}
+		if err != nil {
+			stats.Invisible = append(stats.Invisible, listed)
+		} else if parsed.Breaking {
+			listed.Note = parsed.BreakingNote()
+			stats.Breaking = append(stats.Breaking, listed)
+		}
+	}
+
+	stats.ConventionalPercent = conventionalPercent(stats.Commits, stats.Types)
+
+	for _, author := range authors {
+		author.ConventionalPercent = conventionalPercent(author.Commits, author.Types)
+		stats.Authors = append(stats.Authors, *author)
+	}
+	sort.SliceStable(stats.Authors, func(i, j int) bool {
+		if stats.Authors[i].Commits != stats.Authors[j].Commits {
+			return stats.Authors[i].Commits > stats.Authors[j].Commits
+		}
+		if stats.Authors[i].Name != stats.Authors[j].Name {
+			return stats.Authors[i].Name < stats.Authors[j].Name
+		}
+		return stats.Authors[i].Email < stats.Authors[j].Email
+	})
+	for i := range stats.Authors {
+		stats.Authors[i].Rank = i + 1
+	}
+
+	newestFirst := func(commits []types.ChangelogCommit) {
+		sort.SliceStable(commits, func(i, j int) bool { return commits[i].Date.After(commits[j].Date) })
+	}
+	newestFirst(stats.Invisible)
+	newestFirst(stats.Breaking)
+
+	return stats
+}
+
+// changelogType maps a Conventional Commit type to one of ChangelogTypes.
+func changelogType(commitType string) string {
+	switch commitType {
+	case "feat", "fix", "docs", "refactor":
+		return commitType
+	}
+	return "chore"
+}
+
+func newTypeCounts() map[string]int {
+	counts := make(map[string]int, len(ChangelogTypes))
+	for _, kind := range ChangelogTypes {
+		counts[kind] = 0
+	}
+	return counts
+}
+
+// conventionalPercent is the share of commits that are not of type other.
+func conventionalPercent(commits int, counts map[string]int) float64 {
+	if commits == 0 {
+		return 0
+	}
+	return float64(commits-counts["other"]) / float64(commits) * 100
+}
+
+// formatTypeCounts lists the non-zero counts in ChangelogTypes order, or all
+// of them when all is set.
+func formatTypeCounts(counts map[string]int, all bool) string {
+	var parts []string
+	for _, kind := range ChangelogTypes {
+		if counts[kind] > 0 || all {
+			parts = append(parts, fmt.Sprintf("%s %d", kind, counts[kind]))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// shortHash abbreviates a commit hash the way git log --oneline does.
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
+
+func (p *Printer) PrintChangelogReadiness(stats types.ChangelogStats, topN int) {
+	since := stats.Since.Format("2006-01-02")
+	fmt.Fprintln(p.w, p.titleStyle.Render(fmt.Sprintf("Changelog Readiness - Conventional Commits per Author (since %s)", since)))
+
+	if stats.Commits == 0 {
+		fmt.Fprintln(p.w, p.cellStyle.Render(fmt.Sprintf("📭 No commits since %s", since)))
+		return
+	}
+
+	fmt.Fprintf(p.w, "📝 %s commits, %s conventional – %s\n",
+		p.cellStyle.Render(p.formatCount(stats.Commits)),
+		p.conventionalShare(stats.ConventionalPercent), formatTypeCounts(stats.Types, true))
+
+	maxEntries := p.limit(topN, len(stats.Authors))
+
+	t := newTable(rankColumn, column{header: "Author"}, column{header: "Email"}, column{header: "Commits", right: true},
+		column{header: "Conventional", right: true}, column{header: "Types"})
+	for i := 0; i < maxEntries; i++ {
+		entry := stats.Authors[i]
+		t.row(
+			cell(p.rankStyle, fmt.Sprintf("%d", entry.Rank)),
+			cell(p.nameStyle, entry.Name),
+			cell(p.emailStyle, entry.Email),
+			p.count(entry.Commits),
+			cell(p.conventionalStyle(entry.ConventionalPercent), fmt.Sprintf("%.1f%%", entry.ConventionalPercent)),
+			formatTypeCounts(entry.Types, false),
+		)
+	}
+	p.printTable(t)
+
+	fmt.Fprintln(p.w)
+	fmt.Fprintln(p.w, p.titleStyle.Render("Invisible in a Changelog - Commits Without a Conventional Type"))
+	if len(stats.Invisible) == 0 {
+		fmt.Fprintln(p.w, p.cellStyle.Render("🎉 Every commit follows Conventional Commits"))
+	}
+	for i, commit := range stats.Invisible {
+		if i == topN {
+			fmt.Fprintln(p.w, p.emailStyle.Render(fmt.Sprintf("… and %d more", len(stats.Invisible)-topN)))
+			break
+		}
+		fmt.Fprintf(p.w, "%s %s – %s\n",
+			p.rankStyle.Render(shortHash(commit.Hash)), p.nameStyle.Render(commit.Name), p.warningStyle.Render(commit.Subject))
+	}
+
+	fmt.Fprintln(p.w)
+	fmt.Fprintln(p.w, p.titleStyle.Render("Breaking Changes"))
+	if len(stats.Breaking) == 0 {
+		fmt.Fprintln(p.w, p.cellStyle.Render("No breaking changes marked"))
+	}
+	for i, commit := range stats.Breaking {
+		if i == topN {
+			fmt.Fprintln(p.w, p.emailStyle.Render(fmt.Sprintf("… and %d more", len(stats.Breaking)-topN)))
+			break
+		}
+		line := fmt.Sprintf("%s %s – %s", p.rankStyle.Render(shortHash(commit.Hash)), p.nameStyle.Render(commit.Name), p.errorStyle.Render(commit.Subject))
+		// A ! marker alone has the description as its note
+		if note := firstLine(commit.Note); note != "" && !strings.Contains(commit.Subject, note) {
+			line += ": " + p.cellStyle.Render(note)
+		}
+		fmt.Fprintln(p.w, line)
+	}
+}
+
+// conventionalShare renders a conventional commit share, colored like
+// coverage.
+func (p *Printer) conventionalShare(percent float64) string {
+	return p.conventionalStyle(percent).Render(fmt.Sprintf("%.1f%%", percent))
+}
+
+func (p *Printer) conventionalStyle(percent float64) lipgloss.Style {
+	switch {
+	case percent >= 80:
+		return p.cellStyle
+	case percent >= 60:
+		return p.warningStyle
+	default:
+		return p.errorStyle
+	}
+}
+
+// firstLine returns s up to its first newline.
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
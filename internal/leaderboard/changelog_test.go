@@ -0,0 +1,66 @@
+package leaderboard
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+func TestChangelogStats(t *testing.T) {
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := since.AddDate(0, 0, 30)
+	commit := func(hash, author string, day int, subject, body string) types.CommitInfo {
+		return types.CommitInfo{Hash: hash, Author: author, Email: author + "@example.com", Date: since.AddDate(0, 0, day), Message: subject, Body: body}
+	}
+
+	// Newest first, as git log lists them
+	commits := []types.CommitInfo{
+		commit("h7", "bob", 20, "Update stuff", ""),
+		{Hash: "h6", Author: "bob", Email: "bob@example.com", Date: since.AddDate(0, 0, 19), Message: "Merge branch 'feature'", Merge: true},
+		commit("h5", "alice", 15, "feat(api)!: drop v1 endpoints", ""),
+		commit("h4", "alice", 10, "ci: cache modules", ""),
+		commit("h3", "bob", 5, "fix: handle nil config", "BREAKING CHANGE: Load now returns an error\n\nCallers must check it."),
+		commit("h2", "alice", 2, "docs: add install guide", ""),
+		commit("h1", "alice", 1, "wip", ""),
+		// Outside the window
+		commit("h0", "carol", -1, "feat: initial import", ""),
+	}
+
+	stats := ChangelogStats(commits, since, now)
+
+	if stats.Commits != 6 {
+		t.Errorf("Expected 6 commits in the window, but got %d", stats.Commits)
+	}
+	expectedTypes := map[string]int{"feat": 1, "fix": 1, "chore": 1, "docs": 1, "refactor": 0, "other": 2}
+	if !reflect.DeepEqual(stats.Types, expectedTypes) {
+		t.Errorf("Expected types %v, but got %v", expectedTypes, stats.Types)
+	}
+	if int(stats.ConventionalPercent) != 66 {
+		t.Errorf("Expected 66.7%% conventional commits, but got %.1f", stats.ConventionalPercent)
+	}
+
+	if len(stats.Authors) != 2 {
+		t.Fatalf("Expected 2 authors, but got %+v", stats.Authors)
+	}
+	alice := stats.Authors[0]
+	if alice.Rank != 1 || alice.Name != "alice" || alice.Commits != 4 || alice.ConventionalPercent != 75 {
+		t.Errorf("Expected alice first with 4 commits, 75%% conventional, but got %+v", alice)
+	}
+	if alice.Types["chore"] != 1 || alice.Types["other"] != 1 {
+		t.Errorf("Expected alice's ci commit to count as chore and wip as other, but got %v", alice.Types)
+	}
+
+	if len(stats.Invisible) != 2 || stats.Invisible[0].Hash != "h7" || stats.Invisible[1].Hash != "h1" {
+		t.Errorf("Expected h7 and h1 to be invisible, newest first, but got %+v", stats.Invisible)
+	}
+
+	expectedBreaking := []types.ChangelogCommit{
+		{Hash: "h5", Name: "alice", Email: "alice@example.com", Date: since.AddDate(0, 0, 15), Subject: "feat(api)!: drop v1 endpoints", Type: "feat", Scope: "api", Note: "drop v1 endpoints"},
+		{Hash: "h3", Name: "bob", Email: "bob@example.com", Date: since.AddDate(0, 0, 5), Subject: "fix: handle nil config", Type: "fix", Note: "Load now returns an error\n\nCallers must check it."},
+	}
+	if !reflect.DeepEqual(stats.Breaking, expectedBreaking) {
+		t.Errorf("Expected breaking changes %+v, but got %+v", expectedBreaking, stats.Breaking)
+	}
+}
@@ -0,0 +1,91 @@
+package leaderboard
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+// metricLabels are the display names of the compared metrics.
+var metricLabels = map[string]string{
+	"coverage": "Coverage",
+	"issues":   "Lint issues",
+	"errors":   "Errors",
+	"warnings": "Warnings",
+	"debt":     "Technical debt",
+	"loc":      "Lines of code",
+}
+
+// MetricLabel returns the display name of a compared metric, such as
+// "Lint issues" for issues.
+func MetricLabel(metric string) string {
+	if label := metricLabels[metric]; label != "" {
+		return label
+	}
+	return metric
+}
+
+// metricFormat is the format of the values of metric: a percentage for
+// coverage and a count otherwise.
+func metricFormat(metric string) string {
+	if metric == "coverage" {
+		return "%.1f%%"
+	}
+	return "%.0f"
+}
+
+// FormatMetric formats a value of metric, such as 81.3% for coverage.
+func FormatMetric(metric string, value float64) string {
+	return fmt.Sprintf(metricFormat(metric), value)
+}
+
+// FormatChange formats the change of delta, such as +3 or -1.8%, or "no
+// change", and reports whether it is for the better (1), the worse (-1) or
+// neither (0).
+func FormatChange(delta types.MetricDelta) (string, int) {
+	change := fmt.Sprintf("%+"+metricFormat(delta.Metric)[1:], delta.Delta)
+	switch {
+	case delta.Delta == 0:
+		return "no change", 0
+	case (delta.Delta > 0) == delta.HigherIsBetter:
+		return change, 1
+	}
+	return change, -1
+}
+
+// changeStyle is the style of a change FormatChange rated as for the
+// better, the worse or neither.
+func (p *Printer) changeStyle(rating int) lipgloss.Style {
+	switch rating {
+	case 1:
+		return p.cellStyle.Foreground(lipgloss.Color("#00FF00"))
+	case -1:
+		return p.errorStyle
+	}
+	return p.cellStyle
+}
+
+// PrintComparison prints the change of each metric from refA to refB,
+// followed by a warning for each tool the refs were analyzed with at
+// different versions.
+func (p *Printer) PrintComparison(refA, refB string, deltas []types.MetricDelta, toolChanges []types.ToolVersionChange) {
+	fmt.Fprintln(p.w, p.titleStyle.Render(fmt.Sprintf("Branch Comparison - %s → %s", refA, refB)))
+
+	for _, delta := range deltas {
+		name := p.nameStyle.Render(fmt.Sprintf("%-15s", MetricLabel(delta.Metric)))
+
+		if delta.Missing {
+			fmt.Fprintf(p.w, "%s %s\n", name, p.emailStyle.Render("not measured at both refs"))
+			continue
+		}
+
+		change, rating := FormatChange(delta)
+		fmt.Fprintf(p.w, "%s %s → %s (%s)\n", name,
+			p.cellStyle.Render(FormatMetric(delta.Metric, delta.A)), p.cellStyle.Render(FormatMetric(delta.Metric, delta.B)), p.changeStyle(rating).Render(change))
+	}
+
+	for _, change := range toolChanges {
+		fmt.Fprintln(p.w, p.warningStyle.Render(fmt.Sprintf("  ⚠️  %s %s at %s but %s at %s; the change may come from the tool", change.Tool, change.A, refA, change.B, refB)))
+	}
+}
@@ -0,0 +1,236 @@
+package leaderboard
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+const (
+	// dashboardRows is how many entries of each leaderboard the dashboard
+	// lists
+	dashboardRows = 3
+	// dashboardWidth is the width the dashboard is laid out in when the
+	// output width is unknown
+	dashboardWidth = 100
+	// minDashboardColumn is the narrowest a column of the dashboard gets;
+	// on narrower output the columns go one under the other
+	minDashboardColumn = 40
+	// dashboardGap separates the columns of the dashboard
+	dashboardGap = 2
+	// gaugeWidth is how many cells the coverage gauge takes
+	gaugeWidth = 20
+)
+
+// DashboardTotal is one repository total the dashboard shows.
+type DashboardTotal struct {
+	Metric         string // issues, errors, warnings, debt or coverage
+	Value          float64
+	HigherIsBetter bool
+}
+
+// DashboardTotals returns the totals of report the dashboard shows, in
+// order: issues, errors, warnings, debt and coverage. Totals report did
+// not measure are left out. They are the totals a logged run records, so
+// they can be compared with those of an earlier run.
+func DashboardTotals(report *types.Report) []DashboardTotal {
+	var totals []DashboardTotal
+	if summary := report.Summary; summary != nil {
+		totals = append(totals,
+			DashboardTotal{Metric: "issues", Value: float64(summary.TotalIssues)},
+			DashboardTotal{Metric: "errors", Value: float64(summary.Errors)},
+			DashboardTotal{Metric: "warnings", Value: float64(summary.Warnings)})
+	}
+	if _, failed := report.Failures["debt"]; !failed && (report.Requested("debt") || len(report.TechnicalDebt) > 0) {
+		debt := 0
+		for _, entry := range report.TechnicalDebt {
+			debt += entry.TotalDebt
+		}
+		totals = append(totals, DashboardTotal{Metric: "debt", Value: float64(debt)})
+	}
+	if len(report.Coverage) > 0 {
+		totals = append(totals, DashboardTotal{Metric: "coverage", Value: report.OverallCoverage, HigherIsBetter: true})
+	}
+	return totals
+}
+
+// PrintDashboard prints a summary of report that fits one screen: its
+// totals, with their change since trend when there is one, the number of
+// warnings of the run, a coverage gauge against threshold, and the top
+// entries of the author, file, rule, debt and coverage leaderboards. The
+// summary is laid out in two columns sized to the output width.
+func (p *Printer) PrintDashboard(report *types.Report, trend *types.RunTrend, threshold float64) {
+	fmt.Fprintln(p.w, p.titleStyle.Render("Quality Dashboard"))
+	if trend != nil {
+		fmt.Fprintln(p.w, p.emailStyle.Render(fmt.Sprintf("Changes since the run logged %s ago", formatDuration(p.now().Sub(trend.Since)))))
+	}
+	fmt.Fprintln(p.w)
+
+	width := p.width
+	if width <= 0 {
+		width = dashboardWidth
+	}
+	columnWidth := (width - dashboardGap) / 2
+	stacked := columnWidth < minDashboardColumn
+	if stacked {
+		columnWidth = width
+	}
+
+	deltas := make(map[string]types.MetricDelta)
+	if trend != nil {
+		for _, delta := range trend.Deltas {
+			if !delta.Missing {
+				deltas[delta.Metric] = delta
+			}
+		}
+	}
+
+	left := p.dashboardTotals(report, deltas, trend != nil, columnWidth)
+	left = append(left, "", p.coverageGauge(report, deltas, threshold), "")
+	left = append(left, p.dashboardBlock(report, "authors", columnWidth, p.topAuthors(report))...)
+
+	var right []string
+	for i, block := range [][]string{
+		p.dashboardBlock(report, "files", columnWidth, p.topFiles(report)),
+		p.dashboardBlock(report, "rules", columnWidth, p.topRules(report)),
+		p.dashboardBlock(report, "debt", columnWidth, p.topDebt(report)),
+		p.dashboardBlock(report, "coverage", columnWidth, p.worstCoverage(report, threshold)),
+	} {
+		if i > 0 {
+			right = append(right, "")
+		}
+		right = append(right, block...)
+	}
+
+	for i := range left {
+		left[i] = ansi.Truncate(left[i], columnWidth, "")
+	}
+	for i := range right {
+		right[i] = ansi.Truncate(right[i], columnWidth, "")
+	}
+
+	var lines string
+	if stacked {
+		lines = strings.Join(append(append(left, ""), right...), "\n")
+	} else {
+		column := p.renderer.NewStyle().Width(columnWidth + dashboardGap)
+		lines = lipgloss.JoinHorizontal(lipgloss.Top, column.Render(strings.Join(left, "\n")), strings.Join(right, "\n"))
+	}
+	for _, line := range strings.Split(lines, "\n") {
+		fmt.Fprintln(p.w, strings.TrimRight(line, " "))
+	}
+}
+
+// dashboardTotals lays out the totals of report but coverage, which has a
+// gauge, with their change in deltas, followed by the number of warnings
+// of the run.
+func (p *Printer) dashboardTotals(report *types.Report, deltas map[string]types.MetricDelta, trend bool, width int) []string {
+	columns := []column{{header: "Total"}, {header: "Now", right: true}}
+	if trend {
+		columns = append(columns, column{header: "Change", right: true})
+	}
+	t := newTable(columns...)
+	for _, total := range DashboardTotals(report) {
+		if total.Metric == "coverage" {
+			continue
+		}
+		cells := []string{cell(p.nameStyle, MetricLabel(total.Metric)), p.count(int(total.Value))}
+		if delta, ok := deltas[total.Metric]; ok {
+			change, rating := FormatChange(delta)
+			cells = append(cells, cell(p.changeStyle(rating), change))
+		}
+		t.row(cells...)
+	}
+	t.row(cell(p.nameStyle, "Run warnings"), cell(p.warningStyle, p.formatCount(len(report.Warnings))))
+	return p.renderTableWidth(t, width)
+}
+
+// coverageGauge shows the overall coverage of report as a bar, in the
+// error color below threshold, with its change in deltas.
+func (p *Printer) coverageGauge(report *types.Report, deltas map[string]types.MetricDelta, threshold float64) string {
+	label := "  " + p.headerStyle.Render("Coverage") + " "
+	if len(report.Coverage) == 0 {
+		return label + cell(p.emailStyle, "not measured")
+	}
+
+	percent := max(0, min(100, report.OverallCoverage))
+	filled := int(percent/100*gaugeWidth + 0.5)
+	style := p.changeStyle(1)
+	if percent < threshold {
+		style = p.errorStyle
+	}
+	gauge := label + cell(style, strings.Repeat("█", filled)) + cell(p.emailStyle, strings.Repeat("░", gaugeWidth-filled)) +
+		" " + cell(style, FormatMetric("coverage", percent))
+	if delta, ok := deltas["coverage"]; ok {
+		change, rating := FormatChange(delta)
+		gauge += " " + cell(p.changeStyle(rating), "("+change+")")
+	}
+	return gauge
+}
+
+// dashboardBlock lays out the top entries of the leaderboard named name in
+// t, or says why there are none.
+func (p *Printer) dashboardBlock(report *types.Report, name string, width int, t *table) []string {
+	var note string
+	switch _, failed := report.Failures[name]; {
+	case failed:
+		note = cell(p.errorStyle, "failed, see --"+name)
+	case !report.Requested(name):
+		note = cell(p.emailStyle, "not measured")
+	case len(t.rows) == 0:
+		note = cell(p.emailStyle, "none")
+	default:
+		return p.renderTableWidth(t, width)
+	}
+	t.rows = nil
+	return append(p.renderTableWidth(t, width), "  "+note)
+}
+
+func (p *Printer) topAuthors(report *types.Report) *table {
+	t := newTable(rankColumn, column{header: "Author"}, column{header: "Issues", right: true})
+	for i, entry := range report.Authors[:min(dashboardRows, len(report.Authors))] {
+		t.row(cell(p.rankStyle, fmt.Sprintf("%d", i+1)), cell(p.nameStyle, entry.Name), p.count(entry.Count))
+	}
+	return t
+}
+
+func (p *Printer) topFiles(report *types.Report) *table {
+	t := newTable(rankColumn, fileColumn, column{header: "Issues", right: true})
+	for i, entry := range report.Files[:min(dashboardRows, len(report.Files))] {
+		t.row(cell(p.rankStyle, fmt.Sprintf("%d", i+1)), p.pathCell(p.cellStyle, entry.Path), p.count(entry.Count))
+	}
+	return t
+}
+
+func (p *Printer) topRules(report *types.Report) *table {
+	t := newTable(rankColumn, column{header: "Rule"}, column{header: "Violations", right: true})
+	for i, entry := range report.Rules[:min(dashboardRows, len(report.Rules))] {
+		t.row(cell(p.rankStyle, fmt.Sprintf("%d", i+1)), cell(p.topRuleStyle, entry.Rule), p.count(entry.Count))
+	}
+	return t
+}
+
+func (p *Printer) topDebt(report *types.Report) *table {
+	t := newTable(rankColumn, fileColumn, column{header: "Debt", right: true})
+	for i, entry := range report.TechnicalDebt[:min(dashboardRows, len(report.TechnicalDebt))] {
+		t.row(cell(p.rankStyle, fmt.Sprintf("%d", i+1)), p.pathCell(p.cellStyle, entry.Path), p.count(entry.TotalDebt))
+	}
+	return t
+}
+
+// worstCoverage lists the least covered files, which the coverage
+// leaderboard ranks first, in the error color below threshold.
+func (p *Printer) worstCoverage(report *types.Report, threshold float64) *table {
+	t := newTable(rankColumn, fileColumn, column{header: "Coverage", right: true})
+	for i, entry := range report.Coverage[:min(dashboardRows, len(report.Coverage))] {
+		style := p.cellStyle
+		if entry.CoveragePercent < threshold {
+			style = p.errorStyle
+		}
+		t.row(cell(p.rankStyle, fmt.Sprintf("%d", i+1)), p.pathCell(p.cellStyle, entry.Path), cell(style, FormatMetric("coverage", entry.CoveragePercent)))
+	}
+	return t
+}
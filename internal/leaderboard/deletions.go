@@ -0,0 +1,189 @@
+package leaderboard
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/xeon-zolt/codecompass/internal/detect"
+	"github.com/xeon-zolt/codecompass/internal/git"
+	"github.com/xeon-zolt/codecompass/internal/types"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// GenerateDeletionStats credits the lines removed by the non-merge commits
+// on HEAD made between since and now. Vendored and generated files are not
+// counted unless includeVendored is set.
+func GenerateDeletionStats(ctx context.Context, dir string, dateType git.DateType, since, now time.Time, includeVendored bool) (*types.DeletionStats, error) {
+	commits, err := git.GetCommitLineChanges(ctx, dir, dateType, since)
+	if err != nil {
+		return nil, err
+	}
+
+	skip := func(string) bool { return false }
+	if !includeVendored {
+		seen := make(map[string]bool)
+		var paths []string
+		for _, commit := range commits {
+			for _, file := range commit.Files {
+				if !seen[file.Path] {
+					seen[file.Path] = true
+					paths = append(paths, file.Path)
+				}
+			}
+		}
+		sort.Strings(paths)
+
+		attributes, err := git.GetAttributes(ctx, dir, paths, detect.Attributes...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read linguist attributes: %w", err)
+		}
+		detector := detect.New(dir, attributes)
+		skip = func(path string) bool { return detector.Classify(path) != "" }
+	}
+
+	stats := DeletionStats(commits, since, now, skip)
+	return &stats, nil
+}
+
+// DeletionStats sums up the lines the non-merge commits made between since
+// and now added and deleted, by author and by commit. Binary files, files
+// only renamed and files skip reports are left out.
+func DeletionStats(commits []types.CommitInfo, since, now time.Time, skip func(path string) bool) types.DeletionStats {
+	stats := types.DeletionStats{Since: since, Authors: []types.DeletionAuthorEntry{}, Cleanups: []types.CleanupCommit{}}
+
+	authors := make(map[string]*types.DeletionAuthorEntry)
+	var order []string
+
+	for _, commit := range commits {
+		if commit.Merge || commit.Date.Before(since) || commit.Date.After(now) {
+			continue
+		}
+
+		var added, deleted int
+		counted := false
+		for _, file := range commit.Files {
+			if file.Binary || (file.OldPath != "" && file.Added == 0 && file.Deleted == 0) || skip(file.Path) {
+				continue
+			}
+			added += file.Added
+			deleted += file.Deleted
+			counted = true
+		}
+		if !counted {
+			continue
+		}
+
+		stats.Commits++
+		stats.AddedLines += added
+		stats.DeletedLines += deleted
+
+		author := authors[commit.Email]
+		if author == nil {
+			// Commits are newest first, so this is the latest name
+			author = &types.DeletionAuthorEntry{Name: commit.Author, Email: commit.Email}
+			authors[commit.Email] = author
+			order = append(order, commit.Email)
+		}
+		author.Commits++
+		author.AddedLines += added
+		author.DeletedLines += deleted
+		author.NetRemoved += deleted - added
+
+		if deleted > added {
+			stats.Cleanups = append(stats.Cleanups, types.CleanupCommit{
+				Hash:         commit.Hash,
+				Name:         commit.Author,
+				Email:        commit.Email,
+				Date:         commit.Date,
+				Subject:      commit.Message,
+				AddedLines:   added,
+				DeletedLines: deleted,
+				NetRemoved:   deleted - added,
+			})
+		}
+	}
+
+	for _, email := range order {
+		if author := authors[email]; author.NetRemoved > 0 {
+			stats.Authors = append(stats.Authors, *author)
+		}
+	}
+	sort.SliceStable(stats.Authors, func(i, j int) bool {
+		if stats.Authors[i].NetRemoved != stats.Authors[j].NetRemoved {
+			return stats.Authors[i].NetRemoved > stats.Authors[j].NetRemoved
+		}
+		if stats.Authors[i].Name != stats.Authors[j].Name {
+			return stats.Authors[i].Name < stats.Authors[j].Name
+		}
+		return stats.Authors[i].Email < stats.Authors[j].Email
+	})
+	for i := range stats.Authors {
+		stats.Authors[i].Rank = i + 1
+	}
+
+	// Cleanups removing as much as each other stay newest first
+	sort.SliceStable(stats.Cleanups, func(i, j int) bool { return stats.Cleanups[i].NetRemoved > stats.Cleanups[j].NetRemoved })
+
+	return stats
+}
+
+// PrintDeletionStats celebrates the authors who removed the most lines and
+// the commits that removed the most, in green where other leaderboards
+// point at problems.
+func (p *Printer) PrintDeletionStats(stats types.DeletionStats, topN int) {
+	since := stats.Since.Format("2006-01-02")
+	celebrate := p.changeStyle(1)
+	title := p.titleStyle.Background(lipgloss.Color("#005f00"))
+	fmt.Fprintln(p.w, title.Render(fmt.Sprintf("Cleanup Crew - Lines Removed per Author (since %s)", since)))
+
+	if stats.Commits == 0 {
+		fmt.Fprintln(p.w, p.cellStyle.Render(fmt.Sprintf("📭 No commits since %s", since)))
+		return
+	}
+
+	fmt.Fprintf(p.w, "🧹 %s lines removed and %s added in %s commits\n",
+		celebrate.Render(p.formatCount(stats.DeletedLines)), p.formatCount(stats.AddedLines), p.formatCount(stats.Commits))
+
+	if len(stats.Authors) == 0 {
+		fmt.Fprintln(p.w, p.cellStyle.Render("🌱 The codebase grew: nobody removed more lines than they added"))
+		return
+	}
+
+	maxEntries := p.limit(topN, len(stats.Authors))
+
+	t := newTable(rankColumn, column{header: "Author"}, column{header: "Email"}, column{header: "Commits", right: true},
+		column{header: "Removed", right: true}, column{header: "Added", right: true}, column{header: "Net Removed", right: true})
+	for i := 0; i < maxEntries; i++ {
+		entry := stats.Authors[i]
+		t.row(
+			cell(p.rankStyle, fmt.Sprintf("%d", entry.Rank)),
+			cell(p.nameStyle, entry.Name),
+			cell(p.emailStyle, entry.Email),
+			p.count(entry.Commits),
+			p.count(entry.DeletedLines),
+			p.count(entry.AddedLines),
+			cell(celebrate, p.formatCount(entry.NetRemoved)),
+		)
+	}
+	p.printTable(t)
+
+	fmt.Fprintln(p.w)
+	fmt.Fprintln(p.w, title.Render("Cleanup Commits - Most Lines Removed"))
+
+	maxEntries = p.limit(topN, len(stats.Cleanups))
+
+	t = newTable(column{header: "Commit"}, column{header: "Author"}, column{header: "Net Removed", right: true}, column{header: "Subject"})
+	for i := 0; i < maxEntries; i++ {
+		commit := stats.Cleanups[i]
+		t.row(
+			cell(p.rankStyle, shortHash(commit.Hash)),
+			cell(p.nameStyle, commit.Name),
+			cell(celebrate, p.formatCount(commit.NetRemoved)),
+			cell(p.cellStyle, commit.Subject),
+		)
+	}
+	p.printTable(t)
+}
@@ -0,0 +1,60 @@
+package leaderboard
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+func TestDeletionStats(t *testing.T) {
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := since.AddDate(0, 0, 30)
+	commit := func(hash, author string, day int, subject string, files ...types.FileLineChange) types.CommitInfo {
+		return types.CommitInfo{Hash: hash, Author: author, Email: author + "@example.com", Date: since.AddDate(0, 0, day), Message: subject, Files: files}
+	}
+	lines := func(path string, added, deleted int) types.FileLineChange {
+		return types.FileLineChange{Path: path, Added: added, Deleted: deleted}
+	}
+
+	// Newest first, as git log lists them
+	commits := []types.CommitInfo{
+		commit("h7", "bob", 20, "Drop unused flags", lines("src/flags.go", 0, 30), lines("src/main.go", 2, 5)),
+		commit("h6", "bob", 18, "Move the parser", types.FileLineChange{Path: "src/parser/parse.go", OldPath: "src/parse.go"}),
+		commit("h5", "alice", 15, "Update vendored lib", lines("vendor/lib/lib.go", 0, 500)),
+		commit("h4", "alice", 10, "Remove the legacy parser", lines("src/legacy.go", 0, 200), types.FileLineChange{Path: "logo.png", Binary: true}),
+		commit("h3", "carol", 5, "Add the exporter", lines("src/export.go", 120, 0)),
+		commit("h2", "alice", 2, "Tidy up", lines("src/main.go", 10, 30)),
+		{Hash: "h1", Author: "bob", Email: "bob@example.com", Date: since.AddDate(0, 0, 1), Message: "Merge", Merge: true, Files: []types.FileLineChange{lines("src/a.go", 0, 900)}},
+		// Outside the window
+		commit("h0", "carol", -1, "Delete everything", lines("src/main.go", 0, 1000)),
+	}
+
+	stats := DeletionStats(commits, since, now, func(path string) bool { return strings.HasPrefix(path, "vendor/") })
+
+	// The rename, the vendored update and the merge do not count
+	if stats.Commits != 4 || stats.AddedLines != 132 || stats.DeletedLines != 265 {
+		t.Errorf("Expected 4 commits adding 132 and deleting 265 lines, but got %+v", stats)
+	}
+
+	expectedAuthors := []types.DeletionAuthorEntry{
+		{Rank: 1, Name: "alice", Email: "alice@example.com", Commits: 2, AddedLines: 10, DeletedLines: 230, NetRemoved: 220},
+		{Rank: 2, Name: "bob", Email: "bob@example.com", Commits: 1, AddedLines: 2, DeletedLines: 35, NetRemoved: 33},
+	}
+	if !reflect.DeepEqual(stats.Authors, expectedAuthors) {
+		t.Errorf("Expected authors %+v, but got %+v", expectedAuthors, stats.Authors)
+	}
+
+	var hashes []string
+	for _, cleanup := range stats.Cleanups {
+		hashes = append(hashes, cleanup.Hash)
+	}
+	if strings.Join(hashes, ",") != "h4,h7,h2" {
+		t.Errorf("Expected cleanups h4, h7 and h2, most removed first, but got %v", hashes)
+	}
+	if cleanup := stats.Cleanups[0]; cleanup.Subject != "Remove the legacy parser" || cleanup.NetRemoved != 200 || cleanup.Name != "alice" {
+		t.Errorf("Expected the legacy parser removal first, but got %+v", cleanup)
+	}
+}
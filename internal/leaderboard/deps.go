@@ -0,0 +1,111 @@
+package leaderboard
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/xeon-zolt/codecompass/internal/deps"
+	"github.com/xeon-zolt/codecompass/internal/types"
+	"github.com/xeon-zolt/codecompass/internal/utils"
+)
+
+// GenerateDependencyLeaderboard counts the dependencies each lockfile and
+// requirements file of files declares, as deps reads them, without touching
+// the network. Files that cannot be read are skipped with a warning.
+// Entries are ranked by the share of their direct dependencies that are
+// pinned, lowest first; files that do not tell their direct dependencies
+// come last.
+func GenerateDependencyLeaderboard(dir string, files []string, warnings *utils.WarningCollector) []types.DependencyEntry {
+	var entries []types.DependencyEntry
+	for _, file := range files {
+		manifest, err := deps.Read(dir, file)
+		if err != nil {
+			warnings.Add(types.NewWarning("Dependency file skipped", "file", file, "error", err))
+			continue
+		}
+
+		entry := types.DependencyEntry{Path: file, Ecosystem: manifest.Ecosystem, Direct: len(manifest.Direct), Locked: manifest.Locked}
+		for name, spec := range manifest.Direct {
+			switch deps.Classify(manifest.Ecosystem, spec) {
+			case deps.Pinned:
+				entry.Pinned++
+			case deps.Ranged:
+				entry.Ranged++
+				entry.RangedPackages = append(entry.RangedPackages, name)
+			}
+		}
+		sort.Strings(entry.RangedPackages)
+		if entry.Direct > 0 {
+			entry.PinnedPercent = float64(entry.Pinned) / float64(entry.Direct) * 100
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if (a.Direct > 0) != (b.Direct > 0) {
+			return a.Direct > 0
+		}
+		if a.PinnedPercent != b.PinnedPercent {
+			return a.PinnedPercent < b.PinnedPercent
+		}
+		if a.Ranged != b.Ranged {
+			return a.Ranged > b.Ranged
+		}
+		return a.Path < b.Path
+	})
+	for i := range entries {
+		entries[i].Rank = i + 1
+	}
+	return entries
+}
+
+// maxRangedPackages is how many ranged packages a row of the dependency
+// leaderboard names.
+const maxRangedPackages = 3
+
+func (p *Printer) PrintDependencyLeaderboard(entries []types.DependencyEntry, topN int) {
+	fmt.Fprintln(p.w, p.titleStyle.Render("Dependency Leaderboard - Pinned vs Ranged Dependencies"))
+
+	if len(entries) == 0 {
+		fmt.Fprintln(p.w, p.cellStyle.Render("📭 No package-lock.json, yarn.lock or requirements file found"))
+		return
+	}
+
+	maxEntries := p.limit(topN, len(entries))
+
+	t := newTable(rankColumn, fileColumn, column{header: "Ecosystem"}, column{header: "Direct", right: true},
+		column{header: "Pinned", right: true}, column{header: "Ranged", right: true}, column{header: "Pinned %", right: true},
+		column{header: "Locked", right: true}, column{header: "Ranged Packages"})
+	for i := 0; i < maxEntries; i++ {
+		entry := entries[i]
+
+		// Files that do not tell their direct dependencies have no share
+		percent := ""
+		if entry.Direct > 0 {
+			percent = p.conventionalShare(entry.PinnedPercent)
+		}
+		locked := ""
+		if entry.Locked > 0 {
+			locked = p.count(entry.Locked)
+		}
+		ranged := entry.RangedPackages
+		if len(ranged) > maxRangedPackages {
+			ranged = append(ranged[:maxRangedPackages:maxRangedPackages], fmt.Sprintf("… %d more", len(entry.RangedPackages)-maxRangedPackages))
+		}
+
+		t.row(
+			cell(p.rankStyle, fmt.Sprintf("%d", entry.Rank)),
+			p.pathCell(p.cellStyle, entry.Path),
+			cell(p.emailStyle, entry.Ecosystem),
+			p.count(entry.Direct),
+			p.count(entry.Pinned),
+			p.count(entry.Ranged),
+			percent,
+			locked,
+			cell(p.emailStyle, strings.Join(ranged, ", ")),
+		)
+	}
+	p.printTable(t)
+}
@@ -0,0 +1,58 @@
+package leaderboard
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/xeon-zolt/codecompass/internal/types"
+	"github.com/xeon-zolt/codecompass/internal/utils"
+)
+
+func TestGenerateDependencyLeaderboard(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"requirements.txt": "django==4.2.11\nrequests>=2.31\nflask\n",
+		"web/package-lock.json": `{"lockfileVersion": 3, "packages": {
+			"": {"dependencies": {"express": "^4.17.1", "lodash": "4.17.21"}, "devDependencies": {"left-pad": "github:stevemao/left-pad"}},
+			"node_modules/express": {"version": "4.19.2"},
+			"node_modules/lodash": {"version": "4.17.21"},
+			"node_modules/left-pad": {"version": "1.3.0"},
+			"node_modules/express/node_modules/qs": {"version": "6.11.0"}
+		}}`,
+		"docs/yarn.lock":        "react@^18.0.0:\n  version \"18.3.1\"\n",
+		"api/package-lock.json": "{",
+	}
+	for file, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	warnings := utils.NewWarningCollector()
+	entries := GenerateDependencyLeaderboard(dir, []string{"api/package-lock.json", "docs/yarn.lock", "requirements.txt", "web/package-lock.json"}, warnings)
+
+	// One of three pinned, computed at run time as the generator does
+	pinned, direct := 1.0, 3.0
+	oneThird := pinned / direct * 100
+	// The yarn.lock has no package.json beside it, so it ranks last
+	expected := []types.DependencyEntry{
+		{Rank: 1, Path: "requirements.txt", Ecosystem: "pypi", Direct: 3, Pinned: 1, Ranged: 2, PinnedPercent: oneThird,
+			RangedPackages: []string{"flask", "requests"}},
+		{Rank: 2, Path: "web/package-lock.json", Ecosystem: "npm", Direct: 3, Locked: 4, Pinned: 1, Ranged: 1, PinnedPercent: oneThird,
+			RangedPackages: []string{"express"}},
+		{Rank: 3, Path: "docs/yarn.lock", Ecosystem: "npm", Locked: 1},
+	}
+	if !reflect.DeepEqual(entries, expected) {
+		t.Errorf("Expected %+v, but got %+v", expected, entries)
+	}
+
+	if got := warnings.Warnings(); len(got) != 1 {
+		t.Errorf("Expected a warning for the invalid lockfile, but got %+v", got)
+	}
+}
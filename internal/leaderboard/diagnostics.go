@@ -0,0 +1,45 @@
+package leaderboard
+
+import (
+	"strings"
+
+	"github.com/xeon-zolt/codecompass/internal/types"
+	"github.com/xeon-zolt/codecompass/internal/utils"
+)
+
+// GenerateDiagnostics turns the warnings of report into diagnostics, grouped
+// as they are listed after the leaderboards, with every file of each group.
+func GenerateDiagnostics(report *types.Report) types.Diagnostics {
+	diagnostics := types.Diagnostics{
+		SchemaVersion:       types.DiagnosticsSchemaVersion,
+		ReportSchemaVersion: report.SchemaVersion,
+		GeneratedAt:         report.GeneratedAt,
+		Repo:                report.Repo.Path,
+		Groups:              []types.DiagnosticGroup{},
+		Warnings:            []types.Diagnostic{},
+	}
+
+	files := make(map[string][]string)
+	seen := make(map[[2]string]bool)
+	for _, warning := range report.Warnings {
+		file := warning.Param("file")
+		diagnostics.Warnings = append(diagnostics.Warnings, types.Diagnostic{
+			Type:    warning.Message,
+			File:    file,
+			Message: strings.TrimPrefix(warning.String(), "⚠️ "),
+			Params:  warning.Params,
+		})
+		if key := [2]string{warning.Template(), file}; file != "" && !seen[key] {
+			seen[key] = true
+			files[key[0]] = append(files[key[0]], file)
+		}
+	}
+	for _, group := range utils.GroupWarnings(report.Warnings) {
+		diagnostics.Groups = append(diagnostics.Groups, types.DiagnosticGroup{
+			Type:  group.Warning.Message,
+			Count: group.Count,
+			Files: files[group.Warning.Template()],
+		})
+	}
+	return diagnostics
+}
@@ -0,0 +1,60 @@
+package leaderboard
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+func TestGenerateDiagnostics(t *testing.T) {
+	report := &types.Report{
+		SchemaVersion: 37,
+		GeneratedAt:   goldenNow,
+		Repo:          types.RepoInfo{Path: "/src/acme"},
+		Warnings: []types.Warning{
+			types.NewWarning("Skipped ESLint: no package.json or ESLint config in the repository root", "phase", "eslint"),
+			types.NewWarning("Blame failed", "phase", "blame", "file", "src/app.js", "error", "exit status 128"),
+			types.NewWarning("Blame failed", "phase", "blame", "file", "src/legacy/parser.js", "error", "exit status 128"),
+			types.NewWarning("Blame failed", "phase", "blame", "file", "src/app.js", "error", "exit status 128"),
+		},
+	}
+
+	data, err := json.MarshalIndent(GenerateDiagnostics(report), "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data = append(data, '\n')
+
+	golden := filepath.Join("testdata", "diagnostics.golden")
+	if *update {
+		if err := os.WriteFile(golden, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("Failed to read %s (run go test -update to create it): %v", golden, err)
+	}
+	if string(data) != string(expected) {
+		t.Errorf("Output does not match %s\ngot:\n%s\nexpected:\n%s", golden, data, expected)
+	}
+}
+
+func TestGenerateDiagnosticsWithoutWarnings(t *testing.T) {
+	data, err := json.Marshal(GenerateDiagnostics(&types.Report{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"groups", "warnings"} {
+		if list, ok := decoded[key].([]any); !ok || len(list) != 0 {
+			t.Errorf("%s = %v, want an empty array", key, decoded[key])
+		}
+	}
+}
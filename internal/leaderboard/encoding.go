@@ -0,0 +1,202 @@
+package leaderboard
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/xeon-zolt/codecompass/internal/types"
+	"github.com/xeon-zolt/codecompass/internal/utils"
+)
+
+// encodingChunkSize is how much of each file is read to classify it.
+const encodingChunkSize = 8 * 1024
+
+// Line endings reported in EncodingEntry.LineEnding.
+const (
+	LineEndingLF    = "LF"
+	LineEndingCRLF  = "CRLF"
+	LineEndingMixed = "mixed"
+	LineEndingNone  = "none"
+)
+
+// Encodings reported in EncodingEntry.Encoding.
+const (
+	EncodingUTF8    = "UTF-8"
+	EncodingUTF16LE = "UTF-16LE"
+	EncodingUTF16BE = "UTF-16BE"
+	EncodingNonUTF8 = "non-UTF-8"
+)
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// GenerateEncodingLeaderboard flags text files with CRLF or mixed line
+// endings, a byte order mark, or bytes that are not valid UTF-8. Only the
+// first chunk of each file is read. Binary files are skipped. A topN above
+// zero keeps only the files with the most problems.
+func GenerateEncodingLeaderboard(ctx context.Context, dir string, trackedFiles map[string]bool, topN int) ([]types.EncodingEntry, error) {
+	var entries []types.EncodingEntry
+
+	for filePath := range trackedFiles {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if shouldSkipFile(filePath) {
+			continue
+		}
+
+		file, err := utils.OpenRegular(filepath.Join(dir, filePath))
+		if err != nil {
+			continue
+		}
+
+		chunk := make([]byte, encodingChunkSize)
+		n, err := io.ReadFull(file, chunk)
+		file.Close()
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			continue
+		}
+
+		entry, ok := classifyEncoding(chunk[:n], n == encodingChunkSize)
+		if !ok || encodingProblems(entry) == 0 {
+			continue
+		}
+		entry.Path = filePath
+		entries = append(entries, entry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		pi, pj := encodingProblems(entries[i]), encodingProblems(entries[j])
+		if pi != pj {
+			return pi > pj
+		}
+		return entries[i].Path < entries[j].Path
+	})
+
+	if topN > 0 && len(entries) > topN {
+		entries = entries[:topN]
+	}
+
+	return entries, nil
+}
+
+// classifyEncoding reports the line endings, BOM and encoding of the start of
+// a file. truncated means the file continues past chunk. It returns false
+// for binary content.
+func classifyEncoding(chunk []byte, truncated bool) (types.EncodingEntry, bool) {
+	var entry types.EncodingEntry
+
+	switch {
+	case bytes.HasPrefix(chunk, bomUTF8):
+		entry.HasBOM = true
+		chunk = chunk[len(bomUTF8):]
+	case bytes.HasPrefix(chunk, bomUTF16LE):
+		// UTF-16 text is full of NUL bytes, so it is classified by its BOM
+		// alone
+		return types.EncodingEntry{HasBOM: true, Encoding: EncodingUTF16LE, LineEnding: LineEndingNone}, true
+	case bytes.HasPrefix(chunk, bomUTF16BE):
+		return types.EncodingEntry{HasBOM: true, Encoding: EncodingUTF16BE, LineEnding: LineEndingNone}, true
+	}
+
+	if bytes.IndexByte(chunk, 0) >= 0 {
+		return entry, false
+	}
+
+	// Don't mistake a rune cut off at the end of the chunk for invalid UTF-8
+	if truncated {
+		if start := lastRuneStart(chunk); start >= 0 && !utf8.FullRune(chunk[start:]) {
+			chunk = chunk[:start]
+		}
+	}
+
+	entry.Encoding = EncodingUTF8
+	if !utf8.Valid(chunk) {
+		entry.Encoding = EncodingNonUTF8
+	}
+
+	crlf := bytes.Count(chunk, []byte("\r\n"))
+	lf := bytes.Count(chunk, []byte("\n")) - crlf
+	switch {
+	case crlf > 0 && lf > 0:
+		entry.LineEnding = LineEndingMixed
+	case crlf > 0:
+		entry.LineEnding = LineEndingCRLF
+	case lf > 0:
+		entry.LineEnding = LineEndingLF
+	default:
+		entry.LineEnding = LineEndingNone
+	}
+
+	return entry, true
+}
+
+// lastRuneStart returns the index of the first byte of the last rune in b,
+// or -1 if there is none in the last utf8.UTFMax bytes.
+func lastRuneStart(b []byte) int {
+	for i := len(b) - 1; i >= 0 && i >= len(b)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(b[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// encodingProblems counts the problems flagged for an entry.
+func encodingProblems(entry types.EncodingEntry) int {
+	problems := 0
+	if entry.LineEnding == LineEndingCRLF || entry.LineEnding == LineEndingMixed {
+		problems++
+	}
+	if entry.HasBOM {
+		problems++
+	}
+	if entry.Encoding != EncodingUTF8 {
+		problems++
+	}
+	return problems
+}
+
+func (p *Printer) PrintEncodingLeaderboard(entries []types.EncodingEntry, topN int) {
+	fmt.Fprintln(p.w, p.titleStyle.Render("Encoding Leaderboard - Line Ending and Encoding Issues"))
+
+	if len(entries) == 0 {
+		fmt.Fprintln(p.w, p.cellStyle.Render("🎉 All text files are UTF-8 with LF line endings"))
+		return
+	}
+
+	maxEntries := p.limit(topN, len(entries))
+
+	t := newTable(rankColumn, fileColumn, column{header: "Line Endings"}, column{header: "BOM"}, column{header: "Encoding"})
+	for i := 0; i < maxEntries; i++ {
+		entry := entries[i]
+
+		// Only the problems of a file are filled in
+		lineEnding := ""
+		switch entry.LineEnding {
+		case LineEndingCRLF:
+			lineEnding = cell(p.warningStyle, "CRLF")
+		case LineEndingMixed:
+			lineEnding = cell(p.errorStyle, "mixed")
+		}
+		bom := ""
+		if entry.HasBOM {
+			bom = cell(p.warningStyle, "BOM")
+		}
+		encoding := ""
+		if entry.Encoding != EncodingUTF8 {
+			encoding = cell(p.errorStyle, entry.Encoding)
+		}
+
+		t.row(cell(p.rankStyle, fmt.Sprintf("%d", i+1)), p.pathCell(p.cellStyle, entry.Path), lineEnding, bom, encoding)
+	}
+	p.printTable(t)
+}
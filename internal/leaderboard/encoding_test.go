@@ -0,0 +1,87 @@
+package leaderboard
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+func TestGenerateEncodingLeaderboard(t *testing.T) {
+	dir := t.TempDir()
+
+	// The multi-byte rune straddles the end of the chunk that is read
+	straddling := strings.Repeat("a", encodingChunkSize-1) + "é\n"
+
+	files := map[string]string{
+		"clean.js":      "const a = 1;\nconst b = 'héllo';\n",
+		"crlf.js":       "const a = 1;\r\nconst b = 2;\r\n",
+		"bom.js":        "\xEF\xBB\xBFconst a = 1;\n",
+		"mixed.js":      "const a = 1;\r\nconst b = 2;\n",
+		"latin1.txt":    "caf\xE9\n",
+		"utf16.txt":     "\xFF\xFEa\x00\n\x00",
+		"binary.dat":    "\x00\x01\x02\r\n",
+		"no-newline.js": "const a = 1;",
+		"long.txt":      straddling,
+	}
+	trackedFiles := make(map[string]bool)
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		trackedFiles[name] = true
+	}
+
+	entries, err := GenerateEncodingLeaderboard(context.Background(), dir, trackedFiles, 0)
+	if err != nil {
+		t.Fatalf("GenerateEncodingLeaderboard failed: %v", err)
+	}
+
+	expected := []types.EncodingEntry{
+		{Path: "utf16.txt", LineEnding: LineEndingNone, HasBOM: true, Encoding: EncodingUTF16LE},
+		{Path: "bom.js", LineEnding: LineEndingLF, HasBOM: true, Encoding: EncodingUTF8},
+		{Path: "crlf.js", LineEnding: LineEndingCRLF, Encoding: EncodingUTF8},
+		{Path: "latin1.txt", LineEnding: LineEndingLF, Encoding: EncodingNonUTF8},
+		{Path: "mixed.js", LineEnding: LineEndingMixed, Encoding: EncodingUTF8},
+	}
+
+	if len(entries) != len(expected) {
+		t.Fatalf("Expected %d entries, but got %+v", len(expected), entries)
+	}
+	for i, entry := range entries {
+		if entry != expected[i] {
+			t.Errorf("Expected entry %d to be %+v, but got %+v", i, expected[i], entry)
+		}
+	}
+
+	top, err := GenerateEncodingLeaderboard(context.Background(), dir, trackedFiles, 2)
+	if err != nil {
+		t.Fatalf("GenerateEncodingLeaderboard failed: %v", err)
+	}
+	if len(top) != 2 || top[0] != expected[0] || top[1] != expected[1] {
+		t.Errorf("Expected topN to keep the first 2 entries, but got %+v", top)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := GenerateEncodingLeaderboard(ctx, dir, trackedFiles, 0); err != context.Canceled {
+		t.Errorf("Expected a cancelled context to stop the scan, but got %v", err)
+	}
+}
+
+func TestClassifyEncodingCleanFile(t *testing.T) {
+	entry, ok := classifyEncoding([]byte("package main\n\nfunc main() {}\n"), false)
+	if !ok {
+		t.Fatal("Expected a text file to be classified")
+	}
+
+	if entry.LineEnding != LineEndingLF || entry.HasBOM || entry.Encoding != EncodingUTF8 {
+		t.Errorf("Expected a clean UTF-8 file with LF endings, but got %+v", entry)
+	}
+	if encodingProblems(entry) != 0 {
+		t.Errorf("Expected no problems for a clean file, but got %d", encodingProblems(entry))
+	}
+}
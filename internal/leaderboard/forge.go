@@ -0,0 +1,71 @@
+package leaderboard
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+func (p *Printer) PrintForgeStats(stats types.ForgeStats, topN int) {
+	since := stats.Since.Format("2006-01-02")
+	fmt.Fprintln(p.w, p.titleStyle.Render(fmt.Sprintf("Pull Request Leaderboard - Merged PRs per Author (%s since %s)", stats.Repo, since)))
+
+	if stats.PullRequests == 0 {
+		fmt.Fprintln(p.w, p.cellStyle.Render(fmt.Sprintf("📭 No pull requests merged since %s", since)))
+		return
+	}
+
+	maxEntries := p.limit(topN, len(stats.Authors))
+
+	t := newTable(rankColumn, column{header: "Author"}, column{header: "Merged", right: true},
+		column{header: "Avg Lines", right: true}, column{header: "Avg Time to Merge", right: true})
+	for i := 0; i < maxEntries; i++ {
+		entry := stats.Authors[i]
+		t.row(
+			cell(p.rankStyle, fmt.Sprintf("%d", i+1)),
+			cell(p.nameStyle, entry.Login),
+			p.count(entry.Merged),
+			fmt.Sprintf("%.0f", entry.AvgSize),
+			formatMergeTime(entry.AvgTimeToMerge),
+		)
+	}
+	p.printTable(t)
+
+	fmt.Fprintln(p.w)
+	fmt.Fprintln(p.w, p.titleStyle.Render("Review Leaderboard - Reviews Given per Reviewer"))
+
+	if len(stats.Reviewers) == 0 {
+		fmt.Fprintln(p.w, p.cellStyle.Render("📭 No reviews found on merged pull requests"))
+		return
+	}
+
+	maxEntries = p.limit(topN, len(stats.Reviewers))
+
+	t = newTable(rankColumn, column{header: "Reviewer"}, column{header: "Reviews", right: true},
+		column{header: "Approvals", right: true}, column{header: "PRs", right: true})
+	for i := 0; i < maxEntries; i++ {
+		entry := stats.Reviewers[i]
+		t.row(
+			cell(p.rankStyle, fmt.Sprintf("%d", i+1)),
+			cell(p.nameStyle, entry.Login),
+			p.count(entry.Reviews),
+			p.count(entry.Approvals),
+			p.count(entry.PullRequests),
+		)
+	}
+	p.printTable(t)
+}
+
+// formatMergeTime formats a time to merge in days and hours, or hours and
+// minutes when it is under a day.
+func formatMergeTime(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd %dh", int(d.Hours())/24, int(d.Hours())%24)
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh %dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+}
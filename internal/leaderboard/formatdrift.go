@@ -0,0 +1,173 @@
+package leaderboard
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/xeon-zolt/codecompass/internal/config"
+	"github.com/xeon-zolt/codecompass/internal/git"
+	"github.com/xeon-zolt/codecompass/internal/ruff"
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+// GenerateFormatDriftLeaderboard runs ruff format and the import sorting
+// rules in diff mode on the Python files of trackedFiles, and ranks the
+// files by the lines they would change and the authors by the lines of
+// theirs that would change, ignored authors left out. A topN above zero
+// keeps only the first of each.
+func GenerateFormatDriftLeaderboard(ctx context.Context, dir string, trackedFiles map[string]bool, cfg *config.Config, blamer *git.Blamer, topN int) (*types.FormatDriftStats, error) {
+	var pythonFiles []string
+	for file := range trackedFiles {
+		if strings.HasSuffix(file, ".py") && !shouldSkipFile(file) {
+			pythonFiles = append(pythonFiles, file)
+		}
+	}
+	if len(pythonFiles) == 0 {
+		return &types.FormatDriftStats{}, nil
+	}
+	sort.Strings(pythonFiles)
+
+	format, err := ruff.RunFormatDiff(ctx, dir, pythonFiles)
+	if err != nil {
+		return nil, err
+	}
+	imports, err := ruff.RunImportDiff(ctx, dir, pythonFiles)
+	if err != nil {
+		return nil, err
+	}
+	return attributeFormatDrift(ctx, format, imports, blamer, cfg, topN)
+}
+
+// attributeFormatDrift combines the format and import drift of each file
+// and blames the lines they would change.
+func attributeFormatDrift(ctx context.Context, format, imports []ruff.FileDrift, blamer *git.Blamer, cfg *config.Config, topN int) (*types.FormatDriftStats, error) {
+	entries := make(map[string]*types.FormatDriftEntry)
+	lines := make(map[string]map[int]int)
+	// add merges the lines of drift into its file and returns how many
+	// there are
+	add := func(drift ruff.FileDrift) (*types.FormatDriftEntry, int) {
+		entry := entries[drift.Path]
+		if entry == nil {
+			entry = &types.FormatDriftEntry{Path: drift.Path}
+			entries[drift.Path] = entry
+			lines[drift.Path] = make(map[int]int)
+		}
+		for line, n := range drift.Lines {
+			lines[drift.Path][line] += n
+		}
+		changed := drift.Changed()
+		entry.Lines += changed
+		return entry, changed
+	}
+	for _, drift := range format {
+		entry, changed := add(drift)
+		entry.FormatLines += changed
+	}
+	for _, drift := range imports {
+		entry, changed := add(drift)
+		entry.ImportLines += changed
+	}
+
+	stats := &types.FormatDriftStats{Files: []types.FormatDriftEntry{}, Authors: []types.FormatDriftAuthorEntry{}}
+	authors := make(map[string]*types.FormatDriftAuthorEntry)
+	for path, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		stats.Files = append(stats.Files, *entry)
+
+		// Files blame fails on are still ranked, without authors
+		blameMap, err := blamer.BlameFile(ctx, path)
+		if err != nil {
+			continue
+		}
+		counted := make(map[string]bool)
+		for line, n := range lines[path] {
+			info, found := blameMap[line]
+			if !found || info.Email == "" || cfg.ShouldIgnoreAuthor(info.Email, info.Name) {
+				continue
+			}
+			author := authors[info.Email]
+			if author == nil {
+				author = &types.FormatDriftAuthorEntry{Name: info.Name, Email: info.Email}
+				authors[info.Email] = author
+			}
+			author.Lines += n
+			if !counted[info.Email] {
+				counted[info.Email] = true
+				author.Files++
+			}
+		}
+	}
+	for _, author := range authors {
+		stats.Authors = append(stats.Authors, *author)
+	}
+
+	sort.Slice(stats.Files, func(i, j int) bool {
+		if stats.Files[i].Lines != stats.Files[j].Lines {
+			return stats.Files[i].Lines > stats.Files[j].Lines
+		}
+		return stats.Files[i].Path < stats.Files[j].Path
+	})
+	sort.Slice(stats.Authors, func(i, j int) bool {
+		if stats.Authors[i].Lines != stats.Authors[j].Lines {
+			return stats.Authors[i].Lines > stats.Authors[j].Lines
+		}
+		return stats.Authors[i].Email < stats.Authors[j].Email
+	})
+	if topN > 0 {
+		stats.Files = stats.Files[:min(topN, len(stats.Files))]
+		stats.Authors = stats.Authors[:min(topN, len(stats.Authors))]
+	}
+	for i := range stats.Files {
+		stats.Files[i].Rank = i + 1
+	}
+	for i := range stats.Authors {
+		stats.Authors[i].Rank = i + 1
+	}
+	return stats, nil
+}
+
+// PrintFormatDriftLeaderboard prints the topN files ruff would rewrite the
+// most of, and the topN authors of the lines it would change.
+func (p *Printer) PrintFormatDriftLeaderboard(stats types.FormatDriftStats, topN int) {
+	fmt.Fprintln(p.w, p.titleStyle.Render("Format Drift Leaderboard - Lines ruff format and Import Sorting Would Change"))
+
+	if len(stats.Files) == 0 {
+		fmt.Fprintln(p.w, p.cellStyle.Render("🎉 Every Python file is formatted and its imports sorted"))
+		return
+	}
+
+	t := newTable(rankColumn, fileColumn, column{header: "Lines", right: true},
+		column{header: "Format", right: true}, column{header: "Imports", right: true})
+	for i, entry := range stats.Files[:min(topN, len(stats.Files))] {
+		t.row(
+			cell(p.rankStyle, fmt.Sprintf("%d", i+1)),
+			p.pathCell(p.cellStyle, entry.Path),
+			p.count(entry.Lines),
+			cell(p.warningStyle, p.formatCount(entry.FormatLines)),
+			cell(p.warningStyle, p.formatCount(entry.ImportLines)),
+		)
+	}
+	p.printTable(t)
+
+	if len(stats.Authors) == 0 {
+		return
+	}
+	fmt.Fprintln(p.w)
+	fmt.Fprintln(p.w, p.titleStyle.Render("Format Drift Authors - Who Last Wrote the Lines That Would Change"))
+	t = newTable(rankColumn, column{header: "Author"}, column{header: "Email"},
+		column{header: "Lines", right: true}, column{header: "Files", right: true})
+	for i, author := range stats.Authors[:min(topN, len(stats.Authors))] {
+		t.row(
+			cell(p.rankStyle, fmt.Sprintf("%d", i+1)),
+			cell(p.nameStyle, author.Name),
+			cell(p.emailStyle, author.Email),
+			p.count(author.Lines),
+			p.formatCount(author.Files),
+		)
+	}
+	p.printTable(t)
+}
@@ -0,0 +1,72 @@
+package leaderboard
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/xeon-zolt/codecompass/internal/config"
+	"github.com/xeon-zolt/codecompass/internal/git"
+	"github.com/xeon-zolt/codecompass/internal/logging"
+	"github.com/xeon-zolt/codecompass/internal/ruff"
+	"github.com/xeon-zolt/codecompass/internal/testutil"
+	"github.com/xeon-zolt/codecompass/internal/types"
+	"github.com/xeon-zolt/codecompass/internal/utils"
+)
+
+func TestAttributeFormatDrift(t *testing.T) {
+	dir := testutil.NewRepo(t).
+		WithAuthor("Alice", "alice@example.com").
+		Commit("add app", map[string]string{
+			"app.py":  "import sys, os\nx=1\ny = 2\n",
+			"util.py": "z=3\n",
+		}).
+		WithAuthor("Bob", "bob@example.com").
+		Commit("edit app", map[string]string{"app.py": "import sys, os\nx=1\ny=2\n"}).
+		WithAuthor("Bot", "bot@example.com").
+		Commit("add generated", map[string]string{"gen.py": "a=1\n"}).
+		Dir()
+
+	format := []ruff.FileDrift{
+		{Path: "app.py", Lines: map[int]int{2: 1, 3: 3}},
+		{Path: "gen.py", Lines: map[int]int{1: 1}},
+		{Path: "util.py", Lines: map[int]int{1: 1}},
+	}
+	imports := []ruff.FileDrift{{Path: "app.py", Lines: map[int]int{1: 2}}}
+
+	cfg := config.NewConfig()
+	cfg.IgnoredAuthors = []string{"bot@example.com"}
+	blamer := git.NewBlamer(dir, utils.NewSemaphore(1), logging.Discard(), utils.NewWarningCollector())
+	stats, err := attributeFormatDrift(context.Background(), format, imports, blamer, cfg, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedFiles := []types.FormatDriftEntry{
+		{Rank: 1, Path: "app.py", Lines: 6, FormatLines: 4, ImportLines: 2},
+		{Rank: 2, Path: "gen.py", Lines: 1, FormatLines: 1},
+		{Rank: 3, Path: "util.py", Lines: 1, FormatLines: 1},
+	}
+	if !reflect.DeepEqual(stats.Files, expectedFiles) {
+		t.Errorf("Expected files %+v, but got %+v", expectedFiles, stats.Files)
+	}
+
+	// The ignored author's file is ranked but not attributed
+	expectedAuthors := []types.FormatDriftAuthorEntry{
+		{Rank: 1, Name: "Alice", Email: "alice@example.com", Lines: 4, Files: 2},
+		{Rank: 2, Name: "Bob", Email: "bob@example.com", Lines: 3, Files: 1},
+	}
+	if !reflect.DeepEqual(stats.Authors, expectedAuthors) {
+		t.Errorf("Expected authors %+v, but got %+v", expectedAuthors, stats.Authors)
+	}
+}
+
+func TestGenerateFormatDriftLeaderboardWithoutPython(t *testing.T) {
+	// Ruff is not run, so it does not have to be installed
+	t.Setenv("PATH", t.TempDir())
+
+	stats, err := GenerateFormatDriftLeaderboard(context.Background(), t.TempDir(), map[string]bool{"main.go": true}, config.NewConfig(), nil, 0)
+	if err != nil || len(stats.Files) != 0 {
+		t.Errorf("Expected no drift without Python files, but got %+v, %v", stats, err)
+	}
+}
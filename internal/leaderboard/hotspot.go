@@ -0,0 +1,79 @@
+package leaderboard
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+// GenerateHotspotLeaderboard joins the churn leaderboard with the lint
+// issues of each file by path and ranks the files by their changes times
+// their issues, the files to refactor first. Files without changes or
+// without issues are no hotspots and are left out. Ties are ranked by
+// issues, then path.
+func GenerateHotspotLeaderboard(churnEntries []types.ChurnEntry, fileStats map[string]*types.FileStats, topN int) []types.HotspotEntry {
+	issues := make(map[string]int, len(fileStats))
+	for file, stats := range fileStats {
+		issues[filepath.ToSlash(file)] += stats.Count + stats.Overflow
+	}
+
+	var entries []types.HotspotEntry
+	for _, churn := range churnEntries {
+		count := issues[churn.Path]
+		if churn.Changes == 0 || count == 0 {
+			continue
+		}
+		entries = append(entries, types.HotspotEntry{
+			Path:         churn.Path,
+			Changes:      churn.Changes,
+			Issues:       count,
+			HotspotScore: churn.Changes * count,
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.HotspotScore != b.HotspotScore {
+			return a.HotspotScore > b.HotspotScore
+		}
+		if a.Issues != b.Issues {
+			return a.Issues > b.Issues
+		}
+		return a.Path < b.Path
+	})
+
+	if topN > 0 && len(entries) > topN {
+		entries = entries[:topN]
+	}
+	for i := range entries {
+		entries[i].Rank = i + 1
+	}
+	return entries
+}
+
+func (p *Printer) PrintHotspotLeaderboard(entries []types.HotspotEntry, topN int) {
+	fmt.Fprintln(p.w, p.titleStyle.Render("Hotspot Leaderboard - Frequently Changed Files with the Most Issues"))
+
+	if len(entries) == 0 {
+		fmt.Fprintln(p.w, p.cellStyle.Render("📭 No changed files with lint issues found"))
+		return
+	}
+
+	maxEntries := p.limit(topN, len(entries))
+
+	t := newTable(rankColumn, fileColumn, column{header: "Score", right: true},
+		column{header: "Changes", right: true}, column{header: "Issues", right: true})
+	for i := 0; i < maxEntries; i++ {
+		entry := entries[i]
+		t.row(
+			cell(p.rankStyle, fmt.Sprintf("%d", entry.Rank)),
+			p.pathCell(p.cellStyle, entry.Path),
+			p.count(entry.HotspotScore),
+			p.count(entry.Changes),
+			p.count(entry.Issues),
+		)
+	}
+	p.printTable(t)
+}
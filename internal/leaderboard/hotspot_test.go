@@ -0,0 +1,41 @@
+package leaderboard
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+func TestGenerateHotspotLeaderboard(t *testing.T) {
+	churn := []types.ChurnEntry{
+		{Path: "src/churned.js", Changes: 40},
+		{Path: "src/hotspot.js", Changes: 12},
+		{Path: "src/buggy.js", Changes: 1},
+		{Path: "src/clean.js", Changes: 25},
+	}
+	fileStats := map[string]*types.FileStats{
+		"src/churned.js": {Path: "src/churned.js", Count: 1},
+		"src/hotspot.js": {Path: "src/hotspot.js", Count: 6, Overflow: 2},
+		"src/buggy.js":   {Path: "src/buggy.js", Count: 30},
+		// Never changed in history, such as an untracked file
+		"src/new.js": {Path: "src/new.js", Count: 50},
+	}
+
+	entries := GenerateHotspotLeaderboard(churn, fileStats, 0)
+
+	// The file both changed often and with many issues outranks those that
+	// are only one or the other
+	expected := []types.HotspotEntry{
+		{Rank: 1, Path: "src/hotspot.js", Changes: 12, Issues: 8, HotspotScore: 96},
+		{Rank: 2, Path: "src/churned.js", Changes: 40, Issues: 1, HotspotScore: 40},
+		{Rank: 3, Path: "src/buggy.js", Changes: 1, Issues: 30, HotspotScore: 30},
+	}
+	if !reflect.DeepEqual(entries, expected) {
+		t.Errorf("Expected %+v, but got %+v", expected, entries)
+	}
+
+	if top := GenerateHotspotLeaderboard(churn, fileStats, 1); len(top) != 1 || top[0].Path != "src/hotspot.js" {
+		t.Errorf("Expected only src/hotspot.js with topN 1, but got %+v", top)
+	}
+}
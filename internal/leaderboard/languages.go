@@ -0,0 +1,180 @@
+package leaderboard
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/xeon-zolt/codecompass/internal/lang"
+	"github.com/xeon-zolt/codecompass/internal/types"
+	"github.com/xeon-zolt/codecompass/internal/utils"
+)
+
+// otherLanguage is the name files of no known language are counted under.
+const otherLanguage = "Other"
+
+// GenerateLanguageComposition counts the files and lines of each language
+// among files, as told by lang.Detect, the most lines first. Text files of
+// no known language are counted as Other, always last; binary files, with a
+// NUL byte near their start, are left out.
+func GenerateLanguageComposition(ctx context.Context, dir string, files map[string]bool) ([]types.LanguageEntry, error) {
+	counts := make(map[string]*types.LanguageEntry)
+	total := 0
+	for file := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		lines, ok := countTextLines(filepath.Join(dir, file))
+		if !ok {
+			continue
+		}
+		name := lang.Detect(dir, file)
+		if name == "" {
+			name = otherLanguage
+		}
+		entry := counts[name]
+		if entry == nil {
+			entry = &types.LanguageEntry{Name: name}
+			counts[name] = entry
+		}
+		entry.Files++
+		entry.Lines += lines
+		total += lines
+	}
+
+	entries := make([]types.LanguageEntry, 0, len(counts))
+	for _, entry := range counts {
+		if total > 0 {
+			entry.Percent = float64(entry.Lines) * 100 / float64(total)
+		}
+		entries = append(entries, *entry)
+	}
+	sortLanguages(entries)
+	return entries, nil
+}
+
+// MergeLanguageCompositions adds up the compositions of several
+// repositories.
+func MergeLanguageCompositions(compositions ...[]types.LanguageEntry) []types.LanguageEntry {
+	counts := make(map[string]*types.LanguageEntry)
+	total := 0
+	for _, composition := range compositions {
+		for _, entry := range composition {
+			merged := counts[entry.Name]
+			if merged == nil {
+				merged = &types.LanguageEntry{Name: entry.Name}
+				counts[entry.Name] = merged
+			}
+			merged.Files += entry.Files
+			merged.Lines += entry.Lines
+			total += entry.Lines
+		}
+	}
+
+	var entries []types.LanguageEntry
+	for _, entry := range counts {
+		if total > 0 {
+			entry.Percent = float64(entry.Lines) * 100 / float64(total)
+		}
+		entries = append(entries, *entry)
+	}
+	sortLanguages(entries)
+	return entries
+}
+
+// sortLanguages orders entries by lines, then by name, with Other last.
+func sortLanguages(entries []types.LanguageEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if (entries[i].Name == otherLanguage) != (entries[j].Name == otherLanguage) {
+			return entries[j].Name == otherLanguage
+		}
+		if entries[i].Lines != entries[j].Lines {
+			return entries[i].Lines > entries[j].Lines
+		}
+		return entries[i].Name < entries[j].Name
+	})
+}
+
+// countTextLines counts the lines of the file at path, a last line without
+// a newline included. It returns false for files that cannot be read or
+// look binary.
+func countTextLines(path string) (int, bool) {
+	f, err := utils.OpenRegular(path)
+	if err != nil {
+		return 0, false
+	}
+	defer f.Close()
+
+	buf := make([]byte, 32*1024)
+	lines, first := 0, true
+	var last byte = '\n'
+	for {
+		n, err := f.Read(buf)
+		if n > 0 {
+			if first && bytes.IndexByte(buf[:n], 0) >= 0 {
+				return 0, false
+			}
+			first = false
+			lines += bytes.Count(buf[:n], []byte{'\n'})
+			last = buf[n-1]
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, false
+		}
+	}
+	if last != '\n' {
+		lines++
+	}
+	return lines, true
+}
+
+// composedLanguages is how many languages FormatLanguageComposition names
+// before folding the rest into other.
+const composedLanguages = 5
+
+// FormatLanguageComposition describes entries in one line, such as
+// "TypeScript 61%, Python 28%, other 11%". Languages past the first few,
+// or under half a percent, are counted as other.
+func FormatLanguageComposition(entries []types.LanguageEntry) string {
+	var parts []string
+	other := 0.0
+	for _, entry := range entries {
+		if entry.Name == otherLanguage || len(parts) == composedLanguages || entry.Percent < 0.5 {
+			other += entry.Percent
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s %.0f%%", entry.Name, entry.Percent))
+	}
+	if other >= 0.5 {
+		parts = append(parts, fmt.Sprintf("other %.0f%%", other))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// PrintLanguageComposition prints the make-up of the repository by
+// language, or nothing without files to count.
+func (p *Printer) PrintLanguageComposition(entries []types.LanguageEntry) {
+	if len(entries) == 0 {
+		return
+	}
+	fmt.Fprintf(p.w, "  • Languages: %s\n", p.cellStyle.Render(FormatLanguageComposition(entries)))
+
+	t := newTable(column{header: "Language"}, column{header: "Files", right: true},
+		column{header: "Lines", right: true}, column{header: "Share", right: true})
+	for _, entry := range entries {
+		t.row(
+			cell(p.nameStyle, entry.Name),
+			p.count(entry.Files),
+			p.count(entry.Lines),
+			cell(p.cellStyle, fmt.Sprintf("%.1f%%", entry.Percent)),
+		)
+	}
+	p.printTable(t)
+}
@@ -1,32 +1,43 @@
 package leaderboard
 
 import (
-	"bufio"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
 
-	"codecompass/internal/config"
-	"codecompass/internal/coverage"
-	"codecompass/internal/git"
-	"codecompass/internal/spellcheck"
-	"codecompass/internal/types"
+	"github.com/xeon-zolt/codecompass/internal/config"
+	"github.com/xeon-zolt/codecompass/internal/coverage"
+	"github.com/xeon-zolt/codecompass/internal/eslint"
+	"github.com/xeon-zolt/codecompass/internal/fingerprint"
+	"github.com/xeon-zolt/codecompass/internal/git"
+	"github.com/xeon-zolt/codecompass/internal/spellcheck"
+	"github.com/xeon-zolt/codecompass/internal/suppression"
+	"github.com/xeon-zolt/codecompass/internal/types"
+	"github.com/xeon-zolt/codecompass/internal/utils"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 )
 
-func GenerateAuthorLeaderboard(authorStats map[string]*types.AuthorStats, topN int) []types.LeaderboardEntry {
+// GenerateAuthorLeaderboard ranks authors by their issues, weighted by
+// weights when they are set.
+func GenerateAuthorLeaderboard(authorStats map[string]*types.AuthorStats, topN int, weights *config.IssueWeights) []types.LeaderboardEntry {
 	var entries []types.LeaderboardEntry
 	for email, stats := range authorStats {
 		var topRule string
 		var topCount int
 		for rule, count := range stats.Rules {
-			if count > topCount {
+			if count > topCount || (count == topCount && rule < topRule) {
 				topRule = rule
 				topCount = count
 			}
@@ -36,31 +47,93 @@ func GenerateAuthorLeaderboard(authorStats map[string]*types.AuthorStats, topN i
 		}
 
 		entries = append(entries, types.LeaderboardEntry{
-			Name:     stats.Name,
-			Email:    email,
-			Count:    stats.Count,
-			TopRule:  topRule,
-			TopCount: topCount,
-			Files:    len(stats.Files),
-			Errors:   stats.Errors,
-			Warnings: stats.Warnings,
+			Name:          stats.Name,
+			Email:         email,
+			Count:         stats.Count,
+			DecayedScore:  stats.Decayed,
+			WeightedScore: weightedScore(stats.Rules, stats.RuleErrors, weights),
+			TopRule:       topRule,
+			TopCount:      topCount,
+			Files:         len(stats.Files),
+			Errors:        stats.Errors,
+			Warnings:      stats.Warnings,
 		})
 	}
 
-	sort.Slice(entries, func(i, j int) bool {
-		return entries[i].Count > entries[j].Count
-	})
+	sort.SliceStable(entries, func(i, j int) bool { return authorLess(entries[i], entries[j]) })
 
 	return entries
 }
 
-func GenerateFileLeaderboard(fileStats map[string]*types.FileStats, topN int) []types.FileLeaderboardEntry {
+// weightedScore returns the issues counted in rules, of which ruleErrors are
+// errors, weighted by weights, or zero without weights. Rules are added up
+// in order so the score does not depend on map order.
+func weightedScore(rules, ruleErrors map[string]int, weights *config.IssueWeights) float64 {
+	if weights == nil {
+		return 0
+	}
+	names := make([]string, 0, len(rules))
+	for rule := range rules {
+		names = append(names, rule)
+	}
+	sort.Strings(names)
+
+	var score float64
+	for _, rule := range names {
+		errors := ruleErrors[rule]
+		score += float64(errors)*weights.Weight(rule, true) + float64(rules[rule]-errors)*weights.Weight(rule, false)
+	}
+	return score
+}
+
+// authorLess orders authors by weighted score when issues are weighted by
+// severity, by decayed score when they are weighted by age, then by issues,
+// name and email.
+func authorLess(a, b types.LeaderboardEntry) bool {
+	if a.WeightedScore != b.WeightedScore {
+		return a.WeightedScore > b.WeightedScore
+	}
+	if a.DecayedScore != b.DecayedScore {
+		return a.DecayedScore > b.DecayedScore
+	}
+	if a.Count != b.Count {
+		return a.Count > b.Count
+	}
+	if a.Name != b.Name {
+		return a.Name < b.Name
+	}
+	return a.Email < b.Email
+}
+
+// FileSort is the column the file leaderboard is sorted by.
+type FileSort string
+
+const (
+	FileSortIssues   FileSort = "issues"
+	FileSortErrors   FileSort = "errors"
+	FileSortWarnings FileSort = "warnings"
+)
+
+// ParseFileSort parses the column of a --sort files=COLUMN option: issues,
+// errors or warnings.
+func ParseFileSort(value string) (FileSort, error) {
+	switch sortBy := FileSort(value); sortBy {
+	case FileSortIssues, FileSortErrors, FileSortWarnings:
+		return sortBy, nil
+	}
+	return "", fmt.Errorf("invalid file sort %q: expected issues, errors or warnings", value)
+}
+
+// GenerateFileLeaderboard ranks files by sortBy, then by issues and path.
+// An empty sortBy sorts by issues, ranking by weighted score first when
+// weights are set, and by decayed score when issues are weighted by age.
+func GenerateFileLeaderboard(fileStats map[string]*types.FileStats, topN int, sortBy FileSort, weights *config.IssueWeights) []types.FileLeaderboardEntry {
 	var entries []types.FileLeaderboardEntry
 	for _, stats := range fileStats {
 		var topRule string
 		var topCount int
 		for rule, count := range stats.Rules {
-			if count > topCount {
+			if count > topCount || (count == topCount && rule < topRule) {
 				topRule = rule
 				topCount = count
 			}
@@ -70,21 +143,72 @@ func GenerateFileLeaderboard(fileStats map[string]*types.FileStats, topN int) []
 		}
 
 		entries = append(entries, types.FileLeaderboardEntry{
-			Path:     stats.Path,
-			Count:    stats.Count,
-			TopRule:  topRule,
-			TopCount: topCount,
-			Authors:  len(stats.Authors),
+			Path:          stats.Path,
+			Count:         stats.Count,
+			DecayedScore:  stats.Decayed,
+			WeightedScore: weightedScore(stats.Rules, stats.RuleErrors, weights),
+			Errors:        stats.Errors,
+			Warnings:      stats.Warnings,
+			TopRule:       topRule,
+			TopCount:      topCount,
+			Authors:       len(stats.Authors),
+			TopAuthors:    topAuthors(stats.Authors, topAuthorsPerFile),
+			Overflow:      stats.Overflow,
 		})
 	}
 
-	sort.Slice(entries, func(i, j int) bool {
-		return entries[i].Count > entries[j].Count
+	key := func(entry types.FileLeaderboardEntry) int {
+		switch sortBy {
+		case FileSortErrors:
+			return entry.Errors
+		case FileSortWarnings:
+			return entry.Warnings
+		}
+		return entry.Count
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		byIssues := sortBy == "" || sortBy == FileSortIssues
+		if byIssues && entries[i].WeightedScore != entries[j].WeightedScore {
+			return entries[i].WeightedScore > entries[j].WeightedScore
+		}
+		if byIssues && entries[i].DecayedScore != entries[j].DecayedScore {
+			return entries[i].DecayedScore > entries[j].DecayedScore
+		}
+		if key(entries[i]) != key(entries[j]) {
+			return key(entries[i]) > key(entries[j])
+		}
+		if entries[i].Count != entries[j].Count {
+			return entries[i].Count > entries[j].Count
+		}
+		return entries[i].Path < entries[j].Path
 	})
 
 	return entries
 }
 
+// topAuthorsPerFile is how many authors the file leaderboard keeps per file.
+const topAuthorsPerFile = 3
+
+// topAuthors returns the n authors with the most issues, ties broken by email.
+func topAuthors(authors map[string]int, n int) []types.AuthorCount {
+	var counts []types.AuthorCount
+	for email, count := range authors {
+		counts = append(counts, types.AuthorCount{Email: email, Count: count})
+	}
+
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Email < counts[j].Email
+	})
+
+	if len(counts) > n {
+		counts = counts[:n]
+	}
+	return counts
+}
+
 func GenerateRuleLeaderboard(ruleStats map[string]*types.RuleStats, topN int) []types.RuleLeaderboardEntry {
 	var entries []types.RuleLeaderboardEntry
 	for _, stats := range ruleStats {
@@ -93,31 +217,200 @@ func GenerateRuleLeaderboard(ruleStats map[string]*types.RuleStats, topN int) []
 			Count:   stats.Count,
 			Authors: len(stats.Authors),
 			Files:   len(stats.Files),
+			Fixable: stats.FixableCount,
 		})
 	}
 
-	sort.Slice(entries, func(i, j int) bool {
-		return entries[i].Count > entries[j].Count
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Count != entries[j].Count {
+			return entries[i].Count > entries[j].Count
+		}
+		return entries[i].Rule < entries[j].Rule
 	})
 
 	return entries
 }
 
-func GenerateLinesOfCodeLeaderboard(trackedFiles map[string]bool, topN int) []types.LinesOfCodeEntry {
+// CorePlugin is the plugin rules without a namespace are grouped under.
+const CorePlugin = "core"
+
+// RulePlugin returns the plugin namespace of an ESLint rule: everything
+// before the last slash, so @typescript-eslint/no-unused-vars belongs to
+// @typescript-eslint and @next/next/no-img-element to @next/next.
+func RulePlugin(rule string) string {
+	if i := strings.LastIndex(rule, "/"); i > 0 {
+		return rule[:i]
+	}
+	return CorePlugin
+}
+
+// GenerateRulePluginLeaderboard rolls the rule statistics up by plugin.
+func GenerateRulePluginLeaderboard(ruleStats map[string]*types.RuleStats) []types.RulePluginEntry {
+	plugins := make(map[string]*types.RulePluginEntry)
+	for _, stats := range ruleStats {
+		plugin := RulePlugin(stats.Rule)
+		entry := plugins[plugin]
+		if entry == nil {
+			entry = &types.RulePluginEntry{Plugin: plugin}
+			plugins[plugin] = entry
+		}
+		entry.Count += stats.Count
+		entry.DistinctRules++
+	}
+
+	entries := make([]types.RulePluginEntry, 0, len(plugins))
+	for _, entry := range plugins {
+		entries = append(entries, *entry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Count != entries[j].Count {
+			return entries[i].Count > entries[j].Count
+		}
+		return entries[i].Plugin < entries[j].Plugin
+	})
+	for i := range entries {
+		entries[i].Rank = i + 1
+	}
+
+	return entries
+}
+
+// UngroupedRules is the group of the rules in no rule group of the config.
+const UngroupedRules = "ungrouped"
+
+// GenerateRuleGroupLeaderboard rolls the rule statistics up by the rule
+// groups of cfg.
+func GenerateRuleGroupLeaderboard(ruleStats map[string]*types.RuleStats, cfg *config.Config) []types.RuleGroupEntry {
+	groups := make(map[string]*types.RuleGroupEntry)
+	for _, stats := range ruleStats {
+		group := cfg.RuleGroup(stats.Rule)
+		if group == "" {
+			group = UngroupedRules
+		}
+		entry := groups[group]
+		if entry == nil {
+			entry = &types.RuleGroupEntry{Group: group}
+			groups[group] = entry
+		}
+		entry.Count += stats.Count
+		entry.DistinctRules++
+	}
+
+	entries := make([]types.RuleGroupEntry, 0, len(groups))
+	for _, entry := range groups {
+		entries = append(entries, *entry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Count != entries[j].Count {
+			return entries[i].Count > entries[j].Count
+		}
+		return entries[i].Group < entries[j].Group
+	})
+	for i := range entries {
+		entries[i].Rank = i + 1
+	}
+
+	return entries
+}
+
+// GenerateRuleAuthorMatrix cross-tabulates the violations of each rule by
+// author, keeping only the cells with violations. Cells are sorted by count,
+// then by rule and email.
+func GenerateRuleAuthorMatrix(authorStats map[string]*types.AuthorStats) []types.RuleAuthorCell {
+	var cells []types.RuleAuthorCell
+	for email, stats := range authorStats {
+		for rule, count := range stats.Rules {
+			if count > 0 {
+				cells = append(cells, types.RuleAuthorCell{Rule: rule, Name: stats.Name, Email: email, Count: count})
+			}
+		}
+	}
+
+	sort.Slice(cells, func(i, j int) bool {
+		if cells[i].Count != cells[j].Count {
+			return cells[i].Count > cells[j].Count
+		}
+		if cells[i].Rule != cells[j].Rule {
+			return cells[i].Rule < cells[j].Rule
+		}
+		return cells[i].Email < cells[j].Email
+	})
+	return cells
+}
+
+// matrixTop is the largest cell of a row or column of the rule by author
+// cross-tab, with the total of that row or column.
+type matrixTop struct {
+	types.RuleAuthorCell
+	Total int
+}
+
+// topCells returns the largest cell for each key of cells, such as each
+// rule, ordered by the total of the key. Cells must be sorted as
+// GenerateRuleAuthorMatrix sorts them, so ties go to the first.
+func topCells(cells []types.RuleAuthorCell, key func(types.RuleAuthorCell) string) []matrixTop {
+	tops := make(map[string]*matrixTop)
+	var order []string
+	for _, c := range cells {
+		k := key(c)
+		if tops[k] == nil {
+			tops[k] = &matrixTop{RuleAuthorCell: c}
+			order = append(order, k)
+		}
+		tops[k].Total += c.Count
+	}
+
+	entries := make([]matrixTop, 0, len(order))
+	for _, k := range order {
+		entries = append(entries, *tops[k])
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Total != entries[j].Total {
+			return entries[i].Total > entries[j].Total
+		}
+		return key(entries[i].RuleAuthorCell) < key(entries[j].RuleAuthorCell)
+	})
+	return entries
+}
+
+// topAuthorPerRule returns the author with the most violations of each rule
+// in cells, the rules with the most violations first.
+func topAuthorPerRule(cells []types.RuleAuthorCell) []matrixTop {
+	return topCells(cells, func(c types.RuleAuthorCell) string { return c.Rule })
+}
+
+// topRulePerAuthor returns the rule each author in cells violates most, the
+// authors with the most violations first.
+func topRulePerAuthor(cells []types.RuleAuthorCell) []matrixTop {
+	return topCells(cells, func(c types.RuleAuthorCell) string { return c.Email })
+}
+
+func GenerateLinesOfCodeLeaderboard(ctx context.Context, dir string, trackedFiles map[string]bool, topN int, progress utils.ProgressFunc) []types.LinesOfCodeEntry {
 	var entries []types.LinesOfCodeEntry
 
+	done := 0
 	for filePath := range trackedFiles {
+		progress.Report(done, len(trackedFiles))
+		done++
+
 		// Skip binary files and common non-code files
 		if shouldSkipFile(filePath) {
 			continue
 		}
 
-		lineCount, err := git.GetFileLineCount(filePath)
+		// Never follow symlinks; an in-repo target is counted on its own
+		if utils.IsSymlink(filepath.Join(dir, filePath)) {
+			continue
+		}
+
+		lineCount, err := git.GetFileLineCount(ctx, filepath.Join(dir, filePath))
 		if err != nil {
 			continue
 		}
 
-		fileInfo, err := os.Stat(filePath)
+		fileInfo, err := os.Lstat(filepath.Join(dir, filePath))
 		if err != nil {
 			continue
 		}
@@ -128,16 +421,23 @@ func GenerateLinesOfCodeLeaderboard(trackedFiles map[string]bool, topN int) []ty
 			Size:  fileInfo.Size(),
 		})
 	}
+	progress.Report(done, len(trackedFiles))
 
-	sort.Slice(entries, func(i, j int) bool {
-		return entries[i].Lines > entries[j].Lines
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Lines != entries[j].Lines {
+			return entries[i].Lines > entries[j].Lines
+		}
+		return entries[i].Path < entries[j].Path
 	})
 
 	return entries
 }
 
-func GenerateCommitCountLeaderboard(topN int) ([]types.CommitCountEntry, error) {
-	authorCommits, err := git.GetAuthorCommitCounts()
+// GenerateCommitCountLeaderboard ranks authors by the commits they are
+// credited with, co-authored ones counted as credit says, on all branches or
+// in revs when given.
+func GenerateCommitCountLeaderboard(ctx context.Context, dir string, dateType git.DateType, credit git.CoAuthorCredit, topN int, revs ...string) ([]types.CommitCountEntry, error) {
+	authorCommits, err := git.GetAuthorCommitCounts(ctx, dir, dateType, credit, revs...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get commit data: %w", err)
 	}
@@ -147,15 +447,24 @@ func GenerateCommitCountLeaderboard(topN int) ([]types.CommitCountEntry, error)
 		entries = append(entries, stats)
 	}
 
-	sort.Slice(entries, func(i, j int) bool {
-		return entries[i].Commits > entries[j].Commits
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Credit != entries[j].Credit {
+			return entries[i].Credit > entries[j].Credit
+		}
+		if entries[i].Commits != entries[j].Commits {
+			return entries[i].Commits > entries[j].Commits
+		}
+		if entries[i].Name != entries[j].Name {
+			return entries[i].Name < entries[j].Name
+		}
+		return entries[i].Email < entries[j].Email
 	})
 
 	return entries, nil
 }
 
-func GenerateRecentContributorsLeaderboard(topN int) ([]types.RecentContributorEntry, error) {
-	recentContributors, err := git.GetRecentContributors(30)
+func GenerateRecentContributorsLeaderboard(ctx context.Context, dir string, dateType git.DateType, topN int) ([]types.RecentContributorEntry, error) {
+	recentContributors, err := git.GetRecentContributors(ctx, dir, 30, dateType)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get recent contributor data: %w", err)
 	}
@@ -165,54 +474,132 @@ func GenerateRecentContributorsLeaderboard(topN int) ([]types.RecentContributorE
 		entries = append(entries, stats)
 	}
 
-	sort.Slice(entries, func(i, j int) bool {
-		return entries[i].RecentCommits > entries[j].RecentCommits
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].RecentCommits != entries[j].RecentCommits {
+			return entries[i].RecentCommits > entries[j].RecentCommits
+		}
+		if entries[i].Name != entries[j].Name {
+			return entries[i].Name < entries[j].Name
+		}
+		return entries[i].Email < entries[j].Email
 	})
 
 	return entries, nil
 }
 
-func GenerateSummaryStats(authorStats map[string]*types.AuthorStats, fileStats map[string]*types.FileStats, ruleStats map[string]*types.RuleStats) {
-	fmt.Println(titleStyle.Render("Repository Summary"))
-
-	totalIssues := 0
-	totalErrors := 0
-	totalWarnings := 0
+// GenerateSummaryStats totals the issues of a run, and weights them by
+// weights when they are set.
+func GenerateSummaryStats(authorStats map[string]*types.AuthorStats, fileStats map[string]*types.FileStats, ruleStats map[string]*types.RuleStats, weights *config.IssueWeights) types.SummaryStats {
+	summary := types.SummaryStats{
+		Authors: len(authorStats),
+		Files:   len(fileStats),
+		Rules:   len(ruleStats),
+	}
 
-	for _, stats := range authorStats {
-		totalIssues += stats.Count
-		totalErrors += stats.Errors
-		totalWarnings += stats.Warnings
+	emails := make([]string, 0, len(authorStats))
+	for email := range authorStats {
+		emails = append(emails, email)
+	}
+	sort.Strings(emails)
+	for _, email := range emails {
+		stats := authorStats[email]
+		summary.TotalIssues += stats.Count
+		summary.Errors += stats.Errors
+		summary.Warnings += stats.Warnings
+		summary.WeightedIssues += weightedScore(stats.Rules, stats.RuleErrors, weights)
 	}
 
-	fmt.Printf("  • Total Issues: %s\n", cellStyle.Render(fmt.Sprintf("%d", totalIssues)))
-	fmt.Printf("  • Errors: %s, Warnings: %s\n",
-		errorStyle.Render(fmt.Sprintf("%d", totalErrors)),
-		warningStyle.Render(fmt.Sprintf("%d", totalWarnings)))
-	fmt.Printf("  • Authors with issues: %s\n", cellStyle.Render(fmt.Sprintf("%d", len(authorStats))))
-	fmt.Printf("  • Files with issues: %s\n", cellStyle.Render(fmt.Sprintf("%d", len(fileStats))))
-	fmt.Printf("  • Unique rule violations: %s\n", cellStyle.Render(fmt.Sprintf("%d", len(ruleStats))))
+	if parseErrors, exists := ruleStats[eslint.ParseErrorRuleID]; exists {
+		summary.UnparseableFiles = len(parseErrors.Files)
+	}
 
 	if len(authorStats) > 0 {
-		avgIssuesPerAuthor := float64(totalIssues) / float64(len(authorStats))
-		fmt.Printf("  • Average issues per author: %s\n",
-			cellStyle.Render(fmt.Sprintf("%.1f", avgIssuesPerAuthor)))
+		summary.AvgIssuesPerAuthor = float64(summary.TotalIssues) / float64(len(authorStats))
 	}
 
 	if len(fileStats) > 0 {
-		avgIssuesPerFile := float64(totalIssues) / float64(len(fileStats))
-		fmt.Printf("  • Average issues per file: %s\n",
-			cellStyle.Render(fmt.Sprintf("%.1f", avgIssuesPerFile)))
+		summary.AvgIssuesPerFile = float64(summary.TotalIssues) / float64(len(fileStats))
+	}
+
+	return summary
+}
+
+// ChurnSort is the column the churn leaderboard is sorted by.
+type ChurnSort string
+
+const (
+	ChurnSortChanges ChurnSort = "changes"
+	ChurnSortRate    ChurnSort = "rate"
+)
+
+// ParseChurnSort parses the column of a --sort churn=COLUMN option: changes
+// or rate.
+func ParseChurnSort(value string) (ChurnSort, error) {
+	switch sortBy := ChurnSort(value); sortBy {
+	case ChurnSortChanges, ChurnSortRate:
+		return sortBy, nil
 	}
+	return "", fmt.Errorf("invalid churn sort %q: expected changes or rate", value)
 }
 
-func GenerateCodeChurnLeaderboard(trackedFiles map[string]bool, topN int) ([]types.ChurnEntry, error) {
-	cmd := exec.Command("git", "log", "--numstat", "--pretty=format:")
+// SpellCheckSort is the column the authors of the spell check leaderboard
+// are ranked by.
+type SpellCheckSort string
+
+const (
+	SpellCheckSortErrors SpellCheckSort = "errors"
+	SpellCheckSortRate   SpellCheckSort = "rate"
+)
+
+// ParseSpellCheckSort parses the column of a --sort spellcheck=COLUMN
+// option: errors or rate.
+func ParseSpellCheckSort(value string) (SpellCheckSort, error) {
+	switch sortBy := SpellCheckSort(value); sortBy {
+	case SpellCheckSortErrors, SpellCheckSortRate:
+		return sortBy, nil
+	}
+	return "", fmt.Errorf("invalid spellcheck sort %q: expected errors or rate", value)
+}
+
+// spellingRate returns the misspellings of an author per 1,000 of the
+// words they wrote that were checked, zero when there are none.
+func spellingRate(stats *types.SpellCheckAuthorStats) float64 {
+	if stats.TotalWords == 0 {
+		return 0
+	}
+	return float64(stats.TotalErrors) / float64(stats.TotalWords) * 1000
+}
+
+// hoursPerMonth is the length of an average month.
+const hoursPerMonth = 365.25 * 24 / 12
+
+// churnRate returns the changes per month of a file added at created. Files
+// younger than a month count as a month old, so a file added yesterday is
+// not ranked by a rate it has not kept up.
+func churnRate(changes int, created, now time.Time) float64 {
+	if created.IsZero() {
+		return 0
+	}
+	months := max(now.Sub(created).Hours()/hoursPerMonth, 1)
+	return float64(changes) / months
+}
+
+// GenerateCodeChurnLeaderboard ranks the tracked files by the commits that
+// changed them, or by those changes per month since the file was added
+// when sortBy is ChurnSortRate. An empty sortBy means ChurnSortChanges.
+func GenerateCodeChurnLeaderboard(ctx context.Context, dir string, trackedFiles map[string]bool, dateType git.DateType, now time.Time, topN int, sortBy ChurnSort) ([]types.ChurnEntry, error) {
+	cmd := exec.CommandContext(ctx, "git", "-c", "core.quotePath=false", "log", "--numstat", "--pretty=format:")
+	cmd.Dir = dir
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get git log data: %w", err)
 	}
 
+	created, err := git.GetFileCreationDates(ctx, dir, dateType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file creation dates: %w", err)
+	}
+
 	churnData := make(map[string]*types.ChurnEntry)
 
 	lines := strings.Split(string(output), "\n")
@@ -241,7 +628,7 @@ func GenerateCodeChurnLeaderboard(trackedFiles map[string]bool, topN int) ([]typ
 		}
 
 		if churnData[filePath] == nil {
-			churnData[filePath] = &types.ChurnEntry{Path: filePath}
+			churnData[filePath] = &types.ChurnEntry{Path: filePath, FirstCommit: created[filePath]}
 		}
 
 		entry := churnData[filePath]
@@ -253,18 +640,26 @@ func GenerateCodeChurnLeaderboard(trackedFiles map[string]bool, topN int) ([]typ
 
 	var entries []types.ChurnEntry
 	for _, entry := range churnData {
+		entry.ChurnRate = churnRate(entry.Changes, entry.FirstCommit, now)
 		entries = append(entries, *entry)
 	}
 
-	sort.Slice(entries, func(i, j int) bool {
-		return entries[i].Changes > entries[j].Changes
+	sort.SliceStable(entries, func(i, j int) bool {
+		if sortBy == ChurnSortRate && entries[i].ChurnRate != entries[j].ChurnRate {
+			return entries[i].ChurnRate > entries[j].ChurnRate
+		}
+		if entries[i].Changes != entries[j].Changes {
+			return entries[i].Changes > entries[j].Changes
+		}
+		return entries[i].Path < entries[j].Path
 	})
 
 	return entries, nil
 }
 
-func GenerateBugDensityLeaderboard(trackedFiles map[string]bool, topN int) ([]types.BugDensityEntry, error) {
-	cmd := exec.Command("git", "log", "--name-only", "--pretty=format:%H|%s")
+func GenerateBugDensityLeaderboard(ctx context.Context, dir string, trackedFiles map[string]bool, topN int) ([]types.BugDensityEntry, error) {
+	cmd := exec.CommandContext(ctx, "git", "-c", "core.quotePath=false", "log", "--name-only", "--pretty=format:%H|%s")
+	cmd.Dir = dir
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get commit data: %w", err)
@@ -278,6 +673,21 @@ func GenerateBugDensityLeaderboard(trackedFiles map[string]bool, topN int) ([]ty
 	var currentCommitIsBug bool
 	var currentFiles []string
 
+	// flush counts the files of the commit just read
+	flush := func() {
+		for _, filePath := range currentFiles {
+			if fileStats[filePath] == nil {
+				fileStats[filePath] = &types.BugDensityEntry{Path: filePath}
+			}
+
+			entry := fileStats[filePath]
+			entry.TotalCommits++
+			if currentCommitIsBug {
+				entry.BugFixes++
+			}
+		}
+	}
+
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
 		if line == "" {
@@ -285,20 +695,7 @@ func GenerateBugDensityLeaderboard(trackedFiles map[string]bool, topN int) ([]ty
 		}
 
 		if strings.Contains(line, "|") {
-			// Process previous commit's files
-			if len(currentFiles) > 0 {
-				for _, filePath := range currentFiles {
-					if fileStats[filePath] == nil {
-						fileStats[filePath] = &types.BugDensityEntry{Path: filePath}
-					}
-
-					entry := fileStats[filePath]
-					entry.TotalCommits++
-					if currentCommitIsBug {
-						entry.BugFixes++
-					}
-				}
-			}
+			flush()
 
 			// New commit
 			parts := strings.Split(line, "|")
@@ -314,6 +711,7 @@ func GenerateBugDensityLeaderboard(trackedFiles map[string]bool, topN int) ([]ty
 			}
 		}
 	}
+	flush()
 
 	var entries []types.BugDensityEntry
 	for _, entry := range fileStats {
@@ -325,43 +723,68 @@ func GenerateBugDensityLeaderboard(trackedFiles map[string]bool, topN int) ([]ty
 		}
 	}
 
-	sort.Slice(entries, func(i, j int) bool {
-		return entries[i].BugRatio > entries[j].BugRatio
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].BugRatio != entries[j].BugRatio {
+			return entries[i].BugRatio > entries[j].BugRatio
+		}
+		return entries[i].Path < entries[j].Path
 	})
 
 	return entries, nil
 }
 
-func GenerateTechnicalDebtLeaderboard(trackedFiles map[string]bool, topN int) ([]types.TechnicalDebtEntry, error) {
+// GenerateTechnicalDebtLeaderboard counts the TODO, FIXME and HACK comments
+// of each file, leaving out those filter suppresses. Files with a line
+// longer than maxLineKB kilobytes are skipped and added to warnings, so a
+// partial count is never reported.
+func GenerateTechnicalDebtLeaderboard(dir string, trackedFiles map[string]bool, maxLineKB int, filter *suppression.Filter, warnings *utils.WarningCollector, topN int, progress utils.ProgressFunc) ([]types.TechnicalDebtEntry, error) {
 	var entries []types.TechnicalDebtEntry
 
 	todoRegex := regexp.MustCompile(`(?i)//\s*todo|#\s*todo|/\*\s*todo`)
 	fixmeRegex := regexp.MustCompile(`(?i)//\s*fixme|#\s*fixme|/\*\s*fixme`)
 	hackRegex := regexp.MustCompile(`(?i)//\s*hack|#\s*hack|/\*\s*hack`)
 
+	// counted reports whether the marker on line counts, recording it with
+	// filter
+	var counter fingerprint.Counter
+	counted := func(path, marker, line string) bool {
+		record := suppression.Record{Kind: suppression.KindDebt, Path: path, Rule: marker, Fingerprint: counter.Next(path, marker, line)}
+		return !filter.Suppress(record)
+	}
+
+	done := 0
 	for filePath := range trackedFiles {
-		file, err := os.Open(filePath)
+		progress.Report(done, len(trackedFiles))
+		done++
+
+		file, err := utils.OpenRegular(filepath.Join(dir, filePath))
 		if err != nil {
 			continue
 		}
 
 		var todoCount, fixmeCount, hackCount int
-		scanner := bufio.NewScanner(file)
+		scanner := utils.NewLineScanner(file, maxLineKB)
+		path := filepath.ToSlash(filePath)
+		counter = make(fingerprint.Counter)
 
 		for scanner.Scan() {
 			line := scanner.Text()
-			if todoRegex.MatchString(line) {
+			if todoRegex.MatchString(line) && counted(path, "TODO", line) {
 				todoCount++
 			}
-			if fixmeRegex.MatchString(line) {
+			if fixmeRegex.MatchString(line) && counted(path, "FIXME", line) {
 				fixmeCount++
 			}
-			if hackRegex.MatchString(line) {
+			if hackRegex.MatchString(line) && counted(path, "HACK", line) {
 				hackCount++
 			}
 		}
 
 		file.Close()
+		if err := scanner.Err(); err != nil {
+			warnings.Add(types.NewWarning("Technical debt scan skipped", "file", filePath, "error", err))
+			continue
+		}
 
 		totalDebt := todoCount + fixmeCount + hackCount
 		if totalDebt > 0 {
@@ -374,25 +797,48 @@ func GenerateTechnicalDebtLeaderboard(trackedFiles map[string]bool, topN int) ([
 			})
 		}
 	}
+	progress.Report(done, len(trackedFiles))
 
-	sort.Slice(entries, func(i, j int) bool {
-		return entries[i].TotalDebt > entries[j].TotalDebt
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].TotalDebt != entries[j].TotalDebt {
+			return entries[i].TotalDebt > entries[j].TotalDebt
+		}
+		return entries[i].Path < entries[j].Path
 	})
 
 	return entries, nil
 }
 
-func GenerateCodeCoverageLeaderboard(trackedFiles map[string]bool, coverageFile string, topN int) ([]types.CoverageEntry, float64) {
-	coverageData, err := coverage.ParseCoverageFile(coverageFile)
+// GenerateCodeCoverageLeaderboard ranks the tracked files by coverage, lowest
+// first, and returns the overall coverage and how the report compares with
+// lastCommit, the commit date of HEAD, and the covered files. A repository
+// without a report has no entries and nil freshness.
+func GenerateCodeCoverageLeaderboard(dir string, trackedFiles map[string]bool, coverageFile string, lastCommit time.Time, topN int) ([]types.CoverageEntry, float64, *types.CoverageFreshness, error) {
+	reportPath, err := coverage.ResolveCoverageFile(dir, coverageFile)
+	if errors.Is(err, coverage.ErrNotFound) {
+		// Coverage is optional, so a repository without a report has no data
+		return nil, 0.0, nil, nil
+	}
 	if err != nil {
-		return nil, 0.0
+		return nil, 0.0, nil, err
+	}
+	coverageData, err := coverage.ParseCoverageFile(dir, reportPath)
+	if err != nil {
+		return nil, 0.0, nil, err
+	}
+	freshness, err := coverage.Freshness(dir, reportPath, coverageData, lastCommit)
+	if err != nil {
+		return nil, 0.0, nil, err
 	}
 
-	entries := coverage.GetCoverageStats(coverageData, trackedFiles)
+	entries := coverage.GetCoverageStats(dir, coverageData, trackedFiles)
 
 	// Sort by coverage percentage (lowest first - files that need attention)
-	sort.Slice(entries, func(i, j int) bool {
-		return entries[i].CoveragePercent < entries[j].CoveragePercent
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].CoveragePercent != entries[j].CoveragePercent {
+			return entries[i].CoveragePercent < entries[j].CoveragePercent
+		}
+		return entries[i].Path < entries[j].Path
 	})
 
 	// Overall coverage summary
@@ -409,11 +855,11 @@ func GenerateCodeCoverageLeaderboard(trackedFiles map[string]bool, coverageFile
 		overallCoverage = float64(coveredLines) / float64(totalLines) * 100
 	}
 
-	return entries, overallCoverage
+	return entries, overallCoverage, freshness, nil
 }
 
-func GenerateSpellCheckLeaderboard(trackedFiles map[string]bool, cfg *config.Config, topN int) ([]types.SpellCheckEntry, map[string]*types.SpellCheckAuthorStats, error) {
-	entries, authorStats, err := spellcheck.AnalyzeSpelling(trackedFiles, cfg)
+func GenerateSpellCheckLeaderboard(ctx context.Context, dir string, trackedFiles map[string]bool, cfg *config.Config, blamer *git.Blamer, warnings *utils.WarningCollector, topN int, progress utils.ProgressFunc) ([]types.SpellCheckEntry, map[string]*types.SpellCheckAuthorStats, error) {
+	entries, authorStats, err := spellcheck.AnalyzeSpelling(ctx, dir, trackedFiles, cfg, blamer, warnings, progress)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to analyze spelling: %w", err)
 	}
@@ -479,395 +925,707 @@ func formatDuration(d time.Duration) string {
 	}
 }
 
-func formatNetLines(net int) string {
+func (p *Printer) formatNetLines(net int) string {
 	if net > 0 {
-		return lipgloss.NewStyle().Foreground(lipgloss.Color("#00FF00")).Render(fmt.Sprintf("+%d", net))
+		return p.renderer.NewStyle().Foreground(lipgloss.Color("#00FF00")).Render("+" + p.formatCount(net))
 	} else if net < 0 {
-		return lipgloss.NewStyle().Foreground(lipgloss.Color("#FF0000")).Render(fmt.Sprintf("%d", net))
+		return p.renderer.NewStyle().Foreground(lipgloss.Color("#FF0000")).Render(p.formatCount(net))
 	}
-	return lipgloss.NewStyle().Foreground(lipgloss.Color("#878787")).Render("0")
+	return p.renderer.NewStyle().Foreground(lipgloss.Color("#878787")).Render("0")
 }
 
-// Print functions (moved from main.go for better organization)
+// filePath renders path for a table cell, marking files that are not
+// tracked by git yet.
+func (p *Printer) filePath(path string, untracked bool) string {
+	if untracked {
+		return p.pathCell(p.cellStyle, path) + " " + cell(p.warningStyle, "(untracked)")
+	}
+	return p.pathCell(p.cellStyle, path)
+}
 
-var (
-	titleStyle = lipgloss.NewStyle().
-		Bold(true).
-		Foreground(lipgloss.Color("#FAFAFA")).
-		Background(lipgloss.Color("#5d5d5d")).
-		PaddingLeft(1).
-		PaddingRight(1)
+// Printer renders leaderboards to a writer. Colors are chosen for the
+// writer, so output to a file or pipe is plain text.
+type Printer struct {
+	w        io.Writer
+	renderer *lipgloss.Renderer
+
+	// now is the time recent activity is measured against
+	now func() time.Time
+
+	pathStyle    PathStyle
+	verbose      bool
+	width        int  // of the output in columns, zero if unknown
+	groupNumbers bool // in counts, such as 12,345
+
+	// spellCheckSort is the column the spell check authors are ranked by
+	spellCheckSort SpellCheckSort
+
+	// maxRows caps the entries of every leaderboard, whatever topN asks,
+	// zero for no cap. capped is the cap note of the leaderboard being
+	// printed, shown under its table.
+	maxRows int
+	capHint string
+	capped  string
+
+	titleStyle   lipgloss.Style
+	headerStyle  lipgloss.Style
+	cellStyle    lipgloss.Style
+	rankStyle    lipgloss.Style
+	nameStyle    lipgloss.Style
+	emailStyle   lipgloss.Style
+	topRuleStyle lipgloss.Style
+	errorStyle   lipgloss.Style
+	warningStyle lipgloss.Style
+}
 
-	headerStyle = lipgloss.NewStyle().
-		Bold(true).
-		Foreground(lipgloss.Color("#FAFAFA"))
+// NewPrinter returns a printer that writes to w, with colors if w is a
+// terminal that supports them.
+func NewPrinter(w io.Writer) *Printer {
+	return newPrinter(w, lipgloss.NewRenderer(w))
+}
 
-	cellStyle = lipgloss.NewStyle().
+// NewPlainPrinter returns a printer that writes to w without colors or text
+// attributes.
+func NewPlainPrinter(w io.Writer) *Printer {
+	return newPrinter(w, lipgloss.NewRenderer(w, termenv.WithProfile(termenv.Ascii)))
+}
+
+// SetOutput makes p write to w, keeping the colors it chose for the writer
+// it was made for, such as when its output is held back to be paged.
+func (p *Printer) SetOutput(w io.Writer) {
+	p.w = w
+}
+
+func newPrinter(w io.Writer, r *lipgloss.Renderer) *Printer {
+	cellStyle := r.NewStyle().
 		PaddingLeft(1).
 		PaddingRight(1)
 
-	rankStyle = cellStyle.Copy().
-		Foreground(lipgloss.Color("#878787"))
+	return &Printer{
+		w:         w,
+		renderer:  r,
+		now:       time.Now,
+		pathStyle: PathFull,
+
+		titleStyle: r.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("#FAFAFA")).
+			Background(lipgloss.Color("#5d5d5d")).
+			PaddingLeft(1).
+			PaddingRight(1),
+
+		headerStyle: r.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("#FAFAFA")),
+
+		cellStyle:    cellStyle,
+		rankStyle:    cellStyle.Foreground(lipgloss.Color("#878787")),
+		nameStyle:    cellStyle.Foreground(lipgloss.Color("#d75f00")),
+		emailStyle:   cellStyle.Foreground(lipgloss.Color("#878787")),
+		topRuleStyle: cellStyle.Foreground(lipgloss.Color("#ffd700")),
+		errorStyle:   cellStyle.Foreground(lipgloss.Color("#ff0000")),
+		warningStyle: cellStyle.Foreground(lipgloss.Color("#ffff00")),
+	}
+}
+func (p *Printer) PrintSummaryStats(summary types.SummaryStats) {
+	fmt.Fprintln(p.w, p.titleStyle.Render("Repository Summary"))
 
-	nameStyle = cellStyle.Copy().
-		Foreground(lipgloss.Color("#d75f00"))
+	fmt.Fprintf(p.w, "  • Total Issues: %s\n", p.cellStyle.Render(p.formatCount(summary.TotalIssues)))
+	fmt.Fprintf(p.w, "  • Errors: %s, Warnings: %s\n",
+		p.errorStyle.Render(p.formatCount(summary.Errors)),
+		p.warningStyle.Render(p.formatCount(summary.Warnings)))
+	if summary.WeightedIssues > 0 {
+		fmt.Fprintf(p.w, "  • Weighted issues: %s\n", p.cellStyle.Render(fmt.Sprintf("%.1f", summary.WeightedIssues)))
+	}
+	fmt.Fprintf(p.w, "  • Authors with issues: %s\n", p.cellStyle.Render(p.formatCount(summary.Authors)))
+	fmt.Fprintf(p.w, "  • Files with issues: %s\n", p.cellStyle.Render(p.formatCount(summary.Files)))
+	fmt.Fprintf(p.w, "  • Unique rule violations: %s\n", p.cellStyle.Render(p.formatCount(summary.Rules)))
 
-	emailStyle = cellStyle.Copy().
-		Foreground(lipgloss.Color("#878787"))
+	if summary.UnparseableFiles > 0 {
+		fmt.Fprintf(p.w, "  • Unparseable files: %s\n", p.errorStyle.Render(p.formatCount(summary.UnparseableFiles)))
+	}
 
-	topRuleStyle = cellStyle.Copy().
-		Foreground(lipgloss.Color("#ffd700"))
+	if summary.Authors > 0 {
+		fmt.Fprintf(p.w, "  • Average issues per author: %s\n",
+			p.cellStyle.Render(fmt.Sprintf("%.1f", summary.AvgIssuesPerAuthor)))
+	}
 
-	errorStyle = cellStyle.Copy().
-		Foreground(lipgloss.Color("#ff0000"))
+	if summary.Files > 0 {
+		fmt.Fprintf(p.w, "  • Average issues per file: %s\n",
+			p.cellStyle.Render(fmt.Sprintf("%.1f", summary.AvgIssuesPerFile)))
+	}
+}
 
-	warningStyle = cellStyle.Copy().
-		Foreground(lipgloss.Color("#ffff00"))
-)
+// PrintIssueBaseline prints how many issues are new since the reference,
+// below the repository summary.
+func (p *Printer) PrintIssueBaseline(baseline types.IssueBaseline) {
+	fmt.Fprintf(p.w, "  • New issues: %s, Pre-existing: %s, Fixed: %s (compared with %s)\n",
+		p.errorStyle.Render(p.formatCount(baseline.New)),
+		p.warningStyle.Render(p.formatCount(baseline.Existing)),
+		p.cellStyle.Render(p.formatCount(baseline.Fixed)),
+		baseline.Reference)
+}
 
-func PrintAuthorLeaderboard(entries []types.LeaderboardEntry, topN int) {
-	fmt.Println(titleStyle.Render("Author Leaderboard - Most ESLint Issues"))
+func (p *Printer) PrintAuthorLeaderboard(entries []types.LeaderboardEntry, topN int) {
+	fmt.Fprintln(p.w, p.titleStyle.Render("Author Leaderboard - Most ESLint Issues"))
 
 	if len(entries) == 0 {
-		fmt.Println(cellStyle.Render("🎉 Everyone's clean. No one to shame."))
+		fmt.Fprintln(p.w, p.cellStyle.Render("🎉 Everyone's clean. No one to shame."))
 		return
 	}
 
-	maxEntries := topN
-	if len(entries) < maxEntries {
-		maxEntries = len(entries)
+	maxEntries := p.limit(topN, len(entries))
+
+	// Authors merged across repositories also count their repositories
+	multiRepo := false
+	// Weighted issues show the score authors are ranked by
+	decayed, weighted := false, false
+	for _, entry := range entries {
+		multiRepo = multiRepo || len(entry.Repos) > 0
+		decayed = decayed || entry.DecayedScore > 0
+		weighted = weighted || entry.WeightedScore > 0
 	}
 
+	columns := []column{rankColumn, {header: "Author"}, {header: "Email"},
+		{header: "Issues", right: true}}
+	if weighted {
+		columns = append(columns, column{header: "Weighted", right: true})
+	}
+	if decayed {
+		columns = append(columns, column{header: "Decayed", right: true})
+	}
+	columns = append(columns, column{header: "Errors", right: true}, column{header: "Warnings", right: true},
+		column{header: "Files", right: true})
+	if multiRepo {
+		columns = append(columns, column{header: "Repos", right: true})
+	}
+	t := newTable(append(columns, column{header: "Top Rule"})...)
 	for i := 0; i < maxEntries; i++ {
 		entry := entries[i]
-		rank := rankStyle.Render(fmt.Sprintf("%2d", i+1))
-		name := nameStyle.Render(entry.Name)
-		email := emailStyle.Render(fmt.Sprintf("(%s)", entry.Email))
-		topRule := topRuleStyle.Render(entry.TopRule)
-		errors := errorStyle.Render(fmt.Sprintf("%d", entry.Errors))
-		warnings := warningStyle.Render(fmt.Sprintf("%d", entry.Warnings))
-
-		fmt.Printf("%s. %s %s – %d issues (%s errors, %s warnings), %d files, top rule: %s (%d)\n",
-			rank, name, email, entry.Count, errors, warnings, entry.Files, topRule, entry.TopCount)
+		cells := []string{
+			cell(p.rankStyle, fmt.Sprintf("%d", i+1)),
+			cell(p.nameStyle, entry.Name),
+			cell(p.emailStyle, entry.Email),
+			p.count(entry.Count),
+		}
+		if weighted {
+			cells = append(cells, cell(p.cellStyle, fmt.Sprintf("%.1f", entry.WeightedScore)))
+		}
+		if decayed {
+			cells = append(cells, cell(p.cellStyle, fmt.Sprintf("%.1f", entry.DecayedScore)))
+		}
+		cells = append(cells,
+			cell(p.errorStyle, p.formatCount(entry.Errors)),
+			cell(p.warningStyle, p.formatCount(entry.Warnings)),
+			p.count(entry.Files),
+		)
+		if multiRepo {
+			cells = append(cells, p.count(len(entry.Repos)))
+		}
+		t.row(append(cells, fmt.Sprintf("%s (%s)", cell(p.topRuleStyle, entry.TopRule), p.formatCount(entry.TopCount)))...)
 	}
+	p.printTable(t)
 }
 
-func PrintFileLeaderboard(entries []types.FileLeaderboardEntry, topN int) {
-	fmt.Println(titleStyle.Render("File Leaderboard - Most Problematic Files"))
+// PrintFileLeaderboard prints the most problematic files. With detail set,
+// each file is followed by the authors blamed for most of its issues.
+func (p *Printer) PrintFileLeaderboard(entries []types.FileLeaderboardEntry, topN int, detail bool) {
+	fmt.Fprintln(p.w, p.titleStyle.Render("File Leaderboard - Most Problematic Files"))
+
+	maxEntries := p.limit(topN, len(entries))
 
-	maxEntries := topN
-	if len(entries) < maxEntries {
-		maxEntries = len(entries)
+	// Files whose owners were resolved show who should act on them
+	owned := slices.ContainsFunc(entries[:maxEntries], func(entry types.FileLeaderboardEntry) bool { return entry.OwnerSource != "" })
+	decayed := slices.ContainsFunc(entries[:maxEntries], func(entry types.FileLeaderboardEntry) bool { return entry.DecayedScore > 0 })
+	weighted := slices.ContainsFunc(entries[:maxEntries], func(entry types.FileLeaderboardEntry) bool { return entry.WeightedScore > 0 })
+
+	columns := []column{rankColumn, fileColumn, {header: "Issues", right: true}}
+	if weighted {
+		columns = append(columns, column{header: "Weighted", right: true})
+	}
+	if decayed {
+		columns = append(columns, column{header: "Decayed", right: true})
+	}
+	columns = append(columns, column{header: "Errors", right: true}, column{header: "Warnings", right: true},
+		column{header: "Authors", right: true}, column{header: "Top Rule"})
+	if owned {
+		columns = append(columns, column{header: "Owner"})
 	}
 
+	t := newTable(columns...)
 	for i := 0; i < maxEntries; i++ {
 		entry := entries[i]
-		rank := rankStyle.Render(fmt.Sprintf("%2d", i+1))
-		path := cellStyle.Render(entry.Path)
-		topRule := topRuleStyle.Render(entry.TopRule)
 
-		fmt.Printf("%s. %s – %d issues, %d authors, top rule: %s (%d)\n",
-			rank, path, entry.Count, entry.Authors, topRule, entry.TopCount)
+		issues := p.count(entry.Count)
+		if entry.Overflow > 0 {
+			issues += " " + cell(p.emailStyle, fmt.Sprintf("(+%s more)", p.formatCount(entry.Overflow)))
+		}
+
+		cells := []string{
+			cell(p.rankStyle, fmt.Sprintf("%d", i+1)),
+			p.pathCell(p.cellStyle, entry.Path),
+			issues,
+		}
+		if weighted {
+			cells = append(cells, cell(p.cellStyle, fmt.Sprintf("%.1f", entry.WeightedScore)))
+		}
+		if decayed {
+			cells = append(cells, cell(p.cellStyle, fmt.Sprintf("%.1f", entry.DecayedScore)))
+		}
+		cells = append(cells,
+			cell(p.errorStyle, p.formatCount(entry.Errors)),
+			cell(p.warningStyle, p.formatCount(entry.Warnings)),
+			p.count(entry.Authors),
+			fmt.Sprintf("%s (%s)", cell(p.topRuleStyle, entry.TopRule), p.formatCount(entry.TopCount)),
+		)
+		if owned {
+			cells = append(cells, cell(p.nameStyle, formatOwners(entry)))
+		}
+		t.row(cells...)
+
+		if detail {
+			// The authors blamed for the file share its issue column
+			for _, author := range entry.TopAuthors {
+				t.row("", "  "+cell(p.emailStyle, author.Email), p.count(author.Count))
+			}
+		}
 	}
+	p.printTable(t)
 }
 
-func PrintRuleLeaderboard(entries []types.RuleLeaderboardEntry, topN int) {
-	fmt.Println(titleStyle.Render("Rule Leaderboard - Most Violated Rules"))
+func (p *Printer) PrintRuleLeaderboard(entries []types.RuleLeaderboardEntry, topN int) {
+	fmt.Fprintln(p.w, p.titleStyle.Render("Rule Leaderboard - Most Violated Rules"))
 
-	maxEntries := topN
-	if len(entries) < maxEntries {
-		maxEntries = len(entries)
+	maxEntries := p.limit(topN, len(entries))
+
+	// Verbose output links the documentation of the rules that have any
+	columns := []column{rankColumn, {header: "Rule"}, {header: "Violations", right: true},
+		{header: "Fixable", right: true}, {header: "Authors", right: true}, {header: "Files", right: true}}
+	if p.verbose && slices.ContainsFunc(entries[:maxEntries], func(entry types.RuleLeaderboardEntry) bool { return entry.DocURL != "" }) {
+		columns = append(columns, column{header: "Docs"})
 	}
 
+	t := newTable(columns...)
 	for i := 0; i < maxEntries; i++ {
 		entry := entries[i]
-		rank := rankStyle.Render(fmt.Sprintf("%2d", i+1))
-		rule := cellStyle.Render(entry.Rule)
-
-		fmt.Printf("%s. %s – %d violations, %d authors, %d files\n",
-			rank, rule, entry.Count, entry.Authors, entry.Files)
-	}
+		t.row(
+			cell(p.rankStyle, fmt.Sprintf("%d", i+1)),
+			cell(p.cellStyle, entry.Rule),
+			p.count(entry.Count),
+			p.count(entry.Fixable),
+			p.count(entry.Authors),
+			p.count(entry.Files),
+			cell(p.emailStyle, entry.DocURL),
+		)
+	}
+	p.printTable(t)
 }
 
-func PrintLinesOfCodeLeaderboard(entries []types.LinesOfCodeEntry, topN int) {
-	fmt.Println(titleStyle.Render("Lines of Code Leaderboard - Largest Files"))
+func (p *Printer) PrintRulePluginLeaderboard(entries []types.RulePluginEntry, topN int) {
+	fmt.Fprintln(p.w, p.titleStyle.Render("Rule Plugin Leaderboard - Most Violated Plugins"))
 
-	maxEntries := topN
-	if len(entries) < maxEntries {
-		maxEntries = len(entries)
-	}
+	maxEntries := p.limit(topN, len(entries))
 
+	t := newTable(rankColumn, column{header: "Plugin"}, column{header: "Violations", right: true}, column{header: "Rules", right: true})
 	for i := 0; i < maxEntries; i++ {
 		entry := entries[i]
-		rank := rankStyle.Render(fmt.Sprintf("%2d", i+1))
-		path := cellStyle.Render(entry.Path)
-		size := emailStyle.Render(formatFileSize(entry.Size))
-
-		fmt.Printf("%s. %s – %s lines (%s)\n",
-			rank, path, cellStyle.Render(fmt.Sprintf("%d", entry.Lines)), size)
-	}
+		t.row(
+			cell(p.rankStyle, fmt.Sprintf("%d", i+1)),
+			cell(p.cellStyle, entry.Plugin),
+			p.count(entry.Count),
+			p.count(entry.DistinctRules),
+		)
+	}
+	p.printTable(t)
 }
 
-func PrintCommitCountLeaderboard(entries []types.CommitCountEntry, topN int) {
-	fmt.Println(titleStyle.Render("Commit Count Leaderboard - Most Active Contributors"))
+func (p *Printer) PrintRuleGroupLeaderboard(entries []types.RuleGroupEntry, topN int) {
+	fmt.Fprintln(p.w, p.titleStyle.Render("Rule Group Leaderboard - Violations per Rule Group"))
 
 	if len(entries) == 0 {
-		fmt.Println(cellStyle.Render("📭 No commit data found"))
+		fmt.Fprintln(p.w, p.cellStyle.Render("🎉 No rule violations to group"))
 		return
 	}
 
-	maxEntries := topN
-	if len(entries) < maxEntries {
-		maxEntries = len(entries)
-	}
+	maxEntries := p.limit(topN, len(entries))
 
+	t := newTable(rankColumn, column{header: "Group"}, column{header: "Violations", right: true}, column{header: "Rules", right: true})
 	for i := 0; i < maxEntries; i++ {
 		entry := entries[i]
-		rank := rankStyle.Render(fmt.Sprintf("%2d", i+1))
-		name := nameStyle.Render(entry.Name)
-		email := emailStyle.Render(fmt.Sprintf("(%s)", entry.Email))
-		timespan := emailStyle.Render(formatDuration(entry.LastCommit.Sub(entry.FirstCommit)))
+		group := cell(p.topRuleStyle, entry.Group)
+		if entry.Group == UngroupedRules {
+			group = cell(p.emailStyle, entry.Group)
+		}
+		t.row(
+			cell(p.rankStyle, fmt.Sprintf("%d", i+1)),
+			group,
+			p.count(entry.Count),
+			p.count(entry.DistinctRules),
+		)
+	}
+	p.printTable(t)
+}
+
+// PrintRuleAuthorMatrix prints the top author of each rule next to the top
+// rule of each author, with the share of the rule's or author's violations
+// the top cell has.
+func (p *Printer) PrintRuleAuthorMatrix(cells []types.RuleAuthorCell, topN int) {
+	fmt.Fprintln(p.w, p.titleStyle.Render("Rule × Author Matrix - Who Violates Which Rules"))
+
+	if len(cells) == 0 {
+		fmt.Fprintln(p.w, p.cellStyle.Render("🎉 No rule violations to cross-tabulate"))
+		return
+	}
+
+	share := func(top matrixTop) string {
+		return fmt.Sprintf("%.0f%%", float64(top.Count)/float64(top.Total)*100)
+	}
+
+	byRule := topAuthorPerRule(cells)
+	left := newTable(rankColumn, column{header: "Rule"}, column{header: "Top Author"}, column{header: "Issues", right: true}, column{header: "Share", right: true})
+	for i, top := range byRule[:min(topN, len(byRule))] {
+		left.row(cell(p.rankStyle, fmt.Sprintf("%d", i+1)), cell(p.topRuleStyle, top.Rule), cell(p.nameStyle, top.Name), p.count(top.Count), share(top))
+	}
 
-		fmt.Printf("%s. %s %s – %s commits (active for %s)\n",
-			rank, name, email, cellStyle.Render(fmt.Sprintf("%d", entry.Commits)), timespan)
+	byAuthor := topRulePerAuthor(cells)
+	right := newTable(rankColumn, column{header: "Author"}, column{header: "Top Rule"}, column{header: "Issues", right: true}, column{header: "Share", right: true})
+	for i, top := range byAuthor[:min(topN, len(byAuthor))] {
+		right.row(cell(p.rankStyle, fmt.Sprintf("%d", i+1)), cell(p.nameStyle, top.Name), cell(p.topRuleStyle, top.Rule), p.count(top.Count), share(top))
 	}
+
+	p.printTablesSideBySide(left, right)
+}
+
+func (p *Printer) PrintLinesOfCodeLeaderboard(entries []types.LinesOfCodeEntry, topN int) {
+	fmt.Fprintln(p.w, p.titleStyle.Render("Lines of Code Leaderboard - Largest Files"))
+
+	maxEntries := p.limit(topN, len(entries))
+
+	t := newTable(rankColumn, fileColumn, column{header: "Lines", right: true}, column{header: "Size", right: true})
+	for i := 0; i < maxEntries; i++ {
+		entry := entries[i]
+		t.row(
+			cell(p.rankStyle, fmt.Sprintf("%d", i+1)),
+			p.filePath(entry.Path, entry.Untracked),
+			p.count(entry.Lines),
+			cell(p.emailStyle, formatFileSize(entry.Size)),
+		)
+	}
+	p.printTable(t)
 }
 
-func PrintRecentContributorsLeaderboard(entries []types.RecentContributorEntry, topN int) {
-	fmt.Println(titleStyle.Render("Recent Contributors Leaderboard - Most Active in Last 30 Days"))
+func (p *Printer) PrintCommitCountLeaderboard(entries []types.CommitCountEntry, topN int) {
+	fmt.Fprintln(p.w, p.titleStyle.Render("Commit Count Leaderboard - Most Active Contributors"))
 
 	if len(entries) == 0 {
-		fmt.Println(cellStyle.Render("📭 No commits in the last 30 days"))
+		fmt.Fprintln(p.w, p.cellStyle.Render("📭 No commit data found"))
 		return
 	}
 
-	maxEntries := topN
-	if len(entries) < maxEntries {
-		maxEntries = len(entries)
+	maxEntries := p.limit(topN, len(entries))
+
+	// The co-author columns only show up in history with Co-authored-by
+	// trailers
+	coAuthored, split := false, false
+	for _, entry := range entries {
+		coAuthored = coAuthored || entry.CoAuthored > 0
+		split = split || entry.Credit > 0
 	}
 
+	columns := []column{rankColumn, {header: "Author"}, {header: "Email"}, {header: "Commits", right: true}}
+	if coAuthored {
+		columns = append(columns, column{header: "Co-authored", right: true})
+	}
+	if split {
+		columns = append(columns, column{header: "Credit", right: true})
+	}
+	t := newTable(append(columns, column{header: "Active For", right: true})...)
 	for i := 0; i < maxEntries; i++ {
 		entry := entries[i]
-		rank := rankStyle.Render(fmt.Sprintf("%2d", i+1))
-		name := nameStyle.Render(entry.Name)
-		email := emailStyle.Render(fmt.Sprintf("(%s)", entry.Email))
-		lastCommitAgo := emailStyle.Render(formatDuration(time.Since(entry.LastCommit)))
-
-		fmt.Printf("%s. %s %s – %s commits (last: %s ago)\n",
-			rank, name, email, cellStyle.Render(fmt.Sprintf("%d", entry.RecentCommits)), lastCommitAgo)
+		cells := []string{
+			cell(p.rankStyle, fmt.Sprintf("%d", i+1)),
+			cell(p.nameStyle, entry.Name),
+			cell(p.emailStyle, entry.Email),
+			p.count(entry.Commits),
+		}
+		if coAuthored {
+			cells = append(cells, p.count(entry.CoAuthored))
+		}
+		if split {
+			cells = append(cells, cell(p.cellStyle, fmt.Sprintf("%.1f", entry.Credit)))
+		}
+		t.row(append(cells, cell(p.emailStyle, formatDuration(entry.LastCommit.Sub(entry.FirstCommit))))...)
 	}
+	p.printTable(t)
 }
 
-func PrintCodeChurnLeaderboard(entries []types.ChurnEntry, topN int) {
-	fmt.Println(titleStyle.Render("Code Churn Leaderboard - Most Frequently Changed Files"))
+func (p *Printer) PrintRecentContributorsLeaderboard(entries []types.RecentContributorEntry, topN int) {
+	fmt.Fprintln(p.w, p.titleStyle.Render("Recent Contributors Leaderboard - Most Active in Last 30 Days"))
 
 	if len(entries) == 0 {
-		fmt.Println(cellStyle.Render("📭 No churn data found"))
+		fmt.Fprintln(p.w, p.cellStyle.Render("📭 No commits in the last 30 days"))
 		return
 	}
 
-	maxEntries := topN
-	if len(entries) < maxEntries {
-		maxEntries = len(entries)
-	}
+	maxEntries := p.limit(topN, len(entries))
 
+	t := newTable(rankColumn, column{header: "Author"}, column{header: "Email"},
+		column{header: "Commits", right: true}, column{header: "Last Commit", right: true})
 	for i := 0; i < maxEntries; i++ {
 		entry := entries[i]
-		rank := rankStyle.Render(fmt.Sprintf("%2d", i+1))
-		path := cellStyle.Render(entry.Path)
+		t.row(
+			cell(p.rankStyle, fmt.Sprintf("%d", i+1)),
+			cell(p.nameStyle, entry.Name),
+			cell(p.emailStyle, entry.Email),
+			p.count(entry.RecentCommits),
+			cell(p.emailStyle, formatDuration(p.now().Sub(entry.LastCommit))+" ago"),
+		)
+	}
+	p.printTable(t)
+}
+
+func (p *Printer) PrintCodeChurnLeaderboard(entries []types.ChurnEntry, topN int) {
+	fmt.Fprintln(p.w, p.titleStyle.Render("Code Churn Leaderboard - Most Frequently Changed Files"))
 
-		fmt.Printf("%s. %s – %s changes (%s lines added, %s deleted, net: %s)\n",
-			rank, path, cellStyle.Render(fmt.Sprintf("%d", entry.Changes)),
-			cellStyle.Render(fmt.Sprintf("%d", entry.AddedLines)),
-			cellStyle.Render(fmt.Sprintf("%d", entry.DeletedLines)),
-			formatNetLines(entry.NetLines))
+	if len(entries) == 0 {
+		fmt.Fprintln(p.w, p.cellStyle.Render("📭 No churn data found"))
+		return
 	}
+
+	maxEntries := p.limit(topN, len(entries))
+
+	t := newTable(rankColumn, fileColumn, column{header: "Changes", right: true}, column{header: "Per Month", right: true},
+		column{header: "Added", right: true}, column{header: "Deleted", right: true}, column{header: "Net", right: true})
+	for i := 0; i < maxEntries; i++ {
+		entry := entries[i]
+		rate := "-"
+		if !entry.FirstCommit.IsZero() {
+			rate = fmt.Sprintf("%.1f", entry.ChurnRate)
+		}
+		t.row(
+			cell(p.rankStyle, fmt.Sprintf("%d", i+1)),
+			p.pathCell(p.cellStyle, entry.Path),
+			p.count(entry.Changes),
+			cell(p.cellStyle, rate),
+			p.count(entry.AddedLines),
+			p.count(entry.DeletedLines),
+			p.formatNetLines(entry.NetLines),
+		)
+	}
+	p.printTable(t)
 }
 
-func PrintBugDensityLeaderboard(entries []types.BugDensityEntry, topN int) {
-	fmt.Println(titleStyle.Render("Bug Density Leaderboard - Files with Highest Bug-Fix Ratio"))
+func (p *Printer) PrintBugDensityLeaderboard(entries []types.BugDensityEntry, topN int) {
+	fmt.Fprintln(p.w, p.titleStyle.Render("Bug Density Leaderboard - Files with Highest Bug-Fix Ratio"))
 
 	if len(entries) == 0 {
-		fmt.Println(cellStyle.Render("📭 No bug density data found"))
+		fmt.Fprintln(p.w, p.cellStyle.Render("📭 No bug density data found"))
 		return
 	}
 
-	maxEntries := topN
-	if len(entries) < maxEntries {
-		maxEntries = len(entries)
-	}
+	maxEntries := p.limit(topN, len(entries))
 
+	t := newTable(rankColumn, fileColumn, column{header: "Bug-Fix Ratio", right: true},
+		column{header: "Fixes", right: true}, column{header: "Commits", right: true})
 	for i := 0; i < maxEntries; i++ {
 		entry := entries[i]
-		rank := rankStyle.Render(fmt.Sprintf("%2d", i+1))
-		path := cellStyle.Render(entry.Path)
 
 		var ratioStyle lipgloss.Style
 		if entry.BugRatio > 30 {
-			ratioStyle = errorStyle
+			ratioStyle = p.errorStyle
 		} else if entry.BugRatio > 15 {
-			ratioStyle = warningStyle
+			ratioStyle = p.warningStyle
 		} else {
-			ratioStyle = cellStyle
+			ratioStyle = p.cellStyle
 		}
 
-		fmt.Printf("%s. %s – %s bug-fix ratio (%d fixes out of %d commits)\n",
-			rank, path, ratioStyle.Render(fmt.Sprintf("%.1f%%", entry.BugRatio)),
-			entry.BugFixes, entry.TotalCommits)
+		t.row(
+			cell(p.rankStyle, fmt.Sprintf("%d", i+1)),
+			p.pathCell(p.cellStyle, entry.Path),
+			cell(ratioStyle, fmt.Sprintf("%.1f%%", entry.BugRatio)),
+			p.count(entry.BugFixes),
+			p.count(entry.TotalCommits),
+		)
 	}
+	p.printTable(t)
 }
 
-func PrintTechnicalDebtLeaderboard(entries []types.TechnicalDebtEntry, topN int) {
-	fmt.Println(titleStyle.Render("Technical Debt Leaderboard - Files with Most TODO/FIXME/HACK Comments"))
+func (p *Printer) PrintTechnicalDebtLeaderboard(entries []types.TechnicalDebtEntry, topN int) {
+	fmt.Fprintln(p.w, p.titleStyle.Render("Technical Debt Leaderboard - Files with Most TODO/FIXME/HACK Comments"))
 
 	if len(entries) == 0 {
-		fmt.Println(cellStyle.Render("🎉 No technical debt found (or you have very clean code!)"))
+		fmt.Fprintln(p.w, p.cellStyle.Render("🎉 No technical debt found (or you have very clean code!)"))
 		return
 	}
 
-	maxEntries := topN
-	if len(entries) < maxEntries {
-		maxEntries = len(entries)
+	maxEntries := p.limit(topN, len(entries))
+
+	// Zero counts are left blank so the markers a file has stand out
+	marker := func(style lipgloss.Style, n int) string {
+		if n == 0 {
+			return ""
+		}
+		return cell(style, p.formatCount(n))
 	}
 
+	t := newTable(rankColumn, fileColumn, column{header: "Debt", right: true},
+		column{header: "TODO", right: true}, column{header: "FIXME", right: true}, column{header: "HACK", right: true})
 	for i := 0; i < maxEntries; i++ {
 		entry := entries[i]
-		rank := rankStyle.Render(fmt.Sprintf("%2d", i+1))
-		path := cellStyle.Render(entry.Path)
-
-		var debtItems []string
-		if entry.TodoCount > 0 {
-			debtItems = append(debtItems, warningStyle.Render(fmt.Sprintf("%d TODOs", entry.TodoCount)))
-		}
-		if entry.FixmeCount > 0 {
-			debtItems = append(debtItems, errorStyle.Render(fmt.Sprintf("%d FIXMEs", entry.FixmeCount)))
-		}
-		if entry.HackCount > 0 {
-			debtItems = append(debtItems, topRuleStyle.Render(fmt.Sprintf("%d HACKs", entry.HackCount)))
-		}
-
-		fmt.Printf("%s. %s – %s total debt (%s)\n",
-			rank, path, cellStyle.Render(fmt.Sprintf("%d", entry.TotalDebt)), strings.Join(debtItems, ", "))
-	}
+		t.row(
+			cell(p.rankStyle, fmt.Sprintf("%d", i+1)),
+			p.filePath(entry.Path, entry.Untracked),
+			p.count(entry.TotalDebt),
+			marker(p.warningStyle, entry.TodoCount),
+			marker(p.errorStyle, entry.FixmeCount),
+			marker(p.topRuleStyle, entry.HackCount),
+		)
+	}
+	p.printTable(t)
 }
 
-func PrintCodeCoverageLeaderboard(entries []types.CoverageEntry, overallCoverage float64, topN int) {
-	fmt.Println(titleStyle.Render("Code Coverage Leaderboard - Coverage by File"))
+// PrintCodeCoverageLeaderboard prints the files with the lowest coverage.
+// Files and overall coverage below threshold, the min-coverage-threshold
+// setting, are highlighted and the files below it are counted. A stale
+// report is called out in the title and under it, with the covered files
+// that no longer exist.
+func (p *Printer) PrintCodeCoverageLeaderboard(entries []types.CoverageEntry, overallCoverage, threshold float64, freshness *types.CoverageFreshness, topN int) {
+	title := "Code Coverage Leaderboard - Coverage by File"
+	stale := coverage.StaleMessage(freshness)
+	if stale != "" {
+		title += " (stale report)"
+	}
+	fmt.Fprintln(p.w, p.titleStyle.Render(title))
+	if stale != "" {
+		fmt.Fprintln(p.w, p.warningStyle.Render(fmt.Sprintf("  ⚠️  The %s; regenerate it for accurate numbers", stale)))
+	}
+	if freshness != nil && freshness.MissingFiles > 0 {
+		fmt.Fprintln(p.w, p.warningStyle.Render(fmt.Sprintf("  ⚠️  %s covered files no longer exist", p.formatCount(freshness.MissingFiles))))
+	}
 
 	if len(entries) == 0 {
-		fmt.Println(cellStyle.Render("📭 No coverage data found for tracked files"))
+		fmt.Fprintln(p.w, p.cellStyle.Render("📭 No coverage data found for tracked files"))
 		return
 	}
 
-	maxEntries := topN
-	if len(entries) < maxEntries {
-		maxEntries = len(entries)
-	}
+	maxEntries := p.limit(topN, len(entries))
+
+	fmt.Fprintln(p.w, p.emailStyle.Render("  (Showing files with lowest coverage - need attention)"))
 
-	fmt.Println(emailStyle.Render("  (Showing files with lowest coverage - need attention)"))
+	// Functions and branches are blank for formats that do not report them
+	share := func(covered, total int) string {
+		if total == 0 {
+			return ""
+		}
+		return cell(p.emailStyle, fmt.Sprintf("%.0f%%", float64(covered)/float64(total)*100))
+	}
 
+	t := newTable(rankColumn, fileColumn, column{header: "Coverage", right: true},
+		column{header: "Lines", right: true}, column{header: "Functions", right: true}, column{header: "Branches", right: true})
 	for i := 0; i < maxEntries; i++ {
 		entry := entries[i]
-		rank := rankStyle.Render(fmt.Sprintf("%2d", i+1))
-		path := cellStyle.Render(entry.Path)
-
-		var coverageStyle lipgloss.Style
-		if entry.CoveragePercent >= 80 {
-			coverageStyle = cellStyle
-		} else if entry.CoveragePercent >= 60 {
-			coverageStyle = warningStyle
-		} else {
-			coverageStyle = errorStyle
-		}
 
-		coverageStr := coverageStyle.Render(fmt.Sprintf("%.1f%%", entry.CoveragePercent))
+		coverageStyle := p.cellStyle
+		if entry.CoveragePercent < threshold {
+			coverageStyle = p.errorStyle
+		}
 
-		var additionalInfo []string
+		lines := ""
 		if entry.LinesTotal > 0 {
-			additionalInfo = append(additionalInfo, fmt.Sprintf("%d/%d lines", entry.LinesCovered, entry.LinesTotal))
-		}
-		if entry.FunctionsTotal > 0 {
-			functionsPercent := float64(entry.FunctionsCovered) / float64(entry.FunctionsTotal) * 100
-			additionalInfo = append(additionalInfo, fmt.Sprintf("%.0f%% functions", functionsPercent))
-		}
-		if entry.BranchesTotal > 0 {
-			branchesPercent := float64(entry.BranchesCovered) / float64(entry.BranchesTotal) * 100
-			additionalInfo = append(additionalInfo, fmt.Sprintf("%.0f%% branches", branchesPercent))
+			lines = cell(p.emailStyle, p.formatCount(entry.LinesCovered)+"/"+p.formatCount(entry.LinesTotal))
 		}
 
-		infoStr := ""
-		if len(additionalInfo) > 0 {
-			infoStr = fmt.Sprintf(" (%s)", emailStyle.Render(strings.Join(additionalInfo, ", ")))
-		}
+		t.row(
+			cell(p.rankStyle, fmt.Sprintf("%d", i+1)),
+			p.pathCell(p.cellStyle, entry.Path),
+			cell(coverageStyle, fmt.Sprintf("%.1f%%", entry.CoveragePercent)),
+			lines,
+			share(entry.FunctionsCovered, entry.FunctionsTotal),
+			share(entry.BranchesCovered, entry.BranchesTotal),
+		)
+	}
+	p.printTable(t)
 
-		fmt.Printf("%s. %s – %s%s\n", rank, path, coverageStr, infoStr)
+	below := 0
+	for _, entry := range entries {
+		if entry.CoveragePercent < threshold {
+			below++
+		}
+	}
+	if below > 0 {
+		fmt.Fprintf(p.w, "\n  %s %s of %s files below the %.1f%% minimum coverage\n",
+			p.warningStyle.Render("⚠️"), cell(p.errorStyle, p.formatCount(below)), p.formatCount(len(entries)), threshold)
 	}
 
 	if len(entries) > topN {
-		fmt.Println(cellStyle.Render("\n🏆 Files with highest coverage:"))
+		fmt.Fprintln(p.w, p.cellStyle.Render("\n🏆 Files with highest coverage:"))
 
-		sort.Slice(entries, func(i, j int) bool {
-			return entries[i].CoveragePercent > entries[j].CoveragePercent
+		// Sort a copy so the caller's lowest-first order survives printing
+		highest := append([]types.CoverageEntry{}, entries...)
+		sort.SliceStable(highest, func(i, j int) bool {
+			if highest[i].CoveragePercent != highest[j].CoveragePercent {
+				return highest[i].CoveragePercent > highest[j].CoveragePercent
+			}
+			return highest[i].Path < highest[j].Path
 		})
 
 		maxHighCoverage := 5
-		if len(entries) < maxHighCoverage {
-			maxHighCoverage = len(entries)
+		if len(highest) < maxHighCoverage {
+			maxHighCoverage = len(highest)
 		}
 
+		t := newTable(fileColumn, column{header: "Coverage", right: true})
 		for i := 0; i < maxHighCoverage; i++ {
-			entry := entries[i]
-			if entry.CoveragePercent < 80 {
+			entry := highest[i]
+			if entry.CoveragePercent < threshold {
 				continue
 			}
-
-			path := cellStyle.Render(entry.Path)
-			coverageStr := cellStyle.Render(fmt.Sprintf("%.1f%%", entry.CoveragePercent))
-
-			fmt.Printf("     %s – %s\n", path, coverageStr)
+			t.row(p.pathCell(p.cellStyle, entry.Path), cell(p.cellStyle, fmt.Sprintf("%.1f%%", entry.CoveragePercent)))
 		}
+		p.printTable(t)
 	}
 
 	if overallCoverage > 0 {
-		fmt.Printf("\n  %s Overall Coverage: %s (%d/%d lines covered)\n",
-			cellStyle.Render("📊"),
-			cellStyle.Render(fmt.Sprintf("%.1f%%", overallCoverage)),
-			(int)(overallCoverage/100*float64(entries[0].LinesTotal)), entries[0].LinesTotal)
+		overallStyle := p.cellStyle
+		if overallCoverage < threshold {
+			overallStyle = p.errorStyle
+		}
+		fmt.Fprintf(p.w, "\n  %s Overall Coverage: %s (%s/%s lines covered)\n",
+			p.cellStyle.Render("📊"),
+			overallStyle.Render(fmt.Sprintf("%.1f%%", overallCoverage)),
+			p.formatCount((int)(overallCoverage/100*float64(entries[0].LinesTotal))), p.formatCount(entries[0].LinesTotal))
 	}
 }
 
-func PrintSpellCheckLeaderboard(entries []types.SpellCheckEntry, authorStats map[string]*types.SpellCheckAuthorStats, topN int) {
-	fmt.Println(titleStyle.Render("Spell Check Leaderboard - Files with Most Spelling Errors"))
+func (p *Printer) PrintSpellCheckLeaderboard(entries []types.SpellCheckEntry, authorStats map[string]*types.SpellCheckAuthorStats, topN int) {
+	fmt.Fprintln(p.w, p.titleStyle.Render("Spell Check Leaderboard - Files with Most Spelling Errors"))
 
 	if len(entries) == 0 {
-		fmt.Println(cellStyle.Render("🎉 No spelling issues found or no text files to analyze"))
+		fmt.Fprintln(p.w, p.cellStyle.Render("🎉 No spelling issues found or no text files to analyze"))
 		return
 	}
 
-	printSpellCheckFileLeaderboard(entries, topN)
-	printSpellCheckAuthorLeaderboard(authorStats, topN)
+	p.printSpellCheckFileLeaderboard(entries, topN)
+	p.printSpellCheckAuthorLeaderboard(authorStats, topN)
 }
 
-func printSpellCheckFileLeaderboard(entries []types.SpellCheckEntry, topN int) {
-	fmt.Println(titleStyle.Render("Files with Most Spelling Errors"))
+func (p *Printer) printSpellCheckFileLeaderboard(entries []types.SpellCheckEntry, topN int) {
+	fmt.Fprintln(p.w, p.titleStyle.Render("Files with Most Spelling Errors"))
 
-	maxEntries := topN
-	if len(entries) < maxEntries {
-		maxEntries = len(entries)
-	}
+	maxEntries := p.limit(topN, len(entries))
 
+	t := newTable(rankColumn, fileColumn, column{header: "Error Rate", right: true},
+		column{header: "Misspelled", right: true}, column{header: "Words", right: true}, column{header: "Top Misspellings"})
 	for i := 0; i < maxEntries; i++ {
 		entry := entries[i]
-		rank := rankStyle.Render(fmt.Sprintf("%2d", i+1))
-		path := cellStyle.Render(entry.Path)
 
 		var errorColor lipgloss.Style
 		if entry.ErrorRate > 10 {
-			errorColor = errorStyle
+			errorColor = p.errorStyle
 		} else if entry.ErrorRate > 5 {
-			errorColor = warningStyle
+			errorColor = p.warningStyle
 		} else {
-			errorColor = cellStyle
+			errorColor = p.cellStyle
 		}
 
 		var topMisspellings []string
@@ -879,8 +1637,11 @@ func printSpellCheckFileLeaderboard(entries []types.SpellCheckEntry, topN int) {
 		for word, count := range entry.TopMisspellings {
 			wordCounts = append(wordCounts, wordCount{word, count})
 		}
-		sort.Slice(wordCounts, func(i, j int) bool {
-			return wordCounts[i].count > wordCounts[j].count
+		sort.SliceStable(wordCounts, func(i, j int) bool {
+			if wordCounts[i].count != wordCounts[j].count {
+				return wordCounts[i].count > wordCounts[j].count
+			}
+			return wordCounts[i].word < wordCounts[j].word
 		})
 
 		for i, wc := range wordCounts {
@@ -890,21 +1651,21 @@ func printSpellCheckFileLeaderboard(entries []types.SpellCheckEntry, topN int) {
 			topMisspellings = append(topMisspellings, fmt.Sprintf("%s(%d)", wc.word, wc.count))
 		}
 
-		misspellingsStr := ""
-		if len(topMisspellings) > 0 {
-			misspellingsStr = fmt.Sprintf(" [%s]", strings.Join(topMisspellings, ", "))
-		}
-
-		fmt.Printf("    %s. %s – %s error rate (%d/%d words)%s\n",
-			rank, path, errorColor.Render(fmt.Sprintf("%.1f%%", entry.ErrorRate)),
-			entry.MisspelledWords, entry.TotalWords,
-			emailStyle.Render(misspellingsStr))
+		t.row(
+			cell(p.rankStyle, fmt.Sprintf("%d", i+1)),
+			p.filePath(entry.Path, entry.Untracked),
+			cell(errorColor, fmt.Sprintf("%.1f%%", entry.ErrorRate)),
+			p.count(entry.MisspelledWords),
+			p.count(entry.TotalWords),
+			cell(p.emailStyle, strings.Join(topMisspellings, ", ")),
+		)
 	}
+	p.printTable(t)
 
 	if len(entries) > 0 && len(entries[0].Issues) > 0 {
-		fmt.Printf("\n  %s Examples from %s:\n",
-			warningStyle.Render("🔍"),
-			cellStyle.Render(entries[0].Path))
+		fmt.Fprintf(p.w, "\n  %s Examples from %s:\n",
+			p.warningStyle.Render("🔍"),
+			p.cellStyle.Render(entries[0].Path))
 
 		maxExamples := 5
 		for i, issue := range entries[0].Issues {
@@ -919,26 +1680,28 @@ func printSpellCheckFileLeaderboard(entries []types.SpellCheckEntry, topN int) {
 
 			authorStr := ""
 			if issue.Author != "unknown" {
-				authorStr = fmt.Sprintf(" (by %s)", nameStyle.Render(issue.Author))
+				authorStr = fmt.Sprintf(" (by %s)", p.nameStyle.Render(issue.Author))
 			}
 
-			fmt.Printf("    Line %d: '%s' in %s%s%s\n",
+			fmt.Fprintf(p.w, "    Line %d: '%s' in %s%s%s\n",
 				issue.Line,
-				errorStyle.Render(issue.Word),
+				p.errorStyle.Render(issue.Word),
 				issue.Type,
 				authorStr,
-				cellStyle.Render(suggestionStr))
+				p.cellStyle.Render(suggestionStr))
 		}
 	}
 }
 
-func printSpellCheckAuthorLeaderboard(authorStats map[string]*types.SpellCheckAuthorStats, topN int) {
-	fmt.Println(titleStyle.Render("Authors with Most Spelling Errors"))
+func (p *Printer) printSpellCheckAuthorLeaderboard(authorStats map[string]*types.SpellCheckAuthorStats, topN int) {
+	fmt.Fprintln(p.w, p.titleStyle.Render("Authors with Most Spelling Errors"))
 
 	type authorEntry struct {
 		Name            string
 		Email           string
 		TotalErrors     int
+		TotalWords      int
+		Rate            float64
 		Files           int
 		TopMistake      string
 		TopMistakeCount int
@@ -949,7 +1712,7 @@ func printSpellCheckAuthorLeaderboard(authorStats map[string]*types.SpellCheckAu
 		var topMistake string
 		var topCount int
 		for mistake, count := range stats.CommonMistakes {
-			if count > topCount {
+			if count > topCount || (count == topCount && mistake < topMistake) {
 				topMistake = mistake
 				topCount = count
 			}
@@ -959,34 +1722,56 @@ func printSpellCheckAuthorLeaderboard(authorStats map[string]*types.SpellCheckAu
 			Name:            stats.Name,
 			Email:           stats.Email,
 			TotalErrors:     stats.TotalErrors,
+			TotalWords:      stats.TotalWords,
+			Rate:            spellingRate(stats),
 			Files:           len(stats.Files),
 			TopMistake:      topMistake,
 			TopMistakeCount: topCount,
 		})
 	}
 
-	sort.Slice(entries, func(i, j int) bool {
-		return entries[i].TotalErrors > entries[j].TotalErrors
+	// Authors who write more prose make more mistakes, so the rate ranks
+	// them by how carefully they write instead
+	sort.SliceStable(entries, func(i, j int) bool {
+		if p.spellCheckSort == SpellCheckSortRate && entries[i].Rate != entries[j].Rate {
+			return entries[i].Rate > entries[j].Rate
+		}
+		if entries[i].TotalErrors != entries[j].TotalErrors {
+			return entries[i].TotalErrors > entries[j].TotalErrors
+		}
+		if entries[i].Name != entries[j].Name {
+			return entries[i].Name < entries[j].Name
+		}
+		return entries[i].Email < entries[j].Email
 	})
 
-	maxEntries := topN
-	if len(entries) < maxEntries {
-		maxEntries = len(entries)
-	}
+	maxEntries := p.limit(topN, len(entries))
 
+	t := newTable(rankColumn, column{header: "Author"}, column{header: "Email"},
+		column{header: "Errors", right: true}, column{header: "Per 1k Words", right: true}, column{header: "Files", right: true}, column{header: "Top Mistake"})
 	for i := 0; i < maxEntries; i++ {
 		entry := entries[i]
-		rank := rankStyle.Render(fmt.Sprintf("%2d", i+1))
-		name := nameStyle.Render(entry.Name)
-		email := emailStyle.Render(fmt.Sprintf("(%s)", entry.Email))
 
-		topMistakeStr := ""
+		// Reports saved before authors had word counts have no rate
+		rate := "-"
+		if entry.TotalWords > 0 {
+			rate = fmt.Sprintf("%.1f", entry.Rate)
+		}
+
+		topMistake := ""
 		if entry.TopMistake != "" {
-			topMistakeStr = fmt.Sprintf(", top mistake: %s(%d)",
-				topRuleStyle.Render(entry.TopMistake), entry.TopMistakeCount)
+			topMistake = fmt.Sprintf("%s (%s)", cell(p.topRuleStyle, entry.TopMistake), p.formatCount(entry.TopMistakeCount))
 		}
 
-		fmt.Printf("    %s. %s %s – %d errors in %d files%s\n",
-			rank, name, email, entry.TotalErrors, entry.Files, topMistakeStr)
-	}
-}
\ No newline at end of file
+		t.row(
+			cell(p.rankStyle, fmt.Sprintf("%d", i+1)),
+			cell(p.nameStyle, entry.Name),
+			cell(p.emailStyle, entry.Email),
+			p.count(entry.TotalErrors),
+			cell(p.cellStyle, rate),
+			p.count(entry.Files),
+			topMistake,
+		)
+	}
+	p.printTable(t)
+}
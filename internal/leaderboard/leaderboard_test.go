@@ -1,9 +1,22 @@
 package leaderboard
 
 import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
-	"codecompass/internal/types"
+	"github.com/xeon-zolt/codecompass/internal/config"
+	"github.com/xeon-zolt/codecompass/internal/git"
+	"github.com/xeon-zolt/codecompass/internal/logging"
+	"github.com/xeon-zolt/codecompass/internal/testutil"
+	"github.com/xeon-zolt/codecompass/internal/types"
+	"github.com/xeon-zolt/codecompass/internal/utils"
 )
 
 func TestGenerateAuthorLeaderboard(t *testing.T) {
@@ -15,7 +28,7 @@ func TestGenerateAuthorLeaderboard(t *testing.T) {
 		},
 	}
 
-	entries := GenerateAuthorLeaderboard(authorStats, 10)
+	entries := GenerateAuthorLeaderboard(authorStats, 10, nil)
 
 	if len(entries) != 1 {
 		t.Fatalf("Expected 1 entry, but got %d", len(entries))
@@ -44,7 +57,7 @@ func TestGenerateFileLeaderboard(t *testing.T) {
 		},
 	}
 
-	entries := GenerateFileLeaderboard(fileStats, 10)
+	entries := GenerateFileLeaderboard(fileStats, 10, FileSortIssues, nil)
 
 	if len(entries) != 1 {
 		t.Fatalf("Expected 1 entry, but got %d", len(entries))
@@ -64,6 +77,141 @@ func TestGenerateFileLeaderboard(t *testing.T) {
 	}
 }
 
+func TestGenerateFileLeaderboardSortsBySeverity(t *testing.T) {
+	fileStats := map[string]*types.FileStats{
+		"warnings.js": {Path: "warnings.js", Count: 200, Warnings: 200},
+		"errors.js":   {Path: "errors.js", Count: 50, Errors: 50},
+		"mixed.js":    {Path: "mixed.js", Count: 120, Errors: 50, Warnings: 70},
+	}
+
+	for _, tt := range []struct {
+		sortBy   FileSort
+		expected []string
+	}{
+		{FileSortIssues, []string{"warnings.js", "mixed.js", "errors.js"}},
+		// Ties in errors fall back to the most issues
+		{FileSortErrors, []string{"mixed.js", "errors.js", "warnings.js"}},
+		{FileSortWarnings, []string{"warnings.js", "mixed.js", "errors.js"}},
+		{"", []string{"warnings.js", "mixed.js", "errors.js"}},
+	} {
+		var paths []string
+		for _, entry := range GenerateFileLeaderboard(fileStats, 0, tt.sortBy, nil) {
+			paths = append(paths, entry.Path)
+		}
+		if !reflect.DeepEqual(paths, tt.expected) {
+			t.Errorf("Sorting by %q: expected %v, but got %v", tt.sortBy, tt.expected, paths)
+		}
+	}
+
+	entries := GenerateFileLeaderboard(fileStats, 0, FileSortIssues, nil)
+	if entries[1].Errors != 50 || entries[1].Warnings != 70 {
+		t.Errorf("Expected the errors and warnings of mixed.js, but got %+v", entries[1])
+	}
+
+	if _, err := ParseFileSort("authors"); err == nil {
+		t.Error("Expected an error for an unknown file sort column")
+	}
+}
+
+func TestWeightedRankings(t *testing.T) {
+	// 300 trivial warnings against 40 errors, 10 of them security ones
+	authorStats := map[string]*types.AuthorStats{
+		"noisy@example.com": {Name: "Noisy", Count: 300, Warnings: 300, Rules: map[string]int{"no-console": 300}},
+		"risky@example.com": {Name: "Risky", Count: 40, Errors: 40,
+			Rules: map[string]int{"eqeqeq": 30, "security/detect-eval": 10}, RuleErrors: map[string]int{"eqeqeq": 30, "security/detect-eval": 10}},
+		"mixed@example.com": {Name: "Mixed", Count: 100, Errors: 20, Warnings: 80,
+			Rules: map[string]int{"eqeqeq": 20, "no-console": 80}, RuleErrors: map[string]int{"eqeqeq": 20}},
+	}
+	fileStats := make(map[string]*types.FileStats)
+	for email, stats := range authorStats {
+		path := strings.Split(email, "@")[0] + ".js"
+		fileStats[path] = &types.FileStats{Path: path, Count: stats.Count, Errors: stats.Errors, Warnings: stats.Warnings, Rules: stats.Rules, RuleErrors: stats.RuleErrors}
+	}
+
+	tests := []struct {
+		name     string
+		weights  *config.IssueWeights
+		expected []string
+		scores   []float64
+	}{
+		{"unweighted", nil, []string{"noisy", "mixed", "risky"}, []float64{0, 0, 0}},
+		{"errors outweigh warnings", &config.IssueWeights{Error: 10, Warning: 1},
+			[]string{"risky", "noisy", "mixed"}, []float64{400, 300, 280}},
+		{"equal weights rank like counts", &config.IssueWeights{Error: 1, Warning: 1},
+			[]string{"noisy", "mixed", "risky"}, []float64{300, 100, 40}},
+		{"rule weights win over severity", &config.IssueWeights{Error: 5, Warning: 1, Rules: []config.RuleWeight{{Pattern: "no-console", Weight: 0}, {Pattern: "security/*", Weight: 50}}},
+			[]string{"risky", "mixed", "noisy"}, []float64{650, 100, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authors := GenerateAuthorLeaderboard(authorStats, 0, tt.weights)
+			files := GenerateFileLeaderboard(fileStats, 0, FileSortIssues, tt.weights)
+			for i, name := range tt.expected {
+				if authors[i].Name != strings.ToUpper(name[:1])+name[1:] || authors[i].WeightedScore != tt.scores[i] {
+					t.Errorf("Expected author %d to be %s weighing %v, but got %+v", i, name, tt.scores[i], authors[i])
+				}
+				if files[i].Path != name+".js" || files[i].WeightedScore != tt.scores[i] {
+					t.Errorf("Expected file %d to be %s.js weighing %v, but got %+v", i, name, tt.scores[i], files[i])
+				}
+			}
+			// The raw split is kept whatever the weights
+			if authors[0].Errors+authors[0].Warnings != authors[0].Count {
+				t.Errorf("Expected the raw errors and warnings to add up to the issues, but got %+v", authors[0])
+			}
+
+			summary := GenerateSummaryStats(authorStats, fileStats, nil, tt.weights)
+			if total := tt.scores[0] + tt.scores[1] + tt.scores[2]; summary.WeightedIssues != total || summary.TotalIssues != 440 {
+				t.Errorf("Expected 440 issues weighing %v, but got %+v", total, summary)
+			}
+		})
+	}
+
+	// Sorting by another column ignores the weights
+	files := GenerateFileLeaderboard(fileStats, 0, FileSortWarnings, &config.IssueWeights{Error: 10, Warning: 1})
+	if files[0].Path != "noisy.js" {
+		t.Errorf("Expected the file with the most warnings first, but got %+v", files[0])
+	}
+}
+
+func TestGenerateFileLeaderboardTopAuthors(t *testing.T) {
+	fileStats := map[string]*types.FileStats{
+		"app.js": {
+			Path:    "app.js",
+			Count:   10,
+			Rules:   map[string]int{"no-console": 10},
+			Authors: map[string]int{"bob@example.com": 2, "alice@example.com": 6, "carol@example.com": 1, "dave@example.com": 1},
+		},
+		"util.js": {
+			Path:    "util.js",
+			Count:   3,
+			Rules:   map[string]int{"eqeqeq": 3},
+			Authors: map[string]int{"bob@example.com": 3},
+		},
+	}
+
+	entries := GenerateFileLeaderboard(fileStats, 10, FileSortIssues, nil)
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, but got %d", len(entries))
+	}
+
+	expected := []types.AuthorCount{
+		{Email: "alice@example.com", Count: 6},
+		{Email: "bob@example.com", Count: 2},
+		{Email: "carol@example.com", Count: 1},
+	}
+	if !reflect.DeepEqual(entries[0].TopAuthors, expected) {
+		t.Errorf("Expected top authors %+v for app.js, but got %+v", expected, entries[0].TopAuthors)
+	}
+
+	if entries[0].Authors != 4 {
+		t.Errorf("Expected 4 distinct authors for app.js, but got %d", entries[0].Authors)
+	}
+
+	if len(entries[1].TopAuthors) != 1 || entries[1].TopAuthors[0].Email != "bob@example.com" {
+		t.Errorf("Expected bob to be the top author of util.js, but got %+v", entries[1].TopAuthors)
+	}
+}
+
 func TestGenerateRuleLeaderboard(t *testing.T) {
 	ruleStats := map[string]*types.RuleStats{
 		"no-console": {
@@ -87,3 +235,406 @@ func TestGenerateRuleLeaderboard(t *testing.T) {
 		t.Errorf("Expected count to be 10, but got %d", entry.Count)
 	}
 }
+
+func TestGenerateRuleAuthorMatrix(t *testing.T) {
+	authorStats := map[string]*types.AuthorStats{
+		"alice@example.com": {Name: "Alice", Count: 12, Rules: map[string]int{"no-floating-promises": 9, "no-console": 3}},
+		"bob@example.com":   {Name: "Bob", Count: 6, Rules: map[string]int{"no-console": 6}},
+		"carol@example.com": {Name: "Carol", Count: 3, Rules: map[string]int{"eqeqeq": 3, "semi": 0}},
+	}
+
+	cells := GenerateRuleAuthorMatrix(authorStats)
+	expected := []types.RuleAuthorCell{
+		{Rule: "no-floating-promises", Name: "Alice", Email: "alice@example.com", Count: 9},
+		{Rule: "no-console", Name: "Bob", Email: "bob@example.com", Count: 6},
+		{Rule: "eqeqeq", Name: "Carol", Email: "carol@example.com", Count: 3},
+		{Rule: "no-console", Name: "Alice", Email: "alice@example.com", Count: 3},
+	}
+	if !reflect.DeepEqual(cells, expected) {
+		t.Fatalf("Expected the non-empty cells sorted by count\ngot:  %+v\nwant: %+v", cells, expected)
+	}
+
+	// no-console totals 9 and ties no-floating-promises, broken by rule name
+	var byRule []string
+	for _, top := range topAuthorPerRule(cells) {
+		byRule = append(byRule, fmt.Sprintf("%s:%s:%d/%d", top.Rule, top.Name, top.Count, top.Total))
+	}
+	if expected := []string{"no-console:Bob:6/9", "no-floating-promises:Alice:9/9", "eqeqeq:Carol:3/3"}; !reflect.DeepEqual(byRule, expected) {
+		t.Errorf("Expected top authors per rule %v, got %v", expected, byRule)
+	}
+
+	var byAuthor []string
+	for _, top := range topRulePerAuthor(cells) {
+		byAuthor = append(byAuthor, fmt.Sprintf("%s:%s:%d/%d", top.Name, top.Rule, top.Count, top.Total))
+	}
+	if expected := []string{"Alice:no-floating-promises:9/12", "Bob:no-console:6/6", "Carol:eqeqeq:3/3"}; !reflect.DeepEqual(byAuthor, expected) {
+		t.Errorf("Expected top rules per author %v, got %v", expected, byAuthor)
+	}
+}
+
+func TestGeneratorsAreDeterministic(t *testing.T) {
+	// Every entry ties on the primary key, so only the tie-breaks decide order
+	authorStats := make(map[string]*types.AuthorStats)
+	fileStats := make(map[string]*types.FileStats)
+	ruleStats := make(map[string]*types.RuleStats)
+	trackedFiles := make(map[string]bool)
+	dir := t.TempDir()
+
+	for i := 0; i < 20; i++ {
+		name := fmt.Sprintf("file%02d.js", i)
+		authorStats[fmt.Sprintf("dev%02d@example.com", i)] = &types.AuthorStats{
+			Name:  "Dev",
+			Count: 3,
+			Rules: map[string]int{"no-console": 1, "eqeqeq": 1, "semi": 1},
+		}
+		fileStats[name] = &types.FileStats{Path: name, Count: 3, Rules: map[string]int{"semi": 3}}
+		ruleStats[fmt.Sprintf("rule-%02d", i)] = &types.RuleStats{Rule: fmt.Sprintf("rule-%02d", i), Count: 3}
+
+		trackedFiles[name] = true
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("// TODO: one\n// FIXME: two\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	generators := map[string]func() interface{}{
+		"authors": func() interface{} { return GenerateAuthorLeaderboard(authorStats, 0, nil) },
+		"files":   func() interface{} { return GenerateFileLeaderboard(fileStats, 0, FileSortIssues, nil) },
+		"rules":   func() interface{} { return GenerateRuleLeaderboard(ruleStats, 0) },
+		"loc": func() interface{} {
+			return GenerateLinesOfCodeLeaderboard(context.Background(), dir, trackedFiles, 0, nil)
+		},
+		"debt": func() interface{} {
+			entries, _ := GenerateTechnicalDebtLeaderboard(dir, trackedFiles, 1024, nil, utils.NewWarningCollector(), 0, nil)
+			return entries
+		},
+	}
+
+	for name, generate := range generators {
+		first := fmt.Sprintf("%+v", generate())
+		for run := 0; run < 5; run++ {
+			if again := fmt.Sprintf("%+v", generate()); again != first {
+				t.Errorf("%s leaderboard order changed between runs:\n%s\n%s", name, first, again)
+				break
+			}
+		}
+	}
+
+	authors := GenerateAuthorLeaderboard(authorStats, 0, nil)
+	if authors[0].Email != "dev00@example.com" || authors[0].TopRule != "eqeqeq" {
+		t.Errorf("Expected ties broken by email and top rule name, but got %+v", authors[0])
+	}
+
+	files := GenerateLinesOfCodeLeaderboard(context.Background(), dir, trackedFiles, 0, nil)
+	if files[0].Path != "file00.js" || files[len(files)-1].Path != "file19.js" {
+		t.Errorf("Expected ties broken by path, but got %s first and %s last", files[0].Path, files[len(files)-1].Path)
+	}
+}
+
+func TestFileScansReportProgress(t *testing.T) {
+	dir := t.TempDir()
+	trackedFiles := make(map[string]bool)
+	for _, name := range []string{"a.js", "b.js", "logo.png"} {
+		trackedFiles[name] = true
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("// TODO: one\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	scans := map[string]func(progress utils.ProgressFunc){
+		"loc": func(progress utils.ProgressFunc) {
+			GenerateLinesOfCodeLeaderboard(context.Background(), dir, trackedFiles, 0, progress)
+		},
+		"debt": func(progress utils.ProgressFunc) {
+			GenerateTechnicalDebtLeaderboard(dir, trackedFiles, 1024, nil, utils.NewWarningCollector(), 0, progress)
+		},
+	}
+	for name, scan := range scans {
+		var updates [][2]int
+		scan(func(done, total int) {
+			updates = append(updates, [2]int{done, total})
+		})
+
+		// Skipped files count as done too
+		if len(updates) == 0 || updates[0] != [2]int{0, 3} || updates[len(updates)-1] != [2]int{3, 3} {
+			t.Errorf("Expected %s to report from 0 to 3 of 3 files, but got %v", name, updates)
+		}
+	}
+}
+
+func TestGenerateTechnicalDebtLeaderboardLongLines(t *testing.T) {
+	dir := t.TempDir()
+	// Minified code, with a line past the 64 KB default of bufio.Scanner
+	content := "// TODO: before\n" + strings.Repeat("x", 100*1024) + "\n// FIXME: after\n"
+	if err := os.WriteFile(filepath.Join(dir, "bundle.min.js"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	trackedFiles := map[string]bool{"bundle.min.js": true}
+
+	warnings := utils.NewWarningCollector()
+	entries, err := GenerateTechnicalDebtLeaderboard(dir, trackedFiles, 1024, nil, warnings, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].TodoCount != 1 || entries[0].FixmeCount != 1 {
+		t.Errorf("Expected the markers on both sides of the long line, got %+v", entries)
+	}
+	if len(warnings.Warnings()) != 0 {
+		t.Errorf("Expected no warnings, got %v", warnings.Warnings())
+	}
+
+	// Past max-line-size the file is skipped with a warning
+	warnings = utils.NewWarningCollector()
+	entries, err = GenerateTechnicalDebtLeaderboard(dir, trackedFiles, 64, nil, warnings, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("Expected the file to be skipped, got %+v", entries)
+	}
+	if w := warnings.Warnings(); len(w) != 1 || !strings.Contains(w[0].String(), "bundle.min.js") || !strings.Contains(w[0].String(), "max-line-size") {
+		t.Errorf("Expected a warning naming the file and max-line-size, got %v", w)
+	}
+}
+
+func TestGitGeneratorsAreDeterministic(t *testing.T) {
+	// Every author and file ties, so only the tie-breaks decide order
+	repo := testutil.NewRepo(t).At(time.Now().Add(-48 * time.Hour))
+	files := make(map[string]string)
+	trackedFiles := make(map[string]bool)
+	var lcov strings.Builder
+	for i := 0; i < 8; i++ {
+		name := fmt.Sprintf("file%02d.js", i)
+		files[name] = "// teh recieve\n"
+		trackedFiles[name] = true
+		fmt.Fprintf(&lcov, "SF:%s\nLF:4\nLH:2\nend_of_record\n", name)
+	}
+	files["lcov.info"] = lcov.String()
+	repo.Commit("initial commit", files)
+
+	// Bug density needs at least five commits per file
+	for i := 0; i < 8; i++ {
+		for name := range trackedFiles {
+			files[name] = fmt.Sprintf("// teh recieve\n// fix %d\n", i)
+		}
+		repo.WithAuthor(fmt.Sprintf("Dev %02d", i), fmt.Sprintf("dev%02d@example.com", i)).
+			Commit("fix typo", files)
+	}
+	dir := repo.Dir()
+
+	ctx := context.Background()
+	blamer := git.NewBlamer(dir, utils.NewSemaphore(4), logging.Discard(), utils.NewWarningCollector())
+	generators := map[string]func() interface{}{
+		"commits": func() interface{} {
+			entries, _ := GenerateCommitCountLeaderboard(ctx, dir, git.AuthorDate, git.FullCredit, 0)
+			return entries
+		},
+		"recent": func() interface{} {
+			entries, _ := GenerateRecentContributorsLeaderboard(ctx, dir, git.AuthorDate, 0)
+			return entries
+		},
+		"churn": func() interface{} {
+			entries, _ := GenerateCodeChurnLeaderboard(ctx, dir, trackedFiles, git.AuthorDate, time.Now(), 0, ChurnSortChanges)
+			return entries
+		},
+		"bugs": func() interface{} {
+			entries, _ := GenerateBugDensityLeaderboard(ctx, dir, trackedFiles, 0)
+			return entries
+		},
+		"coverage": func() interface{} {
+			entries, _, _, _ := GenerateCodeCoverageLeaderboard(dir, trackedFiles, "", time.Now(), 0)
+			return entries
+		},
+		"spellcheck": func() interface{} {
+			entries, _, _ := GenerateSpellCheckLeaderboard(ctx, dir, trackedFiles, config.NewConfig(), blamer, utils.NewWarningCollector(), 0, nil)
+			return entries
+		},
+	}
+
+	for name, generate := range generators {
+		first := fmt.Sprintf("%+v", generate())
+		if first == "[]" {
+			t.Errorf("%s leaderboard is empty, so its order is not tested", name)
+			continue
+		}
+		for run := 0; run < 5; run++ {
+			if again := fmt.Sprintf("%+v", generate()); again != first {
+				t.Errorf("%s leaderboard order changed between runs:\n%s\n%s", name, first, again)
+				break
+			}
+		}
+	}
+}
+
+func TestGenerateCodeChurnLeaderboard(t *testing.T) {
+	dir := testutil.NewRepo(t).
+		WithAuthor("Alice", "alice@example.com").
+		Commit("add files", map[string]string{"a.js": "1\n2\n3\n", "b.js": "x\n", "untracked.js": "z\n"}).
+		WithAuthor("Bob", "bob@example.com").
+		Commit("grow a", map[string]string{"a.js": "1\n2\n3\n4\n"}).
+		Branch("feature").
+		Commit("change b", map[string]string{"b.js": "y\n"}).
+		Checkout("main").
+		Commit("shrink a", map[string]string{"a.js": "1\n"}).
+		Merge("feature", "merge feature").
+		Dir()
+
+	entries, err := GenerateCodeChurnLeaderboard(context.Background(), dir, map[string]bool{"a.js": true, "b.js": true}, git.AuthorDate, time.Now(), 0, ChurnSortChanges)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("Expected churn for the 2 tracked files, but got %+v", entries)
+	}
+
+	// The merge commit adds no changes of its own
+	a, b := entries[0], entries[1]
+	if a.Path != "a.js" || a.Changes != 3 || a.AddedLines != 4 || a.DeletedLines != 3 || a.NetLines != 1 {
+		t.Errorf("Unexpected churn for a.js: %+v", a)
+	}
+	if b.Path != "b.js" || b.Changes != 2 || b.AddedLines != 2 || b.DeletedLines != 1 {
+		t.Errorf("Unexpected churn for b.js: %+v", b)
+	}
+}
+
+func TestGenerateCodeChurnLeaderboardRate(t *testing.T) {
+	now := time.Now()
+	old := now.AddDate(-3, 0, 0).Truncate(time.Second)
+	repo := testutil.NewRepo(t).At(old).
+		Commit("add old files", map[string]string{"old.js": "0\n", "moved.js": "m\n"})
+	for i := 1; i < 8; i++ {
+		repo.Commit("change old", map[string]string{"old.js": fmt.Sprintf("%d\n", i)})
+	}
+
+	// A young file changed often, and a renamed file keeping its age
+	repo.At(now.AddDate(0, -2, 0)).Commit("add new", map[string]string{"new.js": "0\n"})
+	for i := 1; i < 5; i++ {
+		repo.Commit("change new", map[string]string{"new.js": fmt.Sprintf("%d\n", i)})
+	}
+	repo.Git("mv", "moved.js", "renamed.js")
+	repo.Commit("rename", nil).Commit("change renamed", map[string]string{"renamed.js": "n\n"})
+	dir := repo.Dir()
+	tracked := map[string]bool{"old.js": true, "new.js": true, "renamed.js": true}
+
+	byChanges, err := GenerateCodeChurnLeaderboard(context.Background(), dir, tracked, git.AuthorDate, now, 0, ChurnSortChanges)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(byChanges) != 3 || byChanges[0].Path != "old.js" || byChanges[1].Path != "new.js" {
+		t.Fatalf("Expected old.js, then new.js by changes, but got %+v", byChanges)
+	}
+
+	byRate, err := GenerateCodeChurnLeaderboard(context.Background(), dir, tracked, git.AuthorDate, now, 0, ChurnSortRate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if byRate[0].Path != "new.js" || byRate[1].Path != "old.js" {
+		t.Fatalf("Expected new.js, then old.js by rate, but got %+v", byRate)
+	}
+	if rate := byRate[0].ChurnRate; math.Abs(rate-2.5) > 0.1 {
+		t.Errorf("Expected new.js to change about 2.5 times a month, got %.2f", rate)
+	}
+	if rate := byRate[1].ChurnRate; math.Abs(rate-8.0/36) > 0.01 {
+		t.Errorf("Expected old.js to change about 0.22 times a month, got %.2f", rate)
+	}
+	if renamed := byRate[2]; renamed.Path != "renamed.js" || !renamed.FirstCommit.Equal(old) {
+		t.Errorf("Expected renamed.js to date from %v, its add under the old name, but got %+v", old, renamed)
+	}
+}
+
+func TestGenerateBugDensityLeaderboard(t *testing.T) {
+	repo := testutil.NewRepo(t).
+		Commit("initial import", map[string]string{"a.js": "0", "b.js": "0", "c.js": "0"})
+	for _, commit := range []struct {
+		msg   string
+		files []string
+	}{
+		{"fix crash", []string{"a.js"}},
+		{"tweak one", []string{"a.js", "b.js"}},
+		{"fix overflow", []string{"a.js"}},
+		{"tweak two", []string{"b.js"}},
+		{"tweak three", []string{"a.js", "b.js"}},
+		{"bug in parser", []string{"b.js"}},
+	} {
+		files := make(map[string]string)
+		for _, file := range commit.files {
+			files[file] = commit.msg
+		}
+		repo.Commit(commit.msg, files)
+	}
+
+	tracked := map[string]bool{"a.js": true, "b.js": true, "c.js": true}
+	entries, err := GenerateBugDensityLeaderboard(context.Background(), repo.Dir(), tracked, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// c.js has too few commits to be ranked; the initial import counts
+	// towards the others
+	if len(entries) != 2 {
+		t.Fatalf("Expected a.js and b.js to be ranked, but got %+v", entries)
+	}
+
+	a, b := entries[0], entries[1]
+	if a.Path != "a.js" || a.TotalCommits != 5 || a.BugFixes != 2 || a.BugRatio != 40 {
+		t.Errorf("Unexpected bug density for a.js: %+v", a)
+	}
+	if b.Path != "b.js" || b.TotalCommits != 5 || b.BugFixes != 1 || b.BugRatio != 20 {
+		t.Errorf("Unexpected bug density for b.js: %+v", b)
+	}
+}
+
+func TestGenerateRuleGroupLeaderboard(t *testing.T) {
+	ruleStats := map[string]*types.RuleStats{
+		"no-undef":                    {Rule: "no-undef", Count: 4},
+		"react-hooks/exhaustive-deps": {Rule: "react-hooks/exhaustive-deps", Count: 2},
+		"indent":                      {Rule: "indent", Count: 9},
+		"no-console":                  {Rule: "no-console", Count: 1},
+	}
+	cfg := config.NewConfig()
+	cfg.RuleGroups = []config.RuleGroup{
+		{Name: "correctness", Patterns: []string{"no-undef", "react-hooks/*"}},
+		{Name: "style", Patterns: []string{"indent", "quotes"}},
+		// A rule counts towards the first group that matches it
+		{Name: "react", Patterns: []string{"react-hooks/*"}},
+	}
+
+	entries := GenerateRuleGroupLeaderboard(ruleStats, cfg)
+
+	expected := []types.RuleGroupEntry{
+		{Rank: 1, Group: "style", Count: 9, DistinctRules: 1},
+		{Rank: 2, Group: "correctness", Count: 6, DistinctRules: 2},
+		{Rank: 3, Group: UngroupedRules, Count: 1, DistinctRules: 1},
+	}
+	if !reflect.DeepEqual(entries, expected) {
+		t.Errorf("Expected %+v, but got %+v", expected, entries)
+	}
+}
+
+func TestGenerateRulePluginLeaderboard(t *testing.T) {
+	ruleStats := map[string]*types.RuleStats{
+		"@typescript-eslint/no-unused-vars":  {Rule: "@typescript-eslint/no-unused-vars", Count: 5},
+		"@typescript-eslint/no-explicit-any": {Rule: "@typescript-eslint/no-explicit-any", Count: 3},
+		"react/jsx-key":                      {Rule: "react/jsx-key", Count: 2},
+		"@next/next/no-img-element":          {Rule: "@next/next/no-img-element", Count: 2},
+		"no-console":                         {Rule: "no-console", Count: 4},
+		"eqeqeq":                             {Rule: "eqeqeq", Count: 1},
+	}
+
+	entries := GenerateRulePluginLeaderboard(ruleStats)
+
+	expected := []types.RulePluginEntry{
+		{Rank: 1, Plugin: "@typescript-eslint", Count: 8, DistinctRules: 2},
+		{Rank: 2, Plugin: "core", Count: 5, DistinctRules: 2},
+		{Rank: 3, Plugin: "@next/next", Count: 2, DistinctRules: 1},
+		{Rank: 4, Plugin: "react", Count: 2, DistinctRules: 1},
+	}
+	if len(entries) != len(expected) {
+		t.Fatalf("Expected %d plugins, but got %+v", len(expected), entries)
+	}
+	for i, entry := range entries {
+		if entry != expected[i] {
+			t.Errorf("Expected entry %d to be %+v, but got %+v", i, expected[i], entry)
+		}
+	}
+}
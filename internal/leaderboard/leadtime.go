@@ -0,0 +1,152 @@
+package leaderboard
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/xeon-zolt/codecompass/internal/git"
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+// GenerateLeadTimeStats measures the lead time and merge cadence of the
+// branches merged into HEAD between since and now. It only reads git
+// history, so no forge API token is needed.
+func GenerateLeadTimeStats(ctx context.Context, dir string, since, now time.Time) (*types.LeadTimeStats, error) {
+	merges, err := git.GetMainlineMerges(ctx, dir, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get merge commits: %w", err)
+	}
+
+	stats := LeadTimeStats(merges, since, now)
+	return &stats, nil
+}
+
+// LeadTimeStats summarizes merges made between since and now. Percentiles use
+// the nearest-rank method, and weeks start on Monday (UTC).
+func LeadTimeStats(merges []types.MergeInfo, since, now time.Time) types.LeadTimeStats {
+	stats := types.LeadTimeStats{Since: since}
+
+	type authorKey struct{ name, email string }
+	var leadTimes []time.Duration
+	authors := make(map[authorKey][]time.Duration)
+	weeks := make(map[time.Time]int)
+
+	for _, merge := range merges {
+		if merge.MergedAt.Before(since) || merge.MergedAt.After(now) {
+			continue
+		}
+		// Rebased or backdated branches can start after the merge
+		leadTime := merge.MergedAt.Sub(merge.FirstCommitAt)
+		if leadTime < 0 {
+			leadTime = 0
+		}
+
+		leadTimes = append(leadTimes, leadTime)
+		key := authorKey{merge.Author, merge.Email}
+		authors[key] = append(authors[key], leadTime)
+		weeks[weekStart(merge.MergedAt)]++
+	}
+
+	stats.Merges = len(leadTimes)
+	stats.Median = percentile(leadTimes, 50)
+	stats.P75 = percentile(leadTimes, 75)
+	stats.P90 = percentile(leadTimes, 90)
+
+	if window := now.Sub(since); window > 0 {
+		stats.MergesPerWeek = float64(stats.Merges) / (window.Hours() / (7 * 24))
+	}
+
+	// Weeks without merges are listed too so gaps in the cadence show
+	for week := weekStart(since); !week.After(now); week = week.AddDate(0, 0, 7) {
+		stats.Weeks = append(stats.Weeks, types.WeeklyMerges{Week: week, Merges: weeks[week]})
+	}
+
+	for key, durations := range authors {
+		stats.Authors = append(stats.Authors, types.LeadTimeAuthorEntry{
+			Name:   key.name,
+			Email:  key.email,
+			Merges: len(durations),
+			Median: percentile(durations, 50),
+			P90:    percentile(durations, 90),
+		})
+	}
+	sort.SliceStable(stats.Authors, func(i, j int) bool {
+		if stats.Authors[i].Merges != stats.Authors[j].Merges {
+			return stats.Authors[i].Merges > stats.Authors[j].Merges
+		}
+		if stats.Authors[i].Name != stats.Authors[j].Name {
+			return stats.Authors[i].Name < stats.Authors[j].Name
+		}
+		return stats.Authors[i].Email < stats.Authors[j].Email
+	})
+	for i := range stats.Authors {
+		stats.Authors[i].Rank = i + 1
+	}
+
+	return stats
+}
+
+func (p *Printer) PrintLeadTimeStats(stats types.LeadTimeStats, topN int) {
+	since := stats.Since.Format("2006-01-02")
+	fmt.Fprintln(p.w, p.titleStyle.Render(fmt.Sprintf("Lead Time Leaderboard - Branch Lead Time per Author (merged since %s)", since)))
+
+	if stats.Merges == 0 {
+		fmt.Fprintln(p.w, p.cellStyle.Render(fmt.Sprintf("📭 No branches merged since %s", since)))
+		return
+	}
+
+	fmt.Fprintf(p.w, "🔀 %s merges, %s per week – median %s, p75 %s, p90 %s\n",
+		p.cellStyle.Render(p.formatCount(stats.Merges)), p.cellStyle.Render(fmt.Sprintf("%.1f", stats.MergesPerWeek)),
+		formatMergeTime(stats.Median), formatMergeTime(stats.P75), formatMergeTime(stats.P90))
+
+	maxEntries := p.limit(topN, len(stats.Authors))
+
+	t := newTable(rankColumn, column{header: "Author"}, column{header: "Merges", right: true},
+		column{header: "Median", right: true}, column{header: "P90", right: true})
+	for i := 0; i < maxEntries; i++ {
+		entry := stats.Authors[i]
+		t.row(
+			cell(p.rankStyle, fmt.Sprintf("%d", entry.Rank)),
+			cell(p.nameStyle, entry.Name),
+			p.count(entry.Merges),
+			formatMergeTime(entry.Median),
+			formatMergeTime(entry.P90),
+		)
+	}
+	p.printTable(t)
+
+	fmt.Fprintln(p.w)
+	fmt.Fprintln(p.w, p.titleStyle.Render("Merge Cadence - Merges per Week"))
+	t = newTable(column{header: "Week"}, column{header: "Merges", right: true})
+	for _, week := range stats.Weeks {
+		t.row(week.Week.Format("2006-01-02"), p.count(week.Merges))
+	}
+	p.printTable(t)
+}
+
+// percentile returns the p-th percentile of durations by nearest rank, or
+// zero when there are none.
+func percentile(durations []time.Duration, p float64) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+
+	sorted := append([]time.Duration(nil), durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	index := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	if index < 0 {
+		index = 0
+	}
+	return sorted[index]
+}
+
+// weekStart returns midnight UTC on the Monday of the week containing t.
+func weekStart(t time.Time) time.Time {
+	t = t.UTC()
+	daysSinceMonday := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-daysSinceMonday, 0, 0, 0, 0, time.UTC)
+}
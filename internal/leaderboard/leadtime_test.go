@@ -0,0 +1,74 @@
+package leaderboard
+
+import (
+	"testing"
+	"time"
+
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+func TestLeadTimeStats(t *testing.T) {
+	// A Wednesday, two weeks before now
+	since := time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)
+	now := since.AddDate(0, 0, 14)
+	merge := func(author string, mergedAt time.Time, leadTime time.Duration) types.MergeInfo {
+		return types.MergeInfo{Author: author, Email: author + "@example.com", MergedAt: mergedAt, FirstCommitAt: mergedAt.Add(-leadTime)}
+	}
+
+	merges := []types.MergeInfo{
+		merge("bob", since.AddDate(0, 0, 1), 2*time.Hour),
+		merge("bob", since.AddDate(0, 0, 2), 10*time.Hour),
+		merge("alice", since.AddDate(0, 0, 3), 4*time.Hour),
+		merge("bob", since.AddDate(0, 0, 12), 30*time.Hour),
+		// Outside the window
+		merge("carol", since.AddDate(0, 0, -1), time.Hour),
+	}
+
+	stats := LeadTimeStats(merges, since, now)
+
+	if stats.Merges != 4 {
+		t.Errorf("Expected 4 merges in the window, but got %d", stats.Merges)
+	}
+	if stats.Median != 4*time.Hour || stats.P75 != 10*time.Hour || stats.P90 != 30*time.Hour {
+		t.Errorf("Expected median 4h, p75 10h and p90 30h, but got %v, %v and %v", stats.Median, stats.P75, stats.P90)
+	}
+	if stats.MergesPerWeek != 2 {
+		t.Errorf("Expected 2 merges per week, but got %.2f", stats.MergesPerWeek)
+	}
+
+	expectedWeeks := []types.WeeklyMerges{
+		{Week: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Merges: 3},
+		{Week: time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC), Merges: 0},
+		{Week: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), Merges: 1},
+	}
+	if len(stats.Weeks) != len(expectedWeeks) {
+		t.Fatalf("Expected %d weeks, but got %+v", len(expectedWeeks), stats.Weeks)
+	}
+	for i, week := range stats.Weeks {
+		if !week.Week.Equal(expectedWeeks[i].Week) || week.Merges != expectedWeeks[i].Merges {
+			t.Errorf("Expected week %d to be %+v, but got %+v", i, expectedWeeks[i], week)
+		}
+	}
+
+	expectedAuthors := []types.LeadTimeAuthorEntry{
+		{Rank: 1, Name: "bob", Email: "bob@example.com", Merges: 3, Median: 10 * time.Hour, P90: 30 * time.Hour},
+		{Rank: 2, Name: "alice", Email: "alice@example.com", Merges: 1, Median: 4 * time.Hour, P90: 4 * time.Hour},
+	}
+	if len(stats.Authors) != len(expectedAuthors) {
+		t.Fatalf("Expected %d authors, but got %+v", len(expectedAuthors), stats.Authors)
+	}
+	for i, author := range stats.Authors {
+		if author != expectedAuthors[i] {
+			t.Errorf("Expected author %d to be %+v, but got %+v", i, expectedAuthors[i], author)
+		}
+	}
+}
+
+func TestLeadTimeStatsWithoutMerges(t *testing.T) {
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	stats := LeadTimeStats(nil, since, since.AddDate(0, 0, 7))
+
+	if stats.Merges != 0 || stats.Median != 0 || stats.MergesPerWeek != 0 || len(stats.Authors) != 0 {
+		t.Errorf("Expected empty statistics, but got %+v", stats)
+	}
+}
@@ -0,0 +1,267 @@
+package leaderboard
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/xeon-zolt/codecompass/internal/cerrors"
+	"github.com/xeon-zolt/codecompass/internal/git"
+	"github.com/xeon-zolt/codecompass/internal/types"
+	"github.com/xeon-zolt/codecompass/internal/utils"
+)
+
+// GenerateLFSReport checks the files at HEAD that .gitattributes routes
+// through Git LFS and reports which were committed as raw blobs instead of
+// pointers. LFS object sizes need git lfs; without it they are left out with
+// a warning.
+func GenerateLFSReport(ctx context.Context, dir string, warnings *utils.WarningCollector) (*types.LFSStats, error) {
+	patterns, err := readLFSPatterns(filepath.Join(dir, ".gitattributes"))
+	if err != nil {
+		return nil, err
+	}
+
+	blobs, err := git.GetHeadBlobs(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, len(blobs))
+	for i, blob := range blobs {
+		paths[i] = blob.Path
+	}
+	tracked, err := git.GetLFSTrackedPaths(ctx, dir, paths)
+	if err != nil {
+		return nil, err
+	}
+
+	// Anything larger cannot be a pointer, so is not read
+	var small []string
+	for _, blob := range blobs {
+		if tracked[blob.Path] && blob.Size < git.LFSPointerMaxSize {
+			small = append(small, blob.Hash)
+		}
+	}
+	contents, err := git.ReadBlobs(ctx, dir, small)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &types.LFSStats{Patterns: make([]types.LFSPatternEntry, len(patterns))}
+	for i, pattern := range patterns {
+		stats.Patterns[i].Pattern = pattern
+	}
+
+	for _, blob := range blobs {
+		if !tracked[blob.Path] {
+			continue
+		}
+		content, read := contents[blob.Hash]
+		pointer := read && git.IsLFSPointer(content)
+
+		stats.Tracked++
+		if pointer {
+			stats.Pointers++
+		} else {
+			stats.Raw++
+			stats.Violations = append(stats.Violations, types.LFSViolation{Path: blob.Path, Size: blob.Size})
+		}
+
+		for i, pattern := range patterns {
+			if !matchAttributePattern(pattern, blob.Path) {
+				continue
+			}
+			stats.Patterns[i].Files++
+			if pointer {
+				stats.Patterns[i].Pointers++
+			} else {
+				stats.Patterns[i].Raw++
+			}
+		}
+	}
+
+	sort.SliceStable(stats.Violations, func(i, j int) bool {
+		if stats.Violations[i].Size != stats.Violations[j].Size {
+			return stats.Violations[i].Size > stats.Violations[j].Size
+		}
+		return stats.Violations[i].Path < stats.Violations[j].Path
+	})
+	for i := range stats.Violations {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		commit, err := git.GetLastChange(ctx, dir, stats.Violations[i].Path)
+		if err != nil {
+			continue
+		}
+		stats.Violations[i].Commit = commit.Hash
+		stats.Violations[i].Author = commit.Author
+		stats.Violations[i].Email = commit.Email
+		stats.Violations[i].Date = commit.Date
+	}
+
+	sizes, err := git.GetLFSObjectSizes(ctx, dir)
+	var toolErr *cerrors.ErrToolNotFound
+	switch {
+	case errors.As(err, &toolErr):
+		if stats.Pointers > 0 {
+			warnings.Add(types.NewWarning("LFS object sizes skipped (git lfs is not installed)"))
+		}
+	case err != nil:
+		return nil, err
+	default:
+		stats.ObjectsCounted = true
+		stats.Objects = len(sizes)
+		for _, size := range sizes {
+			stats.ObjectSize += size
+		}
+	}
+
+	return stats, nil
+}
+
+// readLFSPatterns returns the patterns the .gitattributes file at name sets
+// filter=lfs on, in file order. A missing file has none.
+func readLFSPatterns(name string) ([]string, error) {
+	file, err := os.Open(name)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read .gitattributes: %w", err)
+	}
+	defer file.Close()
+
+	var patterns []string
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		for _, attribute := range fields[1:] {
+			if attribute == "filter=lfs" && !seen[fields[0]] {
+				seen[fields[0]] = true
+				patterns = append(patterns, fields[0])
+			}
+		}
+	}
+	return patterns, scanner.Err()
+}
+
+// matchAttributePattern reports whether a .gitattributes pattern matches
+// path. As in git, a pattern without a slash matches the file name at any
+// depth, and one with a slash matches the whole path from the root, where **
+// spans directories.
+func matchAttributePattern(pattern, filePath string) bool {
+	if !strings.Contains(pattern, "/") {
+		filePath = path.Base(filePath)
+	}
+
+	re, err := regexp.Compile("^" + globRegexp(strings.TrimPrefix(pattern, "/")) + "$")
+	return err == nil && re.MatchString(filePath)
+}
+
+// globRegexp translates a wildmatch pattern into a regular expression.
+func globRegexp(pattern string) string {
+	var b strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			b.WriteString("[^/]*")
+		case pattern[i] == '?':
+			b.WriteString("[^/]")
+		case pattern[i] == '[' && strings.IndexByte(pattern[i:], ']') > 1:
+			end := i + strings.IndexByte(pattern[i:], ']')
+			class := pattern[i+1 : end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i = end
+		default:
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	return b.String()
+}
+
+func (p *Printer) PrintLFSReport(stats types.LFSStats, topN int) {
+	fmt.Fprintln(p.w, p.titleStyle.Render("Git LFS Report - Pointer Integrity"))
+
+	if len(stats.Patterns) == 0 && stats.Tracked == 0 {
+		fmt.Fprintln(p.w, p.cellStyle.Render("📭 No Git LFS patterns in .gitattributes"))
+		return
+	}
+
+	coverage := 100.0
+	if stats.Tracked > 0 {
+		coverage = float64(stats.Pointers) / float64(stats.Tracked) * 100
+	}
+	fmt.Fprintf(p.w, "📦 %s files tracked by LFS: %s pointers, %s raw blobs (%.1f%% stored in LFS)\n",
+		p.formatCount(stats.Tracked), p.formatCount(stats.Pointers), p.cellStyle.Render(p.formatCount(stats.Raw)), coverage)
+
+	t := newTable(column{header: "Pattern"}, column{header: "Files", right: true},
+		column{header: "Pointers", right: true}, column{header: "Raw", right: true})
+	for _, pattern := range stats.Patterns {
+		raw := p.count(pattern.Raw)
+		if pattern.Raw > 0 {
+			raw = "⚠️ " + cell(p.errorStyle, p.formatCount(pattern.Raw))
+		}
+		t.row(cell(p.topRuleStyle, pattern.Pattern), p.count(pattern.Files), p.count(pattern.Pointers), raw)
+	}
+	p.printTable(t)
+
+	if stats.ObjectsCounted {
+		fmt.Fprintf(p.w, "🗄️ %s LFS objects, about %s\n", p.formatCount(stats.Objects), formatFileSize(stats.ObjectSize))
+	} else {
+		fmt.Fprintln(p.w, "🗄️ LFS object sizes unavailable (git lfs is not installed)")
+	}
+
+	if len(stats.Violations) == 0 {
+		return
+	}
+
+	fmt.Fprintln(p.w)
+	fmt.Fprintln(p.w, p.titleStyle.Render("LFS Violations - Raw Blobs Where Pointers Were Expected"))
+
+	maxEntries := p.limit(topN, len(stats.Violations))
+
+	t = newTable(rankColumn, fileColumn, column{header: "Size", right: true},
+		column{header: "Commit"}, column{header: "Author"}, column{header: "Date"})
+	for i := 0; i < maxEntries; i++ {
+		entry := stats.Violations[i]
+
+		// The commit that added the blob is unknown when history is shallow
+		commit, author, date := cell(p.emailStyle, "unknown"), "", ""
+		if entry.Commit != "" {
+			commit = shortHash(entry.Commit)
+			author = fmt.Sprintf("%s %s", cell(p.nameStyle, entry.Author), cell(p.emailStyle, fmt.Sprintf("(%s)", entry.Email)))
+			date = entry.Date.UTC().Format("2006-01-02")
+		}
+
+		t.row(
+			cell(p.rankStyle, fmt.Sprintf("%d", i+1)),
+			p.pathCell(p.cellStyle, entry.Path),
+			formatFileSize(entry.Size),
+			commit,
+			author,
+			date,
+		)
+	}
+	p.printTable(t)
+}
@@ -0,0 +1,106 @@
+package leaderboard
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/xeon-zolt/codecompass/internal/testutil"
+	"github.com/xeon-zolt/codecompass/internal/types"
+	"github.com/xeon-zolt/codecompass/internal/utils"
+)
+
+const lfsPointer = "version https://git-lfs.github.com/spec/v1\n" +
+	"oid sha256:4d7a214614ab2935c943f9e0ff69d22eadbb8f32b1258daaa5e2ca24d17e2393\n" +
+	"size 1048576\n"
+
+func TestGenerateLFSReport(t *testing.T) {
+	// Without git lfs installed the filter does nothing, so raw files stay raw
+	dir := testutil.NewRepo(t).
+		WithAuthor("Alice", "alice@example.com").
+		Commit("add lfs patterns", map[string]string{
+			".gitattributes": "# binaries\n*.png filter=lfs diff=lfs merge=lfs -text\nassets/**/*.bin filter=lfs -text\n*.md text\n",
+			"logo.png":       lfsPointer,
+			"readme.md":      "# readme\n",
+		}).
+		WithAuthor("Bob", "bob@example.com").
+		Commit("add raw binaries", map[string]string{
+			"images/big.png":       "\x89PNG\r\n\x1a\n" + strings.Repeat("\x00", 2000),
+			"assets/data/blob.bin": strings.Repeat("\x01", 1500),
+			"data/other.bin":       strings.Repeat("\x02", 3000),
+		}).
+		Dir()
+
+	stats, err := GenerateLFSReport(context.Background(), dir, utils.NewWarningCollector())
+	if err != nil {
+		t.Fatalf("GenerateLFSReport failed: %v", err)
+	}
+
+	if stats.Tracked != 3 || stats.Pointers != 1 || stats.Raw != 2 {
+		t.Errorf("Expected 3 tracked files, 1 pointer and 2 raw blobs, but got %+v", stats)
+	}
+
+	expectedPatterns := []types.LFSPatternEntry{
+		{Pattern: "*.png", Files: 2, Pointers: 1, Raw: 1},
+		{Pattern: "assets/**/*.bin", Files: 1, Pointers: 0, Raw: 1},
+	}
+	if len(stats.Patterns) != len(expectedPatterns) {
+		t.Fatalf("Expected patterns %+v, but got %+v", expectedPatterns, stats.Patterns)
+	}
+	for i, pattern := range stats.Patterns {
+		if pattern != expectedPatterns[i] {
+			t.Errorf("Expected pattern %d to be %+v, but got %+v", i, expectedPatterns[i], pattern)
+		}
+	}
+
+	if len(stats.Violations) != 2 {
+		t.Fatalf("Expected 2 violations, but got %+v", stats.Violations)
+	}
+	largest := stats.Violations[0]
+	if largest.Path != "images/big.png" || largest.Size != 2008 || largest.Author != "Bob" || largest.Commit == "" {
+		t.Errorf("Expected images/big.png from Bob first, but got %+v", largest)
+	}
+	if stats.Violations[1].Path != "assets/data/blob.bin" {
+		t.Errorf("Expected assets/data/blob.bin second, but got %+v", stats.Violations[1])
+	}
+}
+
+func TestGenerateLFSReportWithoutAttributes(t *testing.T) {
+	dir := testutil.NewRepo(t).
+		Commit("initial", map[string]string{"main.go": "package main\n"}).
+		Dir()
+
+	stats, err := GenerateLFSReport(context.Background(), dir, utils.NewWarningCollector())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Tracked != 0 || len(stats.Patterns) != 0 {
+		t.Errorf("Expected nothing tracked by LFS, but got %+v", stats)
+	}
+}
+
+func TestMatchAttributePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"*.png", "logo.png", true},
+		{"*.png", "deep/dir/logo.png", true},
+		{"*.png", "logo.png.txt", false},
+		{"/assets/*.bin", "assets/a.bin", true},
+		{"/assets/*.bin", "assets/sub/a.bin", false},
+		{"assets/**/*.bin", "assets/a.bin", true},
+		{"assets/**/*.bin", "assets/x/y/a.bin", true},
+		{"assets/**/*.bin", "other/assets/a.bin", false},
+		{"media/**", "media/video/clip.mp4", true},
+		{"*.[ch]", "main.c", true},
+		{"*.[!ch]", "main.c", false},
+	}
+
+	for _, tt := range tests {
+		if got := matchAttributePattern(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("matchAttributePattern(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,342 @@
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/xeon-zolt/codecompass/internal/config"
+	"github.com/xeon-zolt/codecompass/internal/types"
+	"github.com/xeon-zolt/codecompass/internal/utils"
+)
+
+// function is a function body found in a file, from the line of its
+// declaration to the line of its closing brace.
+type function struct {
+	name      string
+	startLine int
+	endLine   int
+}
+
+// functionScanners finds the functions of the languages measured by the long
+// function leaderboard, by file extension.
+var functionScanners = map[string]func(lines []string) []function{
+	".go":  scanGoFunctions,
+	".js":  scanJSFunctions,
+	".jsx": scanJSFunctions,
+	".mjs": scanJSFunctions,
+	".cjs": scanJSFunctions,
+	".ts":  scanJSFunctions,
+	".tsx": scanJSFunctions,
+	".mts": scanJSFunctions,
+	".cts": scanJSFunctions,
+}
+
+// MeasuresFunctions reports whether the long function leaderboard measures
+// the functions of filePath.
+func MeasuresFunctions(filePath string) bool {
+	return functionScanners[strings.ToLower(filepath.Ext(filePath))] != nil
+}
+
+// GenerateLongFunctionLeaderboard lists the Go, JavaScript and TypeScript
+// functions with more lines than cfg.LongFunctionLines, longest first.
+// Function bodies are found by brace counting, so the line counts include
+// comments and blank lines. Files with a line longer than max-line-size are
+// skipped and added to warnings. A topN above zero keeps only the longest.
+func GenerateLongFunctionLeaderboard(ctx context.Context, dir string, trackedFiles map[string]bool, cfg *config.Config, warnings *utils.WarningCollector, topN int) ([]types.LongFunctionEntry, error) {
+	threshold := cfg.LongFunctionLines
+	if threshold <= 0 {
+		threshold = config.NewConfig().LongFunctionLines
+	}
+
+	var entries []types.LongFunctionEntry
+
+	for filePath := range trackedFiles {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		scan := functionScanners[strings.ToLower(filepath.Ext(filePath))]
+		if scan == nil || shouldSkipFile(filePath) {
+			continue
+		}
+
+		lines, err := readLines(filepath.Join(dir, filePath), cfg.MaxLineSize)
+		if errors.Is(err, utils.ErrLineTooLong) {
+			warnings.Add(types.NewWarning("Long function scan skipped", "file", filePath, "error", err))
+			continue
+		} else if err != nil {
+			continue
+		}
+
+		for _, fn := range scan(lines) {
+			length := fn.endLine - fn.startLine + 1
+			if length <= threshold {
+				continue
+			}
+			entries = append(entries, types.LongFunctionEntry{
+				Path:         filePath,
+				FunctionName: fn.name,
+				StartLine:    fn.startLine,
+				Lines:        length,
+			})
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Lines != entries[j].Lines {
+			return entries[i].Lines > entries[j].Lines
+		}
+		if entries[i].Path != entries[j].Path {
+			return entries[i].Path < entries[j].Path
+		}
+		return entries[i].StartLine < entries[j].StartLine
+	})
+
+	if topN > 0 && len(entries) > topN {
+		entries = entries[:topN]
+	}
+
+	return entries, nil
+}
+
+// readLines returns the lines of the regular file at path, of at most
+// maxLineKB kilobytes each.
+func readLines(path string, maxLineKB int) ([]string, error) {
+	file, err := utils.OpenRegular(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := utils.NewLineScanner(file, maxLineKB)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines, scanner.Err()
+}
+
+// goFuncDecl matches a top-level Go function or method declaration,
+// capturing the receiver type and the name.
+var goFuncDecl = regexp.MustCompile(`^func\s*(?:\(\s*(?:[A-Za-z_]\w*\s+)?\*?\s*([A-Za-z_]\w*)(?:\[[^\]]*\])?\s*\)\s*)?([A-Za-z_]\w*)`)
+
+// scanGoFunctions finds the top-level functions of gofmt-formatted Go
+// source, where a declaration starts at the beginning of a line and its body
+// ends at the first line holding only a closing brace. Function literals are
+// counted as part of the function around them.
+func scanGoFunctions(lines []string) []function {
+	var functions []function
+
+	for i := 0; i < len(lines); i++ {
+		match := goFuncDecl.FindStringSubmatch(lines[i])
+		if match == nil {
+			continue
+		}
+		fn := function{name: match[2], startLine: i + 1}
+		if match[1] != "" {
+			fn.name = match[1] + "." + match[2]
+		}
+
+		// Find the line opening the body; the signature may span lines,
+		// and functions implemented in assembly have no body
+		open := -1
+		for j := i; j < len(lines); j++ {
+			line := strings.TrimRight(lines[j], " \t")
+			if j > i && (line == "" || line == ")" || strings.HasPrefix(line, "func")) {
+				break
+			}
+			if strings.HasSuffix(line, "{") {
+				open = j
+				break
+			}
+			if j == i && strings.HasSuffix(line, "}") {
+				fn.endLine = i + 1
+				break
+			}
+		}
+		if fn.endLine != 0 {
+			functions = append(functions, fn)
+			continue
+		}
+		if open < 0 {
+			continue
+		}
+
+		for j := open + 1; j < len(lines); j++ {
+			if strings.TrimRight(lines[j], " \t") == "}" {
+				fn.endLine = j + 1
+				break
+			}
+		}
+		if fn.endLine == 0 {
+			continue
+		}
+		functions = append(functions, fn)
+		i = fn.endLine - 1
+	}
+
+	return functions
+}
+
+// jsFunctionStarts match the lines that declare a JavaScript or TypeScript
+// function, capturing its name. They are tried in order on the line with
+// its indentation removed.
+var jsFunctionStarts = []*regexp.Regexp{
+	// function declarations and expressions
+	regexp.MustCompile(`^(?:export\s+)?(?:default\s+)?(?:async\s+)?function\b\s*\*?\s*([A-Za-z_$][\w$]*)?`),
+	// const handler = async (req) => { and const handler = function () {
+	regexp.MustCompile(`^(?:export\s+)?(?:const|let|var)\s+([A-Za-z_$][\w$]*)[^=]*=\s*(?:async\s+)?(?:function\b|\([^)]*\)\s*(?::[^=]+)?=>|[A-Za-z_$][\w$]*\s*=>|\(\s*$)`),
+	// object properties, class fields and assignments
+	regexp.MustCompile(`^(?:(?:public|private|protected|static|readonly|override)\s+)*([A-Za-z_$][\w$.]*)\s*[:=]\s*(?:async\s+)?(?:function\b|\([^)]*\)\s*(?::[^=]+)?=>|[A-Za-z_$][\w$]*\s*=>)`),
+	// class and object literal methods, with the body opened on the line
+	regexp.MustCompile(`^(?:(?:public|private|protected|static|async|readonly|override|get|set)\s+)*\*?\s*([A-Za-z_$][\w$]*)\s*(?:<[^>]*>)?\s*\((?:[^()]|\([^()]*\))*\)\s*(?::\s*[^{;]+)?\{\s*$`),
+}
+
+// jsKeywords are the statements that look like a method declaration, such
+// as if (ready) {.
+var jsKeywords = map[string]bool{
+	"if": true, "for": true, "while": true, "switch": true, "catch": true, "with": true, "return": true,
+}
+
+// scanJSFunctions finds the functions of JavaScript and TypeScript source,
+// including nested ones, by counting braces outside strings, template
+// literals and comments. Anonymous callbacks are counted as part of the
+// function around them.
+func scanJSFunctions(lines []string) []function {
+	type open struct {
+		function
+		depth int
+	}
+
+	var (
+		functions []function
+		stack     []open
+
+		// pending is a declaration whose body has not been opened yet
+		pending    *function
+		depth      int
+		parenDepth int
+		inComment  bool
+		inTemplate bool
+		// templates holds the brace depth outside each ${ being read
+		templates []int
+	)
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if !inComment && !inTemplate {
+			for _, start := range jsFunctionStarts {
+				match := start.FindStringSubmatch(trimmed)
+				if match == nil || jsKeywords[match[1]] {
+					continue
+				}
+				name := match[1]
+				if name == "" {
+					name = "(anonymous)"
+				}
+				pending = &function{name: name, startLine: i + 1}
+				break
+			}
+		}
+
+		var quote byte
+		for j := 0; j < len(line); j++ {
+			c := line[j]
+			switch {
+			case inComment:
+				if c == '*' && j+1 < len(line) && line[j+1] == '/' {
+					inComment = false
+					j++
+				}
+			case inTemplate:
+				switch {
+				case c == '\\':
+					j++
+				case c == '`':
+					inTemplate = false
+				case c == '$' && j+1 < len(line) && line[j+1] == '{':
+					templates = append(templates, depth)
+					depth++
+					inTemplate = false
+					j++
+				}
+			case quote != 0:
+				if c == '\\' {
+					j++
+				} else if c == quote {
+					quote = 0
+				}
+			case c == '\'' || c == '"':
+				quote = c
+			case c == '`':
+				inTemplate = true
+			case c == '/' && j+1 < len(line) && line[j+1] == '/':
+				j = len(line)
+			case c == '/' && j+1 < len(line) && line[j+1] == '*':
+				inComment = true
+				j++
+			case c == '(':
+				parenDepth++
+			case c == ')':
+				parenDepth = max(parenDepth-1, 0)
+			case c == ';':
+				pending = nil
+			case c == '{':
+				if pending != nil {
+					stack = append(stack, open{function: *pending, depth: depth})
+					pending = nil
+				}
+				depth++
+			case c == '}':
+				depth = max(depth-1, 0)
+				if n := len(templates); n > 0 && templates[n-1] == depth {
+					templates = templates[:n-1]
+					inTemplate = true
+					continue
+				}
+				if n := len(stack); n > 0 && stack[n-1].depth == depth {
+					fn := stack[n-1].function
+					fn.endLine = i + 1
+					functions = append(functions, fn)
+					stack = stack[:n-1]
+				}
+			}
+		}
+
+		// A declaration without a body on its line is kept only while its
+		// parameters or arrow continue on the next one
+		if pending != nil && parenDepth == 0 && !strings.HasSuffix(trimmed, "=>") {
+			pending = nil
+		}
+	}
+
+	return functions
+}
+
+func (p *Printer) PrintLongFunctionLeaderboard(entries []types.LongFunctionEntry, topN int) {
+	fmt.Fprintln(p.w, p.titleStyle.Render("Long Function Leaderboard - Longest Function Bodies"))
+
+	if len(entries) == 0 {
+		fmt.Fprintln(p.w, p.cellStyle.Render("🎉 No function runs past the line threshold"))
+		return
+	}
+
+	maxEntries := p.limit(topN, len(entries))
+
+	t := newTable(rankColumn, column{header: "Function"}, column{header: "Location", path: true}, column{header: "Lines", right: true})
+	for i := 0; i < maxEntries; i++ {
+		entry := entries[i]
+		t.row(
+			cell(p.rankStyle, fmt.Sprintf("%d", i+1)),
+			cell(p.nameStyle, entry.FunctionName),
+			cell(p.emailStyle, fmt.Sprintf("%s:%d", p.displayPath(entry.Path), entry.StartLine)),
+			cell(p.warningStyle, p.formatCount(entry.Lines)),
+		)
+	}
+	p.printTable(t)
+}
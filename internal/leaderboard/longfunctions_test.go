@@ -0,0 +1,63 @@
+package leaderboard
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xeon-zolt/codecompass/internal/config"
+	"github.com/xeon-zolt/codecompass/internal/types"
+	"github.com/xeon-zolt/codecompass/internal/utils"
+)
+
+// body returns n statements for a function body.
+func body(n int, statement string) string {
+	return strings.Repeat("\t"+statement+"\n", n)
+}
+
+func TestGenerateLongFunctionLeaderboard(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"server.go": "package main\n\n" +
+			"func short() int {\n\treturn 1\n}\n\n" +
+			"func (s *Server) Handle(\n\tw http.ResponseWriter,\n\tr *http.Request,\n) error {\n" + body(8, "x++") + "\tif true {\n\t\treturn nil\n\t}\n\treturn nil\n}\n\n" +
+			"func oneLiner() {}\n\n" +
+			"//go:noescape\nfunc assembly(x int) int\n\n" +
+			"func long() {\n\tf := func() {\n" + body(10, "x++") + "\t}\n\tf()\n}\n",
+		"app.ts": "export function short(a: number): number {\n  return a;\n}\n\n" +
+			"export const fetchAll = async (ids: string[]): Promise<void> => {\n" + body(10, "await load(`${ids.map((id) => `{${id}`)}`);") + "};\n\n" +
+			"class Store {\n  save(item: Item): void {\n    // } in a comment\n    const s = \"}\";\n" + body(9, "this.count++;") + "  }\n\n  load(): void {\n    return;\n  }\n}\n\n" +
+			"function overload(a: string): void;\n" +
+			"describe('store', () => {\n" + body(20, "it('works', () => {});") + "});\n",
+		"notes.md": "function notCode() {\n" + body(20, "x++") + "}\n",
+	}
+	trackedFiles := make(map[string]bool)
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		trackedFiles[name] = true
+	}
+
+	cfg := config.NewConfig()
+	cfg.LongFunctionLines = 10
+
+	entries, err := GenerateLongFunctionLeaderboard(context.Background(), dir, trackedFiles, cfg, utils.NewWarningCollector(), 0)
+	if err != nil {
+		t.Fatalf("GenerateLongFunctionLeaderboard failed: %v", err)
+	}
+
+	expected := []types.LongFunctionEntry{
+		{Path: "server.go", FunctionName: "Server.Handle", StartLine: 7, Lines: 17},
+		{Path: "server.go", FunctionName: "long", StartLine: 30, Lines: 15},
+		{Path: "app.ts", FunctionName: "save", StartLine: 19, Lines: 13},
+		{Path: "app.ts", FunctionName: "fetchAll", StartLine: 5, Lines: 12},
+	}
+	if !reflect.DeepEqual(entries, expected) {
+		t.Errorf("Expected long functions %+v, but got %+v", expected, entries)
+	}
+}
@@ -0,0 +1,219 @@
+package leaderboard
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+// RepoSort is the column repositories are ranked by in a report of several
+// repositories.
+type RepoSort string
+
+const (
+	// RepoSortIssueDensity ranks the most lint issues per thousand lines of
+	// code first
+	RepoSortIssueDensity RepoSort = "issues-per-kloc"
+	// RepoSortCoverage ranks the lowest line coverage first, and
+	// repositories without a coverage report last
+	RepoSortCoverage RepoSort = "coverage"
+	// RepoSortDebt ranks the most technical debt markers first
+	RepoSortDebt RepoSort = "debt"
+)
+
+// SummarizeRepo rolls the report of one repository up to its entry in a
+// report of several repositories. Issues are counted from the file
+// leaderboard, overflow included, so the counts match its totals.
+func SummarizeRepo(repo string, report *types.Report) types.RepoEntry {
+	entry := types.RepoEntry{Repo: repo, Files: report.Repo.AnalyzedFiles, Authors: len(report.Authors)}
+	for _, file := range report.Files {
+		entry.Issues += file.Count + file.Overflow
+	}
+	for _, file := range report.LinesOfCode {
+		entry.LinesOfCode += file.Lines
+	}
+	for _, file := range report.TechnicalDebt {
+		entry.Debt += file.TotalDebt
+	}
+	for _, file := range report.Coverage {
+		entry.LinesCovered += file.LinesCovered
+		entry.LinesTotal += file.LinesTotal
+	}
+	if entry.LinesOfCode > 0 {
+		entry.IssuesPerKLOC = float64(entry.Issues) / float64(entry.LinesOfCode) * 1000
+	}
+	return entry
+}
+
+func repoCoverage(entry types.RepoEntry) float64 {
+	return float64(entry.LinesCovered) / float64(entry.LinesTotal) * 100
+}
+
+// SortRepos returns a copy of entries ranked by column, ties broken by the
+// name of the repository.
+func SortRepos(entries []types.RepoEntry, by RepoSort) []types.RepoEntry {
+	sorted := append([]types.RepoEntry(nil), entries...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		switch by {
+		case RepoSortCoverage:
+			if (a.LinesTotal > 0) != (b.LinesTotal > 0) {
+				return a.LinesTotal > 0
+			}
+			if a.LinesTotal > 0 && repoCoverage(a) != repoCoverage(b) {
+				return repoCoverage(a) < repoCoverage(b)
+			}
+		case RepoSortDebt:
+			if a.Debt != b.Debt {
+				return a.Debt > b.Debt
+			}
+		default:
+			if a.IssuesPerKLOC != b.IssuesPerKLOC {
+				return a.IssuesPerKLOC > b.IssuesPerKLOC
+			}
+		}
+		return a.Repo < b.Repo
+	})
+	return sorted
+}
+
+// MergeAuthorLeaderboards merges the author leaderboards of several
+// repositories, boards[i] being that of repos[i], into one. Authors are
+// matched by email, ignoring case, list their repositories in the order of
+// repos, and keep the name, email and top rule of the repository they have
+// the most issues in. Entries are sorted like GenerateAuthorLeaderboard.
+func MergeAuthorLeaderboards(repos []string, boards [][]types.LeaderboardEntry) []types.LeaderboardEntry {
+	merged := make(map[string]*types.LeaderboardEntry)
+	// The issues of the repository each merged entry took its name from
+	mostIssues := make(map[string]int)
+	var order []string
+	for i, repo := range repos {
+		for _, entry := range boards[i] {
+			key := strings.ToLower(entry.Email)
+			author := merged[key]
+			if author == nil {
+				author = &types.LeaderboardEntry{}
+				merged[key] = author
+				order = append(order, key)
+			}
+			author.Count += entry.Count
+			author.DecayedScore += entry.DecayedScore
+			author.WeightedScore += entry.WeightedScore
+			author.Errors += entry.Errors
+			author.Warnings += entry.Warnings
+			author.Files += entry.Files
+			author.Repos = append(author.Repos, repo)
+			if entry.Count > mostIssues[key] || author.Name == "" {
+				mostIssues[key] = entry.Count
+				author.Name = entry.Name
+				author.Email = entry.Email
+				author.TopRule = entry.TopRule
+				author.TopCount = entry.TopCount
+			}
+		}
+	}
+
+	entries := make([]types.LeaderboardEntry, 0, len(merged))
+	for _, key := range order {
+		entries = append(entries, *merged[key])
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return authorLess(entries[i], entries[j]) })
+	return entries
+}
+
+// repoLeaderboardTitles are the titles of the repository leaderboards by
+// the column they are ranked by.
+var repoLeaderboardTitles = map[RepoSort]string{
+	RepoSortIssueDensity: "Repository Leaderboard - Most Issues per KLOC",
+	RepoSortCoverage:     "Repository Leaderboard - Lowest Coverage",
+	RepoSortDebt:         "Repository Leaderboard - Most Technical Debt",
+}
+
+// PrintRepoLeaderboard prints the repositories ranked by column.
+func (p *Printer) PrintRepoLeaderboard(entries []types.RepoEntry, by RepoSort, topN int) {
+	fmt.Fprintln(p.w, p.titleStyle.Render(repoLeaderboardTitles[by]))
+
+	if len(entries) == 0 {
+		fmt.Fprintln(p.w, p.cellStyle.Render("📭 No repositories were analyzed"))
+		return
+	}
+
+	sorted := SortRepos(entries, by)
+	if len(sorted) > topN {
+		sorted = sorted[:topN]
+	}
+
+	var t *table
+	switch by {
+	case RepoSortCoverage:
+		t = newTable(rankColumn, column{header: "Repository"}, column{header: "Coverage", right: true},
+			column{header: "Lines Covered", right: true}, column{header: "Lines Total", right: true})
+	case RepoSortDebt:
+		t = newTable(rankColumn, column{header: "Repository"}, column{header: "Debt", right: true},
+			column{header: "LOC", right: true})
+	default:
+		t = newTable(rankColumn, column{header: "Repository"}, column{header: "Issues/KLOC", right: true},
+			column{header: "Issues", right: true}, column{header: "LOC", right: true})
+	}
+	for i, entry := range sorted {
+		rank := cell(p.rankStyle, fmt.Sprintf("%d", i+1))
+		repo := cell(p.nameStyle, entry.Repo)
+		switch by {
+		case RepoSortCoverage:
+			coverage := "n/a"
+			if entry.LinesTotal > 0 {
+				coverage = fmt.Sprintf("%.1f%%", repoCoverage(entry))
+			}
+			t.row(rank, repo, coverage, p.count(entry.LinesCovered), p.count(entry.LinesTotal))
+		case RepoSortDebt:
+			t.row(rank, repo, p.count(entry.Debt), p.count(entry.LinesOfCode))
+		default:
+			t.row(rank, repo, cell(p.cellStyle, fmt.Sprintf("%.1f", entry.IssuesPerKLOC)), p.count(entry.Issues), p.count(entry.LinesOfCode))
+		}
+	}
+	p.printTable(t)
+}
+
+// PrintRepoSummary prints a row per repository, in the order they were
+// listed.
+func (p *Printer) PrintRepoSummary(entries []types.RepoEntry) {
+	fmt.Fprintln(p.w, p.titleStyle.Render("Repository Summary"))
+
+	if len(entries) == 0 {
+		fmt.Fprintln(p.w, p.cellStyle.Render("📭 No repositories were analyzed"))
+		return
+	}
+
+	t := newTable(column{header: "Repository"}, column{header: "Files", right: true}, column{header: "LOC", right: true},
+		column{header: "Authors", right: true}, column{header: "Issues", right: true}, column{header: "Issues/KLOC", right: true},
+		column{header: "Debt", right: true}, column{header: "Coverage", right: true})
+	for _, entry := range entries {
+		coverage := "n/a"
+		if entry.LinesTotal > 0 {
+			coverage = fmt.Sprintf("%.1f%%", repoCoverage(entry))
+		}
+		t.row(cell(p.nameStyle, entry.Repo), p.count(entry.Files), p.count(entry.LinesOfCode), p.count(entry.Authors),
+			p.count(entry.Issues), fmt.Sprintf("%.1f", entry.IssuesPerKLOC), p.count(entry.Debt), coverage)
+	}
+	p.printTable(t)
+}
+
+// PrintRepoFailures lists the repositories that could not be analyzed, with
+// the error of each.
+func (p *Printer) PrintRepoFailures(failures map[string]string) {
+	fmt.Fprintln(p.w, p.titleStyle.Render("Failed Repositories"))
+
+	repos := make([]string, 0, len(failures))
+	for repo := range failures {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+
+	t := newTable(column{header: "Repository"}, column{header: "Error"})
+	for _, repo := range repos {
+		t.row(cell(p.nameStyle, repo), cell(p.errorStyle, failures[repo]))
+	}
+	p.printTable(t)
+}
@@ -0,0 +1,83 @@
+package leaderboard
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+func TestSummarizeRepo(t *testing.T) {
+	report := &types.Report{
+		Repo:    types.RepoInfo{Path: "/src/api", TrackedFiles: 4, AnalyzedFiles: 3},
+		Authors: []types.LeaderboardEntry{{Email: "alice@example.com"}, {Email: "bob@example.com"}},
+		Files: []types.FileLeaderboardEntry{
+			{Path: "a.js", Count: 4},
+			{Path: "dist/b.js", Count: 200, Overflow: 100},
+		},
+		LinesOfCode:   []types.LinesOfCodeEntry{{Path: "a.js", Lines: 1000}, {Path: "dist/b.js", Lines: 2000}},
+		TechnicalDebt: []types.TechnicalDebtEntry{{Path: "a.js", TotalDebt: 2}, {Path: "c.js", TotalDebt: 1}},
+		Coverage:      []types.CoverageEntry{{Path: "a.js", LinesCovered: 30, LinesTotal: 40}},
+	}
+
+	expected := types.RepoEntry{
+		Repo: "../api", Files: 3, LinesOfCode: 3000, Issues: 304, IssuesPerKLOC: 304.0 / 3,
+		Debt: 3, Authors: 2, LinesCovered: 30, LinesTotal: 40,
+	}
+	if entry := SummarizeRepo("../api", report); !reflect.DeepEqual(entry, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, entry)
+	}
+
+	// Without lines of code there is no density
+	if entry := SummarizeRepo("empty", &types.Report{}); entry.IssuesPerKLOC != 0 {
+		t.Errorf("Expected no issue density for an empty repository, got %v", entry.IssuesPerKLOC)
+	}
+}
+
+func TestSortRepos(t *testing.T) {
+	entries := []types.RepoEntry{
+		{Repo: "a", IssuesPerKLOC: 2, Debt: 5},
+		{Repo: "b", IssuesPerKLOC: 9, Debt: 5, LinesCovered: 90, LinesTotal: 100},
+		{Repo: "c", IssuesPerKLOC: 2, Debt: 7, LinesCovered: 1, LinesTotal: 10},
+	}
+
+	tests := map[RepoSort][]string{
+		RepoSortIssueDensity: {"b", "a", "c"},
+		RepoSortCoverage:     {"c", "b", "a"},
+		RepoSortDebt:         {"c", "a", "b"},
+	}
+	for by, expected := range tests {
+		var repos []string
+		for _, entry := range SortRepos(entries, by) {
+			repos = append(repos, entry.Repo)
+		}
+		if !reflect.DeepEqual(repos, expected) {
+			t.Errorf("SortRepos by %s = %v; expected %v", by, repos, expected)
+		}
+	}
+
+	// The entries themselves keep their order
+	if entries[0].Repo != "a" || entries[1].Repo != "b" {
+		t.Errorf("Expected SortRepos to leave its input alone, got %+v", entries)
+	}
+}
+
+func TestMergeAuthorLeaderboards(t *testing.T) {
+	merged := MergeAuthorLeaderboards([]string{"web", "api"}, [][]types.LeaderboardEntry{
+		{
+			{Name: "Alice", Email: "alice@example.com", Count: 3, Errors: 1, Warnings: 2, Files: 2, TopRule: "eqeqeq", TopCount: 2},
+			{Name: "Bob", Email: "bob@example.com", Count: 5, Warnings: 5, Files: 1, TopRule: "prefer-const", TopCount: 5},
+		},
+		{
+			{Name: "Alice Smith", Email: "Alice@Example.com", Count: 8, Errors: 8, Files: 4, TopRule: "no-console", TopCount: 6},
+		},
+	})
+
+	expected := []types.LeaderboardEntry{
+		{Name: "Alice Smith", Email: "Alice@Example.com", Count: 11, Errors: 9, Warnings: 2, Files: 6, TopRule: "no-console", TopCount: 6, Repos: []string{"web", "api"}},
+		{Name: "Bob", Email: "bob@example.com", Count: 5, Warnings: 5, Files: 1, TopRule: "prefer-const", TopCount: 5, Repos: []string{"web"}},
+	}
+	if !reflect.DeepEqual(merged, expected) {
+		t.Errorf("Expected authors merged by email\ngot:  %+v\nwant: %+v", merged, expected)
+	}
+}
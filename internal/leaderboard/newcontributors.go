@@ -0,0 +1,172 @@
+package leaderboard
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/xeon-zolt/codecompass/internal/git"
+	"github.com/xeon-zolt/codecompass/internal/types"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// GenerateNewContributors finds the authors whose first commit falls
+// between since and now, with the commits they made and the files they
+// touched. graced has the issues of each author, by email, that were left
+// off the author leaderboard in their grace period of gracePeriodDays.
+func GenerateNewContributors(ctx context.Context, dir string, dateType git.DateType, since, now time.Time, gracePeriodDays int, graced map[string]*types.AuthorStats) (*types.NewContributorStats, error) {
+	authors, err := git.GetAuthorCommitCounts(ctx, dir, dateType, git.NoCredit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit data: %w", err)
+	}
+	commits, err := git.GetCommitLineChanges(ctx, dir, dateType, since)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := NewContributorStats(authors, commits, since, now, graced)
+	stats.GracePeriodDays = gracePeriodDays
+	return &stats, nil
+}
+
+// NewContributorStats picks the authors of authors whose first commit falls
+// between since and now, latest first, and counts the files their commits
+// of commits touched and their issues of graced.
+func NewContributorStats(authors map[string]types.CommitCountEntry, commits []types.CommitInfo, since, now time.Time, graced map[string]*types.AuthorStats) types.NewContributorStats {
+	stats := types.NewContributorStats{Since: since, Authors: []types.NewContributorEntry{}}
+
+	files := make(map[string]map[string]bool)
+	for _, commit := range commits {
+		if commit.Merge {
+			continue
+		}
+		if files[commit.Email] == nil {
+			files[commit.Email] = make(map[string]bool)
+		}
+		for _, file := range commit.Files {
+			files[commit.Email][file.Path] = true
+		}
+	}
+
+	for email, author := range authors {
+		if author.FirstCommit.Before(since) || author.FirstCommit.After(now) {
+			continue
+		}
+		entry := types.NewContributorEntry{
+			Name:         author.Name,
+			Email:        email,
+			FirstCommit:  author.FirstCommit,
+			Commits:      author.Commits,
+			FilesTouched: len(files[email]),
+		}
+		if issues := graced[email]; issues != nil {
+			entry.GracedIssues = issues.Count
+		}
+		stats.Authors = append(stats.Authors, entry)
+	}
+
+	sort.SliceStable(stats.Authors, func(i, j int) bool {
+		if !stats.Authors[i].FirstCommit.Equal(stats.Authors[j].FirstCommit) {
+			return stats.Authors[i].FirstCommit.After(stats.Authors[j].FirstCommit)
+		}
+		if stats.Authors[i].Name != stats.Authors[j].Name {
+			return stats.Authors[i].Name < stats.Authors[j].Name
+		}
+		return stats.Authors[i].Email < stats.Authors[j].Email
+	})
+	for i := range stats.Authors {
+		stats.Authors[i].Rank = i + 1
+	}
+
+	return stats
+}
+
+// WithoutGracedIssues returns the author stats of authorStats less the
+// issues of graced, leaving out the authors without any left. The author
+// leaderboard is generated from them, so new contributors are not ranked
+// on what they wrote in their grace period.
+func WithoutGracedIssues(authorStats, graced map[string]*types.AuthorStats) map[string]*types.AuthorStats {
+	if len(graced) == 0 {
+		return authorStats
+	}
+
+	kept := make(map[string]*types.AuthorStats, len(authorStats))
+	for email, stats := range authorStats {
+		issues := graced[email]
+		if issues == nil {
+			kept[email] = stats
+			continue
+		}
+		if stats.Count <= issues.Count {
+			continue
+		}
+
+		left := *stats
+		left.Count -= issues.Count
+		left.Errors -= issues.Errors
+		left.Warnings -= issues.Warnings
+		left.Decayed -= issues.Decayed
+		left.Rules = subtractCounts(stats.Rules, issues.Rules)
+		left.RuleErrors = subtractCounts(stats.RuleErrors, issues.RuleErrors)
+		left.Files = subtractCounts(stats.Files, issues.Files)
+		kept[email] = &left
+	}
+	return kept
+}
+
+// subtractCounts returns counts less those of subtracted, leaving out the
+// keys without any left.
+func subtractCounts(counts, subtracted map[string]int) map[string]int {
+	left := make(map[string]int, len(counts))
+	for key, count := range counts {
+		if count -= subtracted[key]; count > 0 {
+			left[key] = count
+		}
+	}
+	return left
+}
+
+// PrintNewContributors welcomes the authors whose first commit falls in the
+// window, with the issues their grace period kept off the author
+// leaderboard when there is one.
+func (p *Printer) PrintNewContributors(stats types.NewContributorStats, topN int) {
+	since := stats.Since.Format("2006-01-02")
+	title := p.titleStyle.Background(lipgloss.Color("#005f00"))
+	fmt.Fprintln(p.w, title.Render(fmt.Sprintf("New Contributors - First Commit in the Window (since %s)", since)))
+
+	if len(stats.Authors) == 0 {
+		fmt.Fprintln(p.w, p.cellStyle.Render(fmt.Sprintf("📭 No first commits since %s", since)))
+		return
+	}
+
+	maxEntries := p.limit(topN, len(stats.Authors))
+
+	columns := []column{rankColumn, {header: "Author"}, {header: "Email"}, {header: "First Commit"},
+		{header: "Commits", right: true}, {header: "Files Touched", right: true}}
+	if stats.GracePeriodDays > 0 {
+		columns = append(columns, column{header: "Graced Issues", right: true})
+	}
+	t := newTable(columns...)
+	for i := 0; i < maxEntries; i++ {
+		entry := stats.Authors[i]
+		cells := []string{
+			cell(p.rankStyle, fmt.Sprintf("%d", entry.Rank)),
+			cell(p.nameStyle, entry.Name),
+			cell(p.emailStyle, entry.Email),
+			cell(p.cellStyle, entry.FirstCommit.Format("2006-01-02")),
+			p.count(entry.Commits),
+			p.count(entry.FilesTouched),
+		}
+		if stats.GracePeriodDays > 0 {
+			cells = append(cells, p.count(entry.GracedIssues))
+		}
+		t.row(cells...)
+	}
+	p.printTable(t)
+
+	if stats.GracePeriodDays > 0 {
+		fmt.Fprintf(p.w, "🌱 Issues from their first %d days are left off the author leaderboard\n", stats.GracePeriodDays)
+	}
+}
@@ -0,0 +1,81 @@
+package leaderboard
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+func TestNewContributorStats(t *testing.T) {
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := since.AddDate(0, 0, 90)
+	author := func(name string, first time.Time, commits int) types.CommitCountEntry {
+		return types.CommitCountEntry{Name: name, Email: name + "@example.com", Commits: commits, FirstCommit: first, LastCommit: now}
+	}
+	authors := map[string]types.CommitCountEntry{
+		"alice@example.com": author("alice", since.AddDate(-1, 0, 0), 40),
+		"bob@example.com":   author("bob", since.AddDate(0, 0, 10), 3),
+		"carol@example.com": author("carol", since.AddDate(0, 0, 60), 1),
+	}
+	commit := func(name string, merge bool, paths ...string) types.CommitInfo {
+		info := types.CommitInfo{Author: name, Email: name + "@example.com", Merge: merge}
+		for _, path := range paths {
+			info.Files = append(info.Files, types.FileLineChange{Path: path})
+		}
+		return info
+	}
+	commits := []types.CommitInfo{
+		commit("carol", false, "docs/readme.md"),
+		commit("bob", false, "src/a.go", "src/b.go"),
+		commit("bob", false, "src/a.go"),
+		commit("bob", true, "src/c.go"),
+		commit("alice", false, "src/a.go"),
+	}
+	graced := map[string]*types.AuthorStats{"bob@example.com": {Count: 4}}
+
+	stats := NewContributorStats(authors, commits, since, now, graced)
+
+	// Alice joined before the window, and the latest first commit ranks first
+	expected := []types.NewContributorEntry{
+		{Rank: 1, Name: "carol", Email: "carol@example.com", FirstCommit: since.AddDate(0, 0, 60), Commits: 1, FilesTouched: 1},
+		{Rank: 2, Name: "bob", Email: "bob@example.com", FirstCommit: since.AddDate(0, 0, 10), Commits: 3, FilesTouched: 2, GracedIssues: 4},
+	}
+	if !reflect.DeepEqual(stats.Authors, expected) {
+		t.Errorf("Expected %+v, but got %+v", expected, stats.Authors)
+	}
+	if !stats.Since.Equal(since) {
+		t.Errorf("Expected the window to start at %s, but got %s", since, stats.Since)
+	}
+}
+
+func TestWithoutGracedIssues(t *testing.T) {
+	authorStats := map[string]*types.AuthorStats{
+		"alice@example.com": {Name: "Alice", Count: 3, Errors: 2, Warnings: 1, Rules: map[string]int{"eqeqeq": 2, "no-console": 1},
+			RuleErrors: map[string]int{"eqeqeq": 2}, Files: map[string]int{"a.js": 2, "b.js": 1}},
+		"bob@example.com":   {Name: "Bob", Count: 1, Warnings: 1, Rules: map[string]int{"no-console": 1}, Files: map[string]int{"c.js": 1}},
+		"carol@example.com": {Name: "Carol", Count: 2, Errors: 2, Rules: map[string]int{"eqeqeq": 2}, Files: map[string]int{"d.js": 2}},
+	}
+	graced := map[string]*types.AuthorStats{
+		"alice@example.com": {Count: 2, Errors: 2, Rules: map[string]int{"eqeqeq": 2}, RuleErrors: map[string]int{"eqeqeq": 2}, Files: map[string]int{"a.js": 2}},
+		"bob@example.com":   {Count: 1, Warnings: 1, Rules: map[string]int{"no-console": 1}, Files: map[string]int{"c.js": 1}},
+	}
+
+	ranked := WithoutGracedIssues(authorStats, graced)
+
+	// Bob had nothing but graced issues
+	if _, found := ranked["bob@example.com"]; found || len(ranked) != 2 {
+		t.Errorf("Expected Bob left out, but got %+v", ranked)
+	}
+	expected := &types.AuthorStats{Name: "Alice", Count: 1, Warnings: 1, Rules: map[string]int{"no-console": 1}, RuleErrors: map[string]int{}, Files: map[string]int{"b.js": 1}}
+	if alice := ranked["alice@example.com"]; !reflect.DeepEqual(alice, expected) {
+		t.Errorf("Expected %+v, but got %+v", expected, alice)
+	}
+	if ranked["carol@example.com"] != authorStats["carol@example.com"] {
+		t.Errorf("Expected Carol's stats unchanged, but got %+v", ranked["carol@example.com"])
+	}
+	if authorStats["alice@example.com"].Count != 3 {
+		t.Errorf("Expected the author stats left as they were, but got %+v", authorStats["alice@example.com"])
+	}
+}
@@ -0,0 +1,216 @@
+package leaderboard
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/xeon-zolt/codecompass/internal/codeowners"
+	"github.com/xeon-zolt/codecompass/internal/config"
+	"github.com/xeon-zolt/codecompass/internal/git"
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+// Where the owners of a file came from, as recorded in
+// FileLeaderboardEntry.OwnerSource.
+const (
+	OwnerSourceCodeowners = "codeowners"
+	OwnerSourceBlame      = "blame"
+	OwnerSourceNone       = "none"
+)
+
+// unownedLabel names the worklist of the files no owner source resolved.
+const unownedLabel = "unowned"
+
+// ResolveFileOwners sets the owners of each file in entries, trying the
+// sources of cfg.OwnerResolution in order: the last CODEOWNERS rule matching
+// the file, when owners is not nil, and the author of most of its current
+// lines, ignored authors left out. Files no source resolves are marked
+// unowned.
+func ResolveFileOwners(ctx context.Context, entries []types.FileLeaderboardEntry, owners *codeowners.File, blamer *git.Blamer, cfg *config.Config) error {
+	for i := range entries {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		entry := &entries[i]
+		entry.Owners, entry.OwnerSource = nil, OwnerSourceNone
+		for _, source := range cfg.OwnerResolution {
+			switch source {
+			case OwnerSourceCodeowners:
+				if owners != nil {
+					entry.Owners, _ = owners.Owners(filepath.ToSlash(entry.Path))
+				}
+			case OwnerSourceBlame:
+				// The files with issues were blamed already, so this is
+				// served from the blamer's cache
+				blameMap, err := blamer.BlameFile(ctx, entry.Path)
+				if err == nil {
+					if email := dominantAuthor(blameMap, cfg); email != "" {
+						entry.Owners = []string{email}
+					}
+				}
+			}
+			if len(entry.Owners) > 0 {
+				entry.OwnerSource = source
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// dominantAuthor returns the email of the author of most lines in blameMap,
+// the first by email on a tie, or "" when every line is by an ignored
+// author.
+func dominantAuthor(blameMap map[int]types.BlameInfo, cfg *config.Config) string {
+	lines := make(map[string]int)
+	for _, info := range blameMap {
+		if info.Email == "" || cfg.ShouldIgnoreAuthor(info.Email, info.Name) {
+			continue
+		}
+		lines[info.Email]++
+	}
+
+	var top string
+	for email, count := range lines {
+		if top == "" || count > lines[top] || (count == lines[top] && email < top) {
+			top = email
+		}
+	}
+	return top
+}
+
+// OwnerWorklist is the files of one owner from the file leaderboard, in
+// leaderboard order.
+type OwnerWorklist struct {
+	Owner  string // empty for the unowned files
+	Issues int
+	Files  []types.FileLeaderboardEntry
+}
+
+// GroupFilesByOwner regroups the file leaderboard under each owner, so each
+// gets its own worklist. A file with several owners is on the worklist of
+// each. Worklists are sorted by issues, then by owner, with the unowned
+// files last.
+func GroupFilesByOwner(entries []types.FileLeaderboardEntry) []OwnerWorklist {
+	byOwner := make(map[string]*OwnerWorklist)
+	var worklists []*OwnerWorklist
+	add := func(owner string, entry types.FileLeaderboardEntry) {
+		worklist := byOwner[owner]
+		if worklist == nil {
+			worklist = &OwnerWorklist{Owner: owner}
+			byOwner[owner] = worklist
+			worklists = append(worklists, worklist)
+		}
+		worklist.Issues += entry.Count
+		worklist.Files = append(worklist.Files, entry)
+	}
+
+	for _, entry := range entries {
+		if len(entry.Owners) == 0 {
+			add("", entry)
+		}
+		for _, owner := range entry.Owners {
+			add(owner, entry)
+		}
+	}
+
+	sort.SliceStable(worklists, func(i, j int) bool {
+		if (worklists[i].Owner == "") != (worklists[j].Owner == "") {
+			return worklists[j].Owner == ""
+		}
+		if worklists[i].Issues != worklists[j].Issues {
+			return worklists[i].Issues > worklists[j].Issues
+		}
+		return worklists[i].Owner < worklists[j].Owner
+	})
+
+	result := make([]OwnerWorklist, len(worklists))
+	for i, worklist := range worklists {
+		result[i] = *worklist
+	}
+	return result
+}
+
+// ownerLabel names the owner of worklist.
+func ownerLabel(worklist OwnerWorklist) string {
+	if worklist.Owner == "" {
+		return unownedLabel
+	}
+	return worklist.Owner
+}
+
+// formatOwners joins the owners of a file for display.
+func formatOwners(entry types.FileLeaderboardEntry) string {
+	if len(entry.Owners) == 0 {
+		return unownedLabel
+	}
+	return strings.Join(entry.Owners, ", ")
+}
+
+// PrintFilesByOwner prints the worklist of each owner, its topN files most
+// problematic first.
+func (p *Printer) PrintFilesByOwner(worklists []OwnerWorklist, topN int) {
+	fmt.Fprintln(p.w, p.titleStyle.Render("Files by Owner - A Worklist per Owner"))
+
+	if len(worklists) == 0 {
+		fmt.Fprintln(p.w, p.cellStyle.Render("🎉 No files with issues to hand out"))
+		return
+	}
+
+	for i, worklist := range worklists {
+		if i > 0 {
+			fmt.Fprintln(p.w)
+		}
+		fmt.Fprintf(p.w, "  👥 %s %s\n", p.nameStyle.Render(ownerLabel(worklist)),
+			p.emailStyle.Render(fmt.Sprintf("(files: %s, issues: %s)", p.formatCount(len(worklist.Files)), p.formatCount(worklist.Issues))))
+
+		t := newTable(rankColumn, fileColumn, column{header: "Issues", right: true},
+			column{header: "Errors", right: true}, column{header: "Warnings", right: true}, column{header: "Top Rule"})
+		for j, entry := range worklist.Files[:min(topN, len(worklist.Files))] {
+			t.row(
+				cell(p.rankStyle, fmt.Sprintf("%d", j+1)),
+				p.pathCell(p.cellStyle, entry.Path),
+				p.count(entry.Count),
+				cell(p.errorStyle, p.formatCount(entry.Errors)),
+				cell(p.warningStyle, p.formatCount(entry.Warnings)),
+				fmt.Sprintf("%s (%s)", cell(p.topRuleStyle, entry.TopRule), p.formatCount(entry.TopCount)),
+			)
+		}
+		p.printTable(t)
+	}
+}
+
+// WriteFilesByOwnerMarkdown writes the worklist of each owner, its topN
+// files most problematic first, as a Markdown section to paste into the
+// owner's channel.
+func WriteFilesByOwnerMarkdown(w io.Writer, worklists []OwnerWorklist, topN int) error {
+	var b strings.Builder
+	b.WriteString("# Files by Owner\n")
+	if len(worklists) == 0 {
+		b.WriteString("\nNo files with issues to hand out.\n")
+	}
+	for _, worklist := range worklists {
+		fmt.Fprintf(&b, "\n## %s\n\n", markdownEscape(ownerLabel(worklist)))
+		fmt.Fprintf(&b, "Files: %d, issues: %d\n\n", len(worklist.Files), worklist.Issues)
+		b.WriteString("| # | File | Issues | Errors | Warnings | Top Rule |\n")
+		b.WriteString("|--:|------|-------:|-------:|---------:|----------|\n")
+		for i, entry := range worklist.Files[:min(topN, len(worklist.Files))] {
+			fmt.Fprintf(&b, "| %d | `%s` | %d | %d | %d | %s (%d) |\n", i+1, markdownEscape(filepath.ToSlash(entry.Path)),
+				entry.Count, entry.Errors, entry.Warnings, markdownEscape(entry.TopRule), entry.TopCount)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownEscape escapes the characters that would end a Markdown table
+// cell or a line.
+func markdownEscape(text string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(text)
+}
@@ -0,0 +1,122 @@
+package leaderboard
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xeon-zolt/codecompass/internal/codeowners"
+	"github.com/xeon-zolt/codecompass/internal/config"
+	"github.com/xeon-zolt/codecompass/internal/git"
+	"github.com/xeon-zolt/codecompass/internal/logging"
+	"github.com/xeon-zolt/codecompass/internal/testutil"
+	"github.com/xeon-zolt/codecompass/internal/types"
+	"github.com/xeon-zolt/codecompass/internal/utils"
+)
+
+func TestResolveFileOwners(t *testing.T) {
+	repo := testutil.NewRepo(t).
+		WithAuthor("Alice", "alice@example.com").
+		Commit("add billing", map[string]string{
+			"src/billing/invoice.ts": "a\nb\nc\n",
+			"src/app.ts":             "a\nb\nc\n",
+			"tools/build.sh":         "a\n",
+		}).
+		WithAuthor("Bob", "bob@example.com").
+		Commit("rewrite app", map[string]string{"src/app.ts": "x\ny\nc\n"}).
+		WithAuthor("Bot", "bot@example.com").
+		Commit("regenerate", map[string]string{"tools/build.sh": "generated\n"})
+	dir := repo.Dir()
+
+	owners, err := codeowners.Parse(strings.NewReader("/src/billing/ @acme/billing\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	blamer := git.NewBlamer(dir, utils.NewSemaphore(1), logging.Discard(), utils.NewWarningCollector())
+
+	cfg := config.NewConfig()
+	cfg.IgnoredAuthors = []string{"bot@example.com"}
+	entries := []types.FileLeaderboardEntry{{Path: "src/billing/invoice.ts"}, {Path: "src/app.ts"}, {Path: "tools/build.sh"}}
+	if err := ResolveFileOwners(context.Background(), entries, owners, blamer, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		owners []string
+		source string
+	}{
+		{[]string{"@acme/billing"}, OwnerSourceCodeowners},
+		{[]string{"bob@example.com"}, OwnerSourceBlame},
+		// Only an ignored author wrote the file
+		{nil, OwnerSourceNone},
+	}
+	for i, entry := range entries {
+		if !reflect.DeepEqual(entry.Owners, expected[i].owners) || entry.OwnerSource != expected[i].source {
+			t.Errorf("Expected %s to be owned by %v from %s, got %v from %s",
+				entry.Path, expected[i].owners, expected[i].source, entry.Owners, entry.OwnerSource)
+		}
+	}
+
+	// Blame first overrides CODEOWNERS
+	cfg.OwnerResolution = []string{"blame", "codeowners"}
+	if err := ResolveFileOwners(context.Background(), entries, owners, blamer, cfg); err != nil {
+		t.Fatal(err)
+	}
+	if entry := entries[0]; !reflect.DeepEqual(entry.Owners, []string{"alice@example.com"}) || entry.OwnerSource != OwnerSourceBlame {
+		t.Errorf("Expected the billing file to be owned by its author, got %v from %s", entry.Owners, entry.OwnerSource)
+	}
+}
+
+func TestGroupFilesByOwner(t *testing.T) {
+	entries := []types.FileLeaderboardEntry{
+		{Path: "src/billing/invoice.ts", Count: 9, Owners: []string{"@acme/billing", "@alice"}},
+		{Path: "vendor/lib.js", Count: 50},
+		{Path: "src/app.ts", Count: 5, Owners: []string{"@acme/web"}},
+		{Path: "src/billing/tax.ts", Count: 2, Owners: []string{"@acme/billing"}},
+	}
+
+	worklists := GroupFilesByOwner(entries)
+	var got []string
+	for _, worklist := range worklists {
+		var paths []string
+		for _, file := range worklist.Files {
+			paths = append(paths, file.Path)
+		}
+		got = append(got, ownerLabel(worklist)+"="+strings.Join(paths, ","))
+	}
+
+	// Unowned files come last, however many issues they have
+	expected := []string{
+		"@acme/billing=src/billing/invoice.ts,src/billing/tax.ts",
+		"@alice=src/billing/invoice.ts",
+		"@acme/web=src/app.ts",
+		"unowned=vendor/lib.js",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+	if worklists[0].Issues != 11 {
+		t.Errorf("Expected 11 issues for @acme/billing, got %d", worklists[0].Issues)
+	}
+}
+
+func TestWriteFilesByOwnerMarkdown(t *testing.T) {
+	worklists := GroupFilesByOwner([]types.FileLeaderboardEntry{
+		{Path: "src/a|b.ts", Count: 3, Errors: 1, Warnings: 2, TopRule: "no-console", TopCount: 2, Owners: []string{"@acme/web"}},
+		{Path: "src/c.ts", Count: 1, Warnings: 1, TopRule: "eqeqeq", TopCount: 1, Owners: []string{"@acme/web"}},
+	})
+
+	var buf bytes.Buffer
+	if err := WriteFilesByOwnerMarkdown(&buf, worklists, 1); err != nil {
+		t.Fatal(err)
+	}
+	expected := "# Files by Owner\n\n## @acme/web\n\nFiles: 2, issues: 4\n\n" +
+		"| # | File | Issues | Errors | Warnings | Top Rule |\n" +
+		"|--:|------|-------:|-------:|---------:|----------|\n" +
+		"| 1 | `src/a\\|b.ts` | 3 | 1 | 2 | no-console (2) |\n"
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
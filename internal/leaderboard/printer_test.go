@@ -0,0 +1,698 @@
+package leaderboard
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xeon-zolt/codecompass/internal/history"
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+var goldenNow = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+var goldenRepos = []types.RepoEntry{
+	{Repo: "../api", Files: 30, LinesOfCode: 2400, Issues: 17, IssuesPerKLOC: 7.08, Debt: 3, Authors: 4, LinesCovered: 1800, LinesTotal: 2000},
+	{Repo: "https://example.com/acme/web.git", Files: 52, LinesOfCode: 5100, Issues: 42, IssuesPerKLOC: 8.24, Debt: 11, Authors: 6},
+	{Repo: "../tools", Files: 8, LinesOfCode: 600, Debt: 1, Authors: 1, LinesCovered: 150, LinesTotal: 300},
+}
+
+// goldenDashboard is a report with every leaderboard the dashboard shows.
+var goldenDashboard = types.Report{
+	Leaderboards: []string{"authors", "files", "rules", "debt", "coverage", "summary"},
+	Authors: []types.LeaderboardEntry{
+		{Name: "Alice", Email: "alice@example.com", Count: 9},
+		{Name: "Bob", Email: "bob@example.com", Count: 5},
+		{Name: "Carol", Email: "carol@example.com", Count: 2},
+		{Name: "Dave", Email: "dave@example.com", Count: 1},
+	},
+	Files: []types.FileLeaderboardEntry{
+		{Path: "src/components/checkout/PaymentForm.tsx", Count: 8},
+		{Path: "src/app.js", Count: 6},
+	},
+	Rules: []types.RuleLeaderboardEntry{
+		{Rule: "@typescript-eslint/no-explicit-any", Count: 7},
+		{Rule: "no-console", Count: 6},
+		{Rule: "eqeqeq", Count: 4},
+	},
+	TechnicalDebt: []types.TechnicalDebtEntry{
+		{Path: "src/app.js", TodoCount: 3, TotalDebt: 4},
+		{Path: "scripts/build.py", TodoCount: 1, TotalDebt: 1},
+	},
+	Coverage: []types.CoverageEntry{
+		{Path: "src/legacy/parser.js", CoveragePercent: 12.5},
+		{Path: "src/app.js", CoveragePercent: 81},
+	},
+	OverallCoverage: 72.5,
+	Summary:         &types.SummaryStats{TotalIssues: 17, Errors: 4, Warnings: 13, Authors: 4, Files: 2, Rules: 3},
+	Warnings:        []types.Warning{{Message: "git blame failed"}, {Message: "git blame failed"}},
+}
+
+func TestPrintersGolden(t *testing.T) {
+	tests := []struct {
+		name  string
+		print func(p *Printer)
+	}{
+		{"authors", func(p *Printer) {
+			p.PrintAuthorLeaderboard([]types.LeaderboardEntry{
+				{Name: "Alice", Email: "alice@example.com", Count: 12, Errors: 4, Warnings: 8, Files: 3, TopRule: "no-console", TopCount: 7},
+				{Name: "Bob", Email: "bob@example.com", Count: 5, Errors: 0, Warnings: 5, Files: 1, TopRule: "prefer-const", TopCount: 5},
+			}, 15)
+		}},
+		{"authors-varied-widths", func(p *Printer) {
+			// Emoji and CJK characters take two columns each
+			p.PrintAuthorLeaderboard([]types.LeaderboardEntry{
+				{Name: "🦊 Fox", Email: "fox@example.com", Count: 1024, Errors: 1000, Warnings: 24, Files: 120, TopRule: "no-undef", TopCount: 900},
+				{Name: "渡辺", Email: "watanabe@example.jp", Count: 7, Errors: 7, Files: 2, TopRule: "eqeqeq", TopCount: 7},
+				{Name: "Bartholomew Featherstonehaugh", Email: "b@x.io", Count: 3, Warnings: 3, Files: 1, TopRule: "no-console", TopCount: 3},
+			}, 15)
+		}},
+		{"authors-weighted", func(p *Printer) {
+			p.PrintAuthorLeaderboard([]types.LeaderboardEntry{
+				{Name: "Bob", Email: "bob@example.com", Count: 5, Errors: 4, Warnings: 1, Files: 2, TopRule: "no-undef", TopCount: 4, WeightedScore: 21},
+				{Name: "Alice", Email: "alice@example.com", Count: 12, Warnings: 12, Files: 3, TopRule: "no-console", TopCount: 7, WeightedScore: 12},
+			}, 15)
+		}},
+		{"authors-empty", func(p *Printer) {
+			p.PrintAuthorLeaderboard(nil, 15)
+		}},
+		{"files", func(p *Printer) {
+			p.PrintFileLeaderboard([]types.FileLeaderboardEntry{
+				{Path: "dist/bundle.min.js", Count: 200, Errors: 200, Overflow: 1843, Authors: 1, TopRule: "no-undef", TopCount: 150},
+				{Path: "src/app.js", Count: 9, Errors: 3, Warnings: 6, Authors: 2, TopRule: "no-console", TopCount: 6},
+				{Path: "src/util.js", Count: 3, Warnings: 3, Authors: 1, TopRule: "eqeqeq", TopCount: 3},
+			}, 15, false)
+		}},
+		{"files-detail", func(p *Printer) {
+			p.PrintFileLeaderboard([]types.FileLeaderboardEntry{
+				{Path: "src/app.js", Count: 9, Authors: 2, TopRule: "no-console", TopCount: 6, TopAuthors: []types.AuthorCount{
+					{Email: "alice@example.com", Count: 7},
+					{Email: "bob@example.com", Count: 2},
+				}},
+				{Path: "src/util.js", Count: 3, Authors: 1, TopRule: "eqeqeq", TopCount: 3, TopAuthors: []types.AuthorCount{
+					{Email: "bob@example.com", Count: 3},
+				}},
+			}, 15, true)
+		}},
+		{"files-owners", func(p *Printer) {
+			p.PrintFileLeaderboard([]types.FileLeaderboardEntry{
+				{Path: "src/billing/invoice.ts", Count: 9, Errors: 3, Warnings: 6, Authors: 2, TopRule: "no-console", TopCount: 6,
+					Owners: []string{"@acme/billing", "@alice"}, OwnerSource: OwnerSourceCodeowners},
+				{Path: "src/util.js", Count: 3, Warnings: 3, Authors: 1, TopRule: "eqeqeq", TopCount: 3,
+					Owners: []string{"bob@example.com"}, OwnerSource: OwnerSourceBlame},
+				{Path: "vendor/lib.js", Count: 1, Errors: 1, Authors: 1, TopRule: "no-undef", TopCount: 1, OwnerSource: OwnerSourceNone},
+			}, 15, false)
+		}},
+		{"files-by-owner", func(p *Printer) {
+			p.PrintFilesByOwner(GroupFilesByOwner([]types.FileLeaderboardEntry{
+				{Path: "src/billing/invoice.ts", Count: 9, Errors: 3, Warnings: 6, TopRule: "no-console", TopCount: 6, Owners: []string{"@acme/billing"}},
+				{Path: "src/billing/tax.ts", Count: 4, Errors: 4, TopRule: "no-undef", TopCount: 4, Owners: []string{"@acme/billing"}},
+				{Path: "src/util.js", Count: 3, Warnings: 3, TopRule: "eqeqeq", TopCount: 3, Owners: []string{"bob@example.com"}},
+				{Path: "vendor/lib.js", Count: 1, Errors: 1, TopRule: "no-undef", TopCount: 1},
+			}), 15)
+		}},
+		{"files-by-owner-empty", func(p *Printer) {
+			p.PrintFilesByOwner(nil, 15)
+		}},
+		{"rules", func(p *Printer) {
+			p.PrintRuleLeaderboard([]types.RuleLeaderboardEntry{
+				{Rule: "no-console", Count: 7, Fixable: 3, Authors: 1, Files: 2},
+				{Rule: "prefer-const", Count: 5, Authors: 2, Files: 1},
+			}, 1)
+		}},
+		{"rules-verbose", func(p *Printer) {
+			p.SetVerbose(true)
+			p.PrintRuleLeaderboard([]types.RuleLeaderboardEntry{
+				{Rule: "@typescript-eslint/no-floating-promises", Count: 842, Authors: 9, Files: 120, DocURL: "https://typescript-eslint.io/rules/no-floating-promises"},
+				{Rule: "team/no-foo", Count: 5, Authors: 2, Files: 1},
+			}, 15)
+		}},
+		{"loc", func(p *Printer) {
+			p.PrintLinesOfCodeLeaderboard([]types.LinesOfCodeEntry{
+				{Path: "src/app.js", Lines: 1200, Size: 48 * 1024},
+				{Path: "README.md", Lines: 40, Size: 900},
+				{Path: "notes.md", Lines: 12, Size: 300, Untracked: true},
+			}, 15)
+		}},
+		{"loc-varied-widths", func(p *Printer) {
+			p.PrintLinesOfCodeLeaderboard([]types.LinesOfCodeEntry{
+				{Path: "packages/web/src/components/dashboard/widgets/ChartWidget.tsx", Lines: 98765, Size: 3 * 1024 * 1024},
+				{Path: "docs/🚀-launch.md", Lines: 410, Size: 12 * 1024, Untracked: true},
+				{Path: "文档/说明.md", Lines: 12, Size: 300},
+				{Path: "a.go", Lines: 1, Size: 10},
+			}, 15)
+		}},
+		{"loc-path-truncate", func(p *Printer) {
+			p.SetPathStyle(PathTruncate)
+			p.PrintLinesOfCodeLeaderboard([]types.LinesOfCodeEntry{
+				{Path: "packages/web/src/components/dashboard/widgets/ChartWidget.tsx", Lines: 98765, Size: 3 * 1024 * 1024},
+				{Path: "docs/🚀-launch.md", Lines: 410, Size: 12 * 1024, Untracked: true},
+				{Path: "a.go", Lines: 1, Size: 10},
+			}, 15)
+		}},
+		{"files-path-basename", func(p *Printer) {
+			p.SetPathStyle(PathBasename)
+			p.SetVerbose(true)
+			p.PrintFileLeaderboard([]types.FileLeaderboardEntry{
+				{Path: "src/components/app.js", Count: 9, Authors: 2, TopRule: "no-console", TopCount: 6},
+				{Path: "util.js", Count: 3, Authors: 1, TopRule: "eqeqeq", TopCount: 3},
+			}, 15, false)
+		}},
+		{"commits", func(p *Printer) {
+			p.PrintCommitCountLeaderboard([]types.CommitCountEntry{
+				{Name: "Alice", Email: "alice@example.com", Commits: 42, FirstCommit: goldenNow.AddDate(0, -3, 0), LastCommit: goldenNow},
+				{Name: "Bob", Email: "bob@example.com", Commits: 1, FirstCommit: goldenNow, LastCommit: goldenNow},
+			}, 15)
+		}},
+		{"commits-co-authored", func(p *Printer) {
+			p.PrintCommitCountLeaderboard([]types.CommitCountEntry{
+				{Name: "Alice", Email: "alice@example.com", Commits: 4, CoAuthored: 2, Credit: 3, FirstCommit: goldenNow.AddDate(0, -1, 0), LastCommit: goldenNow},
+				{Name: "Bob", Email: "bob@example.com", Commits: 2, Credit: 1.5, FirstCommit: goldenNow, LastCommit: goldenNow},
+			}, 15)
+		}},
+		{"recent", func(p *Printer) {
+			p.PrintRecentContributorsLeaderboard([]types.RecentContributorEntry{
+				{Name: "Alice", Email: "alice@example.com", RecentCommits: 8, LastCommit: goldenNow.Add(-2 * time.Hour)},
+				{Name: "Bob", Email: "bob@example.com", RecentCommits: 2, LastCommit: goldenNow.AddDate(0, 0, -9)},
+			}, 15)
+		}},
+		{"churn", func(p *Printer) {
+			p.PrintCodeChurnLeaderboard([]types.ChurnEntry{
+				{Path: "src/app.js", Changes: 14, AddedLines: 300, DeletedLines: 120, NetLines: 180, FirstCommit: goldenNow.AddDate(0, -4, 0), ChurnRate: 3.5},
+				{Path: "src/old.js", Changes: 3, AddedLines: 10, DeletedLines: 60, NetLines: -50, FirstCommit: goldenNow.AddDate(-2, 0, 0), ChurnRate: 0.125},
+				{Path: "src/same.js", Changes: 2, AddedLines: 5, DeletedLines: 5, NetLines: 0},
+			}, 15)
+		}},
+		{"bugs", func(p *Printer) {
+			p.PrintBugDensityLeaderboard([]types.BugDensityEntry{
+				{Path: "src/app.js", BugFixes: 4, TotalCommits: 10, BugRatio: 40},
+				{Path: "src/util.js", BugFixes: 1, TotalCommits: 5, BugRatio: 20},
+				{Path: "src/ok.js", BugFixes: 0, TotalCommits: 6, BugRatio: 0},
+			}, 15)
+		}},
+		{"debt", func(p *Printer) {
+			p.PrintTechnicalDebtLeaderboard([]types.TechnicalDebtEntry{
+				{Path: "src/app.js", TodoCount: 3, FixmeCount: 1, HackCount: 1, TotalDebt: 5},
+				{Path: "src/util.js", TodoCount: 1, TotalDebt: 1},
+			}, 15)
+		}},
+		{"coverage", func(p *Printer) {
+			p.PrintCodeCoverageLeaderboard([]types.CoverageEntry{
+				{Path: "src/app.js", LinesCovered: 40, LinesTotal: 100, CoveragePercent: 40, FunctionsCovered: 2, FunctionsTotal: 4},
+				{Path: "src/util.js", LinesCovered: 70, LinesTotal: 100, CoveragePercent: 70},
+				{Path: "src/math.js", LinesCovered: 95, LinesTotal: 100, CoveragePercent: 95, BranchesCovered: 9, BranchesTotal: 10},
+			}, 68.3, 80, nil, 2)
+		}},
+		{"coverage-stale", func(p *Printer) {
+			p.PrintCodeCoverageLeaderboard([]types.CoverageEntry{
+				{Path: "src/app.js", LinesCovered: 40, LinesTotal: 100, CoveragePercent: 40},
+			}, 40, 80, &types.CoverageFreshness{
+				Report: "coverage/lcov.info", ModifiedAt: goldenNow.AddDate(0, 0, -23), LastCommit: goldenNow,
+				StaleDays: 23, MissingFiles: 2,
+			}, 15)
+		}},
+		{"spellcheck", func(p *Printer) {
+			p.PrintSpellCheckLeaderboard([]types.SpellCheckEntry{
+				{
+					Path: "docs/guide.md", MisspelledWords: 3, TotalWords: 20, ErrorRate: 15,
+					TopMisspellings: map[string]int{"recieve": 2, "teh": 1},
+					Issues: []types.SpellIssue{
+						{Word: "recieve", Line: 4, Type: "comment", Author: "Alice", Suggestions: []string{"receive"}},
+						{Word: "teh", Line: 9, Type: "string", Author: "unknown"},
+					},
+				},
+			}, map[string]*types.SpellCheckAuthorStats{
+				"alice@example.com": {Name: "Alice", Email: "alice@example.com", TotalErrors: 2, TotalWords: 16, Files: map[string]int{"docs/guide.md": 2}, CommonMistakes: map[string]int{"recieve": 2}},
+			}, 15)
+		}},
+		{"spellcheck-rate", func(p *Printer) {
+			// Bob misspells the most words, but writes far more of them
+			p.SetSpellCheckSort(SpellCheckSortRate)
+			p.PrintSpellCheckLeaderboard([]types.SpellCheckEntry{
+				{Path: "docs/guide.md", MisspelledWords: 8, TotalWords: 620, ErrorRate: 1.3, TopMisspellings: map[string]int{"recieve": 5, "teh": 3}},
+			}, map[string]*types.SpellCheckAuthorStats{
+				"alice@example.com": {Name: "Alice", Email: "alice@example.com", TotalErrors: 2, TotalWords: 20, Files: map[string]int{"docs/guide.md": 2}, CommonMistakes: map[string]int{"teh": 2}},
+				"bob@example.com":   {Name: "Bob", Email: "bob@example.com", TotalErrors: 5, TotalWords: 500, Files: map[string]int{"docs/guide.md": 5}, CommonMistakes: map[string]int{"recieve": 5}},
+				"carol@example.com": {Name: "Carol", Email: "carol@example.com", TotalErrors: 1, Files: map[string]int{"docs/guide.md": 1}, CommonMistakes: map[string]int{"teh": 1}},
+			}, 15)
+		}},
+		{"encoding", func(p *Printer) {
+			p.PrintEncodingLeaderboard([]types.EncodingEntry{
+				{Path: "docs/legacy.txt", LineEnding: LineEndingMixed, HasBOM: true, Encoding: EncodingNonUTF8},
+				{Path: "scripts/build.bat", LineEnding: LineEndingCRLF, Encoding: EncodingUTF8},
+				{Path: "src/app.js", LineEnding: LineEndingLF, HasBOM: true, Encoding: EncodingUTF8},
+			}, 15)
+		}},
+		{"encoding-empty", func(p *Printer) {
+			p.PrintEncodingLeaderboard(nil, 15)
+		}},
+		{"long-functions", func(p *Printer) {
+			p.PrintLongFunctionLeaderboard([]types.LongFunctionEntry{
+				{Path: "internal/server/server.go", FunctionName: "Server.Handle", StartLine: 42, Lines: 180},
+				{Path: "src/store.ts", FunctionName: "save", StartLine: 7, Lines: 64},
+			}, 15)
+		}},
+		{"long-functions-empty", func(p *Printer) {
+			p.PrintLongFunctionLeaderboard(nil, 15)
+		}},
+		{"format-drift", func(p *Printer) {
+			p.PrintFormatDriftLeaderboard(types.FormatDriftStats{
+				Files: []types.FormatDriftEntry{
+					{Path: "src/app.py", Lines: 42, FormatLines: 38, ImportLines: 4},
+					{Path: "lib/util.py", Lines: 3, FormatLines: 3},
+				},
+				Authors: []types.FormatDriftAuthorEntry{
+					{Name: "Alice", Email: "alice@example.com", Lines: 30, Files: 2},
+					{Name: "Bob", Email: "bob@example.com", Lines: 15, Files: 1},
+				},
+			}, 15)
+		}},
+		{"format-drift-empty", func(p *Printer) {
+			p.PrintFormatDriftLeaderboard(types.FormatDriftStats{}, 15)
+		}},
+		{"forge", func(p *Printer) {
+			p.PrintForgeStats(types.ForgeStats{
+				Forge: "github", Repo: "owner/repo", Since: goldenNow.AddDate(0, 0, -90), PullRequests: 5,
+				Authors: []types.PullRequestAuthorEntry{
+					{Login: "alice", Merged: 3, AvgSize: 152.4, AvgTimeToMerge: 26*time.Hour + 30*time.Minute},
+					{Login: "bob", Merged: 2, AvgSize: 12, AvgTimeToMerge: 45 * time.Minute},
+				},
+				Reviewers: []types.ReviewerEntry{
+					{Login: "bob", Reviews: 4, Approvals: 3, PullRequests: 3},
+					{Login: "alice", Reviews: 1, Approvals: 1, PullRequests: 1},
+				},
+			}, 15)
+		}},
+		{"forge-empty", func(p *Printer) {
+			p.PrintForgeStats(types.ForgeStats{Forge: "github", Repo: "owner/repo", Since: goldenNow.AddDate(0, 0, -90)}, 15)
+		}},
+		{"lead-time", func(p *Printer) {
+			p.PrintLeadTimeStats(types.LeadTimeStats{
+				Since: goldenNow.AddDate(0, 0, -14), Merges: 3, Median: 5 * time.Hour, P75: 26 * time.Hour, P90: 50 * time.Hour, MergesPerWeek: 1.5,
+				Weeks: []types.WeeklyMerges{
+					{Week: time.Date(2024, 5, 13, 0, 0, 0, 0, time.UTC), Merges: 2},
+					{Week: time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC), Merges: 0},
+					{Week: time.Date(2024, 5, 27, 0, 0, 0, 0, time.UTC), Merges: 1},
+				},
+				Authors: []types.LeadTimeAuthorEntry{
+					{Rank: 1, Name: "Bob", Email: "bob@example.com", Merges: 2, Median: 5 * time.Hour, P90: 50 * time.Hour},
+					{Rank: 2, Name: "Alice", Email: "alice@example.com", Merges: 1, Median: 26 * time.Hour, P90: 26 * time.Hour},
+				},
+			}, 15)
+		}},
+		{"lead-time-empty", func(p *Printer) {
+			p.PrintLeadTimeStats(types.LeadTimeStats{Since: goldenNow.AddDate(0, 0, -90)}, 15)
+		}},
+		{"changelog", func(p *Printer) {
+			p.PrintChangelogReadiness(types.ChangelogStats{
+				Since: goldenNow.AddDate(0, 0, -90), Commits: 5, ConventionalPercent: 60,
+				Types: map[string]int{"feat": 1, "fix": 1, "chore": 1, "docs": 0, "refactor": 0, "other": 2},
+				Authors: []types.ChangelogAuthorEntry{
+					{Rank: 1, Name: "Alice", Email: "alice@example.com", Commits: 3, ConventionalPercent: 100, Types: map[string]int{"feat": 1, "fix": 1, "chore": 1}},
+					{Rank: 2, Name: "Bob", Email: "bob@example.com", Commits: 2, Types: map[string]int{"other": 2}},
+				},
+				Invisible: []types.ChangelogCommit{
+					{Hash: "9f8e7d6c5b4a", Name: "Bob", Subject: "Update stuff"},
+					{Hash: "1a2b3c4d5e6f", Name: "Bob", Subject: "wip"},
+					{Hash: "5e6f7a8b9c0d", Name: "Bob", Subject: "more fixes"},
+				},
+				Breaking: []types.ChangelogCommit{
+					{Hash: "abcdef012345", Name: "Alice", Subject: "feat(api)!: drop v1 endpoints", Type: "feat", Scope: "api", Note: "drop v1 endpoints"},
+					{Hash: "0123456789ab", Name: "Alice", Subject: "fix: handle nil config", Type: "fix", Note: "Load now returns an error\n\nCallers must check it."},
+				},
+			}, 2)
+		}},
+		{"compare", func(p *Printer) {
+			p.PrintComparison("main", "feature", []types.MetricDelta{
+				{Metric: "coverage", A: 81.3, B: 79.5, Delta: -1.8, HigherIsBetter: true},
+				{Metric: "issues", A: 120, B: 96, Delta: -24},
+				{Metric: "debt", A: 14, B: 14},
+				{Metric: "loc", A: 5000, Missing: true},
+			}, []types.ToolVersionChange{{Tool: "eslint", A: "8.57.0", B: "9.1.0"}})
+		}},
+		{"deletions", func(p *Printer) {
+			p.PrintDeletionStats(types.DeletionStats{
+				Since: goldenNow.AddDate(0, 0, -90), Commits: 6, AddedLines: 310, DeletedLines: 1450,
+				Authors: []types.DeletionAuthorEntry{
+					{Rank: 1, Name: "Alice", Email: "alice@example.com", Commits: 3, AddedLines: 40, DeletedLines: 1240, NetRemoved: 1200},
+					{Rank: 2, Name: "Bob", Email: "bob@example.com", Commits: 2, AddedLines: 30, DeletedLines: 150, NetRemoved: 120},
+				},
+				Cleanups: []types.CleanupCommit{
+					{Hash: "9f8e7d6c5b4a", Name: "Alice", AddedLines: 12, DeletedLines: 1012, NetRemoved: 1000, Subject: "Remove the legacy parser"},
+					{Hash: "1a2b3c4d5e6f", Name: "Bob", DeletedLines: 150, NetRemoved: 150, Subject: "Drop unused feature flags"},
+					{Hash: "5e6f7a8b9c0d", Name: "Alice", AddedLines: 10, DeletedLines: 90, NetRemoved: 80, Subject: "Inline the config helpers"},
+				},
+			}, 2)
+		}},
+		{"deletions-growth", func(p *Printer) {
+			p.PrintDeletionStats(types.DeletionStats{Since: goldenNow.AddDate(0, 0, -90), Commits: 2, AddedLines: 120, DeletedLines: 15}, 15)
+		}},
+		{"new-contributors", func(p *Printer) {
+			p.PrintNewContributors(types.NewContributorStats{
+				Since: goldenNow.AddDate(0, 0, -90), GracePeriodDays: 30,
+				Authors: []types.NewContributorEntry{
+					{Rank: 1, Name: "Carol", Email: "carol@example.com", FirstCommit: goldenNow.AddDate(0, 0, -5), Commits: 2, FilesTouched: 3, GracedIssues: 4},
+					{Rank: 2, Name: "Dave", Email: "dave@example.com", FirstCommit: goldenNow.AddDate(0, 0, -40), Commits: 12, FilesTouched: 18},
+				},
+			}, 10)
+		}},
+		{"new-contributors-empty", func(p *Printer) {
+			p.PrintNewContributors(types.NewContributorStats{Since: goldenNow.AddDate(0, 0, -90)}, 10)
+		}},
+		{"snapshot-diff", func(p *Printer) {
+			p.PrintSnapshotDiffs("20260110_093000", "20260117_093000 (9f8e7d6)", []types.SnapshotDiff{{
+				Leaderboard: "files", Metric: "Issues",
+				Worsened:    []types.SnapshotChange{{Key: "src/api.ts", A: 4, B: 9, Delta: 5}},
+				Improved:    []types.SnapshotChange{{Key: "src/app.ts", A: 12, B: 3, Delta: -9}},
+				Appeared:    []types.SnapshotChange{{Key: "src/new.ts", B: 2, Delta: 2}},
+				Disappeared: []types.SnapshotChange{{Key: "src/old.ts", A: 7, Delta: -7}},
+			}, {
+				Leaderboard: "coverage", Metric: "CoveragePercent", HigherIsBetter: true,
+			}}, []types.ToolVersionChange{{Tool: "eslint", A: "8.57.0", B: "9.1.0"}}, 15)
+		}},
+		{"changelog-empty", func(p *Printer) {
+			p.PrintChangelogReadiness(types.ChangelogStats{Since: goldenNow.AddDate(0, 0, -90)}, 15)
+		}},
+		{"timezones", func(p *Printer) {
+			p.PrintTimezoneStats(types.TimezoneStats{
+				MinCommits: 10, Commits: 70, WeekendShare: 10, OffHoursShare: 30,
+				Offsets: []types.UTCOffsetEntry{
+					{Offset: -8 * 3600, Authors: 1, Commits: 20},
+					{Offset: 5*3600 + 1800, Authors: 1, Commits: 50},
+				},
+				Authors: []types.TimezoneAuthorEntry{
+					{Rank: 1, Name: "Priya", Email: "priya@example.com", Commits: 50, Offset: 5*3600 + 1800, WeekendShare: 4, OffHoursShare: 20},
+					{Rank: 2, Name: "Sam", Email: "sam@example.com", Commits: 20, Offset: -8 * 3600, WeekendShare: 25, OffHoursShare: 55},
+				},
+			}, 15)
+		}},
+		{"timezones-empty", func(p *Printer) {
+			p.PrintTimezoneStats(types.TimezoneStats{MinCommits: 10}, 15)
+		}},
+		{"vulns", func(p *Printer) {
+			p.PrintVulnerabilityLeaderboard([]types.VulnEntry{
+				{Ecosystem: "npm", Package: "lodash", InstalledVersion: "4.17.11", Severity: "critical", AdvisoryID: "GHSA-jf85-cpcp-j695", FixedIn: "4.17.21", Direct: []string{"lodash"}},
+				{Ecosystem: "npm", Package: "lodash", InstalledVersion: "4.17.11", Severity: "high", AdvisoryID: "GHSA-35jh-r3h4-6jhm", FixedIn: "4.17.21", Direct: []string{"lodash"}},
+				{Ecosystem: "npm", Package: "qs", InstalledVersion: "6.2.3", Severity: "high", AdvisoryID: "GHSA-hrpp-h998-j3pp", Direct: []string{"express"}},
+				{Ecosystem: "pypi", Package: "flask", InstalledVersion: "0.5", Severity: "unknown", AdvisoryID: "PYSEC-2019-179", FixedIn: "1.0", Direct: []string{"flask"}},
+			}, 15)
+		}},
+		{"vulns-empty", func(p *Printer) {
+			p.PrintVulnerabilityLeaderboard(nil, 15)
+		}},
+		{"deps", func(p *Printer) {
+			p.PrintDependencyLeaderboard([]types.DependencyEntry{
+				{Rank: 1, Path: "requirements.txt", Ecosystem: "pypi", Direct: 6, Pinned: 2, Ranged: 4, PinnedPercent: 33.33,
+					RangedPackages: []string{"flask-login", "numpy", "pytest", "requests"}},
+				{Rank: 2, Path: "web/package-lock.json", Ecosystem: "npm", Direct: 5, Locked: 1284, Pinned: 2, Ranged: 2, PinnedPercent: 40,
+					RangedPackages: []string{"express", "jest"}},
+				{Rank: 3, Path: "docs/yarn.lock", Ecosystem: "npm", Locked: 212},
+			}, 15)
+		}},
+		{"deps-empty", func(p *Printer) {
+			p.PrintDependencyLeaderboard(nil, 15)
+		}},
+		{"hotspots", func(p *Printer) {
+			p.PrintHotspotLeaderboard([]types.HotspotEntry{
+				{Rank: 1, Path: "src/checkout/cart.ts", Changes: 148, Issues: 37, HotspotScore: 5476},
+				{Rank: 2, Path: "src/app.js", Changes: 12, Issues: 9, HotspotScore: 108},
+			}, 15)
+		}},
+		{"hotspots-empty", func(p *Printer) {
+			p.PrintHotspotLeaderboard(nil, 15)
+		}},
+		{"run-log", func(p *Printer) {
+			// Times are local, as the log is read where it was written
+			p.PrintRunLog([]history.RunRecord{
+				{Time: time.Date(2024, 5, 30, 9, 0, 0, 0, time.Local), Duration: 1500 * time.Millisecond, Head: "0123456789abcdef",
+					ConfigFingerprint: "9f86d081884c7d65", Flags: map[string]string{"debt": "true", "top": "10"}, Leaderboards: map[string]int{"debt": 12, "loc": 40}},
+				{Time: time.Date(2024, 5, 31, 9, 0, 0, 0, time.Local), Duration: 52 * time.Millisecond, Head: "fedcba9876543210", Dirty: true,
+					ConfigFingerprint: "9f86d081884c7d65", Flags: map[string]string{"require-coverage": "true"}, Failures: map[string]string{"coverage": "no coverage"},
+					Warnings: 3, ExitStatus: 5, Error: "Coverage is required: no coverage report found"},
+			}, 15)
+		}},
+		{"run-log-empty", func(p *Printer) {
+			p.PrintRunLog(nil, 15)
+		}},
+		{"suppression", func(p *Printer) {
+			p.PrintSuppression(types.Suppression{
+				File: ".codecompass-baseline.json", Issues: 120, DebtMarkers: 34, Misspellings: 2,
+				Stale: []types.StaleSuppression{
+					{Kind: "debt", Path: "src/app.js", Rule: "TODO"},
+					{Kind: "issue", Path: "src/old.js", Rule: "no-unused-vars", Deleted: true},
+					{Kind: "spelling", Path: "README.md", Rule: "teh"},
+				},
+			}, 2)
+		}},
+		{"rule-plugins", func(p *Printer) {
+			p.PrintRulePluginLeaderboard([]types.RulePluginEntry{
+				{Rank: 1, Plugin: "@typescript-eslint", Count: 42, DistinctRules: 5},
+				{Rank: 2, Plugin: "core", Count: 7, DistinctRules: 3},
+				{Rank: 3, Plugin: "react", Count: 2, DistinctRules: 1},
+			}, 15)
+		}},
+		{"rule-groups", func(p *Printer) {
+			p.PrintRuleGroupLeaderboard([]types.RuleGroupEntry{
+				{Rank: 1, Group: "style", Count: 3108, DistinctRules: 12},
+				{Rank: 2, Group: "correctness", Count: 412, DistinctRules: 5},
+				{Rank: 3, Group: UngroupedRules, Count: 9, DistinctRules: 2},
+			}, 15)
+		}},
+		{"rule-author-matrix", func(p *Printer) {
+			p.PrintRuleAuthorMatrix([]types.RuleAuthorCell{
+				{Rule: "@typescript-eslint/no-floating-promises", Name: "Alice", Email: "alice@example.com", Count: 9},
+				{Rule: "no-console", Name: "Bob", Email: "bob@example.com", Count: 6},
+				{Rule: "no-console", Name: "Alice", Email: "alice@example.com", Count: 3},
+				{Rule: "eqeqeq", Name: "Carol", Email: "carol@example.com", Count: 1},
+			}, 15)
+		}},
+		{"rule-author-matrix-empty", func(p *Printer) {
+			p.PrintRuleAuthorMatrix(nil, 15)
+		}},
+		{"lfs", func(p *Printer) {
+			p.PrintLFSReport(types.LFSStats{
+				Tracked: 12, Pointers: 10, Raw: 2,
+				Patterns: []types.LFSPatternEntry{
+					{Pattern: "*.psd", Files: 5, Pointers: 5},
+					{Pattern: "*.png", Files: 7, Pointers: 5, Raw: 2},
+				},
+				Violations: []types.LFSViolation{
+					{Path: "assets/hero.png", Size: 12 * 1024 * 1024, Commit: "a1b2c3d4e5f6", Author: "Bob", Email: "bob@example.com", Date: goldenNow.AddDate(0, 0, -3)},
+					{Path: "assets/icon.png", Size: 2048},
+				},
+			}, 15)
+		}},
+		{"lfs-empty", func(p *Printer) {
+			p.PrintLFSReport(types.LFSStats{}, 15)
+		}},
+		{"workspaces", func(p *Printer) {
+			p.PrintWorkspaceLeaderboard([]types.WorkspaceEntry{
+				{Name: ".", Path: ".", Files: 4, LinesOfCode: 120, Issues: 1},
+				{Name: "@acme/api", Path: "packages/api", Kind: "npm", Files: 30, LinesOfCode: 2400, Issues: 17, Debt: 3, LinesCovered: 1800, LinesTotal: 2000},
+				{Name: "@acme/web", Path: "packages/web", Kind: "npm", Files: 52, LinesOfCode: 5100, Issues: 42, Debt: 11},
+			})
+		}},
+		{"workspaces-empty", func(p *Printer) {
+			p.PrintWorkspaceLeaderboard(nil)
+		}},
+		{"authors-multi-repo", func(p *Printer) {
+			p.PrintAuthorLeaderboard([]types.LeaderboardEntry{
+				{Name: "Alice", Email: "alice@example.com", Count: 19, Errors: 6, Warnings: 13, Files: 5, TopRule: "no-console", TopCount: 7, Repos: []string{"api", "web"}},
+				{Name: "Bob", Email: "bob@example.com", Count: 5, Warnings: 5, Files: 1, TopRule: "prefer-const", TopCount: 5, Repos: []string{"web"}},
+			}, 15)
+		}},
+		{"repos-issues", func(p *Printer) {
+			p.PrintRepoLeaderboard(goldenRepos, RepoSortIssueDensity, 15)
+		}},
+		{"repos-coverage", func(p *Printer) {
+			p.PrintRepoLeaderboard(goldenRepos, RepoSortCoverage, 15)
+		}},
+		{"repos-debt", func(p *Printer) {
+			p.PrintRepoLeaderboard(goldenRepos, RepoSortDebt, 2)
+		}},
+		{"repos-summary", func(p *Printer) {
+			p.PrintRepoSummary(goldenRepos)
+		}},
+		{"repos-empty", func(p *Printer) {
+			p.PrintRepoLeaderboard(nil, RepoSortIssueDensity, 15)
+		}},
+		{"repo-failures", func(p *Printer) {
+			p.PrintRepoFailures(map[string]string{
+				"https://example.com/acme/legacy.git": "failed to clone https://example.com/acme/legacy.git: exit status 128",
+				"../missing":                          "not a git repository: ../missing",
+			})
+		}},
+		{"summary", func(p *Printer) {
+			p.PrintSummaryStats(types.SummaryStats{
+				TotalIssues: 17, Errors: 4, Warnings: 13, Authors: 2, Files: 2, Rules: 3,
+				UnparseableFiles: 1, AvgIssuesPerAuthor: 8.5, AvgIssuesPerFile: 8.5,
+			})
+		}},
+		{"languages", func(p *Printer) {
+			p.PrintLanguageComposition([]types.LanguageEntry{
+				{Name: "TypeScript", Files: 40, Lines: 6100, Percent: 61},
+				{Name: "Python", Files: 12, Lines: 2800, Percent: 28},
+				{Name: "Shell", Files: 3, Lines: 40, Percent: 0.4},
+				{Name: "Other", Files: 9, Lines: 1060, Percent: 10.6},
+			})
+		}},
+		{"issue-baseline", func(p *Printer) {
+			p.PrintIssueBaseline(types.IssueBaseline{Reference: ".codecompass/history/baseline.txt", New: 3, Existing: 14, Fixed: 2})
+		}},
+		{"report-card", func(p *Printer) {
+			p.PrintReportCard(types.ReportCard{
+				Categories: []types.CategoryGrade{
+					{Category: "coverage", Score: 80, Weight: 25, Grade: "B", Detail: "80.0% covered"},
+					{Category: "bus-factor", Score: 25, Weight: 25, Grade: "F", Detail: "1 author(s) made more than half the commits"},
+				},
+				Score: 52.5,
+				Grade: "F",
+			})
+		}},
+		{"score", func(p *Printer) {
+			p.PrintScore(types.ScoreStats{
+				Score: 71.2,
+				Components: []types.ScoreComponent{
+					{Component: "issues", Score: 84, Weight: 35.3, Detail: "1.60 issues per 100 lines"},
+					{Component: "debt", Score: 80, Weight: 17.6, Detail: "0.40 debt markers per 100 lines"},
+					{Component: "churn", Score: 90, Weight: 17.6, Detail: "5.0 changes per file"},
+					{Component: "complexity", Score: 35, Weight: 29.4, Detail: "65.0% of function code in long functions"},
+				},
+				Files: []types.FileScore{
+					{Rank: 1, Path: "src/server.go", Score: 42.5, Components: map[string]float64{"issues": 80, "debt": 50, "churn": 80, "complexity": 0}},
+					{Rank: 2, Path: "scripts/build.py", Score: 73.3, Components: map[string]float64{"issues": 60, "debt": 100, "churn": 70}},
+					{Rank: 3, Path: "src/util.go", Score: 100, Components: map[string]float64{"issues": 100, "debt": 100, "churn": 100, "complexity": 100}},
+				},
+			}, 2)
+		}},
+		{"score-empty", func(p *Printer) {
+			p.PrintScore(types.ScoreStats{}, 15)
+		}},
+		{"dashboard", func(p *Printer) {
+			p.SetWidth(100)
+			p.PrintDashboard(&goldenDashboard, &types.RunTrend{
+				Since: goldenNow.AddDate(0, 0, -2),
+				Deltas: []types.MetricDelta{
+					{Metric: "issues", A: 20, B: 17, Delta: -3},
+					{Metric: "errors", A: 3, B: 4, Delta: 1},
+					{Metric: "warnings", A: 17, B: 13, Delta: -4},
+					{Metric: "debt", Missing: true},
+					{Metric: "coverage", A: 70, B: 72.5, Delta: 2.5, HigherIsBetter: true},
+				},
+			}, 80)
+		}},
+		{"dashboard-narrow", func(p *Printer) {
+			p.SetWidth(60)
+			p.PrintDashboard(&goldenDashboard, nil, 80)
+		}},
+		{"dashboard-empty", func(p *Printer) {
+			p.SetWidth(100)
+			p.PrintDashboard(&types.Report{
+				Leaderboards: []string{"authors", "files", "rules", "summary"},
+				Summary:      &types.SummaryStats{},
+				Failures:     map[string]string{"rules": "eslint failed"},
+			}, nil, 80)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			p := NewPlainPrinter(&buf)
+			p.now = func() time.Time { return goldenNow }
+			tt.print(p)
+
+			golden := filepath.Join("testdata", tt.name+".golden")
+			if *update {
+				if err := os.MkdirAll("testdata", 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(golden, buf.Bytes(), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			expected, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("Failed to read %s (run go test -update to create it): %v", golden, err)
+			}
+			if got := buf.String(); got != string(expected) {
+				t.Errorf("Output does not match %s\ngot:\n%s\nexpected:\n%s", golden, got, expected)
+			}
+		})
+	}
+}
+
+func TestPrintCodeCoverageLeaderboardKeepsEntryOrder(t *testing.T) {
+	entries := []types.CoverageEntry{
+		{Path: "src/app.js", CoveragePercent: 40},
+		{Path: "src/util.js", CoveragePercent: 70},
+		{Path: "src/math.js", CoveragePercent: 95},
+	}
+	original := append([]types.CoverageEntry{}, entries...)
+
+	// topN below the entry count also prints the highest coverage files
+	NewPlainPrinter(&bytes.Buffer{}).PrintCodeCoverageLeaderboard(entries, 68.3, 80, nil, 2)
+
+	for i := range entries {
+		if entries[i] != original[i] {
+			t.Fatalf("Expected printing to leave entries untouched, but got %v", entries)
+		}
+	}
+}
+
+func TestPrinterGroupNumbers(t *testing.T) {
+	t.Setenv("LC_ALL", "en_US.UTF-8")
+	entries := []types.ChurnEntry{{Path: "src/app.js", Changes: 1234, AddedLines: 1234567, DeletedLines: 999, NetLines: 1233568}}
+
+	var raw, grouped bytes.Buffer
+	NewPlainPrinter(&raw).PrintCodeChurnLeaderboard(entries, 15)
+	p := NewPlainPrinter(&grouped)
+	p.SetGroupNumbers(true)
+	p.PrintCodeChurnLeaderboard(entries, 15)
+
+	if !strings.Contains(raw.String(), "1234567") {
+		t.Errorf("Expected counts ungrouped by default, but got\n%s", raw.String())
+	}
+	for _, count := range []string{"1,234", "1,234,567", "999", "+1,233,568"} {
+		if !strings.Contains(grouped.String(), count) {
+			t.Errorf("Expected %s in\n%s", count, grouped.String())
+		}
+	}
+}
+
+func TestPrinterMaxRows(t *testing.T) {
+	var entries []types.FileLeaderboardEntry
+	for i := range 3412 {
+		entries = append(entries, types.FileLeaderboardEntry{Path: fmt.Sprintf("src/file%04d.js", i), Count: 3412 - i})
+	}
+
+	var buf bytes.Buffer
+	p := NewPlainPrinter(&buf)
+	p.SetGroupNumbers(true)
+	p.SetMaxRows(200, "save the report for the full list")
+	p.PrintFileLeaderboard(entries, 500, false)
+	p.PrintRuleLeaderboard([]types.RuleLeaderboardEntry{{Rule: "no-console", Count: 3}}, 500)
+
+	output := buf.String()
+	if !strings.Contains(output, "src/file0199.js") || strings.Contains(output, "src/file0200.js") {
+		t.Errorf("Expected the first 200 files, but got\n%s", output)
+	}
+	if got := strings.Count(output, "showing"); got != 1 || !strings.Contains(output, "showing 200 of 3,412; save the report for the full list") {
+		t.Errorf("Expected one cap note under the files, but got\n%s", output)
+	}
+
+	// Leaderboards within the cap are shown whole
+	buf.Reset()
+	p.PrintFileLeaderboard(entries[:20], 500, false)
+	if strings.Contains(buf.String(), "showing") || !strings.Contains(buf.String(), "src/file0019.js") {
+		t.Errorf("Expected 20 files without a note, but got\n%s", buf.String())
+	}
+}
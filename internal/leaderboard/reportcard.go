@@ -0,0 +1,155 @@
+package leaderboard
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/xeon-zolt/codecompass/internal/config"
+	"github.com/xeon-zolt/codecompass/internal/types"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Scores fall linearly from 100 to 0 as these limits are reached.
+const (
+	maxIssuesPerFile = 10.0 // lint issues per analyzed file
+	maxDebtPerFile   = 2.0  // TODO/FIXME/HACK markers per analyzed file
+	maxSpellingRate  = 10.0 // percent of checked words misspelled
+	fullBusFactor    = 4    // authors needed to cover more than half the commits
+)
+
+// BusFactor returns the smallest number of authors who together made more
+// than half of the commits.
+func BusFactor(commits []types.CommitCountEntry) int {
+	counts := make([]int, 0, len(commits))
+	total := 0
+	for _, entry := range commits {
+		counts = append(counts, entry.Commits)
+		total += entry.Commits
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(counts)))
+
+	covered := 0
+	for i, count := range counts {
+		covered += count
+		if covered*2 > total {
+			return i + 1
+		}
+	}
+	return len(counts)
+}
+
+// GenerateReportCard grades each category with data in results on a 0-100
+// scale and combines them into an overall grade using the weights and
+// cutoffs from cfg.
+func GenerateReportCard(results types.ReportCardInput, cfg *config.Config) types.ReportCard {
+	files := math.Max(float64(results.AnalyzedFiles), 1)
+
+	var categories []types.CategoryGrade
+	add := func(category string, score float64, detail string) {
+		score = math.Max(0, math.Min(100, score))
+		categories = append(categories, types.CategoryGrade{
+			Category: category,
+			Score:    score,
+			Weight:   cfg.ReportCardWeights[category],
+			Grade:    letterGrade(score, cfg.ReportCardCutoffs),
+			Detail:   detail,
+		})
+	}
+
+	if results.HasCoverage {
+		add("coverage", results.CoveragePercent, fmt.Sprintf("%.1f%% covered", results.CoveragePercent))
+	}
+
+	if results.HasIssues {
+		perFile := float64(results.Issues) / files
+		add("issues", 100*(1-perFile/maxIssuesPerFile), fmt.Sprintf("%.2f issues per file", perFile))
+	}
+
+	if results.HasDebt {
+		perFile := float64(results.DebtItems) / files
+		add("debt", 100*(1-perFile/maxDebtPerFile), fmt.Sprintf("%.2f debt markers per file", perFile))
+	}
+
+	if results.HasSpelling && results.TotalWords > 0 {
+		rate := float64(results.MisspelledWords) / float64(results.TotalWords) * 100
+		add("spelling", 100*(1-rate/maxSpellingRate), fmt.Sprintf("%.1f%% of words misspelled", rate))
+	}
+
+	if results.HasBusFactor {
+		add("bus-factor", 100*float64(results.BusFactor)/fullBusFactor, fmt.Sprintf("%d author(s) made more than half the commits", results.BusFactor))
+	}
+
+	card := types.ReportCard{Categories: categories, Grade: "N/A"}
+
+	var weighted, totalWeight float64
+	for _, category := range categories {
+		weighted += category.Score * category.Weight
+		totalWeight += category.Weight
+	}
+	if totalWeight > 0 {
+		card.Score = weighted / totalWeight
+		card.Grade = letterGrade(card.Score, cfg.ReportCardCutoffs)
+	}
+
+	return card
+}
+
+// letterGrade maps score to A-F using the minimum scores for A, B, C and D.
+func letterGrade(score float64, cutoffs []float64) string {
+	for i, cutoff := range cutoffs {
+		if i < 4 && score >= cutoff {
+			return string(rune('A' + i))
+		}
+	}
+	return "F"
+}
+
+func (p *Printer) PrintReportCard(card types.ReportCard) {
+	fmt.Fprintln(p.w, p.titleStyle.Render("Compass Report Card"))
+
+	if len(card.Categories) == 0 {
+		fmt.Fprintln(p.w, p.cellStyle.Render("📭 Not enough data to grade this repository"))
+		return
+	}
+
+	for _, category := range card.Categories {
+		fmt.Fprintf(p.w, "  • %-11s %s %s %s\n",
+			category.Category,
+			p.gradeStyle(category.Grade).Render(category.Grade),
+			p.cellStyle.Render(fmt.Sprintf("%5.1f", category.Score)),
+			p.emailStyle.Render(category.Detail))
+	}
+
+	// Categories without data are left out of the overall grade
+	graded := make(map[string]bool)
+	for _, category := range card.Categories {
+		graded[category.Category] = true
+	}
+	var ungraded []string
+	for _, category := range config.ReportCardCategories {
+		if !graded[category] {
+			ungraded = append(ungraded, category)
+		}
+	}
+	if len(ungraded) > 0 {
+		fmt.Fprintf(p.w, "  %s\n", p.emailStyle.Render("Not graded (no data): "+strings.Join(ungraded, ", ")))
+	}
+
+	fmt.Fprintf(p.w, "\n  Overall grade: %s %s\n",
+		p.gradeStyle(card.Grade).Render(card.Grade),
+		p.cellStyle.Render(fmt.Sprintf("(%.1f/100)", card.Score)))
+}
+
+func (p *Printer) gradeStyle(grade string) lipgloss.Style {
+	switch grade {
+	case "A", "B":
+		return p.cellStyle.Foreground(lipgloss.Color("#00FF00"))
+	case "C", "D":
+		return p.warningStyle
+	default:
+		return p.errorStyle
+	}
+}
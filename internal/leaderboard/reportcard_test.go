@@ -0,0 +1,92 @@
+package leaderboard
+
+import (
+	"math"
+	"testing"
+
+	"github.com/xeon-zolt/codecompass/internal/config"
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+func TestBusFactor(t *testing.T) {
+	tests := []struct {
+		commits  []int
+		expected int
+	}{
+		{nil, 0},
+		{[]int{10}, 1},
+		{[]int{5, 5}, 2},
+		{[]int{6, 4}, 1},
+		{[]int{1, 3, 2, 2, 2}, 3},
+	}
+
+	for _, tt := range tests {
+		var entries []types.CommitCountEntry
+		for _, count := range tt.commits {
+			entries = append(entries, types.CommitCountEntry{Commits: count})
+		}
+
+		if got := BusFactor(entries); got != tt.expected {
+			t.Errorf("BusFactor(%v) = %d, expected %d", tt.commits, got, tt.expected)
+		}
+	}
+}
+
+func TestGenerateReportCard(t *testing.T) {
+	input := types.ReportCardInput{
+		AnalyzedFiles:   10,
+		HasCoverage:     true,
+		CoveragePercent: 80,
+		HasIssues:       true,
+		Issues:          20,
+		HasDebt:         true,
+		DebtItems:       5,
+		HasSpelling:     true,
+		MisspelledWords: 20,
+		TotalWords:      1000,
+		HasBusFactor:    true,
+		BusFactor:       2,
+	}
+
+	card := GenerateReportCard(input, config.NewConfig())
+
+	expected := map[string]struct {
+		score float64
+		grade string
+	}{
+		"coverage":   {80, "B"},
+		"issues":     {80, "B"},
+		"debt":       {75, "C"},
+		"spelling":   {80, "B"},
+		"bus-factor": {50, "F"},
+	}
+
+	if len(card.Categories) != len(expected) {
+		t.Fatalf("Expected %d categories, but got %+v", len(expected), card.Categories)
+	}
+	for _, category := range card.Categories {
+		want := expected[category.Category]
+		if math.Abs(category.Score-want.score) > 0.001 || category.Grade != want.grade {
+			t.Errorf("Expected %s to score %.1f (%s), but got %.1f (%s)", category.Category, want.score, want.grade, category.Score, category.Grade)
+		}
+	}
+
+	// (80*25 + 80*25 + 75*15 + 80*10 + 50*25) / 100
+	if math.Abs(card.Score-71.75) > 0.001 || card.Grade != "C" {
+		t.Errorf("Expected overall 71.75 (C), but got %.2f (%s)", card.Score, card.Grade)
+	}
+
+	// Reweighting to coverage alone lifts the grade to a B
+	cfg := config.NewConfig()
+	cfg.ReportCardWeights = map[string]float64{"coverage": 1}
+	if card := GenerateReportCard(input, cfg); card.Grade != "B" {
+		t.Errorf("Expected a coverage-only grade of B, but got %s", card.Grade)
+	}
+}
+
+func TestGenerateReportCardWithoutData(t *testing.T) {
+	card := GenerateReportCard(types.ReportCardInput{AnalyzedFiles: 3, HasSpelling: true}, config.NewConfig())
+	if len(card.Categories) != 0 || card.Grade != "N/A" {
+		t.Errorf("Expected no categories and an N/A grade, but got %+v", card)
+	}
+}
@@ -0,0 +1,72 @@
+package leaderboard
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+// RuleNamePlaceholder is replaced with the name of a rule, without its
+// plugin namespace, in rule documentation URL templates.
+const RuleNamePlaceholder = "{rule}"
+
+// ruleDocLinks are the documentation URL templates of well-known ESLint
+// plugins, by plugin namespace.
+var ruleDocLinks = map[string]string{
+	"@typescript-eslint": "https://typescript-eslint.io/rules/{rule}",
+	"react":              "https://github.com/jsx-eslint/eslint-plugin-react/blob/master/docs/rules/{rule}.md",
+	"jsx-a11y":           "https://github.com/jsx-eslint/eslint-plugin-jsx-a11y/blob/main/docs/rules/{rule}.md",
+	"import":             "https://github.com/import-js/eslint-plugin-import/blob/main/docs/rules/{rule}.md",
+}
+
+const (
+	eslintDocLink = "https://eslint.org/docs/latest/rules/{rule}"
+	ruffDocLink   = "https://docs.astral.sh/ruff/rules/?q={rule}"
+)
+
+var (
+	// ruffCode matches Ruff rule codes such as E501 or PLR0913
+	ruffCode = regexp.MustCompile(`^[A-Z]+[0-9]+$`)
+	// eslintCoreRule matches the names of ESLint core rules such as no-console
+	eslintCoreRule = regexp.MustCompile(`^[a-z]+(-[a-z]+)*$`)
+)
+
+// RuleDocURL returns the documentation URL of rule, or "" when it is not
+// known. The templates in custom, by plugin namespace as RulePlugin returns
+// it, take precedence over the built-in ones for ESLint core rules, Ruff
+// codes and well-known ESLint plugins.
+func RuleDocURL(rule string, custom map[string]string) string {
+	plugin := RulePlugin(rule)
+	name := rule
+	if plugin != CorePlugin {
+		name = rule[len(plugin)+1:]
+	}
+	if name == "" {
+		return ""
+	}
+
+	template, ok := custom[plugin]
+	if !ok {
+		switch {
+		case plugin != CorePlugin:
+			template = ruleDocLinks[plugin]
+		case ruffCode.MatchString(rule):
+			template = ruffDocLink
+		case eslintCoreRule.MatchString(rule):
+			template = eslintDocLink
+		}
+	}
+	if template == "" {
+		return ""
+	}
+	return strings.ReplaceAll(template, RuleNamePlaceholder, name)
+}
+
+// LinkRuleDocs sets the documentation URL of each rule in entries, as
+// RuleDocURL resolves it.
+func LinkRuleDocs(entries []types.RuleLeaderboardEntry, custom map[string]string) {
+	for i := range entries {
+		entries[i].DocURL = RuleDocURL(entries[i].Rule, custom)
+	}
+}
@@ -0,0 +1,47 @@
+package leaderboard
+
+import "testing"
+
+func TestRuleDocURL(t *testing.T) {
+	custom := map[string]string{
+		"team":       "https://wiki.example.com/lint/{rule}",
+		"@acme/lint": "https://acme.dev/rules/{rule}.html",
+		"react":      "https://react.example.com/{rule}",
+	}
+
+	tests := []struct {
+		rule     string
+		expected string
+	}{
+		// ESLint core rules
+		{"no-console", "https://eslint.org/docs/latest/rules/no-console"},
+		{"eqeqeq", "https://eslint.org/docs/latest/rules/eqeqeq"},
+		// typescript-eslint and other well-known plugins
+		{"@typescript-eslint/no-floating-promises", "https://typescript-eslint.io/rules/no-floating-promises"},
+		{"import/order", "https://github.com/import-js/eslint-plugin-import/blob/main/docs/rules/order.md"},
+		{"jsx-a11y/alt-text", "https://github.com/jsx-eslint/eslint-plugin-jsx-a11y/blob/main/docs/rules/alt-text.md"},
+		// Ruff codes
+		{"E501", "https://docs.astral.sh/ruff/rules/?q=E501"},
+		{"PLR0913", "https://docs.astral.sh/ruff/rules/?q=PLR0913"},
+		// Custom templates, which win over the built-in ones
+		{"team/no-foo", "https://wiki.example.com/lint/no-foo"},
+		{"@acme/lint/no-bar", "https://acme.dev/rules/no-bar.html"},
+		{"react/jsx-key", "https://react.example.com/jsx-key"},
+		// Unknown rules
+		{"unknown-plugin/no-baz", ""},
+		{"com.puppycrawl.tools.checkstyle.checks.sizes.LineLengthCheck", ""},
+		{"Generic.PHP.Syntax", ""},
+		{"team/", ""},
+		{"", ""},
+	}
+	for _, test := range tests {
+		if got := RuleDocURL(test.rule, custom); got != test.expected {
+			t.Errorf("RuleDocURL(%q) = %q; expected %q", test.rule, got, test.expected)
+		}
+	}
+
+	// Built-in templates apply without custom ones
+	if got := RuleDocURL("react/jsx-key", nil); got != "https://github.com/jsx-eslint/eslint-plugin-react/blob/master/docs/rules/jsx-key.md" {
+		t.Errorf("Expected the built-in react template, but got %q", got)
+	}
+}
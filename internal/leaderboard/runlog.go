@@ -0,0 +1,105 @@
+package leaderboard
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/xeon-zolt/codecompass/internal/history"
+)
+
+// PrintRunLog prints the topN most recent records of the run log, newest
+// first: when each run started and what it analyzed, how it ended, and
+// what it was asked and found.
+func (p *Printer) PrintRunLog(records []history.RunRecord, topN int) {
+	fmt.Fprintln(p.w, p.titleStyle.Render("Run Log - Most Recent Runs"))
+	if len(records) == 0 {
+		fmt.Fprintln(p.w, p.cellStyle.Render("📭 No runs logged yet"))
+		return
+	}
+
+	maxEntries := p.limit(topN, len(records))
+	t := newTable(column{header: "Started"}, column{header: "Commit"}, column{header: "Exit", right: true},
+		column{header: "Duration", right: true}, column{header: "Leaderboards"}, column{header: "Warnings", right: true},
+		column{header: "Config"}, column{header: "Flags"})
+	for i := 0; i < maxEntries; i++ {
+		record := records[len(records)-1-i]
+
+		// A star marks a work tree with uncommitted changes
+		commit := record.Head
+		if len(commit) > 7 {
+			commit = commit[:7]
+		}
+		if record.Dirty {
+			commit += "*"
+		}
+		exit := cell(p.cellStyle, fmt.Sprintf("%d", record.ExitStatus))
+		if record.ExitStatus != 0 {
+			exit = cell(p.errorStyle, fmt.Sprintf("%d", record.ExitStatus))
+		}
+		fingerprint := record.ConfigFingerprint
+		if len(fingerprint) > 8 {
+			fingerprint = fingerprint[:8]
+		}
+
+		t.row(
+			cell(p.cellStyle, record.Time.Local().Format("2006-01-02 15:04")),
+			cell(p.nameStyle, commit),
+			exit,
+			cell(p.cellStyle, formatRunDuration(record.Duration)),
+			cell(p.cellStyle, formatRunLeaderboards(record)),
+			p.count(record.Warnings),
+			cell(p.emailStyle, fingerprint),
+			cell(p.cellStyle, formatRunFlags(record.Flags)),
+		)
+	}
+	p.printTable(t)
+
+	// The errors of failed runs are too long for the table
+	for i := 0; i < maxEntries; i++ {
+		record := records[len(records)-1-i]
+		if record.Error != "" {
+			fmt.Fprintf(p.w, "  %s %s\n", cell(p.cellStyle, record.Time.Local().Format("2006-01-02 15:04")), cell(p.errorStyle, record.Error))
+		}
+	}
+}
+
+// formatRunDuration formats how long a run took to the millisecond under a
+// second, and to the tenth of a second above, such as 52ms or 1m3.5s.
+func formatRunDuration(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
+
+// formatRunLeaderboards formats the leaderboards a run generated with their
+// entries, and those that failed, sorted by name, such as "debt(12),
+// loc(40), churn(failed)".
+func formatRunLeaderboards(record history.RunRecord) string {
+	var boards []string
+	for name, entries := range record.Leaderboards {
+		boards = append(boards, fmt.Sprintf("%s(%d)", name, entries))
+	}
+	for name := range record.Failures {
+		boards = append(boards, name+"(failed)")
+	}
+	sort.Strings(boards)
+	return strings.Join(boards, ", ")
+}
+
+// formatRunFlags formats the flags a run was given as they would be typed,
+// sorted by name, such as "--debt --top=10".
+func formatRunFlags(flags map[string]string) string {
+	var args []string
+	for name, value := range flags {
+		if value == "true" {
+			args = append(args, "--"+name)
+		} else {
+			args = append(args, fmt.Sprintf("--%s=%s", name, value))
+		}
+	}
+	sort.Strings(args)
+	return strings.Join(args, " ")
+}
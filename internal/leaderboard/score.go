@@ -0,0 +1,83 @@
+package leaderboard
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/xeon-zolt/codecompass/internal/config"
+	"github.com/xeon-zolt/codecompass/internal/types"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func (p *Printer) PrintScore(stats types.ScoreStats, topN int) {
+	fmt.Fprintln(p.w, p.titleStyle.Render("Quality Score - Weighted Components and the Worst Files"))
+
+	if len(stats.Components) == 0 {
+		fmt.Fprintln(p.w, p.cellStyle.Render("📭 Not enough data to score this repository"))
+		return
+	}
+
+	scored := make(map[string]bool)
+	for _, component := range stats.Components {
+		scored[component.Component] = true
+		fmt.Fprintf(p.w, "  • %-11s %s %s %s\n",
+			component.Component,
+			p.scoreStyle(component.Score).Render(fmt.Sprintf("%5.1f", component.Score)),
+			p.cellStyle.Render(fmt.Sprintf("%4.0f%%", component.Weight)),
+			p.emailStyle.Render(component.Detail))
+	}
+
+	// Components without data or weight are left out of the score
+	var unscored []string
+	for _, component := range config.ScoreComponents {
+		if !scored[component] {
+			unscored = append(unscored, component)
+		}
+	}
+	if len(unscored) > 0 {
+		fmt.Fprintf(p.w, "  %s\n", p.emailStyle.Render("Not scored (no data or no weight): "+strings.Join(unscored, ", ")))
+	}
+
+	fmt.Fprintf(p.w, "\n  Repository score: %s\n\n", p.scoreStyle(stats.Score).Render(fmt.Sprintf("%.1f/100", stats.Score)))
+
+	maxEntries := p.limit(topN, len(stats.Files))
+
+	// A column per scored component, blank for files it does not apply to
+	columns := []column{rankColumn, fileColumn, {header: "Score", right: true}}
+	for _, component := range stats.Components {
+		columns = append(columns, column{header: strings.ToUpper(component.Component[:1]) + component.Component[1:], right: true})
+	}
+
+	t := newTable(columns...)
+	for i := 0; i < maxEntries; i++ {
+		entry := stats.Files[i]
+
+		row := []string{
+			cell(p.rankStyle, fmt.Sprintf("%d", entry.Rank)),
+			p.pathCell(p.nameStyle, entry.Path),
+			cell(p.scoreStyle(entry.Score), fmt.Sprintf("%.1f", entry.Score)),
+		}
+		for _, component := range stats.Components {
+			score := ""
+			if s, ok := entry.Components[component.Component]; ok {
+				score = cell(p.emailStyle, fmt.Sprintf("%.0f", s))
+			}
+			row = append(row, score)
+		}
+		t.row(row...)
+	}
+	p.printTable(t)
+}
+
+// scoreStyle colors a 0-100 score like the report card grades.
+func (p *Printer) scoreStyle(score float64) lipgloss.Style {
+	switch {
+	case score >= 80:
+		return p.cellStyle.Foreground(lipgloss.Color("#00FF00"))
+	case score >= 60:
+		return p.warningStyle
+	default:
+		return p.errorStyle
+	}
+}
@@ -0,0 +1,156 @@
+package leaderboard
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+// snapshotSection is one of the sections a snapshot diff is printed in.
+type snapshotSection struct {
+	title string
+	rows  []snapshotRow
+}
+
+// snapshotRow is an entry of a snapshot diff, with whether it appeared or
+// disappeared.
+type snapshotRow struct {
+	change                types.SnapshotChange
+	appeared, disappeared bool
+}
+
+// snapshotSections returns the sections of diff: the entries that
+// worsened, those that improved, and those that appeared or disappeared.
+func snapshotSections(diff types.SnapshotDiff) []snapshotSection {
+	rows := func(changes []types.SnapshotChange, appeared, disappeared bool) []snapshotRow {
+		result := make([]snapshotRow, len(changes))
+		for i, change := range changes {
+			result[i] = snapshotRow{change, appeared, disappeared}
+		}
+		return result
+	}
+	return []snapshotSection{
+		{"Worsened", rows(diff.Worsened, false, false)},
+		{"Improved", rows(diff.Improved, false, false)},
+		{"Appeared / Disappeared", append(rows(diff.Appeared, true, false), rows(diff.Disappeared, false, true)...)},
+	}
+}
+
+// formatSnapshotValue formats a value of a snapshot diff with up to two
+// decimals, such as 12 or 81.25.
+func formatSnapshotValue(value float64) string {
+	return strconv.FormatFloat(math.Round(value*100)/100, 'f', -1, 64)
+}
+
+// cells returns the before, after, change and percentage cells of the row,
+// and whether the change is for the better (1), the worse (-1) or neither
+// (0).
+func (r snapshotRow) cells(higherIsBetter bool) (before, after, delta, percent string, rating int) {
+	before, after = formatSnapshotValue(r.change.A), formatSnapshotValue(r.change.B)
+	if r.appeared {
+		before = "new"
+	}
+	if r.disappeared {
+		after = "gone"
+	}
+	delta = formatSnapshotValue(r.change.Delta)
+	if r.change.Delta > 0 {
+		delta = "+" + delta
+	}
+	if p, ok := r.change.Percent(); ok {
+		percent = fmt.Sprintf("%+.1f%%", p)
+	}
+	switch {
+	case r.change.Delta == 0:
+		rating = 0
+	case (r.change.Delta > 0) == higherIsBetter:
+		rating = 1
+	default:
+		rating = -1
+	}
+	return before, after, delta, percent, rating
+}
+
+// PrintSnapshotDiffs prints how each leaderboard of diffs changed from run
+// a to run b, the topN entries of each section, followed by a warning for
+// each tool the runs used at different versions.
+func (p *Printer) PrintSnapshotDiffs(a, b string, diffs []types.SnapshotDiff, toolChanges []types.ToolVersionChange, topN int) {
+	if len(diffs) == 0 {
+		fmt.Fprintln(p.w, p.cellStyle.Render(fmt.Sprintf("📭 %s and %s logged no leaderboards that can be compared", a, b)))
+	}
+
+	for i, diff := range diffs {
+		if i > 0 {
+			fmt.Fprintln(p.w)
+		}
+		fmt.Fprintln(p.w, p.titleStyle.Render(fmt.Sprintf("Leaderboard Diff - %s by %s, %s → %s", diff.Leaderboard, diff.Metric, a, b)))
+
+		for _, section := range snapshotSections(diff) {
+			fmt.Fprintf(p.w, "%s %s\n", cell(p.nameStyle, section.title), cell(p.emailStyle, fmt.Sprintf("(%d)", len(section.rows))))
+			if len(section.rows) == 0 {
+				continue
+			}
+
+			maxEntries := p.limit(topN, len(section.rows))
+			t := newTable(column{header: "Entry", path: true}, column{header: "Before", right: true}, column{header: "After", right: true},
+				column{header: "Change", right: true}, column{header: "%", right: true})
+			for _, row := range section.rows[:maxEntries] {
+				before, after, delta, percent, rating := row.cells(diff.HigherIsBetter)
+				t.row(
+					cell(p.nameStyle, row.change.Key),
+					cell(p.cellStyle, before),
+					cell(p.cellStyle, after),
+					cell(p.changeStyle(rating), delta),
+					cell(p.changeStyle(rating), percent),
+				)
+			}
+			p.printTable(t)
+		}
+	}
+
+	for _, change := range toolChanges {
+		fmt.Fprintln(p.w, p.warningStyle.Render(fmt.Sprintf("  ⚠️  %s %s at %s but %s at %s; the change may come from the tool", change.Tool, change.A, a, change.B, b)))
+	}
+}
+
+// WriteSnapshotDiffsMarkdown writes how each leaderboard of diffs changed
+// from run a to run b as a Markdown document, to paste into a release
+// retrospective. Each section lists its topN entries.
+func WriteSnapshotDiffsMarkdown(w io.Writer, a, b string, diffs []types.SnapshotDiff, toolChanges []types.ToolVersionChange, topN int) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Leaderboard Diff: %s → %s\n", markdownEscape(a), markdownEscape(b))
+	if len(toolChanges) > 0 {
+		changes := make([]string, len(toolChanges))
+		for i, change := range toolChanges {
+			changes[i] = fmt.Sprintf("%s %s → %s", change.Tool, change.A, change.B)
+		}
+		fmt.Fprintf(&sb, "\n> Tool versions changed between the runs (%s), so part of the changes may come from the tools.\n", markdownEscape(strings.Join(changes, ", ")))
+	}
+	if len(diffs) == 0 {
+		sb.WriteString("\nThe runs logged no leaderboards that can be compared.\n")
+	}
+
+	for _, diff := range diffs {
+		fmt.Fprintf(&sb, "\n## %s by %s\n", markdownEscape(diff.Leaderboard), markdownEscape(diff.Metric))
+		for _, section := range snapshotSections(diff) {
+			fmt.Fprintf(&sb, "\n### %s (%d)\n\n", section.title, len(section.rows))
+			if len(section.rows) == 0 {
+				sb.WriteString("None.\n")
+				continue
+			}
+			sb.WriteString("| Entry | Before | After | Change | % |\n")
+			sb.WriteString("|-------|-------:|------:|-------:|--:|\n")
+			for _, row := range section.rows[:min(topN, len(section.rows))] {
+				before, after, delta, percent, _ := row.cells(diff.HigherIsBetter)
+				fmt.Fprintf(&sb, "| %s | %s | %s | %s | %s |\n", markdownEscape(row.change.Key), before, after, delta, percent)
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
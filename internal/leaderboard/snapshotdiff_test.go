@@ -0,0 +1,30 @@
+package leaderboard
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+func TestWriteSnapshotDiffsMarkdown(t *testing.T) {
+	diffs := []types.SnapshotDiff{{
+		Leaderboard: "coverage", Metric: "CoveragePercent", HigherIsBetter: true,
+		Improved:    []types.SnapshotChange{{Key: "src/a|b.ts", A: 40, B: 65.5, Delta: 25.5}, {Key: "src/c.ts", A: 80, B: 81, Delta: 1}},
+		Disappeared: []types.SnapshotChange{{Key: "src/old.ts", A: 12.25, Delta: -12.25}},
+	}}
+
+	var buf bytes.Buffer
+	if err := WriteSnapshotDiffsMarkdown(&buf, "20260110_093000", "20260117_093000", diffs, nil, 1); err != nil {
+		t.Fatal(err)
+	}
+	expected := "# Leaderboard Diff: 20260110_093000 → 20260117_093000\n\n## coverage by CoveragePercent\n" +
+		"\n### Worsened (0)\n\nNone.\n" +
+		"\n### Improved (2)\n\n| Entry | Before | After | Change | % |\n|-------|-------:|------:|-------:|--:|\n" +
+		"| src/a\\|b.ts | 40 | 65.5 | +25.5 | +63.7% |\n" +
+		"\n### Appeared / Disappeared (1)\n\n| Entry | Before | After | Change | % |\n|-------|-------:|------:|-------:|--:|\n" +
+		"| src/old.ts | 12.25 | gone | -12.25 | -100.0% |\n"
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
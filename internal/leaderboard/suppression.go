@@ -0,0 +1,91 @@
+package leaderboard
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/xeon-zolt/codecompass/internal/fingerprint"
+	"github.com/xeon-zolt/codecompass/internal/suppression"
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+// SuppressSpelling leaves the misspellings filter suppresses out of the
+// spell check leaderboard and the author stats, as if they had not been
+// found, and ranks the files again. Every misspelling is recorded with
+// filter.
+func SuppressSpelling(entries []types.SpellCheckEntry, authors map[string]*types.SpellCheckAuthorStats, filter *suppression.Filter) []types.SpellCheckEntry {
+	if filter == nil {
+		return entries
+	}
+
+	for i := range entries {
+		entry := &entries[i]
+		path := filepath.ToSlash(entry.Path)
+		counter := make(fingerprint.Counter)
+		kept := entry.Issues[:0]
+		for _, issue := range entry.Issues {
+			word := strings.ToLower(issue.Word)
+			record := suppression.Record{Kind: suppression.KindSpelling, Path: path, Rule: word, Fingerprint: counter.Next(path, word, issue.Context)}
+			if !filter.Suppress(record) {
+				kept = append(kept, issue)
+				continue
+			}
+
+			entry.MisspelledWords--
+			if entry.TopMisspellings[word]--; entry.TopMisspellings[word] <= 0 {
+				delete(entry.TopMisspellings, word)
+			}
+			// Misspellings without blame were not counted for anyone
+			if author := authors[issue.AuthorEmail]; author != nil && author.CommonMistakes[word] > 0 {
+				author.TotalErrors--
+				if author.Files[entry.Path]--; author.Files[entry.Path] <= 0 {
+					delete(author.Files, entry.Path)
+				}
+				if author.CommonMistakes[word]--; author.CommonMistakes[word] <= 0 {
+					delete(author.CommonMistakes, word)
+				}
+				if author.TotalErrors <= 0 {
+					delete(authors, issue.AuthorEmail)
+				}
+			}
+		}
+		entry.Issues = kept
+		if entry.TotalWords > 0 {
+			entry.ErrorRate = float64(entry.MisspelledWords) / float64(entry.TotalWords) * 100
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].ErrorRate != entries[j].ErrorRate {
+			return entries[i].ErrorRate > entries[j].ErrorRate
+		}
+		return entries[i].Path < entries[j].Path
+	})
+	return entries
+}
+
+// PrintSuppression prints how many findings the suppression baseline left
+// out of the leaderboards, followed by the topN of its stale entries.
+func (p *Printer) PrintSuppression(s types.Suppression, topN int) {
+	fmt.Fprintf(p.w, "  • Suppressed by baseline: %s (%s issues, %s debt markers, %s misspellings in %s)\n",
+		p.warningStyle.Render(p.formatCount(s.Total())),
+		p.formatCount(s.Issues), p.formatCount(s.DebtMarkers), p.formatCount(s.Misspellings), s.File)
+	if len(s.Stale) == 0 {
+		return
+	}
+
+	fmt.Fprintf(p.w, "  • Stale baseline entries: %s, write the baseline again to drop them\n", p.cellStyle.Render(p.formatCount(len(s.Stale))))
+	maxEntries := min(topN, len(s.Stale))
+	for _, stale := range s.Stale[:maxEntries] {
+		reason := "gone"
+		if stale.Deleted {
+			reason = "file deleted"
+		}
+		fmt.Fprintf(p.w, "    - %s %s in %s (%s)\n", stale.Kind, stale.Rule, stale.Path, reason)
+	}
+	if maxEntries < len(s.Stale) {
+		fmt.Fprintf(p.w, "    … %d more\n", len(s.Stale)-maxEntries)
+	}
+}
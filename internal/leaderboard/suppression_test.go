@@ -0,0 +1,90 @@
+package leaderboard
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/xeon-zolt/codecompass/internal/suppression"
+	"github.com/xeon-zolt/codecompass/internal/types"
+	"github.com/xeon-zolt/codecompass/internal/utils"
+)
+
+func TestGenerateTechnicalDebtLeaderboardSuppression(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "app.js"), []byte("// TODO: legacy\n// FIXME: legacy\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	trackedFiles := map[string]bool{"app.js": true}
+
+	recording := suppression.NewFilter(nil, false)
+	if _, err := GenerateTechnicalDebtLeaderboard(dir, trackedFiles, 1024, recording, utils.NewWarningCollector(), 0, nil); err != nil {
+		t.Fatal(err)
+	}
+	recording.Check(suppression.KindDebt, trackedFiles)
+	baseline := &suppression.Baseline{Records: recording.Records()}
+	if len(baseline.Records) != 2 {
+		t.Fatalf("Expected both markers recorded, but got %+v", baseline.Records)
+	}
+
+	// A marker added above those accepted is the only one counted
+	if err := os.WriteFile(filepath.Join(dir, "app.js"), []byte("// HACK: new\n\n// TODO: legacy\n// FIXME: legacy\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	filter := suppression.NewFilter(baseline, true)
+	entries, err := GenerateTechnicalDebtLeaderboard(dir, trackedFiles, 1024, filter, utils.NewWarningCollector(), 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []types.TechnicalDebtEntry{{Path: "app.js", HackCount: 1, TotalDebt: 1}}
+	if !reflect.DeepEqual(entries, expected) {
+		t.Errorf("Expected %+v, but got %+v", expected, entries)
+	}
+	if n := filter.Suppressed(suppression.KindDebt); n != 2 {
+		t.Errorf("Expected 2 suppressed, but got %d", n)
+	}
+}
+
+func TestSuppressSpelling(t *testing.T) {
+	entries := func() []types.SpellCheckEntry {
+		return []types.SpellCheckEntry{
+			{Path: "docs/a.md", MisspelledWords: 1, TotalWords: 10, ErrorRate: 10, TopMisspellings: map[string]int{"teh": 1},
+				Issues: []types.SpellIssue{{Word: "teh", Context: "teh end", AuthorEmail: "dev@example.com"}}},
+			{Path: "docs/b.md", MisspelledWords: 2, TotalWords: 40, ErrorRate: 5, TopMisspellings: map[string]int{"recieve": 2},
+				Issues: []types.SpellIssue{
+					{Word: "recieve", Context: "we recieve", AuthorEmail: "dev@example.com"},
+					{Word: "Recieve", Context: "Recieve it", AuthorEmail: "dev@example.com"},
+				}},
+		}
+	}
+	authors := func() map[string]*types.SpellCheckAuthorStats {
+		return map[string]*types.SpellCheckAuthorStats{"dev@example.com": {Email: "dev@example.com", TotalErrors: 3,
+			Files: map[string]int{"docs/a.md": 1, "docs/b.md": 2}, CommonMistakes: map[string]int{"teh": 1, "recieve": 2}}}
+	}
+
+	recording := suppression.NewFilter(nil, false)
+	SuppressSpelling(entries(), authors(), recording)
+	recording.Check(suppression.KindSpelling, map[string]bool{"docs/a.md": true, "docs/b.md": true})
+	records := recording.Records()
+	if len(records) != 3 || records[0].Rule != "teh" {
+		t.Fatalf("Expected the 3 misspellings recorded, but got %+v", records)
+	}
+
+	// Accept the misspelling of a.md and either of b.md
+	filter := suppression.NewFilter(&suppression.Baseline{Records: records[:2]}, true)
+	stats := authors()
+	got := SuppressSpelling(entries(), stats, filter)
+
+	if len(got) != 2 || got[0].Path != "docs/b.md" || got[0].MisspelledWords != 1 || got[0].ErrorRate != 2.5 || len(got[0].Issues) != 1 ||
+		!reflect.DeepEqual(got[0].TopMisspellings, map[string]int{"recieve": 1}) {
+		t.Errorf("Expected one misspelling left in docs/b.md, ranked first, but got %+v", got)
+	}
+	if len(got) == 2 && (got[1].MisspelledWords != 0 || got[1].ErrorRate != 0 || len(got[1].TopMisspellings) != 0) {
+		t.Errorf("Expected no misspellings left in docs/a.md, but got %+v", got[1])
+	}
+	author := stats["dev@example.com"]
+	if author.TotalErrors != 1 || !reflect.DeepEqual(author.Files, map[string]int{"docs/b.md": 1}) || !reflect.DeepEqual(author.CommonMistakes, map[string]int{"recieve": 1}) {
+		t.Errorf("Expected the author left with one misspelling, but got %+v", author)
+	}
+}
@@ -0,0 +1,313 @@
+package leaderboard
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/xeon-zolt/codecompass/internal/utils"
+)
+
+// PathStyle is how file-scoped leaderboards show file paths.
+type PathStyle string
+
+const (
+	// PathFull shows paths as they are
+	PathFull PathStyle = "full"
+	// PathBasename shows only the file name, with the full path beside it
+	// in verbose output
+	PathBasename PathStyle = "basename"
+	// PathTruncate replaces the leading directories of paths wider than
+	// TruncatedPathWidth with "...", keeping the file name
+	PathTruncate PathStyle = "truncate"
+)
+
+// TruncatedPathWidth is the widest a path is shown with PathTruncate.
+const TruncatedPathWidth = 40
+
+// ParsePathStyle parses a --path-style value: full, basename or truncate.
+func ParsePathStyle(value string) (PathStyle, error) {
+	switch style := PathStyle(value); style {
+	case PathFull, PathBasename, PathTruncate:
+		return style, nil
+	}
+	return "", fmt.Errorf("invalid path style %q: expected full, basename or truncate", value)
+}
+
+// column is a column of a table. Counts and other numbers are right-aligned
+// so their digits line up.
+type column struct {
+	header string
+	right  bool
+	path   bool // cut from the left when too wide, keeping the file name
+}
+
+// rankColumn is the first column of every leaderboard.
+var rankColumn = column{header: "#", right: true}
+
+// fileColumn is the column of file paths in file leaderboards.
+var fileColumn = column{header: "File", path: true}
+
+// minColumnWidth is the narrowest a text column is cut to when a table is
+// fitted to the output width, unless its header is wider.
+const minColumnWidth = 8
+
+// cellEllipsis stands in for the part of a cell cut to fit its column.
+const cellEllipsis = "..."
+
+// table collects the rows of a leaderboard, each a rendered cell per column,
+// and prints them in aligned columns.
+type table struct {
+	columns []column
+	rows    [][]string
+}
+
+func newTable(columns ...column) *table {
+	return &table{columns: columns}
+}
+
+// row adds a row of rendered cells. Missing trailing cells are left empty.
+func (t *table) row(cells ...string) {
+	t.rows = append(t.rows, cells)
+}
+
+// printTable prints t under a header, followed by the note of the cap when
+// limit cut its leaderboard short.
+func (p *Printer) printTable(t *table) {
+	for _, line := range p.renderTable(t) {
+		fmt.Fprintln(p.w, line)
+	}
+	if p.capped != "" {
+		fmt.Fprintln(p.w, p.emailStyle.Render(p.capped))
+		p.capped = ""
+	}
+}
+
+// limit returns how many of total entries a leaderboard shows: topN, or
+// fewer when there are fewer entries or SetMaxRows capped them. A capped
+// leaderboard gets a note under its table.
+func (p *Printer) limit(topN, total int) int {
+	shown := min(topN, total)
+	if p.maxRows > 0 && shown > p.maxRows {
+		shown = p.maxRows
+		p.capped = fmt.Sprintf("showing %s of %s", p.formatCount(shown), p.formatCount(total))
+		if p.capHint != "" {
+			p.capped += "; " + p.capHint
+		}
+	}
+	return shown
+}
+
+// SetMaxRows caps every leaderboard at rows entries, whatever topN asks, so
+// a huge --top cannot flood a terminal. Capped leaderboards are followed by
+// a note with hint, such as where to find the full list. Zero, the
+// default, leaves them uncapped.
+func (p *Printer) SetMaxRows(rows int, hint string) {
+	p.maxRows, p.capHint = rows, hint
+}
+
+// printTablesSideBySide prints left and right next to each other, the
+// shorter one padded with blank lines.
+func (p *Printer) printTablesSideBySide(left, right *table) {
+	leftLines, rightLines := p.renderTable(left), p.renderTable(right)
+	width, rightWidth := 0, 0
+	for _, line := range leftLines {
+		width = max(width, lipgloss.Width(line))
+	}
+	for _, line := range rightLines {
+		rightWidth = max(rightWidth, lipgloss.Width(line))
+	}
+
+	// Tables too wide to fit next to each other go one under the other
+	if p.width > 0 && width+2+rightWidth > p.width {
+		p.printTable(left)
+		fmt.Fprintln(p.w)
+		p.printTable(right)
+		return
+	}
+	for i := 0; i < max(len(leftLines), len(rightLines)); i++ {
+		var l, r string
+		if i < len(leftLines) {
+			l = leftLines[i]
+		}
+		if i < len(rightLines) {
+			r = rightLines[i]
+		}
+		line := l + strings.Repeat(" ", width-lipgloss.Width(l)) + "  " + r
+		fmt.Fprintln(p.w, strings.TrimRight(line, " "))
+	}
+}
+
+// renderTable lays out t, header first, padding every cell to the widest in
+// its column. Widths are measured with lipgloss.Width, which skips color
+// codes and counts wide characters such as emoji as two cells, so the
+// columns line up in colored and plain output alike. With an output width
+// set, text columns are narrowed to fit it.
+func (p *Printer) renderTable(t *table) []string {
+	return p.renderTableWidth(t, p.width)
+}
+
+// renderTableWidth lays out t like renderTable, fitted to width columns
+// rather than the output width.
+func (p *Printer) renderTableWidth(t *table, width int) []string {
+	widths := make([]int, len(t.columns))
+	for i, col := range t.columns {
+		widths[i] = lipgloss.Width(col.header)
+	}
+	for _, row := range t.rows {
+		for i, cell := range row {
+			if i < len(widths) && lipgloss.Width(cell) > widths[i] {
+				widths[i] = lipgloss.Width(cell)
+			}
+		}
+	}
+
+	if width > 0 {
+		t.fit(widths, width)
+	}
+
+	headers := make([]string, len(t.columns))
+	for i, col := range t.columns {
+		headers[i] = col.header
+	}
+	lines := []string{p.headerStyle.Render(t.line(headers, widths))}
+	for _, row := range t.rows {
+		lines = append(lines, t.line(row, widths))
+	}
+	return lines
+}
+
+// fit narrows the text columns of t, widest first, until a row laid out in
+// widths takes at most width columns. The rank, counts and other
+// right-aligned columns keep their width, and no column gets narrower than
+// minColumnWidth or its header, so a table with many columns can still be
+// wider than width.
+func (t *table) fit(widths []int, width int) {
+	total := 0
+	for _, w := range widths {
+		total += 2 + w
+	}
+	for total > width {
+		widest := -1
+		for i, col := range t.columns {
+			if col.right || widths[i] <= max(minColumnWidth, lipgloss.Width(col.header)) {
+				continue
+			}
+			if widest < 0 || widths[i] > widths[widest] {
+				widest = i
+			}
+		}
+		if widest < 0 {
+			return
+		}
+		widths[widest]--
+		total--
+	}
+}
+
+// truncate cuts text, a rendered cell of col, to width columns, keeping
+// its colors. Paths lose their leading directories and other text its end.
+func (col column) truncate(text string, width int) string {
+	over := lipgloss.Width(text) - width
+	if over <= 0 {
+		return text
+	}
+	if col.path {
+		return ansi.TruncateLeft(text, over+len(cellEllipsis), cellEllipsis)
+	}
+	return ansi.Truncate(text, width, cellEllipsis)
+}
+
+// line lays out one row, indented and two spaces apart, without trailing
+// spaces. Cells wider than their column are cut to fit.
+func (t *table) line(cells []string, widths []int) string {
+	var b strings.Builder
+	for i, col := range t.columns {
+		text := ""
+		if i < len(cells) {
+			text = col.truncate(cells[i], widths[i])
+		}
+		pad := strings.Repeat(" ", widths[i]-lipgloss.Width(text))
+
+		b.WriteString("  ")
+		if col.right {
+			b.WriteString(pad + text)
+		} else {
+			b.WriteString(text + pad)
+		}
+	}
+	return strings.TrimRight(b.String(), " ")
+}
+
+// cell renders text in style without its padding, which the gap between the
+// columns of a table takes the place of.
+func cell(style lipgloss.Style, text string) string {
+	return style.UnsetPaddingLeft().UnsetPaddingRight().Render(text)
+}
+
+// count renders a count for a table cell.
+func (p *Printer) count(n int) string {
+	return cell(p.cellStyle, p.formatCount(n))
+}
+
+// formatCount formats a count of issues, lines or commits, grouping its
+// digits in thousands when SetGroupNumbers turned that on.
+func (p *Printer) formatCount(n int) string {
+	if p.groupNumbers {
+		return utils.FormatCount(n)
+	}
+	return strconv.Itoa(n)
+}
+
+// SetGroupNumbers sets whether p groups the digits of counts in thousands,
+// such as 12,345, with the separator of the locale.
+func (p *Printer) SetGroupNumbers(group bool) {
+	p.groupNumbers = group
+}
+
+// SetPathStyle sets how p shows file paths.
+func (p *Printer) SetPathStyle(style PathStyle) {
+	p.pathStyle = style
+}
+
+// SetWidth fits the tables p prints to width columns. Zero, the default,
+// leaves them as wide as their cells.
+func (p *Printer) SetWidth(width int) {
+	p.width = width
+}
+
+// SetVerbose makes p show more detail: the full path of each file with
+// PathBasename, and the documentation links of rules.
+func (p *Printer) SetVerbose(verbose bool) {
+	p.verbose = verbose
+}
+
+// SetSpellCheckSort sets the column p ranks the authors of the spell check
+// leaderboard by. Empty means SpellCheckSortErrors.
+func (p *Printer) SetSpellCheckSort(sortBy SpellCheckSort) {
+	p.spellCheckSort = sortBy
+}
+
+// displayPath shortens file as the path style of p asks.
+func (p *Printer) displayPath(file string) string {
+	switch p.pathStyle {
+	case PathBasename:
+		return path.Base(file)
+	case PathTruncate:
+		return utils.TruncatePath(file, TruncatedPathWidth)
+	}
+	return file
+}
+
+// pathCell renders file in style for a table cell, shortened as the path
+// style of p asks.
+func (p *Printer) pathCell(style lipgloss.Style, file string) string {
+	text := cell(style, p.displayPath(file))
+	if p.pathStyle == PathBasename && p.verbose && path.Base(file) != file {
+		text += " " + cell(p.emailStyle, "("+file+")")
+	}
+	return text
+}
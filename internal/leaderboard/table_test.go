@@ -0,0 +1,93 @@
+package leaderboard
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+var colorCodes = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+func TestPrintTableAlignsColoredCells(t *testing.T) {
+	entries := []types.ChurnEntry{
+		{Path: "src/🚀.js", Changes: 140, AddedLines: 3000, DeletedLines: 12, NetLines: 2988},
+		{Path: "lib/日本語/util.js", Changes: 3, AddedLines: 10, DeletedLines: 60, NetLines: -50},
+	}
+
+	var plain, colored bytes.Buffer
+	NewPlainPrinter(&plain).PrintCodeChurnLeaderboard(entries, 15)
+	renderer := lipgloss.NewRenderer(&colored)
+	renderer.SetColorProfile(termenv.TrueColor)
+	newPrinter(&colored, renderer).PrintCodeChurnLeaderboard(entries, 15)
+
+	if !colorCodes.MatchString(colored.String()) {
+		t.Fatalf("Expected colored output, but got %q", colored.String())
+	}
+	// Color codes must not count towards the widths of the columns
+	if got := colorCodes.ReplaceAllString(colored.String(), ""); got != plain.String() {
+		t.Errorf("Expected colored output to line up like plain output\ngot:\n%s\nexpected:\n%s", got, plain.String())
+	}
+
+	// Every row ends its right-aligned last column at the same width
+	lines := strings.Split(strings.TrimSpace(plain.String()), "\n")[1:]
+	for _, line := range lines[1:] {
+		if lipgloss.Width(line) != lipgloss.Width(lines[0]) {
+			t.Errorf("Expected every line as wide as the header, but got\n%s", strings.Join(lines, "\n"))
+			break
+		}
+	}
+}
+
+func TestParsePathStyle(t *testing.T) {
+	for _, value := range []string{"full", "basename", "truncate"} {
+		if style, err := ParsePathStyle(value); err != nil || string(style) != value {
+			t.Errorf("ParsePathStyle(%q) = %q, %v; expected %q", value, style, err, value)
+		}
+	}
+	if _, err := ParsePathStyle("short"); err == nil {
+		t.Error("Expected an error for an unknown path style")
+	}
+}
+
+func TestPrintTableFitsWidth(t *testing.T) {
+	entries := []types.ChurnEntry{
+		{Path: "packages/web/src/components/charts/LineChart.tsx", Changes: 140, AddedLines: 3000, DeletedLines: 12, NetLines: 2988},
+		{Path: "lib/util.js", Changes: 3, AddedLines: 10, DeletedLines: 60, NetLines: -50},
+	}
+
+	var wide, narrow bytes.Buffer
+	NewPlainPrinter(&wide).PrintCodeChurnLeaderboard(entries, 15)
+	p := NewPlainPrinter(&narrow)
+	p.SetWidth(70)
+	p.PrintCodeChurnLeaderboard(entries, 15)
+
+	wideLines := strings.Split(strings.TrimSpace(wide.String()), "\n")[1:]
+	lines := strings.Split(strings.TrimSpace(narrow.String()), "\n")[1:]
+	if lipgloss.Width(wideLines[0]) <= 70 {
+		t.Fatalf("Expected the table to be wider than 70 columns unfitted, but got\n%s", wide.String())
+	}
+	for _, line := range lines {
+		if lipgloss.Width(line) > 70 {
+			t.Errorf("Expected lines of at most 70 columns, but got\n%s", narrow.String())
+			break
+		}
+	}
+
+	// The path loses its leading directories, the rank and counts nothing
+	fields := strings.Fields(lines[1])
+	if fields[0] != "1" || !strings.HasPrefix(fields[1], "...") || !strings.HasSuffix(fields[1], "/LineChart.tsx") {
+		t.Errorf("Expected the rank and a truncated path, but got %q", lines[1])
+	}
+	wideFields := strings.Fields(wideLines[1])
+	if strings.Join(fields[2:], " ") != strings.Join(wideFields[2:], " ") {
+		t.Errorf("Expected counts %v, but got %v", wideFields[2:], fields[2:])
+	}
+	if !strings.Contains(lines[2], "lib/util.js") {
+		t.Errorf("Expected a short path to be kept, but got %q", lines[2])
+	}
+}
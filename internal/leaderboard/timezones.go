@@ -0,0 +1,187 @@
+package leaderboard
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/xeon-zolt/codecompass/internal/git"
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+// Local working hours for --timezones: commits at or after WorkdayEnd, or
+// before WorkdayStart, are outside them.
+const (
+	WorkdayStart = 9
+	WorkdayEnd   = 18
+)
+
+// GenerateTimezoneStats reads the author dates of every non-merge commit and
+// describes when each author with at least minCommits commits works, in
+// their own UTC offset.
+func GenerateTimezoneStats(ctx context.Context, dir string, minCommits int) (*types.TimezoneStats, error) {
+	commits, err := git.GetAuthorDates(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := TimezoneStats(commits, minCommits)
+	return &stats, nil
+}
+
+// TimezoneStats summarizes commits by author, whose Date must carry the
+// author's UTC offset. An author's offset is the one most of their commits
+// were made in, so a daylight saving change does not split them; ties go to
+// the offset used most recently.
+func TimezoneStats(commits []types.CommitInfo, minCommits int) types.TimezoneStats {
+	type authorCommits struct {
+		name, email       string
+		commits           int
+		weekend, offHours int
+		offsets           map[int]int
+		lastUsed          map[int]time.Time
+	}
+
+	authors := make(map[string]*authorCommits)
+	for _, commit := range commits {
+		author := authors[commit.Email]
+		if author == nil {
+			author = &authorCommits{name: commit.Author, email: commit.Email, offsets: make(map[int]int), lastUsed: make(map[int]time.Time)}
+			authors[commit.Email] = author
+		}
+
+		// Date is already in the author's offset, so these are local times
+		_, offset := commit.Date.Zone()
+		author.commits++
+		author.offsets[offset]++
+		if commit.Date.After(author.lastUsed[offset]) {
+			author.lastUsed[offset] = commit.Date
+		}
+		if weekday := commit.Date.Weekday(); weekday == time.Saturday || weekday == time.Sunday {
+			author.weekend++
+		}
+		if hour := commit.Date.Hour(); hour < WorkdayStart || hour >= WorkdayEnd {
+			author.offHours++
+		}
+	}
+
+	stats := types.TimezoneStats{MinCommits: minCommits}
+	offsets := make(map[int]*types.UTCOffsetEntry)
+	var weekend, offHours int
+
+	for _, author := range authors {
+		if author.commits < minCommits {
+			continue
+		}
+
+		offset, uses := 0, -1
+		for candidate, count := range author.offsets {
+			if count > uses || (count == uses && author.lastUsed[candidate].After(author.lastUsed[offset])) {
+				offset, uses = candidate, count
+			}
+		}
+
+		stats.Authors = append(stats.Authors, types.TimezoneAuthorEntry{
+			Name:          author.name,
+			Email:         author.email,
+			Commits:       author.commits,
+			Offset:        offset,
+			WeekendShare:  share(author.weekend, author.commits),
+			OffHoursShare: share(author.offHours, author.commits),
+		})
+
+		stats.Commits += author.commits
+		weekend += author.weekend
+		offHours += author.offHours
+
+		entry := offsets[offset]
+		if entry == nil {
+			entry = &types.UTCOffsetEntry{Offset: offset}
+			offsets[offset] = entry
+		}
+		entry.Authors++
+		entry.Commits += author.commits
+	}
+
+	stats.WeekendShare = share(weekend, stats.Commits)
+	stats.OffHoursShare = share(offHours, stats.Commits)
+
+	for _, entry := range offsets {
+		stats.Offsets = append(stats.Offsets, *entry)
+	}
+	sort.Slice(stats.Offsets, func(i, j int) bool { return stats.Offsets[i].Offset < stats.Offsets[j].Offset })
+
+	sort.SliceStable(stats.Authors, func(i, j int) bool {
+		if stats.Authors[i].Commits != stats.Authors[j].Commits {
+			return stats.Authors[i].Commits > stats.Authors[j].Commits
+		}
+		if stats.Authors[i].Name != stats.Authors[j].Name {
+			return stats.Authors[i].Name < stats.Authors[j].Name
+		}
+		return stats.Authors[i].Email < stats.Authors[j].Email
+	})
+	for i := range stats.Authors {
+		stats.Authors[i].Rank = i + 1
+	}
+
+	return stats
+}
+
+// share returns part as a percentage of total, or zero when total is zero.
+func share(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total) * 100
+}
+
+// formatUTCOffset formats an offset in seconds east of UTC, such as
+// "UTC+05:30" or "UTC-08:00".
+func formatUTCOffset(offset int) string {
+	return "UTC" + time.Unix(0, 0).In(time.FixedZone("", offset)).Format("-07:00")
+}
+
+func (p *Printer) PrintTimezoneStats(stats types.TimezoneStats, topN int) {
+	fmt.Fprintln(p.w, p.titleStyle.Render("Timezone Leaderboard - When Authors Commit (local time)"))
+	fmt.Fprintln(p.w, "ℹ️ Descriptive only: commit times show when work was committed, not how much anyone works or how well.")
+
+	if len(stats.Authors) == 0 {
+		fmt.Fprintln(p.w, p.cellStyle.Render(fmt.Sprintf("📭 No authors with at least %d commits", stats.MinCommits)))
+		return
+	}
+
+	fmt.Fprintf(p.w, "🌍 %s commits by %s authors – %.1f%% on weekends, %.1f%% outside %02d:00–%02d:00\n",
+		p.cellStyle.Render(p.formatCount(stats.Commits)), p.formatCount(len(stats.Authors)), stats.WeekendShare, stats.OffHoursShare, WorkdayStart, WorkdayEnd)
+
+	t := newTable(column{header: "Offset"}, column{header: "Authors", right: true},
+		column{header: "Commits", right: true}, column{header: "Share", right: true})
+	for _, entry := range stats.Offsets {
+		t.row(
+			cell(p.topRuleStyle, formatUTCOffset(entry.Offset)),
+			p.count(entry.Authors),
+			p.count(entry.Commits),
+			fmt.Sprintf("%.1f%%", share(entry.Commits, stats.Commits)),
+		)
+	}
+	p.printTable(t)
+	fmt.Fprintln(p.w)
+
+	maxEntries := p.limit(topN, len(stats.Authors))
+
+	t = newTable(rankColumn, column{header: "Author"}, column{header: "Email"}, column{header: "Offset"},
+		column{header: "Commits", right: true}, column{header: "Weekends", right: true}, column{header: "Off Hours", right: true})
+	for i := 0; i < maxEntries; i++ {
+		entry := stats.Authors[i]
+		t.row(
+			cell(p.rankStyle, fmt.Sprintf("%d", entry.Rank)),
+			cell(p.nameStyle, entry.Name),
+			cell(p.emailStyle, entry.Email),
+			formatUTCOffset(entry.Offset),
+			p.count(entry.Commits),
+			fmt.Sprintf("%.1f%%", entry.WeekendShare),
+			fmt.Sprintf("%.1f%%", entry.OffHoursShare),
+		)
+	}
+	p.printTable(t)
+}
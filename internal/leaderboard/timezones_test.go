@@ -0,0 +1,80 @@
+package leaderboard
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+func TestTimezoneStats(t *testing.T) {
+	india := time.FixedZone("IST", 5*3600+1800)
+	winter := time.FixedZone("CET", 3600)
+	summer := time.FixedZone("CEST", 2*3600)
+	commit := func(author string, date time.Time) types.CommitInfo {
+		return types.CommitInfo{Author: author, Email: author + "@example.com", Date: date}
+	}
+
+	commits := []types.CommitInfo{
+		// Monday to Wednesday in a half-hour zone, one of them at night
+		commit("priya", time.Date(2024, 1, 1, 10, 0, 0, 0, india)),
+		commit("priya", time.Date(2024, 1, 2, 23, 30, 0, 0, india)),
+		commit("priya", time.Date(2024, 1, 3, 17, 59, 0, 0, india)),
+		// Across a daylight saving change, mostly in summer time
+		commit("hans", time.Date(2024, 3, 29, 9, 0, 0, 0, winter)),
+		commit("hans", time.Date(2024, 4, 1, 18, 0, 0, 0, summer)),
+		commit("hans", time.Date(2024, 4, 6, 12, 0, 0, 0, summer)),
+		commit("hans", time.Date(2024, 4, 8, 8, 0, 0, 0, summer)),
+		// Below the minimum commit count
+		commit("drive-by", time.Date(2024, 1, 6, 3, 0, 0, 0, time.UTC)),
+	}
+
+	stats := TimezoneStats(commits, 2)
+
+	expectedAuthors := []types.TimezoneAuthorEntry{
+		{Rank: 1, Name: "hans", Email: "hans@example.com", Commits: 4, Offset: 2 * 3600, WeekendShare: 25, OffHoursShare: 50},
+		{Rank: 2, Name: "priya", Email: "priya@example.com", Commits: 3, Offset: 5*3600 + 1800, WeekendShare: 0, OffHoursShare: share(1, 3)},
+	}
+	if !reflect.DeepEqual(stats.Authors, expectedAuthors) {
+		t.Errorf("Expected %+v, but got %+v", expectedAuthors, stats.Authors)
+	}
+
+	expectedOffsets := []types.UTCOffsetEntry{
+		{Offset: 2 * 3600, Authors: 1, Commits: 4},
+		{Offset: 5*3600 + 1800, Authors: 1, Commits: 3},
+	}
+	if !reflect.DeepEqual(stats.Offsets, expectedOffsets) {
+		t.Errorf("Expected %+v, but got %+v", expectedOffsets, stats.Offsets)
+	}
+
+	if stats.Commits != 7 || stats.MinCommits != 2 {
+		t.Errorf("Expected 7 commits from authors with at least 2, but got %d (minimum %d)", stats.Commits, stats.MinCommits)
+	}
+	if stats.WeekendShare != share(1, 7) || stats.OffHoursShare != share(3, 7) {
+		t.Errorf("Expected 1 of 7 commits on weekends and 3 outside working hours, but got %.1f%% and %.1f%%", stats.WeekendShare, stats.OffHoursShare)
+	}
+}
+
+func TestTimezoneStatsTiedOffsets(t *testing.T) {
+	east := time.FixedZone("", -5*3600)
+	west := time.FixedZone("", -8*3600)
+	commits := []types.CommitInfo{
+		{Author: "sam", Email: "sam@example.com", Date: time.Date(2024, 1, 1, 10, 0, 0, 0, east)},
+		{Author: "sam", Email: "sam@example.com", Date: time.Date(2024, 2, 1, 10, 0, 0, 0, west)},
+	}
+
+	// Sam moved west, so the more recent offset wins the tie
+	stats := TimezoneStats(commits, 1)
+	if len(stats.Authors) != 1 || stats.Authors[0].Offset != -8*3600 {
+		t.Errorf("Expected the most recently used offset, but got %+v", stats.Authors)
+	}
+}
+
+func TestFormatUTCOffset(t *testing.T) {
+	for offset, expected := range map[int]string{0: "UTC+00:00", 5*3600 + 1800: "UTC+05:30", -8 * 3600: "UTC-08:00", -(3*3600 + 1800): "UTC-03:30"} {
+		if got := formatUTCOffset(offset); got != expected {
+			t.Errorf("Expected %s for %d, but got %s", expected, offset, got)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/xeon-zolt/codecompass/internal/cerrors"
+	"github.com/xeon-zolt/codecompass/internal/types"
+	"github.com/xeon-zolt/codecompass/internal/utils"
+	"github.com/xeon-zolt/codecompass/internal/vulns"
+)
+
+// GenerateVulnerabilityLeaderboard audits the dependencies in dir with every
+// scanner whose lockfile is present. Scanners whose tool is not installed
+// are skipped with a warning; other failures are returned along with the
+// vulnerabilities the remaining scanners found.
+func GenerateVulnerabilityLeaderboard(ctx context.Context, dir string, warnings *utils.WarningCollector) ([]types.VulnEntry, error) {
+	var entries []types.VulnEntry
+	var errs []error
+
+	for _, scanner := range vulns.Scanners() {
+		if !scanner.Detect(dir) {
+			continue
+		}
+
+		found, err := scanner.Run(ctx, dir)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		var toolErr *cerrors.ErrToolNotFound
+		if errors.As(err, &toolErr) {
+			warnings.Add(types.NewWarning("Vulnerability scan skipped", "tool", scanner.Tool, "error", toolErr.Err))
+			continue
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		entries = append(entries, found...)
+	}
+
+	vulns.Sort(entries)
+	return entries, errors.Join(errs...)
+}
+
+// VulnerabilitySummary formats the counts as vulns-<severity>=<count> pairs,
+// the names --fail-on style gates match on.
+func VulnerabilitySummary(entries []types.VulnEntry) string {
+	counts := vulns.Counts(entries)
+	pairs := make([]string, len(vulns.Severities))
+	for i, severity := range vulns.Severities {
+		pairs[i] = fmt.Sprintf("vulns-%s=%d", severity, counts[severity])
+	}
+	return strings.Join(pairs, " ")
+}
+
+type directDependencyCount struct {
+	name       string
+	total      int
+	severities map[string]int
+}
+
+// vulnerabilitiesPerDirectDependency counts the vulnerabilities each direct
+// dependency pulls in, most first.
+func vulnerabilitiesPerDirectDependency(entries []types.VulnEntry) []directDependencyCount {
+	byName := make(map[string]*directDependencyCount)
+	for _, entry := range entries {
+		for _, name := range entry.Direct {
+			count := byName[name]
+			if count == nil {
+				count = &directDependencyCount{name: name, severities: make(map[string]int)}
+				byName[name] = count
+			}
+			count.total++
+			count.severities[entry.Severity]++
+		}
+	}
+
+	counts := make([]directDependencyCount, 0, len(byName))
+	for _, count := range byName {
+		counts = append(counts, *count)
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].total != counts[j].total {
+			return counts[i].total > counts[j].total
+		}
+		return counts[i].name < counts[j].name
+	})
+	return counts
+}
+
+func (p *Printer) PrintVulnerabilityLeaderboard(entries []types.VulnEntry, topN int) {
+	fmt.Fprintln(p.w, p.titleStyle.Render("Vulnerability Leaderboard - Known Vulnerabilities by Severity"))
+
+	if len(entries) == 0 {
+		fmt.Fprintln(p.w, p.cellStyle.Render("✅ No known vulnerabilities found"))
+		return
+	}
+
+	fmt.Fprintf(p.w, "🛡️ %s\n", VulnerabilitySummary(entries))
+
+	// entries are sorted by severity, so each group is contiguous
+	for start := 0; start < len(entries); {
+		severity := entries[start].Severity
+		end := start
+		for end < len(entries) && entries[end].Severity == severity {
+			end++
+		}
+
+		fmt.Fprintln(p.w, p.topRuleStyle.Render(fmt.Sprintf("%s (%d)", strings.ToUpper(severity), end-start)))
+		shown := end
+		if topN < end-start {
+			shown = start + topN
+		}
+		t := newTable(column{header: "Package"}, column{header: "Version"}, column{header: "Advisory"}, column{header: "Fixed In"})
+		for _, entry := range entries[start:shown] {
+			fix := cell(p.emailStyle, "no fix available")
+			if entry.FixedIn != "" {
+				fix = entry.FixedIn
+			}
+			t.row(cell(p.nameStyle, entry.Package), entry.InstalledVersion, cell(p.cellStyle, entry.AdvisoryID), fix)
+		}
+		p.printTable(t)
+		if shown < end {
+			fmt.Fprintf(p.w, "  … and %d more\n", end-shown)
+		}
+		start = end
+	}
+
+	fmt.Fprintln(p.w)
+	fmt.Fprintln(p.w, p.titleStyle.Render("Direct Dependencies - Vulnerabilities Pulled In"))
+
+	counts := vulnerabilitiesPerDirectDependency(entries)
+	maxEntries := p.limit(topN, len(counts))
+
+	t := newTable(rankColumn, column{header: "Dependency"}, column{header: "Vulnerabilities", right: true}, column{header: "Severities"})
+	for i := 0; i < maxEntries; i++ {
+		count := counts[i]
+
+		var breakdown []string
+		for _, severity := range vulns.Severities {
+			if n := count.severities[severity]; n > 0 {
+				breakdown = append(breakdown, fmt.Sprintf("%d %s", n, severity))
+			}
+		}
+
+		t.row(
+			cell(p.rankStyle, fmt.Sprintf("%d", i+1)),
+			cell(p.nameStyle, count.name),
+			p.count(count.total),
+			strings.Join(breakdown, ", "),
+		)
+	}
+	p.printTable(t)
+}
@@ -0,0 +1,43 @@
+package leaderboard
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/xeon-zolt/codecompass/internal/types"
+	"github.com/xeon-zolt/codecompass/internal/utils"
+)
+
+func TestGenerateVulnerabilityLeaderboardWithoutTools(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "package-lock.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	warnings := utils.NewWarningCollector()
+	entries, err := GenerateVulnerabilityLeaderboard(context.Background(), dir, warnings)
+	if err != nil {
+		t.Fatalf("Expected a missing npm to be skipped, but got %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("Expected no vulnerabilities, but got %+v", entries)
+	}
+
+	// pip-audit has no requirements to audit, so only npm is reported
+	if got := warnings.Warnings(); len(got) != 1 || got[0].Param("tool") != "npm" {
+		t.Errorf("Expected a warning that npm was skipped, but got %v", got)
+	}
+}
+
+func TestVulnerabilitySummary(t *testing.T) {
+	entries := []types.VulnEntry{{Severity: "critical"}, {Severity: "high"}, {Severity: "critical"}}
+
+	expected := "vulns-critical=2 vulns-high=1 vulns-moderate=0 vulns-low=0 vulns-unknown=0"
+	if got := VulnerabilitySummary(entries); got != expected {
+		t.Errorf("Expected %q, but got %q", expected, got)
+	}
+}
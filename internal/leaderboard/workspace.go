@@ -0,0 +1,81 @@
+package leaderboard
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/xeon-zolt/codecompass/internal/types"
+	"github.com/xeon-zolt/codecompass/internal/workspace"
+)
+
+// GenerateWorkspaceLeaderboard rolls the file-based results of a single run
+// up to the innermost workspace containing each file, so the linters run
+// once however many packages there are. Files outside every workspace are
+// rolled up under ".". Entries are sorted by path.
+func GenerateWorkspaceLeaderboard(workspaces []workspace.Workspace, files map[string]bool, fileStats map[string]*types.FileStats,
+	linesOfCode []types.LinesOfCodeEntry, debt []types.TechnicalDebtEntry, coverage []types.CoverageEntry) []types.WorkspaceEntry {
+	entries := make(map[string]*types.WorkspaceEntry)
+	entryFor := func(file string) *types.WorkspaceEntry {
+		w, ok := workspace.Find(workspaces, filepath.ToSlash(file))
+		if !ok {
+			w = workspace.Workspace{Name: ".", Path: "."}
+		}
+		entry := entries[w.Path]
+		if entry == nil {
+			entry = &types.WorkspaceEntry{Name: w.Name, Path: w.Path, Kind: w.Kind}
+			entries[w.Path] = entry
+		}
+		return entry
+	}
+
+	for _, w := range workspaces {
+		entries[w.Path] = &types.WorkspaceEntry{Name: w.Name, Path: w.Path, Kind: w.Kind}
+	}
+
+	for file := range files {
+		entryFor(file).Files++
+	}
+	for file, stats := range fileStats {
+		entryFor(file).Issues += stats.Count + stats.Overflow
+	}
+	for _, entry := range linesOfCode {
+		entryFor(entry.Path).LinesOfCode += entry.Lines
+	}
+	for _, entry := range debt {
+		entryFor(entry.Path).Debt += entry.TotalDebt
+	}
+	for _, entry := range coverage {
+		rollup := entryFor(entry.Path)
+		rollup.LinesCovered += entry.LinesCovered
+		rollup.LinesTotal += entry.LinesTotal
+	}
+
+	result := make([]types.WorkspaceEntry, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, *entry)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Path < result[j].Path })
+	return result
+}
+
+func (p *Printer) PrintWorkspaceLeaderboard(entries []types.WorkspaceEntry) {
+	fmt.Fprintln(p.w, p.titleStyle.Render("Workspace Roll-up - File-Based Leaderboards per Package"))
+
+	if len(entries) == 0 {
+		fmt.Fprintln(p.w, p.cellStyle.Render("📭 No workspaces in package.json, pnpm-workspace.yaml or go.work"))
+		return
+	}
+
+	t := newTable(column{header: "Package"}, column{header: "Files", right: true}, column{header: "LOC", right: true},
+		column{header: "Issues", right: true}, column{header: "Debt", right: true}, column{header: "Coverage", right: true})
+	for _, entry := range entries {
+		coverage := "n/a"
+		if entry.LinesTotal > 0 {
+			coverage = fmt.Sprintf("%.1f%%", float64(entry.LinesCovered)/float64(entry.LinesTotal)*100)
+		}
+		t.row(cell(p.nameStyle, entry.Name), p.count(entry.Files), p.count(entry.LinesOfCode),
+			p.count(entry.Issues), p.count(entry.Debt), coverage)
+	}
+	p.printTable(t)
+}
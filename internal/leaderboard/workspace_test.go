@@ -0,0 +1,57 @@
+package leaderboard
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/xeon-zolt/codecompass/internal/types"
+	"github.com/xeon-zolt/codecompass/internal/workspace"
+)
+
+func TestGenerateWorkspaceLeaderboard(t *testing.T) {
+	workspaces := []workspace.Workspace{
+		{Name: "@acme/api", Path: "packages/api", Kind: workspace.KindNPM},
+		{Name: "@acme/web", Path: "packages/web", Kind: workspace.KindNPM},
+		{Name: "@acme/web-widgets", Path: "packages/web/widgets", Kind: workspace.KindNPM},
+		{Name: "@acme/unused", Path: "packages/unused", Kind: workspace.KindNPM},
+	}
+	files := map[string]bool{
+		"scripts/build.js":               true,
+		"packages/api/index.js":          true,
+		"packages/api/routes.js":         true,
+		"packages/web/app.js":            true,
+		"packages/web/widgets/button.js": true,
+		"packages/webhooks/handler.js":   true,
+	}
+	fileStats := map[string]*types.FileStats{
+		"packages/api/index.js":          {Count: 3, Overflow: 2},
+		"packages/web/widgets/button.js": {Count: 1},
+	}
+	linesOfCode := []types.LinesOfCodeEntry{
+		{Path: "packages/api/index.js", Lines: 100},
+		{Path: "packages/api/routes.js", Lines: 50},
+		{Path: "scripts/build.js", Lines: 10},
+	}
+	debt := []types.TechnicalDebtEntry{
+		{Path: "packages/web/app.js", TotalDebt: 4},
+	}
+	coverage := []types.CoverageEntry{
+		{Path: "packages/api/index.js", LinesCovered: 80, LinesTotal: 100},
+		{Path: "packages/api/routes.js", LinesCovered: 10, LinesTotal: 50},
+	}
+
+	entries := GenerateWorkspaceLeaderboard(workspaces, files, fileStats, linesOfCode, debt, coverage)
+
+	expected := []types.WorkspaceEntry{
+		// Outside every workspace, including the look-alike packages/webhooks
+		{Name: ".", Path: ".", Files: 2, LinesOfCode: 10},
+		{Name: "@acme/api", Path: "packages/api", Kind: "npm", Files: 2, LinesOfCode: 150, Issues: 5, LinesCovered: 90, LinesTotal: 150},
+		{Name: "@acme/unused", Path: "packages/unused", Kind: "npm"},
+		{Name: "@acme/web", Path: "packages/web", Kind: "npm", Files: 1, Debt: 4},
+		// A nested package keeps its own files
+		{Name: "@acme/web-widgets", Path: "packages/web/widgets", Kind: "npm", Files: 1, Issues: 1},
+	}
+	if !reflect.DeepEqual(entries, expected) {
+		t.Errorf("Expected %+v, but got %+v", expected, entries)
+	}
+}
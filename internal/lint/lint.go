@@ -0,0 +1,85 @@
+// Package lint defines the interface CodeCompass uses to collect issues from
+// linters, along with a registry of the sources available to a run.
+package lint
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/xeon-zolt/codecompass/internal/config"
+	"github.com/xeon-zolt/codecompass/internal/eslint"
+	"github.com/xeon-zolt/codecompass/internal/ruff"
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+// Source is a linter CodeCompass can collect issues from.
+type Source interface {
+	// Name identifies the source in output and timings.
+	Name() string
+
+	// Detect reports whether the source applies to the repository in dir.
+	// Sources that run a command to find out stop when ctx is done.
+	Detect(ctx context.Context, dir string) bool
+
+	// Run lints the repository in dir and returns issues for the given
+	// files. Issue file paths are relative to dir.
+	Run(ctx context.Context, dir string, files map[string]bool, cfg *config.Config) ([]types.Issue, error)
+}
+
+// Versioned is implemented by sources that can tell the versions of the
+// tools they run, such as the linter and the runtime it runs on, by tool
+// name. Tools that do not tell their version are mapped to
+// toolversion.Unknown.
+type Versioned interface {
+	Versions(ctx context.Context, dir string) map[string]string
+}
+
+// Registry is an ordered set of lint sources with unique names.
+type Registry struct {
+	sources []Source
+	names   map[string]bool
+}
+
+func NewRegistry() *Registry {
+	return &Registry{names: make(map[string]bool)}
+}
+
+// Register adds source to the registry. Names must be unique.
+func (r *Registry) Register(source Source) error {
+	if r.names[source.Name()] {
+		return fmt.Errorf("lint source %q is already registered", source.Name())
+	}
+	r.names[source.Name()] = true
+	r.sources = append(r.sources, source)
+	return nil
+}
+
+// Sources returns the registered sources in registration order.
+func (r *Registry) Sources() []Source {
+	return append([]Source{}, r.sources...)
+}
+
+// BuiltinRegistry returns the built-in ESLint and Ruff sources.
+func BuiltinRegistry() *Registry {
+	registry := NewRegistry()
+	registry.Register(eslint.Source{})
+	registry.Register(ruff.Source{})
+	return registry
+}
+
+// DefaultRegistry returns the built-in sources followed by any exec plugins
+// found on PATH. Plugins that reuse a built-in name are skipped with a
+// warning logged to logger.
+func DefaultRegistry(logger *slog.Logger) *Registry {
+	registry := BuiltinRegistry()
+
+	for _, plugin := range DiscoverPlugins(os.Getenv("PATH")) {
+		if err := registry.Register(plugin); err != nil {
+			logger.Warn("Skipped lint plugin", "plugin", plugin.Name(), "path", plugin.Path(), "error", err)
+		}
+	}
+
+	return registry
+}
@@ -0,0 +1,211 @@
+package lint
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/xeon-zolt/codecompass/internal/config"
+	"github.com/xeon-zolt/codecompass/internal/repopath"
+	"github.com/xeon-zolt/codecompass/internal/toolversion"
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+// PluginPrefix is the executable name prefix for exec plugins. A plugin
+// named codecompass-lint-foo is registered as the "foo" source.
+//
+// The protocol is deliberately small:
+//
+//   - "codecompass-lint-foo detect" runs in the repository root and exits 0
+//     when the plugin applies to the repository.
+//   - "codecompass-lint-foo run" runs in the repository root, reads a
+//     PluginRequest as JSON on stdin and writes a PluginResponse as JSON on
+//     stdout. A non-zero exit status fails the source.
+//   - "codecompass-lint-foo version" is optional and prints the version of
+//     the plugin, or of the linter it wraps, on stdout. Plugins that do not
+//     answer it are recorded with an unknown version.
+//
+// Issues for files that were not in the request, or for ignored rules, are
+// dropped.
+const PluginPrefix = "codecompass-lint-"
+
+// detectTimeout bounds how long a plugin may take to answer detect.
+const detectTimeout = 10 * time.Second
+
+// PluginRequest is sent to a plugin on stdin.
+type PluginRequest struct {
+	// Root is the absolute path of the repository.
+	Root string `json:"root"`
+
+	// Files are the tracked files to lint, relative to Root with forward
+	// slashes, in sorted order.
+	Files []string `json:"files"`
+}
+
+// PluginResponse is read from a plugin's stdout.
+type PluginResponse struct {
+	Issues []PluginIssue `json:"issues"`
+}
+
+// PluginIssue is a single issue reported by a plugin. Severity is 1 for a
+// warning and 2 for an error; zero is treated as a warning.
+type PluginIssue struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Rule     string `json:"rule"`
+	Message  string `json:"message"`
+	Severity int    `json:"severity"`
+}
+
+// Plugin is a lint source backed by an executable following the plugin
+// protocol.
+type Plugin struct {
+	name string
+	path string
+}
+
+// NewPlugin returns a plugin for the executable at path. The source name is
+// the file name without PluginPrefix.
+func NewPlugin(path string) *Plugin {
+	// Plugins run in the repository, so a relative path would not resolve
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+
+	return &Plugin{
+		name: strings.TrimPrefix(filepath.Base(path), PluginPrefix),
+		path: path,
+	}
+}
+
+// DiscoverPlugins finds executables named codecompass-lint-* in the
+// directories of pathList. As with command lookup, the first directory
+// wins when a name appears more than once. Plugins are sorted by name.
+func DiscoverPlugins(pathList string) []*Plugin {
+	seen := make(map[string]bool)
+	var plugins []*Plugin
+
+	for _, dir := range filepath.SplitList(pathList) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			name := entry.Name()
+			if !strings.HasPrefix(name, PluginPrefix) || name == PluginPrefix || seen[name] {
+				continue
+			}
+
+			info, err := entry.Info()
+			if err != nil || !info.Mode().IsRegular() || info.Mode().Perm()&0111 == 0 {
+				continue
+			}
+
+			seen[name] = true
+			plugins = append(plugins, NewPlugin(filepath.Join(dir, name)))
+		}
+	}
+
+	sort.Slice(plugins, func(i, j int) bool {
+		return plugins[i].name < plugins[j].name
+	})
+
+	return plugins
+}
+
+func (p *Plugin) Name() string {
+	return p.name
+}
+
+// Path returns the plugin executable.
+func (p *Plugin) Path() string {
+	return p.path
+}
+
+func (p *Plugin) Detect(ctx context.Context, dir string) bool {
+	ctx, cancel := context.WithTimeout(ctx, detectTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, p.path, "detect")
+	cmd.Dir = dir
+	return cmd.Run() == nil
+}
+
+// Versions returns the version the plugin prints for "version".
+func (p *Plugin) Versions(ctx context.Context, dir string) map[string]string {
+	return map[string]string{p.name: toolversion.Command(ctx, dir, p.path, "version")}
+}
+
+func (p *Plugin) Run(ctx context.Context, dir string, files map[string]bool, cfg *config.Config) ([]types.Issue, error) {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve plugin directory: %w", err)
+	}
+
+	request := PluginRequest{Root: root, Files: []string{}}
+	for file := range files {
+		request.Files = append(request.Files, filepath.ToSlash(file))
+	}
+	sort.Strings(request.Files)
+
+	input, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode plugin request: %w", err)
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, p.path, "run")
+	cmd.Dir = dir
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s failed: %w: %s", p.name, err, strings.TrimSpace(stderr.String()))
+	}
+
+	var response PluginResponse
+	if err := json.Unmarshal(output, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse plugin %s output: %w", p.name, err)
+	}
+
+	requested := make(map[string]bool, len(request.Files))
+	for _, file := range request.Files {
+		requested[file] = true
+	}
+
+	resolver := repopath.New(root, requested)
+	var issues []types.Issue
+	for _, issue := range response.Issues {
+		file := resolver.Resolve(issue.File)
+		if !requested[file] || (cfg != nil && cfg.ShouldIgnoreRule(issue.Rule)) {
+			continue
+		}
+
+		severity := issue.Severity
+		if severity == 0 {
+			severity = 1
+		}
+
+		issues = append(issues, types.Issue{
+			FilePath: file,
+			Line:     issue.Line,
+			RuleID:   issue.Rule,
+			Message:  issue.Message,
+			Severity: severity,
+		})
+	}
+
+	return issues, nil
+}
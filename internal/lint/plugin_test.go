@@ -0,0 +1,203 @@
+package lint
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xeon-zolt/codecompass/internal/config"
+	"github.com/xeon-zolt/codecompass/internal/logging"
+	"github.com/xeon-zolt/codecompass/internal/testutil"
+	"github.com/xeon-zolt/codecompass/internal/toolversion"
+)
+
+// samplePlugins is the directory holding the sample plugin shipped with the
+// repository.
+var samplePlugins = filepath.Join("..", "..", "examples", "plugins")
+
+func writeExecutable(t *testing.T, dir, name, content string) {
+	t.Helper()
+
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDiscoverPlugins(t *testing.T) {
+	first := t.TempDir()
+	second := t.TempDir()
+
+	writeExecutable(t, first, "codecompass-lint-b", "#!/bin/sh\n")
+	writeExecutable(t, second, "codecompass-lint-b", "#!/bin/sh\n")
+	writeExecutable(t, second, "codecompass-lint-a", "#!/bin/sh\n")
+	if err := os.WriteFile(filepath.Join(second, "codecompass-lint-noexec"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	writeExecutable(t, second, "unrelated", "#!/bin/sh\n")
+
+	plugins := DiscoverPlugins(first + string(os.PathListSeparator) + second)
+	if len(plugins) != 2 {
+		t.Fatalf("Expected 2 plugins, but got %d", len(plugins))
+	}
+
+	if plugins[0].Name() != "a" || plugins[1].Name() != "b" {
+		t.Errorf("Expected plugins a and b, but got %s and %s", plugins[0].Name(), plugins[1].Name())
+	}
+
+	// The first directory on the path wins
+	if filepath.Dir(plugins[1].Path()) != first {
+		t.Errorf("Expected plugin b from %s, but got %s", first, plugins[1].Path())
+	}
+}
+
+func TestRegistryRejectsDuplicateNames(t *testing.T) {
+	registry := NewRegistry()
+	if err := registry.Register(NewPlugin("/bin/codecompass-lint-x")); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.Register(NewPlugin("/usr/bin/codecompass-lint-x")); err == nil {
+		t.Errorf("Expected an error registering a duplicate source name")
+	}
+	if len(registry.Sources()) != 1 {
+		t.Errorf("Expected 1 registered source, but got %d", len(registry.Sources()))
+	}
+}
+
+func TestDefaultRegistryWarnsAboutShadowingPlugins(t *testing.T) {
+	bin := t.TempDir()
+	writeExecutable(t, bin, "codecompass-lint-eslint", "#!/bin/sh\n")
+	writeExecutable(t, bin, "codecompass-lint-todo", "#!/bin/sh\n")
+	t.Setenv("PATH", bin)
+
+	collector := logging.NewCollector(logging.Discard().Handler(), nil)
+	registry := DefaultRegistry(slog.New(collector))
+
+	var names []string
+	for _, source := range registry.Sources() {
+		names = append(names, source.Name())
+	}
+	if strings.Join(names, ",") != "eslint,ruff,todo" {
+		t.Errorf("Expected eslint, ruff and todo sources, but got %v", names)
+	}
+
+	warnings := collector.Warnings()
+	if len(warnings) != 1 || !strings.Contains(warnings[0].String(), "codecompass-lint-eslint") {
+		t.Errorf("Expected a warning naming the skipped plugin, but got %v", warnings)
+	}
+}
+
+func TestPluginDetectCancelled(t *testing.T) {
+	bin := t.TempDir()
+	writeExecutable(t, bin, "codecompass-lint-slow", "#!/bin/sh\nexec sleep 30\n")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	start := time.Now()
+	if NewPlugin(filepath.Join(bin, "codecompass-lint-slow")).Detect(ctx, t.TempDir()) {
+		t.Errorf("Expected a cancelled detect to fail")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected cancellation to stop detect promptly, but it took %v", elapsed)
+	}
+}
+
+func TestSamplePlugin(t *testing.T) {
+	repo := testutil.NewRepo(t).Write(map[string]string{
+		"main.js":         "// TODO: remove\nconsole.log(1);\n",
+		"skipped.js":      "// TODO: not requested\n",
+		`odd "name":1.js`: "// TODO: escaped\n",
+	})
+	repo.Git("add", ".")
+	dir := repo.Dir()
+
+	plugins := DiscoverPlugins(samplePlugins)
+	if len(plugins) != 1 || plugins[0].Name() != "todo" {
+		t.Fatalf("Expected the sample todo plugin, but got %v", plugins)
+	}
+	plugin := plugins[0]
+
+	if !plugin.Detect(context.Background(), dir) {
+		t.Fatalf("Expected the sample plugin to detect a git repository")
+	}
+	if plugin.Detect(context.Background(), t.TempDir()) {
+		t.Errorf("Expected the sample plugin not to detect a plain directory")
+	}
+
+	issues, err := plugin.Run(context.Background(), dir, map[string]bool{"main.js": true}, config.NewConfig())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	// Quotes and colons in file names must not break the JSON response
+	odd, err := plugin.Run(context.Background(), dir, map[string]bool{`odd "name":1.js`: true}, config.NewConfig())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(odd) != 1 || odd[0].FilePath != `odd "name":1.js` {
+		t.Errorf("Expected one issue for the oddly named file, but got %+v", odd)
+	}
+
+	if len(issues) != 1 {
+		t.Fatalf("Expected 1 issue, but got %+v", issues)
+	}
+
+	issue := issues[0]
+	if issue.FilePath != "main.js" || issue.Line != 1 || issue.RuleID != "todo/todo-comment" || issue.Severity != 1 {
+		t.Errorf("Unexpected issue: %+v", issue)
+	}
+
+	// Ignored rules are dropped
+	cfg := config.NewConfig()
+	cfg.IgnoredRules = []string{"todo/todo-comment"}
+	issues, err = plugin.Run(context.Background(), dir, map[string]bool{"main.js": true}, cfg)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("Expected ignored rule to be dropped, but got %+v", issues)
+	}
+}
+
+func TestPluginRunFailure(t *testing.T) {
+	bin := t.TempDir()
+	writeExecutable(t, bin, "codecompass-lint-broken", "#!/bin/sh\necho boom >&2\nexit 3\n")
+
+	_, err := NewPlugin(filepath.Join(bin, "codecompass-lint-broken")).Run(context.Background(), t.TempDir(), nil, nil)
+	if err == nil {
+		t.Fatalf("Expected an error from a failing plugin")
+	}
+	if got := err.Error(); !strings.Contains(got, "boom") {
+		t.Errorf("Expected the error to include stderr, but got %q", got)
+	}
+}
+
+func TestPluginVersions(t *testing.T) {
+	bin := t.TempDir()
+	writeExecutable(t, bin, "codecompass-lint-versioned", "#!/bin/sh\n[ \"$1\" = version ] && echo \"mylinter v2.3.1\"\n")
+	writeExecutable(t, bin, "codecompass-lint-unversioned", "#!/bin/sh\necho \"usage: $0 detect|run\" >&2\nexit 2\n")
+
+	tests := []struct {
+		plugin string
+		want   string
+	}{
+		{"versioned", "2.3.1"},
+		{"unversioned", toolversion.Unknown},
+	}
+	for _, tt := range tests {
+		plugin := NewPlugin(filepath.Join(bin, PluginPrefix+tt.plugin))
+		got := plugin.Versions(context.Background(), t.TempDir())
+		if len(got) != 1 || got[tt.plugin] != tt.want {
+			t.Errorf("Versions() of %s = %v, want %s=%s", tt.plugin, got, tt.plugin, tt.want)
+		}
+	}
+
+	sample := NewPlugin(filepath.Join(samplePlugins, "codecompass-lint-todo"))
+	if got := sample.Versions(context.Background(), t.TempDir()); got["todo"] != "1.0.0" {
+		t.Errorf("Versions() of the sample plugin = %v, want todo=1.0.0", got)
+	}
+}
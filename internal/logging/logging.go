@@ -0,0 +1,94 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+
+	"github.com/xeon-zolt/codecompass/internal/types"
+	"github.com/xeon-zolt/codecompass/internal/utils"
+)
+
+// New returns a logger that writes records at or above level to w, either as
+// human-readable text or as JSON lines.
+func New(w io.Writer, level slog.Leveler, jsonFormat bool) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: level}
+	if jsonFormat {
+		return slog.New(slog.NewJSONHandler(w, opts))
+	}
+	return slog.New(slog.NewTextHandler(w, opts))
+}
+
+// Discard returns a logger that drops every record.
+func Discard() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelError + 1}))
+}
+
+// Collector is a slog.Handler that adds every warning and error it sees to a
+// utils.WarningCollector before passing records on to the
+// wrapped handler. It lets a run report its warnings at the end regardless
+// of the log level in use.
+type Collector struct {
+	next  slog.Handler
+	attrs []slog.Attr
+	store *utils.WarningCollector
+}
+
+// NewCollector wraps next in a Collector that adds warnings to store. A nil
+// store gets a new collector of its own.
+func NewCollector(next slog.Handler, store *utils.WarningCollector) *Collector {
+	if store == nil {
+		store = utils.NewWarningCollector()
+	}
+	return &Collector{next: next, store: store}
+}
+
+func (c *Collector) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= slog.LevelWarn || c.next.Enabled(ctx, level)
+}
+
+func (c *Collector) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level >= slog.LevelWarn {
+		c.store.Add(recordWarning(r, c.attrs))
+	}
+	if c.next.Enabled(ctx, r.Level) {
+		return c.next.Handle(ctx, r)
+	}
+	return nil
+}
+
+func (c *Collector) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &Collector{
+		next:  c.next.WithAttrs(attrs),
+		attrs: append(append([]slog.Attr{}, c.attrs...), attrs...),
+		store: c.store,
+	}
+}
+
+func (c *Collector) WithGroup(name string) slog.Handler {
+	return &Collector{next: c.next.WithGroup(name), attrs: c.attrs, store: c.store}
+}
+
+// Warnings returns the collected warnings in the order they were logged.
+func (c *Collector) Warnings() []types.Warning {
+	return c.store.Warnings()
+}
+
+// recordWarning turns a record into a warning with its attributes as
+// params, which renders as "⚠️ Blame failed (file=main.js error=exit status
+// 128)".
+func recordWarning(r slog.Record, attrs []slog.Attr) types.Warning {
+	warning := types.Warning{Message: r.Message}
+	appendAttr := func(a slog.Attr) bool {
+		// The phase is context for logs, not useful in the summary
+		if a.Key != "phase" {
+			warning.Params = append(warning.Params, types.WarningParam{Key: a.Key, Value: a.Value.String()})
+		}
+		return true
+	}
+	for _, a := range attrs {
+		appendAttr(a)
+	}
+	r.Attrs(appendAttr)
+	return warning
+}
@@ -0,0 +1,55 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestCollectorKeepsWarningsBelowHandlerLevel(t *testing.T) {
+	var buf bytes.Buffer
+	collector := NewCollector(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelError}), nil)
+	logger := slog.New(collector).With("phase", "blame")
+
+	logger.Debug("ignored")
+	logger.Warn("Blame failed", "file", "main.js", "error", "exit status 128")
+	logger.Error("Analysis failed")
+
+	warnings := collector.Warnings()
+	if len(warnings) != 2 {
+		t.Fatalf("Expected 2 collected warnings, but got %v", warnings)
+	}
+
+	if warnings[0].String() != "⚠️ Blame failed (file=main.js error=exit status 128)" || warnings[0].Param("file") != "main.js" {
+		t.Errorf("Unexpected warning: %+v", warnings[0])
+	}
+
+	if warnings[1].String() != "⚠️ Analysis failed" {
+		t.Errorf("Unexpected warning: %+v", warnings[1])
+	}
+
+	// Only the error reaches the wrapped handler
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("Expected 1 JSON log line, but got %d: %s", len(lines), buf.String())
+	}
+
+	var record map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatalf("Expected a JSON log line, but got %q: %v", lines[0], err)
+	}
+	if record["msg"] != "Analysis failed" || record["phase"] != "blame" {
+		t.Errorf("Unexpected JSON record: %v", record)
+	}
+}
+
+func TestDiscard(t *testing.T) {
+	collector := NewCollector(Discard().Handler(), nil)
+	slog.New(collector).Warn("still collected")
+
+	if len(collector.Warnings()) != 1 {
+		t.Errorf("Expected warnings to be collected from a discarding logger")
+	}
+}
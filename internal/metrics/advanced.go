@@ -2,6 +2,7 @@ package metrics
 
 import (
 	"bufio"
+	"context"
 	"os"
 	"os/exec"
 	"regexp"
@@ -35,8 +36,8 @@ type TechnicalDebtEntry struct {
 	TotalDebt  int
 }
 
-func GetCodeChurnLeaderboard(trackedFiles map[string]bool) ([]ChurnEntry, error) {
-	cmd := exec.Command("git", "log", "--numstat", "--pretty=format:")
+func GetCodeChurnLeaderboard(ctx context.Context, trackedFiles map[string]bool) ([]ChurnEntry, error) {
+	cmd := exec.CommandContext(ctx, "git", "-c", "core.quotePath=false", "log", "--numstat", "--pretty=format:")
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, err
@@ -83,9 +84,9 @@ func GetCodeChurnLeaderboard(trackedFiles map[string]bool) ([]ChurnEntry, error)
 	return entries, nil
 }
 
-func GetBugDensityLeaderboard(trackedFiles map[string]bool) ([]BugDensityEntry, error) {
+func GetBugDensityLeaderboard(ctx context.Context, trackedFiles map[string]bool) ([]BugDensityEntry, error) {
 	// Get all commits
-	cmd := exec.Command("git", "log", "--name-only", "--pretty=format:%H|%s")
+	cmd := exec.CommandContext(ctx, "git", "-c", "core.quotePath=false", "log", "--name-only", "--pretty=format:%H|%s")
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, err
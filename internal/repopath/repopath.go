@@ -0,0 +1,137 @@
+// Package repopath resolves the paths linters and coverage reports give for
+// a file to the one git tracks it under, so every leaderboard keys on the
+// same string. The same file can be named absolutely, through a symlink,
+// such as a repository under /tmp on macOS, where it is /private/tmp, or in
+// another case on case-insensitive file systems.
+package repopath
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Resolver resolves paths against the tracked files of a repository.
+type Resolver struct {
+	root     string          // Absolute
+	realRoot string          // root with its symlinks resolved
+	files    map[string]bool // Slash-separated, relative to root
+
+	// folded indexes files by their lowercase path, built on the first
+	// path that needs it
+	folded map[string][]string
+}
+
+// New returns a Resolver for files, relative to dir with forward slashes as
+// git lists them.
+func New(dir string, files map[string]bool) *Resolver {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		root = dir
+	}
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		realRoot = root
+	}
+	return &Resolver{root: root, realRoot: realRoot, files: files}
+}
+
+// Resolve returns path, absolute or relative to the repository root, as it
+// is tracked: relative to the root with forward slashes, without the
+// symlinks in the repository it goes through, and in the case git knows it
+// by when the file system ignores case. Paths of files that are not
+// tracked come back cleaned, relative to the root when they are in it.
+func (r *Resolver) Resolve(path string) string {
+	rel, local := r.relative(path)
+	if !local || r.files[rel] {
+		return rel
+	}
+
+	if resolved, ok := r.resolveSymlinks(rel); ok {
+		if r.files[resolved] {
+			return resolved
+		}
+		if tracked, ok := r.matchCase(resolved); ok {
+			return tracked
+		}
+	}
+	if tracked, ok := r.matchCase(rel); ok {
+		return tracked
+	}
+	return rel
+}
+
+// relative returns path cleaned with forward slashes, relative to the root
+// when it is in the repository, and whether it is.
+func (r *Resolver) relative(path string) (string, bool) {
+	path = filepath.Clean(filepath.FromSlash(path))
+	if !filepath.IsAbs(path) {
+		return filepath.ToSlash(path), filepath.IsLocal(path)
+	}
+
+	rel, ok := within(r.root, path)
+	if !ok {
+		rel, ok = within(r.realRoot, path)
+	}
+	if !ok {
+		// Such as a path under /private/tmp for a root under /tmp
+		if resolved, err := filepath.EvalSymlinks(path); err == nil {
+			rel, ok = within(r.realRoot, resolved)
+		}
+	}
+	if !ok {
+		return filepath.ToSlash(path), false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// resolveSymlinks returns rel with the symlinks it goes through resolved,
+// when they stay in the repository.
+func (r *Resolver) resolveSymlinks(rel string) (string, bool) {
+	resolved, err := filepath.EvalSymlinks(filepath.Join(r.root, filepath.FromSlash(rel)))
+	if err != nil {
+		return "", false
+	}
+	resolvedRel, ok := within(r.realRoot, resolved)
+	if !ok {
+		return "", false
+	}
+	return filepath.ToSlash(resolvedRel), true
+}
+
+// matchCase returns the tracked file rel names in another case. Only a file
+// that is the same one on disk counts, so files differing in case on a
+// case-sensitive file system are told apart.
+func (r *Resolver) matchCase(rel string) (string, bool) {
+	if r.folded == nil {
+		r.folded = make(map[string][]string, len(r.files))
+		for file := range r.files {
+			lower := strings.ToLower(file)
+			r.folded[lower] = append(r.folded[lower], file)
+		}
+	}
+
+	candidates := r.folded[strings.ToLower(rel)]
+	if len(candidates) == 0 {
+		return "", false
+	}
+	info, err := os.Stat(filepath.Join(r.root, filepath.FromSlash(rel)))
+	if err != nil {
+		return "", false
+	}
+	for _, candidate := range candidates {
+		if candidateInfo, err := os.Stat(filepath.Join(r.root, filepath.FromSlash(candidate))); err == nil && os.SameFile(info, candidateInfo) {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+// within returns path relative to root when it is in root.
+func within(root, path string) (string, bool) {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || !filepath.IsLocal(rel) {
+		return "", false
+	}
+	return rel, true
+}
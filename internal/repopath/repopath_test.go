@@ -0,0 +1,134 @@
+package repopath
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// newRepo writes files, by slash-separated path, to a new directory and
+// returns it with the files as tracked.
+func newRepo(t *testing.T, files ...string) (string, map[string]bool) {
+	t.Helper()
+
+	dir := t.TempDir()
+	tracked := make(map[string]bool)
+	for _, file := range files {
+		path := filepath.Join(dir, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(file), 0644); err != nil {
+			t.Fatal(err)
+		}
+		tracked[file] = true
+	}
+	return dir, tracked
+}
+
+func TestResolve(t *testing.T) {
+	dir, tracked := newRepo(t, "src/app.js", "README.md")
+	resolver := New(dir, tracked)
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"src/app.js", "src/app.js"},
+		{"./src/../src/app.js", "src/app.js"},
+		{filepath.Join(dir, "src", "app.js"), "src/app.js"},
+		{filepath.Join(dir, "src", "new.js"), "src/new.js"},
+		{"src/missing.js", "src/missing.js"},
+		{"../other/app.js", "../other/app.js"},
+		{"/elsewhere/app.js", "/elsewhere/app.js"},
+	}
+	for _, tt := range tests {
+		if got := resolver.Resolve(tt.path); got != tt.want {
+			t.Errorf("Resolve(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestResolveSymlinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks need privileges on Windows")
+	}
+
+	dir, tracked := newRepo(t, "src/app.js")
+	// A symlinked directory in the repository, and one leaving it
+	if err := os.Symlink("src", filepath.Join(dir, "lib")); err != nil {
+		t.Fatal(err)
+	}
+	outside, _ := newRepo(t, "app.js")
+	if err := os.Symlink(outside, filepath.Join(dir, "vendor")); err != nil {
+		t.Fatal(err)
+	}
+	// The repository reached through a symlink, as /tmp is on macOS
+	link := filepath.Join(t.TempDir(), "repo")
+	if err := os.Symlink(dir, link); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, root := range []string{dir, link} {
+		resolver := New(root, tracked)
+		tests := []struct {
+			path string
+			want string
+		}{
+			{"lib/app.js", "src/app.js"},
+			{filepath.Join(dir, "lib", "app.js"), "src/app.js"},
+			{filepath.Join(dir, "src", "app.js"), "src/app.js"},
+			{filepath.Join(link, "lib", "app.js"), "src/app.js"},
+			{"vendor/app.js", "vendor/app.js"},
+		}
+		for _, tt := range tests {
+			if got := resolver.Resolve(tt.path); got != tt.want {
+				t.Errorf("With root %s, Resolve(%q) = %q, want %q", root, tt.path, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestResolveCase(t *testing.T) {
+	dir, tracked := newRepo(t, "src/Button.tsx")
+	resolver := New(dir, tracked)
+
+	// Only a case-insensitive file system finds the file in another case
+	_, err := os.Stat(filepath.Join(dir, "SRC", "button.tsx"))
+	insensitive := err == nil
+
+	want := "SRC/button.tsx"
+	if insensitive {
+		want = "src/Button.tsx"
+	}
+	if got := resolver.Resolve("SRC/button.tsx"); got != want {
+		t.Errorf("Resolve(%q) = %q, want %q (case-insensitive: %v)", "SRC/button.tsx", got, want, insensitive)
+	}
+	if got := resolver.Resolve(filepath.Join(dir, "src", "BUTTON.tsx")); insensitive && got != "src/Button.tsx" {
+		t.Errorf("Resolve() of an absolute path in another case = %q, want src/Button.tsx", got)
+	}
+}
+
+func TestResolveKeepsCaseVariantsApart(t *testing.T) {
+	dir, tracked := newRepo(t, "notes.md")
+	if _, err := os.Stat(filepath.Join(dir, "NOTES.md")); err == nil {
+		t.Skip("the file system ignores case")
+	}
+	// Two files differing only in case are both tracked on a case-sensitive
+	// file system
+	if err := os.WriteFile(filepath.Join(dir, "NOTES.md"), []byte("other"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tracked["NOTES.md"] = true
+
+	resolver := New(dir, tracked)
+	for _, path := range []string{"notes.md", "NOTES.md"} {
+		if got := resolver.Resolve(path); got != path {
+			t.Errorf("Resolve(%q) = %q, want it unchanged", path, got)
+		}
+	}
+	if got := resolver.Resolve("Notes.md"); got != "Notes.md" {
+		t.Errorf("Resolve(%q) = %q, want it unchanged as no such file exists", "Notes.md", got)
+	}
+}
@@ -0,0 +1,64 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+// SaveReport writes r to path as indented JSON.
+func SaveReport(path string, r *types.Report) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+// LoadReport reads a report written by SaveReport. Reports of a newer schema
+// than this version of CodeCompass knows are rejected, since the fields it
+// does not know would be lost.
+func LoadReport(path string) (*types.Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report: %w", err)
+	}
+
+	var r types.Report
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("failed to parse report %s: %w", path, err)
+	}
+	if err := r.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid report %s: %w", path, err)
+	}
+	return &r, nil
+}
+
+// SaveAttentionList writes list to path as indented JSON.
+func SaveAttentionList(path string, list types.AttentionList) error {
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode attention list: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write attention list: %w", err)
+	}
+	return nil
+}
+
+// SaveDiagnostics writes diagnostics to path as indented JSON.
+func SaveDiagnostics(path string, diagnostics types.Diagnostics) error {
+	data, err := json.MarshalIndent(diagnostics, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode warnings: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write warnings: %w", err)
+	}
+	return nil
+}
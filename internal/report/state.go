@@ -0,0 +1,158 @@
+package report
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// StateVersion is the format of the state file. Sections saved in another
+// format are discarded when the file is loaded.
+const StateVersion = 1
+
+// State records the sections of a run that finished, so a run that is
+// interrupted can be resumed without computing them again. Each section is
+// saved with a hash of the inputs it was computed from, and is only reused
+// while that hash still matches.
+type State struct {
+	Version  int                      `json:"version"`
+	Sections map[string]*StateSection `json:"sections"`
+
+	path string
+	mu   sync.Mutex
+}
+
+// StateSection is one finished section of a run.
+type StateSection struct {
+	InputHash string            `json:"input_hash"`
+	Completed time.Time         `json:"completed"`
+	Results   []json.RawMessage `json:"results"`
+}
+
+// NewState returns an empty state that is saved to path.
+func NewState(path string) *State {
+	return &State{Version: StateVersion, Sections: make(map[string]*StateSection), path: path}
+}
+
+// LoadState reads the state file at path. A missing file, or one written in
+// another format, gives an empty state.
+func LoadState(path string) (*State, error) {
+	state := NewState(path)
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	var saved State
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("failed to parse state file %s: %w", path, err)
+	}
+	if saved.Version == StateVersion && saved.Sections != nil {
+		state.Sections = saved.Sections
+	}
+	return state, nil
+}
+
+// Path returns the file the state is saved to.
+func (s *State) Path() string {
+	return s.path
+}
+
+// Lookup decodes the saved results of section into results, which must be
+// pointers in the order they were passed to Complete. It reports false,
+// leaving results untouched, when the section has not finished or was
+// computed from other inputs.
+func (s *State) Lookup(section, inputHash string, results ...any) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	saved := s.Sections[section]
+	if saved == nil || saved.InputHash != inputHash || len(saved.Results) != len(results) {
+		return false
+	}
+
+	// Decode everything before assigning so a bad entry changes nothing
+	decoded := make([]reflect.Value, len(results))
+	for i, raw := range saved.Results {
+		target := reflect.ValueOf(results[i])
+		if target.Kind() != reflect.Pointer || target.IsNil() {
+			return false
+		}
+		value := reflect.New(target.Elem().Type())
+		if json.Unmarshal(raw, value.Interface()) != nil {
+			return false
+		}
+		decoded[i] = value.Elem()
+	}
+	for i, result := range results {
+		reflect.ValueOf(result).Elem().Set(decoded[i])
+	}
+	return true
+}
+
+// Complete records results as the output of section and saves the state.
+func (s *State) Complete(section, inputHash string, results ...any) error {
+	encoded := make([]json.RawMessage, len(results))
+	for i, result := range results {
+		raw, err := json.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s results: %w", section, err)
+		}
+		encoded[i] = raw
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.Sections[section] = &StateSection{InputHash: inputHash, Completed: time.Now().UTC(), Results: encoded}
+	return s.save()
+}
+
+// save writes the state to a temporary file and renames it into place, so a
+// crash while saving leaves the previous state intact.
+func (s *State) save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	defer os.Remove(temp.Name())
+
+	if _, err := temp.Write(data); err != nil {
+		temp.Close()
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := temp.Close(); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := os.Rename(temp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	return nil
+}
+
+// InputHash returns a hash identifying parts, for comparing the inputs a
+// section was computed from.
+func InputHash(parts ...string) string {
+	hash := sha256.New()
+	for _, part := range parts {
+		// Length prefixes keep {"ab", "c"} and {"a", "bc"} apart
+		fmt.Fprintf(hash, "%d:%s;", len(part), part)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
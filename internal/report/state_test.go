@@ -0,0 +1,79 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+func TestStateRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	state, err := LoadState(path)
+	if err != nil {
+		t.Fatalf("Expected a missing state file to load as empty, but got %v", err)
+	}
+
+	entries := []types.LinesOfCodeEntry{{Rank: 1, Path: "main.go", Lines: 42}}
+	overall := 87.5
+	if err := state.Complete("loc", "hash-1", entries, overall); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var gotEntries []types.LinesOfCodeEntry
+	var gotOverall float64
+	if !loaded.Lookup("loc", "hash-1", &gotEntries, &gotOverall) {
+		t.Fatal("Expected the saved section to be reused with a matching input hash")
+	}
+	if !reflect.DeepEqual(gotEntries, entries) || gotOverall != overall {
+		t.Errorf("Expected %+v and %v, but got %+v and %v", entries, overall, gotEntries, gotOverall)
+	}
+
+	gotEntries = nil
+	if loaded.Lookup("loc", "hash-2", &gotEntries, &gotOverall) || gotEntries != nil {
+		t.Errorf("Expected a changed input hash to miss, but got %+v", gotEntries)
+	}
+	if loaded.Lookup("churn", "hash-1", &gotEntries) {
+		t.Error("Expected a section that never finished to miss")
+	}
+}
+
+func TestLoadStateDiscardsOtherVersions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	data := `{"version": 999, "sections": {"loc": {"input_hash": "h", "results": [[]]}}}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	state, err := LoadState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(state.Sections) != 0 {
+		t.Errorf("Expected sections from another format to be discarded, but got %+v", state.Sections)
+	}
+
+	if err := os.WriteFile(path, []byte("not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadState(path); err == nil {
+		t.Error("Expected a corrupt state file to fail to load")
+	}
+}
+
+func TestInputHash(t *testing.T) {
+	if InputHash("ab", "c") == InputHash("a", "bc") {
+		t.Error("Expected differently split parts to hash differently")
+	}
+	if InputHash("a", "b") != InputHash("a", "b") {
+		t.Error("Expected equal parts to hash equally")
+	}
+}
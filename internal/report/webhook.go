@@ -0,0 +1,112 @@
+// Package report delivers finished reports to other systems, and keeps the
+// state that lets an interrupted run resume.
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+// DefaultWebhookTimeout bounds a webhook request when Webhook.Timeout is not
+// set.
+const DefaultWebhookTimeout = 10 * time.Second
+
+// Webhook posts a report as JSON to a URL, such as a Slack incoming webhook
+// or a CI endpoint.
+type Webhook struct {
+	// URL receives the POST request.
+	URL string
+
+	// Token is sent as a bearer token when it is not empty.
+	Token string
+
+	// Timeout bounds the whole request. Zero means DefaultWebhookTimeout.
+	Timeout time.Duration
+}
+
+// NewWebhook returns a Webhook for url with the default timeout.
+func NewWebhook(url, token string) *Webhook {
+	return &Webhook{URL: url, Token: token}
+}
+
+// Payload is the JSON body a Webhook posts. Text is a one-line summary,
+// which is what Slack displays; Report is the full result for endpoints that
+// process it.
+type Payload struct {
+	Text   string        `json:"text"`
+	Report *types.Report `json:"report"`
+}
+
+// NewPayload builds the payload for r.
+func NewPayload(r *types.Report) Payload {
+	return Payload{Text: Summary(r), Report: r}
+}
+
+// Summary describes r in one line, for example "🧭 CodeCompass: myrepo –
+// 17 lint issues (4 errors), grade B (82.5), 2 warning(s)".
+func Summary(r *types.Report) string {
+	var parts []string
+	if r.Summary != nil {
+		parts = append(parts, fmt.Sprintf("%d lint issues (%d errors)", r.Summary.TotalIssues, r.Summary.Errors))
+	}
+	if r.ReportCard != nil && r.ReportCard.Grade != "" {
+		parts = append(parts, fmt.Sprintf("grade %s (%.1f)", r.ReportCard.Grade, r.ReportCard.Score))
+	}
+	if len(r.Failures) > 0 {
+		parts = append(parts, fmt.Sprintf("%d failed leaderboard(s)", len(r.Failures)))
+	}
+	if len(r.Warnings) > 0 {
+		parts = append(parts, fmt.Sprintf("%d warning(s)", len(r.Warnings)))
+	}
+	if len(parts) == 0 {
+		parts = append(parts, fmt.Sprintf("%d leaderboards", len(r.Leaderboards)))
+	}
+
+	return fmt.Sprintf("🧭 CodeCompass: %s – %s", filepath.Base(r.Repo.Path), strings.Join(parts, ", "))
+}
+
+// Send posts r to the webhook. Responses outside 2xx are returned as errors
+// with the start of the response body.
+func (w *Webhook) Send(ctx context.Context, r *types.Report) error {
+	body, err := json.Marshal(NewPayload(r))
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	timeout := w.Timeout
+	if timeout == 0 {
+		timeout = DefaultWebhookTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+w.Token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 200))
+		return fmt.Errorf("webhook returned %s: %s", resp.Status, strings.TrimSpace(string(snippet)))
+	}
+	return nil
+}
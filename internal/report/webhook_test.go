@@ -0,0 +1,111 @@
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+func testReport() *types.Report {
+	r := types.NewReport("/src/myrepo")
+	r.Leaderboards = []string{"authors", "report-card"}
+	r.Authors = []types.LeaderboardEntry{{Rank: 1, Name: "Alice", Email: "alice@example.com", Count: 3}}
+	r.Summary = &types.SummaryStats{TotalIssues: 17, Errors: 4, Warnings: 13}
+	r.ReportCard = &types.ReportCard{Score: 82.5, Grade: "B"}
+	r.Warnings = []types.Warning{types.NewWarning("Blame failed", "file", "a.js", "error", "exit status 128")}
+	return &r
+}
+
+func TestWebhookSend(t *testing.T) {
+	var got map[string]json.RawMessage
+	var auth, contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		contentType = r.Header.Get("Content-Type")
+		if r.Method != http.MethodPost {
+			t.Errorf("Expected a POST request, but got %s", r.Method)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Errorf("Expected a JSON body, but got %q: %v", body, err)
+		}
+	}))
+	defer server.Close()
+
+	if err := NewWebhook(server.URL, "secret").Send(context.Background(), testReport()); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	if auth != "Bearer secret" || contentType != "application/json" {
+		t.Errorf("Expected a bearer token and JSON content type, but got %q and %q", auth, contentType)
+	}
+	if len(got) != 2 || got["text"] == nil || got["report"] == nil {
+		t.Fatalf("Expected a payload with text and report, but got keys %v", got)
+	}
+
+	var text string
+	if err := json.Unmarshal(got["text"], &text); err != nil {
+		t.Fatal(err)
+	}
+	if text != "🧭 CodeCompass: myrepo – 17 lint issues (4 errors), grade B (82.5), 1 warning(s)" {
+		t.Errorf("Unexpected summary %q", text)
+	}
+
+	var report types.Report
+	if err := json.Unmarshal(got["report"], &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.SchemaVersion != types.ReportSchemaVersion || len(report.Authors) != 1 || report.Authors[0].Name != "Alice" {
+		t.Errorf("Expected the full report in the payload, but got %+v", report)
+	}
+}
+
+func TestWebhookSendWithoutToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			t.Errorf("Expected no Authorization header, but got %q", auth)
+		}
+	}))
+	defer server.Close()
+
+	if err := NewWebhook(server.URL, "").Send(context.Background(), testReport()); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+}
+
+func TestWebhookSendErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid_token", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	err := NewWebhook(server.URL, "wrong").Send(context.Background(), testReport())
+	if err == nil || !strings.Contains(err.Error(), "403") || !strings.Contains(err.Error(), "invalid_token") {
+		t.Errorf("Expected an error with the status and body, but got %v", err)
+	}
+}
+
+func TestWebhookSendTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	webhook := &Webhook{URL: server.URL, Timeout: 50 * time.Millisecond}
+	start := time.Now()
+	if err := webhook.Send(context.Background(), testReport()); err == nil {
+		t.Error("Expected a timeout error")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected the timeout to stop the request promptly, but it took %v", elapsed)
+	}
+}
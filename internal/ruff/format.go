@@ -0,0 +1,180 @@
+package ruff
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/xeon-zolt/codecompass/internal/utils"
+)
+
+// FileDrift is how much of one file ruff would rewrite.
+type FileDrift struct {
+	Path string
+
+	// Lines maps the lines of the file as it is that ruff would rewrite to
+	// the number of changed lines each accounts for. A change replacing
+	// some lines with more charges the extra lines to the last one it
+	// replaces, and lines inserted on their own are charged to the line
+	// before them, so the counts add up to Changed.
+	Lines map[int]int
+}
+
+// Changed returns the number of lines ruff would change in the file.
+func (d FileDrift) Changed() int {
+	changed := 0
+	for _, count := range d.Lines {
+		changed += count
+	}
+	return changed
+}
+
+// RunFormatDiff runs ruff format --check --diff on files in dir and returns
+// the files it would reformat.
+func RunFormatDiff(ctx context.Context, dir string, files []string) ([]FileDrift, error) {
+	return runDiff(ctx, dir, append([]string{"format", "--check", "--diff"}, files...))
+}
+
+// RunImportDiff runs ruff check --diff with the import sorting rules on files
+// in dir and returns the files whose imports it would sort.
+func RunImportDiff(ctx context.Context, dir string, files []string) ([]FileDrift, error) {
+	return runDiff(ctx, dir, append([]string{"check", "--select=I", "--diff"}, files...))
+}
+
+// runDiff runs ruff with args in dir and parses the unified diff it prints.
+// Ruff exits with 1 when it would change a file, which is not an error.
+func runDiff(ctx context.Context, dir string, args []string) ([]FileDrift, error) {
+	if err := utils.RequireTool("ruff"); err != nil {
+		return nil, err
+	}
+	cmd := exec.CommandContext(ctx, "ruff", args...)
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr) && exitErr.ExitCode() == 1:
+	case errors.As(err, &exitErr):
+		return nil, fmt.Errorf("failed to run ruff %s: %w: %s", args[0], err, strings.TrimSpace(string(exitErr.Stderr)))
+	case err != nil:
+		return nil, fmt.Errorf("failed to run ruff %s: %w", args[0], err)
+	}
+
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve ruff directory: %w", err)
+	}
+	return ParseDiff(output, root), nil
+}
+
+// diffHunkHeader matches the header of a hunk of unified diff output,
+// capturing where it starts in the old file and how many lines it spans
+// there and in the new file.
+var diffHunkHeader = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+\d+(?:,(\d+))? @@`)
+
+// ParseDiff parses unified diff output into the lines each file would have
+// changed, sorted by path. Paths are made relative to root when they are
+// absolute.
+func ParseDiff(output []byte, root string) []FileDrift {
+	byPath := make(map[string]*FileDrift)
+	var current *FileDrift
+
+	// oldLeft and newLeft are the lines of the current hunk still to come,
+	// which tells a removed line starting with "-- " from a file header
+	var oldLine, oldLeft, newLeft int
+	var removed []int
+	var added int
+
+	// flush charges the change read so far to the lines it replaces
+	flush := func() {
+		if current != nil && (len(removed) > 0 || added > 0) {
+			if len(removed) == 0 {
+				current.Lines[max(oldLine-1, 1)] += added
+			}
+			for _, line := range removed {
+				current.Lines[line]++
+			}
+			if extra := added - len(removed); extra > 0 && len(removed) > 0 {
+				current.Lines[removed[len(removed)-1]] += extra
+			}
+		}
+		removed, added = nil, 0
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(nil, max(len(output)+1, bufio.MaxScanTokenSize))
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if oldLeft > 0 || newLeft > 0 {
+			switch {
+			case strings.HasPrefix(line, "-"):
+				removed = append(removed, oldLine)
+				oldLine++
+				oldLeft--
+			case strings.HasPrefix(line, "+"):
+				added++
+				newLeft--
+			case strings.HasPrefix(line, `\`):
+				// No newline at end of file
+			default:
+				flush()
+				oldLine++
+				oldLeft--
+				newLeft--
+			}
+			if oldLeft <= 0 && newLeft <= 0 {
+				flush()
+			}
+			continue
+		}
+
+		if path, found := strings.CutPrefix(line, "+++ "); found {
+			path, _, _ = strings.Cut(path, "\t")
+			if filepath.IsAbs(path) {
+				if rel, err := filepath.Rel(root, path); err == nil {
+					path = rel
+				}
+			}
+			path = filepath.ToSlash(path)
+			current = byPath[path]
+			if current == nil {
+				current = &FileDrift{Path: path, Lines: make(map[int]int)}
+				byPath[path] = current
+			}
+		} else if match := diffHunkHeader.FindStringSubmatch(line); match != nil {
+			oldLine, _ = strconv.Atoi(match[1])
+			oldLeft, newLeft = 1, 1
+			if match[2] != "" {
+				oldLeft, _ = strconv.Atoi(match[2])
+			}
+			if match[3] != "" {
+				newLeft, _ = strconv.Atoi(match[3])
+			}
+			// A hunk without old lines starts after the line it names
+			if oldLeft == 0 {
+				oldLine++
+			}
+		}
+	}
+
+	drifts := make([]FileDrift, 0, len(byPath))
+	for _, drift := range byPath {
+		if len(drift.Lines) > 0 {
+			drifts = append(drifts, *drift)
+		}
+	}
+	sort.Slice(drifts, func(i, j int) bool { return drifts[i].Path < drifts[j].Path })
+	return drifts
+}
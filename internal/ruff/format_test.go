@@ -0,0 +1,71 @@
+package ruff
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/xeon-zolt/codecompass/internal/cerrors"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestParseDiff(t *testing.T) {
+	drifts := ParseDiff(readFixture(t, "format.diff"), "/repo")
+
+	expected := []FileDrift{
+		{Path: "lib/util.py", Lines: map[int]int{
+			// Inserted before the first line
+			1: 2,
+			4: 1,
+		}},
+		{Path: "src/app.py", Lines: map[int]int{
+			// The blank lines after the imports count with them
+			1: 3,
+			// A call split over three lines
+			3: 3,
+			7: 1,
+			// A removed line that looks like a file header
+			21: 1,
+		}},
+	}
+	if !reflect.DeepEqual(drifts, expected) {
+		t.Errorf("Expected %+v, but got %+v", expected, drifts)
+	}
+	if changed := drifts[1].Changed(); changed != 8 {
+		t.Errorf("Expected 8 changed lines in src/app.py, but got %d", changed)
+	}
+}
+
+func TestParseDiffImports(t *testing.T) {
+	drifts := ParseDiff(readFixture(t, "imports.diff"), "/repo")
+
+	expected := []FileDrift{{Path: "src/app.py", Lines: map[int]int{1: 2}}}
+	if !reflect.DeepEqual(drifts, expected) {
+		t.Errorf("Expected %+v, but got %+v", expected, drifts)
+	}
+	if drifts := ParseDiff(nil, "/repo"); len(drifts) != 0 {
+		t.Errorf("Expected no drift without a diff, but got %+v", drifts)
+	}
+}
+
+func TestRunFormatDiffWithoutRuff(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	_, err := RunFormatDiff(context.Background(), t.TempDir(), []string{"main.py"})
+	var toolErr *cerrors.ErrToolNotFound
+	if !errors.As(err, &toolErr) || toolErr.Tool != "ruff" {
+		t.Errorf("Expected ErrToolNotFound for ruff, but got %v", err)
+	}
+}
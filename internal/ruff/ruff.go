@@ -1,13 +1,20 @@
 package ruff
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 
-	"codecompass/internal/types"
+	"github.com/xeon-zolt/codecompass/internal/cerrors"
+	"github.com/xeon-zolt/codecompass/internal/config"
+	"github.com/xeon-zolt/codecompass/internal/repopath"
+	"github.com/xeon-zolt/codecompass/internal/toolversion"
+	"github.com/xeon-zolt/codecompass/internal/types"
+	"github.com/xeon-zolt/codecompass/internal/utils"
 )
 
 // RuffIssue represents a single issue reported by Ruff.
@@ -19,11 +26,56 @@ type RuffIssue struct {
 		Column int `json:"column"`
 	} `json:"location"`
 	Filename string `json:"filename"`
-	Fix      *struct{} `json:"fix"` // We don't care about the fix for now
+	Fix      *struct {
+		// Applicability is safe for fixes ruff check --fix applies, and
+		// unsafe or display-only for the ones it leaves alone
+		Applicability string `json:"applicability"`
+	} `json:"fix"`
 }
 
-// RunRuff executes the ruff linter and parses its JSON output.
-func RunRuff(files []string, ruffRules []string, ruffIgnorePaths []string) ([]types.Issue, error) {
+// Fixable reports whether ruff check --fix fixes the issue. Ruff versions
+// without applicability only reported fixes they applied.
+func (i RuffIssue) Fixable() bool {
+	return i.Fix != nil && (i.Fix.Applicability == "" || i.Fix.Applicability == "safe")
+}
+
+// Source is the Ruff lint source for Python files.
+type Source struct{}
+
+func (Source) Name() string {
+	return "ruff"
+}
+
+// Detect always succeeds; Run returns cerrors.ErrNotApplicable for
+// repositories without Python files.
+func (Source) Detect(ctx context.Context, dir string) bool {
+	return true
+}
+
+// Versions returns the version of Ruff.
+func (Source) Versions(ctx context.Context, dir string) map[string]string {
+	return map[string]string{"ruff": toolversion.Command(ctx, dir, "ruff", "--version")}
+}
+
+func (Source) Run(ctx context.Context, dir string, files map[string]bool, cfg *config.Config) ([]types.Issue, error) {
+	var pythonFiles []string
+	for file := range files {
+		if strings.HasSuffix(file, ".py") {
+			pythonFiles = append(pythonFiles, file)
+		}
+	}
+	if len(pythonFiles) == 0 {
+		return nil, fmt.Errorf("no Python files to lint: %w", cerrors.ErrNotApplicable)
+	}
+	sort.Strings(pythonFiles)
+
+	return RunRuff(ctx, dir, pythonFiles, cfg.RuffRules, cfg.RuffIgnorePaths)
+}
+
+// RunRuff executes the ruff linter in dir and parses its JSON output. File
+// paths on the returned issues are relative to dir. Ruff is killed when ctx
+// is done.
+func RunRuff(ctx context.Context, dir string, files []string, ruffRules []string, ruffIgnorePaths []string) ([]types.Issue, error) {
 	args := []string{"check", "--output-format=json"}
 
 	// Add rules if specified
@@ -39,8 +91,15 @@ func RunRuff(files []string, ruffRules []string, ruffIgnorePaths []string) ([]ty
 	// Add files to check
 	args = append(args, files...)
 
-	cmd := exec.Command("ruff", args...)
+	if err := utils.RequireTool("ruff"); err != nil {
+		return nil, err
+	}
+	cmd := exec.CommandContext(ctx, "ruff", args...)
+	cmd.Dir = dir
 	output, err := cmd.Output()
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
 
 	if err != nil {
 		// Ruff returns non-zero exit code if issues are found, which is not an error for us.
@@ -56,15 +115,22 @@ func RunRuff(files []string, ruffRules []string, ruffIgnorePaths []string) ([]ty
 		return nil, fmt.Errorf("failed to parse ruff output: %w", err)
 	}
 
+	checked := make(map[string]bool, len(files))
+	for _, file := range files {
+		checked[filepath.ToSlash(file)] = true
+	}
+	resolver := repopath.New(dir, checked)
+
 	var issues []types.Issue
 	for _, ruffIssue := range ruffIssues {
 		// Convert RuffIssue to CodeCompass's generic Issue format
 		issues = append(issues, types.Issue{
-			FilePath: filepath.ToSlash(ruffIssue.Filename),
+			FilePath: resolver.Resolve(ruffIssue.Filename),
 			Line:     ruffIssue.Location.Row,
 			RuleID:   ruffIssue.Code,
 			Message:  ruffIssue.Message,
 			Severity: 1, // Ruff issues are typically errors/warnings, map to a default severity
+			Fixable:  ruffIssue.Fixable(),
 		})
 	}
 
@@ -0,0 +1,42 @@
+package ruff
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/xeon-zolt/codecompass/internal/cerrors"
+)
+
+func TestRunRuffWithoutRuff(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	_, err := RunRuff(context.Background(), t.TempDir(), []string{"main.py"}, nil, nil)
+	var toolErr *cerrors.ErrToolNotFound
+	if !errors.As(err, &toolErr) || toolErr.Tool != "ruff" {
+		t.Errorf("Expected ErrToolNotFound for ruff, but got %v", err)
+	}
+}
+
+func TestRuffIssueFixable(t *testing.T) {
+	output := []byte(`[
+		{"code": "F401", "message": "unused import", "filename": "/repo/a.py", "location": {"row": 1, "column": 1}, "fix": {"applicability": "safe", "edits": []}},
+		{"code": "B006", "message": "mutable default", "filename": "/repo/a.py", "location": {"row": 3, "column": 1}, "fix": {"applicability": "unsafe", "edits": []}},
+		{"code": "E501", "message": "line too long", "filename": "/repo/a.py", "location": {"row": 5, "column": 89}, "fix": null},
+		{"code": "W291", "message": "trailing whitespace", "filename": "/repo/a.py", "location": {"row": 7, "column": 4}, "fix": {"edits": []}}
+	]`)
+
+	var issues []RuffIssue
+	if err := json.Unmarshal(output, &issues); err != nil {
+		t.Fatal(err)
+	}
+
+	// Unsafe fixes are left alone by ruff check --fix
+	expected := map[string]bool{"F401": true, "B006": false, "E501": false, "W291": true}
+	for _, issue := range issues {
+		if issue.Fixable() != expected[issue.Code] {
+			t.Errorf("Expected %s fixable to be %v, but got %v", issue.Code, expected[issue.Code], issue.Fixable())
+		}
+	}
+}
@@ -0,0 +1,251 @@
+// Package score combines the per-file measurements of a run into a weighted
+// quality score for every file and for the repository, on a 0-100 scale
+// where higher is better.
+package score
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/xeon-zolt/codecompass/internal/config"
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+// Component scores fall linearly from 100 to 0 as these limits are reached.
+// Coverage is scored as the percentage of lines covered, and complexity as
+// the percentage of lines outside functions longer than long-function-lines.
+const (
+	maxIssuesPer100Lines = 10.0 // lint issues per 100 lines of code
+	maxDebtPer100Lines   = 2.0  // TODO/FIXME/HACK markers per 100 lines of code
+	maxChanges           = 50.0 // commits that changed the file
+)
+
+// File holds the measurements of one file.
+type File struct {
+	Path  string
+	Lines int
+
+	Issues  int
+	Debt    int
+	Changes int
+
+	// WeightedIssues are Issues weighted by their severity and rule, scored
+	// in place of them when Input.Weighted is set
+	WeightedIssues float64
+
+	// CoverableLines is zero for a file missing from the coverage report
+	CoveredLines   int
+	CoverableLines int
+
+	// Functions is set for files in a language whose function lengths are
+	// measured
+	Functions         bool
+	LongFunctionLines int
+}
+
+// Input is what a score is computed from.
+type Input struct {
+	Files []File
+
+	// Measured lists the components measured in the run. The others are
+	// left out of every score, and the weights of the rest re-normalized.
+	Measured map[string]bool
+
+	// Weighted scores issues by their weight rather than their number.
+	Weighted bool
+}
+
+// Compute scores every file with lines of code in input and the repository
+// as a whole. A component with a weight of zero, or not measured for a file,
+// is left out and the remaining weights re-normalized, so a missing coverage
+// report neither lowers nor raises any score. Files are sorted worst first.
+//
+// The repository score combines the repository-wide measurements, such as
+// all issues per 100 lines, rather than averaging the file scores, so each
+// component can be explained by a single number.
+func Compute(input Input, weights map[string]float64) types.ScoreStats {
+	stats := types.ScoreStats{Files: []types.FileScore{}}
+
+	var totals File
+	files, covered, functionLines := 0, 0, 0
+	for _, file := range input.Files {
+		if file.Lines <= 0 {
+			continue
+		}
+		files++
+		totals.Lines += file.Lines
+		totals.Issues += file.Issues
+		totals.WeightedIssues += file.WeightedIssues
+		totals.Debt += file.Debt
+		totals.Changes += file.Changes
+		totals.CoveredLines += file.CoveredLines
+		totals.CoverableLines += file.CoverableLines
+		if file.Functions {
+			totals.Functions = true
+			totals.LongFunctionLines += file.LongFunctionLines
+			functionLines += file.Lines
+		}
+		if file.CoverableLines > 0 {
+			covered++
+		}
+
+		components := componentScores(file, input.Measured, input.Weighted, 1)
+		score, shares := combine(components, weights)
+		if shares == nil {
+			continue
+		}
+		stats.Files = append(stats.Files, types.FileScore{Path: file.Path, Score: score, Components: components, Details: fileDetails(file, components, input.Weighted)})
+	}
+
+	sort.SliceStable(stats.Files, func(i, j int) bool {
+		if stats.Files[i].Score != stats.Files[j].Score {
+			return stats.Files[i].Score < stats.Files[j].Score
+		}
+		return stats.Files[i].Path < stats.Files[j].Path
+	})
+	for i := range stats.Files {
+		stats.Files[i].Rank = i + 1
+	}
+
+	if len(stats.Files) == 0 {
+		return stats
+	}
+
+	// Churn is averaged over the files, and complexity only measured in
+	// the files with functions
+	components := componentScores(totals, input.Measured, input.Weighted, files)
+	if _, ok := components["complexity"]; ok {
+		components["complexity"] = 100 - percent(totals.LongFunctionLines, functionLines)
+	}
+	score, shares := combine(components, weights)
+	if shares == nil {
+		return stats
+	}
+	stats.Score = score
+
+	details := map[string]string{
+		"issues":     fmt.Sprintf("%.2f %s per 100 lines", issuesPer100(totals, input.Weighted), issuesName(input.Weighted)),
+		"coverage":   fmt.Sprintf("%.1f%% of lines covered in %d file(s)", percent(totals.CoveredLines, totals.CoverableLines), covered),
+		"debt":       fmt.Sprintf("%.2f debt markers per 100 lines", per100(totals.Debt, totals.Lines)),
+		"churn":      fmt.Sprintf("%.1f changes per file", float64(totals.Changes)/float64(files)),
+		"complexity": fmt.Sprintf("%.1f%% of function code in long functions", percent(totals.LongFunctionLines, functionLines)),
+	}
+	for _, component := range config.ScoreComponents {
+		if share, ok := shares[component]; ok {
+			stats.Components = append(stats.Components, types.ScoreComponent{
+				Component: component,
+				Score:     components[component],
+				Weight:    share,
+				Detail:    details[component],
+			})
+		}
+	}
+
+	return stats
+}
+
+// fileDetails describes what each of the components of file was scored
+// from.
+func fileDetails(file File, components map[string]float64, weighted bool) map[string]string {
+	issues := fmt.Sprintf("%d issues", file.Issues)
+	if weighted {
+		issues = fmt.Sprintf("%d issues weighing %.1f", file.Issues, file.WeightedIssues)
+	}
+	details := map[string]string{
+		"issues":     fmt.Sprintf("%s, %.2f per 100 lines", issues, issuesPer100(file, weighted)),
+		"coverage":   fmt.Sprintf("%.1f%% of lines covered", percent(file.CoveredLines, file.CoverableLines)),
+		"debt":       fmt.Sprintf("%d debt markers, %.2f per 100 lines", file.Debt, per100(file.Debt, file.Lines)),
+		"churn":      fmt.Sprintf("%d changes", file.Changes),
+		"complexity": fmt.Sprintf("%.1f%% of lines in long functions", percent(file.LongFunctionLines, file.Lines)),
+	}
+	for component := range details {
+		if _, ok := components[component]; !ok {
+			delete(details, component)
+		}
+	}
+	return details
+}
+
+// componentScores scores the components measured for file, issues by
+// their weight when weighted is set. files is the number of files file sums
+// up, which churn is averaged over.
+func componentScores(file File, measured map[string]bool, weighted bool, files int) map[string]float64 {
+	scores := make(map[string]float64)
+	if measured["issues"] {
+		scores["issues"] = linear(issuesPer100(file, weighted), maxIssuesPer100Lines)
+	}
+	if measured["coverage"] && file.CoverableLines > 0 {
+		scores["coverage"] = percent(file.CoveredLines, file.CoverableLines)
+	}
+	if measured["debt"] {
+		scores["debt"] = linear(per100(file.Debt, file.Lines), maxDebtPer100Lines)
+	}
+	if measured["churn"] {
+		scores["churn"] = linear(float64(file.Changes)/float64(files), maxChanges)
+	}
+	if measured["complexity"] && file.Functions {
+		scores["complexity"] = 100 - percent(file.LongFunctionLines, file.Lines)
+	}
+	return scores
+}
+
+// combine returns the weighted mean of components and the share of each
+// weighted component in percent. The shares are nil when no component has
+// a weight.
+func combine(components map[string]float64, weights map[string]float64) (float64, map[string]float64) {
+	var weighted, totalWeight float64
+	for component, score := range components {
+		weighted += score * weights[component]
+		totalWeight += weights[component]
+	}
+	if totalWeight <= 0 {
+		return 0, nil
+	}
+
+	shares := make(map[string]float64)
+	for component := range components {
+		if weights[component] > 0 {
+			shares[component] = weights[component] / totalWeight * 100
+		}
+	}
+	return weighted / totalWeight, shares
+}
+
+// linear scores value from 100 at zero down to 0 at limit.
+func linear(value, limit float64) float64 {
+	return math.Max(0, math.Min(100, 100*(1-value/limit)))
+}
+
+// issuesPer100 returns the issues of file per 100 lines, or their weight
+// when weighted is set.
+func issuesPer100(file File, weighted bool) float64 {
+	if !weighted {
+		return per100(file.Issues, file.Lines)
+	}
+	if file.Lines == 0 {
+		return 0
+	}
+	return file.WeightedIssues / float64(file.Lines) * 100
+}
+
+func issuesName(weighted bool) string {
+	if weighted {
+		return "weighted issues"
+	}
+	return "issues"
+}
+
+func per100(count, lines int) float64 {
+	if lines == 0 {
+		return 0
+	}
+	return float64(count) / float64(lines) * 100
+}
+
+func percent(part, whole int) float64 {
+	if whole == 0 {
+		return 0
+	}
+	return math.Min(100, float64(part)/float64(whole)*100)
+}
@@ -0,0 +1,158 @@
+package score
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/xeon-zolt/codecompass/internal/config"
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+var all = map[string]bool{"issues": true, "coverage": true, "debt": true, "churn": true, "complexity": true}
+
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestCompute(t *testing.T) {
+	input := Input{
+		Files: []File{
+			// issues 80, coverage 50, debt 50, churn 80, complexity 60
+			{Path: "bad.go", Lines: 100, Issues: 2, Debt: 1, Changes: 10, CoveredLines: 40, CoverableLines: 80, Functions: true, LongFunctionLines: 40},
+			// Every component at 100; not in the coverage report
+			{Path: "good.go", Lines: 100, Functions: true},
+			// No functions are measured in Python, and no lines means no score
+			{Path: "script.py", Lines: 50, Issues: 2, Changes: 5, CoveredLines: 50, CoverableLines: 50},
+			{Path: "empty.go"},
+		},
+		Measured: all,
+	}
+
+	stats := Compute(input, config.NewConfig().ScoreWeights)
+
+	if len(stats.Files) != 3 {
+		t.Fatalf("Expected 3 scored files, but got %+v", stats.Files)
+	}
+
+	bad := stats.Files[0]
+	if bad.Path != "bad.go" || bad.Rank != 1 {
+		t.Errorf("Expected bad.go to rank worst, but got %+v", stats.Files)
+	}
+	expectedComponents := map[string]float64{"issues": 80, "coverage": 50, "debt": 50, "churn": 80, "complexity": 60}
+	if !reflect.DeepEqual(bad.Components, expectedComponents) {
+		t.Errorf("Expected components %v, but got %v", expectedComponents, bad.Components)
+	}
+	// (80*30 + 50*25 + 50*15 + 80*15 + 60*15) / 100
+	if !approx(bad.Score, 65) {
+		t.Errorf("Expected bad.go to score 65, but got %v", bad.Score)
+	}
+
+	// script.py has no complexity: (60*30 + 100*25 + 100*15 + 90*15) / 85
+	script := stats.Files[1]
+	if script.Path != "script.py" || !approx(script.Score, 7150.0/85) {
+		t.Errorf("Expected script.py to score %v, but got %+v", 7150.0/85, script)
+	}
+	if _, ok := script.Components["complexity"]; ok {
+		t.Errorf("Expected no complexity for a Python file, but got %v", script.Components)
+	}
+	expectedDetails := map[string]string{
+		"issues":   "2 issues, 4.00 per 100 lines",
+		"coverage": "100.0% of lines covered",
+		"debt":     "0 debt markers, 0.00 per 100 lines",
+		"churn":    "5 changes",
+	}
+	if !reflect.DeepEqual(script.Details, expectedDetails) {
+		t.Errorf("Expected details %v, but got %v", expectedDetails, script.Details)
+	}
+
+	if good := stats.Files[2]; good.Path != "good.go" || !approx(good.Score, 100) {
+		t.Errorf("Expected good.go to score 100, but got %+v", good)
+	}
+
+	// 4 issues and 1 marker in 250 lines, 90 of 130 lines covered, 15
+	// changes over 3 files and 40 of 200 function lines in long functions
+	expected := []types.ScoreComponent{
+		{Component: "issues", Score: 84, Weight: 30},
+		{Component: "coverage", Score: 90.0 / 130 * 100, Weight: 25},
+		{Component: "debt", Score: 80, Weight: 15},
+		{Component: "churn", Score: 90, Weight: 15},
+		{Component: "complexity", Score: 80, Weight: 15},
+	}
+	if len(stats.Components) != len(expected) {
+		t.Fatalf("Expected components %+v, but got %+v", expected, stats.Components)
+	}
+	score := 0.0
+	for i, component := range stats.Components {
+		if component.Component != expected[i].Component || !approx(component.Score, expected[i].Score) || !approx(component.Weight, expected[i].Weight) {
+			t.Errorf("Expected component %+v, but got %+v", expected[i], component)
+		}
+		if component.Detail == "" {
+			t.Errorf("Expected a detail for %s", component.Component)
+		}
+		score += component.Score * component.Weight / 100
+	}
+	if !approx(stats.Score, score) {
+		t.Errorf("Expected the repository score %v to combine its components, but got %v", score, stats.Score)
+	}
+}
+
+func TestComputeRenormalizesMissingComponents(t *testing.T) {
+	files := []File{{Path: "a.go", Lines: 100, Issues: 5, Debt: 1, CoveredLines: 10, CoverableLines: 100}}
+	weights := map[string]float64{"issues": 30, "coverage": 25, "debt": 15, "churn": 15, "complexity": 0}
+
+	// Coverage, churn and complexity were not measured
+	stats := Compute(Input{Files: files, Measured: map[string]bool{"issues": true, "debt": true}}, weights)
+
+	// (50*30 + 50*15) / 45
+	if !approx(stats.Score, 50) || !approx(stats.Files[0].Score, 50) {
+		t.Errorf("Expected a score of 50 from issues and debt alone, but got %v and %+v", stats.Score, stats.Files)
+	}
+	if len(stats.Components) != 2 || !approx(stats.Components[0].Weight, 30.0/45*100) || !approx(stats.Components[1].Weight, 15.0/45*100) {
+		t.Errorf("Expected the issues and debt weights to be re-normalized, but got %+v", stats.Components)
+	}
+
+	// Files with only unweighted components are not scored
+	stats = Compute(Input{Files: []File{{Path: "a.go", Lines: 10, Functions: true, LongFunctionLines: 10}}, Measured: map[string]bool{"complexity": true}}, weights)
+	if stats.Score != 0 || len(stats.Components) != 0 || len(stats.Files) != 0 {
+		t.Errorf("Expected no score without weighted components, but got %+v", stats)
+	}
+}
+
+func TestComputeWeightedIssues(t *testing.T) {
+	// 2 errors weighing 5 each outweigh 8 warnings
+	files := []File{
+		{Path: "errors.go", Lines: 100, Issues: 2, WeightedIssues: 10},
+		{Path: "warnings.go", Lines: 100, Issues: 8, WeightedIssues: 8},
+	}
+	weights := map[string]float64{"issues": 1}
+
+	stats := Compute(Input{Files: files, Measured: map[string]bool{"issues": true}}, weights)
+	if stats.Files[0].Path != "warnings.go" || !approx(stats.Score, 50) {
+		t.Errorf("Expected counted issues to rank warnings.go worst and score 50, but got %v and %+v", stats.Score, stats.Files)
+	}
+
+	stats = Compute(Input{Files: files, Measured: map[string]bool{"issues": true}, Weighted: true}, weights)
+	if stats.Files[0].Path != "errors.go" || !approx(stats.Files[0].Score, 0) || !approx(stats.Score, 10) {
+		t.Errorf("Expected weighted issues to rank errors.go worst and score 10, but got %v and %+v", stats.Score, stats.Files)
+	}
+	if detail := stats.Components[0].Detail; detail != "9.00 weighted issues per 100 lines" {
+		t.Errorf("Expected the detail to name weighted issues, but got %q", detail)
+	}
+	if detail := stats.Files[0].Details["issues"]; detail != "2 issues weighing 10.0, 10.00 per 100 lines" {
+		t.Errorf("Expected the file detail to name the weight, but got %q", detail)
+	}
+}
+
+func TestComputeClampsScores(t *testing.T) {
+	files := []File{{Path: "a.go", Lines: 10, Issues: 50, Debt: 10, Changes: 500}}
+	stats := Compute(Input{Files: files, Measured: all}, config.NewConfig().ScoreWeights)
+	if stats.Score != 0 {
+		t.Errorf("Expected measurements past the limits to score 0, but got %v", stats.Score)
+	}
+
+	stats = Compute(Input{Measured: all}, config.NewConfig().ScoreWeights)
+	if stats.Score != 0 || stats.Components != nil || len(stats.Files) != 0 {
+		t.Errorf("Expected an empty score without files, but got %+v", stats)
+	}
+}
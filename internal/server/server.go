@@ -0,0 +1,479 @@
+// Package server serves the report of a repository over HTTP for dashboards:
+// as JSON at /report, as a one-page quality dashboard at /, with its
+// leaderboards at /leaderboards and the trends of its totals at /trends. The
+// report is analyzed on the first request and cached, then analyzed again
+// once it is older than a TTL.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"html/template"
+	"math"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/xeon-zolt/codecompass/internal/leaderboard"
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+// AnalyzeFunc analyzes the repository being served.
+type AnalyzeFunc func(ctx context.Context) (*types.Report, error)
+
+// TrendFunc returns the change of the totals of a report since an earlier
+// run, or nil when there is none to compare with.
+type TrendFunc func(report *types.Report) *types.RunTrend
+
+// HistoryFunc returns the history of at most runs of the runs logged before
+// a report, or nil when there is none.
+type HistoryFunc func(report *types.Report, runs int) *types.RunHistory
+
+// Server caches the report of one repository and serves it.
+type Server struct {
+	analyze AnalyzeFunc
+	trend   TrendFunc
+	history HistoryFunc
+	ttl     time.Duration
+	now     func() time.Time
+
+	// ctx is what analyses run under, so a client hanging up does not
+	// cancel one other requests wait for
+	ctx context.Context
+
+	// mu is held while analyzing, so concurrent requests share one
+	// analysis rather than each running their own
+	mu         sync.Mutex
+	report     *types.Report
+	runTrend   *types.RunTrend
+	runHistory *types.RunHistory
+	analyzedAt time.Time
+}
+
+// New returns a Server analyzing with analyze. A ttl of zero keeps the first
+// report for as long as the server runs.
+func New(analyze AnalyzeFunc, ttl time.Duration) *Server {
+	return &Server{analyze: analyze, ttl: ttl, now: time.Now, ctx: context.Background()}
+}
+
+// SetTrend makes the dashboard show the change of the totals of each report
+// as trend returns it.
+func (s *Server) SetTrend(trend TrendFunc) {
+	s.trend = trend
+}
+
+// SetHistory makes the leaderboards show the trend of each rule and file
+// over the runs history returns, and the trends page chart the totals.
+func (s *Server) SetHistory(history HistoryFunc) {
+	s.history = history
+}
+
+// Report returns the cached report, analyzing the repository first when
+// there is none yet or it has expired. A failed analysis is not cached, so
+// the next request tries again.
+func (s *Server) Report() (*types.Report, error) {
+	report, _, _, err := s.current()
+	return report, err
+}
+
+// current returns the cached report with its trend and history, as Report
+// does.
+func (s *Server) current() (*types.Report, *types.RunTrend, *types.RunHistory, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.report != nil && (s.ttl == 0 || s.now().Sub(s.analyzedAt) < s.ttl) {
+		return s.report, s.runTrend, s.runHistory, nil
+	}
+	report, err := s.analyze(s.ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	s.report, s.runTrend, s.runHistory, s.analyzedAt = report, nil, nil, s.now()
+	if s.trend != nil {
+		s.runTrend = s.trend(report)
+	}
+	if s.history != nil {
+		s.runHistory = s.history(report, historyRuns)
+	}
+	return report, s.runTrend, s.runHistory, nil
+}
+
+// Handler returns the handler of /, /leaderboards, /trends and /report.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.serveDashboard)
+	mux.HandleFunc("GET /leaderboards", s.serveLeaderboards)
+	mux.HandleFunc("GET /trends", s.serveTrends)
+	mux.HandleFunc("GET /report", s.serveJSON)
+	return mux
+}
+
+// Serve serves on listener until ctx is done, then shuts down, letting
+// requests in flight finish. Analyses run under ctx.
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	s.ctx = ctx
+	srv := &http.Server{Handler: s.Handler(), ReadHeaderTimeout: 10 * time.Second}
+
+	done := make(chan error, 1)
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		done <- srv.Shutdown(shutdownCtx)
+	}()
+
+	if err := srv.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return <-done
+}
+
+func (s *Server) serveJSON(w http.ResponseWriter, r *http.Request) {
+	report, err := s.Report()
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	w.Header().Set("Last-Modified", report.GeneratedAt.UTC().Format(http.TimeFormat))
+	writeJSON(w, http.StatusOK, report)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(v)
+}
+
+// htmlRows is how many entries each leaderboard of the leaderboards page
+// lists, dashboardRows how many the dashboard lists, and historyRuns how
+// many logged runs the trends are drawn over, besides the report's own.
+const (
+	htmlRows      = 15
+	dashboardRows = 3
+	historyRuns   = 20
+)
+
+func (s *Server) serveDashboard(w http.ResponseWriter, r *http.Request) {
+	report, trend, _, err := s.current()
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		pages.ExecuteTemplate(w, "dashboard", pageData{Error: err.Error()})
+		return
+	}
+	pages.ExecuteTemplate(w, "dashboard", pageData{
+		Report:   report,
+		Trend:    trend,
+		Totals:   dashboardTotals(report, trend),
+		Authors:  report.Authors[:min(dashboardRows, len(report.Authors))],
+		Files:    report.Files[:min(dashboardRows, len(report.Files))],
+		Rules:    report.Rules[:min(dashboardRows, len(report.Rules))],
+		Debt:     report.TechnicalDebt[:min(dashboardRows, len(report.TechnicalDebt))],
+		Coverage: report.Coverage[:min(dashboardRows, len(report.Coverage))],
+	})
+}
+
+func (s *Server) serveLeaderboards(w http.ResponseWriter, r *http.Request) {
+	report, _, history, err := s.current()
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		pages.ExecuteTemplate(w, "leaderboards", pageData{Error: err.Error()})
+		return
+	}
+	data := pageData{
+		Report:  report,
+		History: history,
+		Authors: report.Authors[:min(htmlRows, len(report.Authors))],
+		Files:   report.Files[:min(htmlRows, len(report.Files))],
+		Rules:   report.Rules[:min(htmlRows, len(report.Rules))],
+	}
+	if history != nil {
+		data.RuleTrends = make(map[string]template.HTML, len(data.Rules))
+		for _, entry := range data.Rules {
+			data.RuleTrends[entry.Rule] = sparkline(withCurrent(history, history.Rules[entry.Rule], float64(entry.Count)))
+		}
+		data.FileTrends = make(map[string]template.HTML, len(data.Files))
+		for _, entry := range data.Files {
+			data.FileTrends[entry.Path] = sparkline(withCurrent(history, history.Files[entry.Path], float64(entry.Count)))
+		}
+	}
+	pages.ExecuteTemplate(w, "leaderboards", data)
+}
+
+func (s *Server) serveTrends(w http.ResponseWriter, r *http.Request) {
+	report, _, history, err := s.current()
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		pages.ExecuteTemplate(w, "trends", pageData{Error: err.Error()})
+		return
+	}
+	data := pageData{Report: report, History: history}
+	if history != nil {
+		times := append(append([]time.Time(nil), history.Times...), report.GeneratedAt)
+		for _, total := range leaderboard.DashboardTotals(report) {
+			values := withCurrent(history, history.Totals[total.Metric], total.Value)
+			data.Charts = append(data.Charts, trendChart{
+				Label: leaderboard.MetricLabel(total.Metric),
+				Value: leaderboard.FormatMetric(total.Metric, total.Value),
+				Chart: chart(total.Metric, times, values),
+			})
+		}
+	}
+	pages.ExecuteTemplate(w, "trends", data)
+}
+
+// withCurrent returns the values a series had in the runs of history, NaN
+// for all of them when it had none, followed by its value in the report.
+func withCurrent(history *types.RunHistory, past []float64, current float64) []float64 {
+	values := make([]float64, 0, len(history.Times)+1)
+	if past == nil {
+		for range history.Times {
+			values = append(values, math.NaN())
+		}
+	}
+	return append(append(values, past...), current)
+}
+
+type pageData struct {
+	Error    string
+	Report   *types.Report
+	Trend    *types.RunTrend
+	History  *types.RunHistory
+	Totals   []totalRow
+	Authors  []types.LeaderboardEntry
+	Files    []types.FileLeaderboardEntry
+	Rules    []types.RuleLeaderboardEntry
+	Debt     []types.TechnicalDebtEntry
+	Coverage []types.CoverageEntry
+
+	// RuleTrends and FileTrends are the sparklines of the rules and files
+	// listed, and Charts those of the totals
+	RuleTrends map[string]template.HTML
+	FileTrends map[string]template.HTML
+	Charts     []trendChart
+}
+
+// trendChart is the chart of a total of the trends page.
+type trendChart struct {
+	Label, Value string
+	Chart        template.HTML
+}
+
+// totalRow is a total of the dashboard with its change, which Class marks
+// better or worse.
+type totalRow struct {
+	Label, Value, Change, Class string
+}
+
+// dashboardTotals returns the totals of report but coverage, which has a
+// gauge, with their change since trend.
+func dashboardTotals(report *types.Report, trend *types.RunTrend) []totalRow {
+	deltas := make(map[string]types.MetricDelta)
+	if trend != nil {
+		for _, delta := range trend.Deltas {
+			if !delta.Missing {
+				deltas[delta.Metric] = delta
+			}
+		}
+	}
+
+	var rows []totalRow
+	for _, total := range leaderboard.DashboardTotals(report) {
+		if total.Metric == "coverage" {
+			continue
+		}
+		row := totalRow{Label: leaderboard.MetricLabel(total.Metric), Value: leaderboard.FormatMetric(total.Metric, total.Value)}
+		if delta, ok := deltas[total.Metric]; ok {
+			var rating int
+			row.Change, rating = leaderboard.FormatChange(delta)
+			row.Class = map[int]string{1: "better", -1: "worse"}[rating]
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// coverageChange formats the change of the overall coverage since trend,
+// or returns "" when either run did not measure it.
+func coverageChange(trend *types.RunTrend) string {
+	if trend == nil {
+		return ""
+	}
+	for _, delta := range trend.Deltas {
+		if delta.Metric == "coverage" && !delta.Missing {
+			change, _ := leaderboard.FormatChange(delta)
+			return change
+		}
+	}
+	return ""
+}
+
+// pages are the HTML views of a report: the dashboard, the leaderboards and
+// the trends.
+var pages = template.Must(template.New("pages").Funcs(template.FuncMap{
+	"inc":            func(i int) int { return i + 1 },
+	"coverageChange": coverageChange,
+}).Parse(`{{define "head"}}<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>CodeCompass{{with .Report}} - {{.Repo.Path}}{{end}}</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2rem; color: #222; }
+table { border-collapse: collapse; margin-bottom: 2rem; }
+th, td { padding: 0.25rem 0.75rem; border-bottom: 1px solid #ddd; text-align: left; }
+td.n { text-align: right; font-variant-numeric: tabular-nums; }
+.error, .worse { color: #b00020; }
+.better { color: #1b7f3b; }
+.muted { color: #777; }
+.columns { display: grid; grid-template-columns: repeat(auto-fit, minmax(24rem, 1fr)); column-gap: 2rem; }
+meter { width: 12rem; }
+svg text { font-size: 12px; fill: #777; }
+.sparkline, .chart { color: #3867d6; vertical-align: middle; }
+</style>
+</head>
+<body>
+{{end}}
+
+{{define "dashboard"}}{{template "head" .}}
+<h1>CodeCompass{{with .Report}} - {{.Repo.Path}}{{end}}</h1>
+{{if .Error}}
+<p class="error">The analysis failed: {{.Error}}</p>
+{{else}}{{$report := .Report}}
+<p>Generated {{.Report.GeneratedAt.Format "2006-01-02 15:04:05 MST"}}{{with .Trend}}, compared with the run logged {{.Since.Format "2006-01-02 15:04:05 MST"}}{{end}}. The full leaderboards are at <a href="leaderboards">leaderboards</a>, the trends at <a href="trends">trends</a>, and the report at <a href="report">report</a>.</p>
+<div class="columns">
+<div>
+<h2>Totals</h2>
+<table>
+<tr><th>Total</th><th>Now</th>{{if .Trend}}<th>Change</th>{{end}}</tr>
+{{range .Totals}}<tr><td>{{.Label}}</td><td class="n">{{.Value}}</td>{{if $.Trend}}<td class="n {{.Class}}">{{.Change}}</td>{{end}}</tr>
+{{end}}<tr><td>Run warnings</td><td class="n">{{len .Report.Warnings}}</td>{{if .Trend}}<td></td>{{end}}</tr>
+</table>
+<h2>Coverage</h2>
+{{if .Report.Coverage}}<p><meter min="0" max="100" value="{{printf "%.1f" .Report.OverallCoverage}}"></meter> {{printf "%.1f%%" .Report.OverallCoverage}}{{with coverageChange .Trend}} ({{.}}){{end}}</p>
+{{else}}<p class="muted">Not measured</p>
+{{end}}
+<h2>Authors</h2>
+{{if .Authors}}<table>
+<tr><th>#</th><th>Author</th><th>Issues</th></tr>
+{{range $i, $e := .Authors}}<tr><td class="n">{{inc $i}}</td><td>{{$e.Name}}</td><td class="n">{{$e.Count}}</td></tr>
+{{end}}</table>
+{{else if $report.Requested "authors"}}<p class="muted">None</p>
+{{else}}<p class="muted">Not measured</p>
+{{end}}
+</div>
+<div>
+<h2>Files</h2>
+{{if .Files}}<table>
+<tr><th>#</th><th>File</th><th>Issues</th></tr>
+{{range $i, $e := .Files}}<tr><td class="n">{{inc $i}}</td><td>{{$e.Path}}</td><td class="n">{{$e.Count}}</td></tr>
+{{end}}</table>
+{{else if $report.Requested "files"}}<p class="muted">None</p>
+{{else}}<p class="muted">Not measured</p>
+{{end}}
+<h2>Rules</h2>
+{{if .Rules}}<table>
+<tr><th>#</th><th>Rule</th><th>Violations</th></tr>
+{{range $i, $e := .Rules}}<tr><td class="n">{{inc $i}}</td><td>{{if $e.DocURL}}<a href="{{$e.DocURL}}">{{$e.Rule}}</a>{{else}}{{$e.Rule}}{{end}}</td><td class="n">{{$e.Count}}</td></tr>
+{{end}}</table>
+{{else if $report.Requested "rules"}}<p class="muted">None</p>
+{{else}}<p class="muted">Not measured</p>
+{{end}}
+<h2>Technical Debt</h2>
+{{if .Debt}}<table>
+<tr><th>#</th><th>File</th><th>Debt</th></tr>
+{{range $i, $e := .Debt}}<tr><td class="n">{{inc $i}}</td><td>{{$e.Path}}</td><td class="n">{{$e.TotalDebt}}</td></tr>
+{{end}}</table>
+{{else if $report.Requested "debt"}}<p class="muted">None</p>
+{{else}}<p class="muted">Not measured</p>
+{{end}}
+<h2>Least Covered Files</h2>
+{{if .Coverage}}<table>
+<tr><th>#</th><th>File</th><th>Coverage</th></tr>
+{{range $i, $e := .Coverage}}<tr><td class="n">{{inc $i}}</td><td>{{$e.Path}}</td><td class="n">{{printf "%.1f%%" $e.CoveragePercent}}</td></tr>
+{{end}}</table>
+{{else if $report.Requested "coverage"}}<p class="muted">None</p>
+{{else}}<p class="muted">Not measured</p>
+{{end}}
+</div>
+</div>
+{{end}}
+</body>
+</html>
+{{end}}
+
+{{define "leaderboards"}}{{template "head" .}}
+{{if .Error}}
+<h1>CodeCompass</h1>
+<p class="error">The analysis failed: {{.Error}}</p>
+{{else}}{{with .Report}}
+<h1>CodeCompass - {{.Repo.Path}}</h1>
+<p>Generated {{.GeneratedAt.Format "2006-01-02 15:04:05 MST"}} from {{.Repo.AnalyzedFiles}} of {{.Repo.TrackedFiles}} tracked files. The dashboard is at <a href="./">/</a>, the trends at <a href="trends">trends</a>, and the full report at <a href="report">report</a>.</p>
+{{with .Summary}}
+<h2>Summary</h2>
+<ul>
+<li>Issues: {{.TotalIssues}} ({{.Errors}} errors, {{.Warnings}} warnings)</li>
+<li>Authors with issues: {{.Authors}}</li>
+<li>Files with issues: {{.Files}}</li>
+<li>Rules violated: {{.Rules}}</li>
+</ul>
+{{end}}
+{{with .Repo.Languages}}
+<h2>Languages</h2>
+<table>
+<tr><th>Language</th><th>Files</th><th>Lines</th><th>Share</th></tr>
+{{range .}}<tr><td>{{.Name}}</td><td class="n">{{.Files}}</td><td class="n">{{.Lines}}</td><td class="n">{{printf "%.1f%%" .Percent}}</td></tr>
+{{end}}</table>
+{{end}}
+{{end}}
+{{with .Authors}}
+<h2>Authors</h2>
+<table>
+<tr><th>#</th><th>Author</th><th>Email</th><th>Issues</th><th>Errors</th><th>Warnings</th><th>Files</th><th>Top Rule</th></tr>
+{{range $i, $e := .}}<tr><td class="n">{{inc $i}}</td><td>{{$e.Name}}</td><td>{{$e.Email}}</td><td class="n">{{$e.Count}}</td><td class="n">{{$e.Errors}}</td><td class="n">{{$e.Warnings}}</td><td class="n">{{$e.Files}}</td><td>{{$e.TopRule}} ({{$e.TopCount}})</td></tr>
+{{end}}</table>
+{{end}}
+{{with .Files}}
+<h2>Files</h2>
+<table>
+<tr><th>#</th><th>File</th><th>Issues</th><th>Errors</th><th>Warnings</th><th>Authors</th><th>Top Rule</th>{{if $.History}}<th>Trend</th>{{end}}</tr>
+{{range $i, $e := .}}<tr><td class="n">{{inc $i}}</td><td>{{$e.Path}}</td><td class="n">{{$e.Count}}</td><td class="n">{{$e.Errors}}</td><td class="n">{{$e.Warnings}}</td><td class="n">{{$e.Authors}}</td><td>{{$e.TopRule}} ({{$e.TopCount}})</td>{{if $.History}}<td>{{index $.FileTrends $e.Path}}</td>{{end}}</tr>
+{{end}}</table>
+{{end}}
+{{with .Rules}}
+<h2>Rules</h2>
+<table>
+<tr><th>#</th><th>Rule</th><th>Violations</th><th>Authors</th><th>Files</th>{{if $.History}}<th>Trend</th>{{end}}</tr>
+{{range $i, $e := .}}<tr><td class="n">{{inc $i}}</td><td>{{if $e.DocURL}}<a href="{{$e.DocURL}}">{{$e.Rule}}</a>{{else}}{{$e.Rule}}{{end}}</td><td class="n">{{$e.Count}}</td><td class="n">{{$e.Authors}}</td><td class="n">{{$e.Files}}</td>{{if $.History}}<td>{{index $.RuleTrends $e.Rule}}</td>{{end}}</tr>
+{{end}}</table>
+{{end}}
+{{end}}
+</body>
+</html>
+{{end}}
+
+{{define "trends"}}{{template "head" .}}
+{{if .Error}}
+<h1>CodeCompass</h1>
+<p class="error">The analysis failed: {{.Error}}</p>
+{{else}}
+<h1>CodeCompass - {{.Report.Repo.Path}}</h1>
+{{with .History}}<p>The totals of the {{len .Times}} runs logged before the one generated {{$.Report.GeneratedAt.Format "2006-01-02 15:04:05 MST"}}, and of that run. Runs that did not measure a total leave a gap. The dashboard is at <a href="./">/</a>, and the leaderboards at <a href="leaderboards">leaderboards</a>.</p>
+{{range $.Charts}}
+<h2>{{.Label}}: {{.Value}}</h2>
+{{.Chart}}
+{{else}}<p class="muted">The run measured no totals</p>
+{{end}}
+{{else}}<p class="muted">No runs were logged before this one. Log runs with --log-history to chart their trends.</p>
+{{end}}
+{{end}}
+</body>
+</html>
+{{end}}`))
@@ -0,0 +1,218 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+func TestHandlerServesCachedReport(t *testing.T) {
+	var analyses atomic.Int32
+	s := New(func(ctx context.Context) (*types.Report, error) {
+		analyses.Add(1)
+		report := types.NewReport("/src/app")
+		report.Leaderboards = []string{"authors"}
+		report.Authors = []types.LeaderboardEntry{{Rank: 1, Name: "Alice <admin>", Email: "alice@example.com", Count: 3, TopRule: "eqeqeq", TopCount: 2}}
+		return &report, nil
+	}, time.Hour)
+	now := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	handler := s.Handler()
+
+	// Concurrent requests share one analysis
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/report", nil))
+		}()
+	}
+	wg.Wait()
+	if n := analyses.Load(); n != 1 {
+		t.Errorf("Expected one analysis for concurrent requests, got %d", n)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/report", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("GET /report = %d %s; expected 200 application/json", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"schema_version", "generated_at", "repo", "leaderboards", "authors"} {
+		if _, ok := body[key]; !ok {
+			t.Errorf("Report JSON has no %q: %s", key, rec.Body.String())
+		}
+	}
+	var authors []types.LeaderboardEntry
+	if err := json.Unmarshal(body["authors"], &authors); err != nil || len(authors) != 1 || authors[0].Email != "alice@example.com" {
+		t.Errorf("authors = %s; expected Alice", body["authors"])
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/leaderboards", nil))
+	if html := rec.Body.String(); rec.Code != http.StatusOK || !strings.Contains(html, "Alice &lt;admin&gt;") || !strings.Contains(html, "eqeqeq (2)") {
+		t.Errorf("GET /leaderboards = %d with:\n%s\nexpected the escaped author leaderboard", rec.Code, html)
+	}
+
+	// Expired reports are analyzed again
+	now = now.Add(time.Hour)
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/report", nil))
+	if n := analyses.Load(); n != 2 {
+		t.Errorf("Expected the expired report to be analyzed again, got %d analyses", n)
+	}
+
+	for _, req := range []*http.Request{httptest.NewRequest("POST", "/report", nil), httptest.NewRequest("GET", "/missing", nil)} {
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusMethodNotAllowed && rec.Code != http.StatusNotFound {
+			t.Errorf("%s %s = %d; expected it to be refused", req.Method, req.URL.Path, rec.Code)
+		}
+	}
+}
+
+func TestHandlerServesDashboard(t *testing.T) {
+	var trends atomic.Int32
+	s := New(func(ctx context.Context) (*types.Report, error) {
+		report := types.NewReport("/src/app")
+		report.Leaderboards = []string{"authors", "coverage", "summary"}
+		report.Authors = []types.LeaderboardEntry{
+			{Name: "Alice", Count: 9}, {Name: "Bob", Count: 5}, {Name: "Carol", Count: 2}, {Name: "Dave", Count: 1},
+		}
+		report.Coverage = []types.CoverageEntry{{Path: "src/app.js", CoveragePercent: 72.5}}
+		report.OverallCoverage = 72.5
+		report.Summary = &types.SummaryStats{TotalIssues: 17, Errors: 4, Warnings: 13}
+		report.Warnings = []types.Warning{{Message: "git blame failed"}}
+		return &report, nil
+	}, 0)
+	s.SetTrend(func(report *types.Report) *types.RunTrend {
+		trends.Add(1)
+		return &types.RunTrend{Since: time.Date(2026, 9, 30, 9, 0, 0, 0, time.UTC), Deltas: []types.MetricDelta{
+			{Metric: "issues", A: 20, B: 17, Delta: -3},
+			{Metric: "coverage", A: 70, B: 72.5, Delta: 2.5, HigherIsBetter: true},
+		}}
+	})
+	handler := s.Handler()
+
+	for i := 0; i < 2; i++ {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+		html := rec.Body.String()
+		for _, want := range []string{
+			`<td>Lint issues</td><td class="n">17</td><td class="n better">-3</td>`,
+			`<td>Run warnings</td><td class="n">1</td>`,
+			`72.5% (&#43;2.5%)`, // + is escaped
+			"Carol",
+			"2026-09-30 09:00:00 UTC",
+		} {
+			if rec.Code != http.StatusOK || !strings.Contains(html, want) {
+				t.Errorf("GET / = %d with:\n%s\nexpected %q", rec.Code, html, want)
+			}
+		}
+		// Only the top entries are listed, and files were not measured
+		if strings.Contains(html, "Dave") || !strings.Contains(html, "Not measured") {
+			t.Errorf("GET / listed:\n%s\nexpected the top 3 authors and unmeasured files", html)
+		}
+	}
+	// The trend is cached with the report
+	if n := trends.Load(); n != 1 {
+		t.Errorf("Expected the trend to be read once, got %d", n)
+	}
+}
+
+func TestHandlerRetriesFailedAnalysis(t *testing.T) {
+	fail := true
+	s := New(func(ctx context.Context) (*types.Report, error) {
+		if fail {
+			return nil, errors.New("not a git repository")
+		}
+		report := types.NewReport("/src/app")
+		return &report, nil
+	}, 0)
+	handler := s.Handler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/report", nil))
+	if rec.Code != http.StatusInternalServerError || !strings.Contains(rec.Body.String(), `"error": "not a git repository"`) {
+		t.Errorf("GET /report of a failed analysis = %d %s; expected 500 with the error", rec.Code, rec.Body.String())
+	}
+
+	fail = false
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/report", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("GET /report after a failed analysis = %d; expected it to be analyzed again", rec.Code)
+	}
+}
+
+func TestHandlerServesTrends(t *testing.T) {
+	s := New(func(ctx context.Context) (*types.Report, error) {
+		report := types.NewReport("/src/app")
+		report.GeneratedAt = time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+		report.Leaderboards = []string{"files", "rules", "summary"}
+		report.Files = []types.FileLeaderboardEntry{{Path: "src/app.js", Count: 4}, {Path: "src/new.js", Count: 1}}
+		report.Rules = []types.RuleLeaderboardEntry{{Rule: "eqeqeq", Count: 2}}
+		report.Summary = &types.SummaryStats{TotalIssues: 5}
+		return &report, nil
+	}, 0)
+	handler := s.Handler()
+
+	// Without history the sparklines are left out
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/leaderboards", nil))
+	if html := rec.Body.String(); strings.Contains(html, "<th>Trend</th>") || strings.Contains(html, "<svg") {
+		t.Errorf("GET /leaderboards without history listed:\n%s\nexpected no sparklines", html)
+	}
+
+	var runs atomic.Int32
+	s = New(s.analyze, 0)
+	s.SetHistory(func(report *types.Report, n int) *types.RunHistory {
+		runs.Store(int32(n))
+		return &types.RunHistory{
+			Times:  []time.Time{time.Date(2026, 9, 1, 9, 0, 0, 0, time.UTC), time.Date(2026, 9, 15, 9, 0, 0, 0, time.UTC)},
+			Totals: map[string][]float64{"issues": {9, 7}},
+			Rules:  map[string][]float64{"eqeqeq": {6, 4}},
+			Files:  map[string][]float64{"src/app.js": {8, math.NaN()}},
+		}
+	})
+	handler = s.Handler()
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/leaderboards", nil))
+	html := rec.Body.String()
+	for _, want := range []string{
+		"<th>Trend</th>",
+		`d="M0,0 L40,9 L80,18"`, // eqeqeq, 6, 4 then 2
+		`d="M0,0h0 M80,18h0"`,   // src/app.js, 8, a gap then 4
+		`d="M80,9h0"`,           // src/new.js, only now
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("GET /leaderboards listed:\n%s\nexpected %q", html, want)
+		}
+	}
+	if n := runs.Load(); n != historyRuns {
+		t.Errorf("Expected the history of %d runs asked for, got %d", historyRuns, n)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/trends", nil))
+	html = rec.Body.String()
+	for _, want := range []string{"<h2>Lint issues: 5</h2>", `d="M0,0 L240,60 L480,120"`, ">2026-09-01</text>", ">2026-10-01</text>"} {
+		if rec.Code != http.StatusOK || !strings.Contains(html, want) {
+			t.Errorf("GET /trends = %d with:\n%s\nexpected %q", rec.Code, html, want)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package server
+
+import (
+	"fmt"
+	"html/template"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xeon-zolt/codecompass/internal/leaderboard"
+)
+
+// Sizes of the sparklines of the leaderboards, and of the plot of the
+// charts of the trends page, in pixels.
+const (
+	sparklineWidth, sparklineHeight = 80, 18
+	chartWidth, chartHeight         = 480, 120
+)
+
+// plotPath returns the SVG path data drawing values as a line across a box
+// of width by height, the lowest value at the bottom and the highest at the
+// top. A flat series is drawn across the middle. NaN values, runs without
+// a value, break the line, and a value between two gaps is drawn as a dot.
+// It returns "" when every value is NaN.
+func plotPath(values []float64, width, height float64) string {
+	low, high, ok := valueRange(values)
+	if !ok {
+		return ""
+	}
+
+	var path strings.Builder
+	drawing := false
+	for i, value := range values {
+		if math.IsNaN(value) {
+			if drawing {
+				path.WriteString(dotIfAlone(values, i-1))
+			}
+			drawing = false
+			continue
+		}
+
+		x := width / 2
+		if len(values) > 1 {
+			x = float64(i) * width / float64(len(values)-1)
+		}
+		y := height / 2
+		if high > low {
+			y = height - (value-low)/(high-low)*height
+		}
+
+		if path.Len() > 0 {
+			path.WriteString(" ")
+		}
+		if drawing {
+			path.WriteString("L")
+		} else {
+			path.WriteString("M")
+		}
+		path.WriteString(coordinate(x) + "," + coordinate(y))
+		drawing = true
+	}
+	if drawing {
+		path.WriteString(dotIfAlone(values, len(values)-1))
+	}
+	return path.String()
+}
+
+// valueRange returns the lowest and highest of values, leaving out NaN,
+// and reports false when every value is NaN.
+func valueRange(values []float64) (low, high float64, ok bool) {
+	low, high = math.Inf(1), math.Inf(-1)
+	for _, value := range values {
+		if !math.IsNaN(value) {
+			low, high, ok = min(low, value), max(high, value), true
+		}
+	}
+	return low, high, ok
+}
+
+// dotIfAlone returns the zero-length line that draws the value at i of
+// values as a dot, with round line caps, when it has no value before it to
+// be joined to.
+func dotIfAlone(values []float64, i int) string {
+	if i == 0 || math.IsNaN(values[i-1]) {
+		return "h0"
+	}
+	return ""
+}
+
+// coordinate formats a coordinate to a tenth of a pixel.
+func coordinate(value float64) string {
+	return strconv.FormatFloat(math.Round(value*10)/10, 'f', -1, 64)
+}
+
+// sparkline returns an inline SVG of the trend of values, or "" when there
+// is none to draw.
+func sparkline(values []float64) template.HTML {
+	path := plotPath(values, sparklineWidth, sparklineHeight)
+	if path == "" {
+		return ""
+	}
+	return template.HTML(fmt.Sprintf(`<svg class="sparkline" width="%d" height="%d" viewBox="-2 -2 %d %d" role="img" aria-label="Trend over %d runs">`+
+		`<path d="%s" fill="none" stroke="currentColor" stroke-width="1.5" stroke-linecap="round" stroke-linejoin="round"/></svg>`,
+		sparklineWidth+4, sparklineHeight+4, sparklineWidth+4, sparklineHeight+4, len(values), path))
+}
+
+// chart returns an SVG charting the values of metric at times, labeled with
+// the lowest and highest value and the first and last date, or "" when
+// there is none to draw.
+func chart(metric string, times []time.Time, values []float64) template.HTML {
+	path := plotPath(values, chartWidth, chartHeight)
+	if path == "" {
+		return ""
+	}
+	low, high, _ := valueRange(values)
+
+	// The plot is framed by the value labels on its left and the dates
+	// below it
+	const left, top = 64, 8
+	var svg strings.Builder
+	fmt.Fprintf(&svg, `<svg class="chart" width="%d" height="%d" viewBox="0 0 %d %d" role="img" aria-label="%s over %d runs">`,
+		left+chartWidth+8, top+chartHeight+24, left+chartWidth+8, top+chartHeight+24, template.HTMLEscapeString(leaderboard.MetricLabel(metric)), len(values))
+	fmt.Fprintf(&svg, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#ddd"/>`, left, top+chartHeight, left+chartWidth, top+chartHeight)
+	fmt.Fprintf(&svg, `<text x="%d" y="%d" text-anchor="end">%s</text>`, left-6, top+4, leaderboard.FormatMetric(metric, high))
+	fmt.Fprintf(&svg, `<text x="%d" y="%d" text-anchor="end">%s</text>`, left-6, top+chartHeight+4, leaderboard.FormatMetric(metric, low))
+	if len(times) > 0 {
+		fmt.Fprintf(&svg, `<text x="%d" y="%d">%s</text>`, left, top+chartHeight+18, times[0].Format("2006-01-02"))
+		fmt.Fprintf(&svg, `<text x="%d" y="%d" text-anchor="end">%s</text>`, left+chartWidth, top+chartHeight+18, times[len(times)-1].Format("2006-01-02"))
+	}
+	fmt.Fprintf(&svg, `<path transform="translate(%d %d)" d="%s" fill="none" stroke="currentColor" stroke-width="2" stroke-linecap="round" stroke-linejoin="round"/></svg>`, left, top, path)
+	return template.HTML(svg.String())
+}
@@ -0,0 +1,54 @@
+package server
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPlotPath(t *testing.T) {
+	nan := math.NaN()
+	tests := []struct {
+		name     string
+		values   []float64
+		expected string
+	}{
+		// The highest value at the top, the lowest at the bottom
+		{"rising", []float64{0, 5, 10}, "M0,20 L50,10 L100,0"},
+		{"scaled", []float64{10, 40, 20, 30}, "M0,20 L33.3,0 L66.7,13.3 L100,6.7"},
+		{"flat", []float64{7, 7}, "M0,10 L100,10"},
+		{"single", []float64{3}, "M50,10h0"},
+		// Gaps break the line, and lone values are dots
+		{"gap", []float64{0, nan, 10, 5}, "M0,20h0 M66.7,0 L100,10"},
+		{"leading gap", []float64{nan, 0, 10}, "M50,20 L100,0"},
+		{"trailing lone", []float64{0, 10, nan, 5}, "M0,20 L33.3,0 M100,10h0"},
+		{"empty", nil, ""},
+		{"all gaps", []float64{nan, nan}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if path := plotPath(tt.values, 100, 20); path != tt.expected {
+				t.Errorf("plotPath(%v) = %q; expected %q", tt.values, path, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSparklineAndChart(t *testing.T) {
+	if svg := sparkline([]float64{math.NaN()}); svg != "" {
+		t.Errorf("Expected no sparkline without values, got %s", svg)
+	}
+	svg := string(sparkline([]float64{4, 2}))
+	if !strings.HasPrefix(svg, `<svg class="sparkline"`) || !strings.Contains(svg, `d="M0,0 L80,18"`) || !strings.Contains(svg, "Trend over 2 runs") {
+		t.Errorf("Expected a sparkline falling across its box, got %s", svg)
+	}
+
+	times := []time.Time{time.Date(2026, 9, 1, 9, 0, 0, 0, time.UTC), time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)}
+	svg = string(chart("coverage", times, []float64{61.25, 72.5}))
+	for _, want := range []string{">72.5%</text>", ">61.2%</text>", ">2026-09-01</text>", ">2026-10-01</text>", `d="M0,120 L480,0"`} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("Expected the chart to have %q, got %s", want, svg)
+		}
+	}
+}
@@ -1,18 +1,18 @@
 package spellcheck
 
 import (
-	"bufio"
-	"os"
+	"context"
+	"errors"
+	"path/filepath"
 	"regexp"
 	"sort"
 	"strings"
-	"sync"
 	"unicode"
 
-	"codecompass/internal/config"
-	"codecompass/internal/git"
-	"codecompass/internal/types"
-	"codecompass/internal/utils"
+	"github.com/xeon-zolt/codecompass/internal/config"
+	"github.com/xeon-zolt/codecompass/internal/git"
+	"github.com/xeon-zolt/codecompass/internal/types"
+	"github.com/xeon-zolt/codecompass/internal/utils"
 
 	"github.com/sajari/fuzzy"
 )
@@ -21,6 +21,94 @@ type SpellChecker struct {
 	model      *fuzzy.Model
 	customDict map[string]bool
 	progDict   map[string]bool
+	index      dictionaryIndex
+	// minWordLen is the shortest word length that is spell checked
+	minWordLen int
+}
+
+// dictionaryIndex buckets dictionary words by length and then by first letter
+// so suggestion lookup only compares against plausible candidates.
+type dictionaryIndex map[int]map[byte][]string
+
+func newDictionaryIndex(dictionary map[string]bool) dictionaryIndex {
+	index := make(dictionaryIndex)
+	for word := range dictionary {
+		if word == "" {
+			continue
+		}
+		if index[len(word)] == nil {
+			index[len(word)] = make(map[byte][]string)
+		}
+		index[len(word)][word[0]] = append(index[len(word)][word[0]], word)
+	}
+
+	// Sort buckets so suggestions come back in a stable order
+	for _, buckets := range index {
+		for _, words := range buckets {
+			sort.Strings(words)
+		}
+	}
+
+	return index
+}
+
+// candidates returns dictionary words a single edit away from word: a
+// substitution in the same length bucket, or an insertion or deletion in the
+// buckets one letter shorter and longer. The matching first-letter bucket is
+// compared before the others. Words in other buckets can only match when the
+// edit is to the first letter, so those are compared by suffix alone.
+func (idx dictionaryIndex) candidates(word string, limit int) []string {
+	var result []string
+	if word == "" {
+		return result
+	}
+
+	for _, length := range []int{len(word), len(word) - 1, len(word) + 1} {
+		buckets := idx[length]
+		for _, dictWord := range buckets[word[0]] {
+			if boundedLevenshteinDistance(word, dictWord, 1) == 1 {
+				result = append(result, dictWord)
+				if len(result) >= limit {
+					return result
+				}
+			}
+		}
+
+		letters := make([]byte, 0, len(buckets))
+		for letter := range buckets {
+			if letter != word[0] {
+				letters = append(letters, letter)
+			}
+		}
+		sort.Slice(letters, func(i, j int) bool { return letters[i] < letters[j] })
+
+		for _, letter := range letters {
+			for _, dictWord := range buckets[letter] {
+				if firstLetterEdit(word, dictWord) {
+					result = append(result, dictWord)
+					if len(result) >= limit {
+						return result
+					}
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// firstLetterEdit reports whether dictWord is word with its first letter
+// substituted, deleted, or preceded by another letter.
+func firstLetterEdit(word, dictWord string) bool {
+	switch len(dictWord) - len(word) {
+	case 0:
+		return dictWord[1:] == word[1:]
+	case -1:
+		return dictWord == word[1:]
+	case 1:
+		return dictWord[1:] == word
+	}
+	return false
 }
 
 func NewSpellChecker(cfg *config.Config) (*SpellChecker, error) {
@@ -32,10 +120,18 @@ func NewSpellChecker(cfg *config.Config) (*SpellChecker, error) {
 	commonWords := loadCommonWords()
 	model.Train(commonWords)
 
+	// A Config not built by NewConfig leaves the length unset
+	minWordLen := cfg.SpellCheckMinWordLen
+	if minWordLen <= 0 {
+		minWordLen = config.NewConfig().SpellCheckMinWordLen
+	}
+
 	return &SpellChecker{
 		model:      model,
 		customDict: createCustomDict(cfg.CustomWords),
 		progDict:   createProgrammingDict(),
+		index:      newDictionaryIndex(basicDictionary),
+		minWordLen: minWordLen,
 	}, nil
 }
 
@@ -244,7 +340,10 @@ var basicDictionary = map[string]bool{
 	"world": true, "years": true, "yourself": true,
 }
 
-func AnalyzeSpelling(trackedFiles map[string]bool, cfg *config.Config) ([]types.SpellCheckEntry, map[string]*types.SpellCheckAuthorStats, error) {
+// AnalyzeSpelling checks comments in the tracked files of the repository in
+// dir, attributing misspellings to authors through blamer. Files that cannot
+// be read are skipped and added to warnings.
+func AnalyzeSpelling(ctx context.Context, dir string, trackedFiles map[string]bool, cfg *config.Config, blamer *git.Blamer, warnings *utils.WarningCollector, progress utils.ProgressFunc) ([]types.SpellCheckEntry, map[string]*types.SpellCheckAuthorStats, error) {
 	spellChecker, err := NewSpellChecker(cfg)
 	if err != nil {
 		return nil, nil, err
@@ -253,13 +352,24 @@ func AnalyzeSpelling(trackedFiles map[string]bool, cfg *config.Config) ([]types.
 	var entries []types.SpellCheckEntry
 	authorStats := make(map[string]*types.SpellCheckAuthorStats)
 
+	var files []string
 	for filePath := range trackedFiles {
-		if !isSpellCheckFile(filePath, cfg) {
-			continue
+		if isSpellCheckFile(filePath, cfg) {
+			files = append(files, filePath)
+		}
+	}
+
+	for i, filePath := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
 		}
+		progress.Report(i, len(files))
 
-		entry, fileAuthorStats, err := analyzeFileSpelling(filePath, spellChecker, cfg)
-		if err != nil {
+		entry, fileAuthorStats, err := analyzeFileSpelling(ctx, dir, filePath, cfg.MaxLineSize, spellChecker, blamer)
+		if errors.Is(err, utils.ErrSymlink) {
+			continue
+		} else if err != nil {
+			warnings.Add(types.NewWarning("Spell check skipped", "file", filePath, "error", err))
 			continue
 		}
 
@@ -279,23 +389,38 @@ func AnalyzeSpelling(trackedFiles map[string]bool, cfg *config.Config) ([]types.
 				}
 
 				authorStats[email].TotalErrors += stats.TotalErrors
-				authorStats[email].Files[filePath] += stats.TotalErrors
+				authorStats[email].TotalWords += stats.TotalWords
+				if stats.TotalErrors > 0 {
+					authorStats[email].Files[filePath] += stats.TotalErrors
+				}
 				for mistake, count := range stats.CommonMistakes {
 					authorStats[email].CommonMistakes[mistake] += count
 				}
 			}
 		}
 	}
+	progress.Report(len(files), len(files))
 
-	sort.Slice(entries, func(i, j int) bool {
-		return entries[i].ErrorRate > entries[j].ErrorRate
+	// Authors who only wrote words that are spelled right have no place on
+	// the leaderboard
+	for email, stats := range authorStats {
+		if stats.TotalErrors == 0 {
+			delete(authorStats, email)
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].ErrorRate != entries[j].ErrorRate {
+			return entries[i].ErrorRate > entries[j].ErrorRate
+		}
+		return entries[i].Path < entries[j].Path
 	})
 
 	return entries, authorStats, nil
 }
 
-func analyzeFileSpelling(filePath string, spellChecker *SpellChecker, cfg *config.Config) (types.SpellCheckEntry, map[string]*types.SpellCheckAuthorStats, error) {
-	file, err := os.Open(filePath)
+func analyzeFileSpelling(ctx context.Context, dir, filePath string, maxLineKB int, spellChecker *SpellChecker, blamer *git.Blamer) (types.SpellCheckEntry, map[string]*types.SpellCheckAuthorStats, error) {
+	file, err := utils.OpenRegular(filepath.Join(dir, filePath))
 	if err != nil {
 		return types.SpellCheckEntry{}, nil, err
 	}
@@ -310,12 +435,12 @@ func analyzeFileSpelling(filePath string, spellChecker *SpellChecker, cfg *confi
 	authorStats := make(map[string]*types.SpellCheckAuthorStats)
 
 	// Get git blame for this file
-	blameMap, err := getBlameForSpellCheck(filePath, cfg)
+	blameMap, err := getBlameForSpellCheck(ctx, filePath, blamer)
 	if err != nil {
 		blameMap = make(map[int]types.BlameInfo)
 	}
 
-	scanner := bufio.NewScanner(file)
+	scanner := utils.NewLineScanner(file, maxLineKB)
 	lineNum := 0
 
 	// Focus mainly on comments and documentation
@@ -356,24 +481,23 @@ func analyzeFileSpelling(filePath string, spellChecker *SpellChecker, cfg *confi
 	return entry, authorStats, scanner.Err()
 }
 
-func getBlameForSpellCheck(filePath string, cfg *config.Config) (map[int]types.BlameInfo, error) {
-	var mu sync.Mutex
-	var warningLogs []string
-	semaphore := utils.NewSemaphore(cfg.GetConcurrency())
-
-	return git.BlameFile(filePath, &warningLogs, &mu, semaphore)
+func getBlameForSpellCheck(ctx context.Context, filePath string, blamer *git.Blamer) (map[int]types.BlameInfo, error) {
+	return blamer.BlameFile(ctx, filePath)
 }
 
 func analyzeText(text string, lineNum int, contextType string, entry *types.SpellCheckEntry, authorStats map[string]*types.SpellCheckAuthorStats, blameInfo *types.BlameInfo, spellChecker *SpellChecker) {
-	words := extractWords(text)
+	words := extractWords(stripNonProse(text))
 
 	for _, word := range words {
 		// Skip very short words or likely code
-		if len(word) < 4 || isLikelyCode(word) {
+		if len(word) < spellChecker.minWordLen || isLikelyCode(word) {
 			continue
 		}
 
 		entry.TotalWords++
+		if blameInfo != nil {
+			authorStatsFor(authorStats, blameInfo).TotalWords++
+		}
 
 		if !spellChecker.IsCorrect(word) {
 			entry.MisspelledWords++
@@ -398,17 +522,20 @@ func analyzeText(text string, lineNum int, contextType string, entry *types.Spel
 	}
 }
 
-func analyzeIdentifier(identifier string, lineNum int, entry *types.SpellCheckEntry, authorStats map[string]*types.SpellCheckAuthorStats, blameInfo *types.BlameInfo, customDict map[string]bool) {
+func analyzeIdentifier(identifier string, lineNum int, entry *types.SpellCheckEntry, authorStats map[string]*types.SpellCheckAuthorStats, blameInfo *types.BlameInfo, spellChecker *SpellChecker) {
 	words := splitIdentifier(identifier)
 
 	for _, word := range words {
-		if len(word) < 3 {
+		if len(word) < spellChecker.minWordLen {
 			continue
 		}
 
 		entry.TotalWords++
+		if blameInfo != nil {
+			authorStatsFor(authorStats, blameInfo).TotalWords++
+		}
 
-		if !isCorrectlySpelledWithCustom(word, customDict) && !isCommonAbbreviation(word) {
+		if !isCorrectlySpelledWithCustom(word, spellChecker.customDict) && !isCommonAbbreviation(word) {
 			entry.MisspelledWords++
 			entry.TopMisspellings[strings.ToLower(word)]++
 
@@ -417,7 +544,7 @@ func analyzeIdentifier(identifier string, lineNum int, entry *types.SpellCheckEn
 				Line:        lineNum,
 				Context:     identifier,
 				Type:        "identifier",
-				Suggestions: getSuggestions(word),
+				Suggestions: spellChecker.getSuggestions(word),
 				Author:      getAuthorName(blameInfo),
 				AuthorEmail: getAuthorEmail(blameInfo),
 			}
@@ -443,7 +570,9 @@ func isCorrectlySpelledWithCustom(word string, customDict map[string]bool) bool
 	return isCorrectlySpelled(word)
 }
 
-func updateAuthorStats(authorStats map[string]*types.SpellCheckAuthorStats, blameInfo *types.BlameInfo, word string) {
+// authorStatsFor returns the stats of the author blameInfo blames, adding
+// them to authorStats the first time.
+func authorStatsFor(authorStats map[string]*types.SpellCheckAuthorStats, blameInfo *types.BlameInfo) *types.SpellCheckAuthorStats {
 	email := blameInfo.Email
 	if authorStats[email] == nil {
 		authorStats[email] = &types.SpellCheckAuthorStats{
@@ -454,9 +583,13 @@ func updateAuthorStats(authorStats map[string]*types.SpellCheckAuthorStats, blam
 			CommonMistakes: make(map[string]int),
 		}
 	}
+	return authorStats[email]
+}
 
-	authorStats[email].TotalErrors++
-	authorStats[email].CommonMistakes[strings.ToLower(word)]++
+func updateAuthorStats(authorStats map[string]*types.SpellCheckAuthorStats, blameInfo *types.BlameInfo, word string) {
+	stats := authorStatsFor(authorStats, blameInfo)
+	stats.TotalErrors++
+	stats.CommonMistakes[strings.ToLower(word)]++
 }
 
 func getAuthorName(blameInfo *types.BlameInfo) string {
@@ -478,11 +611,8 @@ func isSpellCheckFile(filePath string, cfg *config.Config) bool {
 		return false
 	}
 
-	// Check against ignored paths
-	for _, ignorePath := range cfg.SpellCheckIgnorePaths {
-		if strings.Contains(filePath, ignorePath) {
-			return false
-		}
+	if cfg.ShouldIgnoreForLeaderboard("spellcheck", filePath) {
+		return false
 	}
 
 	// Check file extension
@@ -495,6 +625,28 @@ func isSpellCheckFile(filePath string, cfg *config.Config) bool {
 	return false
 }
 
+// codeSpan matches backtick-quoted inline code.
+var codeSpan = regexp.MustCompile("`[^`]*`")
+
+// stripNonProse removes the parts of a comment that are not prose, so their
+// words are neither spell checked nor counted: inline code spans, URLs,
+// email addresses, paths and tokens such as fmt.Println, std::vector or
+// node->next.
+func stripNonProse(text string) string {
+	fields := strings.Fields(codeSpan.ReplaceAllString(text, " "))
+	prose := fields[:0]
+	for _, field := range fields {
+		// Punctuation around a word, such as a full stop, is not part of it
+		token := strings.Trim(field, `.,;:!?"'()[]{}`)
+		if strings.ContainsAny(token, "./") || strings.Index(token, "@") > 0 ||
+			strings.Contains(token, "::") || strings.Contains(token, "->") {
+			continue
+		}
+		prose = append(prose, field)
+	}
+	return strings.Join(prose, " ")
+}
+
 func extractWords(text string) []string {
 	// Simple word extraction
 	wordRegex := regexp.MustCompile(`[a-zA-Z]+`)
@@ -628,7 +780,7 @@ func isTextFile(filePath string) bool {
 	return false
 }
 
-func getSuggestions(word string) []string {
+func (sc *SpellChecker) getSuggestions(word string) []string {
 	// Simple suggestion algorithm - in a real implementation,
 	// you might use a more sophisticated algorithm like Levenshtein distance
 	var suggestions []string
@@ -657,52 +809,69 @@ func getSuggestions(word string) []string {
 	}
 
 	// Add some basic suggestions based on dictionary
-	for dictWord := range basicDictionary {
-		if len(dictWord) == len(word) && levenshteinDistance(lowerWord, dictWord) == 1 {
-			suggestions = append(suggestions, dictWord)
-			if len(suggestions) >= 3 { // Limit suggestions
-				break
-			}
-		}
+	if limit := 3 - len(suggestions); limit > 0 { // Limit suggestions
+		suggestions = append(suggestions, sc.index.candidates(lowerWord, limit)...)
 	}
 
 	return suggestions
 }
 
 func levenshteinDistance(a, b string) int {
-	if len(a) == 0 {
-		return len(b)
+	return boundedLevenshteinDistance(a, b, -1)
+}
+
+// boundedLevenshteinDistance computes the edit distance between a and b using
+// two rows instead of a full matrix. When maxDistance is non-negative the
+// computation stops as soon as the distance is known to exceed it, returning
+// maxDistance+1.
+func boundedLevenshteinDistance(a, b string, maxDistance int) int {
+	// Keep the rows sized by the shorter string
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+
+	if maxDistance >= 0 && len(a)-len(b) > maxDistance {
+		return maxDistance + 1
 	}
 	if len(b) == 0 {
 		return len(a)
 	}
 
-	matrix := make([][]int, len(a)+1)
-	for i := range matrix {
-		matrix[i] = make([]int, len(b)+1)
-		matrix[i][0] = i // Fix: assign to first element, not entire slice
-	}
-
-	for j := 1; j <= len(b); j++ {
-		matrix[0][j] = j // Fix: assign to matrix[0][j], not matrix[j]
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
 	}
 
 	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		rowMin := current[0]
+
 		for j := 1; j <= len(b); j++ {
 			cost := 0
 			if a[i-1] != b[j-1] {
 				cost = 1
 			}
 
-			matrix[i][j] = minOfThree(
-				matrix[i-1][j]+1,      // deletion
-				matrix[i][j-1]+1,      // insertion
-				matrix[i-1][j-1]+cost, // substitution
+			current[j] = minOfThree(
+				previous[j]+1,      // deletion
+				current[j-1]+1,     // insertion
+				previous[j-1]+cost, // substitution
 			)
+			if current[j] < rowMin {
+				rowMin = current[j]
+			}
+		}
+
+		// Every later row is at least as large as this row's minimum
+		if maxDistance >= 0 && rowMin > maxDistance {
+			return maxDistance + 1
 		}
+
+		previous, current = current, previous
 	}
 
-	return matrix[len(a)][len(b)]
+	return previous[len(b)]
 }
 
 func minOfThree(a, b, c int) int {
@@ -3,7 +3,8 @@ package spellcheck
 import (
 	"testing"
 
-	"codecompass/internal/config"
+	"github.com/xeon-zolt/codecompass/internal/config"
+	"github.com/xeon-zolt/codecompass/internal/types"
 )
 
 func TestSpellChecker(t *testing.T) {
@@ -35,3 +36,289 @@ func TestSpellChecker(t *testing.T) {
 		t.Errorf("Expected suggestions for 'helo'")
 	}
 }
+
+func TestLevenshteinDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"abc", "", 3},
+		{"kitten", "sitting", 3},
+		{"sitting", "kitten", 3},
+		{"flaw", "lawn", 2},
+		{"helo", "hello", 1},
+		{"recieve", "receive", 2},
+		{"same", "same", 0},
+		{"intention", "execution", 5},
+	}
+
+	for _, tt := range tests {
+		if got := levenshteinDistance(tt.a, tt.b); got != tt.expected {
+			t.Errorf("levenshteinDistance(%q, %q) = %d, expected %d", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
+
+func TestBoundedLevenshteinDistance(t *testing.T) {
+	// Distances within the bound are exact
+	if got := boundedLevenshteinDistance("kitten", "sitting", 3); got != 3 {
+		t.Errorf("Expected distance 3 within bound, but got %d", got)
+	}
+
+	// Distances beyond the bound stop early at bound+1
+	if got := boundedLevenshteinDistance("kitten", "sitting", 1); got != 2 {
+		t.Errorf("Expected early termination at 2, but got %d", got)
+	}
+
+	if got := boundedLevenshteinDistance("a", "abcdef", 2); got != 3 {
+		t.Errorf("Expected length difference to terminate at 3, but got %d", got)
+	}
+}
+
+// matrixLevenshteinDistance is the original full matrix implementation, kept
+// as a reference for the two-row version.
+func matrixLevenshteinDistance(a, b string) int {
+	if len(a) == 0 {
+		return len(b)
+	}
+	if len(b) == 0 {
+		return len(a)
+	}
+
+	matrix := make([][]int, len(a)+1)
+	for i := range matrix {
+		matrix[i] = make([]int, len(b)+1)
+		matrix[i][0] = i
+	}
+
+	for j := 1; j <= len(b); j++ {
+		matrix[0][j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 0
+			if a[i-1] != b[j-1] {
+				cost = 1
+			}
+
+			matrix[i][j] = minOfThree(
+				matrix[i-1][j]+1,
+				matrix[i][j-1]+1,
+				matrix[i-1][j-1]+cost,
+			)
+		}
+	}
+
+	return matrix[len(a)][len(b)]
+}
+
+func TestLevenshteinDistanceMatchesMatrix(t *testing.T) {
+	for word := range basicDictionary {
+		if got, want := levenshteinDistance("definately", word), matrixLevenshteinDistance("definately", word); got != want {
+			t.Errorf("levenshteinDistance(%q, %q) = %d, matrix implementation gives %d", "definately", word, got, want)
+		}
+	}
+}
+
+func BenchmarkMatrixLevenshteinDistance(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for word := range basicDictionary {
+			matrixLevenshteinDistance("definately", word)
+		}
+	}
+}
+
+func BenchmarkLevenshteinDistance(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for word := range basicDictionary {
+			levenshteinDistance("definately", word)
+		}
+	}
+}
+
+func BenchmarkBoundedLevenshteinDistance(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for word := range basicDictionary {
+			boundedLevenshteinDistance("definately", word, 1)
+		}
+	}
+}
+
+// linearSuggestions is the original full dictionary scan, kept as a reference
+// for the indexed lookup.
+func linearSuggestions(word string) map[string]bool {
+	result := make(map[string]bool)
+	for dictWord := range basicDictionary {
+		lengthDiff := len(dictWord) - len(word)
+		if lengthDiff >= -1 && lengthDiff <= 1 && levenshteinDistance(word, dictWord) == 1 {
+			result[dictWord] = true
+		}
+	}
+	return result
+}
+
+func TestGetSuggestionsMatchesLinearScan(t *testing.T) {
+	sc, err := NewSpellChecker(config.NewConfig())
+	if err != nil {
+		t.Fatalf("Failed to create spell checker: %v", err)
+	}
+
+	words := []string{"helo", "wrold", "tset", "coed", "mave", "thw", "plsy", "xyzzy", "functon", "ssource", "ource"}
+	for _, word := range words {
+		expected := linearSuggestions(word)
+		got := sc.getSuggestions(word)
+
+		if len(expected) <= 3 && len(got) != len(expected) {
+			t.Errorf("getSuggestions(%q) = %v, expected %d suggestions", word, got, len(expected))
+		}
+		for _, suggestion := range got {
+			if !expected[suggestion] {
+				t.Errorf("getSuggestions(%q) returned unexpected suggestion %q", word, suggestion)
+			}
+		}
+	}
+}
+
+func TestGetSuggestionsInsertionsAndDeletions(t *testing.T) {
+	sc, err := NewSpellChecker(config.NewConfig())
+	if err != nil {
+		t.Fatalf("Failed to create spell checker: %v", err)
+	}
+
+	tests := map[string]string{
+		"functon": "function", // missing letter
+		"returnn": "return",   // extra letter
+		"eturn":   "return",   // missing first letter
+	}
+
+	for word, expected := range tests {
+		found := false
+		for _, suggestion := range sc.getSuggestions(word) {
+			if suggestion == expected {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("getSuggestions(%q) = %v, expected it to include %q", word, sc.getSuggestions(word), expected)
+		}
+	}
+}
+
+func BenchmarkLinearSuggestions(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		linearSuggestions("wrold")
+	}
+}
+
+func BenchmarkIndexedSuggestions(b *testing.B) {
+	sc, err := NewSpellChecker(config.NewConfig())
+	if err != nil {
+		b.Fatalf("Failed to create spell checker: %v", err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sc.getSuggestions("wrold")
+	}
+}
+
+func TestMinWordLength(t *testing.T) {
+	tests := []struct {
+		minWordLen int
+		expected   int
+	}{
+		{minWordLen: 4, expected: 0},
+		{minWordLen: 3, expected: 1},
+		{minWordLen: 0, expected: 0}, // unset falls back to the default
+	}
+
+	for _, tt := range tests {
+		cfg := config.NewConfig()
+		cfg.SpellCheckMinWordLen = tt.minWordLen
+		sc, err := NewSpellChecker(cfg)
+		if err != nil {
+			t.Fatalf("Failed to create spell checker: %v", err)
+		}
+
+		textEntry := types.SpellCheckEntry{TopMisspellings: make(map[string]int)}
+		analyzeText("and thw for", 1, "comment", &textEntry, nil, nil, sc)
+		if textEntry.MisspelledWords != tt.expected {
+			t.Errorf("analyzeText with min length %d found %d misspellings, expected %d", tt.minWordLen, textEntry.MisspelledWords, tt.expected)
+		}
+
+		identEntry := types.SpellCheckEntry{TopMisspellings: make(map[string]int)}
+		analyzeIdentifier("thwValue", 1, &identEntry, nil, nil, sc)
+		if identEntry.TopMisspellings["thw"] != tt.expected {
+			t.Errorf("analyzeIdentifier with min length %d found %d misspellings of 'thw', expected %d", tt.minWordLen, identEntry.TopMisspellings["thw"], tt.expected)
+		}
+	}
+}
+
+func TestAnalyzeTextSkipsNonProse(t *testing.T) {
+	sc, err := NewSpellChecker(config.NewConfig())
+	if err != nil {
+		t.Fatalf("Failed to create spell checker: %v", err)
+	}
+
+	tests := []struct {
+		text       string
+		totalWords int
+		skipped    []string
+	}{
+		{"see https://exampel.com/foo-barr for details", 1, []string{"https", "exampel", "barr"}},
+		{"visit www.exampel.com today", 2, []string{"exampel"}},
+		{"mail sombody@exampel.org about this", 3, []string{"sombody", "exampel"}},
+		{"config lives in ./configz/settngs.yaml and /etc/codecompas", 2, []string{"configz", "settngs", "codecompas"}},
+		{"run `npm ci --prefer-ofline` before testing", 2, []string{"prefer", "ofline"}},
+		{"calls fmt.Printlnn when done", 3, []string{"printlnn"}},
+		{"uses std::vectr internally", 2, []string{"vectr"}},
+		{"follows node->nextt until empty", 3, []string{"nextt"}},
+		// Sentence punctuation does not make a word a token to skip
+		{"this works. really, it does!", 4, nil},
+	}
+
+	for _, tt := range tests {
+		entry := types.SpellCheckEntry{TopMisspellings: make(map[string]int)}
+		analyzeText(tt.text, 1, "comment", &entry, nil, nil, sc)
+		if entry.TotalWords != tt.totalWords {
+			t.Errorf("analyzeText(%q) counted %d words, expected %d", tt.text, entry.TotalWords, tt.totalWords)
+		}
+		for _, word := range tt.skipped {
+			if entry.TopMisspellings[word] > 0 {
+				t.Errorf("analyzeText(%q) flagged %q, which should have been skipped", tt.text, word)
+			}
+		}
+	}
+}
+
+func TestAnalyzeTextCountsAuthorWords(t *testing.T) {
+	sc, err := NewSpellChecker(config.NewConfig())
+	if err != nil {
+		t.Fatalf("Failed to create spell checker: %v", err)
+	}
+
+	entry := types.SpellCheckEntry{TopMisspellings: make(map[string]int)}
+	authorStats := make(map[string]*types.SpellCheckAuthorStats)
+	alice := &types.BlameInfo{Name: "Alice", Email: "alice@example.com"}
+	bob := &types.BlameInfo{Name: "Bob", Email: "bob@example.com"}
+	analyzeText("hello world test code", 1, "comment", &entry, authorStats, alice, sc)
+	analyzeText("hello wrold", 2, "comment", &entry, authorStats, bob, sc)
+	analyzeText("test code", 3, "comment", &entry, authorStats, nil, sc)
+
+	// Words spelled right count too, so the rate of each author can be told
+	if stats := authorStats["alice@example.com"]; stats == nil || stats.TotalWords != 4 || stats.TotalErrors != 0 {
+		t.Errorf("Expected 4 words and no errors for Alice, but got %+v", stats)
+	}
+	if stats := authorStats["bob@example.com"]; stats == nil || stats.TotalWords != 2 || stats.TotalErrors != 1 {
+		t.Errorf("Expected 2 words and 1 error for Bob, but got %+v", stats)
+	}
+	if entry.TotalWords != 8 {
+		t.Errorf("Expected 8 words in the file, but got %d", entry.TotalWords)
+	}
+}
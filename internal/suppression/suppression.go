@@ -0,0 +1,256 @@
+// Package suppression reads and writes the suppression baseline of a
+// repository: the lint issues, debt markers and misspellings accepted as
+// legacy debt, which later runs leave out of the leaderboards so the
+// findings added since stand out.
+package suppression
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+)
+
+// DefaultFile is the suppression baseline runs read, at the root of the
+// repository.
+const DefaultFile = ".codecompass-baseline.json"
+
+// Version is the layout version of the files Write writes.
+const Version = 1
+
+// Kinds of findings a baseline records.
+const (
+	KindIssue    = "issue"
+	KindDebt     = "debt"
+	KindSpelling = "spelling"
+)
+
+// Record is one finding of a baseline. Fingerprint identifies it, as the
+// fingerprint package computes it, so it survives edits to the lines
+// around it; the path and rule are there for the reader of the file and to
+// tell whether the finding is stale.
+type Record struct {
+	Kind        string `json:"kind"`
+	Path        string `json:"path"` // Slash-separated, relative to the repository
+	Rule        string `json:"rule"` // The lint rule, the debt marker, such as TODO, or the misspelled word
+	Fingerprint string `json:"fingerprint"`
+}
+
+func (r Record) key() string {
+	return r.Kind + "\x00" + r.Fingerprint
+}
+
+func (r Record) less(o Record) bool {
+	if r.Kind != o.Kind {
+		return r.Kind < o.Kind
+	}
+	if r.Path != o.Path {
+		return r.Path < o.Path
+	}
+	if r.Rule != o.Rule {
+		return r.Rule < o.Rule
+	}
+	return r.Fingerprint < o.Fingerprint
+}
+
+// Baseline is a suppression baseline read by Read.
+type Baseline struct {
+	// Path is the file the baseline was read from.
+	Path    string
+	Records []Record
+}
+
+type file struct {
+	Version int      `json:"version"`
+	Records []Record `json:"records"`
+}
+
+// Read reads the baseline written by Write to path. A missing file is
+// reported as an error satisfying errors.Is(err, fs.ErrNotExist).
+func Read(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline %s: %w", path, err)
+	}
+	if f.Version != Version {
+		return nil, fmt.Errorf("baseline %s has version %d, expected %d", path, f.Version, Version)
+	}
+	return &Baseline{Path: path, Records: f.Records}, nil
+}
+
+// Write writes records to path as a baseline. Records are sorted by kind,
+// path, rule and fingerprint, one per line, so the file can be committed
+// and its changes reviewed like code.
+func Write(path string, records []Record) error {
+	sorted := append([]Record(nil), records...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].less(sorted[j]) })
+
+	var content bytes.Buffer
+	fmt.Fprintf(&content, "{\n  \"version\": %d,\n  \"records\": [", Version)
+	for i, record := range sorted {
+		line, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			content.WriteString(",")
+		}
+		content.WriteString("\n    ")
+		content.Write(line)
+	}
+	if len(sorted) > 0 {
+		content.WriteString("\n  ")
+	}
+	content.WriteString("]\n}\n")
+
+	if err := os.WriteFile(path, content.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write baseline to %s: %w", path, err)
+	}
+	return nil
+}
+
+// Filter matches the findings of a run with a baseline. It records every
+// finding it is given, so the run can be written as the next baseline, and
+// which of the baseline's records no finding matched.
+//
+// The methods of a nil Filter suppress and record nothing. A Filter is
+// safe for concurrent use.
+type Filter struct {
+	mu sync.Mutex
+
+	// subtract tells whether findings in the baseline are suppressed, or
+	// only recorded
+	subtract bool
+	known    map[string]Record
+	matched  map[string]bool
+
+	found      []Record
+	suppressed map[string]int
+	// checked holds the files each kind of finding was looked for in
+	checked map[string]map[string]bool
+}
+
+// NewFilter returns a filter that matches findings with the records of
+// baseline, which may be nil, and suppresses those it has when subtract is
+// true.
+func NewFilter(baseline *Baseline, subtract bool) *Filter {
+	f := &Filter{
+		subtract:   subtract,
+		known:      make(map[string]Record),
+		matched:    make(map[string]bool),
+		suppressed: make(map[string]int),
+		checked:    make(map[string]map[string]bool),
+	}
+	if baseline != nil {
+		for _, record := range baseline.Records {
+			f.known[record.key()] = record
+		}
+	}
+	return f
+}
+
+// Suppress records a finding of the run and reports whether it is left out
+// of the leaderboards, because the baseline has it.
+func (f *Filter) Suppress(record Record) bool {
+	if f == nil {
+		return false
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.found = append(f.found, record)
+	if _, ok := f.known[record.key()]; !ok {
+		return false
+	}
+	f.matched[record.key()] = true
+	if f.subtract {
+		f.suppressed[record.Kind]++
+	}
+	return f.subtract
+}
+
+// Check records that findings of kind were looked for in files, keyed by
+// their slash-separated paths, so the baseline's records of kind in those
+// files that were not matched are stale.
+func (f *Filter) Check(kind string, files map[string]bool) {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.checked[kind] = files
+}
+
+// Suppressed returns how many findings of kind were suppressed.
+func (f *Filter) Suppressed(kind string) int {
+	if f == nil {
+		return 0
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.suppressed[kind]
+}
+
+// StaleRecord is a record of a baseline that no longer matches a finding.
+type StaleRecord struct {
+	Record
+
+	// Deleted tells whether its file is gone, rather than the finding.
+	Deleted bool
+}
+
+// Stale returns the baseline's records that no finding matched, of the
+// kinds that were checked: those of the files checked, and those of files
+// that exist no more, as exists tells. Records of files left out of the run
+// are not stale. They are sorted as Write sorts them.
+func (f *Filter) Stale(exists func(path string) bool) []StaleRecord {
+	if f == nil {
+		return nil
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var stale []StaleRecord
+	for key, record := range f.known {
+		files, ok := f.checked[record.Kind]
+		if !ok || f.matched[key] {
+			continue
+		}
+		if deleted := !exists(record.Path); deleted || files[record.Path] {
+			stale = append(stale, StaleRecord{Record: record, Deleted: deleted})
+		}
+	}
+	sort.Slice(stale, func(i, j int) bool { return stale[i].less(stale[j].Record) })
+	return stale
+}
+
+// Records returns the findings of the run, to write as the next baseline.
+// The baseline's records of kinds that were not checked are kept, so a run
+// that did not look for misspellings keeps those accepted before.
+func (f *Filter) Records() []Record {
+	if f == nil {
+		return nil
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	records := make([]Record, 0, len(f.found))
+	for _, record := range f.found {
+		if _, ok := f.checked[record.Kind]; ok {
+			records = append(records, record)
+		}
+	}
+	for _, record := range f.known {
+		if _, ok := f.checked[record.Kind]; !ok {
+			records = append(records, record)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].less(records[j]) })
+	return records
+}
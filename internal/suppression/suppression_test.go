@@ -0,0 +1,102 @@
+package suppression
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestWriteAndRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultFile)
+	if _, err := Read(path); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("Expected a missing baseline to be fs.ErrNotExist, but got %v", err)
+	}
+
+	records := []Record{
+		{Kind: KindSpelling, Path: "README.md", Rule: "teh", Fingerprint: "c3"},
+		{Kind: KindDebt, Path: "src/b.js", Rule: "TODO", Fingerprint: "b2"},
+		{Kind: KindDebt, Path: "src/a.js", Rule: "FIXME", Fingerprint: "a1"},
+	}
+	if err := Write(path, records); err != nil {
+		t.Fatal(err)
+	}
+
+	// One record per line, sorted, so changes to the file diff well
+	expected := `{
+  "version": 1,
+  "records": [
+    {"kind":"debt","path":"src/a.js","rule":"FIXME","fingerprint":"a1"},
+    {"kind":"debt","path":"src/b.js","rule":"TODO","fingerprint":"b2"},
+    {"kind":"spelling","path":"README.md","rule":"teh","fingerprint":"c3"}
+  ]
+}
+`
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != expected {
+		t.Errorf("Expected:\n%s\nbut got:\n%s", expected, data)
+	}
+
+	baseline, err := Read(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if baseline.Path != path || !reflect.DeepEqual(baseline.Records, []Record{records[2], records[1], records[0]}) {
+		t.Errorf("Expected the sorted records read back, but got %+v", baseline)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"version": 2, "records": []}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Read(path); err == nil {
+		t.Error("Expected an error for an unknown version")
+	}
+}
+
+func TestFilter(t *testing.T) {
+	kept := Record{Kind: KindDebt, Path: "src/a.js", Rule: "TODO", Fingerprint: "a1"}
+	fixed := Record{Kind: KindDebt, Path: "src/a.js", Rule: "FIXME", Fingerprint: "a2"}
+	deleted := Record{Kind: KindDebt, Path: "src/gone.js", Rule: "HACK", Fingerprint: "g1"}
+	unchecked := Record{Kind: KindDebt, Path: "vendor/x.js", Rule: "TODO", Fingerprint: "x1"}
+	spelling := Record{Kind: KindSpelling, Path: "README.md", Rule: "teh", Fingerprint: "s1"}
+	baseline := &Baseline{Records: []Record{kept, fixed, deleted, unchecked, spelling}}
+
+	filter := NewFilter(baseline, true)
+	added := Record{Kind: KindDebt, Path: "src/a.js", Rule: "TODO", Fingerprint: "a3"}
+	if !filter.Suppress(kept) || filter.Suppress(added) {
+		t.Error("Expected only the baselined finding suppressed")
+	}
+	filter.Check(KindDebt, map[string]bool{"src/a.js": true})
+	if n := filter.Suppressed(KindDebt); n != 1 {
+		t.Errorf("Expected 1 suppressed, but got %d", n)
+	}
+
+	// The vendored file still exists but was left out of the run
+	exists := func(path string) bool { return path != "src/gone.js" }
+	expected := []StaleRecord{{Record: fixed}, {Record: deleted, Deleted: true}}
+	if stale := filter.Stale(exists); !reflect.DeepEqual(stale, expected) {
+		t.Errorf("Expected %+v, but got %+v", expected, stale)
+	}
+
+	// Misspellings were not looked for, so those accepted before are kept
+	records := []Record{kept, added, spelling}
+	if got := filter.Records(); !reflect.DeepEqual(got, records) {
+		t.Errorf("Expected %+v, but got %+v", records, got)
+	}
+
+	// Recording only suppresses nothing
+	recording := NewFilter(baseline, false)
+	if recording.Suppress(kept) || recording.Suppressed(KindDebt) != 0 {
+		t.Error("Expected a recording filter to suppress nothing")
+	}
+
+	var none *Filter
+	if none.Suppress(kept) || none.Records() != nil || none.Stale(exists) != nil {
+		t.Error("Expected a nil filter to suppress and record nothing")
+	}
+}
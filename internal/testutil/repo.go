@@ -0,0 +1,149 @@
+// Package testutil builds git repositories for tests.
+package testutil
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// DefaultAuthorName and DefaultAuthorEmail identify the author of commits
+// made before WithAuthor is called.
+const (
+	DefaultAuthorName  = "Test User"
+	DefaultAuthorEmail = "test@example.com"
+)
+
+// StartDate is the date of the first commit in a new repository. Each commit
+// or merge is one hour after the previous one, so histories are identical
+// from run to run.
+var StartDate = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// RepoBuilder creates a git repository in a temporary directory. Methods
+// fail the test on error and return the builder so calls can be chained:
+//
+//	dir := testutil.NewRepo(t).
+//		WithAuthor("Alice", "alice@example.com").
+//		Commit("initial commit", map[string]string{"main.js": "console.log(1);\n"}).
+//		Dir()
+type RepoBuilder struct {
+	t     testing.TB
+	dir   string
+	name  string
+	email string
+	date  time.Time
+}
+
+// NewRepo initializes an empty repository on branch main.
+func NewRepo(t testing.TB) *RepoBuilder {
+	t.Helper()
+
+	b := &RepoBuilder{
+		t:     t,
+		dir:   t.TempDir(),
+		name:  DefaultAuthorName,
+		email: DefaultAuthorEmail,
+		date:  StartDate,
+	}
+	b.Git("init", "--quiet")
+	b.Git("symbolic-ref", "HEAD", "refs/heads/main")
+	return b
+}
+
+// Dir returns the repository directory.
+func (b *RepoBuilder) Dir() string {
+	return b.dir
+}
+
+// WithAuthor sets the author and committer of the following commits.
+func (b *RepoBuilder) WithAuthor(name, email string) *RepoBuilder {
+	b.name = name
+	b.email = email
+	return b
+}
+
+// At sets the date of the next commit. Later commits follow it an hour
+// apart.
+func (b *RepoBuilder) At(date time.Time) *RepoBuilder {
+	b.date = date
+	return b
+}
+
+// Write writes files, keyed by slash-separated path, without committing
+// them.
+func (b *RepoBuilder) Write(files map[string]string) *RepoBuilder {
+	b.t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(b.dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			b.t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			b.t.Fatal(err)
+		}
+	}
+	return b
+}
+
+// Commit writes files and commits every change in the work tree with msg.
+func (b *RepoBuilder) Commit(msg string, files map[string]string) *RepoBuilder {
+	b.t.Helper()
+
+	b.Write(files)
+	b.Git("add", "--all")
+	b.Git("commit", "--quiet", "--allow-empty", "-m", msg)
+	b.date = b.date.Add(time.Hour)
+	return b
+}
+
+// Branch creates branch from the current commit and checks it out.
+func (b *RepoBuilder) Branch(branch string) *RepoBuilder {
+	b.t.Helper()
+
+	b.Git("checkout", "--quiet", "-b", branch)
+	return b
+}
+
+// Checkout switches to an existing branch.
+func (b *RepoBuilder) Checkout(branch string) *RepoBuilder {
+	b.t.Helper()
+
+	b.Git("checkout", "--quiet", branch)
+	return b
+}
+
+// Merge merges branch into the current branch with a merge commit.
+func (b *RepoBuilder) Merge(branch, msg string) *RepoBuilder {
+	b.t.Helper()
+
+	b.Git("merge", "--quiet", "--no-ff", "-m", msg, branch)
+	b.date = b.date.Add(time.Hour)
+	return b
+}
+
+// Git runs git in the repository as the current author at the current date
+// and returns its trimmed output.
+func (b *RepoBuilder) Git(args ...string) string {
+	b.t.Helper()
+
+	date := b.date.Format(time.RFC3339)
+	cmd := exec.Command("git", append([]string{"-c", "commit.gpgsign=false"}, args...)...)
+	cmd.Dir = b.dir
+	// Ignore the developer's global and system git config so hooks,
+	// templates and defaults like init.defaultBranch cannot change the
+	// fixture
+	cmd.Env = append(os.Environ(),
+		"GIT_CONFIG_GLOBAL="+os.DevNull, "GIT_CONFIG_NOSYSTEM=1",
+		"GIT_AUTHOR_NAME="+b.name, "GIT_AUTHOR_EMAIL="+b.email, "GIT_AUTHOR_DATE="+date,
+		"GIT_COMMITTER_NAME="+b.name, "GIT_COMMITTER_EMAIL="+b.email, "GIT_COMMITTER_DATE="+date)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		b.t.Fatalf("git %v failed: %v\n%s", args, err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
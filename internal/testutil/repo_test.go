@@ -0,0 +1,45 @@
+package testutil
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRepoBuilder(t *testing.T) {
+	repo := NewRepo(t).
+		WithAuthor("Alice", "alice@example.com").
+		Commit("initial commit", map[string]string{"main.js": "one\n"}).
+		Branch("feature").
+		WithAuthor("Bob", "bob@example.com").
+		Commit("add lib", map[string]string{"lib/util.js": "two\n"}).
+		Checkout("main").
+		Merge("feature", "merge feature")
+
+	log := repo.Git("log", "--pretty=format:%an|%cI|%P|%s")
+	lines := strings.Split(log, "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 commits, but got %q", log)
+	}
+
+	// Dates are an hour apart from StartDate, newest first
+	for i, line := range lines {
+		parts := strings.Split(line, "|")
+		date, err := time.Parse(time.RFC3339, parts[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := StartDate.Add(time.Duration(2-i) * time.Hour); !date.Equal(expected) {
+			t.Errorf("Expected commit %q at %v, but got %v", parts[3], expected, date)
+		}
+	}
+
+	merge := strings.Split(lines[0], "|")
+	if merge[0] != "Bob" || len(strings.Fields(merge[2])) != 2 {
+		t.Errorf("Expected a merge commit by Bob with two parents, but got %q", lines[0])
+	}
+
+	if files := repo.Git("ls-files"); files != "lib/util.js\nmain.js" {
+		t.Errorf("Expected both files on main, but got %q", files)
+	}
+}
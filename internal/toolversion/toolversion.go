@@ -0,0 +1,123 @@
+// Package toolversion tells the versions of the external tools a run uses,
+// such as git, Node.js and the linters, so reports record what produced
+// them and comparisons between runs can point out when the tools changed.
+package toolversion
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+// Unknown is the version recorded for a tool that did not tell it.
+const Unknown = "unknown"
+
+// timeout bounds how long a version command may take, so a tool that hangs
+// or waits for input does not hold up the run.
+const timeout = 10 * time.Second
+
+// maxLength bounds the length of a version taken from output without a
+// version number in it.
+const maxLength = 40
+
+var (
+	// versionPattern matches version numbers such as 2.43.0, v8.57.0 or
+	// 1.2.3-beta.1, without the v
+	versionPattern = regexp.MustCompile(`\d+(?:\.\d+)+(?:-[0-9A-Za-z.]*[0-9A-Za-z])?`)
+
+	// escapePattern matches terminal color and cursor sequences
+	escapePattern = regexp.MustCompile("\x1b\\[[0-9;?]*[A-Za-z]")
+)
+
+// Command runs name with args in dir and returns the version it prints on
+// stdout, or on stderr when stdout is empty. It returns Unknown when the
+// command cannot be run, fails, prints nothing or takes too long.
+func Command(ctx context.Context, dir, name string, args ...string) string {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// Children left holding the output open must not hold up the run
+	cmd.WaitDelay = time.Second
+	if err := cmd.Run(); err != nil {
+		return Unknown
+	}
+
+	output := stdout.String()
+	if strings.TrimSpace(output) == "" {
+		output = stderr.String()
+	}
+	if version := Parse(output); version != "" {
+		return version
+	}
+	return Unknown
+}
+
+// Parse returns the version in the output of a version command: its first
+// version number, such as 2.43.0 for "git version 2.43.0", or for output
+// without one, its first non-empty line, shortened. It returns "" for
+// output without any printable text.
+func Parse(output string) string {
+	output = escapePattern.ReplaceAllString(output, "")
+	if version := versionPattern.FindString(output); version != "" {
+		return version
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(strings.Map(func(r rune) rune {
+			if unicode.IsPrint(r) {
+				return r
+			}
+			return -1
+		}, line))
+		if line == "" {
+			continue
+		}
+		if runes := []rune(line); len(runes) > maxLength {
+			line = strings.TrimSpace(string(runes[:maxLength])) + "…"
+		}
+		return line
+	}
+	return ""
+}
+
+// Diff returns the tools a and b both recorded at different versions, by
+// tool name. Tools only one of them used are left out, as they did not
+// measure the same things.
+func Diff(a, b map[string]string) []types.ToolVersionChange {
+	var changes []types.ToolVersionChange
+	for tool, versionA := range a {
+		if versionB, ok := b[tool]; ok && versionA != versionB {
+			changes = append(changes, types.ToolVersionChange{Tool: tool, A: versionA, B: versionB})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Tool < changes[j].Tool })
+	return changes
+}
+
+// Format formats versions as "tool version" pairs sorted by tool, such as
+// "eslint 8.57.0, git 2.43.0".
+func Format(versions map[string]string) string {
+	tools := make([]string, 0, len(versions))
+	for tool := range versions {
+		tools = append(tools, tool)
+	}
+	sort.Strings(tools)
+
+	pairs := make([]string, len(tools))
+	for i, tool := range tools {
+		pairs[i] = tool + " " + versions[tool]
+	}
+	return strings.Join(pairs, ", ")
+}
@@ -0,0 +1,124 @@
+package toolversion
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		output string
+		want   string
+	}{
+		{"git version 2.43.0\n", "2.43.0"},
+		{"git version 2.39.3 (Apple Git-145)\n", "2.39.3"},
+		{"v20.11.1\n", "20.11.1"},
+		{"ruff 0.4.4\n", "0.4.4"},
+		{"\x1b[32mmytool\x1b[0m 1.2.3-beta.1\n", "1.2.3-beta.1"},
+		{"1.2.3-\n", "1.2.3"},
+		{"\r\nnpm WARN config\r\n8.57.0\r\n", "8.57.0"},
+		{"\n\n  nightly build  \n", "nightly build"},
+		{"a tool without any version number in its rather long banner\n", "a tool without any version number in its…"},
+		{"", ""},
+		{" \n\x00\t\n", ""},
+	}
+	for _, tt := range tests {
+		if got := Parse(tt.output); got != tt.want {
+			t.Errorf("Parse(%q) = %q, want %q", tt.output, got, tt.want)
+		}
+	}
+}
+
+// writeExecutable writes a shell script named name to dir.
+func writeExecutable(t *testing.T, dir, name, content string) {
+	t.Helper()
+
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake executables are shell scripts")
+	}
+
+	bin := t.TempDir()
+	writeExecutable(t, bin, "fake-git", "#!/bin/sh\necho \"git version 2.43.0\"\n")
+	writeExecutable(t, bin, "fake-stderr", "#!/bin/sh\necho \"tool 3.1\" >&2\n")
+	writeExecutable(t, bin, "fake-silent", "#!/bin/sh\n")
+	writeExecutable(t, bin, "fake-failing", "#!/bin/sh\necho \"1.0.0\"\nexit 2\n")
+	writeExecutable(t, bin, "fake-args", "#!/bin/sh\n[ \"$1\" = \"--version\" ] && echo \"v$2\"\n")
+	writeExecutable(t, bin, "fake-dir", "#!/bin/sh\ncat VERSION\n")
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "VERSION"), []byte("4.5.6\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"fake-git", []string{"--version"}, "2.43.0"},
+		{"fake-stderr", nil, "3.1"},
+		{"fake-silent", nil, Unknown},
+		{"fake-failing", nil, Unknown},
+		{"fake-args", []string{"--version", "8.57.0"}, "8.57.0"},
+		{"fake-dir", nil, "4.5.6"},
+		{"fake-missing", nil, Unknown},
+	}
+	for _, tt := range tests {
+		if got := Command(context.Background(), dir, filepath.Join(bin, tt.name), tt.args...); got != tt.want {
+			t.Errorf("Command(%s) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCommandCanceled(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake executables are shell scripts")
+	}
+
+	bin := t.TempDir()
+	writeExecutable(t, bin, "fake-hang", "#!/bin/sh\nsleep 60\n")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if got := Command(ctx, t.TempDir(), filepath.Join(bin, "fake-hang")); got != Unknown {
+		t.Errorf("Command() = %q, want %q", got, Unknown)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	a := map[string]string{"git": "2.43.0", "eslint": "8.57.0", "node": "20.11.1", "ruff": "0.4.4"}
+	b := map[string]string{"git": "2.43.0", "eslint": "9.1.0", "node": "22.1.0", "checkstyle": "10.0"}
+
+	want := []types.ToolVersionChange{
+		{Tool: "eslint", A: "8.57.0", B: "9.1.0"},
+		{Tool: "node", A: "20.11.1", B: "22.1.0"},
+	}
+	if got := Diff(a, b); !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() = %v, want %v", got, want)
+	}
+	if got := Diff(a, a); got != nil {
+		t.Errorf("Diff() of the same versions = %v, want nil", got)
+	}
+	if got := Diff(nil, b); got != nil {
+		t.Errorf("Diff() without versions = %v, want nil", got)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	got := Format(map[string]string{"ruff": "0.4.4", "git": "2.43.0", "eslint": Unknown})
+	if want := "eslint unknown, git 2.43.0, ruff 0.4.4"; got != want {
+		t.Errorf("Format() = %q, want %q", got, want)
+	}
+}
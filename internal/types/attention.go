@@ -0,0 +1,41 @@
+package types
+
+import "time"
+
+// AttentionSchemaVersion is the version of the AttentionList layout written
+// by --attention-out. Editor extensions and bots read it on its own, so it is
+// bumped whenever the layout changes, independently of ReportSchemaVersion.
+const AttentionSchemaVersion = 1
+
+// AttentionList is the files of a run that need attention, for tools that
+// highlight them without reading the full Report. It is built from the
+// quality score of the run.
+type AttentionList struct {
+	SchemaVersion       int       `json:"schema_version"`
+	ReportSchemaVersion int       `json:"report_schema_version"` // Of the report the list was built from
+	GeneratedAt         time.Time `json:"generated_at"`
+	Repo                string    `json:"repo"`
+
+	Thresholds AttentionThresholds `json:"thresholds"`
+
+	// Files are worst first, and never nil so the list is always an array.
+	Files []AttentionFile `json:"files"`
+}
+
+// AttentionThresholds are the scores, from 0 to 100, below which a file
+// needs attention. Zero turns a threshold off.
+type AttentionThresholds struct {
+	Score     float64 `json:"score"`     // Of the composite score
+	Component float64 `json:"component"` // Of any one component
+}
+
+// AttentionFile is a file that needs attention. Components has the 0-100
+// score of each component measured for it, out of issues, coverage, debt,
+// churn and complexity; Reasons say in words which thresholds it fell
+// below.
+type AttentionFile struct {
+	Path       string             `json:"path"`
+	Score      float64            `json:"score"`
+	Components map[string]float64 `json:"components"`
+	Reasons    []string           `json:"reasons"`
+}
@@ -1,7 +1,9 @@
 package types
 
+import "time"
+
 type BlameInfo struct {
 	Email string
 	Name  string
+	Time  time.Time // Author time of the commit that last changed the line, zero for uncommitted lines
 }
-
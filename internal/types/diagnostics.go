@@ -0,0 +1,41 @@
+package types
+
+import "time"
+
+// DiagnosticsSchemaVersion is the version of the Diagnostics layout written
+// by --warnings-out. Pipelines read it on its own, so it is bumped whenever
+// the layout changes, independently of ReportSchemaVersion.
+const DiagnosticsSchemaVersion = 1
+
+// Diagnostics is the warnings of a run as records pipelines can act on,
+// such as annotating each file git blame failed for, without parsing the
+// lines printed after the leaderboards.
+type Diagnostics struct {
+	SchemaVersion       int       `json:"schema_version"`
+	ReportSchemaVersion int       `json:"report_schema_version"` // Of the report the diagnostics were built from
+	GeneratedAt         time.Time `json:"generated_at"`
+	Repo                string    `json:"repo"`
+
+	// Groups are the most frequent type first, and Warnings in the order
+	// they were logged. Neither is ever nil, so both are always arrays.
+	Groups   []DiagnosticGroup `json:"groups"`
+	Warnings []Diagnostic      `json:"warnings"`
+}
+
+// Diagnostic is one warning of a run. Type is the message the warning was
+// logged with, shared by every warning of its kind, such as "Blame failed";
+// Message has its params too.
+type Diagnostic struct {
+	Type    string         `json:"type"`
+	File    string         `json:"file,omitempty"`
+	Message string         `json:"message"`
+	Params  []WarningParam `json:"params,omitempty"`
+}
+
+// DiagnosticGroup counts the warnings of one type, such as how many files
+// git blame failed for, and lists every file they name.
+type DiagnosticGroup struct {
+	Type  string   `json:"type"`
+	Count int      `json:"count"`
+	Files []string `json:"files,omitempty"`
+}
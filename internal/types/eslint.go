@@ -1,11 +1,13 @@
 package types
 
 type ESLintMessage struct {
-	RuleID   string `json:"ruleId"`
-	Severity int    `json:"severity"`
-	Message  string `json:"message"`
-	Line     int    `json:"line"`
-	Column   int    `json:"column"`
+	RuleID   string    `json:"ruleId"`
+	Severity int       `json:"severity"`
+	Message  string    `json:"message"`
+	Line     int       `json:"line"`
+	Column   int       `json:"column"`
+	Fatal    bool      `json:"fatal"`
+	Fix      *struct{} `json:"fix"` // set when eslint --fix can fix the issue
 }
 
 type ESLintResult struct {
@@ -13,11 +15,18 @@ type ESLintResult struct {
 	Messages []ESLintMessage `json:"messages"`
 }
 
+// Issue severities. ESLint uses the same numbers.
+const (
+	SeverityOff     = 0
+	SeverityWarning = 1
+	SeverityError   = 2
+)
+
 type Issue struct {
 	FilePath string
 	Line     int
 	RuleID   string
 	Message  string
 	Severity int
+	Fixable  bool // the linter can fix the issue automatically
 }
-
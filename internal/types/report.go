@@ -0,0 +1,218 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ReportSchemaVersion is the version of the Report JSON schema.
+//
+// Bump it whenever a field of Report, or of any type reachable from it, is
+// added, removed, renamed, retyped or given a different json tag, and record
+// the new layout with `go test ./internal/types -update`. Readers use the
+// version to tell which fields they can rely on, so a change that keeps the
+// old version would silently break them. TestReportSchemaVersion fails when
+// the layout no longer matches the golden file of the current version.
+const ReportSchemaVersion = 46
+
+// Report is the canonical result of one analysis run. Every exporter reads
+// from it, and it round-trips through JSON unchanged.
+type Report struct {
+	SchemaVersion int       `json:"schema_version"`
+	GeneratedAt   time.Time `json:"generated_at"`
+	Repo          RepoInfo  `json:"repo"`
+
+	// Leaderboards lists the leaderboards that were requested. Leaderboards
+	// the report card is graded from may be populated without being listed.
+	Leaderboards []string `json:"leaderboards"`
+
+	Authors           []LeaderboardEntry                `json:"authors,omitempty"`
+	Files             []FileLeaderboardEntry            `json:"files,omitempty"`
+	Rules             []RuleLeaderboardEntry            `json:"rules,omitempty"`
+	RulePlugins       []RulePluginEntry                 `json:"rule_plugins,omitempty"`
+	RuleGroups        []RuleGroupEntry                  `json:"rule_groups,omitempty"`
+	RuleAuthors       []RuleAuthorCell                  `json:"rule_authors,omitempty"`
+	RuffRules         []RuleLeaderboardEntry            `json:"ruff_rules,omitempty"`
+	LinesOfCode       []LinesOfCodeEntry                `json:"lines_of_code,omitempty"`
+	Commits           []CommitCountEntry                `json:"commits,omitempty"`
+	Recent            []RecentContributorEntry          `json:"recent,omitempty"`
+	Coverage          []CoverageEntry                   `json:"coverage,omitempty"`
+	OverallCoverage   float64                           `json:"overall_coverage"`
+	CoverageFreshness *CoverageFreshness                `json:"coverage_freshness,omitempty"`
+	Churn             []ChurnEntry                      `json:"churn,omitempty"`
+	Hotspots          []HotspotEntry                    `json:"hotspots,omitempty"`
+	BugDensity        []BugDensityEntry                 `json:"bug_density,omitempty"`
+	TechnicalDebt     []TechnicalDebtEntry              `json:"technical_debt,omitempty"`
+	SpellCheck        []SpellCheckEntry                 `json:"spell_check,omitempty"`
+	SpellCheckAuthors map[string]*SpellCheckAuthorStats `json:"spell_check_authors,omitempty"`
+	Encoding          []EncodingEntry                   `json:"encoding,omitempty"`
+	LongFunctions     []LongFunctionEntry               `json:"long_functions,omitempty"`
+	FormatDrift       *FormatDriftStats                 `json:"format_drift,omitempty"`
+	Forge             *ForgeStats                       `json:"forge,omitempty"`
+	LeadTime          *LeadTimeStats                    `json:"lead_time,omitempty"`
+	Changelog         *ChangelogStats                   `json:"changelog,omitempty"`
+	Timezones         *TimezoneStats                    `json:"timezones,omitempty"`
+	Deletions         *DeletionStats                    `json:"deletions,omitempty"`
+	NewContributors   *NewContributorStats              `json:"new_contributors,omitempty"`
+	Workspaces        []WorkspaceEntry                  `json:"workspaces,omitempty"`
+	Vulnerabilities   []VulnEntry                       `json:"vulnerabilities,omitempty"`
+	Dependencies      []DependencyEntry                 `json:"dependencies,omitempty"`
+	LFS               *LFSStats                         `json:"lfs,omitempty"`
+	Summary           *SummaryStats                     `json:"summary,omitempty"`
+	ReportCard        *ReportCard                       `json:"report_card,omitempty"`
+	Score             *ScoreStats                       `json:"score,omitempty"`
+	IssueBaseline     *IssueBaseline                    `json:"issue_baseline,omitempty"`
+	Suppression       *Suppression                      `json:"suppression,omitempty"`
+
+	// NewContributorIssues counts the issues left off the author
+	// leaderboard because their authors added them within the grace period
+	// after their first commit.
+	NewContributorIssues int `json:"new_contributor_issues,omitempty"`
+
+	// Failures maps leaderboards that failed to generate to the error.
+	Failures map[string]string `json:"failures,omitempty"`
+
+	// Repos summarizes each repository of a report of several
+	// repositories, in the order they were listed, and RepoReports holds
+	// their reports. RepoFailures maps the repositories that could not be
+	// analyzed to the error. All three are empty for a single repository.
+	Repos        []RepoEntry       `json:"repos,omitempty"`
+	RepoReports  []Report          `json:"repo_reports,omitempty"`
+	RepoFailures map[string]string `json:"repo_failures,omitempty"`
+
+	// LintSources has the outcome of each lint source that ran, in run
+	// order.
+	LintSources []LintSourceResult `json:"lint_sources,omitempty"`
+
+	// ToolVersions maps the external tools the run used, such as git, node,
+	// eslint and ruff, to their version, or to "unknown" when a tool did not
+	// tell it.
+	ToolVersions map[string]string `json:"tool_versions,omitempty"`
+
+	// Warnings are non-fatal problems logged during the run, such as files
+	// git blame could not attribute.
+	Warnings []Warning `json:"warnings,omitempty"`
+
+	// Timings records how long each phase took, in nanoseconds.
+	Timings map[string]time.Duration `json:"timings,omitempty"`
+}
+
+// RepoInfo describes the repository a report was generated for.
+type RepoInfo struct {
+	Path          string `json:"path"`
+	TrackedFiles  int    `json:"tracked_files"`
+	AnalyzedFiles int    `json:"analyzed_files"`
+
+	// NoCommits is set for a repository without commits. Its files are
+	// the ones in the work tree, and leaderboards that need git history
+	// fail with cerrors.ErrNoCommits.
+	NoCommits bool `json:"no_commits,omitempty"`
+
+	// Head is the commit HEAD pointed at, empty without commits. Runs in
+	// the history can be told by it.
+	Head string `json:"head,omitempty"`
+
+	// UntrackedFiles counts the untracked, not ignored files added to the
+	// file-based leaderboards with --include-untracked.
+	UntrackedFiles int `json:"untracked_files,omitempty"`
+
+	// DirtyFiles counts the analyzed files with uncommitted changes. Issues
+	// on their changed lines are blamed on the uncommitted author.
+	DirtyFiles int `json:"dirty_files,omitempty"`
+
+	// VendoredFiles and GeneratedFiles count the files left out of the
+	// leaderboards that read file contents, unless --include-vendored is set.
+	VendoredFiles  int `json:"vendored_files,omitempty"`
+	GeneratedFiles int `json:"generated_files,omitempty"`
+
+	// OversizedFiles counts, per requested leaderboard, the files left out
+	// for being larger than max-file-size, or spellcheck-max-file-size for
+	// the spell check.
+	OversizedFiles map[string]int `json:"oversized_files,omitempty"`
+
+	// ExcludedFiles counts, per requested leaderboard, the files left out
+	// by its own ignore paths, such as debt-ignore-paths.
+	ExcludedFiles map[string]int `json:"excluded_files,omitempty"`
+
+	// Languages is the make-up of the analyzed files by language, whatever
+	// --lang restricted the analysis to.
+	Languages []LanguageEntry `json:"languages,omitempty"`
+
+	// ChangedSince is the revision, such as the latest release tag, the
+	// analysis was restricted to the changes since, and ChangedFiles counts
+	// the tracked files changed since it, before any filtering.
+	ChangedSince string `json:"changed_since,omitempty"`
+	ChangedFiles int    `json:"changed_files,omitempty"`
+}
+
+// LanguageEntry is the share of one language in the files of a repository.
+type LanguageEntry struct {
+	Name    string  `json:"name"` // "Other" for files of no known language
+	Files   int     `json:"files"`
+	Lines   int     `json:"lines"`
+	Percent float64 `json:"percent"` // Of the lines of every language
+}
+
+// LintSourceResult is the outcome of running one lint source, with the error
+// it failed with, if any.
+type LintSourceResult struct {
+	Name   string `json:"name"`
+	Issues int    `json:"issues"`
+	Error  string `json:"error,omitempty"`
+}
+
+// NewReport returns an empty report for the repository at path, stamped with
+// the current schema version and time.
+func NewReport(path string) Report {
+	return Report{
+		SchemaVersion: ReportSchemaVersion,
+		GeneratedAt:   time.Now().UTC(),
+		Repo:          RepoInfo{Path: path},
+		Timings:       make(map[string]time.Duration),
+	}
+}
+
+// Requested reports whether the named leaderboard was requested.
+func (r *Report) Requested(leaderboard string) bool {
+	for _, name := range r.Leaderboards {
+		if name == leaderboard {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks that a report, typically one read back from JSON, is
+// complete and internally consistent.
+func (r *Report) Validate() error {
+	if r.SchemaVersion < 1 || r.SchemaVersion > ReportSchemaVersion {
+		return fmt.Errorf("unsupported report schema version %d (supported: 1-%d)", r.SchemaVersion, ReportSchemaVersion)
+	}
+	if r.GeneratedAt.IsZero() {
+		return errors.New("report is missing generated_at")
+	}
+	if r.Repo.Path == "" {
+		return errors.New("report is missing the repository path")
+	}
+	if r.Repo.AnalyzedFiles > r.Repo.TrackedFiles {
+		return fmt.Errorf("report analyzed %d files but only %d are tracked", r.Repo.AnalyzedFiles, r.Repo.TrackedFiles)
+	}
+	if r.OverallCoverage < 0 || r.OverallCoverage > 100 {
+		return fmt.Errorf("overall coverage %.2f is out of range", r.OverallCoverage)
+	}
+	for _, entry := range r.Coverage {
+		if entry.CoveragePercent < 0 || entry.CoveragePercent > 100 {
+			return fmt.Errorf("coverage %.2f for %s is out of range", entry.CoveragePercent, entry.Path)
+		}
+	}
+	if r.ReportCard != nil && r.ReportCard.Grade == "" {
+		return errors.New("report card is missing its grade")
+	}
+	for i := range r.RepoReports {
+		if err := r.RepoReports[i].Validate(); err != nil {
+			return fmt.Errorf("report of %s: %w", r.RepoReports[i].Repo.Path, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package types
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "record the report schema of a new version in testdata")
+
+func sampleReport() Report {
+	at := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	return Report{
+		SchemaVersion: ReportSchemaVersion,
+		GeneratedAt:   at,
+		Repo:          RepoInfo{Path: "/src/app", TrackedFiles: 12, AnalyzedFiles: 10},
+		Leaderboards:  []string{"authors", "files", "coverage", "spellcheck", "summary", "report-card"},
+		Authors: []LeaderboardEntry{
+			{Name: "Alice", Email: "alice@example.com", Count: 3, TopRule: "no-console", TopCount: 2, Files: 1, Errors: 1, Warnings: 2},
+		},
+		Files: []FileLeaderboardEntry{
+			{Path: "src/app.js", Count: 3, TopRule: "no-console", TopCount: 2, Authors: 1, TopAuthors: []AuthorCount{{Email: "alice@example.com", Count: 3}}},
+		},
+		Commits: []CommitCountEntry{
+			{Name: "Alice", Email: "alice@example.com", Commits: 4, FirstCommit: at.AddDate(0, -1, 0), LastCommit: at},
+		},
+		Coverage:        []CoverageEntry{{Path: "src/app.js", LinesCovered: 8, LinesTotal: 10, CoveragePercent: 80}},
+		OverallCoverage: 80,
+		SpellCheck: []SpellCheckEntry{
+			{
+				Path: "README.md", MisspelledWords: 1, TotalWords: 10, ErrorRate: 10,
+				TopMisspellings: map[string]int{"teh": 1},
+				Issues:          []SpellIssue{{Word: "teh", Line: 2, Type: "comment", Suggestions: []string{"the"}}},
+			},
+		},
+		SpellCheckAuthors: map[string]*SpellCheckAuthorStats{
+			"alice@example.com": {Name: "Alice", Email: "alice@example.com", TotalErrors: 1, Files: map[string]int{"README.md": 1}, CommonMistakes: map[string]int{"teh": 1}},
+		},
+		Summary:    &SummaryStats{TotalIssues: 3, Errors: 1, Warnings: 2, Authors: 1, Files: 1, Rules: 2, AvgIssuesPerAuthor: 3, AvgIssuesPerFile: 3},
+		ReportCard: &ReportCard{Categories: []CategoryGrade{{Category: "coverage", Score: 80, Weight: 25, Grade: "B", Detail: "80.0% covered"}}, Score: 80, Grade: "B"},
+		Failures:   map[string]string{"churn": "failed to get churn data: exit status 128"},
+		Warnings:   []Warning{NewWarning("Skipped symlinked file", "file", "link.js")},
+		Timings:    map[string]time.Duration{"total": 1500 * time.Millisecond},
+	}
+}
+
+func TestReportRoundTrip(t *testing.T) {
+	report := sampleReport()
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatalf("Failed to marshal report: %v", err)
+	}
+
+	var decoded Report
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal report: %v", err)
+	}
+
+	if !reflect.DeepEqual(decoded, report) {
+		t.Errorf("Expected report to round-trip unchanged\ngot:  %+v\nwant: %+v", decoded, report)
+	}
+
+	if err := decoded.Validate(); err != nil {
+		t.Errorf("Expected round-tripped report to be valid, but got %v", err)
+	}
+
+	// Leaderboards that were not generated are left out of the JSON
+	if strings.Contains(string(data), `"churn":[`) || !strings.Contains(string(data), fmt.Sprintf(`"schema_version":%d`, ReportSchemaVersion)) {
+		t.Errorf("Unexpected report JSON: %s", data)
+	}
+}
+
+func TestWarningFromOlderReport(t *testing.T) {
+	var report Report
+	data := `{"warnings":["⚠️ Blame failed (file=a.js error=exit status 128)",{"message":"Diff failed","params":[{"key":"file","value":"b.js"}]}]}`
+	if err := json.Unmarshal([]byte(data), &report); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Warning{
+		{Message: "Blame failed (file=a.js error=exit status 128)"},
+		NewWarning("Diff failed", "file", "b.js"),
+	}
+	if !reflect.DeepEqual(report.Warnings, expected) {
+		t.Errorf("Expected warnings %+v, but got %+v", expected, report.Warnings)
+	}
+	if got := report.Warnings[0].String(); got != "⚠️ Blame failed (file=a.js error=exit status 128)" {
+		t.Errorf("Expected an old warning to render as it was, but got %q", got)
+	}
+	if got := report.Warnings[1].Template(); got != "Diff failed (file)" {
+		t.Errorf("Expected template %q, but got %q", "Diff failed (file)", got)
+	}
+}
+
+func TestReportValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(r *Report)
+		valid  bool
+	}{
+		{"valid", func(r *Report) {}, true},
+		{"new report", func(r *Report) { *r = NewReport("/src/app") }, true},
+		{"missing version", func(r *Report) { r.SchemaVersion = 0 }, false},
+		{"future version", func(r *Report) { r.SchemaVersion = ReportSchemaVersion + 1 }, false},
+		{"missing time", func(r *Report) { r.GeneratedAt = time.Time{} }, false},
+		{"missing repo", func(r *Report) { r.Repo.Path = "" }, false},
+		{"more analyzed than tracked", func(r *Report) { r.Repo.AnalyzedFiles = 20 }, false},
+		{"overall coverage out of range", func(r *Report) { r.OverallCoverage = 101 }, false},
+		{"file coverage out of range", func(r *Report) { r.Coverage[0].CoveragePercent = -1 }, false},
+		{"ungraded report card", func(r *Report) { r.ReportCard.Grade = "" }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := sampleReport()
+			tt.modify(&report)
+
+			err := report.Validate()
+			if tt.valid && err != nil {
+				t.Errorf("Expected report to be valid, but got %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Errorf("Expected report to be invalid")
+			}
+		})
+	}
+}
+
+func TestReportRequested(t *testing.T) {
+	report := sampleReport()
+
+	if !report.Requested("files") || report.Requested("churn") {
+		t.Errorf("Expected only requested leaderboards to be reported, got %v", report.Leaderboards)
+	}
+}
+
+// TestReportSchemaVersion fails when the Report layout changes without a
+// ReportSchemaVersion bump. After bumping, -update records the new layout.
+// Existing files are never rewritten, so a change cannot be recorded under
+// a version that was already released.
+func TestReportSchemaVersion(t *testing.T) {
+	checkSchema(t, reflect.TypeOf(Report{}), "report", "ReportSchemaVersion", ReportSchemaVersion)
+}
+
+// TestAttentionSchemaVersion does the same for the AttentionList.
+func TestAttentionSchemaVersion(t *testing.T) {
+	checkSchema(t, reflect.TypeOf(AttentionList{}), "attention", "AttentionSchemaVersion", AttentionSchemaVersion)
+}
+
+// checkSchema compares the layout of typ with the golden file of version,
+// creating it with -update when it does not exist yet. constant names the
+// version to bump.
+func checkSchema(t *testing.T, typ reflect.Type, name, constant string, version int) {
+	t.Helper()
+	var b strings.Builder
+	describeSchema(&b, typ, "", map[reflect.Type]bool{})
+	schema := b.String()
+
+	golden := filepath.Join("testdata", fmt.Sprintf("%s_schema_v%d.golden", name, version))
+	expected, err := os.ReadFile(golden)
+	if os.IsNotExist(err) && *update {
+		if err := os.MkdirAll("testdata", 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(golden, []byte(schema), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("Failed to read %s (bump %s and run go test -update to create it): %v", golden, constant, err)
+	}
+
+	if schema != string(expected) {
+		t.Errorf("%s schema no longer matches version %d; bump %s and run go test -update\ngot:\n%s\nexpected:\n%s",
+			typ.Name(), version, constant, schema, expected)
+	}
+}
+
+// describeSchema writes one line per field reachable from typ, with its Go
+// type and json tag.
+func describeSchema(b *strings.Builder, typ reflect.Type, indent string, seen map[reflect.Type]bool) {
+	for typ.Kind() == reflect.Pointer || typ.Kind() == reflect.Slice || typ.Kind() == reflect.Map {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct || typ == reflect.TypeOf(time.Time{}) || seen[typ] {
+		return
+	}
+	seen[typ] = true
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		fmt.Fprintf(b, "%s%s %s `%s`\n", indent, field.Name, field.Type, field.Tag.Get("json"))
+		describeSchema(b, field.Type, indent+"  ", seen)
+	}
+}
@@ -1,11 +1,16 @@
 package types
 
-import "time"
+import (
+	"math"
+	"time"
+)
 
 type AuthorStats struct {
 	Name       string
 	Count      int
+	Decayed    float64 // Count with each issue weighted by the age of its line, when decay-halflife-days is set
 	Rules      map[string]int
+	RuleErrors map[string]int // The errors among Rules, the rest being warnings
 	Files      map[string]int
 	Errors     int
 	Warnings   int
@@ -17,10 +22,15 @@ type AuthorStats struct {
 type FileStats struct {
 	Path       string
 	Count      int
+	Decayed    float64 // As for AuthorStats
+	Errors     int
+	Warnings   int
 	Rules      map[string]int
+	RuleErrors map[string]int // As for AuthorStats
 	Authors    map[string]int
 	IssueCount int
 	Loc        int // Lines of Code
+	Overflow   int // Issues past max-issues-per-file, not attributed to anyone
 }
 
 type RuleStats struct {
@@ -28,63 +38,198 @@ type RuleStats struct {
 	ViolationCount int
 	Rule           string
 	Count          int
+	FixableCount   int
 	Authors        map[string]int
 	Files          map[string]int
 }
 
+// SummaryStats aggregates issue counts across authors, files and rules.
+type SummaryStats struct {
+	TotalIssues        int     `json:"total_issues"`
+	Errors             int     `json:"errors"`
+	Warnings           int     `json:"warnings"`
+	Authors            int     `json:"authors"`
+	Files              int     `json:"files"`
+	Rules              int     `json:"rules"`
+	UnparseableFiles   int     `json:"unparseable_files"`
+	AvgIssuesPerAuthor float64 `json:"avg_issues_per_author"`
+	AvgIssuesPerFile   float64 `json:"avg_issues_per_file"`
+
+	// WeightedIssues is TotalIssues weighted as the author and file
+	// leaderboards weight them, and zero unless --weighted is set.
+	WeightedIssues float64 `json:"weighted_issues,omitempty"`
+}
+
+// IssueBaseline splits the lint issues of a run into the new ones and those
+// that already were in a reference set of issue fingerprints, such as an
+// accepted baseline.
+type IssueBaseline struct {
+	// Reference is the fingerprint file the issues were compared with.
+	Reference string `json:"reference"`
+	New       int    `json:"new"`
+	Existing  int    `json:"existing"`
+
+	// Fixed counts the issues of the reference that are gone.
+	Fixed int `json:"fixed"`
+}
+
+// Suppression counts the findings of a run left out of the leaderboards
+// because the suppression baseline accepts them as legacy debt.
+type Suppression struct {
+	// File is the baseline the findings were matched with.
+	File         string `json:"file"`
+	Issues       int    `json:"issues"`
+	DebtMarkers  int    `json:"debt_markers"`
+	Misspellings int    `json:"misspellings"`
+
+	// Stale lists the baseline's entries that match nothing any more, so
+	// the baseline can be written again without them.
+	Stale []StaleSuppression `json:"stale,omitempty"`
+}
+
+// Total returns how many findings were suppressed.
+func (s Suppression) Total() int {
+	return s.Issues + s.DebtMarkers + s.Misspellings
+}
+
+// StaleSuppression is an entry of the suppression baseline whose finding is
+// gone, or whose file is.
+type StaleSuppression struct {
+	Kind    string `json:"kind"` // issue, debt or spelling
+	Path    string `json:"path"`
+	Rule    string `json:"rule"`
+	Deleted bool   `json:"deleted,omitempty"` // The file is gone
+}
+
 // Existing leaderboard entries
 type LeaderboardEntry struct {
-	Rank     int
-	Name     string
-	Email    string
-	Count    int
-	TopRule  string
-	TopCount int
-	Files    int
-	Errors   int
-	Warnings int
+	Rank     int    `json:"rank"`
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Count    int    `json:"count"`
+	TopRule  string `json:"top_rule"`
+	TopCount int    `json:"top_count"`
+	Files    int    `json:"files"`
+	Errors   int    `json:"errors"`
+	Warnings int    `json:"warnings"`
+
+	// DecayedScore is Count with each issue weighted down by the age of the
+	// line it is on, halving every decay-halflife-days, and zero when no
+	// half-life is set. Authors are ranked by it when it is set.
+	DecayedScore float64 `json:"decayed_score,omitempty"`
+
+	// WeightedScore is Count with each issue weighted by its severity and
+	// rule, as weight-error, weight-warning and rule-weights set, and zero
+	// unless --weighted is set. Authors are ranked by it when it is set.
+	WeightedScore float64 `json:"weighted_score,omitempty"`
+
+	// Repos lists the repositories the author has issues in, in a report
+	// of several repositories.
+	Repos []string `json:"repos,omitempty"`
 }
 
 type FileLeaderboardEntry struct {
-	Rank     int
-	Path     string
-	Count    int
-	TopRule  string
-	TopCount int
-	Authors  int
+	Rank       int           `json:"rank"`
+	Path       string        `json:"path"`
+	Count      int           `json:"count"`
+	Errors     int           `json:"errors"`
+	Warnings   int           `json:"warnings"`
+	TopRule    string        `json:"top_rule"`
+	TopCount   int           `json:"top_count"`
+	Authors    int           `json:"authors"`
+	TopAuthors []AuthorCount `json:"top_authors"`
+	Overflow   int           `json:"overflow,omitempty"` // Issues past max-issues-per-file
+
+	// DecayedScore is Count weighted by the age of each issue, as for
+	// LeaderboardEntry.
+	DecayedScore float64 `json:"decayed_score,omitempty"`
+
+	// WeightedScore is Count weighted by the severity and rule of each
+	// issue, as for LeaderboardEntry.
+	WeightedScore float64 `json:"weighted_score,omitempty"`
+
+	// Owners are who should act on the file, and OwnerSource where they
+	// came from: codeowners, blame, or none for an unowned file. Both are
+	// empty when owners were not resolved.
+	Owners      []string `json:"owners,omitempty"`
+	OwnerSource string   `json:"owner_source,omitempty"`
+}
+
+// AuthorCount is the number of issues an author is blamed for in one file.
+type AuthorCount struct {
+	Email string `json:"email"`
+	Count int    `json:"count"`
 }
 
 type RuleLeaderboardEntry struct {
-	Rank    int
-	Rule    string
-	Count   int
-	Authors int
-	Files   int
+	Rank    int    `json:"rank"`
+	Rule    string `json:"rule"`
+	Count   int    `json:"count"`
+	Authors int    `json:"authors"`
+	Files   int    `json:"files"`
+	Fixable int    `json:"fixable"`           // violations the linter can fix automatically
+	DocURL  string `json:"doc_url,omitempty"` // documentation of the rule, when known
+}
+
+// RulePluginEntry rolls the rule leaderboard up by the plugin namespace of
+// each rule, such as @typescript-eslint or react.
+type RulePluginEntry struct {
+	Rank          int    `json:"rank"`
+	Plugin        string `json:"plugin"` // "core" for rules without a namespace
+	Count         int    `json:"count"`
+	DistinctRules int    `json:"distinct_rules"`
+}
+
+// RuleGroupEntry rolls the rule leaderboard up by the rule groups of the
+// config.
+type RuleGroupEntry struct {
+	Rank          int    `json:"rank"`
+	Group         string `json:"group"` // "ungrouped" for rules in no group
+	Count         int    `json:"count"`
+	DistinctRules int    `json:"distinct_rules"`
+}
+
+// RuleAuthorCell is a cell of the rule by author cross-tab: the violations
+// of one rule blamed on one author. Only cells with violations are kept.
+type RuleAuthorCell struct {
+	Rule  string `json:"rule"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Count int    `json:"count"`
 }
 
 // New leaderboard entries
 type LinesOfCodeEntry struct {
-	Rank  int
-	Path  string
-	Lines int
-	Size  int64 // File size in bytes
+	Rank  int    `json:"rank"`
+	Path  string `json:"path"`
+	Lines int    `json:"lines"`
+	Size  int64  `json:"size"` // File size in bytes
+
+	// Untracked is set for files not yet added to git
+	Untracked bool `json:"untracked,omitempty"`
 }
 
 type CommitCountEntry struct {
-	Rank        int
-	Name        string
-	Email       string
-	Commits     int
-	FirstCommit time.Time
-	LastCommit  time.Time
+	Rank        int       `json:"rank"`
+	Name        string    `json:"name"`
+	Email       string    `json:"email"`
+	Commits     int       `json:"commits"`
+	FirstCommit time.Time `json:"first_commit"`
+	LastCommit  time.Time `json:"last_commit"`
+
+	// CoAuthored counts the commits the author is credited with as a
+	// co-author rather than the author. Credit is the author's share of
+	// their commits when co-authors split the credit, and zero otherwise.
+	CoAuthored int     `json:"co_authored,omitempty"`
+	Credit     float64 `json:"credit,omitempty"`
 }
 
 type RecentContributorEntry struct {
-	Rank          int
-	Name          string
-	Email         string
-	LastCommit    time.Time
-	RecentCommits int // Commits in last 30 days
+	Rank          int       `json:"rank"`
+	Name          string    `json:"name"`
+	Email         string    `json:"email"`
+	LastCommit    time.Time `json:"last_commit"`
+	RecentCommits int       `json:"recent_commits"` // Commits in last 30 days
 }
 
 // Git commit info
@@ -93,46 +238,172 @@ type CommitInfo struct {
 	Author  string
 	Email   string
 	Date    time.Time
-	Message string
+	Message string // Subject line
+	Body    string // Rest of the message, without the blank line after the subject
+	Merge   bool   // Set for commits with more than one parent
+
+	Files []FileLineChange // Only set by git.GetCommitLineChanges
+}
+
+// FileLineChange is the lines a commit added to and deleted from a file.
+// Binary files have no line counts.
+type FileLineChange struct {
+	Path    string
+	OldPath string // Set when the commit renamed the file
+	Added   int
+	Deleted int
+	Binary  bool
+}
+
+// MergeInfo is a merge commit on the mainline and the branch it merged.
+type MergeInfo struct {
+	Hash          string
+	MergedAt      time.Time // Commit date of the merge
+	FirstCommitAt time.Time // Author date of the oldest commit on the branch
+	Author        string    // Author of the oldest commit on the branch
+	Email         string
+	Commits       int // Commits on the branch
 }
 
 type ChurnEntry struct {
-	Rank         int
-	Path         string
-	Changes      int
-	AddedLines   int
-	DeletedLines int
-	NetLines     int
+	Rank         int       `json:"rank"`
+	Path         string    `json:"path"`
+	Changes      int       `json:"changes"`
+	AddedLines   int       `json:"added_lines"`
+	DeletedLines int       `json:"deleted_lines"`
+	NetLines     int       `json:"net_lines"`
+	FirstCommit  time.Time `json:"first_commit"` // When the file was added, following renames
+	ChurnRate    float64   `json:"churn_rate"`   // Changes per month since FirstCommit
+}
+
+// HotspotEntry is a file that is both changed often and has lint issues,
+// scored by its changes times its issues.
+type HotspotEntry struct {
+	Rank         int    `json:"rank"`
+	Path         string `json:"path"`
+	Changes      int    `json:"changes"` // As for ChurnEntry
+	Issues       int    `json:"issues"`  // Past max-issues-per-file included
+	HotspotScore int    `json:"hotspot_score"`
 }
 
 type BugDensityEntry struct {
-	Rank         int
-	Path         string
-	BugFixes     int
-	TotalCommits int
-	BugRatio     float64
+	Rank         int     `json:"rank"`
+	Path         string  `json:"path"`
+	BugFixes     int     `json:"bug_fixes"`
+	TotalCommits int     `json:"total_commits"`
+	BugRatio     float64 `json:"bug_ratio"`
 }
 
 type TechnicalDebtEntry struct {
-	Rank       int
-	Path       string
-	TodoCount  int
-	FixmeCount int
-	HackCount  int
-	TotalDebt  int
+	Rank       int    `json:"rank"`
+	Path       string `json:"path"`
+	TodoCount  int    `json:"todo_count"`
+	FixmeCount int    `json:"fixme_count"`
+	HackCount  int    `json:"hack_count"`
+	TotalDebt  int    `json:"total_debt"`
+	Untracked  bool   `json:"untracked,omitempty"`
+}
+
+// WorkspaceEntry rolls the file-based leaderboards up to one package of a
+// monorepo. Files outside every package are rolled up under the path ".".
+type WorkspaceEntry struct {
+	Name         string `json:"name"`
+	Path         string `json:"path"`
+	Kind         string `json:"kind,omitempty"` // npm, pnpm or go
+	Files        int    `json:"files"`
+	LinesOfCode  int    `json:"lines_of_code"`
+	Issues       int    `json:"issues"`
+	Debt         int    `json:"debt"`
+	LinesCovered int    `json:"lines_covered"`
+	LinesTotal   int    `json:"lines_total"` // Lines the coverage report instruments
+}
+
+// RepoEntry summarizes one repository of a report of several repositories.
+type RepoEntry struct {
+	Repo          string  `json:"repo"` // the path or clone URL as listed
+	Files         int     `json:"files"`
+	LinesOfCode   int     `json:"lines_of_code"`
+	Issues        int     `json:"issues"`
+	IssuesPerKLOC float64 `json:"issues_per_kloc"`
+	Debt          int     `json:"debt"`
+	Authors       int     `json:"authors"`
+	LinesCovered  int     `json:"lines_covered"`
+	LinesTotal    int     `json:"lines_total"` // Lines the coverage report instruments
+}
+
+// EncodingEntry describes a file with line ending or encoding problems.
+type EncodingEntry struct {
+	Rank       int    `json:"rank"`
+	Path       string `json:"path"`
+	LineEnding string `json:"line_ending"` // "LF", "CRLF", "mixed" or "none"
+	HasBOM     bool   `json:"has_bom"`
+	Encoding   string `json:"encoding"` // "UTF-8", "UTF-16LE", "UTF-16BE" or "non-UTF-8"
+}
+
+// LongFunctionEntry describes a function whose body runs past the
+// long-function-lines threshold.
+type LongFunctionEntry struct {
+	Rank         int    `json:"rank"`
+	Path         string `json:"path"`
+	FunctionName string `json:"function_name"`
+	StartLine    int    `json:"start_line"`
+	Lines        int    `json:"lines"` // From the declaration to the closing brace
+}
+
+// FormatDriftStats lists the Python files ruff format and import sorting
+// would rewrite, and the authors of the lines they would change.
+type FormatDriftStats struct {
+	Files   []FormatDriftEntry       `json:"files"`   // Most changed lines first
+	Authors []FormatDriftAuthorEntry `json:"authors"` // Most changed lines first
+}
+
+// FormatDriftEntry is a Python file ruff would rewrite. Lines is the sum of
+// the other two, which can overlap.
+type FormatDriftEntry struct {
+	Rank        int    `json:"rank"`
+	Path        string `json:"path"`
+	Lines       int    `json:"lines"`
+	FormatLines int    `json:"format_lines"` // Changed by ruff format
+	ImportLines int    `json:"import_lines"` // Changed by sorting imports
+}
+
+// FormatDriftAuthorEntry counts the lines ruff would change that an author
+// last committed, and the files they are in.
+type FormatDriftAuthorEntry struct {
+	Rank  int    `json:"rank"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Lines int    `json:"lines"`
+	Files int    `json:"files"`
 }
 
 // coverage types
 type CoverageEntry struct {
-	Rank             int
-	Path             string
-	LinesCovered     int
-	LinesTotal       int
-	CoveragePercent  float64
-	FunctionsCovered int
-	FunctionsTotal   int
-	BranchesCovered  int
-	BranchesTotal    int
+	Rank             int     `json:"rank"`
+	Path             string  `json:"path"`
+	LinesCovered     int     `json:"lines_covered"`
+	LinesTotal       int     `json:"lines_total"`
+	CoveragePercent  float64 `json:"coverage_percent"`
+	FunctionsCovered int     `json:"functions_covered"`
+	FunctionsTotal   int     `json:"functions_total"`
+	BranchesCovered  int     `json:"branches_covered"`
+	BranchesTotal    int     `json:"branches_total"`
+}
+
+// CoverageFreshness compares the coverage report read with the code it
+// covers, since a report left over from an older run is silently
+// misleading.
+type CoverageFreshness struct {
+	Report       string    `json:"report"`        // Path of the report, relative to the repository when inside it
+	ModifiedAt   time.Time `json:"modified_at"`   // Of the report
+	LastCommit   time.Time `json:"last_commit"`   // Commit date of HEAD
+	NewestSource time.Time `json:"newest_source"` // Newest modification time of the covered files
+
+	// StaleDays is how many days the report is older than the later of
+	// LastCommit and NewestSource, 0 when it is newer than both.
+	StaleDays float64 `json:"stale_days"`
+
+	MissingFiles int `json:"missing_files"` // Covered files that no longer exist
 }
 
 type CoverageData struct {
@@ -151,31 +422,405 @@ type FileCoverage struct {
 
 // Spell check types
 type SpellCheckAuthorStats struct {
-	Name           string
-	Email          string
-	TotalErrors    int
-	Files          map[string]int // filename -> error count
-	CommonMistakes map[string]int // word -> count
+	Name           string         `json:"name"`
+	Email          string         `json:"email"`
+	TotalErrors    int            `json:"total_errors"`
+	TotalWords     int            `json:"total_words"`     // checked words on lines blamed on the author
+	Files          map[string]int `json:"files"`           // filename -> error count
+	CommonMistakes map[string]int `json:"common_mistakes"` // word -> count
 }
 
 type SpellIssue struct {
-	Word        string
-	Line        int
-	Column      int
-	Context     string
-	Type        string // "comment", "string", "identifier"
-	Suggestions []string
-	Author      string
-	AuthorEmail string
+	Word        string   `json:"word"`
+	Line        int      `json:"line"`
+	Column      int      `json:"column"`
+	Context     string   `json:"context"`
+	Type        string   `json:"type"` // "comment", "string", "identifier"
+	Suggestions []string `json:"suggestions"`
+	Author      string   `json:"author"`
+	AuthorEmail string   `json:"author_email"`
 }
 
 // Update existing SpellCheckEntry if needed
 type SpellCheckEntry struct {
-	Rank            int
-	Path            string
+	Rank            int            `json:"rank"`
+	Path            string         `json:"path"`
+	MisspelledWords int            `json:"misspelled_words"`
+	TotalWords      int            `json:"total_words"`
+	ErrorRate       float64        `json:"error_rate"`
+	TopMisspellings map[string]int `json:"top_misspellings"`
+	Issues          []SpellIssue   `json:"issues"`
+	Untracked       bool           `json:"untracked,omitempty"`
+}
+
+// ReportCardInput holds the repository measurements a report card is graded
+// on. Has* fields are false when the data was not collected, in which case
+// that category is left out of the overall grade.
+type ReportCardInput struct {
+	AnalyzedFiles int
+
+	HasCoverage     bool
+	CoveragePercent float64
+
+	HasIssues bool
+	Issues    int
+
+	HasDebt   bool
+	DebtItems int
+
+	HasSpelling     bool
 	MisspelledWords int
 	TotalWords      int
-	ErrorRate       float64
-	TopMisspellings map[string]int
-	Issues          []SpellIssue
+
+	HasBusFactor bool
+	BusFactor    int
+}
+
+type CategoryGrade struct {
+	Category string  `json:"category"`
+	Score    float64 `json:"score"` // 0-100
+	Weight   float64 `json:"weight"`
+	Grade    string  `json:"grade"`
+	Detail   string  `json:"detail"`
+}
+
+type ReportCard struct {
+	Categories []CategoryGrade `json:"categories"`
+	Score      float64         `json:"score"`
+	Grade      string          `json:"grade"`
+}
+
+// ForgeStats holds pull request statistics fetched from a code forge such as
+// GitHub, for pull requests merged at or after Since.
+type ForgeStats struct {
+	Forge        string                   `json:"forge"`
+	Repo         string                   `json:"repo"`
+	Since        time.Time                `json:"since"`
+	PullRequests int                      `json:"pull_requests"`
+	Authors      []PullRequestAuthorEntry `json:"authors"`
+	Reviewers    []ReviewerEntry          `json:"reviewers"`
+}
+
+type PullRequestAuthorEntry struct {
+	Rank           int           `json:"rank"`
+	Login          string        `json:"login"`
+	Merged         int           `json:"merged"`
+	AvgSize        float64       `json:"avg_size"`          // Lines added plus deleted
+	AvgTimeToMerge time.Duration `json:"avg_time_to_merge"` // Nanoseconds from opening to merge
+}
+
+type ReviewerEntry struct {
+	Rank         int    `json:"rank"`
+	Login        string `json:"login"`
+	Reviews      int    `json:"reviews"`
+	Approvals    int    `json:"approvals"`
+	PullRequests int    `json:"pull_requests"` // Distinct pull requests reviewed
+}
+
+// LeadTimeStats holds delivery metrics derived from the merge commits on the
+// mainline made at or after Since. Lead time runs from the first commit on a
+// merged branch to the merge.
+type LeadTimeStats struct {
+	Since         time.Time             `json:"since"`
+	Merges        int                   `json:"merges"`
+	Median        time.Duration         `json:"median"` // Nanoseconds
+	P75           time.Duration         `json:"p75"`
+	P90           time.Duration         `json:"p90"`
+	MergesPerWeek float64               `json:"merges_per_week"`
+	Weeks         []WeeklyMerges        `json:"weeks"`
+	Authors       []LeadTimeAuthorEntry `json:"authors"`
+}
+
+type LeadTimeAuthorEntry struct {
+	Rank   int           `json:"rank"`
+	Name   string        `json:"name"`
+	Email  string        `json:"email"`
+	Merges int           `json:"merges"`
+	Median time.Duration `json:"median"`
+	P90    time.Duration `json:"p90"`
+}
+
+// MetricDelta is the change of one metric between two analyzed refs, or
+// two runs.
+type MetricDelta struct {
+	Metric         string  `json:"metric"` // coverage, issues, errors, warnings, debt or loc
+	A              float64 `json:"a"`
+	B              float64 `json:"b"`
+	Delta          float64 `json:"delta"` // B minus A
+	HigherIsBetter bool    `json:"higher_is_better"`
+
+	// Missing is set when the metric could not be measured at either ref,
+	// such as coverage without a coverage report
+	Missing bool `json:"missing,omitempty"`
+}
+
+// RunTrend is the change of the totals of a run since an earlier logged
+// run.
+type RunTrend struct {
+	Since  time.Time     `json:"since"` // When the earlier run was generated
+	Deltas []MetricDelta `json:"deltas"`
+}
+
+// RunHistory is what the runs logged before a report recorded, oldest
+// first, to chart its trends: the totals of each run by metric, as in
+// RunTrend, the violations of each rule and the issues of each file. Every
+// series has a value for each of Times, NaN where the run did not record
+// one.
+type RunHistory struct {
+	Times  []time.Time
+	Totals map[string][]float64
+	Rules  map[string][]float64
+	Files  map[string][]float64
+}
+
+// SnapshotDiff is the change of one leaderboard between two logged runs,
+// entry by entry. Worsened and Improved hold the entries both runs logged
+// whose value changed, Appeared those only the later run logged and
+// Disappeared those only the earlier one did, each by the size of the
+// change, largest first.
+type SnapshotDiff struct {
+	Leaderboard    string           `json:"leaderboard"` // Such as files or debt
+	Metric         string           `json:"metric"`      // The column compared, such as Issues
+	HigherIsBetter bool             `json:"higher_is_better"`
+	Worsened       []SnapshotChange `json:"worsened"`
+	Improved       []SnapshotChange `json:"improved"`
+	Appeared       []SnapshotChange `json:"appeared"`
+	Disappeared    []SnapshotChange `json:"disappeared"`
+}
+
+// SnapshotChange is the change of one leaderboard entry between two runs.
+// A is 0 for an entry that appeared, and B for one that disappeared.
+type SnapshotChange struct {
+	Key   string  `json:"key"` // Such as a path, an email or a rule
+	A     float64 `json:"a"`
+	B     float64 `json:"b"`
+	Delta float64 `json:"delta"` // B minus A
+}
+
+// Percent returns Delta as a percentage of A. It reports false when A is
+// 0, as for an entry that appeared.
+func (c SnapshotChange) Percent() (float64, bool) {
+	if c.A == 0 {
+		return 0, false
+	}
+	return c.Delta / math.Abs(c.A) * 100, true
+}
+
+// ToolVersionChange is a tool two runs both used at different versions, so
+// the change of their metrics may come from the tool rather than the code.
+type ToolVersionChange struct {
+	Tool string `json:"tool"`
+	A    string `json:"a"`
+	B    string `json:"b"`
+}
+
+// ChangelogStats describes how well the commit messages made at or after
+// Since would feed a changelog generated from Conventional Commits. Merge
+// commits are left out, as changelog generators skip them.
+type ChangelogStats struct {
+	Since               time.Time              `json:"since"`
+	Commits             int                    `json:"commits"`
+	Types               map[string]int         `json:"types"`                // Commits by changelog type: feat, fix, chore, docs, refactor or other
+	ConventionalPercent float64                `json:"conventional_percent"` // Share of commits not of type other
+	Authors             []ChangelogAuthorEntry `json:"authors"`
+	Invisible           []ChangelogCommit      `json:"invisible"` // Commits of type other, newest first
+	Breaking            []ChangelogCommit      `json:"breaking"`  // Commits marked as breaking changes, newest first
+}
+
+type ChangelogAuthorEntry struct {
+	Rank                int            `json:"rank"`
+	Name                string         `json:"name"`
+	Email               string         `json:"email"`
+	Commits             int            `json:"commits"`
+	Types               map[string]int `json:"types"`
+	ConventionalPercent float64        `json:"conventional_percent"`
+}
+
+// ChangelogCommit is a commit listed by the changelog readiness report.
+type ChangelogCommit struct {
+	Hash    string    `json:"hash"`
+	Name    string    `json:"name"`
+	Email   string    `json:"email"`
+	Date    time.Time `json:"date"`
+	Subject string    `json:"subject"`
+	Type    string    `json:"type,omitempty"` // Conventional type as written, empty for type other
+	Scope   string    `json:"scope,omitempty"`
+	Note    string    `json:"note,omitempty"` // BREAKING CHANGE footer, or the description for a ! marker
+}
+
+// DeletionStats credits the lines the non-merge commits made at or after
+// Since removed. Files that were only renamed, vendored files and generated
+// files are not counted, so moving or regenerating code earns no credit.
+type DeletionStats struct {
+	Since        time.Time             `json:"since"`
+	Commits      int                   `json:"commits"` // Commits that changed lines of counted files
+	AddedLines   int                   `json:"added_lines"`
+	DeletedLines int                   `json:"deleted_lines"`
+	Authors      []DeletionAuthorEntry `json:"authors"`  // Authors who removed more lines than they added, most removed first
+	Cleanups     []CleanupCommit       `json:"cleanups"` // Commits that removed more lines than they added, most removed first
+}
+
+type DeletionAuthorEntry struct {
+	Rank         int    `json:"rank"`
+	Name         string `json:"name"`
+	Email        string `json:"email"`
+	Commits      int    `json:"commits"`
+	AddedLines   int    `json:"added_lines"`
+	DeletedLines int    `json:"deleted_lines"`
+	NetRemoved   int    `json:"net_removed"` // DeletedLines less AddedLines
+}
+
+// NewContributorStats is the authors whose first commit falls in the window
+// of a run.
+type NewContributorStats struct {
+	Since           time.Time             `json:"since"`
+	GracePeriodDays int                   `json:"grace_period_days,omitempty"` // 0 when new contributors get no grace period
+	Authors         []NewContributorEntry `json:"authors"`                     // Latest first commit first
+}
+
+type NewContributorEntry struct {
+	Rank         int       `json:"rank"`
+	Name         string    `json:"name"`
+	Email        string    `json:"email"`
+	FirstCommit  time.Time `json:"first_commit"`
+	Commits      int       `json:"commits"`
+	FilesTouched int       `json:"files_touched"` // Files changed by their non-merge commits
+	GracedIssues int       `json:"graced_issues"` // Issues left off the author leaderboard in their grace period
+}
+
+// CleanupCommit is a commit that removed more lines than it added.
+type CleanupCommit struct {
+	Hash         string    `json:"hash"`
+	Name         string    `json:"name"`
+	Email        string    `json:"email"`
+	Date         time.Time `json:"date"`
+	Subject      string    `json:"subject"`
+	AddedLines   int       `json:"added_lines"`
+	DeletedLines int       `json:"deleted_lines"`
+	NetRemoved   int       `json:"net_removed"`
+}
+
+// TimezoneStats describes when authors commit in their own local time. It is
+// descriptive only: commit times say nothing about how much anyone works.
+type TimezoneStats struct {
+	MinCommits    int                   `json:"min_commits"` // Authors with fewer commits are left out
+	Commits       int                   `json:"commits"`
+	WeekendShare  float64               `json:"weekend_share"`   // Percentage of commits on Saturday or Sunday
+	OffHoursShare float64               `json:"off_hours_share"` // Percentage of commits before 09:00 or from 18:00
+	Offsets       []UTCOffsetEntry      `json:"offsets"`
+	Authors       []TimezoneAuthorEntry `json:"authors"`
+}
+
+// UTCOffsetEntry counts the authors whose dominant offset is Offset, and
+// the commits they made.
+type UTCOffsetEntry struct {
+	Offset  int `json:"offset"` // Seconds east of UTC
+	Authors int `json:"authors"`
+	Commits int `json:"commits"`
+}
+
+type TimezoneAuthorEntry struct {
+	Rank          int     `json:"rank"`
+	Name          string  `json:"name"`
+	Email         string  `json:"email"`
+	Commits       int     `json:"commits"`
+	Offset        int     `json:"offset"` // Most used offset, in seconds east of UTC
+	WeekendShare  float64 `json:"weekend_share"`
+	OffHoursShare float64 `json:"off_hours_share"`
+}
+
+// WeeklyMerges counts the merges in the week starting on Monday Week (UTC).
+type WeeklyMerges struct {
+	Week   time.Time `json:"week"`
+	Merges int       `json:"merges"`
+}
+
+// VulnEntry is a known vulnerability in an installed dependency.
+type VulnEntry struct {
+	Ecosystem        string   `json:"ecosystem"` // npm or pypi
+	Package          string   `json:"package"`
+	InstalledVersion string   `json:"installed_version"`
+	Severity         string   `json:"severity"` // critical, high, moderate, low or unknown
+	AdvisoryID       string   `json:"advisory_id"`
+	FixedIn          string   `json:"fixed_in,omitempty"`
+	Direct           []string `json:"direct"` // Direct dependencies that pull the package in
+}
+
+// DependencyEntry counts the dependencies one lockfile or requirements file
+// declares and how many are pinned to a single version. Direct dependencies
+// that are neither, from a git repository, a URL or a path, are the rest.
+type DependencyEntry struct {
+	Rank           int      `json:"rank"`
+	Path           string   `json:"path"`
+	Ecosystem      string   `json:"ecosystem"` // npm or pypi
+	Direct         int      `json:"direct"`    // Development ones included
+	Locked         int      `json:"locked"`    // Transitive ones included; 0 for requirements files
+	Pinned         int      `json:"pinned"`
+	Ranged         int      `json:"ranged"`
+	PinnedPercent  float64  `json:"pinned_percent"`
+	RangedPackages []string `json:"ranged_packages,omitempty"` // Sorted by name
+}
+
+// LFSStats reports whether the files .gitattributes routes through Git LFS
+// are stored as LFS pointers at HEAD.
+type LFSStats struct {
+	Patterns   []LFSPatternEntry `json:"patterns"`
+	Tracked    int               `json:"tracked"` // Files with filter=lfs
+	Pointers   int               `json:"pointers"`
+	Raw        int               `json:"raw"`        // Tracked files committed as raw blobs
+	Violations []LFSViolation    `json:"violations"` // Raw blobs, largest first
+
+	// ObjectsCounted is set when git lfs was available to list the LFS
+	// objects and their approximate total size in bytes.
+	ObjectsCounted bool  `json:"objects_counted"`
+	Objects        int   `json:"objects"`
+	ObjectSize     int64 `json:"object_size"`
+}
+
+// LFSPatternEntry counts the files at HEAD matching one LFS pattern from the
+// root .gitattributes.
+type LFSPatternEntry struct {
+	Pattern  string `json:"pattern"`
+	Files    int    `json:"files"`
+	Pointers int    `json:"pointers"`
+	Raw      int    `json:"raw"`
+}
+
+// LFSViolation is a file committed as a raw blob where an LFS pointer was
+// expected, with the commit that last changed it.
+type LFSViolation struct {
+	Path   string    `json:"path"`
+	Size   int64     `json:"size"` // Bytes
+	Commit string    `json:"commit"`
+	Author string    `json:"author"`
+	Email  string    `json:"email"`
+	Date   time.Time `json:"date"`
+}
+
+// ScoreStats is the composite quality score of a repository and its files,
+// on a 0-100 scale where higher is better.
+type ScoreStats struct {
+	Score      float64          `json:"score"`
+	Components []ScoreComponent `json:"components"`
+	Files      []FileScore      `json:"files"` // Worst first
+}
+
+// ScoreComponent is one measurement the repository score is combined from.
+// Weight is its share of the score in percent, after the weights were
+// re-normalized over the components that were measured.
+type ScoreComponent struct {
+	Component string  `json:"component"`
+	Score     float64 `json:"score"` // 0-100
+	Weight    float64 `json:"weight"`
+	Detail    string  `json:"detail"`
+}
+
+// FileScore is the composite score of one file, with the score of each
+// component measured for it.
+type FileScore struct {
+	Rank       int                `json:"rank"`
+	Path       string             `json:"path"`
+	Score      float64            `json:"score"`
+	Components map[string]float64 `json:"components"`
+	Details    map[string]string  `json:"details,omitempty"` // What each component was scored from
 }
@@ -0,0 +1,80 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Warning is a non-fatal problem logged during a run: what went wrong, and
+// the params it went wrong with, such as the file. Warnings logged from the
+// same place share a message and param keys, so they can be grouped.
+type Warning struct {
+	Message string         `json:"message"`
+	Params  []WarningParam `json:"params,omitempty"` // In the order they were given
+}
+
+// WarningParam is a key and value of a Warning.
+type WarningParam struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// NewWarning returns a warning with params given as alternating keys and
+// values, as with log/slog. Values are formatted with %v.
+func NewWarning(message string, args ...any) Warning {
+	w := Warning{Message: message}
+	for i := 0; i+1 < len(args); i += 2 {
+		w.Params = append(w.Params, WarningParam{Key: fmt.Sprint(args[i]), Value: fmt.Sprint(args[i+1])})
+	}
+	return w
+}
+
+// Param returns the value of the param key, or "" if w has none.
+func (w Warning) Param(key string) string {
+	for _, param := range w.Params {
+		if param.Key == key {
+			return param.Value
+		}
+	}
+	return ""
+}
+
+// Template returns the shape of w: its message and param keys without the
+// values, such as "Blame failed (file, error)".
+func (w Warning) Template() string {
+	if len(w.Params) == 0 {
+		return w.Message
+	}
+	keys := make([]string, len(w.Params))
+	for i, param := range w.Params {
+		keys[i] = param.Key
+	}
+	return fmt.Sprintf("%s (%s)", w.Message, strings.Join(keys, ", "))
+}
+
+// String renders w as one line, such as
+// "⚠️ Blame failed (file=main.js error=exit status 128)".
+func (w Warning) String() string {
+	if len(w.Params) == 0 {
+		return "⚠️ " + w.Message
+	}
+	parts := make([]string, len(w.Params))
+	for i, param := range w.Params {
+		parts[i] = param.Key + "=" + param.Value
+	}
+	return fmt.Sprintf("⚠️ %s (%s)", w.Message, strings.Join(parts, " "))
+}
+
+// UnmarshalJSON also reads the preformatted strings reports of schema
+// version 28 and earlier have for warnings, as warnings without params.
+func (w *Warning) UnmarshalJSON(data []byte) error {
+	var message string
+	if err := json.Unmarshal(data, &message); err == nil {
+		*w = Warning{Message: strings.TrimPrefix(message, "⚠️ ")}
+		return nil
+	}
+
+	type warning Warning
+	return json.Unmarshal(data, (*warning)(w))
+}
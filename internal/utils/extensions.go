@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ParseExtensions parses a comma-separated list of file extensions, such as
+// js,ts or .md, into lowercase extensions with their leading dot.
+func ParseExtensions(value string) ([]string, error) {
+	var extensions []string
+	for _, extension := range strings.Split(value, ",") {
+		extension = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(extension), "."))
+		if extension == "" {
+			continue
+		}
+		if strings.ContainsAny(extension, `./\`) {
+			return nil, fmt.Errorf("invalid extension %q: expected a single extension such as ts or md", extension)
+		}
+		if extension = "." + extension; !slices.Contains(extensions, extension) {
+			extensions = append(extensions, extension)
+		}
+	}
+	if len(extensions) == 0 {
+		return nil, fmt.Errorf("no extension in %q, expected a list such as js,ts", value)
+	}
+	return extensions, nil
+}
+
+// ExtensionFilter keeps files by their extension, as ParseExtensions returns
+// them. The zero filter keeps every file.
+type ExtensionFilter struct {
+	Only    []string // Keep only files with one of these, when set
+	Exclude []string // Leave out files with one of these, even when in Only
+}
+
+// Match reports whether f keeps path. Extensions compare without regard to
+// case, and files without one are only kept when Only is not set.
+func (f ExtensionFilter) Match(path string) bool {
+	extension := strings.ToLower(filepath.Ext(path))
+	if slices.Contains(f.Exclude, extension) {
+		return false
+	}
+	return len(f.Only) == 0 || slices.Contains(f.Only, extension)
+}
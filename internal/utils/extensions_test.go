@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseExtensions(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    []string
+		wantErr bool
+	}{
+		{"js,ts", []string{".js", ".ts"}, false},
+		{" .TSX , md,,tsx", []string{".tsx", ".md"}, false},
+		{"d.ts", nil, true},
+		{"src/js", nil, true},
+		{" , ", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseExtensions(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseExtensions(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseExtensions(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestExtensionFilter(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter ExtensionFilter
+		kept   []string
+		left   []string
+	}{
+		{"none", ExtensionFilter{}, []string{"main.go", "Makefile", "README.md"}, nil},
+		{"only", ExtensionFilter{Only: []string{".ts", ".tsx"}}, []string{"src/app.ts", "src/App.TSX", "types/index.d.ts"}, []string{"src/app.js", "Makefile", "ts/config"}},
+		{"exclude", ExtensionFilter{Exclude: []string{".md", ".json"}}, []string{"main.go", "Makefile", "docs/md/guide.txt"}, []string{"README.md", "package.JSON"}},
+		// Exclude wins when an extension is in both
+		{"both", ExtensionFilter{Only: []string{".ts", ".json"}, Exclude: []string{".json"}}, []string{"src/app.ts"}, []string{"tsconfig.json", "src/app.js"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, path := range tt.kept {
+				if !tt.filter.Match(path) {
+					t.Errorf("Expected %s to be kept", path)
+				}
+			}
+			for _, path := range tt.left {
+				if tt.filter.Match(path) {
+					t.Errorf("Expected %s to be left out", path)
+				}
+			}
+		})
+	}
+}
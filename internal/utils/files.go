@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ErrSymlink is returned by OpenRegular for paths that are symbolic links.
+var ErrSymlink = errors.New("file is a symlink")
+
+// IsSymlink reports whether path is a symbolic link. The link is not
+// followed, so broken links and links leaving the repository are reported
+// like any other.
+func IsSymlink(path string) bool {
+	info, err := os.Lstat(path)
+	return err == nil && info.Mode()&os.ModeSymlink != 0
+}
+
+// OpenRegular opens path for reading unless it is a symbolic link. Git can
+// track symlinks, and following one could read a file outside the
+// repository.
+func OpenRegular(path string) (*os.File, error) {
+	if IsSymlink(path) {
+		return nil, fmt.Errorf("%w: %s", ErrSymlink, path)
+	}
+	return os.Open(path)
+}
+
+// IgnoreFileDirective, in a comment near the top of a file, excludes the
+// file from all analysis, as in /* codecompass:ignore-file */.
+const IgnoreFileDirective = "codecompass:ignore-file"
+
+// ignoreFileHeaderLines is how many lines HasIgnoreFileDirective reads, so a
+// shebang or license header can come before the directive.
+const ignoreFileHeaderLines = 10
+
+// HasIgnoreFileDirective reports whether IgnoreFileDirective appears in the
+// first lines of the file at path. Files that cannot be read do not have it.
+func HasIgnoreFileDirective(path string) bool {
+	file, err := OpenRegular(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for lines := 0; lines < ignoreFileHeaderLines && scanner.Scan(); lines++ {
+		if strings.Contains(scanner.Text(), IgnoreFileDirective) {
+			return true
+		}
+	}
+	return false
+}
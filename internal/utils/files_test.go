@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOpenRegularRejectsSymlinks(t *testing.T) {
+	dir := t.TempDir()
+	outside := filepath.Join(t.TempDir(), "secret.txt")
+	if err := os.WriteFile(outside, []byte("secret"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	regular := filepath.Join(dir, "regular.txt")
+	if err := os.WriteFile(regular, []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	links := map[string]string{
+		"outside.txt": outside,
+		"broken.txt":  filepath.Join(dir, "missing.txt"),
+		"inside.txt":  "regular.txt",
+	}
+	for name, target := range links {
+		if err := os.Symlink(target, filepath.Join(dir, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	file, err := OpenRegular(regular)
+	if err != nil {
+		t.Fatalf("Expected regular file to open, but got %v", err)
+	}
+	file.Close()
+
+	if IsSymlink(regular) {
+		t.Errorf("Expected %s not to be a symlink", regular)
+	}
+
+	for name := range links {
+		path := filepath.Join(dir, name)
+		if !IsSymlink(path) {
+			t.Errorf("Expected %s to be a symlink", name)
+		}
+		if _, err := OpenRegular(path); !errors.Is(err, ErrSymlink) {
+			t.Errorf("Expected ErrSymlink opening %s, but got %v", name, err)
+		}
+	}
+}
+
+func TestHasIgnoreFileDirective(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"block.js":   "/* codecompass:ignore-file */\nvar a = 1;\n",
+		"shebang":    "#!/bin/sh\n# Copyright Example\n# codecompass:ignore-file\necho hi\n",
+		"plain.js":   "var a = 1;\n",
+		"late.js":    "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n// codecompass:ignore-file\n",
+		"mention.md": "# Docs\n\nUse codecompass:ignore-file in a comment.\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for name, expected := range map[string]bool{
+		"block.js":   true,
+		"shebang":    true,
+		"plain.js":   false,
+		"late.js":    false, // past the header
+		"mention.md": true,
+		"missing.js": false,
+	} {
+		if got := HasIgnoreFileDirective(filepath.Join(dir, name)); got != expected {
+			t.Errorf("HasIgnoreFileDirective(%s) = %v, expected %v", name, got, expected)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrLineTooLong is returned by LineScanner.Err when a line is longer than
+// the limit of the scanner.
+var ErrLineTooLong = errors.New("line too long")
+
+// LineScanner reads lines like bufio.Scanner, but up to a limit rather than
+// the 64 KB bufio.Scanner stops at, so minified bundles and data files with
+// a long line can still be scanned.
+type LineScanner struct {
+	*bufio.Scanner
+	limitKB int
+}
+
+// NewLineScanner returns a scanner for the lines of r of at most limitKB
+// kilobytes, the unit of max-line-size. Limits under 64 KB are raised to
+// it.
+func NewLineScanner(r io.Reader, limitKB int) *LineScanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), max(limitKB*1024, bufio.MaxScanTokenSize))
+	return &LineScanner{Scanner: scanner, limitKB: limitKB}
+}
+
+// Err returns the error that stopped the scan, if any. A line over the limit
+// stops it with an error wrapping ErrLineTooLong, which callers should
+// report rather than take the lines read so far as the whole file.
+func (s *LineScanner) Err() error {
+	err := s.Scanner.Err()
+	if errors.Is(err, bufio.ErrTooLong) {
+		return fmt.Errorf("%w: a line is longer than max-line-size (%d KB)", ErrLineTooLong, s.limitKB)
+	}
+	return err
+}
@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLineScanner(t *testing.T) {
+	// A minified line past the 64 KB bufio.Scanner stops at by default
+	long := strings.Repeat("a", 100*1024)
+	input := "first\n" + long + "\nlast\n"
+
+	scanner := NewLineScanner(strings.NewReader(input), 1024)
+	var lines []string
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("Expected the long line to be read, but got %v", err)
+	}
+	if len(lines) != 3 || lines[1] != long || lines[2] != "last" {
+		t.Errorf("Expected 3 lines, got %d", len(lines))
+	}
+
+	scanner = NewLineScanner(strings.NewReader(input), 80)
+	for scanner.Scan() {
+	}
+	if err := scanner.Err(); !errors.Is(err, ErrLineTooLong) || !strings.Contains(err.Error(), "max-line-size (80 KB)") {
+		t.Errorf("Expected ErrLineTooLong naming the limit, but got %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// FormatCount formats n with its digits grouped in thousands, such as
+// 12,345, using the separator of the locale in LC_ALL, LC_NUMERIC or LANG.
+func FormatCount(n int) string {
+	return GroupDigits(n, localeSeparator(os.Getenv))
+}
+
+// GroupDigits formats n with its digits grouped in thousands by sep.
+func GroupDigits(n int, sep string) string {
+	digits := strconv.Itoa(n)
+	sign := ""
+	if n < 0 {
+		sign, digits = "-", digits[1:]
+	}
+	if len(digits) <= 3 {
+		return sign + digits
+	}
+
+	var b strings.Builder
+	b.WriteString(sign)
+	first := len(digits) % 3
+	if first == 0 {
+		first = 3
+	}
+	b.WriteString(digits[:first])
+	for i := first; i < len(digits); i += 3 {
+		b.WriteString(sep)
+		b.WriteString(digits[i : i+3])
+	}
+	return b.String()
+}
+
+// thousandsSeparators are the separators of the languages that do not group
+// digits with a comma. Spaces are no-break spaces, so a count is never
+// wrapped.
+var thousandsSeparators = map[string]string{
+	"de": ".", "nl": ".", "it": ".", "es": ".", "pt": ".", "da": ".", "id": ".", "tr": ".", "el": ".",
+	"fr": " ", "ru": " ", "pl": " ", "cs": " ", "sk": " ", "sv": " ",
+	"fi": " ", "nb": " ", "no": " ", "uk": " ", "hu": " ", "bg": " ",
+}
+
+// localeSeparator returns the thousands separator of the locale set in the
+// environment getenv reads, the first of LC_ALL, LC_NUMERIC and LANG that
+// is set, such as "." for de_DE.UTF-8. Other and unset locales group with
+// a comma.
+func localeSeparator(getenv func(string) string) string {
+	for _, name := range []string{"LC_ALL", "LC_NUMERIC", "LANG"} {
+		locale := getenv(name)
+		if locale == "" {
+			continue
+		}
+		// Such as de_DE.UTF-8: the language comes before the territory
+		language, _, _ := strings.Cut(strings.ToLower(locale), "_")
+		language, _, _ = strings.Cut(language, ".")
+		if sep, ok := thousandsSeparators[language]; ok {
+			return sep
+		}
+		return ","
+	}
+	return ","
+}
@@ -0,0 +1,48 @@
+package utils
+
+import "testing"
+
+func TestGroupDigits(t *testing.T) {
+	tests := []struct {
+		n        int
+		expected string
+	}{
+		{0, "0"},
+		{7, "7"},
+		{999, "999"},
+		{1000, "1,000"},
+		{12345, "12,345"},
+		{999999, "999,999"},
+		{1234567, "1,234,567"},
+		{-1234, "-1,234"},
+		{-999, "-999"},
+		{9876543210, "9,876,543,210"},
+	}
+	for _, tt := range tests {
+		if got := GroupDigits(tt.n, ","); got != tt.expected {
+			t.Errorf("GroupDigits(%d) = %q, expected %q", tt.n, got, tt.expected)
+		}
+	}
+}
+
+func TestLocaleSeparator(t *testing.T) {
+	tests := []struct {
+		env      map[string]string
+		expected string
+	}{
+		{nil, ","},
+		{map[string]string{"LANG": "C"}, ","},
+		{map[string]string{"LANG": "en_US.UTF-8"}, ","},
+		{map[string]string{"LANG": "de_DE.UTF-8"}, "."},
+		{map[string]string{"LANG": "fr_FR.UTF-8"}, " "},
+		// LC_ALL wins over LC_NUMERIC, which wins over LANG
+		{map[string]string{"LANG": "de_DE.UTF-8", "LC_NUMERIC": "en_GB.UTF-8"}, ","},
+		{map[string]string{"LC_NUMERIC": "en_GB.UTF-8", "LC_ALL": "it_IT"}, "."},
+	}
+	for _, tt := range tests {
+		getenv := func(name string) string { return tt.env[name] }
+		if got := localeSeparator(getenv); got != tt.expected {
+			t.Errorf("localeSeparator(%v) = %q, expected %q", tt.env, got, tt.expected)
+		}
+	}
+}
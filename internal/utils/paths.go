@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// pathEllipsis stands in for the leading directories TruncatePath drops.
+const pathEllipsis = "..."
+
+// TruncatePath shortens a slash-separated path to at most maxWidth terminal
+// columns by replacing its leading directories with "...", keeping as many
+// trailing directories as fit, as in .../middle/file.ext. The file name is
+// always kept whole, so a name wider than maxWidth is returned past it.
+func TruncatePath(path string, maxWidth int) string {
+	if runewidth.StringWidth(path) <= maxWidth {
+		return path
+	}
+
+	dirs := strings.Split(path, "/")
+	kept := dirs[len(dirs)-1]
+	for i := len(dirs) - 2; i > 0; i-- {
+		longer := dirs[i] + "/" + kept
+		if runewidth.StringWidth(pathEllipsis+"/"+longer) > maxWidth {
+			break
+		}
+		kept = longer
+	}
+	if len(dirs) == 1 {
+		return kept
+	}
+	return pathEllipsis + "/" + kept
+}
@@ -0,0 +1,30 @@
+package utils
+
+import "testing"
+
+func TestTruncatePath(t *testing.T) {
+	tests := []struct {
+		path     string
+		maxWidth int
+		expected string
+	}{
+		{"src/app.js", 20, "src/app.js"},
+		{"src/app.js", 10, "src/app.js"},
+		{"packages/web/src/components/Chart.tsx", 30, ".../src/components/Chart.tsx"},
+		{"packages/web/src/components/Chart.tsx", 26, ".../components/Chart.tsx"},
+		{"packages/web/src/components/Chart.tsx", 20, ".../Chart.tsx"},
+		// The file name is kept even when it does not fit
+		{"packages/web/src/components/Chart.tsx", 5, ".../Chart.tsx"},
+		{"a-very-long-file-name.js", 10, "a-very-long-file-name.js"},
+		// Wide characters take two columns each
+		{"文档/说明/指南/安装.md", 16, ".../指南/安装.md"},
+		{"/abs/dir/file.go", 12, ".../file.go"},
+	}
+
+	for _, tt := range tests {
+		got := TruncatePath(tt.path, tt.maxWidth)
+		if got != tt.expected {
+			t.Errorf("TruncatePath(%q, %d) = %q; expected %q", tt.path, tt.maxWidth, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,12 @@
+package utils
+
+// ProgressFunc is told how many of total files a scan has done so far.
+type ProgressFunc func(done, total int)
+
+// Report calls f, if set. Scanners take a nil ProgressFunc when nobody is
+// watching.
+func (f ProgressFunc) Report(done, total int) {
+	if f != nil {
+		f(done, total)
+	}
+}
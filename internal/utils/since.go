@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseSince parses the start of an analysis window. value is either a
+// number of days or weeks before now, such as 30d or 12w, or a date in
+// YYYY-MM-DD form, which is taken as midnight UTC.
+func ParseSince(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+
+	if date, err := time.Parse("2006-01-02", value); err == nil {
+		return date, nil
+	}
+
+	if len(value) >= 2 {
+		count, err := strconv.Atoi(value[:len(value)-1])
+		if err == nil && count >= 0 {
+			switch value[len(value)-1] {
+			case 'd':
+				return now.AddDate(0, 0, -count), nil
+			case 'w':
+				return now.AddDate(0, 0, -7*count), nil
+			}
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid since value %q: expected a count of days or weeks such as 30d or 12w, or a YYYY-MM-DD date", value)
+}
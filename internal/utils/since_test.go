@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value    string
+		expected time.Time
+		valid    bool
+	}{
+		{"30d", now.AddDate(0, 0, -30), true},
+		{"2w", now.AddDate(0, 0, -14), true},
+		{"0d", now, true},
+		{"2024-01-01", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), true},
+		{" 7d ", now.AddDate(0, 0, -7), true},
+		{"", time.Time{}, false},
+		{"d", time.Time{}, false},
+		{"-3d", time.Time{}, false},
+		{"3m", time.Time{}, false},
+		{"2024-13-01", time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSince(tt.value, now)
+		if tt.valid && (err != nil || !got.Equal(tt.expected)) {
+			t.Errorf("ParseSince(%q) = %v, %v; expected %v", tt.value, got, err, tt.expected)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("ParseSince(%q) = %v; expected an error", tt.value, got)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// SizeGuard keeps files over a size limit away from the scanners that read
+// them, so one huge file cannot stall a run.
+type SizeGuard struct {
+	limit int64 // in bytes; zero means no limit
+}
+
+// NewSizeGuard returns a guard for files of at most limitKB kilobytes, the
+// unit of max-file-size. Zero or less means no limit.
+func NewSizeGuard(limitKB int) SizeGuard {
+	if limitKB <= 0 {
+		return SizeGuard{}
+	}
+	return SizeGuard{limit: int64(limitKB) * 1024}
+}
+
+// Allows reports whether the file at path is within the limit. The link
+// itself is measured for symbolic links, and files that cannot be measured
+// are allowed, so their readers report the error.
+func (g SizeGuard) Allows(path string) bool {
+	if g.limit == 0 {
+		return true
+	}
+	info, err := os.Lstat(path)
+	return err != nil || info.Size() <= g.limit
+}
+
+// Filter returns the files of files, relative to dir, within the limit, and
+// how many it left out.
+func (g SizeGuard) Filter(dir string, files map[string]bool) (map[string]bool, int) {
+	if g.limit == 0 {
+		return files, 0
+	}
+	kept := make(map[string]bool, len(files))
+	for file := range files {
+		if g.Allows(filepath.Join(dir, file)) {
+			kept[file] = true
+		}
+	}
+	return kept, len(files) - len(kept)
+}
@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSizeGuard(t *testing.T) {
+	dir := t.TempDir()
+	for name, size := range map[string]int{"small.js": 100, "exact.js": 2048, "large.js": 2049} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(strings.Repeat("a", size)), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files := map[string]bool{"small.js": true, "exact.js": true, "large.js": true, "missing.js": true}
+
+	kept, skipped := NewSizeGuard(2).Filter(dir, files)
+	expected := map[string]bool{"small.js": true, "exact.js": true, "missing.js": true}
+	if !reflect.DeepEqual(kept, expected) || skipped != 1 {
+		t.Errorf("Expected %v with 1 skipped, but got %v with %d skipped", expected, kept, skipped)
+	}
+
+	for _, limit := range []int{0, -1} {
+		if kept, skipped := NewSizeGuard(limit).Filter(dir, files); len(kept) != len(files) || skipped != 0 {
+			t.Errorf("Expected no limit for %d KB, but got %v with %d skipped", limit, kept, skipped)
+		}
+	}
+}
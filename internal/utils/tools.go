@@ -0,0 +1,17 @@
+package utils
+
+import (
+	"os/exec"
+
+	"github.com/xeon-zolt/codecompass/internal/cerrors"
+)
+
+// RequireTool checks that the external tool name, such as git or ruff, is
+// on PATH. It returns cerrors.ErrToolNotFound when it is not, so a missing
+// tool is reported by name before running it fails with a bare exec error.
+func RequireTool(name string) error {
+	if _, err := exec.LookPath(name); err != nil {
+		return &cerrors.ErrToolNotFound{Tool: name, Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/xeon-zolt/codecompass/internal/cerrors"
+)
+
+func TestRequireTool(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake tool is a shell script")
+	}
+	dir := t.TempDir()
+	t.Setenv("PATH", dir)
+
+	err := RequireTool("git")
+	var toolErr *cerrors.ErrToolNotFound
+	if !errors.As(err, &toolErr) || toolErr.Tool != "git" || !errors.Is(err, exec.ErrNotFound) {
+		t.Fatalf("Expected ErrToolNotFound for git, but got %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "git"), []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := RequireTool("git"); err != nil {
+		t.Errorf("Expected git found on PATH, but got %v", err)
+	}
+}
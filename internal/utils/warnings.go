@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+// WarningCollector gathers warnings from concurrent workers. It is safe for
+// concurrent use, so callers share one collector instead of a slice guarded
+// by their own mutex.
+type WarningCollector struct {
+	mu       sync.Mutex
+	warnings []types.Warning
+}
+
+func NewWarningCollector() *WarningCollector {
+	return &WarningCollector{}
+}
+
+// Add records a warning.
+func (c *WarningCollector) Add(warning types.Warning) {
+	c.mu.Lock()
+	c.warnings = append(c.warnings, warning)
+	c.mu.Unlock()
+}
+
+// Warnings returns a copy of the collected warnings in the order they were
+// added.
+func (c *WarningCollector) Warnings() []types.Warning {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]types.Warning{}, c.warnings...)
+}
+
+// WarningExamples is how many examples a WarningGroup keeps.
+const WarningExamples = 3
+
+// WarningGroup is a set of warnings with the same template.
+type WarningGroup struct {
+	Warning  types.Warning // The first of the group
+	Count    int
+	Examples []string // The files, or other first params, of the first warnings, without repeats
+}
+
+// GroupWarnings groups warnings by their template, the most frequent first.
+// Groups as frequent as each other keep the order their first warnings were
+// added in.
+func GroupWarnings(warnings []types.Warning) []WarningGroup {
+	var groups []WarningGroup
+	index := make(map[string]int)
+	for _, warning := range warnings {
+		i, found := index[warning.Template()]
+		if !found {
+			i = len(groups)
+			index[warning.Template()] = i
+			groups = append(groups, WarningGroup{Warning: warning})
+		}
+
+		group := &groups[i]
+		group.Count++
+		if example := warningExample(warning); example != "" && len(group.Examples) < WarningExamples && !slices.Contains(group.Examples, example) {
+			group.Examples = append(group.Examples, example)
+		}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool { return groups[i].Count > groups[j].Count })
+	return groups
+}
+
+// warningExample returns what tells warning apart from the others of its
+// group: its file, or else its first param.
+func warningExample(warning types.Warning) string {
+	if file := warning.Param("file"); file != "" {
+		return file
+	}
+	if len(warning.Params) > 0 {
+		return warning.Params[0].Value
+	}
+	return ""
+}
@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+func TestWarningCollectorConcurrentAdd(t *testing.T) {
+	collector := NewWarningCollector()
+
+	const goroutines, perGoroutine = 50, 100
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < perGoroutine; j++ {
+				collector.Add(types.NewWarning("Blame failed", "file", fmt.Sprintf("worker%d/%d.js", i, j)))
+			}
+		}(i)
+	}
+
+	// Reading while workers add must not race either
+	for i := 0; i < 10; i++ {
+		_ = collector.Warnings()
+	}
+	wg.Wait()
+
+	warnings := collector.Warnings()
+	if len(warnings) != goroutines*perGoroutine {
+		t.Fatalf("Expected %d warnings, but got %d", goroutines*perGoroutine, len(warnings))
+	}
+
+	seen := make(map[string]bool)
+	for _, warning := range warnings {
+		seen[warning.Param("file")] = true
+	}
+	if len(seen) != goroutines*perGoroutine {
+		t.Errorf("Expected every warning to be kept once, but got %d distinct", len(seen))
+	}
+}
+
+func TestWarningCollectorReturnsCopy(t *testing.T) {
+	collector := NewWarningCollector()
+	collector.Add(types.NewWarning("first"))
+
+	warnings := collector.Warnings()
+	warnings[0].Message = "changed"
+
+	if got := collector.Warnings()[0].Message; got != "first" {
+		t.Errorf("Expected the collector to be unaffected by callers, but got %q", got)
+	}
+}
+
+func TestGroupWarnings(t *testing.T) {
+	var warnings []types.Warning
+	warnings = append(warnings, types.NewWarning("Spell check skipped", "file", "README.md", "error", "line too long"))
+	for _, file := range []string{"a.js", "b.js", "a.js", "c.js", "d.js"} {
+		warnings = append(warnings, types.NewWarning("Blame failed", "file", file, "error", "exit status 128"))
+	}
+	warnings = append(warnings,
+		types.NewWarning("Vulnerability scan skipped", "tool", "npm", "error", "not found"),
+		types.NewWarning("Vulnerability scan skipped", "tool", "pip-audit", "error", "not found"),
+		// Same message, different shape
+		types.NewWarning("Blame failed", "file", "e.js", "line", "3"),
+	)
+
+	groups := GroupWarnings(warnings)
+
+	expected := []WarningGroup{
+		{Warning: warnings[1], Count: 5, Examples: []string{"a.js", "b.js", "c.js"}},
+		{Warning: warnings[6], Count: 2, Examples: []string{"npm", "pip-audit"}},
+		{Warning: warnings[0], Count: 1, Examples: []string{"README.md"}},
+		{Warning: warnings[8], Count: 1, Examples: []string{"e.js"}},
+	}
+	if !reflect.DeepEqual(groups, expected) {
+		t.Errorf("Expected groups %+v, but got %+v", expected, groups)
+	}
+
+	if groups := GroupWarnings([]types.Warning{types.NewWarning("Analysis failed")}); len(groups) != 1 || groups[0].Count != 1 || groups[0].Examples != nil {
+		t.Errorf("Expected a group without examples, but got %+v", groups)
+	}
+}
@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// OutputWidth returns how many columns output written to f can take: width
+// when it is above zero, as set with --width, the width of the terminal f
+// is, or $COLUMNS. Zero means the width is unknown, as for a pipe without
+// $COLUMNS, and output is not fitted to one.
+func OutputWidth(f *os.File, width int) int {
+	if width > 0 {
+		return width
+	}
+	if f != nil && term.IsTerminal(int(f.Fd())) {
+		if columns, _, err := term.GetSize(int(f.Fd())); err == nil && columns > 0 {
+			return columns
+		}
+	}
+	if columns, err := strconv.Atoi(strings.TrimSpace(os.Getenv("COLUMNS"))); err == nil && columns > 0 {
+		return columns
+	}
+	return 0
+}
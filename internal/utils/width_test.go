@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOutputWidth(t *testing.T) {
+	// A file is not a terminal, so only --width and $COLUMNS count
+	f, err := os.Create(filepath.Join(t.TempDir(), "out.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	tests := []struct {
+		columns  string
+		width    int
+		expected int
+	}{
+		{"", 0, 0},
+		{"", 60, 60},
+		{"100", 0, 100},
+		{"100", 60, 60},
+		{"wide", 0, 0},
+		{"-5", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Setenv("COLUMNS", tt.columns)
+		if got := OutputWidth(f, tt.width); got != tt.expected {
+			t.Errorf("OutputWidth with COLUMNS=%q and width %d = %d; expected %d", tt.columns, tt.width, got, tt.expected)
+		}
+	}
+}
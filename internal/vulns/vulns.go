@@ -0,0 +1,330 @@
+// Package vulns audits a repository's dependencies for known vulnerabilities
+// with npm audit and pip-audit.
+package vulns
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/xeon-zolt/codecompass/internal/types"
+	"github.com/xeon-zolt/codecompass/internal/utils"
+)
+
+// Severities from most to least severe. Advisories without a severity, such
+// as those pip-audit reports, are SeverityUnknown.
+const (
+	SeverityCritical = "critical"
+	SeverityHigh     = "high"
+	SeverityModerate = "moderate"
+	SeverityLow      = "low"
+	SeverityUnknown  = "unknown"
+)
+
+// Severities lists the severities in display order.
+var Severities = []string{SeverityCritical, SeverityHigh, SeverityModerate, SeverityLow, SeverityUnknown}
+
+// SeverityRank orders severities; higher is more severe.
+func SeverityRank(severity string) int {
+	for i, s := range Severities {
+		if s == severity {
+			return len(Severities) - i
+		}
+	}
+	return 0
+}
+
+// Scanner audits the dependencies of one ecosystem.
+type Scanner struct {
+	// Tool is the command the scanner runs.
+	Tool string
+
+	// Detect reports whether dir has a lockfile the scanner can audit.
+	Detect func(dir string) bool
+
+	// Run audits the dependencies in dir.
+	Run func(ctx context.Context, dir string) ([]types.VulnEntry, error)
+}
+
+// Scanners returns the npm and pip scanners.
+func Scanners() []Scanner {
+	return []Scanner{
+		{Tool: "npm", Detect: hasNPMLockfile, Run: RunNPMAudit},
+		{Tool: "pip-audit", Detect: hasPythonRequirements, Run: RunPipAudit},
+	}
+}
+
+func hasNPMLockfile(dir string) bool {
+	return fileExists(filepath.Join(dir, "package-lock.json"))
+}
+
+func hasPythonRequirements(dir string) bool {
+	return fileExists(filepath.Join(dir, "requirements.txt")) || fileExists(filepath.Join(dir, "pyproject.toml"))
+}
+
+func fileExists(name string) bool {
+	info, err := os.Stat(name)
+	return err == nil && !info.IsDir()
+}
+
+// Counts counts entries by severity. Every severity is present.
+func Counts(entries []types.VulnEntry) map[string]int {
+	counts := make(map[string]int)
+	for _, severity := range Severities {
+		counts[severity] = 0
+	}
+	for _, entry := range entries {
+		counts[entry.Severity]++
+	}
+	return counts
+}
+
+// Sort orders entries by severity, then package and advisory.
+func Sort(entries []types.VulnEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if ri, rj := SeverityRank(entries[i].Severity), SeverityRank(entries[j].Severity); ri != rj {
+			return ri > rj
+		}
+		if entries[i].Package != entries[j].Package {
+			return entries[i].Package < entries[j].Package
+		}
+		return entries[i].AdvisoryID < entries[j].AdvisoryID
+	})
+}
+
+// run executes tool in dir and returns its standard output. Both audit tools
+// exit non-zero when they find vulnerabilities, so that is not an error.
+func run(ctx context.Context, dir, tool string, args ...string) ([]byte, error) {
+	if err := utils.RequireTool(tool); err != nil {
+		return nil, err
+	}
+	cmd := exec.CommandContext(ctx, tool, args...)
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr) && len(output) > 0:
+		return output, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to run %s: %w", tool, err)
+	}
+	return output, nil
+}
+
+// RunNPMAudit runs npm audit in dir, which must contain a package-lock.json.
+func RunNPMAudit(ctx context.Context, dir string) ([]types.VulnEntry, error) {
+	output, err := run(ctx, dir, "npm", "audit", "--json")
+	if err != nil {
+		return nil, err
+	}
+
+	// npm audit leaves out installed versions, so they come from the lockfile
+	lockfile, err := os.ReadFile(filepath.Join(dir, "package-lock.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read package-lock.json: %w", err)
+	}
+
+	return parseNPMAudit(output, lockfile)
+}
+
+// npmAudit is the npm audit --json report format of npm 7 and later.
+type npmAudit struct {
+	Vulnerabilities map[string]npmVulnerability `json:"vulnerabilities"`
+	Error           *struct {
+		Summary string `json:"summary"`
+	} `json:"error"`
+}
+
+type npmVulnerability struct {
+	Name     string   `json:"name"`
+	IsDirect bool     `json:"isDirect"`
+	Effects  []string `json:"effects"`
+	Nodes    []string `json:"nodes"`
+
+	// Via holds advisory objects, or the names of vulnerable dependencies
+	// the package is only affected through.
+	Via []json.RawMessage `json:"via"`
+
+	// FixAvailable is false, true, or the package and version to install.
+	FixAvailable json.RawMessage `json:"fixAvailable"`
+}
+
+type npmAdvisory struct {
+	Source   int    `json:"source"`
+	Title    string `json:"title"`
+	URL      string `json:"url"`
+	Severity string `json:"severity"`
+}
+
+type npmLockfile struct {
+	Packages map[string]struct {
+		Version string `json:"version"`
+	} `json:"packages"`
+}
+
+func parseNPMAudit(output, lockfile []byte) ([]types.VulnEntry, error) {
+	var audit npmAudit
+	if err := json.Unmarshal(output, &audit); err != nil {
+		return nil, fmt.Errorf("failed to parse npm audit output: %w", err)
+	}
+	if audit.Error != nil {
+		return nil, fmt.Errorf("npm audit failed: %s", audit.Error.Summary)
+	}
+
+	var lock npmLockfile
+	if err := json.Unmarshal(lockfile, &lock); err != nil {
+		return nil, fmt.Errorf("failed to parse package-lock.json: %w", err)
+	}
+
+	var entries []types.VulnEntry
+	for name, vuln := range audit.Vulnerabilities {
+		var installed string
+		if len(vuln.Nodes) > 0 {
+			installed = lock.Packages[vuln.Nodes[0]].Version
+		}
+
+		var fix struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		}
+		var fixedIn string
+		if json.Unmarshal(vuln.FixAvailable, &fix) == nil && fix.Name == name {
+			fixedIn = fix.Version
+		}
+
+		direct := directDependencies(audit.Vulnerabilities, name, make(map[string]bool))
+
+		for _, raw := range vuln.Via {
+			var advisory npmAdvisory
+			// Names of vulnerable dependencies are reported under their own key
+			if json.Unmarshal(raw, &advisory) != nil {
+				continue
+			}
+
+			entries = append(entries, types.VulnEntry{
+				Ecosystem:        "npm",
+				Package:          name,
+				InstalledVersion: installed,
+				Severity:         normalizeSeverity(advisory.Severity),
+				AdvisoryID:       advisoryID(advisory),
+				FixedIn:          fixedIn,
+				Direct:           direct,
+			})
+		}
+	}
+
+	Sort(entries)
+	return entries, nil
+}
+
+// directDependencies returns the direct dependencies that pull name in,
+// following the packages it affects up to the ones that are direct.
+func directDependencies(vulnerabilities map[string]npmVulnerability, name string, seen map[string]bool) []string {
+	if seen[name] {
+		return nil
+	}
+	seen[name] = true
+
+	vuln := vulnerabilities[name]
+	var direct []string
+	if vuln.IsDirect {
+		direct = append(direct, name)
+	}
+	for _, effect := range vuln.Effects {
+		direct = append(direct, directDependencies(vulnerabilities, effect, seen)...)
+	}
+
+	sort.Strings(direct)
+	return direct
+}
+
+// advisoryID returns the GHSA ID from an advisory URL, or the npm advisory
+// number when the URL has none.
+func advisoryID(advisory npmAdvisory) string {
+	if id := path.Base(advisory.URL); strings.HasPrefix(id, "GHSA-") {
+		return id
+	}
+	return fmt.Sprintf("%d", advisory.Source)
+}
+
+func normalizeSeverity(severity string) string {
+	severity = strings.ToLower(severity)
+	if SeverityRank(severity) == 0 {
+		return SeverityUnknown
+	}
+	return severity
+}
+
+// RunPipAudit runs pip-audit against requirements.txt in dir, or the
+// project in pyproject.toml when there is none.
+func RunPipAudit(ctx context.Context, dir string) ([]types.VulnEntry, error) {
+	args := []string{"-f", "json"}
+	if fileExists(filepath.Join(dir, "requirements.txt")) {
+		args = append(args, "-r", "requirements.txt")
+	} else {
+		args = append(args, ".")
+	}
+
+	output, err := run(ctx, dir, "pip-audit", args...)
+	if err != nil {
+		return nil, err
+	}
+	return parsePipAudit(output)
+}
+
+type pipDependency struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Vulns   []struct {
+		ID          string   `json:"id"`
+		FixVersions []string `json:"fix_versions"`
+	} `json:"vulns"`
+}
+
+// parsePipAudit parses pip-audit -f json output. pip-audit 2 wraps the
+// dependencies in an object; earlier versions print the list alone.
+func parsePipAudit(output []byte) ([]types.VulnEntry, error) {
+	var report struct {
+		Dependencies []pipDependency `json:"dependencies"`
+	}
+	if err := json.Unmarshal(output, &report); err != nil {
+		if err := json.Unmarshal(output, &report.Dependencies); err != nil {
+			return nil, fmt.Errorf("failed to parse pip-audit output: %w", err)
+		}
+	}
+
+	var entries []types.VulnEntry
+	for _, dependency := range report.Dependencies {
+		for _, vuln := range dependency.Vulns {
+			var fixedIn string
+			if len(vuln.FixVersions) > 0 {
+				fixedIn = vuln.FixVersions[0]
+			}
+
+			// pip-audit does not say which requirement pulled a package in
+			entries = append(entries, types.VulnEntry{
+				Ecosystem:        "pypi",
+				Package:          dependency.Name,
+				InstalledVersion: dependency.Version,
+				Severity:         SeverityUnknown,
+				AdvisoryID:       vuln.ID,
+				FixedIn:          fixedIn,
+				Direct:           []string{dependency.Name},
+			})
+		}
+	}
+
+	Sort(entries)
+	return entries, nil
+}
@@ -0,0 +1,110 @@
+package vulns
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/xeon-zolt/codecompass/internal/cerrors"
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestParseNPMAudit(t *testing.T) {
+	entries, err := parseNPMAudit(readFixture(t, "npm-audit.json"), readFixture(t, "package-lock.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// express is only affected through qs, so it has no advisory of its own
+	expected := []types.VulnEntry{
+		{Ecosystem: "npm", Package: "lodash", InstalledVersion: "4.17.11", Severity: SeverityCritical, AdvisoryID: "GHSA-jf85-cpcp-j695", FixedIn: "4.17.21", Direct: []string{"lodash"}},
+		{Ecosystem: "npm", Package: "lodash", InstalledVersion: "4.17.11", Severity: SeverityHigh, AdvisoryID: "GHSA-35jh-r3h4-6jhm", FixedIn: "4.17.21", Direct: []string{"lodash"}},
+		{Ecosystem: "npm", Package: "qs", InstalledVersion: "6.2.3", Severity: SeverityHigh, AdvisoryID: "GHSA-hrpp-h998-j3pp", Direct: []string{"express"}},
+	}
+	if !reflect.DeepEqual(entries, expected) {
+		t.Errorf("Expected %+v, but got %+v", expected, entries)
+	}
+}
+
+func TestParseNPMAuditError(t *testing.T) {
+	output := []byte(`{"error": {"code": "ENOLOCK", "summary": "This command requires an existing lockfile."}}`)
+
+	if _, err := parseNPMAudit(output, []byte(`{}`)); err == nil {
+		t.Error("Expected an npm audit error report to fail")
+	}
+}
+
+func TestParsePipAudit(t *testing.T) {
+	entries, err := parsePipAudit(readFixture(t, "pip-audit.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []types.VulnEntry{
+		{Ecosystem: "pypi", Package: "flask", InstalledVersion: "0.5", Severity: SeverityUnknown, AdvisoryID: "PYSEC-2018-66", FixedIn: "0.12.3", Direct: []string{"flask"}},
+		{Ecosystem: "pypi", Package: "flask", InstalledVersion: "0.5", Severity: SeverityUnknown, AdvisoryID: "PYSEC-2019-179", FixedIn: "1.0", Direct: []string{"flask"}},
+		{Ecosystem: "pypi", Package: "jinja2", InstalledVersion: "2.10", Severity: SeverityUnknown, AdvisoryID: "GHSA-462w-v97r-4m45", Direct: []string{"jinja2"}},
+	}
+	if !reflect.DeepEqual(entries, expected) {
+		t.Errorf("Expected %+v, but got %+v", expected, entries)
+	}
+
+	legacy, err := parsePipAudit(readFixture(t, "pip-audit-legacy.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(legacy) != 1 || legacy[0].AdvisoryID != "PYSEC-2019-179" {
+		t.Errorf("Expected the list format of older pip-audit versions to parse, but got %+v", legacy)
+	}
+
+	if _, err := parsePipAudit([]byte("not json")); err == nil {
+		t.Error("Expected invalid pip-audit output to fail")
+	}
+}
+
+func TestRunWithoutTools(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	for _, scanner := range Scanners() {
+		_, err := scanner.Run(context.Background(), t.TempDir())
+		var toolErr *cerrors.ErrToolNotFound
+		if !errors.As(err, &toolErr) || toolErr.Tool != scanner.Tool {
+			t.Errorf("Expected ErrToolNotFound for %s, but got %v", scanner.Tool, err)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	dir := t.TempDir()
+	scanners := Scanners()
+
+	for _, scanner := range scanners {
+		if scanner.Detect(dir) {
+			t.Errorf("Expected %s not to apply to an empty directory", scanner.Tool)
+		}
+	}
+
+	for _, name := range []string{"package-lock.json", "requirements.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, scanner := range scanners {
+		if !scanner.Detect(dir) {
+			t.Errorf("Expected %s to apply once its lockfile exists", scanner.Tool)
+		}
+	}
+}
@@ -0,0 +1,348 @@
+// Package workspace discovers the packages of a monorepo from its
+// JavaScript workspace and Go workspace manifests.
+package workspace
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Kinds of workspace manifest.
+const (
+	KindNPM  = "npm"  // "workspaces" in package.json, also used by Yarn
+	KindPNPM = "pnpm" // pnpm-workspace.yaml
+	KindGo   = "go"   // go.work
+)
+
+// Workspace is one package of a monorepo.
+type Workspace struct {
+	// Name is the package name from package.json, or the module path from
+	// go.mod. It falls back to Path.
+	Name string
+
+	// Path is the package directory relative to the repository root, with
+	// forward slashes. The root itself is ".".
+	Path string
+
+	Kind string
+}
+
+// Contains reports whether the slash-separated file, relative to the
+// repository root, is inside the workspace.
+func (w Workspace) Contains(file string) bool {
+	return w.Path == "." || strings.HasPrefix(file, w.Path+"/")
+}
+
+// Discover returns the workspaces declared in the manifests at the root of
+// dir, sorted by path. JavaScript workspace patterns are matched against
+// the directories of the package.json files among files, which are
+// slash-separated and relative to dir, so packages that are not part of the
+// repository, such as those under node_modules, are never picked up. A
+// repository without workspace manifests has none.
+func Discover(dir string, files []string) ([]Workspace, error) {
+	var packageDirs []string
+	for _, file := range files {
+		if path.Base(file) == "package.json" {
+			packageDirs = append(packageDirs, path.Dir(file))
+		}
+	}
+
+	var workspaces []Workspace
+	seen := make(map[string]bool)
+	add := func(found []Workspace) {
+		for _, w := range found {
+			if !seen[w.Path] {
+				seen[w.Path] = true
+				workspaces = append(workspaces, w)
+			}
+		}
+	}
+
+	patterns, err := npmPatterns(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return nil, err
+	}
+	add(expand(dir, patterns, packageDirs, KindNPM))
+
+	patterns, err = pnpmPatterns(filepath.Join(dir, "pnpm-workspace.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	add(expand(dir, patterns, packageDirs, KindPNPM))
+
+	uses, err := goWorkUses(filepath.Join(dir, "go.work"))
+	if err != nil {
+		return nil, err
+	}
+	for _, use := range uses {
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(use), "go.mod")); err != nil {
+			continue
+		}
+		add([]Workspace{{Name: goModuleName(filepath.Join(dir, filepath.FromSlash(use), "go.mod"), use), Path: use, Kind: KindGo}})
+	}
+
+	sort.Slice(workspaces, func(i, j int) bool { return workspaces[i].Path < workspaces[j].Path })
+	return workspaces, nil
+}
+
+// Find returns the innermost workspace containing file, so a package nested
+// in another is credited with its own files.
+func Find(workspaces []Workspace, file string) (Workspace, bool) {
+	var found Workspace
+	ok := false
+	for _, w := range workspaces {
+		if w.Contains(file) && (!ok || len(w.Path) > len(found.Path) || found.Path == ".") {
+			found, ok = w, true
+		}
+	}
+	return found, ok
+}
+
+// expand returns the package directories matched by patterns. Patterns
+// starting with ! exclude the directories they match.
+func expand(dir string, patterns, packageDirs []string, kind string) []Workspace {
+	var include, exclude []string
+	for _, pattern := range patterns {
+		if negated, ok := strings.CutPrefix(pattern, "!"); ok {
+			exclude = append(exclude, cleanPattern(negated))
+		} else {
+			include = append(include, cleanPattern(pattern))
+		}
+	}
+
+	var workspaces []Workspace
+	for _, packageDir := range packageDirs {
+		if matchAny(include, packageDir) && !matchAny(exclude, packageDir) {
+			workspaces = append(workspaces, Workspace{
+				Name: packageName(filepath.Join(dir, filepath.FromSlash(packageDir), "package.json"), packageDir),
+				Path: packageDir,
+				Kind: kind,
+			})
+		}
+	}
+	return workspaces
+}
+
+func cleanPattern(pattern string) string {
+	return strings.TrimSuffix(path.Clean(strings.TrimPrefix(pattern, "./")), "/")
+}
+
+func matchAny(patterns []string, dir string) bool {
+	for _, pattern := range patterns {
+		// The root is only a package when listed as ".", never through *
+		if dir == "." {
+			if pattern == "." {
+				return true
+			}
+			continue
+		}
+		if match(strings.Split(pattern, "/"), strings.Split(dir, "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// match reports whether the path segments match the pattern segments, where
+// ** matches any number of segments and the others follow path.Match.
+func match(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if match(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+		return false
+	}
+	return match(pattern[1:], segments[1:])
+}
+
+// npmPatterns reads the workspace patterns of the package.json at name,
+// which npm and Yarn 1 list in "workspaces" or Yarn in
+// "workspaces.packages".
+func npmPatterns(name string) ([]string, error) {
+	data, err := os.ReadFile(name)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read package.json: %w", err)
+	}
+
+	var manifest struct {
+		Workspaces json.RawMessage `json:"workspaces"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse package.json: %w", err)
+	}
+	if len(manifest.Workspaces) == 0 {
+		return nil, nil
+	}
+
+	var patterns []string
+	if json.Unmarshal(manifest.Workspaces, &patterns) == nil {
+		return patterns, nil
+	}
+	var yarn struct {
+		Packages []string `json:"packages"`
+	}
+	if err := json.Unmarshal(manifest.Workspaces, &yarn); err != nil {
+		return nil, fmt.Errorf("failed to parse workspaces in package.json: %w", err)
+	}
+	return yarn.Packages, nil
+}
+
+// pnpmPatterns reads the packages list of a pnpm-workspace.yaml file. Only
+// that list is parsed, in block or flow style.
+func pnpmPatterns(name string) ([]string, error) {
+	file, err := os.Open(name)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pnpm-workspace.yaml: %w", err)
+	}
+	defer file.Close()
+
+	var patterns []string
+	inPackages := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := stripYAMLComment(scanner.Text())
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+
+		// A key at the top level starts a new section
+		if !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "-") {
+			key, value, _ := strings.Cut(trimmed, ":")
+			inPackages = strings.TrimSpace(key) == "packages"
+			// packages: [a, b]
+			if value = strings.TrimSpace(value); inPackages && strings.HasPrefix(value, "[") {
+				for _, item := range strings.Split(strings.Trim(value, "[]"), ",") {
+					if item = unquote(item); item != "" {
+						patterns = append(patterns, item)
+					}
+				}
+				inPackages = false
+			}
+			continue
+		}
+
+		if item, ok := strings.CutPrefix(trimmed, "-"); inPackages && ok {
+			if item = unquote(item); item != "" {
+				patterns = append(patterns, item)
+			}
+		}
+	}
+	return patterns, scanner.Err()
+}
+
+func stripYAMLComment(line string) string {
+	// A # only starts a comment at the start or after a space
+	if strings.HasPrefix(line, "#") {
+		return ""
+	}
+	if i := strings.Index(line, " #"); i >= 0 {
+		return line[:i]
+	}
+	return line
+}
+
+func unquote(value string) string {
+	return strings.Trim(strings.TrimSpace(value), `"'`)
+}
+
+// goWorkUses reads the directories a go.work file uses, as cleaned
+// slash-separated paths relative to it. Directories outside the repository
+// are left out.
+func goWorkUses(name string) ([]string, error) {
+	file, err := os.Open(name)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read go.work: %w", err)
+	}
+	defer file.Close()
+
+	var uses []string
+	inBlock := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+
+		var use string
+		switch {
+		case inBlock && len(fields) == 1 && fields[0] == ")":
+			inBlock = false
+			continue
+		case inBlock && len(fields) == 1:
+			use = fields[0]
+		case len(fields) == 2 && fields[0] == "use" && fields[1] == "(":
+			inBlock = true
+			continue
+		case len(fields) == 2 && fields[0] == "use":
+			use = fields[1]
+		default:
+			continue
+		}
+
+		use = path.Clean(unquote(use))
+		if use == ".." || strings.HasPrefix(use, "../") || path.IsAbs(use) {
+			continue
+		}
+		uses = append(uses, use)
+	}
+	return uses, scanner.Err()
+}
+
+// packageName returns the name in the package.json at name, or fallback.
+func packageName(name, fallback string) string {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return fallback
+	}
+	var manifest struct {
+		Name string `json:"name"`
+	}
+	if json.Unmarshal(data, &manifest) != nil || manifest.Name == "" {
+		return fallback
+	}
+	return manifest.Name
+}
+
+// goModuleName returns the module path in the go.mod at name, or fallback.
+func goModuleName(name, fallback string) string {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return fallback
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if module, ok := strings.CutPrefix(strings.TrimSpace(line), "module "); ok {
+			return unquote(module)
+		}
+	}
+	return fallback
+}
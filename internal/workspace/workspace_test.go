@@ -0,0 +1,151 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeFiles writes files, keyed by slash-separated path, under a new
+// directory and returns it with the paths.
+func writeFiles(t *testing.T, files map[string]string) (string, []string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	var paths []string
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, name)
+	}
+	return dir, paths
+}
+
+func TestDiscoverNPM(t *testing.T) {
+	dir, files := writeFiles(t, map[string]string{
+		"package.json":                        `{"name": "root", "workspaces": ["packages/*", "apps/**", "!packages/legacy"]}`,
+		"packages/ui/package.json":            `{"name": "@acme/ui"}`,
+		"packages/legacy/package.json":        `{"name": "@acme/legacy"}`,
+		"packages/ui/src/button.js":           "",
+		"apps/web/package.json":               `{"name": "web"}`,
+		"apps/mobile/ios/package.json":        `{}`,
+		"tools/package.json":                  `{"name": "tools"}`,
+		"packages/ui/nested/lib/package.json": `{"name": "not-matched"}`,
+	})
+
+	workspaces, err := Discover(dir, files)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The root is not a package of its own, and a package without a name
+	// is named after its directory
+	expected := []Workspace{
+		{Name: "apps/mobile/ios", Path: "apps/mobile/ios", Kind: KindNPM},
+		{Name: "web", Path: "apps/web", Kind: KindNPM},
+		{Name: "@acme/ui", Path: "packages/ui", Kind: KindNPM},
+	}
+	if !reflect.DeepEqual(workspaces, expected) {
+		t.Errorf("Expected %+v, but got %+v", expected, workspaces)
+	}
+}
+
+func TestDiscoverYarnPackages(t *testing.T) {
+	dir, files := writeFiles(t, map[string]string{
+		"package.json":           `{"workspaces": {"packages": ["libs/*"], "nohoist": ["**/react"]}}`,
+		"libs/core/package.json": `{"name": "core"}`,
+	})
+
+	workspaces, err := Discover(dir, files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(workspaces) != 1 || workspaces[0].Name != "core" {
+		t.Errorf("Expected the core package, but got %+v", workspaces)
+	}
+}
+
+func TestDiscoverPNPMAndGo(t *testing.T) {
+	dir, files := writeFiles(t, map[string]string{
+		"pnpm-workspace.yaml":           "# workspace\npackages:\n  - 'packages/*'\n  - \"!packages/private\" # skipped\ncatalog:\n  react: ^18\n",
+		"packages/a/package.json":       `{"name": "a"}`,
+		"packages/private/package.json": `{"name": "private"}`,
+		"go.work":                       "go 1.22\n\nuse (\n\t./services/api // the API\n\t./missing\n\t../outside\n)\nuse ./tools\n",
+		"services/api/go.mod":           "module example.com/api\n\ngo 1.22\n",
+		"tools/go.mod":                  "module example.com/tools\n",
+	})
+
+	workspaces, err := Discover(dir, files)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Directories without go.mod, or outside the repository, are left out
+	expected := []Workspace{
+		{Name: "a", Path: "packages/a", Kind: KindPNPM},
+		{Name: "example.com/api", Path: "services/api", Kind: KindGo},
+		{Name: "example.com/tools", Path: "tools", Kind: KindGo},
+	}
+	if !reflect.DeepEqual(workspaces, expected) {
+		t.Errorf("Expected %+v, but got %+v", expected, workspaces)
+	}
+}
+
+func TestDiscoverPNPMFlowList(t *testing.T) {
+	patterns, err := pnpmPatterns(writeManifest(t, "packages: ['apps/*', \"libs/*\"]\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(patterns, []string{"apps/*", "libs/*"}) {
+		t.Errorf("Expected both flow-style patterns, but got %v", patterns)
+	}
+}
+
+func writeManifest(t *testing.T, content string) string {
+	t.Helper()
+
+	name := filepath.Join(t.TempDir(), "pnpm-workspace.yaml")
+	if err := os.WriteFile(name, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return name
+}
+
+func TestDiscoverWithoutManifests(t *testing.T) {
+	dir, files := writeFiles(t, map[string]string{"package.json": `{"name": "app"}`, "main.js": ""})
+
+	workspaces, err := Discover(dir, files)
+	if err != nil || len(workspaces) != 0 {
+		t.Errorf("Expected no workspaces, but got %+v (error %v)", workspaces, err)
+	}
+
+	bad, files := writeFiles(t, map[string]string{"package.json": "{"})
+	if _, err := Discover(bad, files); err == nil {
+		t.Error("Expected an invalid package.json to fail")
+	}
+}
+
+func TestFind(t *testing.T) {
+	workspaces := []Workspace{{Path: "."}, {Path: "packages/ui"}, {Path: "packages/ui/icons"}, {Path: "packages/uikit"}}
+
+	for file, expected := range map[string]string{
+		"README.md":                  ".",
+		"packages/ui/button.js":      "packages/ui",
+		"packages/ui/icons/star.svg": "packages/ui/icons",
+		"packages/uikit/index.js":    "packages/uikit",
+	} {
+		if found, ok := Find(workspaces, file); !ok || found.Path != expected {
+			t.Errorf("Expected %s to be in %s, but got %+v", file, expected, found)
+		}
+	}
+
+	if _, ok := Find(workspaces[1:], "README.md"); ok {
+		t.Error("Expected a file outside every workspace not to be found")
+	}
+}
@@ -1,28 +1,39 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
-	"log"
+	"io"
+	"io/fs"
+	"log/slog"
+	"math"
+	"net"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"strconv"
 	"strings"
-	"sync"
 	"time"
 
-	"codecompass/internal/analyzer"
-	"codecompass/internal/config"
-	"codecompass/internal/eslint"
-	"codecompass/internal/git"
-	"codecompass/internal/history"
-	"codecompass/internal/leaderboard"
-	"codecompass/internal/types"
-	"codecompass/internal/utils"
-
-	"codecompass/internal/ruff"
+	"github.com/xeon-zolt/codecompass/internal/cerrors"
+	"github.com/xeon-zolt/codecompass/internal/checkstyle"
+	"github.com/xeon-zolt/codecompass/internal/config"
+	"github.com/xeon-zolt/codecompass/internal/git"
+	"github.com/xeon-zolt/codecompass/internal/history"
+	"github.com/xeon-zolt/codecompass/internal/lang"
+	"github.com/xeon-zolt/codecompass/internal/leaderboard"
+	"github.com/xeon-zolt/codecompass/internal/lint"
+	"github.com/xeon-zolt/codecompass/internal/logging"
+	reporting "github.com/xeon-zolt/codecompass/internal/report"
+	"github.com/xeon-zolt/codecompass/internal/server"
+	"github.com/xeon-zolt/codecompass/internal/toolversion"
+	"github.com/xeon-zolt/codecompass/internal/types"
+	"github.com/xeon-zolt/codecompass/internal/utils"
+	"github.com/xeon-zolt/codecompass/pkg/compass"
 
 	"github.com/charmbracelet/lipgloss"
-	"github.com/schollz/progressbar/v3"
 )
 
 const VERSION = "1.0.0"
@@ -45,45 +56,45 @@ const MINI_COMPASS = "🧭"
 var (
 	// Styles for various elements
 	titleStyle = lipgloss.NewStyle().
-		Bold(true).
-		Foreground(lipgloss.Color("#FAFAFA")).
-		Background(lipgloss.Color("#5d5d5d")).
-		PaddingLeft(1).
-		PaddingRight(1)
+			Bold(true).
+			Foreground(lipgloss.Color("#FAFAFA")).
+			Background(lipgloss.Color("#5d5d5d")).
+			PaddingLeft(1).
+			PaddingRight(1)
 
 	headerStyle = lipgloss.NewStyle().
-		Bold(true).
-		Foreground(lipgloss.Color("#FAFAFA"))
+			Bold(true).
+			Foreground(lipgloss.Color("#FAFAFA"))
 
 	infoStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#878787"))
+			Foreground(lipgloss.Color("#878787"))
 
 	successStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#00FF00"))
+			Foreground(lipgloss.Color("#00FF00"))
 
 	warningStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFFF00"))
+			Foreground(lipgloss.Color("#FFFF00"))
 
 	errorStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FF0000"))
+			Foreground(lipgloss.Color("#FF0000"))
 
 	compassArtStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#00FFFF"))
+			Foreground(lipgloss.Color("#00FFFF"))
 
 	logoStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#00FFFF")).
-		Bold(true)
+			Foreground(lipgloss.Color("#00FFFF")).
+			Bold(true)
 
 	versionStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#00FFFF")).
-		Bold(true)
+			Foreground(lipgloss.Color("#00FFFF")).
+			Bold(true)
 
 	usageHeaderStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#0000FF")).
-		Bold(true)
+				Foreground(lipgloss.Color("#0000FF")).
+				Bold(true)
 
 	leaderboardTitleStyle = lipgloss.NewStyle().
-		Bold(true)
+				Bold(true)
 )
 
 func main() {
@@ -97,104 +108,452 @@ func main() {
 		// Leaderboard flags (default to false to be opt-in)
 		showAuthors    = flag.Bool("authors", false, "Show author leaderboard (lint issue contributors)")
 		showFiles      = flag.Bool("files", false, "Show file leaderboard (most problematic files)")
+		filesDetail    = flag.Bool("files-detail", false, "List the top authors of each file in the file leaderboard (implies --files)")
+		filesByOwner   = flag.Bool("files-by-owner", false, "Regroup the file leaderboard into a worklist per owner, from CODEOWNERS or blame (implies --files)")
 		showRules      = flag.Bool("rules", false, "Show rule leaderboard (most violated rules)")
+		showPlugins    = flag.Bool("rule-plugins", false, "Show rule leaderboard rolled up by plugin (@typescript-eslint, react, core...)")
+		showGroups     = flag.Bool("group-rules", false, "Show rule leaderboard rolled up by the rule-group.NAME groups of the config")
+		showRuleAuthor = flag.Bool("rule-author-matrix", false, "Show the top author of each rule and the top rule of each author side by side")
 		showLoc        = flag.Bool("loc", false, "Show lines of code leaderboard")
 		showCommits    = flag.Bool("commits", false, "Show regular commit count leaderboard (non-merges)")
 		showMerges     = flag.Bool("merges", false, "Show merge commit count leaderboard")
 		showRecent     = flag.Bool("recent", false, "Show recent contributors leaderboard")
+		showNewContrib = flag.Bool("new-contributors", false, "Show the authors whose first commit falls in the --since window, with their commits and the files they touched")
 		showCoverage   = flag.Bool("coverage", false, "Show code coverage leaderboard")
 		showChurn      = flag.Bool("churn", false, "Show code churn leaderboard")
+		showDeletions  = flag.Bool("deletions", false, "Show the authors who removed the most lines in the --since window and the commits that removed the most, not counting renames or vendored and generated files")
+		showHotspots   = flag.Bool("hotspots", false, "Show the files both changed often and with many lint issues, ranked by changes times issues")
+		showChurnRate  = flag.Bool("churn-rate", false, "Show the code churn leaderboard ranked by changes per month since each file was added (same as --churn --sort churn=rate)")
 		showBugs       = flag.Bool("bugs", false, "Show bug density leaderboard")
 		showDebt       = flag.Bool("debt", false, "Show technical debt leaderboard")
 		showComplexity = flag.Bool("complexity", false, "Show code complexity leaderboard")
 		showSummary    = flag.Bool("summary", false, "Show repository summary")
 		showSpellCheck = flag.Bool("spellcheck", false, "Show spell check leaderboard")
 		showRuff       = flag.Bool("ruff", false, "Show Ruff (Python) leaderboard")
+		showDrift      = flag.Bool("format-drift", false, "Show the Python files ruff format and import sorting would rewrite, and the authors of those lines")
+		showEncoding   = flag.Bool("encoding-check", false, "Show files with CRLF or mixed line endings, a BOM, or non-UTF-8 bytes")
+		showLongFuncs  = flag.Bool("long-functions", false, "Show Go, JavaScript and TypeScript functions longer than long-function-lines")
+		showGitHub     = flag.Bool("github-stats", false, "Show merged pull requests and reviews from GitHub or GitLab (needs GITHUB_TOKEN or GITLAB_TOKEN)")
+		showLeadTime   = flag.Bool("lead-time", false, "Show branch lead time and merges per week from the merge commits on HEAD")
+		showChangelog  = flag.Bool("changelog-readiness", false, "Show how commits in the --since window classify as Conventional Commits, with the ones a generated changelog would miss")
+		showTimezones  = flag.Bool("timezones", false, "Show each author's usual UTC offset and the share of commits on weekends and outside 9-18 local time")
+		showLFS        = flag.Bool("lfs", false, "Show Git LFS pattern coverage and files committed as raw blobs instead of LFS pointers")
+		showVulns      = flag.Bool("vulns", false, "Show known vulnerabilities in npm and Python dependencies (runs npm audit and pip-audit)")
+		showDeps       = flag.Bool("deps", false, "Show how many dependencies each lockfile and requirements file declares and how many are pinned, offline")
+		showScore      = flag.Bool("score", false, "Show a weighted quality score for the repository and its worst files")
+		showReportCard = flag.Bool("report-card", false, "Show an overall letter grade for the repository")
+		showDashboard  = flag.Bool("dashboard", false, "Show a one-screen summary: totals with their change since the last logged run, a coverage gauge and the top authors, files, rules, debt and least covered files")
+		byWorkspace    = flag.Bool("by-workspace", false, "Roll the file-based leaderboards up per package of a npm, pnpm or Go workspace, before the detailed boards")
 
 		showAll = flag.Bool("all", false, "Show all leaderboards")
 
 		// Configuration flags
 		topN             = flag.Int("top", 15, "Number of entries to show in leaderboards")
+		outputWidth      = flag.Int("width", 0, "Fit leaderboards to N columns (default: the terminal width, or $COLUMNS)")
+		groupNumbers     = flag.Bool("group-numbers", false, "Group the digits of counts in thousands, such as 12,345, with the separator of the locale")
+		noPager          = flag.Bool("no-pager", false, "Print the leaderboards at once, even when they are taller than the terminal")
 		ignoredRulesFlag = flag.String("ignore", "sort-imports,import/order", "Comma-separated list of rules to ignore")
+		ignoredPrefixes  = flag.String("ignore-rule-prefix", "", "Comma-separated rule prefixes to ignore, such as @typescript-eslint/ or D")
 		coverageFile     = flag.String("coverage-file", "", "Path to coverage file (auto-detected if not specified)")
+		checkstyleFile   = flag.String("checkstyle", "", "Read lint issues from a checkstyle XML report, such as one written by Checkstyle or PMD")
 		configFile       = flag.String("config", "", "Path to configuration file")
 		generateConfig   = flag.Bool("generate-config", false, "Generate a sample configuration file")
 		showConfig       = flag.Bool("show-config", false, "Show current configuration and exit")
+		dumpConfig       = flag.Bool("dump-effective-config", false, "Print the resolved configuration in .codecompass.rc format and exit")
+		includeUntracked = flag.Bool("include-untracked", false, "Add untracked files that are not ignored to the lines of code, debt and spell check leaderboards")
+		includeVendored  = flag.Bool("include-vendored", false, "Keep vendored, third-party and generated files in the leaderboards that read file contents and in --deletions")
+		requireClean     = flag.Bool("require-clean", false, "Refuse to run when the work tree has uncommitted changes or untracked files, for CI")
+		requireCoverage  = flag.Bool("require-coverage", false, "Fail the run when the coverage file is missing or cannot be parsed, rather than leave the coverage leaderboard empty, for CI")
+		changedSinceTag  = flag.Bool("changed-since-tag", false, "Only lint and measure the files changed since the latest tag, and count the commits since it")
+		weighted         = flag.Bool("weighted", false, "Rank authors and files, and score issues, by issues weighted by weight-error, weight-warning and rule-weights")
+		baselineWrite    = flag.String("baseline-write", "", "Save the lint issues, debt markers and misspellings found to this file as the suppression baseline; runs leave those in .codecompass-baseline.json, or --baseline-file, out")
+		baselineFile     = flag.String("baseline-file", "", "Read the suppression baseline from this file instead of .codecompass-baseline.json at the repository root")
+		includeBaselined = flag.Bool("include-baselined", false, "Keep the findings of the suppression baseline, .codecompass-baseline.json, in the leaderboards")
 
 		// Advanced flags
-		enableCache = flag.Bool("cache", true, "Enable caching for better performance")
-		verbose     = flag.Bool("verbose", false, "Enable verbose output")
-		quiet       = flag.Bool("quiet", false, "Suppress non-essential output")
+		enableCache     = flag.Bool("cache", true, "Enable caching for better performance")
+		stateFile       = flag.String("state-file", "", "Save finished leaderboards to this file and reuse them when the run is repeated on unchanged inputs")
+		saveReport      = flag.String("save-report", "", "Write the full report, every computed leaderboard included, to this file as JSON")
+		loadReport      = flag.String("load-report", "", "Show a report written by --save-report instead of analyzing the repository")
+		attentionOut    = flag.String("attention-out", "", "Write the files scoring below the attention-score or attention-component-score thresholds, with the reasons, to this file as JSON (implies computing --score)")
+		warningsOut     = flag.String("warnings-out", "", "Write the warnings of the run, such as the files git blame failed for, to this file as JSON records grouped by type")
+		ownersOut       = flag.String("files-by-owner-out", "", "Write the per-owner worklists of --files-by-owner to this file as Markdown, to paste into each team's channel (implies --files-by-owner)")
+		authorReport    = flag.String("author-report", "", "Comma-separated emails of the authors to write --author-report-dir packets for, the --top authors with the most issues when empty")
+		authorReportDir = flag.String("author-report-dir", "", "Write a Markdown packet per author, with their issues, spelling mistakes, activity and the changes since the last logged run, to this directory (implies --authors, --commits and --recent)")
+		reposFile       = flag.String("repos-file", "", "With multi, the file listing the repositories to analyze, one local path or clone URL per line")
+		parallel        = flag.Int("parallel", 4, "With multi, how many repositories to analyze at once")
+		verbose         = flag.Bool("verbose", false, "Enable verbose output")
+		quiet           = flag.Bool("quiet", false, "Suppress non-essential output")
+		logJSON         = flag.Bool("log-json", false, "Write logs to stderr as JSON lines")
 
 		// History logging flags
-		logHistory = flag.Bool("log-history", false, "Enable logging of leaderboard data to CSV files")
-		logDir     = flag.String("log-dir", ".codecompass/history", "Directory to save leaderboard CSV logs")
+		logHistory    = flag.Bool("log-history", false, "Enable logging of leaderboard data to CSV files")
+		logDir        = flag.String("log-dir", ".codecompass/history", "Directory to save leaderboard CSV logs, relative to the current directory (default: .codecompass/history in the repository)")
+		sanitizeCSV   = flag.Bool("sanitize-csv", true, "Defang spreadsheet formulas in leaderboard CSV logs")
+		timeseriesOut = flag.String("timeseries-out", "", "Write the history in --log-dir to this file as JSON time series for Grafana")
+		runLogFile    = flag.String("run-log", "", "Append a JSON line recording each run to this file, even when it fails (default: runs.log in --log-dir with --log-history)")
+		diffFormat    = flag.String("format", "text", "With history diff, how to print the diff: text, or markdown to paste into a document")
+
+		// Notification flags
+		webhookURL   = flag.String("webhook", "", "POST a JSON summary of the report to this URL when the run completes")
+		webhookToken = flag.String("webhook-token", "", "Bearer token to send with --webhook")
+
+		// Serving flags
+		serveAddr = flag.String("serve", "", "Serve the report as JSON at /report, a dashboard at / and the leaderboards at /leaderboards on this address, such as :8080")
+		serveTTL  = flag.Duration("serve-ttl", 0, "With --serve, analyze again on the first request after this long, such as 10m (default: never)")
 	)
 
-	flag.Usage = showUsage
-	flag.Parse()
+	// Empty means the date type from the config file
+	var dateType string
+	flag.Func("date-type", "Commit timestamps to use: author or commit", func(value string) error {
+		_, err := git.ParseDateType(value)
+		dateType = value
+		return err
+	})
+
+	// Empty means the co-author credit from the config file
+	var coAuthorCredit string
+	flag.Func("co-author-credit", "How commit counts credit Co-authored-by trailers: full, split or none", func(value string) error {
+		_, err := git.ParseCoAuthorCredit(value)
+		coAuthorCredit = value
+		return err
+	})
+
+	// Nil means the half-life from the config file
+	var decayHalfLifeDays *float64
+	flag.Func("decay-halflife-days", "Weight each lint issue by the age of its line, halving every N days (0 disables)", func(value string) error {
+		days, err := strconv.ParseFloat(value, 64)
+		if err != nil || days < 0 {
+			return fmt.Errorf("invalid half-life %q, expected a number of days of 0 or more", value)
+		}
+		decayHalfLifeDays = &days
+		return nil
+	})
+
+	// Zero means the default --github-stats, --lead-time and
+	// --changelog-readiness window
+	var since time.Time
+	flag.Func("since", "Start of the --github-stats, --lead-time, --changelog-readiness, --deletions and --new-contributors window: 30d, 12w or YYYY-MM-DD (default: 90d)", func(value string) (err error) {
+		since, err = utils.ParseSince(value, time.Now())
+		return err
+	})
+
+	// The two refs of --compare-branches
+	var compareRefs []string
+	flag.Func("compare-branches", "Compare coverage, lint issues, debt and lines of code between two refs: REF_A,REF_B", func(value string) error {
+		refA, refB, found := strings.Cut(value, ",")
+		refA, refB = strings.TrimSpace(refA), strings.TrimSpace(refB)
+		if !found || refA == "" || refB == "" || strings.Contains(refB, ",") {
+			return errors.New("expected two refs separated by a comma, such as main,feature")
+		}
+		compareRefs = []string{refA, refB}
+		return nil
+	})
+
+	// The columns the file, churn and spell check leaderboards are sorted
+	// by; --sort takes LEADERBOARD=COLUMN so other leaderboards can gain
+	// sort columns
+	fileSort := compass.FileSortIssues
+	churnSort := compass.ChurnSortChanges
+	spellCheckSort := leaderboard.SpellCheckSortErrors
+	flag.Func("sort", "Sort a leaderboard by another column: files=issues (default), files=errors, files=warnings, churn=changes (default), churn=rate, spellcheck=errors (default) or spellcheck=rate", func(value string) error {
+		for _, spec := range strings.Split(value, ",") {
+			board, column, found := strings.Cut(strings.TrimSpace(spec), "=")
+			var err error
+			switch {
+			case found && board == string(compass.LeaderboardFiles):
+				fileSort, err = leaderboard.ParseFileSort(column)
+			case found && board == string(compass.LeaderboardChurn):
+				churnSort, err = leaderboard.ParseChurnSort(column)
+			case found && board == string(compass.LeaderboardSpellCheck):
+				spellCheckSort, err = leaderboard.ParseSpellCheckSort(column)
+			default:
+				return fmt.Errorf("invalid sort %q: expected files=COLUMN, churn=COLUMN or spellcheck=COLUMN, such as files=errors", spec)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	decorations := decorationsAuto
+	flag.Func("decorations", "Print the compass art and the compass on section headings: auto (only when stdout is a terminal), on or off (default: auto)", func(value string) (err error) {
+		decorations, err = parseDecorationMode(value)
+		return err
+	})
+
+	// Nil analyzes the files of every language
+	var languages []string
+	flag.Func("lang", "Comma-separated languages to restrict the linters and file-based leaderboards to, such as js,python,go", func(value string) (err error) {
+		languages, err = lang.Parse(value)
+		return err
+	})
+
+	// Nil analyzes files of every extension
+	var onlyExtensions, excludeExtensions []string
+	flag.Func("only-ext", "Comma-separated file extensions to restrict the linters and file-based leaderboards to, such as ts,tsx", func(value string) (err error) {
+		onlyExtensions, err = utils.ParseExtensions(value)
+		return err
+	})
+	flag.Func("exclude-ext", "Comma-separated file extensions to leave out of the linters and file-based leaderboards, such as md,json; wins over --only-ext", func(value string) (err error) {
+		excludeExtensions, err = utils.ParseExtensions(value)
+		return err
+	})
+
+	pathStyle := leaderboard.PathFull
+	flag.Func("path-style", "How file leaderboards show paths: full, basename or truncate (default: full)", func(value string) (err error) {
+		pathStyle, err = leaderboard.ParsePathStyle(value)
+		return err
+	})
+
+	// The conditions of --fail-on, checked once the run is done
+	var gates []compass.Gate
+	flag.Func("fail-on", "Exit with status 7 when a condition holds: METRIC<N, <=, > or >=, comma-separated, such as score<70; coverage alone fails below min-coverage-threshold, coverage-age alone above coverage-max-age, new-issues alone on any new issue", func(value string) (err error) {
+		gates, err = compass.ParseGates(value)
+		return err
+	})
+
+	// --baseline write accepts the issues found as the baseline later runs
+	// tell new issues apart from
+	var writeBaseline bool
+	flag.Func("baseline", "write: save the fingerprints of the issues found to --log-dir as the accepted baseline", func(value string) error {
+		if value != "write" {
+			return fmt.Errorf("unknown baseline command %q: expected write", value)
+		}
+		writeBaseline = true
+		return nil
+	})
+
+	// The leaderboards history diff compares, every one both runs logged
+	// when empty
+	var diffLeaderboards []string
+	flag.Func("leaderboard", "With history diff, comma-separated leaderboards to compare, such as files,debt (default: every one both runs logged)", func(value string) error {
+		diffLeaderboards = nil
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				diffLeaderboards = append(diffLeaderboards, name)
+			}
+		}
+		return nil
+	})
+
+	flag.Usage = func() { showUsage(os.Stdout) }
+
+	// codecompass multi --repos-file FILE analyzes several repositories and
+	// combines their reports; the flags after it are parsed as usual
+	arguments := os.Args[1:]
+	multi := len(arguments) > 0 && arguments[0] == "multi"
+	if multi {
+		arguments = arguments[1:]
+	}
+	// codecompass history diff RUN_A RUN_B compares two runs logged in
+	// --log-dir
+	historyDiff := len(arguments) > 1 && arguments[0] == "history" && arguments[1] == "diff"
+	// codecompass history runs prints the runs recorded in the run log
+	historyRuns := len(arguments) > 1 && arguments[0] == "history" && arguments[1] == "runs"
+	if historyDiff || historyRuns {
+		arguments = arguments[2:]
+	}
+	positional := parseArguments(flag.CommandLine, arguments)
+
+	logger := newLogger(*verbose, *quiet, *logJSON)
+	status := statusReporter{logger: logger, json: *logJSON, quiet: *quiet}
 
 	if *help || *h {
-		showUsage()
+		showUsage(os.Stdout)
 		return
 	}
 
 	if *version || *v {
-		showVersion()
+		showVersion(os.Stdout)
 		return
 	}
 
 	if *showLogo {
-		showCompassArt()
+		showCompassArt(os.Stdout)
 		return
 	}
 
 	if *generateConfig {
 		filename := ".codecompass.rc"
 		if err := config.GenerateConfigFile(filename); err != nil {
-			log.Fatalf("Failed to generate config file: %v", err)
+			fatal(logger, "Failed to generate config file", "file", filename, "error", err)
+		}
+		status.Info(fmt.Sprintf("✅ Generated configuration file: %s\n", filename), "Generated configuration file", "file", filename)
+		return
+	}
+
+	if historyDiff {
+		if len(positional) != 2 {
+			usageError(fmt.Sprintf("history diff expects two runs, got %d", len(positional)))
+		}
+		if *diffFormat != "text" && *diffFormat != "markdown" {
+			usageError(fmt.Sprintf("invalid format %q: expected text or markdown", *diffFormat))
+		}
+		resolvePathFlags("", false, logDir, coverageFile)
+		if err := showHistoryDiff(*logDir, positional[0], positional[1], diffLeaderboards, *diffFormat, *topN, *outputWidth); err != nil {
+			fatalError(logger, "Failed to compare runs", err, "dir", *logDir)
+		}
+		return
+	}
+
+	if historyRuns {
+		if len(positional) > 0 {
+			usageError(fmt.Sprintf("history runs takes no arguments, got %s", strings.Join(positional, " ")))
+		}
+		resolvePathFlags("", false, logDir, coverageFile)
+		path := *runLogFile
+		if path == "" {
+			path = filepath.Join(*logDir, history.RunLogFile)
+		}
+		records, err := history.ReadRunLog(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			fatal(logger, "No runs logged; record them with --log-history or --run-log", "file", path)
+		} else if err != nil {
+			fatal(logger, "Failed to read run log", "file", path, "error", err)
 		}
-		fmt.Printf("✅ Generated configuration file: %s\n", filename)
+		printer := leaderboard.NewPrinter(os.Stdout)
+		printer.SetWidth(utils.OutputWidth(os.Stdout, *outputWidth))
+		printer.PrintRunLog(records, *topN)
 		return
 	}
 
+	// The repository to analyze, the current directory when empty
+	var repoPath string
+	switch {
+	case multi && len(positional) > 0:
+		usageError("multi takes no directory, list the repositories in --repos-file instead")
+	case len(positional) > 1:
+		usageError(fmt.Sprintf("expected one directory, got %d: %s", len(positional), strings.Join(positional, " ")))
+	case len(positional) == 1:
+		repoPath = positional[0]
+	}
+	resolvePathFlags(repoPath, compareRefs != nil, logDir, coverageFile)
+
+	// The run is recorded however it ends: fatal and exit append the
+	// record before exiting, and returning or panicking appends it here
+	if *runLogFile != "" || *logHistory {
+		path := *runLogFile
+		if path == "" {
+			path = filepath.Join(*logDir, history.RunLogFile)
+		}
+		runLog = startRunLog(logger, path, repoPath)
+		defer func() {
+			if r := recover(); r != nil {
+				runLog.finish(2, fmt.Sprint(r))
+				panic(r)
+			}
+			runLog.finish(0, "")
+		}()
+	}
+
 	if *showAll {
 		*showAuthors = true
 		*showFiles = true
 		*showRules = true
+		*showPlugins = true
 		*showLoc = true
 		*showCommits = true
 		*showMerges = true
 		*showRecent = true
+		*showNewContrib = true
 		*showCoverage = true
 		*showChurn = true
+		*showHotspots = true
+		*showDeletions = true
 		*showBugs = true
 		*showDebt = true
 		*showComplexity = true
 		*showSummary = true
 		*showSpellCheck = true
 		*showRuff = true
+		*showDrift = true
+		*showEncoding = true
+		*showLongFuncs = true
+		*showLFS = true
+		*showDeps = true
+		*showLeadTime = true
+		*showChangelog = true
+		*showTimezones = true
+		*showScore = true
+		*showReportCard = true
+		*byWorkspace = true
 	}
 
-	// Check if any action was requested by the user.
-	actionRequested := *showAuthors || *showFiles || *showRules || *showLoc ||
-		*showCommits || *showMerges || *showRecent || *showCoverage || *showChurn ||
-		*showBugs || *showDebt || *showComplexity || *showSummary || *showSpellCheck || *showRuff ||
-		*showConfig
+	if *ownersOut != "" {
+		*filesByOwner = true
+	}
+	if *showChurnRate {
+		*showChurn = true
+		churnSort = compass.ChurnSortRate
+	}
+	if *authorReport != "" && *authorReportDir == "" {
+		fatal(logger, "--author-report needs --author-report-dir")
+	}
+	for _, gate := range gates {
+		if gate.Metric == "weighted-issues" && !*weighted {
+			fatal(logger, "--fail-on weighted-issues needs --weighted")
+		}
+	}
+	if *authorReportDir != "" {
+		*showAuthors = true
+		*showCommits = true
+		*showRecent = true
+	}
+	if *filesDetail || *filesByOwner {
+		*showFiles = true
+	}
 
-	// Check if Ruff-based leaderboards are needed
-	needsRuff := *showRuff
+	// Check if any action was requested by the user.
+	leaderboardRequested := *showAuthors || *showFiles || *showRules || *showPlugins || *showGroups || *showRuleAuthor || *showLoc ||
+		*showCommits || *showMerges || *showRecent || *showNewContrib || *showCoverage || *showChurn || *showHotspots || *showDeletions ||
+		*showBugs || *showDebt || *showComplexity || *showSummary || *showSpellCheck || *showRuff || *showDrift ||
+		*showEncoding || *showLongFuncs || *showGitHub || *showLeadTime || *showChangelog || *showTimezones || *showVulns || *showDeps || *showLFS || *showScore || *showReportCard || *byWorkspace || *showDashboard || len(gates) > 0
+	// The page served shows the dashboard and the lint leaderboards unless
+	// others are asked for
+	if *serveAddr != "" && !leaderboardRequested {
+		*showAuthors, *showFiles, *showRules, *showSummary, *showDashboard = true, true, true, true, true
+		leaderboardRequested = true
+	}
+	actionRequested := leaderboardRequested || *showConfig || *dumpConfig || *timeseriesOut != "" || *attentionOut != "" || *baselineWrite != "" || compareRefs != nil || *loadReport != "" || multi
 
 	// If no action is specified, show usage information and exit.
 	if !actionRequested && len(flag.Args()) == 0 {
-		showUsage()
+		showUsage(os.Stdout)
 		return
 	}
 
-	if !*quiet {
+	// The effective config dump must be a valid rc file on stdout
+	if *dumpConfig {
+		*quiet = true
+		status.quiet = true
+	}
+
+	// Leaderboards are fitted to the terminal, or to --width when piped
+	width := utils.OutputWidth(os.Stdout, *outputWidth)
+	// and cut short on a terminal, where the full list cannot be read back
+	maxRows := 0
+	if isTerminal(os.Stdout) {
+		maxRows = terminalMaxRows
+	}
+
+	// Piped output and CI logs get no art; JSON logs keep stdout for the
+	// leaderboards alone
+	decorated := decorate(decorations, os.Stdout)
+	if decorated && !*quiet && !*logJSON {
 		fmt.Print(compassArtStyle.Render(COMPASS_ART))
 	}
 
+	// Section headings carry the compass when decorated
+	heading := func(title string) {
+		if decorated {
+			fmt.Printf("\n%s %s", MINI_COMPASS, title)
+		} else {
+			fmt.Printf("\n%s", title)
+		}
+	}
+
 	// Load configuration
 	var cfg *config.Config
 	var err error
@@ -202,20 +561,29 @@ func main() {
 	if *configFile != "" {
 		cfg, err = config.LoadConfigFromFile(*configFile)
 		if err != nil {
-			log.Fatalf("Failed to load config file %s: %v", *configFile, err)
+			fatalError(logger, "Failed to load config file", err, "file", *configFile)
+		}
+	} else if file := config.FindConfigFile(); file != "" {
+		status.Info(fmt.Sprintf("🧭 Using config file: %s\n", file), "Using config file", "file", file)
+		cfg, err = config.LoadConfigFromFile(file)
+		if err != nil && *dumpConfig {
+			// Dumping the defaults would pass them off as the effective config
+			fatalError(logger, "Failed to load config file", err, "file", file)
 		}
-	} else {
-		cfg, err = config.LoadConfig()
 		if err != nil {
-			if !*quiet {
-				fmt.Printf("Warning: Failed to load config: %s\n", warningStyle.Render(err.Error()))
+			// Valid keys still apply; say which ones were dropped even under
+			// --quiet so the run is not silently using different settings.
+			status.Warn(fmt.Sprintf("Warning: Ignoring invalid config in %s:\n%s\n", file, warningStyle.Render(err.Error())), "Ignoring invalid config", err, "file", file)
+			if cfg == nil {
+				cfg = config.NewConfig()
 			}
-			cfg = config.NewConfig()
 		}
+	} else {
+		cfg = config.NewConfig()
 	}
 
 	if *showConfig {
-		cfg.PrintSummary()
+		cfg.PrintSummary(os.Stdout)
 		return
 	}
 
@@ -223,189 +591,608 @@ func main() {
 	if *enableCache {
 		cfg.CacheResults = *enableCache
 	}
+	if dateType != "" {
+		cfg.DateType = dateType
+	}
+	if coAuthorCredit != "" {
+		cfg.CoAuthorCredit = coAuthorCredit
+	}
+	if decayHalfLifeDays != nil {
+		cfg.DecayHalfLifeDays = *decayHalfLifeDays
+	}
+	compass.ResolveGates(gates, cfg)
+	runLog.setConfig(cfg)
 
 	// Parse ignored rules from both config and command line
-	ignoredRules := cfg.IgnoredRules
+	var cmdIgnoredRules []string
 	if *ignoredRulesFlag != "" {
-		cmdIgnoredRules := strings.Split(*ignoredRulesFlag, ",")
+		cmdIgnoredRules = strings.Split(*ignoredRulesFlag, ",")
 		for i, rule := range cmdIgnoredRules {
 			cmdIgnoredRules[i] = strings.TrimSpace(rule)
 		}
-		ignoredRules = append(ignoredRules, cmdIgnoredRules...)
 	}
-
-	// Handle positional arguments (directory path)
-	args := flag.Args()
-	if len(args) > 0 {
-		targetDir := args[0]
-		absPath, err := filepath.Abs(targetDir)
-		if err != nil {
-			log.Fatalf("Failed to resolve path %s: %v", targetDir, err)
+	ignoredRules := append(append([]string{}, cfg.IgnoredRules...), cmdIgnoredRules...)
+	// An empty prefix would ignore every rule
+	var cmdIgnoredPrefixes []string
+	for _, prefix := range strings.Split(*ignoredPrefixes, ",") {
+		if prefix = strings.TrimSpace(prefix); prefix != "" {
+			cmdIgnoredPrefixes = append(cmdIgnoredPrefixes, prefix)
 		}
+	}
 
-		if _, err := os.Stat(absPath); os.IsNotExist(err) {
-			log.Fatalf("Directory does not exist: %s", absPath)
+	if *dumpConfig {
+		effective := *cfg
+		effective.IgnoredRules = ignoredRules
+		effective.IgnoredRulePrefixes = append(append([]string{}, cfg.IgnoredRulePrefixes...), cmdIgnoredPrefixes...)
+		if err := effective.WriteEffective(os.Stdout); err != nil {
+			fatal(logger, "Failed to write effective config", "error", err)
 		}
+		return
+	}
 
-		if err := os.Chdir(absPath); err != nil {
-			log.Fatalf("Failed to change to directory %s: %v", absPath, err)
+	exportTimeSeries := func() {
+		series, err := history.ReadTimeSeries(*logDir, cfg.TimeSeriesMetrics)
+		if err == nil {
+			err = history.WriteTimeSeries(*timeseriesOut, series)
 		}
+		if err != nil {
+			status.Warn(fmt.Sprintf("❌ Failed to export time series: %s\n", errorStyle.Render(err.Error())), "Failed to export time series", err, "dir", *logDir)
+		} else {
+			status.Info(fmt.Sprintf("✅ %d time series written to %s\n", len(series), successStyle.Render(*timeseriesOut)), "Time series written", "file", *timeseriesOut, "series", len(series))
+		}
+	}
 
-		if !*quiet {
-			fmt.Printf("%s Analyzing repository in: %s\n", MINI_COMPASS, absPath)
+	// Without leaderboards there is no run to log, only history to export
+	if *timeseriesOut != "" && !leaderboardRequested {
+		exportTimeSeries()
+		return
+	}
+
+	// Every analysis runs git, so say it is missing up front rather than
+	// with the first command that fails; a saved report needs none
+	if *loadReport == "" {
+		if err := utils.RequireTool("git"); err != nil {
+			fatalError(logger, "git is required but not found", err)
 		}
 	}
 
-	// Validate git repository
-	if err := git.ValidateRepository(); err != nil {
-		log.Fatal("Not in a git repository. Please run from within a git repository or specify a valid git repository path.")
+	if repoPath != "" {
+		if absPath, err := filepath.Abs(repoPath); err == nil {
+			status.Info(fmt.Sprintf("%s Analyzing repository in: %s\n", MINI_COMPASS, absPath), "Analyzing repository", "path", absPath)
+		}
 	}
 
 	// Show configuration summary if verbose
-	if *verbose && !*quiet {
-		cfg.PrintSummary()
+	if *verbose && !*quiet && !*logJSON {
+		cfg.PrintSummary(os.Stdout)
 		fmt.Println()
 	}
 
-	// Get tracked files
-	trackedFiles, err := git.GetTrackedFiles()
-	if err != nil {
-		log.Fatal("Failed to get tracked files:", err)
+	shown := map[compass.Leaderboard]*bool{
+		compass.LeaderboardAuthors:     showAuthors,
+		compass.LeaderboardFiles:       showFiles,
+		compass.LeaderboardRules:       showRules,
+		compass.LeaderboardRulePlugins: showPlugins,
+		compass.LeaderboardRuleGroups:  showGroups,
+		compass.LeaderboardRuleAuthors: showRuleAuthor,
+		compass.LeaderboardLinesOfCode: showLoc,
+		compass.LeaderboardCommits:     showCommits,
+		compass.LeaderboardRecent:      showRecent,
+		compass.LeaderboardNewContrib:  showNewContrib,
+		compass.LeaderboardCoverage:    showCoverage,
+		compass.LeaderboardChurn:       showChurn,
+		compass.LeaderboardHotspots:    showHotspots,
+		compass.LeaderboardDeletions:   showDeletions,
+		compass.LeaderboardBugs:        showBugs,
+		compass.LeaderboardDebt:        showDebt,
+		compass.LeaderboardSummary:     showSummary,
+		compass.LeaderboardSpellCheck:  showSpellCheck,
+		compass.LeaderboardRuff:        showRuff,
+		compass.LeaderboardFormatDrift: showDrift,
+		compass.LeaderboardEncoding:    showEncoding,
+		compass.LeaderboardLongFuncs:   showLongFuncs,
+		compass.LeaderboardGitHub:      showGitHub,
+		compass.LeaderboardLeadTime:    showLeadTime,
+		compass.LeaderboardChangelog:   showChangelog,
+		compass.LeaderboardTimezones:   showTimezones,
+		compass.LeaderboardVulns:       showVulns,
+		compass.LeaderboardDeps:        showDeps,
+		compass.LeaderboardLFS:         showLFS,
+		compass.LeaderboardScore:       showScore,
+		compass.LeaderboardReportCard:  showReportCard,
+		compass.LeaderboardWorkspaces:  byWorkspace,
 	}
-
-	// Filter tracked files based on config
-	filteredFiles := make(map[string]bool)
-	for file := range trackedFiles {
-		if !cfg.ShouldIgnoreFile(file) {
-			filteredFiles[file] = true
+	selected := make(map[compass.Leaderboard]bool, len(shown))
+	for lb, show := range shown {
+		selected[lb] = *show
+	}
+	// Gates need their metric measured even when it is not shown
+	for _, gate := range gates {
+		selected[gate.Leaderboard()] = true
+	}
+	if *attentionOut != "" {
+		selected[compass.LeaderboardScore] = true
+	}
+	// The dashboard summarizes leaderboards it does not show in full
+	if *showDashboard {
+		for _, lb := range compass.DashboardLeaderboards() {
+			selected[lb] = true
 		}
 	}
 
-	if *verbose && !*quiet {
-		fmt.Printf("📁 Found %d tracked files (%d after filtering)\n", len(trackedFiles), len(filteredFiles))
+	var leaderboards []compass.Leaderboard
+	for _, lb := range compass.AllLeaderboards() {
+		if selected[lb] {
+			leaderboards = append(leaderboards, lb)
+		}
 	}
 
-	// Check if ESLint-based leaderboards are needed
-	needsESLint := *showAuthors || *showFiles || *showRules
+	reporter := newProgressReporter(os.Stderr, isTerminal(os.Stderr))
+	progress := func(phase string, done, total int) {
+		if *quiet {
+			return
+		}
 
-	var issues []types.Issue
-	var ruffIssues []types.Issue
-	var authorStats map[string]*types.AuthorStats
-	var fileStats map[string]*types.FileStats
-	var ruleStats map[string]*types.RuleStats
-	var warningLogs []string
+		// Spinners and bars would interleave with JSON records
+		if *logJSON {
+			switch phase {
+			case "eslint":
+				status.Info("", "Running ESLint analysis")
+			case "ruff":
+				status.Info("", "Running Ruff analysis")
+			}
+			return
+		}
+		reporter.update(phase, done, total)
+	}
 
-	if needsESLint {
-		if !*quiet {
-			fmt.Printf("%s %s\n", MINI_COMPASS, lipgloss.NewStyle().Foreground(lipgloss.Color("#0000FF")).Render("Running ESLint analysis..."))
+	// Plugins skipped during discovery are listed with the run's warnings
+	discovery := logging.NewCollector(logger.Handler(), nil)
+	registry := lint.DefaultRegistry(slog.New(discovery))
+	if *checkstyleFile != "" {
+		// Lint sources run in the repository root, so resolve the report first
+		path, err := filepath.Abs(*checkstyleFile)
+		if err == nil {
+			_, err = os.Stat(path)
 		}
+		if err != nil {
+			fatal(logger, "Failed to read checkstyle report", "file", *checkstyleFile, "error", err)
+		}
+		if err := registry.Register(checkstyle.Source{Path: path}); err != nil {
+			fatal(logger, "Failed to add checkstyle report", "file", *checkstyleFile, "error", err)
+		}
+	}
+	if *verbose {
+		for _, source := range registry.Sources() {
+			if plugin, ok := source.(*lint.Plugin); ok {
+				status.Info(fmt.Sprintf("🔌 Found lint plugin %s: %s\n", plugin.Name(), plugin.Path()), "Found lint plugin", "plugin", plugin.Name(), "path", plugin.Path())
+			}
+		}
+	}
 
-		// Run ESLint
-		eslintIssues, err := eslint.RunESLint(filteredFiles, ignoredRules)
+	var state *compass.RunState
+	if *stateFile != "" {
+		state, err = compass.LoadRunState(*stateFile)
 		if err != nil {
-			fmt.Printf("❌ Warning: Failed to run ESLint: %s\n", errorStyle.Render(err.Error()))
-			needsESLint = false // Disable ESLint leaderboards if it fails
-		} else {
-			issues = append(issues, eslintIssues...)
+			fatalError(logger, "Failed to load state file", err, "file", *stateFile)
 		}
+	}
 
-		if !*quiet {
-			fmt.Printf("📊 %d lint issues collected.\n", len(eslintIssues))
-			if len(ignoredRules) > 0 {
-				fmt.Printf("🚫 Ignored ESLint rules: %s\n", strings.Join(ignoredRules, ", "))
-			}
+	// Cancel running git, ESLint and Ruff commands on Ctrl+C
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if writeBaseline && (multi || compareRefs != nil || *loadReport != "") {
+		fatal(logger, "--baseline write cannot be used with multi, --compare-branches or --load-report")
+	}
+	if *baselineWrite != "" && (multi || compareRefs != nil || *loadReport != "") {
+		fatal(logger, "--baseline-write cannot be used with multi, --compare-branches or --load-report")
+	}
+	// The per-rule and per-file counts of the packets are not saved with
+	// a report
+	if *authorReportDir != "" && (multi || compareRefs != nil || *loadReport != "") {
+		fatal(logger, "--author-report-dir cannot be used with multi, --compare-branches or --load-report")
+	}
+
+	// Release notes want the changes since the latest tag
+	changedSince := ""
+	if *changedSinceTag {
+		if multi || compareRefs != nil || *loadReport != "" {
+			fatal(logger, "--changed-since-tag cannot be used with multi, --compare-branches or --load-report")
+		}
+		if changedSince, err = compass.LatestTag(ctx, repoPath); err != nil {
+			fatalError(logger, "Failed to find the latest tag", err)
 		}
 	}
 
-	if needsRuff {
-		if !*quiet {
-			fmt.Printf("%s %s\n", MINI_COMPASS, lipgloss.NewStyle().Foreground(lipgloss.Color("#0000FF")).Render("Running Ruff analysis..."))
+	// Issues are told apart from the baseline in --log-dir, or from those of
+	// the last logged run, whenever there is one
+	var baseline *compass.Baseline
+	if !multi && compareRefs == nil && *loadReport == "" {
+		if baseline, err = compass.ReadBaseline(*logDir); err != nil {
+			status.Warn(fmt.Sprintf("❌ Failed to read the issue baseline: %s\n", errorStyle.Render(err.Error())), "Failed to read issue baseline", err, "dir", *logDir)
 		}
+	}
 
-		pythonFiles := []string{}
-		for file := range filteredFiles {
-			if strings.HasSuffix(file, ".py") {
-				pythonFiles = append(pythonFiles, file)
+	// Findings of the suppression baseline committed with the repository
+	// are left out of the leaderboards unless asked for
+	var suppressionBaseline *compass.SuppressionBaseline
+	if !multi && compareRefs == nil && *loadReport == "" {
+		if suppressionBaseline, err = compass.ReadSuppressionBaseline(repoPath, *baselineFile); err != nil {
+			status.Warn(fmt.Sprintf("❌ Failed to read the suppression baseline: %s\n", errorStyle.Render(err.Error())), "Failed to read suppression baseline", err)
+		}
+	}
+
+	options := compass.Options{
+		RepoPath:     repoPath,
+		Leaderboards: leaderboards,
+		Config:       cfg,
+		IgnoredRules: cmdIgnoredRules,
+		CoverageFile: *coverageFile,
+		FileSort:     fileSort,
+		ChurnSort:    churnSort,
+		Weighted:     *weighted,
+		Since:        since,
+		Sources:      registry.Sources(),
+		State:        state,
+		Progress:     progress,
+		Logger:       logger,
+		TrackIssues:  *logHistory || writeBaseline,
+		Baseline:     baseline,
+
+		IgnoredRulePrefixes: cmdIgnoredPrefixes,
+		IncludeUntracked:    *includeUntracked,
+		IncludeVendored:     *includeVendored,
+		Languages:           languages,
+		OnlyExtensions:      onlyExtensions,
+		ExcludeExtensions:   excludeExtensions,
+		ChangedSince:        changedSince,
+		OutputDirs:          []string{*logDir, *authorReportDir},
+		RequireClean:        *requireClean,
+		RequireCoverage:     *requireCoverage,
+		Suppression:         suppressionBaseline,
+		IncludeBaselined:    *includeBaselined,
+		RecordFindings:      *baselineWrite != "",
+	}
+
+	// deliver saves and sends a finished report and lists its warnings
+	deliver := func(report *compass.Report) {
+		if *saveReport != "" {
+			if err := compass.SaveReport(*saveReport, report); err != nil {
+				status.Warn(fmt.Sprintf("❌ Failed to save report: %s\n", errorStyle.Render(err.Error())), "Failed to save report", err, "file", *saveReport)
+			} else {
+				status.Info(fmt.Sprintf("✅ Report saved to %s\n", successStyle.Render(*saveReport)), "Report saved", "file", *saveReport)
 			}
 		}
 
-		currentRuffIssues, err := ruff.RunRuff(pythonFiles, cfg.RuffRules, cfg.RuffIgnorePaths)
-		if err != nil {
-			fmt.Printf("❌ Warning: Failed to run Ruff: %s\n", errorStyle.Render(err.Error()))
-			needsRuff = false // Disable Ruff leaderboards if it fails
-		} else {
-			ruffIssues = append(ruffIssues, currentRuffIssues...)
-			issues = append(issues, currentRuffIssues...)
+		if *attentionOut != "" {
+			if err := compass.SaveAttentionList(*attentionOut, report, cfg); err != nil {
+				status.Warn(fmt.Sprintf("❌ Failed to write the attention list: %s\n", errorStyle.Render(err.Error())), "Failed to write attention list", err, "file", *attentionOut)
+			} else {
+				status.Info(fmt.Sprintf("✅ Files needing attention written to %s\n", successStyle.Render(*attentionOut)), "Attention list written", "file", *attentionOut)
+			}
+		}
+
+		if *warningsOut != "" {
+			if err := compass.SaveDiagnostics(*warningsOut, report); err != nil {
+				status.Warn(fmt.Sprintf("❌ Failed to write the warnings: %s\n", errorStyle.Render(err.Error())), "Failed to write warnings", err, "file", *warningsOut)
+			} else {
+				status.Info(fmt.Sprintf("✅ %d warnings written to %s\n", len(report.Warnings), successStyle.Render(*warningsOut)), "Warnings written", "file", *warningsOut, "warnings", len(report.Warnings))
+			}
+		}
+
+		if *webhookURL != "" {
+			if err := reporting.NewWebhook(*webhookURL, *webhookToken).Send(ctx, &report.Report); err != nil {
+				status.Warn(fmt.Sprintf("❌ Failed to send webhook: %s\n", errorStyle.Render(err.Error())), "Failed to send webhook", err)
+			} else {
+				status.Info("✅ Report sent to webhook\n", "Report sent to webhook")
+			}
+		}
+
+		// JSON logs already carry every warning as it happened
+		if len(report.Warnings) > 0 && !*quiet && !*logJSON {
+			heading(warningStyle.Render("Navigation Warnings:") + "\n")
+			printWarnings(os.Stdout, report.Warnings, cfg.MaxWarningGroups, *verbose)
 		}
+	}
 
-		if !*quiet {
-			fmt.Printf("📊 %d Ruff issues collected.\n", len(currentRuffIssues))
-			if len(cfg.RuffRules) > 0 {
-				fmt.Printf("🚫 Ruff rules: %s\n", strings.Join(cfg.RuffRules, ", "))
+	// Serving analyzes the repository for as long as the server runs
+	if *serveAddr != "" {
+		if multi || compareRefs != nil || *loadReport != "" {
+			fatal(logger, "--serve cannot be used with multi, --compare-branches or --load-report")
+		}
+		listener, err := net.Listen("tcp", *serveAddr)
+		if err != nil {
+			fatal(logger, "Failed to listen", "address", *serveAddr, "error", err)
+		}
+		// Analyses run while serving, where a progress bar has no place
+		options.Progress = nil
+		srv := server.New(func(ctx context.Context) (*types.Report, error) {
+			report, err := compass.Run(ctx, options)
+			if err != nil {
+				return nil, err
 			}
-			if len(cfg.RuffIgnorePaths) > 0 {
-				fmt.Printf("🚫 Ignored Ruff paths: %s\n", strings.Join(cfg.RuffIgnorePaths, ", "))
+			logger.Info("Analyzed repository", "path", repoPath, "issues", report.IssueCount())
+			return &report.Report, nil
+		}, *serveTTL)
+		srv.SetTrend(func(report *types.Report) *types.RunTrend {
+			trend, err := compass.ReadTrend(report, *logDir)
+			if err != nil {
+				logger.Warn("Failed to read previous run", "dir", *logDir, "error", err)
 			}
+			return trend
+		})
+		srv.SetHistory(func(report *types.Report, runs int) *types.RunHistory {
+			runHistory, err := history.ReadRunHistory(*logDir, report.GeneratedAt, runs)
+			if err != nil {
+				logger.Warn("Failed to read logged runs", "dir", *logDir, "error", err)
+			}
+			return runHistory
+		})
+		status.Info(fmt.Sprintf("🌐 Serving the dashboard of %s at http://%s/ (leaderboards at /leaderboards, trends at /trends, JSON at /report)\n", repoPath, listener.Addr()),
+			"Serving report", "path", repoPath, "address", listener.Addr().String())
+		if err := srv.Serve(ctx, listener); err != nil {
+			fatal(logger, "Server failed", "error", err)
 		}
+		return
 	}
 
-	if len(issues) == 0 {
-		if !*quiet {
-			fmt.Printf("%s %s\n", MINI_COMPASS, successStyle.Render("Clean codebase - no issues found!"))
+	// Analyzing several repositories replaces the leaderboards of one
+	if multi {
+		if *reposFile == "" {
+			fatal(logger, "multi needs --repos-file")
+		}
+		if compareRefs != nil || *loadReport != "" || *stateFile != "" || len(gates) > 0 {
+			fatal(logger, "multi cannot be used with --compare-branches, --load-report, --state-file or --fail-on")
+		}
+		repos, err := readReposFile(*reposFile)
+		if err != nil {
+			fatal(logger, "Failed to read repos file", "file", *reposFile, "error", err)
+		}
+		status.Info(fmt.Sprintf("%s Analyzing %d repositories from %s, %d at a time\n", MINI_COMPASS, len(repos), *reposFile, *parallel),
+			"Analyzing repositories", "file", *reposFile, "repos", len(repos), "parallel", *parallel)
+
+		report, err := compass.RunMulti(ctx, repos, *parallel, options)
+		reporter.finish()
+		if errors.Is(err, context.Canceled) {
+			fatal(logger, "Analysis interrupted")
+		} else if err != nil {
+			fatalError(logger, "Analysis failed", err)
 		}
+		runLog.addReport(&report.Report)
+		report.Repo.Path = *reposFile
+
+		printer := leaderboard.NewPrinter(os.Stdout)
+		printer.SetVerbose(*verbose)
+		printer.SetWidth(width)
+		printer.SetGroupNumbers(*groupNumbers)
+		printer.SetMaxRows(maxRows, capHint)
+		showMultiReport(printer, report, *topN)
+		deliver(report)
+		return
+	}
+
+	// Comparing refs replaces the leaderboards of the work tree
+	if compareRefs != nil && *loadReport != "" {
+		fatal(logger, "--compare-branches cannot be used with --load-report")
+	}
+	if compareRefs != nil {
+		status.Info(fmt.Sprintf("%s Comparing %s with %s\n", MINI_COMPASS, compareRefs[0], compareRefs[1]), "Comparing refs", "a", compareRefs[0], "b", compareRefs[1])
+		// Each ref is a run of its own, which one progress bar cannot show
+		options.Progress = nil
+		comparison, err := compass.CompareRefs(ctx, compareRefs[0], compareRefs[1], options)
+		if errors.Is(err, context.Canceled) {
+			fatal(logger, "Comparison interrupted")
+		} else if errors.Is(err, compass.ErrNotGitRepository) {
+			fatalError(logger, "Not in a git repository", err, "path", repoPath)
+		} else if err != nil {
+			fatalError(logger, "Comparison failed", err)
+		}
+		fmt.Println()
+		printer := leaderboard.NewPrinter(os.Stdout)
+		printer.SetWidth(width)
+		printer.SetGroupNumbers(*groupNumbers)
+		printer.PrintComparison(comparison.RefA, comparison.RefB, comparison.Deltas, comparison.ToolChanges)
+		return
+	}
+
+	var report *compass.Report
+	if *loadReport != "" {
+		report, err = compass.LoadReport(*loadReport)
+		if err != nil {
+			fatal(logger, "Failed to load report", "file", *loadReport, "error", err)
+		}
+		status.Info(fmt.Sprintf("📂 Loaded the report of %s from %s, generated %s\n", report.Repo.Path, *loadReport, report.GeneratedAt.Local().Format("2006-01-02 15:04")),
+			"Loaded report", "file", *loadReport, "path", report.Repo.Path, "generated_at", report.GeneratedAt)
+
+		// A report of several repositories has leaderboards of its own
+		if len(report.Repos) > 0 || len(report.RepoFailures) > 0 {
+			printer := leaderboard.NewPrinter(os.Stdout)
+			printer.SetVerbose(*verbose)
+			printer.SetWidth(width)
+			printer.SetGroupNumbers(*groupNumbers)
+			printer.SetMaxRows(maxRows, capHint)
+			showMultiReport(printer, report, *topN)
+			deliver(report)
+			if !checkGates(gates, report, status) {
+				exit(exitGateFailed, "quality gates failed")
+			}
+			return
+		}
+		showSavedLeaderboards(report, shown, status)
 	} else {
-		// Initialize data structures
-		authorStats = make(map[string]*types.AuthorStats)
-		fileStats = make(map[string]*types.FileStats)
-		ruleStats = make(map[string]*types.RuleStats)
-		var mu sync.Mutex
-
-		// Process issues
-		var bar *progressbar.ProgressBar
-		if !*quiet {
-			bar = progressbar.Default(int64(len(issues)))
-		}
-
-		semaphore := utils.NewSemaphore(cfg.GetConcurrency())
-		analyzer := analyzer.New(semaphore, &mu)
-
-		for _, issue := range issues {
-			if err := analyzer.ProcessIssueWithConfig(issue, cfg, authorStats, fileStats, ruleStats, &warningLogs); err != nil {
-				if *verbose {
-					fmt.Printf("Warning: Failed to process issue in %s:%d: %v\n", issue.FilePath, issue.Line, err)
-				}
+		report, err = compass.Run(ctx, options)
+		reporter.finish()
+		if errors.Is(err, context.Canceled) {
+			fatal(logger, "Analysis interrupted")
+		} else if errors.Is(err, compass.ErrNotGitRepository) {
+			fatalError(logger, "Not in a git repository", err, "path", repoPath)
+		} else if err != nil {
+			fatalError(logger, "Analysis failed", err)
+		}
+		runLog.addReport(&report.Report)
+
+		// An auto-detected coverage file that fails to parse only costs its
+		// leaderboard, but one named with --coverage-file is a usage error,
+		// and with --require-coverage no coverage at all is
+		var coverageErr *cerrors.ErrCoverageFormat
+		if err := report.Errors[compass.LeaderboardCoverage]; *requireCoverage && err != nil {
+			fatalError(logger, "Coverage is required", err, "file", *coverageFile)
+		} else if *coverageFile != "" && errors.As(err, &coverageErr) {
+			fatalError(logger, "Failed to parse coverage file", err, "file", *coverageFile)
+		}
+	}
+
+	if len(report.Reused) > 0 {
+		status.Info(fmt.Sprintf("♻️ Reused %d leaderboard(s) from %s\n", len(report.Reused), *stateFile),
+			"Reused leaderboards from state file", "file", *stateFile, "leaderboards", len(report.Reused))
+	}
+
+	if report.Repo.NoCommits {
+		status.Warn("📭 This repository has no commits yet, so leaderboards built from git history are skipped.\n",
+			"Repository has no commits yet", compass.ErrNoCommits)
+	}
+
+	// Attribution of uncommitted lines is a guess worth pointing out
+	if dirty := report.Repo.DirtyFiles; dirty > 0 {
+		status.Warn(fmt.Sprintf("✏️ %s analyzed files have uncommitted changes; issues on their changed lines are blamed on \"uncommitted\". Commit or stash them for exact attribution.\n", warningStyle.Render(strconv.Itoa(dirty))),
+			"Work tree has uncommitted changes", fmt.Errorf("%d analyzed files have uncommitted changes", dirty), "files", dirty)
+	}
+
+	if report.Repo.ChangedSince != "" {
+		status.Info(fmt.Sprintf("🏷️ Analyzing the %d files changed since %s\n", report.Repo.ChangedFiles, report.Repo.ChangedSince),
+			"Analyzing changed files", "since", report.Repo.ChangedSince, "files", report.Repo.ChangedFiles)
+	}
+
+	if *verbose {
+		status.Info(fmt.Sprintf("📁 Found %d tracked files (%d after filtering)\n", report.Repo.TrackedFiles, report.Repo.AnalyzedFiles),
+			"Found tracked files", "tracked", report.Repo.TrackedFiles, "analyzed", report.Repo.AnalyzedFiles)
+		if len(report.ToolVersions) > 0 {
+			status.Info(fmt.Sprintf("🔧 Analyzed with %s\n", toolversion.Format(report.ToolVersions)),
+				"Tool versions", "tools", report.ToolVersions)
+		}
+		if *includeUntracked {
+			status.Info(fmt.Sprintf("📝 Added %d untracked files to the file-based leaderboards\n", report.Repo.UntrackedFiles),
+				"Added untracked files", "untracked", report.Repo.UntrackedFiles)
+		}
+		if report.Repo.VendoredFiles > 0 || report.Repo.GeneratedFiles > 0 {
+			status.Info(fmt.Sprintf("📦 Left %d vendored and %d generated files out of the file content leaderboards (--include-vendored keeps them)\n", report.Repo.VendoredFiles, report.Repo.GeneratedFiles),
+				"Left out vendored and generated files", "vendored", report.Repo.VendoredFiles, "generated", report.Repo.GeneratedFiles)
+		}
+		for _, lb := range compass.AllLeaderboards() {
+			if skipped := report.Repo.OversizedFiles[string(lb)]; skipped > 0 {
+				status.Info(fmt.Sprintf("📏 Left %d files over the size limit out of %s\n", skipped, lb),
+					"Left out oversized files", "leaderboard", string(lb), "files", skipped)
+			}
+			if excluded := report.Repo.ExcludedFiles[string(lb)]; excluded > 0 {
+				status.Info(fmt.Sprintf("🙈 Left %d files matching %s-ignore-paths out of %s\n", excluded, lb, lb),
+					"Left out ignored files", "leaderboard", string(lb), "files", excluded)
+			}
+		}
+	}
+
+	// Issue-based leaderboards are only available when ESLint or a lint
+	// plugin ran
+	issueSourceRan := false
+	for _, source := range report.Sources {
+		if source.Name != "ruff" && source.Err == nil {
+			issueSourceRan = true
+		}
+	}
+	if *showAuthors || *showFiles || *showRules || *showPlugins || *showGroups || *showRuleAuthor || *showHotspots {
+		if report.ESLintError != nil {
+			status.Warn(fmt.Sprintf("❌ Warning: Failed to run ESLint: %s\n", errorStyle.Render(report.ESLintError.Error())), "Failed to run ESLint", report.ESLintError)
+		}
+
+		status.Info(fmt.Sprintf("📊 %d lint issues collected.\n", report.ESLintIssues), "Lint issues collected", "source", "eslint", "issues", report.ESLintIssues)
+		if len(ignoredRules) > 0 {
+			status.Info(fmt.Sprintf("🚫 Ignored ESLint rules: %s\n", strings.Join(ignoredRules, ", ")), "Ignored ESLint rules", "rules", ignoredRules)
+		}
+
+		for _, source := range report.Sources {
+			if source.Name == "eslint" || source.Name == "ruff" {
 				continue
 			}
-			if bar != nil {
-				bar.Add(1)
+			if source.Err != nil {
+				status.Warn(fmt.Sprintf("❌ Warning: Failed to run lint plugin %s: %s\n", source.Name, errorStyle.Render(source.Err.Error())), "Failed to run lint plugin", source.Err, "plugin", source.Name)
+			} else {
+				status.Info(fmt.Sprintf("📊 %d issues collected from lint plugin %s.\n", source.Issues, source.Name), "Lint issues collected", "source", source.Name, "issues", source.Issues)
 			}
+		}
+	}
+
+	if *showRuff {
+		if report.RuffError != nil {
+			status.Warn(fmt.Sprintf("❌ Warning: Failed to run Ruff: %s\n", errorStyle.Render(report.RuffError.Error())), "Failed to run Ruff", report.RuffError)
+		}
 
-			// Small delay to prevent system overload
-			time.Sleep(50 * time.Millisecond)
+		status.Info(fmt.Sprintf("📊 %d Ruff issues collected.\n", report.RuffIssues), "Lint issues collected", "source", "ruff", "issues", report.RuffIssues)
+		if len(cfg.RuffRules) > 0 {
+			status.Info(fmt.Sprintf("🚫 Ruff rules: %s\n", strings.Join(cfg.RuffRules, ", ")), "Ruff rules", "rules", cfg.RuffRules)
+		}
+		if len(cfg.RuffIgnorePaths) > 0 {
+			status.Info(fmt.Sprintf("🚫 Ignored Ruff paths: %s\n", strings.Join(cfg.RuffIgnorePaths, ", ")), "Ignored Ruff paths", "paths", cfg.RuffIgnorePaths)
 		}
+	}
+
+	if report.IssueCount() == 0 {
+		status.Info(fmt.Sprintf("%s %s\n", MINI_COMPASS, successStyle.Render("Clean codebase - no issues found!")), "No lint issues found")
+	}
+
+	// The dashboard counts the warnings of discovery too
+	if *loadReport == "" {
+		report.Warnings = append(discovery.Warnings(), report.Warnings...)
+	}
 
-		if bar != nil {
-			bar.Finish()
+	// The dashboard compares the totals with the last logged run, read
+	// before this one is logged
+	var trend *compass.RunTrend
+	if *showDashboard {
+		if trend, err = compass.ReadTrend(&report.Report, *logDir); err != nil {
+			status.Warn(fmt.Sprintf("❌ Failed to read the previous run: %s\n", errorStyle.Render(err.Error())), "Failed to read previous run", err, "dir", *logDir)
 		}
 	}
 
+	// The leaderboards are paged when they do not fit the terminal. The
+	// printer keeps the colors of the terminal while they are held back.
+	printer := leaderboard.NewPrinter(os.Stdout)
+	paging := startPager(!*noPager)
+	printer.SetOutput(os.Stdout)
+
 	if !*quiet {
-		fmt.Printf("\n%s %s\n", MINI_COMPASS, leaderboardTitleStyle.Render("Code Quality Navigation"))
+		heading(leaderboardTitleStyle.Render("Code Quality Navigation") + "\n")
 		fmt.Printf("%s\n", strings.Repeat("─", 50))
 	}
 
 	// Generate leaderboards with compass directions
-	if *showAuthors && len(issues) > 0 {
-		fmt.Printf("\n🧭 %s", leaderboardTitleStyle.Foreground(lipgloss.Color("#FFFF00")).Render("North: "))
-		if needsESLint {
-			authorEntries := leaderboard.GenerateAuthorLeaderboard(authorStats, *topN)
-			leaderboard.PrintAuthorLeaderboard(authorEntries, *topN)
-			if *logHistory {
-				if err := history.WriteAuthorLeaderboardCSV(*logDir, authorEntries); err != nil {
-					fmt.Printf("❌ Failed to log author leaderboard: %s\n", errorStyle.Render(err.Error()))
-				} else if !*quiet {
-					fmt.Printf("✅ Author leaderboard logged to %s\n", successStyle.Render(*logDir))
-				}
+	printer.SetPathStyle(pathStyle)
+	printer.SetSpellCheckSort(spellCheckSort)
+	printer.SetVerbose(*verbose)
+	printer.SetWidth(width)
+	printer.SetGroupNumbers(*groupNumbers)
+	printer.SetMaxRows(maxRows, capHint)
+	if *showDashboard {
+		heading(leaderboardTitleStyle.Foreground(lipgloss.Color("#00FFFF")).Render("Overview: "))
+		printer.PrintDashboard(&report.Report, trend, cfg.MinCoverageThreshold)
+	}
+
+	if *byWorkspace {
+		heading(leaderboardTitleStyle.Foreground(lipgloss.Color("#00FFFF")).Render("Center+: "))
+		if err := report.Errors[compass.LeaderboardWorkspaces]; err != nil {
+			fmt.Printf("❌ Failed to generate workspace roll-up: %s\n", errorStyle.Render(err.Error()))
+		} else {
+			printer.PrintWorkspaceLeaderboard(report.Workspaces)
+		}
+	}
+
+	if *showAuthors && report.IssueCount() > 0 {
+		heading(leaderboardTitleStyle.Foreground(lipgloss.Color("#FFFF00")).Render("North: "))
+		if err := report.Errors[compass.LeaderboardAuthors]; err != nil {
+			fmt.Printf("❌ Failed to generate author leaderboard: %s\n", errorStyle.Render(err.Error()))
+		} else if issueSourceRan {
+			printer.PrintAuthorLeaderboard(report.Authors, *topN)
+			if report.NewContributorIssues > 0 {
+				fmt.Printf("🌱 %d issues new contributors added in their first %d days are not counted\n", report.NewContributorIssues, cfg.GracePeriodDays)
 			}
 		} else {
 			fmt.Println("Author leaderboard requires ESLint analysis. Run with --authors flag.")
@@ -413,16 +1200,14 @@ func main() {
 	}
 
 	if *showFiles {
-		fmt.Printf("\n🧭 %s", leaderboardTitleStyle.Foreground(lipgloss.Color("#FF0000")).Render("South: "))
-		if needsESLint {
-			fileEntries := leaderboard.GenerateFileLeaderboard(fileStats, *topN)
-			leaderboard.PrintFileLeaderboard(fileEntries, *topN)
-			if *logHistory {
-				if err := history.WriteFileLeaderboardCSV(*logDir, fileEntries); err != nil {
-					fmt.Printf("❌ Failed to log file leaderboard: %s\n", errorStyle.Render(err.Error()))
-				} else if !*quiet {
-					fmt.Printf("✅ File leaderboard logged to %s\n", successStyle.Render(*logDir))
-				}
+		heading(leaderboardTitleStyle.Foreground(lipgloss.Color("#FF0000")).Render("South: "))
+		if err := report.Errors[compass.LeaderboardFiles]; err != nil {
+			fmt.Printf("❌ Failed to generate file leaderboard: %s\n", errorStyle.Render(err.Error()))
+		} else if issueSourceRan {
+			printer.PrintFileLeaderboard(report.Files, *topN, *filesDetail)
+			if *filesByOwner {
+				fmt.Println()
+				printer.PrintFilesByOwner(compass.GroupFilesByOwner(report.Files), *topN)
 			}
 		} else {
 			fmt.Println("File leaderboard requires ESLint analysis. Run with --files flag.")
@@ -430,191 +1215,738 @@ func main() {
 	}
 
 	if *showRules {
-		fmt.Printf("\n🧭 %s", leaderboardTitleStyle.Foreground(lipgloss.Color("#0000FF")).Render("East: "))
-		if needsESLint {
-			ruleEntries := leaderboard.GenerateRuleLeaderboard(ruleStats, *topN)
-			leaderboard.PrintRuleLeaderboard(ruleEntries, *topN)
-			if *logHistory {
-				if err := history.WriteRuleLeaderboardCSV(*logDir, ruleEntries); err != nil {
-					fmt.Printf("❌ Failed to log rule leaderboard: %s\n", errorStyle.Render(err.Error()))
-				} else if !*quiet {
-					fmt.Printf("✅ Rule leaderboard logged to %s\n", successStyle.Render(*logDir))
-				}
-			}
+		heading(leaderboardTitleStyle.Foreground(lipgloss.Color("#0000FF")).Render("East: "))
+		if err := report.Errors[compass.LeaderboardRules]; err != nil {
+			fmt.Printf("❌ Failed to generate rule leaderboard: %s\n", errorStyle.Render(err.Error()))
+		} else if issueSourceRan {
+			printer.PrintRuleLeaderboard(report.Rules, *topN)
 		} else {
 			fmt.Println("Rule leaderboard requires ESLint analysis. Run with --rules flag.")
 		}
 	}
 
-	if *showLoc {
-		fmt.Printf("\n🧭 %s", leaderboardTitleStyle.Foreground(lipgloss.Color("#00FF00")).Render("West: "))
-		locEntries := leaderboard.GenerateLinesOfCodeLeaderboard(filteredFiles, *topN)
-		leaderboard.PrintLinesOfCodeLeaderboard(locEntries, *topN)
-		if *logHistory {
-			if err := history.WriteLinesOfCodeLeaderboardCSV(*logDir, locEntries); err != nil {
-				fmt.Printf("❌ Failed to log lines of code leaderboard: %s\n", errorStyle.Render(err.Error()))
-			} else if !*quiet {
-					fmt.Printf("✅ Lines of code leaderboard logged to %s\n", successStyle.Render(*logDir))
-				}
-			}
+	if *showPlugins {
+		heading(leaderboardTitleStyle.Foreground(lipgloss.Color("#0000FF")).Render("East+: "))
+		if err := report.Errors[compass.LeaderboardRulePlugins]; err != nil {
+			fmt.Printf("❌ Failed to generate rule plugin leaderboard: %s\n", errorStyle.Render(err.Error()))
+		} else if issueSourceRan {
+			printer.PrintRulePluginLeaderboard(report.RulePlugins, *topN)
+		} else {
+			fmt.Println("Rule plugin leaderboard requires ESLint analysis. Run with --rule-plugins flag.")
 		}
+	}
+
+	if *showGroups {
+		heading(leaderboardTitleStyle.Foreground(lipgloss.Color("#0000FF")).Render("East++: "))
+		if err := report.Errors[compass.LeaderboardRuleGroups]; err != nil {
+			fmt.Printf("❌ Failed to generate rule group leaderboard: %s\n", errorStyle.Render(err.Error()))
+		} else if issueSourceRan {
+			printer.PrintRuleGroupLeaderboard(report.RuleGroups, *topN)
+		} else {
+			fmt.Println("Rule group leaderboard requires ESLint analysis. Run with --group-rules flag.")
+		}
+	}
+
+	if *showRuleAuthor {
+		heading(leaderboardTitleStyle.Foreground(lipgloss.Color("#0000FF")).Render("East+++: "))
+		if err := report.Errors[compass.LeaderboardRuleAuthors]; err != nil {
+			fmt.Printf("❌ Failed to generate rule author matrix: %s\n", errorStyle.Render(err.Error()))
+		} else if issueSourceRan {
+			printer.PrintRuleAuthorMatrix(report.RuleAuthors, *topN)
+		} else {
+			fmt.Println("Rule author matrix requires ESLint analysis. Run with --rule-author-matrix flag.")
+		}
+	}
+
+	if *showLoc {
+		heading(leaderboardTitleStyle.Foreground(lipgloss.Color("#00FF00")).Render("West: "))
+		printer.PrintLinesOfCodeLeaderboard(report.LinesOfCode, *topN)
+	}
 
 	if *showCommits {
-		fmt.Printf("\n%s %s", MINI_COMPASS, leaderboardTitleStyle.Foreground(lipgloss.Color("#FF00FF")).Render("NE: "))
-		commitEntries, err := leaderboard.GenerateCommitCountLeaderboard(*topN)
-		if err != nil {
+		heading(leaderboardTitleStyle.Foreground(lipgloss.Color("#FF00FF")).Render("NE: "))
+		if err := report.Errors[compass.LeaderboardCommits]; err != nil {
 			fmt.Printf("❌ Failed to generate commit count leaderboard: %s\n", errorStyle.Render(err.Error()))
 		} else {
-			leaderboard.PrintCommitCountLeaderboard(commitEntries, *topN)
-			if *logHistory {
-				if err := history.WriteCommitCountLeaderboardCSV(*logDir, commitEntries); err != nil {
-					fmt.Printf("❌ Failed to log commit count leaderboard: %v\n", err)
-				} else if !*quiet {
-					fmt.Printf("✅ Commit count leaderboard logged to %s\n", successStyle.Render(*logDir))
-				}
-			}
+			printer.PrintCommitCountLeaderboard(report.Commits, *topN)
 		}
 	}
 
 	if *showRecent {
-		fmt.Printf("\n%s %s", MINI_COMPASS, leaderboardTitleStyle.Foreground(lipgloss.Color("#00FFFF")).Render("NW: "))
-		recentEntries, err := leaderboard.GenerateRecentContributorsLeaderboard(*topN)
-		if err != nil {
+		heading(leaderboardTitleStyle.Foreground(lipgloss.Color("#00FFFF")).Render("NW: "))
+		if err := report.Errors[compass.LeaderboardRecent]; err != nil {
 			fmt.Printf("❌ Failed to generate recent contributors leaderboard: %s\n", errorStyle.Render(err.Error()))
 		} else {
-			leaderboard.PrintRecentContributorsLeaderboard(recentEntries, *topN)
-			if *logHistory {
-				if err := history.WriteRecentContributorsLeaderboardCSV(*logDir, recentEntries); err != nil {
-					fmt.Printf("❌ Failed to log recent contributors leaderboard: %v\n", err)
-				} else if !*quiet {
-					fmt.Printf("✅ Recent contributors leaderboard logged to %s\n", successStyle.Render(*logDir))
-				}
-			}
+			printer.PrintRecentContributorsLeaderboard(report.Recent, *topN)
+		}
+	}
+
+	if *showNewContrib {
+		heading(leaderboardTitleStyle.Foreground(lipgloss.Color("#00FF00")).Render("NW+: "))
+		if err := report.Errors[compass.LeaderboardNewContrib]; err != nil {
+			fmt.Printf("❌ Failed to generate new contributors leaderboard: %s\n", errorStyle.Render(err.Error()))
+		} else {
+			printer.PrintNewContributors(*report.NewContributors, *topN)
 		}
 	}
 
 	if *showCoverage {
-		fmt.Printf("\n🧭 %s", leaderboardTitleStyle.Foreground(lipgloss.Color("#00FF00")).Render("SE: "))
-		coverageEntries, overallCoverage := leaderboard.GenerateCodeCoverageLeaderboard(filteredFiles, *coverageFile, *topN)
-		leaderboard.PrintCodeCoverageLeaderboard(coverageEntries, overallCoverage, *topN)
-		if *logHistory {
-			if err := history.WriteCodeCoverageLeaderboardCSV(*logDir, coverageEntries); err != nil {
-				fmt.Printf("❌ Failed to log code coverage leaderboard: %s\n", errorStyle.Render(err.Error()))
-			} else if !*quiet {
-					fmt.Printf("✅ Code coverage leaderboard logged to %s\n", successStyle.Render(*logDir))
-				}
-			}
+		heading(leaderboardTitleStyle.Foreground(lipgloss.Color("#00FF00")).Render("SE: "))
+		if err := report.Errors[compass.LeaderboardCoverage]; err != nil {
+			fmt.Printf("❌ Failed to generate code coverage leaderboard: %s\n", errorStyle.Render(err.Error()))
+			printHint(err)
+		} else {
+			printer.PrintCodeCoverageLeaderboard(report.Coverage, report.OverallCoverage, cfg.MinCoverageThreshold, report.CoverageFreshness, *topN)
 		}
+	}
 
 	if *showChurn {
-		fmt.Printf("\n🧭 %s", leaderboardTitleStyle.Foreground(lipgloss.Color("#FFFF00")).Render("SW: "))
-		churnEntries, err := leaderboard.GenerateCodeChurnLeaderboard(filteredFiles, *topN)
-		if err != nil {
+		heading(leaderboardTitleStyle.Foreground(lipgloss.Color("#FFFF00")).Render("SW: "))
+		if err := report.Errors[compass.LeaderboardChurn]; err != nil {
 			fmt.Printf("❌ Failed to generate code churn leaderboard: %s\n", errorStyle.Render(err.Error()))
 		} else {
-			leaderboard.PrintCodeChurnLeaderboard(churnEntries, *topN)
-			if *logHistory {
-				if err := history.WriteCodeChurnLeaderboardCSV(*logDir, churnEntries); err != nil {
-					fmt.Printf("❌ Failed to log code churn leaderboard: %v\n", err)
-				} else if !*quiet {
-					fmt.Printf("✅ Code churn leaderboard logged to %s\n", successStyle.Render(*logDir))
-				}
-			}
+			printer.PrintCodeChurnLeaderboard(report.Churn, *topN)
+		}
+	}
+
+	if *showDeletions {
+		heading(leaderboardTitleStyle.Foreground(lipgloss.Color("#00FF00")).Render("SW++: "))
+		if err := report.Errors[compass.LeaderboardDeletions]; err != nil {
+			fmt.Printf("❌ Failed to generate deletion leaderboard: %s\n", errorStyle.Render(err.Error()))
+		} else {
+			printer.PrintDeletionStats(*report.Deletions, *topN)
+		}
+	}
+
+	if *showHotspots {
+		heading(leaderboardTitleStyle.Foreground(lipgloss.Color("#FF0000")).Render("SW+++: "))
+		if err := report.Errors[compass.LeaderboardHotspots]; err != nil {
+			fmt.Printf("❌ Failed to generate hotspot leaderboard: %s\n", errorStyle.Render(err.Error()))
+		} else if issueSourceRan {
+			printer.PrintHotspotLeaderboard(report.Hotspots, *topN)
+		} else {
+			fmt.Println("Hotspot leaderboard requires ESLint analysis. Run with --hotspots flag.")
 		}
 	}
 
 	if *showBugs {
-		fmt.Printf("\n🧭 %s", leaderboardTitleStyle.Foreground(lipgloss.Color("#FF0000")).Render("SSE: "))
-		bugEntries, err := leaderboard.GenerateBugDensityLeaderboard(filteredFiles, *topN)
-		if err != nil {
+		heading(leaderboardTitleStyle.Foreground(lipgloss.Color("#FF0000")).Render("SSE: "))
+		if err := report.Errors[compass.LeaderboardBugs]; err != nil {
 			fmt.Printf("❌ Failed to generate bug density leaderboard: %s\n", errorStyle.Render(err.Error()))
 		} else {
-			leaderboard.PrintBugDensityLeaderboard(bugEntries, *topN)
-			if *logHistory {
-				if err := history.WriteBugDensityLeaderboardCSV(*logDir, bugEntries); err != nil {
-					fmt.Printf("❌ Failed to log bug density leaderboard: %v\n", err)
-				} else if !*quiet {
-					fmt.Printf("✅ Bug density leaderboard logged to %s\n", successStyle.Render(*logDir))
-				}
-			}
+			printer.PrintBugDensityLeaderboard(report.BugDensity, *topN)
 		}
 	}
 
 	if *showDebt {
-		fmt.Printf("\n%s %s", MINI_COMPASS, leaderboardTitleStyle.Render("SSW: "))
-		debtEntries, err := leaderboard.GenerateTechnicalDebtLeaderboard(filteredFiles, *topN)
-		if err != nil {
+		heading(leaderboardTitleStyle.Render("SSW: "))
+		if err := report.Errors[compass.LeaderboardDebt]; err != nil {
 			fmt.Printf("❌ Failed to generate technical debt leaderboard: %s\n", errorStyle.Render(err.Error()))
 		} else {
-			leaderboard.PrintTechnicalDebtLeaderboard(debtEntries, *topN)
-			if *logHistory {
-				if err := history.WriteTechnicalDebtLeaderboardCSV(*logDir, debtEntries); err != nil {
-					fmt.Printf("❌ Failed to log technical debt leaderboard: %v\n", err)
-				} else if !*quiet {
-					fmt.Printf("✅ Technical debt leaderboard logged to %s\n", successStyle.Render(*logDir))
-				}
-			}
+			printer.PrintTechnicalDebtLeaderboard(report.TechnicalDebt, *topN)
 		}
 	}
 
 	if *showComplexity {
-		fmt.Printf("\n🧭 %s", leaderboardTitleStyle.Foreground(lipgloss.Color("#0000FF")).Render("NNW: "))
+		heading(leaderboardTitleStyle.Foreground(lipgloss.Color("#0000FF")).Render("NNW: "))
 		fmt.Printf("Code complexity leaderboard coming soon!\n")
 	}
 
 	if *showSpellCheck {
-		fmt.Printf("\n🧭 %s", leaderboardTitleStyle.Foreground(lipgloss.Color("#00FFFF")).Render("ENE: "))
-		spellEntries, spellAuthorStats, err := leaderboard.GenerateSpellCheckLeaderboard(filteredFiles, cfg, *topN)
-		if err != nil {
+		heading(leaderboardTitleStyle.Foreground(lipgloss.Color("#00FFFF")).Render("ENE: "))
+		if err := report.Errors[compass.LeaderboardSpellCheck]; err != nil {
 			fmt.Printf("❌ Failed to generate spell check leaderboard: %s\n", errorStyle.Render(err.Error()))
 		} else {
-			leaderboard.PrintSpellCheckLeaderboard(spellEntries, spellAuthorStats, *topN)
-			if *logHistory {
-				if err := history.WriteSpellCheckLeaderboardCSV(*logDir, spellEntries); err != nil {
-					fmt.Printf("❌ Failed to log spell check leaderboard: %v\n", err)
-				} else if !*quiet {
-					fmt.Printf("✅ Spell check leaderboard logged to %s\n", successStyle.Render(*logDir))
-				}
-			}
+			printer.PrintSpellCheckLeaderboard(report.SpellCheck, report.SpellCheckAuthors, *topN)
 		}
 	}
 
 	if *showRuff {
-		fmt.Printf("\n\xe2\x90\x80 %s", leaderboardTitleStyle.Foreground(lipgloss.Color("#FFA500")).Render("WNW: "))
-		if len(ruffIssues) > 0 {
-			ruffRuleEntries := leaderboard.GenerateRuleLeaderboard(ruleStats, *topN)
-			leaderboard.PrintRuleLeaderboard(ruffRuleEntries, *topN)
-			if *logHistory {
-				if err := history.WriteRuleLeaderboardCSV(*logDir, ruffRuleEntries); err != nil {
-					fmt.Printf("❌ Failed to log Ruff rule leaderboard: %s\n", errorStyle.Render(err.Error()))
-				} else if !*quiet {
-					fmt.Printf("✅ Ruff rule leaderboard logged to %s\n", successStyle.Render(*logDir))
-				}
-			}
+		heading(leaderboardTitleStyle.Foreground(lipgloss.Color("#FFA500")).Render("WNW: "))
+		if err := report.Errors[compass.LeaderboardRuff]; err != nil {
+			fmt.Printf("❌ Failed to generate Ruff leaderboard: %s\n", errorStyle.Render(err.Error()))
+		} else if report.RuffIssues > 0 {
+			printer.PrintRuleLeaderboard(report.RuffRules, *topN)
 		} else {
 			fmt.Println("No Ruff issues found.")
 		}
 	}
 
+	if *showDrift {
+		heading(leaderboardTitleStyle.Foreground(lipgloss.Color("#FFA500")).Render("WNW+: "))
+		if err := report.Errors[compass.LeaderboardFormatDrift]; err != nil {
+			fmt.Printf("❌ Failed to generate format drift leaderboard: %s\n", errorStyle.Render(err.Error()))
+			printHint(err)
+		} else {
+			printer.PrintFormatDriftLeaderboard(*report.FormatDrift, *topN)
+		}
+	}
+
+	if *showEncoding {
+		heading(leaderboardTitleStyle.Foreground(lipgloss.Color("#FF00FF")).Render("ESE: "))
+		if err := report.Errors[compass.LeaderboardEncoding]; err != nil {
+			fmt.Printf("❌ Failed to generate encoding leaderboard: %s\n", errorStyle.Render(err.Error()))
+		} else {
+			printer.PrintEncodingLeaderboard(report.Encoding, *topN)
+		}
+	}
+
+	if *showLongFuncs {
+		heading(leaderboardTitleStyle.Foreground(lipgloss.Color("#0000FF")).Render("NNW+: "))
+		if err := report.Errors[compass.LeaderboardLongFuncs]; err != nil {
+			fmt.Printf("❌ Failed to generate long function leaderboard: %s\n", errorStyle.Render(err.Error()))
+		} else {
+			printer.PrintLongFunctionLeaderboard(report.LongFunctions, *topN)
+		}
+	}
+
+	if *showGitHub {
+		heading(leaderboardTitleStyle.Foreground(lipgloss.Color("#00FF00")).Render("WSW: "))
+		if err := report.Errors[compass.LeaderboardGitHub]; err != nil {
+			fmt.Printf("❌ Failed to generate pull request statistics: %s\n", errorStyle.Render(err.Error()))
+			printHint(err)
+		} else {
+			printer.PrintForgeStats(*report.Forge, *topN)
+		}
+	}
+
+	if *showLeadTime {
+		heading(leaderboardTitleStyle.Foreground(lipgloss.Color("#00FF00")).Render("WSW+: "))
+		if err := report.Errors[compass.LeaderboardLeadTime]; err != nil {
+			fmt.Printf("❌ Failed to generate lead time statistics: %s\n", errorStyle.Render(err.Error()))
+		} else {
+			printer.PrintLeadTimeStats(*report.LeadTime, *topN)
+		}
+	}
+
+	if *showChangelog {
+		heading(leaderboardTitleStyle.Foreground(lipgloss.Color("#00FF00")).Render("SSW+: "))
+		if err := report.Errors[compass.LeaderboardChangelog]; err != nil {
+			fmt.Printf("❌ Failed to generate changelog readiness: %s\n", errorStyle.Render(err.Error()))
+		} else {
+			printer.PrintChangelogReadiness(*report.Changelog, *topN)
+		}
+	}
+
+	if *showTimezones {
+		heading(leaderboardTitleStyle.Foreground(lipgloss.Color("#00FF00")).Render("WSW++: "))
+		if err := report.Errors[compass.LeaderboardTimezones]; err != nil {
+			fmt.Printf("❌ Failed to generate timezone statistics: %s\n", errorStyle.Render(err.Error()))
+		} else {
+			printer.PrintTimezoneStats(*report.Timezones, *topN)
+		}
+	}
+
+	if *showLFS {
+		heading(leaderboardTitleStyle.Foreground(lipgloss.Color("#FF00FF")).Render("ESE++: "))
+		if err := report.Errors[compass.LeaderboardLFS]; err != nil {
+			fmt.Printf("❌ Failed to generate LFS report: %s\n", errorStyle.Render(err.Error()))
+		} else {
+			printer.PrintLFSReport(*report.LFS, *topN)
+		}
+	}
+
+	if *showVulns {
+		heading(leaderboardTitleStyle.Foreground(lipgloss.Color("#FF00FF")).Render("ESE+: "))
+		if err := report.Errors[compass.LeaderboardVulns]; err != nil {
+			fmt.Printf("❌ Failed to audit dependencies: %s\n", errorStyle.Render(err.Error()))
+		}
+		printer.PrintVulnerabilityLeaderboard(report.Vulnerabilities, *topN)
+	}
+
+	if *showDeps {
+		heading(leaderboardTitleStyle.Foreground(lipgloss.Color("#FF00FF")).Render("ESE+++: "))
+		printer.PrintDependencyLeaderboard(report.Dependencies, *topN)
+	}
+
 	if *showSummary {
-		fmt.Printf("\n%s %s", MINI_COMPASS, leaderboardTitleStyle.Render("Center: "))
-		leaderboard.GenerateSummaryStats(authorStats, fileStats, ruleStats)
+		heading(leaderboardTitleStyle.Render("Center: "))
+		printer.PrintSummaryStats(*report.Summary)
+		printer.PrintLanguageComposition(report.Repo.Languages)
+		if report.IssueBaseline != nil {
+			printer.PrintIssueBaseline(*report.IssueBaseline)
+		}
+		if report.Suppression != nil {
+			printer.PrintSuppression(*report.Suppression, *topN)
+		}
+	}
+
+	if *showScore {
+		heading(leaderboardTitleStyle.Foreground(lipgloss.Color("#00FF00")).Render("North+: "))
+		printer.PrintScore(*report.Score, *topN)
 	}
 
-	if len(warningLogs) > 0 && !*quiet {
-		fmt.Printf("\n%s %s\n", MINI_COMPASS, warningStyle.Render("Navigation Warnings:"))
-		for _, warn := range warningLogs {
-			fmt.Printf("  %s\n", infoStyle.Render(warn))
+	if *showReportCard {
+		heading(leaderboardTitleStyle.Render("True North: "))
+		printer.PrintReportCard(*report.ReportCard)
+	}
+	paging.finish()
+
+	// A loaded report was logged when it was generated, if at all
+	if *logHistory && *loadReport == "" {
+		writer := &history.Writer{Dir: *logDir, Sanitize: *sanitizeCSV}
+		if err := writer.WriteReport(&report.Report); err != nil {
+			status.Warn(fmt.Sprintf("❌ Failed to log leaderboards: %s\n", errorStyle.Render(err.Error())), "Failed to log leaderboards", err, "dir", *logDir)
+		} else {
+			status.Info(fmt.Sprintf("✅ Leaderboards logged to %s\n", successStyle.Render(*logDir)), "Leaderboards logged", "dir", *logDir)
+		}
+		// Without a lint source there are no fingerprints to replace the
+		// last ones with
+		if report.Fingerprints != nil {
+			if err := compass.WriteFingerprints(*logDir, report); err != nil {
+				status.Warn(fmt.Sprintf("❌ Failed to log issue fingerprints: %s\n", errorStyle.Render(err.Error())), "Failed to log issue fingerprints", err, "dir", *logDir)
+			}
+		}
+	}
+
+	if writeBaseline {
+		if report.Fingerprints == nil {
+			err := errors.New("no lint source ran")
+			status.Warn(fmt.Sprintf("❌ Baseline not written: %s\n", errorStyle.Render(err.Error())), "Baseline not written", err)
+		} else if err := compass.WriteBaseline(*logDir, report); err != nil {
+			status.Warn(fmt.Sprintf("❌ Failed to write baseline: %s\n", errorStyle.Render(err.Error())), "Failed to write baseline", err, "dir", *logDir)
+		} else {
+			status.Info(fmt.Sprintf("✅ Baseline of %d issues written to %s\n", len(report.Fingerprints), successStyle.Render(*logDir)),
+				"Baseline written", "dir", *logDir, "issues", len(report.Fingerprints))
 		}
 	}
 
+	if *baselineWrite != "" {
+		if n, err := compass.WriteSuppressionBaseline(*baselineWrite, report); err != nil {
+			status.Warn(fmt.Sprintf("❌ Failed to write suppression baseline: %s\n", errorStyle.Render(err.Error())), "Failed to write suppression baseline", err, "file", *baselineWrite)
+		} else {
+			status.Info(fmt.Sprintf("✅ Suppression baseline of %d findings written to %s\n", n, successStyle.Render(*baselineWrite)),
+				"Suppression baseline written", "file", *baselineWrite, "findings", n)
+		}
+	}
+
+	if *ownersOut != "" && *showFiles && report.Errors[compass.LeaderboardFiles] == nil && issueSourceRan {
+		var markdown strings.Builder
+		err := leaderboard.WriteFilesByOwnerMarkdown(&markdown, compass.GroupFilesByOwner(report.Files), *topN)
+		if err == nil {
+			err = os.WriteFile(*ownersOut, []byte(markdown.String()), 0644)
+		}
+		if err != nil {
+			status.Warn(fmt.Sprintf("❌ Failed to write owner worklists: %s\n", errorStyle.Render(err.Error())), "Failed to write owner worklists", err, "file", *ownersOut)
+		} else {
+			status.Info(fmt.Sprintf("✅ Owner worklists written to %s\n", successStyle.Render(*ownersOut)), "Owner worklists written", "file", *ownersOut)
+		}
+	}
+
+	// Written after logging, which the deltas leave out by time
+	if *authorReportDir != "" {
+		packets, err := compass.AuthorPackets(report, strings.Split(*authorReport, ","), *topN, *logDir)
+		if err != nil {
+			status.Warn(fmt.Sprintf("❌ Failed to read the previous run: %s\n", errorStyle.Render(err.Error())), "Failed to read previous run", err, "dir", *logDir)
+		}
+		paths, err := compass.WriteAuthorPackets(*authorReportDir, report, packets, *topN)
+		if err != nil {
+			status.Warn(fmt.Sprintf("❌ Failed to write author reports: %s\n", errorStyle.Render(err.Error())), "Failed to write author reports", err, "dir", *authorReportDir)
+		} else {
+			status.Info(fmt.Sprintf("✅ %d author reports written to %s\n", len(paths), successStyle.Render(*authorReportDir)), "Author reports written", "dir", *authorReportDir, "authors", len(paths))
+		}
+	}
+
+	// Exported after logging, so the series include this run
+	if *timeseriesOut != "" {
+		exportTimeSeries()
+	}
+
+	deliver(report)
+
+	// Gates are checked last, so a failing run still prints, logs and
+	// sends everything
+	if !checkGates(gates, report, status) {
+		exit(exitGateFailed, "quality gates failed")
+	}
+
 	if *verbose {
-		fmt.Printf("\n%s %s\n", MINI_COMPASS, successStyle.Render("Navigation completed successfully!"))
+		status.Info(fmt.Sprintf("\n%s %s\n", MINI_COMPASS, successStyle.Render("Navigation completed successfully!")), "Navigation completed")
 	}
 }
 
-func showCompassArt() {
-	fmt.Print(compassArtStyle.Render(`
+// showSavedLeaderboards shows the leaderboards a loaded report was run with,
+// or, when leaderboards were asked for, the ones of them the report has.
+func showSavedLeaderboards(report *compass.Report, shown map[compass.Leaderboard]*bool, status statusReporter) {
+	asked := false
+	for _, show := range shown {
+		asked = asked || *show
+	}
+
+	for _, lb := range compass.AllLeaderboards() {
+		show, ok := shown[lb]
+		if !ok {
+			continue
+		}
+		saved := report.Requested(string(lb))
+		if asked && *show && !saved {
+			status.Warn(fmt.Sprintf("⚠️ The loaded report has no %s leaderboard\n", lb), "Loaded report lacks leaderboard", fmt.Errorf("%s was not run", lb), "leaderboard", string(lb))
+		}
+		*show = saved && (*show || !asked)
+	}
+}
+
+// showHistoryDiff prints how the leaderboards changed between the runs
+// logged in dir that ids a and b name, by stamp or commit, as text or
+// markdown.
+func showHistoryDiff(dir, a, b string, leaderboards []string, format string, topN, outputWidth int) error {
+	runs, err := history.ReadRuns(dir)
+	if err != nil {
+		return err
+	}
+	if len(runs) == 0 {
+		return fmt.Errorf("no runs logged in %s; log them with --log-history", dir)
+	}
+	runA, err := history.FindRun(runs, a)
+	if err != nil {
+		return err
+	}
+	runB, err := history.FindRun(runs, b)
+	if err != nil {
+		return err
+	}
+	diffs, err := history.DiffRuns(runA, runB, leaderboards)
+	if err != nil {
+		return err
+	}
+
+	// Runs are named by their stamp and the commit they analyzed
+	label := func(run history.Run) string {
+		if len(run.Head) > 7 {
+			return fmt.Sprintf("%s (%s)", run.Stamp, run.Head[:7])
+		}
+		return run.Stamp
+	}
+	toolChanges := toolversion.Diff(runA.ToolVersions, runB.ToolVersions)
+	if format == "markdown" {
+		return leaderboard.WriteSnapshotDiffsMarkdown(os.Stdout, label(runA), label(runB), diffs, toolChanges, topN)
+	}
+	printer := leaderboard.NewPrinter(os.Stdout)
+	printer.SetWidth(utils.OutputWidth(os.Stdout, outputWidth))
+	printer.PrintSnapshotDiffs(label(runA), label(runB), diffs, toolChanges, topN)
+	return nil
+}
+
+// readReposFile reads the repositories listed in path for multi, one local
+// path or clone URL per line. Blank lines, lines starting with # and
+// repeated repositories are skipped, and relative paths are resolved against
+// the directory of path.
+func readReposFile(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var repos []string
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(string(data), "\n") {
+		repo := strings.TrimSpace(line)
+		if repo == "" || strings.HasPrefix(repo, "#") {
+			continue
+		}
+		key := repo
+		if !git.IsCloneURL(repo) {
+			if !filepath.IsAbs(repo) {
+				repo = filepath.Join(filepath.Dir(path), repo)
+			}
+			if key, err = filepath.Abs(repo); err != nil {
+				return nil, err
+			}
+		}
+		if !seen[key] {
+			seen[key] = true
+			repos = append(repos, repo)
+		}
+	}
+	if len(repos) == 0 {
+		return nil, errors.New("no repositories listed")
+	}
+	return repos, nil
+}
+
+// showMultiReport prints the leaderboards of a report of several
+// repositories: a summary row per repository, the repositories ranked by
+// issue density, coverage and debt, the authors merged across them, and the
+// repositories that failed.
+func showMultiReport(printer *leaderboard.Printer, report *compass.Report, topN int) {
+	fmt.Println()
+	printer.PrintRepoSummary(report.Repos)
+	for _, by := range []leaderboard.RepoSort{leaderboard.RepoSortIssueDensity, leaderboard.RepoSortCoverage, leaderboard.RepoSortDebt} {
+		fmt.Println()
+		printer.PrintRepoLeaderboard(report.Repos, by, topN)
+	}
+	fmt.Println()
+	printer.PrintAuthorLeaderboard(report.Authors, topN)
+	if len(report.RepoFailures) > 0 {
+		fmt.Println()
+		printer.PrintRepoFailures(report.RepoFailures)
+	}
+}
+
+// printWarnings lists warnings, each kind once with how many there were and
+// a few of their files, the most frequent first and at most maxGroups kinds.
+// Verbose output lists every warning instead.
+func printWarnings(w io.Writer, warnings []compass.Warning, maxGroups int, verbose bool) {
+	if verbose {
+		for _, warning := range warnings {
+			fmt.Fprintf(w, "  %s\n", infoStyle.Render(warning.String()))
+		}
+		return
+	}
+
+	groups := utils.GroupWarnings(warnings)
+	shown := groups
+	if maxGroups > 0 && len(groups) > maxGroups {
+		shown = groups[:maxGroups]
+	}
+	for _, group := range shown {
+		line := group.Warning.String()
+		if group.Count > 1 {
+			line = fmt.Sprintf("⚠️ %s: %d warnings", group.Warning.Message, group.Count)
+			if len(group.Examples) > 0 {
+				line += ", such as " + strings.Join(group.Examples, ", ")
+			}
+		}
+		fmt.Fprintf(w, "  %s\n", infoStyle.Render(line))
+	}
+	if hidden := len(groups) - len(shown); hidden > 0 {
+		fmt.Fprintf(w, "  %s\n", infoStyle.Render(fmt.Sprintf("... and %d more kinds of warnings, use --verbose for the full list", hidden)))
+	}
+}
+
+// checkGates reports each --fail-on condition and returns false when any of
+// them holds or could not be checked.
+func checkGates(gates []compass.Gate, report *compass.Report, status statusReporter) bool {
+	passed := true
+	for _, gate := range gates {
+		value, failed, err := gate.Check(report)
+		shown := strconv.FormatFloat(math.Round(value*100)/100, 'f', -1, 64)
+		switch {
+		case err != nil:
+			passed = false
+			status.Warn(fmt.Sprintf("❌ Quality gate %s could not be checked: %s\n", gate, errorStyle.Render(err.Error())), "Quality gate not checked", err, "gate", gate.String())
+		case failed:
+			passed = false
+			status.Warn(fmt.Sprintf("❌ Quality gate %s failed: %s is %s\n", gate, gate.Metric, errorStyle.Render(shown)),
+				"Quality gate failed", fmt.Errorf("%s is %s", gate.Metric, shown), "gate", gate.String(), "value", value)
+		default:
+			status.Info(fmt.Sprintf("✅ Quality gate %s passed: %s is %s\n", gate, gate.Metric, successStyle.Render(shown)),
+				"Quality gate passed", "gate", gate.String(), "value", value)
+		}
+	}
+	return passed
+}
+
+// statusReporter prints progress lines for people at a terminal. With JSON
+// logs the same events are logged as records on stderr instead, so stdout
+// only carries the leaderboards.
+type statusReporter struct {
+	logger *slog.Logger
+	json   bool
+	quiet  bool
+}
+
+// Info prints line unless output is quiet, or logs msg with args.
+func (s statusReporter) Info(line, msg string, args ...any) {
+	if s.json {
+		s.logger.Info(msg, args...)
+	} else if !s.quiet {
+		fmt.Print(line)
+	}
+}
+
+// Warn prints line and a remediation hint for err, or logs msg with err, the
+// hint and args as a warning. Warnings are shown even when output is quiet.
+func (s statusReporter) Warn(line, msg string, err error, args ...any) {
+	if s.json {
+		args = append(args, "error", err)
+		if hint := remediation(err); hint != "" {
+			args = append(args, "hint", hint)
+		}
+		s.logger.Warn(msg, args...)
+		return
+	}
+	fmt.Print(line)
+	printHint(err)
+}
+
+// newLogger builds the stderr logger for the CLI. Text logs only show errors
+// unless --verbose is set, since warnings are summarized after the
+// leaderboards; JSON logs include status and warnings so they can be consumed
+// directly.
+func newLogger(verbose, quiet, jsonFormat bool) *slog.Logger {
+	level := slog.LevelError
+	switch {
+	case quiet:
+	case verbose:
+		level = slog.LevelDebug
+	case jsonFormat:
+		level = slog.LevelInfo
+	}
+	return logging.New(os.Stderr, level, jsonFormat)
+}
+
+// parseArguments parses the flags in arguments wherever they are, before or
+// after the directory, and returns the other arguments. Those after "--"
+// are never flags.
+func parseArguments(flags *flag.FlagSet, arguments []string) []string {
+	var positional []string
+	for {
+		flags.Parse(arguments)
+		rest := flags.Args()
+		if len(rest) == 0 {
+			return positional
+		}
+		if parsed := len(arguments) - len(rest); parsed > 0 && arguments[parsed-1] == "--" {
+			return append(positional, rest...)
+		}
+		positional = append(positional, rest[0])
+		arguments = rest[1:]
+	}
+}
+
+// usageError reports a command line mistake the flag package cannot catch
+// and exits with 2, as for an invalid flag.
+func usageError(message string) {
+	fmt.Fprintln(flag.CommandLine.Output(), message)
+	flag.Usage()
+	exit(2, message)
+}
+
+// resolvePathFlags makes the paths given on the command line relative to
+// the directory codecompass runs in, whichever repository it analyzes: the
+// library reads a relative --coverage-file from the repository. Without
+// --log-dir, history is kept in the repository analyzed. When comparing
+// refs, a relative --coverage-file is still read from each checkout.
+func resolvePathFlags(repoPath string, comparing bool, logDir, coverageFile *string) {
+	explicit := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+
+	if !explicit["log-dir"] {
+		*logDir = filepath.Join(repoPath, *logDir)
+	}
+	if *coverageFile != "" && !comparing {
+		if path, err := filepath.Abs(*coverageFile); err == nil {
+			*coverageFile = path
+		}
+	}
+}
+
+// fatal logs msg as an error and exits.
+func fatal(logger *slog.Logger, msg string, args ...any) {
+	logger.Error(msg, args...)
+	for _, arg := range args {
+		if err, ok := arg.(error); ok {
+			msg += ": " + err.Error()
+		}
+	}
+	exit(1, msg)
+}
+
+// exit records how the run ended in the run log, then exits with code.
+func exit(code int, message string) {
+	runLog.finish(code, message)
+	os.Exit(code)
+}
+
+// Exit codes for errors the user can fix. Other failures exit with 1, and
+// flag parsing errors with 2.
+const (
+	exitNotARepo      = 3
+	exitToolNotFound  = 4
+	exitCoverage      = 5
+	exitConfigInvalid = 6
+	exitGateFailed    = 7
+	exitDirtyWorkTree = 8
+)
+
+// fatalError logs msg with err and a remediation hint, then exits with the
+// code for err.
+func fatalError(logger *slog.Logger, msg string, err error, args ...any) {
+	args = append(args, "error", err)
+	if hint := remediation(err); hint != "" {
+		args = append(args, "hint", hint)
+	}
+	logger.Error(msg, args...)
+	exit(exitCode(err), msg+": "+err.Error())
+}
+
+func exitCode(err error) int {
+	var toolErr *cerrors.ErrToolNotFound
+	var coverageErr *cerrors.ErrCoverageFormat
+	var missingErr *cerrors.ErrCoverageMissing
+	var configErr *cerrors.ErrConfigInvalid
+	var dirtyErr *cerrors.ErrDirtyWorkTree
+
+	switch {
+	case errors.Is(err, cerrors.ErrNotARepo):
+		return exitNotARepo
+	case errors.As(err, &toolErr):
+		return exitToolNotFound
+	case errors.As(err, &coverageErr), errors.As(err, &missingErr):
+		return exitCoverage
+	case errors.As(err, &configErr):
+		return exitConfigInvalid
+	case errors.As(err, &dirtyErr):
+		return exitDirtyWorkTree
+	default:
+		return 1
+	}
+}
+
+// remediation suggests how to fix err, or returns "" when there is nothing
+// specific to suggest.
+func remediation(err error) string {
+	var toolErr *cerrors.ErrToolNotFound
+	var coverageErr *cerrors.ErrCoverageFormat
+	var missingErr *cerrors.ErrCoverageMissing
+	var configErr *cerrors.ErrConfigInvalid
+	var tokenErr *cerrors.ErrMissingToken
+	var limitErr *cerrors.ErrRateLimited
+	var dirtyErr *cerrors.ErrDirtyWorkTree
+
+	switch {
+	case errors.Is(err, cerrors.ErrNotARepo):
+		return "run from within a git repository or pass a repository path"
+	case errors.Is(err, cerrors.ErrNoCommits):
+		return "commit some files (git add . && git commit) to analyze authors and history"
+	case errors.Is(err, cerrors.ErrNoTags):
+		return "tag a release (git tag v1.0.0), or run without --changed-since-tag"
+	case errors.As(err, &toolErr):
+		switch toolErr.Tool {
+		case "npx":
+			return "install Node.js so ESLint can run through npx"
+		case "ruff":
+			return "install ruff (pip install ruff) or run without --ruff"
+		default:
+			return fmt.Sprintf("install %s and make sure it is on PATH", toolErr.Tool)
+		}
+	case errors.As(err, &coverageErr):
+		return "pass an LCOV report (lcov.info) with --coverage-file"
+	case errors.As(err, &missingErr):
+		return "run the tests with coverage first, such as npm test -- --coverage, or pass the report with --coverage-file"
+	case errors.As(err, &configErr):
+		return fmt.Sprintf("fix %s in the config file, or run --generate-config for a valid sample", configErr.Key)
+	case errors.As(err, &tokenErr):
+		return fmt.Sprintf("export %s with a token that can read the repository", tokenErr.Env)
+	case errors.As(err, &dirtyErr):
+		return "commit or stash your changes (git stash --include-untracked), or run without --require-clean"
+	case errors.As(err, &limitErr):
+		return fmt.Sprintf("rerun after %s; cached responses do not count against the limit", limitErr.Reset.Format("15:04"))
+	default:
+		return ""
+	}
+}
+
+// printHint prints the remediation for err below a warning, if there is one.
+func printHint(err error) {
+	if hint := remediation(err); hint != "" {
+		fmt.Printf("   💡 %s\n", infoStyle.Render(hint))
+	}
+}
+
+func showCompassArt(w io.Writer) {
+	fmt.Fprint(w, compassArtStyle.Render(`
         🧭 CodeCompass 🧭
              
             ╭─────╮
@@ -635,82 +1967,160 @@ func showCompassArt() {
     📈 Monitor Progress
 `))
 
-	fmt.Println(logoStyle.Render("\nCodeCompass v" + VERSION))
-	fmt.Println(infoStyle.Render("Your comprehensive code quality navigation tool"))
-	fmt.Println(infoStyle.Render("Navigate your codebase with precision and insight"))
+	fmt.Fprintln(w, logoStyle.Render("\nCodeCompass v"+VERSION))
+	fmt.Fprintln(w, infoStyle.Render("Your comprehensive code quality navigation tool"))
+	fmt.Fprintln(w, infoStyle.Render("Navigate your codebase with precision and insight"))
 }
 
-func showVersion() {
-	fmt.Printf("%s %s v%s\n", MINI_COMPASS, PROJECT_NAME, versionStyle.Render(VERSION))
-	fmt.Println(infoStyle.Render("A comprehensive code quality navigation tool"))
-	fmt.Println(infoStyle.Render("Navigate your codebase with precision and insight"))
-	fmt.Println(infoStyle.Render("Built with Go - https://github.com/your-org/codecompass"))
+func showVersion(w io.Writer) {
+	fmt.Fprintf(w, "%s %s v%s\n", MINI_COMPASS, PROJECT_NAME, versionStyle.Render(VERSION))
+	fmt.Fprintln(w, infoStyle.Render("A comprehensive code quality navigation tool"))
+	fmt.Fprintln(w, infoStyle.Render("Navigate your codebase with precision and insight"))
+	fmt.Fprintln(w, infoStyle.Render("Built with Go - https://github.com/your-org/codecompass"))
 }
 
-func showUsage() {
-	fmt.Print(compassArtStyle.Render(COMPASS_ART))
-	fmt.Println(leaderboardTitleStyle.Render("CodeCompass - Navigate Your Code Quality"))
-	fmt.Println(usageHeaderStyle.Render("\nUSAGE:"))
-	fmt.Printf("  %s [OPTIONS] [DIRECTORY]\n\n", os.Args[0])
-
-	fmt.Println(usageHeaderStyle.Render("ARGUMENTS:"))
-	fmt.Println(infoStyle.Render("  DIRECTORY              Target git repository directory (default: current directory)\n"))
-
-	fmt.Println(usageHeaderStyle.Render("COMPASS DIRECTIONS (Leaderboards):"))
-	fmt.Printf("  %s North    --authors              Author leaderboard (lint issue contributors)\n", MINI_COMPASS)
-	fmt.Printf("  %s South    --files                File leaderboard (most problematic files)\n", MINI_COMPASS)
-	fmt.Printf("  %s East     --rules                Rule leaderboard (most violated rules)\n", MINI_COMPASS)
-	fmt.Printf("  %s West     --loc                  Lines of code leaderboard\n", MINI_COMPASS)
-	fmt.Printf("  %s NE       --commits              Regular commit count leaderboard (non-merges)\n", MINI_COMPASS)
-	fmt.Printf("  %s NNE      --merges               Merge commit count leaderboard\n", MINI_COMPASS)
-	fmt.Printf("  %s NW       --recent               Recent contributors leaderboard\n", MINI_COMPASS)
-	fmt.Printf("  %s SE       --coverage             Code coverage leaderboard\n", MINI_COMPASS)
-	fmt.Printf("  %s SW       --churn                Code churn leaderboard\n", MINI_COMPASS)
-	fmt.Printf("  %s SSE      --bugs                 Bug density leaderboard\n", MINI_COMPASS)
-	fmt.Printf("  %s SSW      --debt                 Technical debt leaderboard\n", MINI_COMPASS)
-	fmt.Printf("  %s NNW      --complexity           Code complexity leaderboard (coming soon)\n", MINI_COMPASS)
-	fmt.Printf("  %s ENE      --spellcheck           Spell check leaderboard\n", MINI_COMPASS)
-		fmt.Printf("  %s WNW      --ruff                   Ruff (Python) leaderboard\n", MINI_COMPASS)
-		fmt.Printf("  %s Center   --summary              Repository summary\n\n", MINI_COMPASS)
-
-	fmt.Println(usageHeaderStyle.Render("CONFIGURATION OPTIONS:"))
-	fmt.Println(infoStyle.Render("  --config FILE          Path to configuration file (.codecompass.rc)"))
-	fmt.Println(infoStyle.Render("  --generate-config      Generate a sample configuration file"))
-	fmt.Println(infoStyle.Render("  --show-config          Show current configuration and exit"))
-	fmt.Println(infoStyle.Render("  --top N                Number of entries to show in leaderboards (default: 15)"))
-	fmt.Println(infoStyle.Render("  --ignore RULES         Comma-separated ESLint rules to ignore"))
-	fmt.Println(infoStyle.Render("  --coverage-file FILE   Path to coverage file (auto-detected if not specified)\n"))
-
-	fmt.Println(usageHeaderStyle.Render("DISPLAY OPTIONS:"))
-	fmt.Println(infoStyle.Render("  --logo                 Show CodeCompass ASCII art"))
-	fmt.Println(infoStyle.Render("  --cache                Enable caching for better performance (default: true)"))
-	fmt.Println(infoStyle.Render("  --verbose              Enable verbose output"))
-	fmt.Println(infoStyle.Render("  --quiet                Suppress non-essential output\n"))
-
-	fmt.Println(usageHeaderStyle.Render("HISTORY LOGGING OPTIONS:"))
-	fmt.Println(infoStyle.Render("  --log-history          Enable logging of leaderboard data to CSV files"))
-	fmt.Println(infoStyle.Render("  --log-dir DIR          Directory to save leaderboard CSV logs (default: .codecompass/history)\n"))
-
-	fmt.Println(usageHeaderStyle.Render("OTHER OPTIONS:"))
-	fmt.Println(infoStyle.Render("  -h, --help             Show this help message"))
-	fmt.Println(infoStyle.Render("  -v, --version          Show version information\n"))
-
-	fmt.Println(usageHeaderStyle.Render("NAVIGATION EXAMPLES:"))
-	fmt.Printf("  %s --all                              # Full compass navigation (all leaderboards)\n", os.Args[0])
-	fmt.Printf("  %s                                     # Show help message\n", os.Args[0])
-	fmt.Printf("  %s /path/to/repo --commits --merges   # Navigate specific repository and compare commits\n", os.Args[0])
-	fmt.Printf("  %s --authors --files                  # North & South directions only\n", os.Args[0])
-	fmt.Printf("  %s --loc --coverage                   # West & SE directions (no ESLint)\n", os.Args[0])
-	fmt.Printf("  %s --generate-config                  # Create .codecompass.rc file\n\n", os.Args[0])
-
-	fmt.Println(usageHeaderStyle.Render("CONFIGURATION FILE:"))
-	fmt.Println(infoStyle.Render("  CodeCompass looks for configuration files in this order:"))
-	fmt.Println(infoStyle.Render("  1. .codecompass.rc"))
-	fmt.Println(infoStyle.Render("  2. .codecompass.config"))
-	fmt.Println(infoStyle.Render("  3. codecompass.config\n"))
-
-	fmt.Println(infoStyle.Render("  Use --generate-config to create a sample configuration file."))
+func showUsage(w io.Writer) {
+	fmt.Fprint(w, compassArtStyle.Render(COMPASS_ART))
+	fmt.Fprintln(w, leaderboardTitleStyle.Render("CodeCompass - Navigate Your Code Quality"))
+	fmt.Fprintln(w, usageHeaderStyle.Render("\nUSAGE:"))
+	fmt.Fprintf(w, "  %s [OPTIONS] [DIRECTORY]\n", os.Args[0])
+	fmt.Fprintf(w, "  %s multi --repos-file FILE [OPTIONS]\n", os.Args[0])
+	fmt.Fprintf(w, "  %s history diff RUN_A RUN_B [OPTIONS]\n", os.Args[0])
+	fmt.Fprintf(w, "  %s history runs [OPTIONS]\n\n", os.Args[0])
+
+	fmt.Fprintln(w, usageHeaderStyle.Render("ARGUMENTS:"))
+	fmt.Fprintln(w, infoStyle.Render("  DIRECTORY              Target git repository directory (default: current directory)\n"))
+
+	fmt.Fprintln(w, usageHeaderStyle.Render("COMPASS DIRECTIONS (Leaderboards):"))
+	fmt.Fprintf(w, "  %s North    --authors              Author leaderboard (lint issue contributors)\n", MINI_COMPASS)
+	fmt.Fprintf(w, "  %s South    --files                File leaderboard (most problematic files)\n", MINI_COMPASS)
+	fmt.Fprintf(w, "  %s South+   --files-detail         File leaderboard with the top authors of each file\n", MINI_COMPASS)
+	fmt.Fprintf(w, "  %s South++  --files-by-owner       File leaderboard regrouped into a worklist per owner\n", MINI_COMPASS)
+	fmt.Fprintf(w, "  %s East     --rules                Rule leaderboard (most violated rules)\n", MINI_COMPASS)
+	fmt.Fprintf(w, "  %s East+    --rule-plugins         Rule leaderboard rolled up by plugin\n", MINI_COMPASS)
+	fmt.Fprintf(w, "  %s East++   --group-rules          Rule leaderboard rolled up by the rule groups of the config\n", MINI_COMPASS)
+	fmt.Fprintf(w, "  %s East+++  --rule-author-matrix   Top author of each rule next to the top rule of each author\n", MINI_COMPASS)
+	fmt.Fprintf(w, "  %s West     --loc                  Lines of code leaderboard\n", MINI_COMPASS)
+	fmt.Fprintf(w, "  %s NE       --commits              Regular commit count leaderboard (non-merges)\n", MINI_COMPASS)
+	fmt.Fprintf(w, "  %s NNE      --merges               Merge commit count leaderboard\n", MINI_COMPASS)
+	fmt.Fprintf(w, "  %s NW       --recent               Recent contributors leaderboard\n", MINI_COMPASS)
+	fmt.Fprintf(w, "  %s NW+      --new-contributors     Authors whose first commit falls in the --since window\n", MINI_COMPASS)
+	fmt.Fprintf(w, "  %s SE       --coverage             Code coverage leaderboard\n", MINI_COMPASS)
+	fmt.Fprintf(w, "  %s SW       --churn                Code churn leaderboard\n", MINI_COMPASS)
+	fmt.Fprintf(w, "  %s SW+      --churn-rate           Code churn leaderboard ranked by changes per month since each file was added\n", MINI_COMPASS)
+	fmt.Fprintf(w, "  %s SW++     --deletions            Authors and commits that removed the most lines\n", MINI_COMPASS)
+	fmt.Fprintf(w, "  %s SW+++    --hotspots             Files both changed often and with many issues, the ones to refactor first\n", MINI_COMPASS)
+	fmt.Fprintf(w, "  %s SSE      --bugs                 Bug density leaderboard\n", MINI_COMPASS)
+	fmt.Fprintf(w, "  %s SSW      --debt                 Technical debt leaderboard\n", MINI_COMPASS)
+	fmt.Fprintf(w, "  %s NNW      --complexity           Code complexity leaderboard (coming soon)\n", MINI_COMPASS)
+	fmt.Fprintf(w, "  %s NNW+     --long-functions       Longest Go, JavaScript and TypeScript functions\n", MINI_COMPASS)
+	fmt.Fprintf(w, "  %s ENE      --spellcheck           Spell check leaderboard\n", MINI_COMPASS)
+	fmt.Fprintf(w, "  %s WNW      --ruff                   Ruff (Python) leaderboard\n", MINI_COMPASS)
+	fmt.Fprintf(w, "  %s WNW+     --format-drift         Python files ruff format and import sorting would rewrite\n", MINI_COMPASS)
+	fmt.Fprintf(w, "  %s ESE      --encoding-check       Line ending and encoding leaderboard\n", MINI_COMPASS)
+	fmt.Fprintf(w, "  %s ESE++    --lfs                  Git LFS pattern coverage and pointer integrity\n", MINI_COMPASS)
+	fmt.Fprintf(w, "  %s ESE+     --vulns                npm audit and pip-audit vulnerability leaderboard\n", MINI_COMPASS)
+	fmt.Fprintf(w, "  %s ESE+++   --deps                 Direct and locked dependencies per lockfile, pinned vs ranged\n", MINI_COMPASS)
+	fmt.Fprintf(w, "  %s WSW      --github-stats         GitHub or GitLab pull request and review leaderboards\n", MINI_COMPASS)
+	fmt.Fprintf(w, "  %s WSW+     --lead-time            Branch lead time and merge cadence from git history\n", MINI_COMPASS)
+	fmt.Fprintf(w, "  %s SSW+     --changelog-readiness  Conventional Commit types per author, unparseable and breaking commits\n", MINI_COMPASS)
+	fmt.Fprintf(w, "  %s WSW++    --timezones            Author UTC offsets and weekend or off-hours commit shares\n", MINI_COMPASS)
+	fmt.Fprintf(w, "  %s Center   --summary              Repository summary\n", MINI_COMPASS)
+	fmt.Fprintf(w, "  %s Center+  --by-workspace         Issues, coverage, debt and LOC per monorepo package\n", MINI_COMPASS)
+	fmt.Fprintf(w, "  %s North+   --score                Weighted quality score per file and for the repository\n", MINI_COMPASS)
+	fmt.Fprintf(w, "  %s North*   --report-card          Overall letter grade (weighted A-F)\n", MINI_COMPASS)
+	fmt.Fprintf(w, "  %s Overview --dashboard            One-screen summary of totals, coverage and the top entries\n\n", MINI_COMPASS)
+
+	fmt.Fprintln(w, usageHeaderStyle.Render("CONFIGURATION OPTIONS:"))
+	fmt.Fprintln(w, infoStyle.Render("  --config FILE          Path to configuration file (.codecompass.rc)"))
+	fmt.Fprintln(w, infoStyle.Render("  --generate-config      Generate a sample configuration file"))
+	fmt.Fprintln(w, infoStyle.Render("  --show-config          Show current configuration and exit"))
+	fmt.Fprintln(w, infoStyle.Render("  --dump-effective-config Print the resolved configuration as a .codecompass.rc file"))
+	fmt.Fprintln(w, infoStyle.Render("  --top N                Number of entries to show in leaderboards (default: 15)"))
+	fmt.Fprintln(w, infoStyle.Render("  --ignore RULES         Comma-separated ESLint rules to ignore"))
+	fmt.Fprintln(w, infoStyle.Render("  --ignore-rule-prefix P Comma-separated rule prefixes to ignore, such as @typescript-eslint/ or D"))
+	fmt.Fprintln(w, infoStyle.Render("  --coverage-file FILE   Path to coverage file (auto-detected if not specified)"))
+	fmt.Fprintln(w, infoStyle.Render("  --checkstyle FILE      Read lint issues from a checkstyle XML report (Checkstyle, PMD, PHP_CodeSniffer...)"))
+	fmt.Fprintln(w, infoStyle.Render("  --include-untracked    Add untracked, not ignored files to the LOC, debt and spell check leaderboards"))
+	fmt.Fprintln(w, infoStyle.Render("  --lang LANGS           Only lint and measure files of these languages, such as js,python,go"))
+	fmt.Fprintln(w, infoStyle.Render("  --only-ext EXTS        Only lint and measure files with these extensions, such as ts,tsx"))
+	fmt.Fprintln(w, infoStyle.Render("  --exclude-ext EXTS     Leave files with these extensions out, such as md,json"))
+	fmt.Fprintln(w, infoStyle.Render("  --include-vendored     Keep vendored, third-party and generated files in the leaderboards that read file contents and --deletions"))
+	fmt.Fprintln(w, infoStyle.Render("  --require-clean        Refuse to run when the work tree has uncommitted changes or untracked files"))
+	fmt.Fprintln(w, infoStyle.Render("  --require-coverage     Fail the run when the coverage file is missing or cannot be parsed"))
+	fmt.Fprintln(w, infoStyle.Render("  --changed-since-tag    Only lint and measure the files changed since the latest tag, and count the commits since it"))
+	fmt.Fprintln(w, infoStyle.Render("  --sort files=COLUMN    Sort the file leaderboard by issues (default), errors or warnings"))
+	fmt.Fprintln(w, infoStyle.Render("  --sort churn=COLUMN    Sort the churn leaderboard by changes (default) or rate, the changes per month since a file was added"))
+	fmt.Fprintln(w, infoStyle.Render("  --path-style STYLE     How file leaderboards show paths: full (default), basename or truncate"))
+	fmt.Fprintln(w, infoStyle.Render("  --compare-branches A,B Compare coverage, lint issues, debt and lines of code between two refs"))
+	fmt.Fprintln(w, infoStyle.Render("  --repos-file FILE      With multi, the repositories to analyze: one local path or clone URL per line"))
+	fmt.Fprintln(w, infoStyle.Render("  --parallel N           With multi, how many repositories to analyze at once (default: 4)"))
+	fmt.Fprintln(w, infoStyle.Render("  --fail-on CONDITIONS   Exit with status 7 when a condition such as score<70 or vulns-critical>0 holds; coverage alone fails below min-coverage-threshold, coverage-age alone above coverage-max-age, new-issues alone on any new issue"))
+	fmt.Fprintln(w, infoStyle.Render("  --date-type TYPE       Commit timestamps to use: author (default) or commit"))
+	fmt.Fprintln(w, infoStyle.Render("  --co-author-credit MODE Credit Co-authored-by trailers: full (default), split or none"))
+	fmt.Fprintln(w, infoStyle.Render("  --decay-halflife-days N Rank authors and files by issues weighted by the age of their lines, halving every N days"))
+	fmt.Fprintln(w, infoStyle.Render("  --weighted             Rank authors and files, and score issues, by weight-error, weight-warning and rule-weights"))
+	fmt.Fprintln(w, infoStyle.Render("  --since WHEN           Start of the --github-stats, --lead-time, --changelog-readiness, --deletions and --new-contributors window: 30d, 12w or YYYY-MM-DD (default: 90d)\n"))
+
+	fmt.Fprintln(w, usageHeaderStyle.Render("DISPLAY OPTIONS:"))
+	fmt.Fprintln(w, infoStyle.Render("  --logo                 Show CodeCompass ASCII art"))
+	fmt.Fprintln(w, infoStyle.Render("  --decorations MODE     Print the compass art and heading compasses: auto (on a terminal), on or off"))
+	fmt.Fprintln(w, infoStyle.Render("  --width N              Fit leaderboards to N columns (default: the terminal width, or $COLUMNS)"))
+	fmt.Fprintln(w, infoStyle.Render("  --group-numbers        Group the digits of counts in thousands, such as 12,345, as the locale does"))
+	fmt.Fprintln(w, infoStyle.Render("  --no-pager             Print the leaderboards at once, even when they are taller than the terminal"))
+	fmt.Fprintln(w, infoStyle.Render("  --cache                Enable caching for better performance (default: true)"))
+	fmt.Fprintln(w, infoStyle.Render("  --state-file FILE      Save finished leaderboards to FILE and reuse them on a repeated run"))
+	fmt.Fprintln(w, infoStyle.Render("  --save-report FILE     Save the report of the run to FILE as JSON"))
+	fmt.Fprintln(w, infoStyle.Render("  --load-report FILE     Show a report saved with --save-report instead of running"))
+	fmt.Fprintln(w, infoStyle.Render("  --attention-out FILE   Write the files below the attention thresholds, with the reasons, to FILE as JSON"))
+	fmt.Fprintln(w, infoStyle.Render("  --files-by-owner-out FILE  Write the --files-by-owner worklists to FILE as Markdown"))
+	fmt.Fprintln(w, infoStyle.Render("  --warnings-out FILE    Write the warnings of the run to FILE as JSON records"))
+	fmt.Fprintln(w, infoStyle.Render("  --author-report-dir DIR    Write a Markdown packet per author to DIR, for --author-report emails or the --top authors"))
+	fmt.Fprintln(w, infoStyle.Render("  --verbose              Enable verbose output"))
+	fmt.Fprintln(w, infoStyle.Render("  --quiet                Suppress non-essential output"))
+	fmt.Fprintln(w, infoStyle.Render("  --log-json             Write logs to stderr as JSON lines\n"))
+
+	fmt.Fprintln(w, usageHeaderStyle.Render("HISTORY LOGGING OPTIONS:"))
+	fmt.Fprintln(w, infoStyle.Render("  --log-history          Enable logging of leaderboard data to CSV files"))
+	fmt.Fprintln(w, infoStyle.Render("  --log-dir DIR          Directory to save leaderboard CSV logs (default: .codecompass/history in the repository)"))
+	fmt.Fprintln(w, infoStyle.Render("  --sanitize-csv         Prefix formula-like cells with ' in CSV logs (default: true)"))
+	fmt.Fprintln(w, infoStyle.Render("  --baseline write       Save the issues found to --log-dir as the baseline new issues are told apart from"))
+	fmt.Fprintln(w, infoStyle.Render("  --baseline-write FILE  Save the issues, debt markers and misspellings found as the suppression baseline"))
+	fmt.Fprintln(w, infoStyle.Render("  --baseline-file FILE   Read the suppression baseline from FILE instead of .codecompass-baseline.json"))
+	fmt.Fprintln(w, infoStyle.Render("  --include-baselined    Keep the findings of .codecompass-baseline.json in the leaderboards"))
+	fmt.Fprintln(w, infoStyle.Render("  --timeseries-out FILE  Write the history in --log-dir to FILE as JSON time series for Grafana"))
+	fmt.Fprintln(w, infoStyle.Render("  --run-log FILE         Append a JSON line recording each run to FILE, even when it fails (default: runs.log in --log-dir with --log-history)"))
+	fmt.Fprintln(w, infoStyle.Render("  --leaderboard NAMES    With history diff, the leaderboards to compare, such as files,debt (default: every one both runs logged)"))
+	fmt.Fprintln(w, infoStyle.Render("  --format FORMAT        With history diff, print the diff as text (default) or markdown\n"))
+
+	fmt.Fprintln(w, usageHeaderStyle.Render("NOTIFICATION OPTIONS:"))
+	fmt.Fprintln(w, infoStyle.Render("  --webhook URL          POST a JSON summary of the report to URL when the run completes"))
+	fmt.Fprintln(w, infoStyle.Render("  --webhook-token TOKEN  Bearer token to send with --webhook\n"))
+
+	fmt.Fprintln(w, usageHeaderStyle.Render("SERVING OPTIONS:"))
+	fmt.Fprintln(w, infoStyle.Render("  --serve ADDR           Serve the report as JSON at /report and as HTML at / on ADDR, such as :8080"))
+	fmt.Fprintln(w, infoStyle.Render("  --serve-ttl DURATION   Analyze again on the first request after DURATION, such as 10m (default: never)\n"))
+
+	fmt.Fprintln(w, usageHeaderStyle.Render("OTHER OPTIONS:"))
+	fmt.Fprintln(w, infoStyle.Render("  -h, --help             Show this help message"))
+	fmt.Fprintln(w, infoStyle.Render("  -v, --version          Show version information\n"))
+
+	fmt.Fprintln(w, usageHeaderStyle.Render("NAVIGATION EXAMPLES:"))
+	fmt.Fprintf(w, "  %s --all                              # Full compass navigation (all leaderboards)\n", os.Args[0])
+	fmt.Fprintf(w, "  %s                                     # Show help message\n", os.Args[0])
+	fmt.Fprintf(w, "  %s /path/to/repo --commits --merges   # Navigate specific repository and compare commits\n", os.Args[0])
+	fmt.Fprintf(w, "  %s --authors --files                  # North & South directions only\n", os.Args[0])
+	fmt.Fprintf(w, "  %s --loc --coverage                   # West & SE directions (no ESLint)\n", os.Args[0])
+	fmt.Fprintf(w, "  %s multi --repos-file repos.txt       # Combine the leaderboards of several repositories\n", os.Args[0])
+	fmt.Fprintf(w, "  %s history diff 20260101 20260201    # Compare the leaderboards of two logged runs\n", os.Args[0])
+	fmt.Fprintf(w, "  %s history runs --top 20             # List the last 20 runs recorded in the run log\n", os.Args[0])
+	fmt.Fprintf(w, "  %s --generate-config                  # Create .codecompass.rc file\n\n", os.Args[0])
+
+	fmt.Fprintln(w, usageHeaderStyle.Render("CONFIGURATION FILE:"))
+	fmt.Fprintln(w, infoStyle.Render("  CodeCompass looks for configuration files in this order:"))
+	fmt.Fprintln(w, infoStyle.Render("  1. .codecompass.rc"))
+	fmt.Fprintln(w, infoStyle.Render("  2. .codecompass.config"))
+	fmt.Fprintln(w, infoStyle.Render("  3. codecompass.config\n"))
+
+	fmt.Fprintln(w, infoStyle.Render("  Use --generate-config to create a sample configuration file."))
 }
-
-
-
@@ -1,55 +1,314 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/xeon-zolt/codecompass/internal/cerrors"
+	"github.com/xeon-zolt/codecompass/internal/history"
+	"github.com/xeon-zolt/codecompass/internal/testutil"
+	"github.com/xeon-zolt/codecompass/internal/types"
 )
 
+// runMainEnv makes the test binary run the CLI instead of the tests, so a
+// test can run it as a subprocess and read its output through a pipe.
+const runMainEnv = "CODECOMPASS_TEST_RUN_MAIN"
+
 func TestMain(m *testing.M) {
+	if os.Getenv(runMainEnv) == "1" {
+		os.Args = append([]string{"codecompass"}, os.Args[1:]...)
+		main()
+		os.Exit(0)
+	}
+
 	// Run tests
 	os.Exit(m.Run())
 }
 
-func TestShowVersion(t *testing.T) {
-	// Redirect stdout to capture output
-	old := os.Stdout
-	r, w, _ := os.Pipe()
-	os.Stdout = w
+// runPiped runs the CLI with args in an empty directory and returns what it
+// wrote to stdout, which is a pipe.
+func runPiped(t *testing.T, args ...string) string {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Dir = t.TempDir()
+	cmd.Env = append(os.Environ(), runMainEnv+"=1")
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("codecompass %s failed: %v", strings.Join(args, " "), err)
+	}
+	return string(out)
+}
 
-	showVersion()
+func TestPathFlagsWithTargetDirectory(t *testing.T) {
+	repo := testutil.NewRepo(t).
+		Commit("initial commit", map[string]string{"a.js": "let a = 1\nlet b = 2\n"}).
+		Dir()
 
-	w.Close()
-	os.Stdout = old
+	// The coverage report and history are next to where codecompass runs,
+	// not in the repository
+	cwd := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(cwd, "coverage"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	lcov := "SF:a.js\nDA:1,1\nDA:2,0\nend_of_record\n"
+	if err := os.WriteFile(filepath.Join(cwd, "coverage", "lcov.info"), []byte(lcov), 0644); err != nil {
+		t.Fatal(err)
+	}
 
-	// Read the output
-	buf := make([]byte, 1024)
-	n, _ := r.Read(buf)
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command(os.Args[0], append([]string{repo, "--coverage", "--log-history", "--quiet"}, args...)...)
+		cmd.Dir = cwd
+		cmd.Env = append(os.Environ(), runMainEnv+"=1")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("codecompass %s failed: %v\n%s", strings.Join(cmd.Args[1:], " "), err, out)
+		}
+	}
+
+	run("--coverage-file", "coverage/lcov.info", "--log-dir", "logs")
+	coverageLogs, _ := filepath.Glob(filepath.Join(cwd, "logs", "coverage_leaderboard_*.csv"))
+	if len(coverageLogs) != 1 {
+		t.Fatalf("Expected the coverage of coverage/lcov.info logged to logs in the working directory, but got %v", coverageLogs)
+	}
+	content, err := os.ReadFile(coverageLogs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "a.js") {
+		t.Errorf("Expected the coverage of a.js to be logged, but got:\n%s", content)
+	}
+	if _, err := os.Stat(filepath.Join(repo, "logs")); !os.IsNotExist(err) {
+		t.Errorf("Expected no logs directory in the repository, but got %v", err)
+	}
+
+	// Without --log-dir, history is kept in the repository
+	run("--coverage-file", filepath.Join(cwd, "coverage", "lcov.info"))
+	if logs, _ := filepath.Glob(filepath.Join(repo, ".codecompass", "history", "coverage_leaderboard_*.csv")); len(logs) != 1 {
+		t.Errorf("Expected the default history in the repository, but got %v", logs)
+	}
+}
+
+func TestRequireCoverageFailsWithoutCoverage(t *testing.T) {
+	repo := testutil.NewRepo(t).
+		Commit("initial commit", map[string]string{"a.js": "let a = 1\n"}).
+		Dir()
+
+	run := func(args ...string) (int, string) {
+		t.Helper()
+		cmd := exec.Command(os.Args[0], append([]string{repo, "--loc", "--quiet"}, args...)...)
+		cmd.Dir = t.TempDir()
+		cmd.Env = append(os.Environ(), runMainEnv+"=1")
+		out, err := cmd.CombinedOutput()
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.ExitCode(), string(out)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		return 0, string(out)
+	}
+
+	if code, out := run(); code != 0 {
+		t.Fatalf("Expected the run without coverage to pass, but it exited with %d:\n%s", code, out)
+	}
+	for _, args := range [][]string{{"--require-coverage"}, {"--require-coverage", "--coverage-file", "missing/lcov.info"}} {
+		if code, out := run(args...); code != exitCoverage || !strings.Contains(out, "Coverage is required") {
+			t.Errorf("Expected %s to exit with %d, but it exited with %d:\n%s", strings.Join(args, " "), exitCoverage, code, out)
+		}
+	}
+}
+
+func TestRunLogRecordsFailedRuns(t *testing.T) {
+	repo := testutil.NewRepo(t).
+		Commit("initial commit", map[string]string{"a.js": "let a = 1\n"}).
+		Dir()
+	runLog := filepath.Join(t.TempDir(), "audit", "runs.log")
+
+	run := func(args ...string) (int, string) {
+		t.Helper()
+		cmd := exec.Command(os.Args[0], args...)
+		cmd.Dir = t.TempDir()
+		cmd.Env = append(os.Environ(), runMainEnv+"=1")
+		out, err := cmd.CombinedOutput()
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.ExitCode(), string(out)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		return 0, string(out)
+	}
+
+	if code, out := run(repo, "--loc", "--quiet", "--run-log", runLog); code != 0 {
+		t.Fatalf("Expected the run to pass, but it exited with %d:\n%s", code, out)
+	}
+	// A run that fails partway is recorded all the same
+	if code, out := run(repo, "--loc", "--quiet", "--run-log", runLog, "--require-coverage", "--webhook-token", "secret"); code != exitCoverage {
+		t.Fatalf("Expected the run to exit with %d, but it exited with %d:\n%s", exitCoverage, code, out)
+	}
+
+	records, err := history.ReadRunLog(runLog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 runs recorded, but got %+v", records)
+	}
+	passed, failed := records[0], records[1]
+	if passed.ExitStatus != 0 || passed.Head == "" || passed.Dirty || passed.Leaderboards["loc"] != 1 || passed.ConfigFingerprint == "" || passed.Flags["loc"] != "true" {
+		t.Errorf("Expected the passing run recorded with its commit, config and leaderboards, but got %+v", passed)
+	}
+	if failed.ExitStatus != exitCoverage || !strings.Contains(failed.Error, "Coverage is required") || failed.Head != passed.Head {
+		t.Errorf("Expected the failed run recorded with its exit status and error, but got %+v", failed)
+	}
+	if failed.Flags["webhook-token"] != "redacted" {
+		t.Errorf("Expected the token hidden, but got %q", failed.Flags["webhook-token"])
+	}
+
+	code, out := run("history", "runs", "--run-log", runLog)
+	if code != 0 || !strings.Contains(out, "Run Log") || !strings.Contains(out, "loc(1)") || !strings.Contains(out, "Coverage is required") {
+		t.Errorf("Expected history runs to list both runs, but it exited with %d:\n%s", code, out)
+	}
+}
+
+func TestBaselineWriteSuppressesNextRun(t *testing.T) {
+	// run runs a debt leaderboard of repo and returns the report it saved
+	run := func(t *testing.T, repo string, args ...string) types.Report {
+		t.Helper()
+		out := t.TempDir()
+		path := filepath.Join(out, "report.json")
+		cmd := exec.Command(os.Args[0], append([]string{repo, "--debt", "--quiet", "--save-report", path}, args...)...)
+		cmd.Dir = filepath.Dir(repo)
+		cmd.Env = append(os.Environ(), runMainEnv+"=1")
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("codecompass %s failed: %v\n%s", strings.Join(args, " "), err, output)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var report types.Report
+		if err := json.Unmarshal(data, &report); err != nil {
+			t.Fatal(err)
+		}
+		return report
+	}
+
+	for _, tt := range []struct {
+		name     string
+		file     string
+		fileFlag bool
+	}{
+		{"default file", ".codecompass-baseline.json", false},
+		{"baseline file", "baseline.json", true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := testutil.NewRepo(t).
+				Commit("initial commit", map[string]string{"a.js": "// TODO: remove this\nlet a = 1\n"}).
+				Dir()
+			// Relative paths are relative to where codecompass runs, the
+			// directory above the repository
+			file := filepath.Join(filepath.Base(repo), tt.file)
+			var read []string
+			if tt.fileFlag {
+				read = []string{"--baseline-file", file}
+			}
+
+			if report := run(t, repo, "--baseline-write", file); len(report.TechnicalDebt) != 1 {
+				t.Fatalf("Expected the TODO counted before the baseline, but got %+v", report.TechnicalDebt)
+			}
+			report := run(t, repo, read...)
+			if len(report.TechnicalDebt) != 0 || report.Suppression == nil || report.Suppression.DebtMarkers != 1 {
+				t.Errorf("Expected the TODO suppressed by the baseline, but got %+v and %+v", report.TechnicalDebt, report.Suppression)
+			}
+		})
+	}
+}
+
+func TestParseArguments(t *testing.T) {
+	tests := []struct {
+		arguments  []string
+		positional []string
+		top        int
+	}{
+		{[]string{"--top", "3"}, nil, 3},
+		{[]string{"repo", "--top", "3"}, []string{"repo"}, 3},
+		{[]string{"--top", "3", "repo", "other"}, []string{"repo", "other"}, 3},
+		{[]string{"repo", "--", "--top"}, []string{"repo", "--top"}, 15},
+	}
+
+	for _, tt := range tests {
+		flags := flag.NewFlagSet("codecompass", flag.ContinueOnError)
+		top := flags.Int("top", 15, "")
+		positional := parseArguments(flags, tt.arguments)
+		if !reflect.DeepEqual(positional, tt.positional) || *top != tt.top {
+			t.Errorf("parseArguments(%q) = %q with --top %d; expected %q with --top %d", tt.arguments, positional, *top, tt.positional, tt.top)
+		}
+	}
+}
+
+func TestShowVersion(t *testing.T) {
+	var buf bytes.Buffer
+	showVersion(&buf)
 
 	// Check if the version is printed
-	if !contains(string(buf[:n]), VERSION) {
+	if !contains(buf.String(), VERSION) {
 		t.Errorf("Expected version %s to be printed", VERSION)
 	}
 }
 
 func TestShowCompassArt(t *testing.T) {
-	// Redirect stdout to capture output
-	old := os.Stdout
-	r, w, _ := os.Pipe()
-	os.Stdout = w
+	var buf bytes.Buffer
+	showCompassArt(&buf)
+
+	// Check if the compass art is printed
+	if !contains(buf.String(), "CodeCompass") {
+		t.Errorf("Expected compass art to be printed")
+	}
+}
 
-	showCompassArt()
+func TestPipedOutputOmitsBanner(t *testing.T) {
+	banner := "Navigate Your Code Quality"
+	if out := runPiped(t, "--show-config"); strings.Contains(out, banner) {
+		t.Errorf("Expected no banner on a pipe, got:\n%s", out)
+	}
+	if out := runPiped(t, "--show-config", "--decorations=on"); !strings.Contains(out, banner) {
+		t.Errorf("Expected the banner with --decorations=on, got:\n%s", out)
+	}
+	if out := runPiped(t, "--show-config", "--decorations=on", "--quiet"); strings.Contains(out, banner) {
+		t.Errorf("Expected --quiet to leave out the banner, got:\n%s", out)
+	}
 
-	w.Close()
-	os.Stdout = old
+	// Section headings carry the compass only when decorated
+	repo := testutil.NewRepo(t).Commit("initial commit", map[string]string{"a.js": "let a = 1\n"}).Dir()
+	if out := runPiped(t, repo, "--ruff"); !strings.Contains(out, "\nWNW: ") || strings.Contains(out, "\u2400") {
+		t.Errorf("Expected a plain Ruff heading on a pipe, got:\n%s", out)
+	}
+	if out := runPiped(t, repo, "--ruff", "--decorations=on"); !strings.Contains(out, "\n"+MINI_COMPASS+" WNW: ") {
+		t.Errorf("Expected the Ruff heading to carry the compass with --decorations=on, got:\n%s", out)
+	}
+}
 
-	// Read the output
-	buf := make([]byte, 1024)
-	n, _ := r.Read(buf)
+func TestShowUsage(t *testing.T) {
+	var buf bytes.Buffer
+	showUsage(&buf)
 
-	// Check if the compass art is printed
-	if !contains(string(buf[:n]), "CodeCompass") {
-		t.Errorf("Expected compass art to be printed")
+	for _, flag := range []string{"--authors", "--report-card", "--date-type", "--since", "--github-stats", "--log-history"} {
+		if !contains(buf.String(), flag) {
+			t.Errorf("Expected usage to document %s", flag)
+		}
 	}
 }
 
@@ -58,3 +317,94 @@ func TestShowCompassArt(t *testing.T) {
 func contains(s, substr string) bool {
 	return strings.Contains(s, substr)
 }
+
+func TestExitCodeAndRemediation(t *testing.T) {
+	tests := []struct {
+		err  error
+		code int
+		hint string
+	}{
+		{fmt.Errorf("%w: /tmp", cerrors.ErrNotARepo), exitNotARepo, "git repository"},
+		{&cerrors.ErrToolNotFound{Tool: "ruff", Err: exec.ErrNotFound}, exitToolNotFound, "--ruff"},
+		{&cerrors.ErrToolNotFound{Tool: "git", Err: exec.ErrNotFound}, exitToolNotFound, "install git"},
+		{&cerrors.ErrCoverageFormat{Path: "coverage.txt", Err: errors.New("unknown format")}, exitCoverage, "--coverage-file"},
+		{&cerrors.ErrCoverageMissing{}, exitCoverage, "with coverage"},
+		{fmt.Errorf(".codecompass.rc:2: %w", &cerrors.ErrConfigInvalid{Key: "max-file-size", Value: "big"}), exitConfigInvalid, "max-file-size"},
+		{fmt.Errorf("failed to fetch pull requests: %w", &cerrors.ErrMissingToken{Env: "GITHUB_TOKEN"}), 1, "export GITHUB_TOKEN"},
+		{&cerrors.ErrRateLimited{API: "GitHub", Reset: time.Date(2024, 6, 1, 13, 30, 0, 0, time.Local)}, 1, "13:30"},
+		{cerrors.ErrNoCommits, 1, "git commit"},
+		{&cerrors.ErrDirtyWorkTree{Files: []string{"a.js"}}, exitDirtyWorkTree, "git stash"},
+		{errors.New("boom"), 1, ""},
+	}
+
+	for _, tt := range tests {
+		if code := exitCode(tt.err); code != tt.code {
+			t.Errorf("exitCode(%v) = %d, expected %d", tt.err, code, tt.code)
+		}
+
+		hint := remediation(tt.err)
+		if tt.hint == "" && hint != "" {
+			t.Errorf("Expected no hint for %v, but got %q", tt.err, hint)
+		} else if !strings.Contains(hint, tt.hint) {
+			t.Errorf("Expected the hint for %v to mention %q, but got %q", tt.err, tt.hint, hint)
+		}
+	}
+}
+
+func TestReadReposFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "repos.txt")
+	content := "# services\n../api\n\nhttps://example.com/acme/web.git\n  /srv/tools  \n../other/../api\nhttps://example.com/acme/web.git\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	repos, err := readReposFile(path)
+	if err != nil {
+		t.Fatalf("readReposFile failed: %v", err)
+	}
+	// Relative paths are resolved against the file, and repeats are skipped
+	expected := []string{filepath.Join(dir, "..", "api"), "https://example.com/acme/web.git", "/srv/tools"}
+	if !reflect.DeepEqual(repos, expected) {
+		t.Errorf("Expected %v, got %v", expected, repos)
+	}
+
+	if err := os.WriteFile(path, []byte("# nothing yet\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readReposFile(path); err == nil {
+		t.Errorf("Expected an error for a file without repositories")
+	}
+}
+
+func TestPrintWarnings(t *testing.T) {
+	var warnings []types.Warning
+	for i := 0; i < 5; i++ {
+		warnings = append(warnings, types.NewWarning("Blame failed", "file", fmt.Sprintf("gen/%d.js", i), "error", "exit status 128"))
+	}
+	warnings = append(warnings,
+		types.NewWarning("Diff failed", "file", "main.js", "error", "exit status 1"),
+		types.NewWarning("Skipped ESLint"),
+	)
+
+	var buf bytes.Buffer
+	printWarnings(&buf, warnings, 2, false)
+	expected := "  ⚠️ Blame failed: 5 warnings, such as gen/0.js, gen/1.js, gen/2.js\n" +
+		"  ⚠️ Diff failed (file=main.js error=exit status 1)\n" +
+		"  ... and 1 more kinds of warnings, use --verbose for the full list\n"
+	if buf.String() != expected {
+		t.Errorf("Expected grouped warnings\n%s\nbut got\n%s", expected, buf.String())
+	}
+
+	// Without a limit every kind is listed, and verbose lists every warning
+	buf.Reset()
+	printWarnings(&buf, warnings, 0, false)
+	if lines := strings.Count(buf.String(), "\n"); lines != 3 {
+		t.Errorf("Expected 3 kinds of warnings, but got\n%s", buf.String())
+	}
+	buf.Reset()
+	printWarnings(&buf, warnings, 2, true)
+	if lines := strings.Count(buf.String(), "\n"); lines != len(warnings) || !strings.Contains(buf.String(), "file=gen/4.js") {
+		t.Errorf("Expected every warning, but got\n%s", buf.String())
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// terminalMaxRows caps the entries of each leaderboard printed to a
+// terminal, so a huge --top cannot freeze it, and capHint says where the
+// rest is.
+const (
+	terminalMaxRows = 200
+	capHint         = "use --save-report, --log-history or pipe the output for the full list"
+)
+
+// defaultLess is LESS for a pager run without it, as git sets it: quit when
+// the output fits one screen, pass colors through and leave the output on
+// the screen after quitting.
+const defaultLess = "FRX"
+
+// pager holds back what is written to stdout, so output taller than the
+// terminal can be shown through a pager once it is complete rather than
+// scrolling past.
+type pager struct {
+	terminal *os.File // stdout while it is held back
+	capture  *os.File // standing in for os.Stdout
+	output   chan []byte
+}
+
+// startPager starts holding back stdout when paging is enabled and stdout
+// is a terminal; piped output and CI logs are never paged. It returns nil
+// otherwise, which finish accepts.
+func startPager(enabled bool) *pager {
+	if !enabled || !isTerminal(os.Stdout) {
+		return nil
+	}
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil
+	}
+
+	p := &pager{terminal: os.Stdout, capture: w, output: make(chan []byte, 1)}
+	go func() {
+		output, _ := io.ReadAll(r)
+		r.Close()
+		p.output <- output
+	}()
+	os.Stdout = w
+	return p
+}
+
+// finish restores stdout and shows what was held back: through the pager
+// when it has more lines than the terminal, otherwise as it is.
+func (p *pager) finish() {
+	if p == nil {
+		return
+	}
+	os.Stdout = p.terminal
+	p.capture.Close()
+	output := <-p.output
+
+	_, height, err := term.GetSize(int(p.terminal.Fd()))
+	if err != nil || !tallerThan(output, height) || !runPager(p.terminal, output) {
+		p.terminal.Write(output)
+	}
+}
+
+// tallerThan reports whether output has more lines than height.
+func tallerThan(output []byte, height int) bool {
+	lines := bytes.Count(output, []byte("\n"))
+	if len(output) > 0 && output[len(output)-1] != '\n' {
+		lines++
+	}
+	return lines > height
+}
+
+// runPager shows output through $PAGER, or less when it is unset, on
+// terminal, and waits for the pager to quit. It reports false when no pager
+// could be started, leaving output to be shown as it is.
+//
+// Ctrl+C reaches the pager too, which handles it, so codecompass ignores it
+// while the pager runs rather than exiting from under it. The terminal is
+// restored afterwards in case the pager died without restoring it.
+func runPager(terminal *os.File, output []byte) bool {
+	command := strings.Fields(os.Getenv("PAGER"))
+	if len(command) == 0 {
+		command = []string{"less"}
+	}
+
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Stdin = bytes.NewReader(output)
+	cmd.Stdout = terminal
+	cmd.Stderr = os.Stderr
+	cmd.Env = os.Environ()
+	if _, ok := os.LookupEnv("LESS"); !ok {
+		cmd.Env = append(cmd.Env, "LESS="+defaultLess)
+	}
+
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	defer signal.Stop(interrupts)
+
+	fd := int(terminal.Fd())
+	state, stateErr := term.GetState(fd)
+	if err := cmd.Start(); err != nil {
+		return false
+	}
+	// A pager quitting before reading everything is not a failure
+	cmd.Wait()
+	if stateErr == nil {
+		term.Restore(fd, state)
+	}
+	return true
+}
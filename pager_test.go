@@ -0,0 +1,82 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestTallerThan(t *testing.T) {
+	tests := []struct {
+		output string
+		height int
+		want   bool
+	}{
+		{"", 2, false},
+		{"a\nb\n", 2, false},
+		{"a\nb\nc\n", 2, true},
+		{"a\nb\nc", 2, true},
+	}
+	for _, tt := range tests {
+		if got := tallerThan([]byte(tt.output), tt.height); got != tt.want {
+			t.Errorf("tallerThan(%q, %d) = %v, want %v", tt.output, tt.height, got, tt.want)
+		}
+	}
+}
+
+func TestRunPager(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake pager is a shell script")
+	}
+
+	dir := t.TempDir()
+	env := filepath.Join(dir, "env")
+	pager := filepath.Join(dir, "fake-pager")
+	script := "#!/bin/sh\necho \"$1 LESS=$LESS\" > " + env + "\ncat\n"
+	if err := os.WriteFile(pager, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	terminal, err := os.Create(filepath.Join(dir, "terminal"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer terminal.Close()
+
+	// LESS is set for the pager when the user has not set it
+	t.Setenv("LESS", "")
+	os.Unsetenv("LESS")
+	t.Setenv("PAGER", pager+" -x")
+	if !runPager(terminal, []byte("line 1\nline 2\n")) {
+		t.Fatal("Expected the pager to run")
+	}
+	shown, _ := os.ReadFile(terminal.Name())
+	if string(shown) != "line 1\nline 2\n" {
+		t.Errorf("Expected the output on the terminal, but got %q", shown)
+	}
+	args, _ := os.ReadFile(env)
+	if string(args) != "-x LESS=FRX\n" {
+		t.Errorf("Expected the pager to get its arguments and LESS=FRX, but got %q", args)
+	}
+
+	t.Setenv("LESS", "R")
+	runPager(terminal, nil)
+	if args, _ := os.ReadFile(env); string(args) != "-x LESS=R\n" {
+		t.Errorf("Expected the user's LESS to be kept, but got %q", args)
+	}
+
+	t.Setenv("PAGER", filepath.Join(dir, "missing-pager"))
+	if runPager(terminal, []byte("line\n")) {
+		t.Error("Expected a missing pager not to run")
+	}
+}
+
+func TestStartPagerWithoutTerminal(t *testing.T) {
+	// Test output is not a terminal, so it is never paged
+	stdout := os.Stdout
+	paging := startPager(true)
+	if paging != nil || os.Stdout != stdout {
+		t.Errorf("Expected output that is not a terminal to be left alone")
+	}
+	paging.finish()
+}
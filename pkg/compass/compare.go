@@ -0,0 +1,128 @@
+package compass
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/xeon-zolt/codecompass/internal/git"
+	"github.com/xeon-zolt/codecompass/internal/toolversion"
+)
+
+// Comparison holds the reports of two refs and the change of each compared
+// metric from RefA to RefB.
+type Comparison struct {
+	RefA, RefB string
+	A, B       *Report
+	Deltas     []MetricDelta
+
+	// ToolChanges are the tools analyzing RefA and RefB at different
+	// versions, such as an ESLint installed in only one of the checkouts
+	ToolChanges []ToolVersionChange
+}
+
+// compareLeaderboards are the leaderboards the compared metrics are read
+// from.
+var compareLeaderboards = []Leaderboard{LeaderboardLinesOfCode, LeaderboardCoverage, LeaderboardDebt, LeaderboardSummary}
+
+// CompareRefs analyzes the repository at refA and at refB and returns the
+// change of coverage, lint issues, technical debt and lines of code. Each
+// ref is checked out in a temporary linked worktree, so the work tree in
+// opts.RepoPath is left alone and uncommitted changes are not part of
+// either side. opts.Leaderboards, opts.State and opts.IncludeUntracked are
+// ignored; a relative opts.CoverageFile is read from each checkout.
+func CompareRefs(ctx context.Context, refA, refB string, opts Options) (*Comparison, error) {
+	dir, err := resolveRepoPath(opts.RepoPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := git.ValidateRepository(ctx, dir); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+
+	tmp, err := os.MkdirTemp("", "codecompass-compare-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a directory for the checkouts: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	comparison := &Comparison{RefA: refA, RefB: refB}
+	for i, ref := range []string{refA, refB} {
+		path := filepath.Join(tmp, fmt.Sprintf("ref%d", i+1))
+		if err := git.AddWorktree(ctx, dir, ref, path); err != nil {
+			return nil, err
+		}
+		// Remove the worktree even when the run was interrupted
+		defer git.RemoveWorktree(context.WithoutCancel(ctx), dir, path)
+
+		refOpts := opts
+		refOpts.RepoPath = path
+		refOpts.Leaderboards = compareLeaderboards
+		refOpts.State = nil
+		refOpts.IncludeUntracked = false
+
+		report, err := Run(ctx, refOpts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to analyze %s: %w", ref, err)
+		}
+		if i == 0 {
+			comparison.A = report
+		} else {
+			comparison.B = report
+		}
+	}
+
+	comparison.Deltas = CompareReports(comparison.A, comparison.B)
+	comparison.ToolChanges = toolversion.Diff(comparison.A.ToolVersions, comparison.B.ToolVersions)
+	return comparison, nil
+}
+
+// CompareReports returns the change of each compared metric from report a
+// to report b, always in the same order: coverage, issues, debt and loc.
+func CompareReports(a, b *Report) []MetricDelta {
+	metrics := []struct {
+		name           string
+		higherIsBetter bool
+		value          func(r *Report) (float64, bool)
+	}{
+		{"coverage", true, func(r *Report) (float64, bool) {
+			return r.OverallCoverage, r.Errors[LeaderboardCoverage] == nil && len(r.Coverage) > 0
+		}},
+		{"issues", false, func(r *Report) (float64, bool) {
+			if r.Summary == nil {
+				return 0, false
+			}
+			return float64(r.Summary.TotalIssues), true
+		}},
+		{"debt", false, func(r *Report) (float64, bool) {
+			total := 0
+			for _, entry := range r.TechnicalDebt {
+				total += entry.TotalDebt
+			}
+			return float64(total), r.Errors[LeaderboardDebt] == nil
+		}},
+		{"loc", false, func(r *Report) (float64, bool) {
+			total := 0
+			for _, entry := range r.LinesOfCode {
+				total += entry.Lines
+			}
+			return float64(total), r.Errors[LeaderboardLinesOfCode] == nil
+		}},
+	}
+
+	deltas := make([]MetricDelta, 0, len(metrics))
+	for _, metric := range metrics {
+		valueA, okA := metric.value(a)
+		valueB, okB := metric.value(b)
+		delta := MetricDelta{Metric: metric.name, A: valueA, B: valueB, HigherIsBetter: metric.higherIsBetter, Missing: !okA || !okB}
+		if !delta.Missing {
+			delta.Delta = valueB - valueA
+		}
+		deltas = append(deltas, delta)
+	}
+	return deltas
+}
@@ -0,0 +1,1788 @@
+// Package compass runs CodeCompass analyses against a git repository and
+// returns the results as typed data. It never prints or exits, so it can be
+// embedded in other tools; the codecompass CLI is a thin layer over Run.
+package compass
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xeon-zolt/codecompass/internal/analyzer"
+	"github.com/xeon-zolt/codecompass/internal/cerrors"
+	"github.com/xeon-zolt/codecompass/internal/checkstyle"
+	"github.com/xeon-zolt/codecompass/internal/codeowners"
+	"github.com/xeon-zolt/codecompass/internal/config"
+	"github.com/xeon-zolt/codecompass/internal/coverage"
+	"github.com/xeon-zolt/codecompass/internal/customrules"
+	"github.com/xeon-zolt/codecompass/internal/deps"
+	"github.com/xeon-zolt/codecompass/internal/detect"
+	"github.com/xeon-zolt/codecompass/internal/eslint"
+	"github.com/xeon-zolt/codecompass/internal/fingerprint"
+	"github.com/xeon-zolt/codecompass/internal/forge"
+	"github.com/xeon-zolt/codecompass/internal/git"
+	"github.com/xeon-zolt/codecompass/internal/history"
+	"github.com/xeon-zolt/codecompass/internal/lang"
+	"github.com/xeon-zolt/codecompass/internal/leaderboard"
+	"github.com/xeon-zolt/codecompass/internal/lint"
+	"github.com/xeon-zolt/codecompass/internal/logging"
+	reporting "github.com/xeon-zolt/codecompass/internal/report"
+	"github.com/xeon-zolt/codecompass/internal/score"
+	"github.com/xeon-zolt/codecompass/internal/suppression"
+	"github.com/xeon-zolt/codecompass/internal/toolversion"
+	"github.com/xeon-zolt/codecompass/internal/types"
+	"github.com/xeon-zolt/codecompass/internal/utils"
+	"github.com/xeon-zolt/codecompass/internal/workspace"
+)
+
+// ErrNotGitRepository is returned by Run when the repository path is not
+// inside a git work tree.
+var ErrNotGitRepository = cerrors.ErrNotARepo
+
+// ErrNoCommits is recorded in Report.Errors for the leaderboards that need
+// git history when the repository has no commits yet.
+var ErrNoCommits = cerrors.ErrNoCommits
+
+// ErrNoTags is returned by LatestTag when no tag is reachable from HEAD.
+var ErrNoTags = cerrors.ErrNoTags
+
+// Typed errors returned by Run, or recorded in a Report, that callers can
+// match with errors.As.
+type (
+	ErrToolNotFound    = cerrors.ErrToolNotFound
+	ErrCoverageFormat  = cerrors.ErrCoverageFormat
+	ErrCoverageMissing = cerrors.ErrCoverageMissing
+	ErrConfigInvalid   = cerrors.ErrConfigInvalid
+	ErrMissingToken    = cerrors.ErrMissingToken
+	ErrRateLimited     = cerrors.ErrRateLimited
+	ErrDirtyWorkTree   = cerrors.ErrDirtyWorkTree
+)
+
+// Config is the resolved CodeCompass configuration.
+type Config = config.Config
+
+// RuleSeverityOverride, RuleGroup and CustomRule are the
+// rule-severity-overrides, rule-group.NAME and custom-rule settings of a
+// Config.
+type (
+	RuleSeverityOverride = config.RuleSeverityOverride
+	RuleGroup            = config.RuleGroup
+	CustomRule           = config.CustomRule
+)
+
+// NewConfig returns a configuration with the default settings.
+func NewConfig() *Config {
+	return config.NewConfig()
+}
+
+// LoadConfigFile reads a .codecompass.rc style configuration file.
+func LoadConfigFile(filename string) (*Config, error) {
+	return config.LoadConfigFromFile(filename)
+}
+
+// LatestTag returns the most recent tag reachable from HEAD in the
+// repository at repoPath, for Options.ChangedSince. Empty means the current
+// working directory.
+func LatestTag(ctx context.Context, repoPath string) (string, error) {
+	dir, err := resolveRepoPath(repoPath)
+	if err != nil {
+		return "", err
+	}
+	if err := git.ValidateRepository(ctx, dir); err != nil {
+		return "", err
+	}
+	return git.GetLatestTag(ctx, dir)
+}
+
+// LintSource is a linter Run can collect issues from. Issues from sources
+// other than Ruff feed the author, file and rule leaderboards.
+type LintSource = lint.Source
+
+// BuiltinSources returns the ESLint and Ruff sources Run uses by default.
+func BuiltinSources() []LintSource {
+	return lint.BuiltinRegistry().Sources()
+}
+
+// DiscoverPlugins returns the codecompass-lint-* executables on PATH as lint
+// sources. Run never executes them unless they are listed in
+// Options.Sources.
+func DiscoverPlugins() []LintSource {
+	var sources []LintSource
+	for _, plugin := range lint.DiscoverPlugins(os.Getenv("PATH")) {
+		sources = append(sources, plugin)
+	}
+	return sources
+}
+
+// CheckstyleSource returns a lint source reading the issues of the
+// checkstyle XML report at path, as written by Checkstyle, PMD and many
+// other linters.
+func CheckstyleSource(path string) LintSource {
+	return checkstyle.Source{Path: path}
+}
+
+// Forge is a code hosting service pull request statistics are fetched from.
+type Forge = forge.Forge
+
+// RunState persists the leaderboards a run has finished, so an interrupted
+// run can be resumed.
+type RunState = reporting.State
+
+// LoadRunState reads the run state file at path, or returns an empty state
+// that will be saved there when the file does not exist.
+func LoadRunState(path string) (*RunState, error) {
+	return reporting.LoadState(path)
+}
+
+// Baseline is a set of issue fingerprints new issues are told apart from.
+type Baseline struct {
+	// Reference names where the fingerprints came from, such as the file
+	// they were read from.
+	Reference    string
+	Fingerprints []string
+}
+
+// SuppressionBaseline is the suppression baseline of a repository, the
+// findings accepted as legacy debt.
+type SuppressionBaseline = suppression.Baseline
+
+// ReadSuppressionBaseline reads the suppression baseline of the repository
+// in dir: path when it is set, otherwise .codecompass-baseline.json at its
+// root. It returns nil when there is none.
+func ReadSuppressionBaseline(dir, path string) (*SuppressionBaseline, error) {
+	if path == "" {
+		path = filepath.Join(dir, suppression.DefaultFile)
+	}
+	baseline, err := suppression.Read(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return baseline, err
+}
+
+// WriteSuppressionBaseline writes the findings of report, a run with
+// Options.RecordFindings, to path as a suppression baseline, and returns
+// how many it wrote. The kinds of findings the run did not look for keep
+// the records of Options.Suppression.
+func WriteSuppressionBaseline(path string, report *Report) (int, error) {
+	if report.findings == nil {
+		return 0, errors.New("the run recorded no findings")
+	}
+	records := report.findings.Records()
+	return len(records), suppression.Write(path, records)
+}
+
+// ReadBaseline reads the fingerprints in the history directory dir: the
+// baseline written by WriteBaseline when there is one, otherwise those of
+// the last run logged with WriteFingerprints. It returns nil when there are
+// neither.
+func ReadBaseline(dir string) (*Baseline, error) {
+	fingerprints, path, err := history.ReadReference(dir)
+	if err != nil || path == "" {
+		return nil, err
+	}
+	return &Baseline{Reference: path, Fingerprints: fingerprints}, nil
+}
+
+// WriteFingerprints records the issue fingerprints of report in the history
+// directory dir as those of the last run. It replaces the previous run's.
+func WriteFingerprints(dir string, report *Report) error {
+	return history.WriteFingerprints(filepath.Join(dir, history.FingerprintsFile), report.Fingerprints)
+}
+
+// WriteBaseline records the issue fingerprints of report in the history
+// directory dir as the accepted baseline. Until it is written again, the
+// issues of later runs are compared with it rather than with the last run.
+func WriteBaseline(dir string, report *Report) error {
+	return history.WriteFingerprints(filepath.Join(dir, history.BaselineFile), report.Fingerprints)
+}
+
+// SaveReport writes report to path as JSON, for LoadReport to read back
+// without analyzing the repository again.
+func SaveReport(path string, report *Report) error {
+	return reporting.SaveReport(path, &report.Report)
+}
+
+// SaveAttentionList writes the files of report needing attention by the
+// thresholds of cfg to path as JSON. The report needs LeaderboardScore.
+func SaveAttentionList(path string, report *Report, cfg *config.Config) error {
+	return reporting.SaveAttentionList(path, leaderboard.GenerateAttentionList(&report.Report, cfg))
+}
+
+// SaveDiagnostics writes the warnings of report to path as JSON records,
+// grouped by type.
+func SaveDiagnostics(path string, report *Report) error {
+	return reporting.SaveDiagnostics(path, leaderboard.GenerateDiagnostics(&report.Report))
+}
+
+// LoadReport reads a report written by SaveReport. The errors of its
+// leaderboards, lint sources and, for a report of RunMulti, repositories are
+// restored from their messages, and no leaderboards are listed as reused.
+func LoadReport(path string) (*Report, error) {
+	saved, err := reporting.LoadReport(path)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{Report: *saved, Errors: make(map[Leaderboard]error)}
+	for lb, msg := range saved.Failures {
+		report.Errors[Leaderboard(lb)] = errors.New(msg)
+	}
+	if saved.RepoFailures != nil {
+		report.RepoErrors = make(map[string]error)
+		for repo, msg := range saved.RepoFailures {
+			report.RepoErrors[repo] = errors.New(msg)
+		}
+	}
+	for _, source := range saved.LintSources {
+		var err error
+		if source.Error != "" {
+			err = errors.New(source.Error)
+		}
+		report.Sources = append(report.Sources, SourceResult{Name: source.Name, Issues: source.Issues, Err: err})
+
+		switch source.Name {
+		case "eslint":
+			report.ESLintIssues, report.ESLintError = source.Issues, err
+		case "ruff":
+			report.RuffIssues, report.RuffError = source.Issues, err
+		}
+	}
+	return report, nil
+}
+
+// FileSort is the column the file leaderboard is sorted by.
+type FileSort = leaderboard.FileSort
+
+const (
+	FileSortIssues   = leaderboard.FileSortIssues
+	FileSortErrors   = leaderboard.FileSortErrors
+	FileSortWarnings = leaderboard.FileSortWarnings
+)
+
+// ChurnSort is the column the churn leaderboard is sorted by.
+type ChurnSort = leaderboard.ChurnSort
+
+const (
+	ChurnSortChanges = leaderboard.ChurnSortChanges
+	ChurnSortRate    = leaderboard.ChurnSortRate
+)
+
+// OwnerWorklist is the files of one owner from the file leaderboard.
+type OwnerWorklist = leaderboard.OwnerWorklist
+
+// GroupFilesByOwner regroups the file leaderboard of a Report under the
+// owners of its files, resolved as the owner-resolution setting says, so
+// each owner gets its own worklist. The unowned files come last.
+func GroupFilesByOwner(entries []FileLeaderboardEntry) []OwnerWorklist {
+	return leaderboard.GroupFilesByOwner(entries)
+}
+
+// ReadTrend returns the change of the totals of report, as the dashboard
+// shows them, since the last run logged in historyDir before it. It returns
+// nil when historyDir has no such run. Totals the earlier run did not
+// measure are Missing, and those report did not measure left out.
+func ReadTrend(report *SerializableReport, historyDir string) (*RunTrend, error) {
+	previous, err := history.ReadRunTotals(historyDir, report.GeneratedAt)
+	if err != nil || previous == nil {
+		return nil, err
+	}
+
+	trend := &RunTrend{Since: previous.Time}
+	for _, total := range leaderboard.DashboardTotals(report) {
+		delta := MetricDelta{Metric: total.Metric, B: total.Value, HigherIsBetter: total.HigherIsBetter}
+		var ok bool
+		if delta.A, ok = previous.Values[total.Metric]; ok {
+			delta.Delta = delta.B - delta.A
+		} else {
+			delta.Missing = true
+		}
+		trend.Deltas = append(trend.Deltas, delta)
+	}
+	return trend, nil
+}
+
+// AuthorPacket is what a Report found about one author.
+type AuthorPacket = leaderboard.AuthorPacket
+
+// AuthorPackets returns the packets of the authors of report with the given
+// emails, or without emails, of the topN authors with the most issues. When
+// historyDir has a run logged before report, the packets are compared with
+// it.
+func AuthorPackets(report *Report, emails []string, topN int, historyDir string) ([]AuthorPacket, error) {
+	packets := leaderboard.BuildAuthorPackets(&report.Report, report.AuthorStats, emails, topN)
+	if historyDir == "" {
+		return packets, nil
+	}
+	previous, err := history.ReadAuthorSnapshot(historyDir, report.GeneratedAt)
+	if err != nil {
+		return packets, err
+	}
+	if previous == nil {
+		return packets, nil
+	}
+	changes := toolversion.Diff(previous.ToolVersions, report.ToolVersions)
+	for i := range packets {
+		packets[i].Previous = previous
+		packets[i].ToolChanges = changes
+	}
+	return packets, nil
+}
+
+// WriteAuthorPackets writes each packet to a Markdown file in dir, named
+// after the author's email, and returns the paths written.
+func WriteAuthorPackets(dir string, report *Report, packets []AuthorPacket, topN int) ([]string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create author report directory: %w", err)
+	}
+
+	var paths []string
+	taken := make(map[string]bool)
+	for _, packet := range packets {
+		name := leaderboard.AuthorPacketFilename(packet.Email)
+		// Emails that only differ in replaced characters get a suffix
+		for n := 2; taken[name]; n++ {
+			name = fmt.Sprintf("%s-%d.md", strings.TrimSuffix(leaderboard.AuthorPacketFilename(packet.Email), ".md"), n)
+		}
+		taken[name] = true
+
+		var markdown strings.Builder
+		if err := leaderboard.WriteAuthorPacketMarkdown(&markdown, packet, report.GeneratedAt, topN); err != nil {
+			return paths, err
+		}
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(markdown.String()), 0644); err != nil {
+			return paths, fmt.Errorf("failed to write author report %s: %w", path, err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// DefaultWindow is how far back the pull request statistics, the lead time,
+// the changelog readiness, the deletions and the new contributors look when
+// Options.Since is not set.
+const DefaultWindow = 90 * 24 * time.Hour
+
+// Leaderboard identifies one of the CodeCompass leaderboards.
+type Leaderboard string
+
+const (
+	LeaderboardAuthors     Leaderboard = "authors"
+	LeaderboardFiles       Leaderboard = "files"
+	LeaderboardRules       Leaderboard = "rules"
+	LeaderboardRulePlugins Leaderboard = "rule-plugins"
+	LeaderboardRuleGroups  Leaderboard = "rule-groups"
+	LeaderboardRuleAuthors Leaderboard = "rule-author-matrix"
+	LeaderboardLinesOfCode Leaderboard = "loc"
+	LeaderboardCommits     Leaderboard = "commits"
+	LeaderboardRecent      Leaderboard = "recent"
+	LeaderboardNewContrib  Leaderboard = "new-contributors"
+	LeaderboardCoverage    Leaderboard = "coverage"
+	LeaderboardChurn       Leaderboard = "churn"
+	LeaderboardHotspots    Leaderboard = "hotspots"
+	LeaderboardBugs        Leaderboard = "bugs"
+	LeaderboardDebt        Leaderboard = "debt"
+	LeaderboardSummary     Leaderboard = "summary"
+	LeaderboardSpellCheck  Leaderboard = "spellcheck"
+	LeaderboardRuff        Leaderboard = "ruff"
+	LeaderboardFormatDrift Leaderboard = "format-drift"
+	LeaderboardEncoding    Leaderboard = "encoding"
+	LeaderboardLongFuncs   Leaderboard = "long-functions"
+	LeaderboardGitHub      Leaderboard = "github-stats"
+	LeaderboardLeadTime    Leaderboard = "lead-time"
+	LeaderboardChangelog   Leaderboard = "changelog-readiness"
+	LeaderboardTimezones   Leaderboard = "timezones"
+	LeaderboardDeletions   Leaderboard = "deletions"
+	LeaderboardWorkspaces  Leaderboard = "by-workspace"
+	LeaderboardVulns       Leaderboard = "vulns"
+	LeaderboardDeps        Leaderboard = "deps"
+	LeaderboardLFS         Leaderboard = "lfs"
+	LeaderboardScore       Leaderboard = "score"
+	LeaderboardReportCard  Leaderboard = "report-card"
+)
+
+// AllLeaderboards returns every leaderboard in display order.
+func AllLeaderboards() []Leaderboard {
+	return []Leaderboard{
+		LeaderboardWorkspaces, LeaderboardAuthors, LeaderboardFiles, LeaderboardRules, LeaderboardRulePlugins, LeaderboardRuleGroups, LeaderboardRuleAuthors,
+		LeaderboardLinesOfCode, LeaderboardCommits, LeaderboardRecent, LeaderboardNewContrib, LeaderboardCoverage, LeaderboardChurn, LeaderboardHotspots, LeaderboardDeletions,
+		LeaderboardBugs, LeaderboardDebt, LeaderboardSummary, LeaderboardSpellCheck, LeaderboardRuff, LeaderboardFormatDrift, LeaderboardEncoding,
+		LeaderboardLongFuncs, LeaderboardLFS, LeaderboardGitHub, LeaderboardLeadTime, LeaderboardChangelog, LeaderboardTimezones, LeaderboardVulns, LeaderboardDeps, LeaderboardScore, LeaderboardReportCard,
+	}
+}
+
+// DashboardLeaderboards returns the leaderboards the dashboard summarizes.
+func DashboardLeaderboards() []Leaderboard {
+	return []Leaderboard{LeaderboardAuthors, LeaderboardFiles, LeaderboardRules, LeaderboardCoverage, LeaderboardDebt, LeaderboardSummary}
+}
+
+// ProgressFunc receives progress updates for a phase of the run. total is
+// zero for phases that are not counted, such as running a linter, and the
+// number of issues or files for the ones that are.
+type ProgressFunc func(phase string, done, total int)
+
+// Options controls a single Run.
+type Options struct {
+	// RepoPath is the git repository to analyze. Empty means the current
+	// working directory.
+	RepoPath string
+
+	// Leaderboards lists the leaderboards to compute.
+	Leaderboards []Leaderboard
+
+	// Config is the resolved configuration. Nil means the defaults.
+	Config *Config
+
+	// IgnoredRules are linter rules to drop in addition to Config.IgnoredRules.
+	IgnoredRules []string
+
+	// IgnoredRulePrefixes drop the linter rules starting with one of them,
+	// in addition to Config.IgnoredRulePrefixes.
+	IgnoredRulePrefixes []string
+
+	// CoverageFile is the coverage report to read, relative to RepoPath.
+	// Empty means auto-detect.
+	CoverageFile string
+
+	// FileSort is the column the file leaderboard is sorted by. Empty means
+	// FileSortIssues.
+	FileSort FileSort
+
+	// ChurnSort is the column the churn leaderboard is sorted by. Empty
+	// means ChurnSortChanges.
+	ChurnSort ChurnSort
+
+	// Weighted ranks authors and files by their issues weighted by the
+	// IssueWeights of the Config, rather than by their number, and scores
+	// issues by their weight.
+	Weighted bool
+
+	// Sources are the linters to run. Nil means BuiltinSources; plugins
+	// only run when they are listed, such as those returned by
+	// DiscoverPlugins. The custom rules of the Config run in any case.
+	Sources []LintSource
+
+	// Since is the start of the window for the pull request statistics,
+	// the lead time, the changelog readiness, the deletions and the new
+	// contributors; the other leaderboards ignore it. Zero means
+	// DefaultWindow before now.
+	Since time.Time
+
+	// Forge is where pull request statistics are fetched from. Nil means
+	// the forge hosting the origin remote, authenticated with GITHUB_TOKEN
+	// or GITLAB_TOKEN; Config.GitLabBaseURL locates self-hosted GitLab.
+	// Nothing is fetched unless LeaderboardGitHub is requested.
+	Forge Forge
+
+	// DisableESLint and DisableRuff skip the linters even when a requested
+	// leaderboard needs them.
+	DisableESLint bool
+	DisableRuff   bool
+
+	// IncludeUntracked adds the untracked files that are not ignored to the
+	// file-based leaderboards: lines of code, debt and spell check. They
+	// are marked Untracked there, and left out of everything built from
+	// linters or git history.
+	IncludeUntracked bool
+
+	// IncludeVendored keeps vendored, third-party and generated files, as
+	// classified by the detect package, in the leaderboards that read file
+	// contents. They are left out by default and counted in RepoInfo.
+	IncludeVendored bool
+
+	// Languages, when set, restricts the linters and the file-based
+	// leaderboards to the files of these languages, named as lang.Parse
+	// returns them. RepoInfo.Languages still counts every language.
+	Languages []string
+
+	// OnlyExtensions, when set, restricts the linters and the file-based
+	// leaderboards to the files with one of these extensions, and
+	// ExcludeExtensions leaves those with one of its own out, winning over
+	// OnlyExtensions. Extensions are as utils.ParseExtensions returns them,
+	// such as .ts. RepoInfo.Languages still counts every file.
+	OnlyExtensions    []string
+	ExcludeExtensions []string
+
+	// ChangedSince, when set, is a revision such as the latest release tag,
+	// found with LatestTag. The linters and the file-based leaderboards are
+	// restricted to the files changed in the work tree since it, untracked
+	// files included, the commit leaderboard counts the commits since it,
+	// and Since defaults to its commit date.
+	ChangedSince string
+
+	// OutputDirs are the directories CodeCompass writes to, such as the
+	// history log directory. Their files are left out of the analysis, so a
+	// run does not measure what earlier runs wrote. Relative paths are
+	// relative to the current working directory; directories outside
+	// RepoPath are ignored.
+	OutputDirs []string
+
+	// RequireClean makes Run return ErrDirtyWorkTree, before analyzing
+	// anything, when the work tree has uncommitted changes or untracked
+	// files. Otherwise issues on the changed lines of tracked files are
+	// blamed on an "uncommitted" author, and RepoInfo counts the files.
+	RequireClean bool
+
+	// RequireCoverage runs the coverage leaderboard and fails it with
+	// ErrCoverageMissing when there is no coverage file, rather than leave
+	// it without entries.
+	RequireCoverage bool
+
+	// TrackIssues sets Report.Fingerprints. Every lint source that applies
+	// runs, whichever leaderboards are requested, so the fingerprints of
+	// two runs can be compared.
+	TrackIssues bool
+
+	// Baseline, when set, is compared with the fingerprints of the issues
+	// found, counting the new and pre-existing ones in
+	// Report.IssueBaseline. It implies TrackIssues.
+	Baseline *Baseline
+
+	// Suppression, when set, is the suppression baseline the lint issues,
+	// debt markers and misspellings found are matched with. Those it has
+	// are left out of the leaderboards and counted in Report.Suppression,
+	// unless IncludeBaselined is set.
+	Suppression      *SuppressionBaseline
+	IncludeBaselined bool
+
+	// RecordFindings fingerprints the lint issues and debt markers found,
+	// and the misspellings when the spell check runs, for
+	// WriteSuppressionBaseline. Every lint source that applies runs, as
+	// does the debt scan.
+	RecordFindings bool
+
+	// State, when set, is saved as each leaderboard that does not depend on
+	// the linters finishes, and leaderboards it holds are reused instead of
+	// computed again while the commit, uncommitted changes, config and
+	// options they were computed from are unchanged.
+	State *RunState
+
+	// Progress, when set, is called as each phase starts, while issues are
+	// attributed to authors and while the lines of code, technical debt and
+	// spell check leaderboards scan files.
+	Progress ProgressFunc
+
+	// Logger receives debug and warning records as the run progresses. Nil
+	// discards them; warnings are collected into Report.Warnings either way.
+	Logger *slog.Logger
+}
+
+// Report holds the results of a Run. The embedded types.Report is the
+// serializable part: its leaderboard slices are sorted and complete, and only
+// leaderboards that were requested, or that the report card is graded or the
+// workspace roll-up is built from, are populated.
+type Report struct {
+	types.Report
+
+	// ESLintIssues and RuffIssues count the issues each linter reported.
+	// ESLintError and RuffError are set when a linter could not run, in
+	// which case the leaderboards depending on it are left empty.
+	ESLintIssues int
+	RuffIssues   int
+	ESLintError  error
+	RuffError    error
+
+	// Sources has one result per lint source that ran, in run order.
+	Sources []SourceResult
+
+	// Errors records leaderboards that failed to generate. Their messages
+	// are also kept in Report.Failures.
+	Errors map[Leaderboard]error
+
+	// Reused lists the leaderboards taken from Options.State rather than
+	// computed, in run order.
+	Reused []Leaderboard
+
+	// RepoErrors records the repositories RunMulti could not analyze. Their
+	// messages are also kept in Report.RepoFailures.
+	RepoErrors map[string]error
+
+	// Fingerprints identify the lint issues found, sorted, when
+	// Options.TrackIssues or Options.Baseline is set and a lint source ran.
+	// They are not saved with the report.
+	Fingerprints []string
+
+	// AuthorStats has the issue counts of each author by rule and file, by
+	// email, when the author leaderboard was generated. They are not saved
+	// with the report.
+	AuthorStats map[string]*types.AuthorStats
+
+	// findings matched the findings of the run with Options.Suppression,
+	// when set or Options.RecordFindings is
+	findings *suppression.Filter
+}
+
+// SourceResult is the outcome of running one lint source.
+type SourceResult struct {
+	Name   string
+	Issues int
+	Err    error
+}
+
+// IssueCount returns the total number of linter issues found.
+func (r *Report) IssueCount() int {
+	count := 0
+	for _, source := range r.Sources {
+		count += source.Issues
+	}
+	return count
+}
+
+// excludeForLeaderboard returns files without those matching the ignore
+// paths of lb, counting them in the report when lb was requested.
+func excludeForLeaderboard(cfg *config.Config, report *Report, enabled map[Leaderboard]bool, lb Leaderboard, files map[string]bool) map[string]bool {
+	if len(cfg.LeaderboardIgnorePaths(string(lb))) == 0 {
+		return files
+	}
+	kept := make(map[string]bool, len(files))
+	excluded := 0
+	for file := range files {
+		if cfg.ShouldIgnoreForLeaderboard(string(lb), file) {
+			excluded++
+		} else {
+			kept[file] = true
+		}
+	}
+	if enabled[lb] && excluded > 0 {
+		if report.Repo.ExcludedFiles == nil {
+			report.Repo.ExcludedFiles = make(map[string]int)
+		}
+		report.Repo.ExcludedFiles[string(lb)] = excluded
+	}
+	return kept
+}
+
+func (r *Report) track(logger *slog.Logger, phase string, start time.Time) {
+	r.Timings[phase] = time.Since(start)
+	logger.Debug("Phase finished", "phase", phase, "duration", r.Timings[phase])
+}
+
+// fail records that lb could not be generated.
+func (r *Report) fail(lb Leaderboard, err error) {
+	r.Errors[lb] = err
+	if r.Failures == nil {
+		r.Failures = make(map[string]string)
+	}
+	r.Failures[string(lb)] = err.Error()
+}
+
+func (o Options) progress(phase string, done, total int) {
+	if o.Progress != nil {
+		o.Progress(phase, done, total)
+	}
+}
+
+// scanProgress reports the files a leaderboard has scanned as the progress
+// of its phase.
+func (o Options) scanProgress(lb Leaderboard) utils.ProgressFunc {
+	if o.Progress == nil {
+		return nil
+	}
+	return func(done, total int) {
+		o.Progress(string(lb), done, total)
+	}
+}
+
+// Run analyzes the repository described by opts.
+func Run(ctx context.Context, opts Options) (*Report, error) {
+	runStart := time.Now()
+
+	cfg := opts.Config
+	if cfg == nil {
+		cfg = config.NewConfig()
+	}
+
+	baseLogger := opts.Logger
+	if baseLogger == nil {
+		baseLogger = logging.Discard()
+	}
+	// Components that add to warnings themselves log on baseLogger so their
+	// warnings are not collected twice
+	warnings := utils.NewWarningCollector()
+	logger := slog.New(logging.NewCollector(baseLogger.Handler(), warnings))
+
+	since := opts.Since
+	if since.IsZero() {
+		since = runStart.Add(-DefaultWindow)
+	}
+
+	enabled := make(map[Leaderboard]bool)
+	for _, lb := range opts.Leaderboards {
+		enabled[lb] = true
+	}
+
+	dir, err := resolveRepoPath(opts.RepoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := git.ValidateRepository(ctx, dir); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+
+	hasCommits, err := git.HasCommits(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	workTree, err := git.GetWorkTreeStatus(ctx, dir)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+	if opts.RequireClean && !workTree.Clean() {
+		var changed []string
+		for _, files := range []map[string]bool{workTree.Modified, workTree.Untracked} {
+			for file := range files {
+				changed = append(changed, file)
+			}
+		}
+		sort.Strings(changed)
+		return nil, &cerrors.ErrDirtyWorkTree{Files: changed}
+	}
+
+	report := &Report{
+		Report: types.NewReport(dir),
+		Errors: make(map[Leaderboard]error),
+	}
+	for _, lb := range opts.Leaderboards {
+		report.Leaderboards = append(report.Leaderboards, string(lb))
+	}
+	// Lint sources add the versions of their tools once they apply
+	report.ToolVersions = map[string]string{"git": toolversion.Command(ctx, dir, "git", "--version")}
+
+	// Before the first commit there is nothing to blame or walk, so only
+	// the files in the work tree are analyzed
+	listFiles := git.GetTrackedFiles
+	if hasCommits {
+		if report.Repo.Head, err = git.GetHead(ctx, dir); err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, err
+		}
+	} else {
+		report.Repo.NoCommits = true
+		listFiles = git.GetWorkTreeFiles
+		logger.Debug("Repository has no commits yet", "phase", "files")
+	}
+
+	phaseStart := time.Now()
+	opts.progress("files", 0, 0)
+	trackedFiles, err := listFiles(ctx, dir)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("failed to get tracked files: %w", err)
+	}
+
+	// Only the files changed since opts.ChangedSince are analyzed; nil
+	// means every file
+	var changedFiles map[string]bool
+	if opts.ChangedSince != "" {
+		if changedFiles, err = git.GetChangedFiles(ctx, dir, opts.ChangedSince); err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, fmt.Errorf("failed to get the files changed since %s: %w", opts.ChangedSince, err)
+		}
+		if opts.Since.IsZero() {
+			if since, err = git.GetCommitTime(ctx, dir, opts.ChangedSince); err != nil {
+				return nil, fmt.Errorf("failed to get the date of %s: %w", opts.ChangedSince, err)
+			}
+		}
+		report.Repo.ChangedSince = opts.ChangedSince
+		for file := range changedFiles {
+			if trackedFiles[file] {
+				report.Repo.ChangedFiles++
+			}
+		}
+	}
+
+	// Filter tracked files based on config
+	filteredFiles := make(map[string]bool)
+	// Walk files in order so warnings are logged deterministically
+	trackedPaths := make([]string, 0, len(trackedFiles))
+	for file := range trackedFiles {
+		trackedPaths = append(trackedPaths, file)
+	}
+	sort.Strings(trackedPaths)
+
+	outputDirs := outputDirPrefixes(dir, opts.OutputDirs)
+	// A suppression baseline read from elsewhere in the repository is left
+	// out like the default one
+	var baselineFile string
+	if opts.Suppression != nil {
+		if path, err := filepath.Abs(opts.Suppression.Path); err == nil {
+			if rel, err := filepath.Rel(dir, path); err == nil && filepath.IsLocal(rel) {
+				baselineFile = filepath.ToSlash(rel)
+			}
+		}
+	}
+
+	// optedOut are the files excluded by their own ignore-file directive,
+	// whose issues are dropped whatever the linters were asked to lint
+	optedOut := make(map[string]bool)
+	// Sizes are checked per leaderboard below, since the spell check has a
+	// limit of its own
+	analyzable := func(file string) bool {
+		if cfg.ShouldIgnorePath(file) {
+			return false
+		}
+		// The suppression baseline names the rules and markers it accepts
+		if inOutputDir(file, outputDirs) || file == suppression.DefaultFile || file == baselineFile {
+			logger.Debug("Skipped file written by CodeCompass", "phase", "files", "file", file)
+			return false
+		}
+		// Git can track symlinks, which may point outside the repository
+		if utils.IsSymlink(filepath.Join(dir, file)) {
+			logger.Warn("Skipped symlinked file", "phase", "files", "file", file)
+			return false
+		}
+		if utils.HasIgnoreFileDirective(filepath.Join(dir, file)) {
+			logger.Debug("Skipped file with an ignore-file directive", "phase", "files", "file", file)
+			optedOut[file] = true
+			return false
+		}
+		return true
+	}
+	extensions := utils.ExtensionFilter{Only: opts.OnlyExtensions, Exclude: opts.ExcludeExtensions}
+	inScope := func(file string) bool {
+		return extensions.Match(file) && (len(opts.Languages) == 0 || slices.Contains(opts.Languages, lang.Detect(dir, file)))
+	}
+	// Files of every language make up the composition, even those left
+	// out by opts.Languages or their extension
+	composedFiles := make(map[string]bool)
+	// Lockfiles and requirements files, whatever their language or size,
+	// for the dependency leaderboard
+	var manifestFiles []string
+	for _, file := range trackedPaths {
+		if analyzable(file) {
+			composedFiles[file] = true
+			if deps.IsManifest(file) {
+				manifestFiles = append(manifestFiles, file)
+			}
+			if inScope(file) && (changedFiles == nil || changedFiles[file]) {
+				filteredFiles[file] = true
+			}
+		}
+	}
+	sizeGuard := utils.NewSizeGuard(cfg.MaxFileSize)
+	analyzedFiles := filteredFiles
+	filteredFiles, oversized := sizeGuard.Filter(dir, analyzedFiles)
+	report.Repo.TrackedFiles = len(trackedFiles)
+	report.Repo.AnalyzedFiles = len(filteredFiles)
+
+	composedFiles, _ = sizeGuard.Filter(dir, composedFiles)
+	report.Repo.Languages, err = leaderboard.GenerateLanguageComposition(ctx, dir, composedFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	// Untracked files only join the leaderboards that read files as they
+	// are. Before the first commit they are already among trackedFiles.
+	fileBasedFiles := analyzedFiles
+	var untrackedPaths []string
+	if opts.IncludeUntracked && hasCommits {
+		untracked, err := git.GetUntrackedFiles(ctx, dir)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, fmt.Errorf("failed to get untracked files: %w", err)
+		}
+		for file := range untracked {
+			untrackedPaths = append(untrackedPaths, file)
+		}
+		sort.Strings(untrackedPaths)
+		untrackedPaths = slices.DeleteFunc(untrackedPaths, func(file string) bool { return !analyzable(file) || !inScope(file) })
+
+		fileBasedFiles = maps.Clone(analyzedFiles)
+		for _, file := range untrackedPaths {
+			fileBasedFiles[file] = true
+		}
+		report.Repo.UntrackedFiles = len(untrackedPaths)
+	}
+	isUntracked := func(file string) bool {
+		_, found := slices.BinarySearch(untrackedPaths, file)
+		return found
+	}
+
+	// Vendored and generated files would swamp the leaderboards that read
+	// file contents, so those only see first-party source
+	contentFiles, encodingFiles := fileBasedFiles, analyzedFiles
+	readsContents := enabled[LeaderboardLinesOfCode] || enabled[LeaderboardDebt] || enabled[LeaderboardSpellCheck] ||
+		enabled[LeaderboardEncoding] || enabled[LeaderboardLongFuncs] || enabled[LeaderboardWorkspaces] || enabled[LeaderboardScore] || enabled[LeaderboardReportCard]
+	if readsContents && !opts.IncludeVendored {
+		paths := make([]string, 0, len(fileBasedFiles))
+		for file := range fileBasedFiles {
+			paths = append(paths, file)
+		}
+		sort.Strings(paths)
+
+		attributes, err := git.GetAttributes(ctx, dir, paths, detect.Attributes...)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			logger.Warn("Failed to read linguist attributes", "phase", "files", "error", err)
+		}
+		detector := detect.New(dir, attributes)
+
+		contentFiles, encodingFiles = make(map[string]bool), make(map[string]bool)
+		for _, file := range paths {
+			switch detector.Classify(file) {
+			case detect.Vendored:
+				report.Repo.VendoredFiles++
+				continue
+			case detect.Generated:
+				report.Repo.GeneratedFiles++
+				continue
+			}
+			contentFiles[file] = true
+			if analyzedFiles[file] {
+				encodingFiles[file] = true
+			}
+		}
+	}
+
+	// Files over max-file-size are left out of every leaderboard, except
+	// that the spell check has its own limit
+	spellCheckFiles, spellCheckOversized := cfg.SpellCheckSizeGuard().Filter(dir, contentFiles)
+	contentFiles, contentOversized := sizeGuard.Filter(dir, contentFiles)
+	encodingFiles, encodingOversized := sizeGuard.Filter(dir, encodingFiles)
+	for lb, skipped := range map[Leaderboard]int{
+		LeaderboardLinesOfCode: contentOversized,
+		LeaderboardDebt:        contentOversized,
+		LeaderboardLongFuncs:   contentOversized,
+		LeaderboardSpellCheck:  spellCheckOversized,
+		LeaderboardEncoding:    encodingOversized,
+		LeaderboardCoverage:    oversized,
+		LeaderboardChurn:       oversized,
+		LeaderboardHotspots:    oversized,
+		LeaderboardBugs:        oversized,
+		LeaderboardAuthors:     oversized,
+		LeaderboardFiles:       oversized,
+		LeaderboardRules:       oversized,
+	} {
+		if enabled[lb] && skipped > 0 {
+			if report.Repo.OversizedFiles == nil {
+				report.Repo.OversizedFiles = make(map[string]int)
+			}
+			report.Repo.OversizedFiles[string(lb)] = skipped
+		}
+	}
+
+	// Some leaderboards leave out more files of their own, such as the
+	// tests out of the debt while they still count in the lines of code
+	locFiles := excludeForLeaderboard(cfg, report, enabled, LeaderboardLinesOfCode, contentFiles)
+	debtFiles := excludeForLeaderboard(cfg, report, enabled, LeaderboardDebt, contentFiles)
+	spellCheckFiles = excludeForLeaderboard(cfg, report, enabled, LeaderboardSpellCheck, spellCheckFiles)
+	churnFiles := excludeForLeaderboard(cfg, report, enabled, LeaderboardChurn, filteredFiles)
+	report.track(logger, "files", phaseStart)
+
+	// Sources see the command-line ignored rules as part of the config
+	lintCfg := *cfg
+	lintCfg.IgnoredRules = append(append([]string{}, cfg.IgnoredRules...), opts.IgnoredRules...)
+	lintCfg.IgnoredRulePrefixes = append(append([]string{}, cfg.IgnoredRulePrefixes...), opts.IgnoredRulePrefixes...)
+
+	sources := opts.Sources
+	if sources == nil {
+		sources = lint.BuiltinRegistry().Sources()
+	}
+	// The custom rules run whatever the sources, once there are any
+	if len(cfg.CustomRules) > 0 && !slices.ContainsFunc(sources, func(source LintSource) bool { return source.Name() == customrules.SourceName }) {
+		sources = append(slices.Clip(sources), customrules.Source{})
+	}
+
+	// The workspace roll-up is built from the file-based leaderboards
+	byWorkspace := enabled[LeaderboardWorkspaces]
+	rolledUp := map[Leaderboard]bool{LeaderboardLinesOfCode: true, LeaderboardDebt: true, LeaderboardCoverage: true}
+
+	// The quality score is combined from the per-file measurements
+	scored := enabled[LeaderboardScore]
+	scoredFrom := map[Leaderboard]bool{
+		LeaderboardLinesOfCode: true, LeaderboardCoverage: true, LeaderboardDebt: true, LeaderboardChurn: true, LeaderboardLongFuncs: true,
+	}
+
+	// Hotspots join the churn leaderboard with the issues of each file
+	hotspots := enabled[LeaderboardHotspots]
+
+	needsIssues := enabled[LeaderboardAuthors] || enabled[LeaderboardFiles] || enabled[LeaderboardRules] || enabled[LeaderboardRulePlugins] || enabled[LeaderboardRuleGroups] || enabled[LeaderboardRuleAuthors] || byWorkspace || hotspots
+	gradeCard := enabled[LeaderboardReportCard]
+	trackIssues := opts.TrackIssues || opts.Baseline != nil
+	needsRuff := !opts.DisableRuff && (enabled[LeaderboardRuff] || gradeCard || scored || trackIssues || opts.RecordFindings)
+	countIssues := needsIssues || gradeCard || scored || trackIssues || opts.RecordFindings
+	issueSourceRan := false
+	lintRan := false
+	// The baseline's issues are only stale when every lint source ran
+	lintFailed := false
+
+	// Findings the suppression baseline has are left out from here on
+	var findings *suppression.Filter
+	if opts.Suppression != nil || opts.RecordFindings {
+		findings = suppression.NewFilter(opts.Suppression, !opts.IncludeBaselined)
+		report.findings = findings
+	}
+
+	var issues []types.Issue
+
+	for _, source := range sources {
+		name := source.Name()
+
+		var wanted bool
+		switch name {
+		case "eslint":
+			wanted = countIssues && !opts.DisableESLint
+		case "ruff":
+			wanted = needsRuff
+		default:
+			wanted = countIssues
+		}
+		if !wanted {
+			continue
+		}
+		if !source.Detect(ctx, dir) {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			// ESLint once ran on every repository, so say why it did not
+			if name == "eslint" {
+				logger.Warn("Skipped ESLint: no package.json or ESLint config in the repository root", "phase", name)
+			} else {
+				logger.Debug("Skipped lint source that does not apply", "phase", name)
+			}
+			continue
+		}
+
+		if versioned, ok := source.(lint.Versioned); ok {
+			for tool, version := range versioned.Versions(ctx, dir) {
+				report.ToolVersions[tool] = version
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		phaseStart = time.Now()
+		opts.progress(name, 0, 0)
+		sourceIssues, err := source.Run(ctx, dir, filteredFiles, &lintCfg)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		// A source with nothing to lint did not run, rather than run clean
+		if errors.Is(err, cerrors.ErrNotApplicable) {
+			logger.Debug("Skipped lint source that does not apply", "phase", name, "reason", err)
+			continue
+		}
+		report.Sources = append(report.Sources, SourceResult{Name: name, Issues: len(sourceIssues), Err: err})
+		result := types.LintSourceResult{Name: name, Issues: len(sourceIssues)}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		report.LintSources = append(report.LintSources, result)
+		report.track(logger, name, phaseStart)
+
+		switch name {
+		case "eslint":
+			report.ESLintError = err
+			report.ESLintIssues = len(sourceIssues)
+			unparseable := eslint.UnparseableFiles(sourceIssues)
+			unparseablePaths := make([]string, 0, len(unparseable))
+			for file := range unparseable {
+				unparseablePaths = append(unparseablePaths, file)
+			}
+			sort.Strings(unparseablePaths)
+			for _, file := range unparseablePaths {
+				logger.Warn("ESLint could not parse file", "phase", "eslint", "file", file, "error", unparseable[file])
+			}
+		case "ruff":
+			report.RuffError = err
+			report.RuffIssues = len(sourceIssues)
+			if err != nil {
+				needsRuff = false
+			}
+		}
+
+		if err != nil {
+			logger.Debug("Lint source failed", "phase", name, "error", err)
+			lintFailed = true
+			continue
+		}
+		lintRan = true
+		if name != "ruff" {
+			issueSourceRan = true
+		}
+		issues = append(issues, sourceIssues...)
+	}
+
+	// Overrides apply to the issues of every source, before anything counts
+	// them
+	issues = applyRuleSeverities(issues, &lintCfg)
+	issues = slices.DeleteFunc(issues, func(issue types.Issue) bool { return optedOut[filepath.ToSlash(issue.FilePath)] })
+
+	if (trackIssues || findings != nil) && lintRan {
+		// The issues the leaderboards count, as the analyzer filters them
+		tracked := slices.DeleteFunc(slices.Clone(issues), func(issue types.Issue) bool {
+			return issue.Severity == types.SeverityOff || cfg.ShouldIgnoreRepoFile(dir, issue.FilePath)
+		})
+		fingerprints := fingerprint.Compute(dir, tracked, cfg.MaxLineSize)
+		if trackIssues {
+			report.Fingerprints = slices.Clone(fingerprints)
+			sort.Strings(report.Fingerprints)
+			if opts.Baseline != nil {
+				added, existing, fixed := fingerprint.Classify(report.Fingerprints, opts.Baseline.Fingerprints)
+				report.IssueBaseline = &types.IssueBaseline{Reference: opts.Baseline.Reference, New: added, Existing: existing, Fixed: fixed}
+			}
+		}
+		if findings != nil {
+			kept := tracked[:0]
+			for i, issue := range tracked {
+				record := suppression.Record{Kind: suppression.KindIssue, Path: filepath.ToSlash(issue.FilePath), Rule: issue.RuleID, Fingerprint: fingerprints[i]}
+				if !findings.Suppress(record) {
+					kept = append(kept, issue)
+				}
+			}
+			issues = kept
+			if !lintFailed {
+				findings.Check(suppression.KindIssue, filteredFiles)
+			}
+		}
+	}
+
+	semaphore := utils.NewSemaphore(cfg.GetConcurrency())
+	blamer := git.NewBlamer(dir, semaphore, baseLogger, warnings)
+	blamer.SetFormat(git.BlameFormat(cfg.BlameFormat))
+	// Untracked files have no history to blame, only the spell check reads them
+	blamer.Skip(untrackedPaths...)
+
+	// The linters see the work tree, so the lines of a dirty file that
+	// differ from HEAD are blamed on the uncommitted author rather than on
+	// whoever committed the lines they replaced
+	var dirtyPaths []string
+	if hasCommits {
+		for file := range workTree.Modified {
+			if filteredFiles[file] {
+				dirtyPaths = append(dirtyPaths, file)
+			}
+		}
+		sort.Strings(dirtyPaths)
+		blamer.MarkDirty(dirtyPaths...)
+		report.Repo.DirtyFiles = len(dirtyPaths)
+	}
+
+	authorStats := make(map[string]*types.AuthorStats)
+	fileStats := make(map[string]*types.FileStats)
+	ruleStats := make(map[string]*types.RuleStats)
+
+	// The issues authors added in their grace period, left off the author
+	// leaderboard; gracing is set when issues were checked for it
+	var graced map[string]*types.AuthorStats
+	gracing := false
+
+	// The report card only needs the issue count, not blame attribution
+	if !hasCommits {
+		for _, lb := range []Leaderboard{LeaderboardAuthors, LeaderboardFiles, LeaderboardRules, LeaderboardRulePlugins, LeaderboardRuleGroups, LeaderboardRuleAuthors, LeaderboardRuff, LeaderboardHotspots} {
+			if enabled[lb] {
+				report.fail(lb, ErrNoCommits)
+			}
+		}
+	} else if len(issues) > 0 && (needsIssues || enabled[LeaderboardRuff]) {
+		phaseStart = time.Now()
+		var mu sync.Mutex
+		issueAnalyzer := analyzer.New(dir, blamer, &mu, warnings)
+		if cfg.GracePeriodDays > 0 {
+			// Only new contributors, who made their first commit in the
+			// window, get a grace period. It runs from the author date of
+			// that commit, whatever date-type the leaderboards use
+			authors, err := git.GetAuthorCommitCounts(ctx, dir, git.AuthorDate, git.NoCredit)
+			if err != nil {
+				logger.Warn("Grace period disabled", "phase", "issues", "error", err)
+			} else {
+				firstCommits := make(map[string]time.Time)
+				for email, author := range authors {
+					if !author.FirstCommit.Before(since) {
+						firstCommits[email] = author.FirstCommit
+					}
+				}
+				issueAnalyzer.SetFirstCommits(firstCommits)
+				gracing = true
+			}
+		}
+
+		opts.progress("issues", 0, len(issues))
+		for i, issue := range issues {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			if err := issueAnalyzer.ProcessIssueWithConfig(ctx, issue, cfg, authorStats, fileStats, ruleStats); err != nil {
+				logger.Debug("Failed to process issue", "phase", "issues", "file", issue.FilePath, "line", issue.Line, "rule", issue.RuleID, "error", err)
+				continue
+			}
+			opts.progress("issues", i+1, len(issues))
+		}
+		graced = issueAnalyzer.Graced()
+		report.track(logger, "issues", phaseStart)
+	}
+
+	var weights *config.IssueWeights
+	if opts.Weighted {
+		weights = &cfg.IssueWeights
+	}
+	if issueSourceRan {
+		if enabled[LeaderboardAuthors] {
+			ranked := leaderboard.WithoutGracedIssues(authorStats, graced)
+			report.Authors = leaderboard.GenerateAuthorLeaderboard(ranked, 0, weights)
+			report.AuthorStats = ranked
+			for _, issues := range graced {
+				report.NewContributorIssues += issues.Count
+			}
+		}
+		if enabled[LeaderboardFiles] {
+			report.Files = leaderboard.GenerateFileLeaderboard(fileStats, 0, opts.FileSort, weights)
+
+			owners, _, err := codeowners.Load(dir)
+			if err != nil {
+				logger.Warn("Failed to read CODEOWNERS", "phase", "files", "error", err)
+			}
+			if err := leaderboard.ResolveFileOwners(ctx, report.Files, owners, blamer, cfg); err != nil {
+				return nil, err
+			}
+		}
+		if enabled[LeaderboardRules] {
+			report.Rules = leaderboard.GenerateRuleLeaderboard(ruleStats, 0)
+			leaderboard.LinkRuleDocs(report.Rules, cfg.RuleDocLinks)
+		}
+		if enabled[LeaderboardRulePlugins] {
+			report.RulePlugins = leaderboard.GenerateRulePluginLeaderboard(ruleStats)
+		}
+		if enabled[LeaderboardRuleGroups] {
+			report.RuleGroups = leaderboard.GenerateRuleGroupLeaderboard(ruleStats, cfg)
+		}
+		if enabled[LeaderboardRuleAuthors] {
+			report.RuleAuthors = leaderboard.GenerateRuleAuthorMatrix(authorStats)
+		}
+	}
+
+	if needsRuff && enabled[LeaderboardRuff] && report.RuffIssues > 0 {
+		report.RuffRules = leaderboard.GenerateRuleLeaderboard(ruleStats, 0)
+		leaderboard.LinkRuleDocs(report.RuffRules, cfg.RuleDocLinks)
+	}
+
+	generators := []struct {
+		leaderboard Leaderboard
+		generate    func() error
+
+		// graded leaderboards also run when the report card is enabled
+		graded bool
+
+		// results point at the report fields generate sets, which are
+		// what Options.State saves
+		results []any
+	}{
+		{LeaderboardLinesOfCode, func() error {
+			report.LinesOfCode = leaderboard.GenerateLinesOfCodeLeaderboard(ctx, dir, locFiles, 0, opts.scanProgress(LeaderboardLinesOfCode))
+			for i := range report.LinesOfCode {
+				report.LinesOfCode[i].Untracked = isUntracked(report.LinesOfCode[i].Path)
+			}
+			return nil
+		}, false, []any{&report.LinesOfCode}},
+		{LeaderboardCommits, func() (err error) {
+			var revs []string
+			if opts.ChangedSince != "" {
+				revs = []string{opts.ChangedSince + "..HEAD"}
+			}
+			report.Commits, err = leaderboard.GenerateCommitCountLeaderboard(ctx, dir, git.DateType(cfg.DateType), git.CoAuthorCredit(cfg.CoAuthorCredit), 0, revs...)
+			return err
+		}, true, []any{&report.Commits}},
+		{LeaderboardRecent, func() (err error) {
+			report.Recent, err = leaderboard.GenerateRecentContributorsLeaderboard(ctx, dir, git.DateType(cfg.DateType), 0)
+			return err
+		}, false, []any{&report.Recent}},
+		{LeaderboardNewContrib, func() (err error) {
+			gracePeriodDays := 0
+			if gracing {
+				gracePeriodDays = cfg.GracePeriodDays
+			}
+			report.NewContributors, err = leaderboard.GenerateNewContributors(ctx, dir, git.DateType(cfg.DateType), since, runStart, gracePeriodDays, graced)
+			return err
+		}, false, []any{&report.NewContributors}},
+		{LeaderboardCoverage, func() (err error) {
+			lastCommit, err := git.GetHeadTime(ctx, dir)
+			if err != nil {
+				return fmt.Errorf("failed to get the date of HEAD: %w", err)
+			}
+			report.Coverage, report.OverallCoverage, report.CoverageFreshness, err = leaderboard.GenerateCodeCoverageLeaderboard(dir, filteredFiles, opts.CoverageFile, lastCommit, 0)
+			if opts.RequireCoverage {
+				// Only a repository without a report has no freshness
+				if err == nil && report.CoverageFreshness == nil {
+					err = &cerrors.ErrCoverageMissing{}
+				} else if errors.Is(err, fs.ErrNotExist) {
+					err = &cerrors.ErrCoverageMissing{Path: opts.CoverageFile}
+				}
+			}
+			if stale := coverage.StaleMessage(report.CoverageFreshness); stale != "" {
+				logger.Warn("Stale coverage: "+stale, "phase", string(LeaderboardCoverage), "file", report.CoverageFreshness.Report)
+			}
+			return err
+		}, true, []any{&report.Coverage, &report.OverallCoverage, &report.CoverageFreshness}},
+		{LeaderboardChurn, func() (err error) {
+			report.Churn, err = leaderboard.GenerateCodeChurnLeaderboard(ctx, dir, churnFiles, git.DateType(cfg.DateType), runStart, 0, opts.ChurnSort)
+			return err
+		}, false, []any{&report.Churn}},
+		{LeaderboardBugs, func() (err error) {
+			report.BugDensity, err = leaderboard.GenerateBugDensityLeaderboard(ctx, dir, filteredFiles, 0)
+			return err
+		}, false, []any{&report.BugDensity}},
+		{LeaderboardDebt, func() (err error) {
+			report.TechnicalDebt, err = leaderboard.GenerateTechnicalDebtLeaderboard(dir, debtFiles, cfg.MaxLineSize, findings, warnings, 0, opts.scanProgress(LeaderboardDebt))
+			findings.Check(suppression.KindDebt, debtFiles)
+			for i := range report.TechnicalDebt {
+				report.TechnicalDebt[i].Untracked = isUntracked(report.TechnicalDebt[i].Path)
+			}
+			return err
+		}, true, []any{&report.TechnicalDebt}},
+		{LeaderboardSpellCheck, func() (err error) {
+			report.SpellCheck, report.SpellCheckAuthors, err = leaderboard.GenerateSpellCheckLeaderboard(ctx, dir, spellCheckFiles, cfg, blamer, warnings, 0, opts.scanProgress(LeaderboardSpellCheck))
+			if err == nil {
+				report.SpellCheck = leaderboard.SuppressSpelling(report.SpellCheck, report.SpellCheckAuthors, findings)
+				findings.Check(suppression.KindSpelling, spellCheckFiles)
+			}
+			for i := range report.SpellCheck {
+				report.SpellCheck[i].Untracked = isUntracked(report.SpellCheck[i].Path)
+			}
+			return err
+		}, cfg.SpellCheckEnabled, []any{&report.SpellCheck, &report.SpellCheckAuthors}},
+		{LeaderboardFormatDrift, func() (err error) {
+			report.FormatDrift, err = leaderboard.GenerateFormatDriftLeaderboard(ctx, dir, filteredFiles, cfg, blamer, 0)
+			return err
+		}, false, []any{&report.FormatDrift}},
+		{LeaderboardEncoding, func() (err error) {
+			report.Encoding, err = leaderboard.GenerateEncodingLeaderboard(ctx, dir, encodingFiles, 0)
+			return err
+		}, false, []any{&report.Encoding}},
+		{LeaderboardLongFuncs, func() (err error) {
+			report.LongFunctions, err = leaderboard.GenerateLongFunctionLeaderboard(ctx, dir, contentFiles, cfg, warnings, 0)
+			return err
+		}, false, []any{&report.LongFunctions}},
+		{LeaderboardGitHub, func() (err error) {
+			report.Forge, err = forgeStats(ctx, dir, opts.Forge, cfg.GitLabBaseURL, since)
+			return err
+		}, false, []any{&report.Forge}},
+		{LeaderboardLeadTime, func() (err error) {
+			report.LeadTime, err = leaderboard.GenerateLeadTimeStats(ctx, dir, since, runStart)
+			return err
+		}, false, []any{&report.LeadTime}},
+		{LeaderboardChangelog, func() (err error) {
+			report.Changelog, err = leaderboard.GenerateChangelogReadiness(ctx, dir, git.DateType(cfg.DateType), since, runStart)
+			return err
+		}, false, []any{&report.Changelog}},
+		{LeaderboardDeletions, func() (err error) {
+			report.Deletions, err = leaderboard.GenerateDeletionStats(ctx, dir, git.DateType(cfg.DateType), since, runStart, opts.IncludeVendored)
+			return err
+		}, false, []any{&report.Deletions}},
+		{LeaderboardTimezones, func() (err error) {
+			report.Timezones, err = leaderboard.GenerateTimezoneStats(ctx, dir, cfg.TimezoneMinCommits)
+			return err
+		}, false, []any{&report.Timezones}},
+		{LeaderboardLFS, func() (err error) {
+			report.LFS, err = leaderboard.GenerateLFSReport(ctx, dir, warnings)
+			return err
+		}, false, []any{&report.LFS}},
+		{LeaderboardVulns, func() (err error) {
+			report.Vulnerabilities, err = leaderboard.GenerateVulnerabilityLeaderboard(ctx, dir, warnings)
+			return err
+		}, false, []any{&report.Vulnerabilities}},
+		{LeaderboardDeps, func() (err error) {
+			report.Dependencies = leaderboard.GenerateDependencyLeaderboard(dir, manifestFiles, warnings)
+			return nil
+		}, false, []any{&report.Dependencies}},
+	}
+
+	state := opts.State
+	var inputs string
+	if state != nil {
+		inputs, err = stateInputHash(ctx, dir, &lintCfg, opts, untrackedPaths)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			logger.Warn("Run state disabled", "phase", "state", "error", err)
+			state = nil
+		}
+	}
+
+	// Leaderboards the suppression baseline leaves findings out of, other
+	// than those counted from the issues
+	suppressedFrom := map[Leaderboard]bool{LeaderboardDebt: true, LeaderboardSpellCheck: true}
+
+	// Leaderboards read from git history or blame
+	needsHistory := map[Leaderboard]bool{
+		LeaderboardCommits: true, LeaderboardRecent: true, LeaderboardNewContrib: true, LeaderboardChurn: true, LeaderboardBugs: true,
+		LeaderboardSpellCheck: true, LeaderboardFormatDrift: true, LeaderboardLeadTime: true, LeaderboardChangelog: true, LeaderboardTimezones: true, LeaderboardDeletions: true, LeaderboardLFS: true,
+	}
+
+	for _, g := range generators {
+		if !enabled[g.leaderboard] && !(gradeCard && g.graded) && !(byWorkspace && rolledUp[g.leaderboard]) && !(scored && scoredFrom[g.leaderboard]) && !(hotspots && g.leaderboard == LeaderboardChurn) &&
+			!(opts.RecordFindings && g.leaderboard == LeaderboardDebt) && !(opts.RequireCoverage && g.leaderboard == LeaderboardCoverage) {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if !hasCommits && needsHistory[g.leaderboard] {
+			report.fail(g.leaderboard, ErrNoCommits)
+			continue
+		}
+
+		// Leaderboards findings are suppressed from are not saved, nor
+		// reused, as the baseline may have changed
+		saved := state != nil && !(findings != nil && suppressedFrom[g.leaderboard])
+		if saved && state.Lookup(string(g.leaderboard), inputs, g.results...) {
+			logger.Debug("Reused leaderboard from run state", "phase", string(g.leaderboard), "file", state.Path())
+			report.Reused = append(report.Reused, g.leaderboard)
+			continue
+		}
+
+		phaseStart = time.Now()
+		opts.progress(string(g.leaderboard), 0, 0)
+		if err := g.generate(); err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			report.fail(g.leaderboard, err)
+		} else if saved {
+			if err := state.Complete(string(g.leaderboard), inputs, g.results...); err != nil {
+				logger.Warn("Failed to save run state", "phase", string(g.leaderboard), "error", err)
+			}
+		}
+		report.track(logger, string(g.leaderboard), phaseStart)
+	}
+
+	if byWorkspace {
+		workspaces, err := workspace.Discover(dir, trackedPaths)
+		if err != nil {
+			report.fail(LeaderboardWorkspaces, err)
+		} else {
+			report.Workspaces = leaderboard.GenerateWorkspaceLeaderboard(workspaces, contentFiles, fileStats, report.LinesOfCode, report.TechnicalDebt, report.Coverage)
+		}
+	}
+
+	if hotspots && hasCommits {
+		if err := report.Errors[LeaderboardChurn]; err != nil {
+			report.fail(LeaderboardHotspots, err)
+		} else if issueSourceRan {
+			report.Hotspots = leaderboard.GenerateHotspotLeaderboard(report.Churn, fileStats, 0)
+		}
+	}
+
+	if opts.Suppression != nil && !opts.IncludeBaselined {
+		report.Suppression = suppressionStats(findings, opts.Suppression.Path, dir)
+		if stale := len(report.Suppression.Stale); stale > 0 {
+			warnings.Add(types.NewWarning("Stale suppression baseline entries", "file", opts.Suppression.Path, "count", stale))
+		}
+	}
+
+	if enabled[LeaderboardSummary] {
+		summary := leaderboard.GenerateSummaryStats(authorStats, fileStats, ruleStats, weights)
+		report.Summary = &summary
+	}
+
+	if scored {
+		stats := score.Compute(scoreInput(report, issues, &lintCfg, weights, dir, lintRan), cfg.ScoreWeights)
+		report.Score = &stats
+	}
+
+	if gradeCard {
+		card := leaderboard.GenerateReportCard(reportCardInput(report, lintRan, len(issues)), cfg)
+		report.ReportCard = &card
+	}
+
+	report.track(logger, "total", runStart)
+	report.Warnings = warnings.Warnings()
+
+	return report, nil
+}
+
+// suppressionStats counts the findings filter suppressed and lists the
+// stale records of the baseline at path, of the repository in dir.
+func suppressionStats(filter *suppression.Filter, path, dir string) *types.Suppression {
+	stats := &types.Suppression{
+		File:         path,
+		Issues:       filter.Suppressed(suppression.KindIssue),
+		DebtMarkers:  filter.Suppressed(suppression.KindDebt),
+		Misspellings: filter.Suppressed(suppression.KindSpelling),
+	}
+	exists := func(file string) bool {
+		_, err := os.Lstat(filepath.Join(dir, filepath.FromSlash(file)))
+		return err == nil
+	}
+	for _, record := range filter.Stale(exists) {
+		stats.Stale = append(stats.Stale, types.StaleSuppression{Kind: record.Kind, Path: record.Path, Rule: record.Rule, Deleted: record.Deleted})
+	}
+	return stats
+}
+
+// reportCardInput collects the measurements the report card is graded from.
+// Issues are only graded when at least one lint source ran.
+func reportCardInput(report *Report, lintRan bool, issues int) types.ReportCardInput {
+	input := types.ReportCardInput{
+		AnalyzedFiles:   report.Repo.AnalyzedFiles,
+		HasCoverage:     len(report.Coverage) > 0,
+		CoveragePercent: report.OverallCoverage,
+		HasIssues:       lintRan,
+		Issues:          issues,
+		HasDebt:         report.Errors[LeaderboardDebt] == nil,
+		HasSpelling:     report.SpellCheck != nil,
+		HasBusFactor:    len(report.Commits) > 0,
+		BusFactor:       leaderboard.BusFactor(report.Commits),
+	}
+
+	for _, entry := range report.TechnicalDebt {
+		input.DebtItems += entry.TotalDebt
+	}
+	for _, entry := range report.SpellCheck {
+		input.MisspelledWords += entry.MisspelledWords
+		input.TotalWords += entry.TotalWords
+	}
+
+	return input
+}
+
+// applyRuleSeverities drops the issues of ignored rules and gives the rest
+// the severity their rule is overridden to, if any.
+func applyRuleSeverities(issues []types.Issue, cfg *config.Config) []types.Issue {
+	kept := issues[:0]
+	for _, issue := range issues {
+		severity, ok := cfg.RuleSeverity(issue.RuleID, issue.Severity)
+		if !ok {
+			continue
+		}
+		issue.Severity = severity
+		kept = append(kept, issue)
+	}
+	return kept
+}
+
+// scoreInput collects the per-file measurements the quality score is
+// computed from, issues weighted by weights when they are set. Untracked
+// files are not scored, since neither the linters nor git history see them.
+func scoreInput(report *Report, issues []types.Issue, cfg *config.Config, weights *config.IssueWeights, dir string, lintRan bool) score.Input {
+	input := score.Input{Weighted: weights != nil, Measured: map[string]bool{
+		"issues":     lintRan,
+		"coverage":   report.Errors[LeaderboardCoverage] == nil && len(report.Coverage) > 0,
+		"debt":       report.Errors[LeaderboardDebt] == nil,
+		"churn":      report.Errors[LeaderboardChurn] == nil,
+		"complexity": report.Errors[LeaderboardLongFuncs] == nil,
+	}}
+
+	issueCounts := make(map[string]int)
+	issueWeights := make(map[string]float64)
+	for _, issue := range issues {
+		if issue.Severity == types.SeverityOff || cfg.ShouldIgnoreRepoFile(dir, issue.FilePath) || cfg.ShouldIgnoreRule(issue.RuleID) {
+			continue
+		}
+		file := filepath.ToSlash(issue.FilePath)
+		issueCounts[file]++
+		if weights != nil {
+			issueWeights[file] += weights.Weight(issue.RuleID, issue.Severity >= types.SeverityError)
+		}
+	}
+	coverage := make(map[string]types.CoverageEntry)
+	for _, entry := range report.Coverage {
+		coverage[entry.Path] = entry
+	}
+	debt := make(map[string]int)
+	for _, entry := range report.TechnicalDebt {
+		debt[entry.Path] = entry.TotalDebt
+	}
+	changes := make(map[string]int)
+	for _, entry := range report.Churn {
+		changes[entry.Path] = entry.Changes
+	}
+	longFunctionLines := make(map[string]int)
+	for _, entry := range report.LongFunctions {
+		longFunctionLines[entry.Path] += entry.Lines
+	}
+
+	for _, entry := range report.LinesOfCode {
+		if entry.Untracked {
+			continue
+		}
+		input.Files = append(input.Files, score.File{
+			Path:              entry.Path,
+			Lines:             entry.Lines,
+			Issues:            issueCounts[entry.Path],
+			WeightedIssues:    issueWeights[entry.Path],
+			Debt:              debt[entry.Path],
+			Changes:           changes[entry.Path],
+			CoveredLines:      coverage[entry.Path].LinesCovered,
+			CoverableLines:    coverage[entry.Path].LinesTotal,
+			Functions:         leaderboard.MeasuresFunctions(entry.Path),
+			LongFunctionLines: longFunctionLines[entry.Path],
+		})
+	}
+
+	return input
+}
+
+// stateInputHash identifies what the leaderboards in a run state are computed
+// from: the analyzed contents, the configuration and the options that affect
+// them.
+func stateInputHash(ctx context.Context, dir string, cfg *config.Config, opts Options, untrackedPaths []string) (string, error) {
+	head, diff, err := git.GetWorkTreeState(ctx, dir)
+	if err != nil {
+		return "", err
+	}
+
+	// Untracked files have no diff, so their size and modification time
+	// stand in for their contents
+	var untracked strings.Builder
+	for _, file := range untrackedPaths {
+		info, err := os.Lstat(filepath.Join(dir, file))
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&untracked, "%s %d %d\n", file, info.Size(), info.ModTime().UnixNano())
+	}
+
+	var settings strings.Builder
+	if err := cfg.WriteEffective(&settings); err != nil {
+		return "", err
+	}
+
+	since := ""
+	if !opts.Since.IsZero() {
+		since = opts.Since.UTC().Format(time.RFC3339)
+	}
+	// A tag can be moved, so the commit it names is what counts
+	changedSince := ""
+	if opts.ChangedSince != "" {
+		if changedSince, err = git.ResolveCommit(ctx, dir, opts.ChangedSince); err != nil {
+			return "", err
+		}
+	}
+
+	return reporting.InputHash(
+		fmt.Sprint(types.ReportSchemaVersion), head, string(diff), settings.String(),
+		opts.CoverageFile, since, fmt.Sprintf("%T", opts.Forge), untracked.String(), fmt.Sprint(opts.IncludeVendored),
+		strings.Join(opts.Languages, ","), strings.Join(opts.OnlyExtensions, ","), strings.Join(opts.ExcludeExtensions, ","), string(opts.ChurnSort), strings.Join(outputDirPrefixes(dir, opts.OutputDirs), ","), changedSince,
+	), nil
+}
+
+// forgeStats fetches the pull requests merged since the start of the window
+// from f, or from the forge hosting the origin remote when f is nil.
+// gitlabBaseURL locates self-hosted GitLab instances.
+func forgeStats(ctx context.Context, dir string, f Forge, gitlabBaseURL string, since time.Time) (*types.ForgeStats, error) {
+	if f == nil {
+		remote, err := git.GetRemoteURL(ctx, dir, "origin")
+		if err != nil {
+			return nil, err
+		}
+
+		// Without a cache directory every run fetches everything again
+		var cache *forge.Cache
+		if cacheDir, err := forge.DefaultCacheDir(); err == nil {
+			cache = forge.NewCache(cacheDir)
+		}
+
+		f, err = forge.Detect(remote, os.Getenv, cache, gitlabBaseURL)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	pullRequests, err := f.MergedPullRequests(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch pull requests from %s: %w", f.Name(), err)
+	}
+
+	stats := forge.Stats(f, pullRequests, since)
+	return &stats, nil
+}
+
+// outputDirPrefixes returns the output directories inside the repository in
+// dir as slash-separated prefixes of the paths of their files, such as
+// ".codecompass/history/". Symlinks are resolved on both sides, so a
+// directory given through a symlink still matches; a directory that does
+// not exist yet is compared as given.
+func outputDirPrefixes(dir string, outputDirs []string) []string {
+	root := dir
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		root = resolved
+	}
+
+	var prefixes []string
+	for _, outputDir := range outputDirs {
+		path, err := filepath.Abs(outputDir)
+		if outputDir == "" || err != nil {
+			continue
+		}
+		pairs := [][2]string{{dir, path}}
+		if resolved, err := filepath.EvalSymlinks(path); err == nil {
+			pairs = append(pairs, [2]string{root, resolved})
+		}
+		for _, pair := range pairs {
+			rel, err := filepath.Rel(pair[0], pair[1])
+			// The repository itself is not an output directory worth
+			// leaving everything out for
+			if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				continue
+			}
+			prefixes = append(prefixes, filepath.ToSlash(rel)+"/")
+		}
+	}
+	return prefixes
+}
+
+// inOutputDir reports whether file, relative to the repository, is in one
+// of the directories outputDirPrefixes returned.
+func inOutputDir(file string, prefixes []string) bool {
+	file = filepath.ToSlash(file)
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(file, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveRepoPath returns the absolute path of the repository directory.
+func resolveRepoPath(repoPath string) (string, error) {
+	if repoPath == "" {
+		repoPath = "."
+	}
+
+	absPath, err := filepath.Abs(repoPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve path %s: %w", repoPath, err)
+	}
+
+	info, err := os.Stat(absPath)
+	if err != nil {
+		return "", fmt.Errorf("directory does not exist: %s", absPath)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("not a directory: %s", absPath)
+	}
+
+	return absPath, nil
+}
@@ -0,0 +1,1545 @@
+package compass
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xeon-zolt/codecompass/internal/forge"
+	"github.com/xeon-zolt/codecompass/internal/history"
+	"github.com/xeon-zolt/codecompass/internal/lint"
+	"github.com/xeon-zolt/codecompass/internal/ruff"
+	"github.com/xeon-zolt/codecompass/internal/testutil"
+	"github.com/xeon-zolt/codecompass/internal/toolversion"
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+// newFixtureRepo creates a git repository with two commits by different
+// authors.
+func newFixtureRepo(t *testing.T) *testutil.RepoBuilder {
+	t.Helper()
+
+	return testutil.NewRepo(t).
+		WithAuthor("Alice", "alice@example.com").
+		Commit("initial commit", map[string]string{
+			"main.js":     "// TODO: remove this\nconsole.log('hello');\n",
+			"lib/util.js": "function add(a, b) {\n  return a + b;\n}\n\nmodule.exports = add;\n",
+		}).
+		WithAuthor("Bob", "bob@example.com").
+		Commit("fix util", map[string]string{
+			"lib/util.js": "// FIXME: handle strings\nfunction add(a, b) {\n  return a + b;\n}\n\nmodule.exports = add;\n",
+		})
+}
+
+func TestRunFixtureRepo(t *testing.T) {
+	dir := newFixtureRepo(t).Dir()
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := Run(context.Background(), Options{
+		RepoPath: dir,
+		Leaderboards: []Leaderboard{
+			LeaderboardLinesOfCode,
+			LeaderboardCommits,
+			LeaderboardChurn,
+			LeaderboardDebt,
+			LeaderboardSummary,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	// The library must not change the process working directory
+	if after, _ := os.Getwd(); after != cwd {
+		t.Errorf("Expected working directory to stay %s, but got %s", cwd, after)
+	}
+
+	if err := report.Validate(); err != nil {
+		t.Errorf("Expected a valid report, but got %v", err)
+	}
+
+	if report.SchemaVersion != types.ReportSchemaVersion || len(report.Leaderboards) != 5 {
+		t.Errorf("Expected a versioned report listing the 5 requested leaderboards, but got version %d and %v", report.SchemaVersion, report.Leaderboards)
+	}
+
+	if report.Repo.TrackedFiles != 2 {
+		t.Errorf("Expected 2 tracked files, but got %d", report.Repo.TrackedFiles)
+	}
+
+	if len(report.Repo.Head) != 40 {
+		t.Errorf("Expected the commit analyzed to be recorded, but got %q", report.Repo.Head)
+	}
+
+	if len(report.LinesOfCode) != 2 || report.LinesOfCode[0].Path != "lib/util.js" {
+		t.Errorf("Expected lib/util.js to be the largest file, but got %+v", report.LinesOfCode)
+	}
+
+	if len(report.Commits) != 2 {
+		t.Fatalf("Expected 2 committers, but got %+v", report.Commits)
+	}
+
+	if len(report.Churn) == 0 || report.Churn[0].Path != "lib/util.js" || report.Churn[0].Changes != 2 {
+		t.Errorf("Expected lib/util.js to have the most churn, but got %+v", report.Churn)
+	}
+
+	if len(report.TechnicalDebt) != 2 {
+		t.Errorf("Expected 2 files with technical debt, but got %+v", report.TechnicalDebt)
+	}
+
+	if report.Summary == nil {
+		t.Errorf("Expected summary to be populated")
+	}
+
+	// Leaderboards that were not requested stay empty and ESLint never runs
+	if report.Authors != nil || report.Recent != nil || report.ESLintIssues != 0 {
+		t.Errorf("Expected unrequested leaderboards to be empty")
+	}
+
+	if len(report.Errors) != 0 {
+		t.Errorf("Expected no leaderboard errors, but got %v", report.Errors)
+	}
+
+	if _, exists := report.Timings["total"]; !exists {
+		t.Errorf("Expected total timing to be recorded")
+	}
+}
+
+func TestRunNotGitRepository(t *testing.T) {
+	_, err := Run(context.Background(), Options{
+		RepoPath:     t.TempDir(),
+		Leaderboards: []Leaderboard{LeaderboardLinesOfCode},
+	})
+	if !errors.Is(err, ErrNotGitRepository) {
+		t.Errorf("Expected ErrNotGitRepository, but got %v", err)
+	}
+}
+
+func TestRunMissingDirectory(t *testing.T) {
+	_, err := Run(context.Background(), Options{
+		RepoPath: filepath.Join(t.TempDir(), "missing"),
+	})
+	if err == nil {
+		t.Errorf("Expected an error for a missing directory")
+	}
+}
+
+func TestRunCancelledContext(t *testing.T) {
+	dir := newFixtureRepo(t).Dir()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := Run(ctx, Options{
+		RepoPath:     dir,
+		Leaderboards: []Leaderboard{LeaderboardLinesOfCode},
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, but got %v", err)
+	}
+}
+
+func TestRunWithPluginSource(t *testing.T) {
+	dir := newFixtureRepo(t).Dir()
+
+	plugin := lint.NewPlugin(filepath.Join("..", "..", "examples", "plugins", "codecompass-lint-todo"))
+	report, err := Run(context.Background(), Options{
+		RepoPath:     dir,
+		Leaderboards: []Leaderboard{LeaderboardAuthors, LeaderboardRules},
+		Sources:      []LintSource{plugin},
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if len(report.Sources) != 1 || report.Sources[0].Name != "todo" || report.Sources[0].Issues != 1 {
+		t.Fatalf("Expected one todo issue, but got %+v", report.Sources)
+	}
+
+	if len(report.Authors) != 1 || report.Authors[0].Name != "Alice" {
+		t.Errorf("Expected Alice to own the TODO, but got %+v", report.Authors)
+	}
+
+	if len(report.Rules) != 1 || report.Rules[0].Rule != "todo/todo-comment" {
+		t.Errorf("Expected the todo rule leaderboard, but got %+v", report.Rules)
+	}
+	if report.ToolVersions["todo"] != "1.0.0" || report.ToolVersions["git"] == "" || report.ToolVersions["git"] == toolversion.Unknown {
+		t.Errorf("Expected the versions of git and the plugin, but got %v", report.ToolVersions)
+	}
+}
+
+func TestRunClassifiesIssuesAgainstBaseline(t *testing.T) {
+	repo := newFixtureRepo(t)
+	history := t.TempDir()
+	plugin := lint.NewPlugin(filepath.Join("..", "..", "examples", "plugins", "codecompass-lint-todo"))
+	opts := Options{
+		RepoPath:     repo.Dir(),
+		Leaderboards: []Leaderboard{LeaderboardSummary},
+		Sources:      []LintSource{plugin},
+		TrackIssues:  true,
+	}
+
+	report, err := Run(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(report.Fingerprints) != 1 || report.IssueBaseline != nil {
+		t.Fatalf("Expected one fingerprint and no baseline, but got %v and %+v", report.Fingerprints, report.IssueBaseline)
+	}
+	if baseline, err := ReadBaseline(history); err != nil || baseline != nil {
+		t.Fatalf("Expected no baseline yet, but got %+v, %v", baseline, err)
+	}
+	if err := WriteBaseline(history, report); err != nil {
+		t.Fatal(err)
+	}
+
+	// Lines added above the TODO keep it pre-existing; a second one is new
+	repo.Commit("more todos", map[string]string{
+		"main.js": "// header\n\n// TODO: remove this\nconsole.log('hello');\n// TODO: and this\n",
+	})
+	baseline, err := ReadBaseline(history)
+	if err != nil || baseline == nil {
+		t.Fatalf("Expected the baseline written, but got %+v, %v", baseline, err)
+	}
+	opts.Baseline = baseline
+	report, err = Run(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	expected := &IssueBaseline{Reference: filepath.Join(history, "baseline.txt"), New: 1, Existing: 1}
+	if !reflect.DeepEqual(report.IssueBaseline, expected) {
+		t.Errorf("Expected %+v, but got %+v", expected, report.IssueBaseline)
+	}
+}
+
+func TestRunSuppressesBaselinedFindings(t *testing.T) {
+	repo := newFixtureRepo(t)
+	opts := Options{
+		RepoPath:     repo.Dir(),
+		Leaderboards: []Leaderboard{LeaderboardSummary},
+		Sources:      []LintSource{lint.NewPlugin(filepath.Join("..", "..", "examples", "plugins", "codecompass-lint-todo"))},
+	}
+
+	if baseline, err := ReadSuppressionBaseline(repo.Dir(), ""); err != nil || baseline != nil {
+		t.Fatalf("Expected no suppression baseline yet, but got %+v, %v", baseline, err)
+	}
+	opts.RecordFindings = true
+	report, err := Run(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	// The TODO issue, and the TODO and FIXME debt markers
+	if n, err := WriteSuppressionBaseline(filepath.Join(repo.Dir(), ".codecompass-baseline.json"), report); err != nil || n != 3 {
+		t.Fatalf("Expected 3 findings written, but got %d, %v", n, err)
+	}
+
+	// Lines added above the TODO keep it baselined, the FIXME is gone
+	repo.Commit("more todos", map[string]string{
+		"main.js":     "// header\n\n// TODO: remove this\nconsole.log('hello');\n// TODO: and this\n",
+		"lib/util.js": "function add(a, b) {\n  return a + b;\n}\n\nmodule.exports = add;\n",
+	})
+	baseline, err := ReadSuppressionBaseline(repo.Dir(), "")
+	if err != nil || baseline == nil {
+		t.Fatalf("Expected the suppression baseline written, but got %+v, %v", baseline, err)
+	}
+	opts.Suppression = baseline
+	opts.RecordFindings = false
+	opts.Leaderboards = []Leaderboard{LeaderboardAuthors, LeaderboardDebt, LeaderboardSummary}
+	report, err = Run(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if report.Summary.TotalIssues != 1 {
+		t.Errorf("Expected only the new TODO counted, but got %d issues", report.Summary.TotalIssues)
+	}
+	if len(report.TechnicalDebt) != 1 || report.TechnicalDebt[0].TotalDebt != 1 {
+		t.Errorf("Expected only the new debt marker counted, but got %+v", report.TechnicalDebt)
+	}
+	expected := &Suppression{
+		File:        baseline.Path,
+		Issues:      1,
+		DebtMarkers: 1,
+		Stale:       []StaleSuppression{{Kind: "debt", Path: "lib/util.js", Rule: "FIXME"}},
+	}
+	if !reflect.DeepEqual(report.Suppression, expected) {
+		t.Errorf("Expected %+v, but got %+v", expected, report.Suppression)
+	}
+
+	opts.IncludeBaselined = true
+	report, err = Run(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if report.Summary.TotalIssues != 2 || report.Suppression != nil {
+		t.Errorf("Expected both TODOs and no suppression, but got %d issues and %+v", report.Summary.TotalIssues, report.Suppression)
+	}
+}
+
+func TestReadTrend(t *testing.T) {
+	repo := newFixtureRepo(t)
+	dir := t.TempDir()
+	opts := Options{
+		RepoPath:     repo.Dir(),
+		Leaderboards: []Leaderboard{LeaderboardAuthors, LeaderboardSummary, LeaderboardDebt},
+		Sources:      []LintSource{lint.NewPlugin(filepath.Join("..", "..", "examples", "plugins", "codecompass-lint-todo"))},
+	}
+
+	previous, err := Run(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if trend, err := ReadTrend(&previous.Report, dir); err != nil || trend != nil {
+		t.Fatalf("Expected no trend without logged runs, but got %+v, %v", trend, err)
+	}
+	if err := (&history.Writer{Dir: dir}).WriteReport(&previous.Report); err != nil {
+		t.Fatal(err)
+	}
+
+	repo.Commit("more todos", map[string]string{
+		"main.js": "// TODO: remove this\nconsole.log('hello');\n// TODO: and this\n",
+	})
+	report, err := Run(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	// Runs are logged by the second
+	report.GeneratedAt = previous.GeneratedAt.Add(time.Minute)
+
+	trend, err := ReadTrend(&report.Report, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trend == nil || !trend.Since.Equal(previous.GeneratedAt.Truncate(time.Second)) {
+		t.Fatalf("Expected the trend since the logged run, but got %+v", trend)
+	}
+	deltas := make(map[string]MetricDelta)
+	for _, delta := range trend.Deltas {
+		deltas[delta.Metric] = delta
+	}
+	if delta := deltas["issues"]; delta.A != 1 || delta.B != 2 || delta.Delta != 1 || delta.Missing {
+		t.Errorf("Expected one more issue, but got %+v", delta)
+	}
+	if delta := deltas["debt"]; delta.Delta != 1 || delta.Missing {
+		t.Errorf("Expected one more debt marker, but got %+v", delta)
+	}
+	if _, ok := deltas["coverage"]; ok {
+		t.Errorf("Expected coverage to be left out, as it was not measured, but got %+v", trend.Deltas)
+	}
+}
+
+func TestSaveAndLoadReport(t *testing.T) {
+	dir := newFixtureRepo(t).Dir()
+
+	broken := filepath.Join(t.TempDir(), "codecompass-lint-broken")
+	if err := os.WriteFile(broken, []byte("#!/bin/sh\n[ \"$1\" = detect ] && exit 0\necho broken >&2\nexit 1\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := Run(context.Background(), Options{
+		RepoPath:     dir,
+		Leaderboards: []Leaderboard{LeaderboardAuthors, LeaderboardRules, LeaderboardLinesOfCode, LeaderboardDebt, LeaderboardCommits, LeaderboardCoverage},
+		Sources: []LintSource{
+			lint.NewPlugin(filepath.Join("..", "..", "examples", "plugins", "codecompass-lint-todo")),
+			lint.NewPlugin(broken),
+		},
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	if err := SaveReport(path, report); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadReport(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	saved, _ := json.Marshal(report.Report)
+	reloaded, _ := json.Marshal(loaded.Report)
+	if string(saved) != string(reloaded) {
+		t.Errorf("Expected the leaderboards to survive a round trip\nsaved:  %s\nloaded: %s", saved, reloaded)
+	}
+	if len(loaded.Authors) == 0 || len(loaded.LinesOfCode) == 0 || len(loaded.Commits) == 0 {
+		t.Errorf("Expected leaderboards in the loaded report, but got %+v", loaded.Report)
+	}
+
+	// Errors are restored from their messages
+	if len(loaded.Sources) != 2 || loaded.Sources[0].Issues != 1 || loaded.Sources[0].Err != nil || loaded.Sources[1].Err == nil {
+		t.Errorf("Expected the todo and broken sources, but got %+v", loaded.Sources)
+	}
+	if len(loaded.Errors) != len(report.Errors) {
+		t.Errorf("Expected errors %v, but got %v", report.Errors, loaded.Errors)
+	}
+	for lb, err := range report.Errors {
+		if loaded.Errors[lb] == nil || loaded.Errors[lb].Error() != err.Error() {
+			t.Errorf("Expected the %s error %q, but got %v", lb, err, loaded.Errors[lb])
+		}
+	}
+}
+
+func TestLoadReportRejectsNewerSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.json")
+	newer := types.NewReport("/repo")
+	newer.SchemaVersion = types.ReportSchemaVersion + 1
+	data, _ := json.Marshal(newer)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadReport(path); err == nil || !strings.Contains(err.Error(), "schema version") {
+		t.Errorf("Expected a newer schema to be rejected, but got %v", err)
+	}
+}
+
+func TestRunRuleSeverityOverridesAndGroups(t *testing.T) {
+	dir := newFixtureRepo(t).Dir()
+	plugin := lint.NewPlugin(filepath.Join("..", "..", "examples", "plugins", "codecompass-lint-todo"))
+
+	cfg := NewConfig()
+	cfg.RuleSeverityOverrides = []RuleSeverityOverride{{Pattern: "todo/*", Severity: "error"}}
+	cfg.RuleGroups = []RuleGroup{{Name: "housekeeping", Patterns: []string{"todo/*"}}}
+
+	report, err := Run(context.Background(), Options{
+		RepoPath:     dir,
+		Config:       cfg,
+		Leaderboards: []Leaderboard{LeaderboardAuthors, LeaderboardRuleGroups, LeaderboardRuleAuthors, LeaderboardSummary},
+		Sources:      []LintSource{plugin},
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	// The plugin reports the TODO as a warning
+	if len(report.Authors) != 1 || report.Authors[0].Errors != 1 || report.Authors[0].Warnings != 0 {
+		t.Errorf("Expected the TODO to count as an error, but got %+v", report.Authors)
+	}
+	if report.Summary == nil || report.Summary.Errors != 1 || report.Summary.Warnings != 0 {
+		t.Errorf("Expected the summary to count 1 error, but got %+v", report.Summary)
+	}
+	expected := []RuleGroupEntry{{Rank: 1, Group: "housekeeping", Count: 1, DistinctRules: 1}}
+	if !reflect.DeepEqual(report.RuleGroups, expected) {
+		t.Errorf("Expected rule groups %+v, but got %+v", expected, report.RuleGroups)
+	}
+	if cells := []RuleAuthorCell{{Rule: "todo/todo-comment", Name: "Alice", Email: "alice@example.com", Count: 1}}; !reflect.DeepEqual(report.RuleAuthors, cells) {
+		t.Errorf("Expected rule author cells %+v, but got %+v", cells, report.RuleAuthors)
+	}
+
+	// ignore-rules wins over an override
+	cfg.IgnoredRules = []string{"todo/todo-comment"}
+	report, err = Run(context.Background(), Options{
+		RepoPath:     dir,
+		Config:       cfg,
+		Leaderboards: []Leaderboard{LeaderboardAuthors, LeaderboardRuleGroups},
+		Sources:      []LintSource{plugin},
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(report.Authors) != 0 || len(report.RuleGroups) != 0 {
+		t.Errorf("Expected the ignored rule to be dropped, but got %+v and %+v", report.Authors, report.RuleGroups)
+	}
+}
+
+func TestRunCustomRules(t *testing.T) {
+	dir := newFixtureRepo(t).Dir()
+
+	cfg := NewConfig()
+	cfg.CustomRules = []CustomRule{{Name: "no-print", Pattern: `console\.log`}, {Name: "no-fixme", Pattern: `\bFIXME\b`}}
+
+	report, err := Run(context.Background(), Options{
+		RepoPath:     dir,
+		Config:       cfg,
+		Leaderboards: []Leaderboard{LeaderboardFiles, LeaderboardRuleAuthors},
+		Sources:      []LintSource{},
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	// Each match is blamed on the author of its line
+	cells := []RuleAuthorCell{
+		{Rule: "no-fixme", Name: "Bob", Email: "bob@example.com", Count: 1},
+		{Rule: "no-print", Name: "Alice", Email: "alice@example.com", Count: 1},
+	}
+	sort.Slice(report.RuleAuthors, func(i, j int) bool { return report.RuleAuthors[i].Rule < report.RuleAuthors[j].Rule })
+	if !reflect.DeepEqual(report.RuleAuthors, cells) {
+		t.Errorf("Expected rule author cells %+v, but got %+v", cells, report.RuleAuthors)
+	}
+	if len(report.Files) != 2 {
+		t.Errorf("Expected issues in main.js and lib/util.js, but got %+v", report.Files)
+	}
+	if len(report.Sources) != 1 || report.Sources[0].Name != "custom" || report.Sources[0].Issues != 2 {
+		t.Errorf("Expected the custom source to find 2 issues, but got %+v", report.Sources)
+	}
+}
+
+func TestRunSkipsFilesWithIgnoreDirective(t *testing.T) {
+	dir := newFixtureRepo(t).
+		Commit("add snippet", map[string]string{
+			"snippet.js": "/* codecompass:ignore-file */\n// TODO: copied from upstream\nvar x = 1;\n",
+		}).
+		Dir()
+	plugin := lint.NewPlugin(filepath.Join("..", "..", "examples", "plugins", "codecompass-lint-todo"))
+
+	report, err := Run(context.Background(), Options{
+		RepoPath:     dir,
+		Leaderboards: []Leaderboard{LeaderboardFiles, LeaderboardLinesOfCode, LeaderboardDebt},
+		Sources:      []LintSource{plugin},
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if report.Repo.TrackedFiles != 3 || report.Repo.AnalyzedFiles != 2 {
+		t.Errorf("Expected 3 tracked and 2 analyzed files, but got %+v", report.Repo)
+	}
+	for _, entry := range report.LinesOfCode {
+		if entry.Path == "snippet.js" {
+			t.Errorf("Expected snippet.js to be left out of the lines of code, but got %+v", report.LinesOfCode)
+		}
+	}
+	for _, entry := range report.TechnicalDebt {
+		if entry.Path == "snippet.js" {
+			t.Errorf("Expected snippet.js to be left out of the debt, but got %+v", report.TechnicalDebt)
+		}
+	}
+	if len(report.Files) != 1 || report.Files[0].Path != "main.js" {
+		t.Errorf("Expected issues only from main.js, but got %+v", report.Files)
+	}
+}
+
+func TestRunSkipsOutputDirs(t *testing.T) {
+	repo := newFixtureRepo(t).
+		Commit("log a run", map[string]string{
+			".codecompass/history/file_leaderboard_20260101_090000.csv": "Rank,Path,TopRule\n1,main.js,todo/todo-comment // TODO\n",
+		})
+	repo.Write(map[string]string{"packets/alice.md": "# Alice\n\nTODO: talk about main.js\n"})
+	dir := repo.Dir()
+	plugin := lint.NewPlugin(filepath.Join("..", "..", "examples", "plugins", "codecompass-lint-todo"))
+
+	report, err := Run(context.Background(), Options{
+		RepoPath:         dir,
+		Leaderboards:     []Leaderboard{LeaderboardFiles, LeaderboardLinesOfCode, LeaderboardDebt},
+		Sources:          []LintSource{plugin},
+		IncludeUntracked: true,
+		OutputDirs:       []string{filepath.Join(dir, ".codecompass", "history"), filepath.Join(dir, "packets"), t.TempDir(), dir},
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if report.Repo.TrackedFiles != 3 || report.Repo.AnalyzedFiles != 2 || report.Repo.UntrackedFiles != 0 {
+		t.Errorf("Expected 3 tracked, 2 analyzed and no untracked files, but got %+v", report.Repo)
+	}
+	for _, entry := range report.LinesOfCode {
+		if inOutputDir(entry.Path, []string{".codecompass/", "packets/"}) {
+			t.Errorf("Expected the output directories to be left out of the lines of code, but got %+v", report.LinesOfCode)
+		}
+	}
+	for _, entry := range report.TechnicalDebt {
+		if inOutputDir(entry.Path, []string{".codecompass/", "packets/"}) {
+			t.Errorf("Expected the output directories to be left out of the debt, but got %+v", report.TechnicalDebt)
+		}
+	}
+	if len(report.Files) != 1 || report.Files[0].Path != "main.js" {
+		t.Errorf("Expected issues only from main.js, but got %+v", report.Files)
+	}
+}
+
+func TestRunSkipsOversizedFiles(t *testing.T) {
+	// 2 KB of prose, code and TODOs, over a 1 KB max-file-size
+	large := strings.Repeat("// TODO: recieve the big file\nfunction big() {\n  return 1;\n}\n", 40)
+	dir := newFixtureRepo(t).
+		Commit("add a large file", map[string]string{"big.js": large}).
+		Dir()
+
+	cfg := NewConfig()
+	cfg.MaxFileSize = 1
+	run := func() *Report {
+		report, err := Run(context.Background(), Options{
+			RepoPath:     dir,
+			Config:       cfg,
+			Leaderboards: []Leaderboard{LeaderboardLinesOfCode, LeaderboardDebt, LeaderboardEncoding, LeaderboardLongFuncs, LeaderboardSpellCheck},
+		})
+		if err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		return report
+	}
+	has := func(report *Report, path string) map[string]bool {
+		found := make(map[string]bool)
+		for _, entry := range report.LinesOfCode {
+			found["loc"] = found["loc"] || entry.Path == path
+		}
+		for _, entry := range report.TechnicalDebt {
+			found["debt"] = found["debt"] || entry.Path == path
+		}
+		for _, entry := range report.Encoding {
+			found["encoding"] = found["encoding"] || entry.Path == path
+		}
+		for _, entry := range report.LongFunctions {
+			found["long-functions"] = found["long-functions"] || entry.Path == path
+		}
+		for _, entry := range report.SpellCheck {
+			found["spellcheck"] = found["spellcheck"] || entry.Path == path
+		}
+		return found
+	}
+
+	report := run()
+	for lb, found := range has(report, "big.js") {
+		if found {
+			t.Errorf("Expected big.js to be left out of %s", lb)
+		}
+	}
+	if report.Repo.AnalyzedFiles != 2 {
+		t.Errorf("Expected 2 analyzed files, but got %+v", report.Repo)
+	}
+	expected := map[string]int{"loc": 1, "debt": 1, "encoding": 1, "long-functions": 1, "spellcheck": 1}
+	if !reflect.DeepEqual(report.Repo.OversizedFiles, expected) {
+		t.Errorf("Expected oversized files %v, but got %v", expected, report.Repo.OversizedFiles)
+	}
+
+	// The spell check has a limit of its own
+	cfg.SpellCheckMaxFileSize = 10
+	report = run()
+	if found := has(report, "big.js"); !found["spellcheck"] || found["loc"] || found["debt"] {
+		t.Errorf("Expected big.js to be spell checked only, but found it in %v", found)
+	}
+	if report.Repo.OversizedFiles["spellcheck"] != 0 {
+		t.Errorf("Expected the spell check to skip nothing, but got %v", report.Repo.OversizedFiles)
+	}
+}
+
+func TestRunRequireCoverage(t *testing.T) {
+	repo := newFixtureRepo(t)
+	opts := Options{RepoPath: repo.Dir(), Leaderboards: []Leaderboard{LeaderboardLinesOfCode}}
+
+	// Without a coverage file the coverage leaderboard is only empty
+	report, err := Run(context.Background(), opts)
+	if err != nil || report.Errors[LeaderboardCoverage] != nil || report.Coverage != nil {
+		t.Fatalf("Expected no coverage and no error, but got %v, %v", report, err)
+	}
+
+	// Required, it runs and fails, whether the file is detected or named
+	opts.RequireCoverage = true
+	for _, file := range []string{"", "coverage/lcov.info"} {
+		opts.CoverageFile = file
+		report, err = Run(context.Background(), opts)
+		if err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		var missing *ErrCoverageMissing
+		if !errors.As(report.Errors[LeaderboardCoverage], &missing) || missing.Path != file {
+			t.Errorf("Expected ErrCoverageMissing for %q, but got %v", file, report.Errors[LeaderboardCoverage])
+		}
+	}
+
+	repo.Write(map[string]string{"coverage/lcov.info": "SF:main.js\nLF:2\nLH:1\nend_of_record\n"})
+	report, err = Run(context.Background(), opts)
+	if err != nil || report.Errors[LeaderboardCoverage] != nil || len(report.Coverage) != 1 {
+		t.Errorf("Expected the coverage of main.js, but got %+v, %v, %v", report.Coverage, report.Errors[LeaderboardCoverage], err)
+	}
+}
+
+func TestRunUncommittedChanges(t *testing.T) {
+	repo := newFixtureRepo(t).
+		Write(map[string]string{"main.js": "// TODO: remove this\nconsole.log('hello');\n// TODO: and this\n", "draft.js": "var x = 1;\n"})
+	plugin := lint.NewPlugin(filepath.Join("..", "..", "examples", "plugins", "codecompass-lint-todo"))
+
+	_, err := Run(context.Background(), Options{
+		RepoPath:     repo.Dir(),
+		Leaderboards: []Leaderboard{LeaderboardAuthors},
+		Sources:      []LintSource{plugin},
+		RequireClean: true,
+	})
+	var dirty *ErrDirtyWorkTree
+	if !errors.As(err, &dirty) || !reflect.DeepEqual(dirty.Files, []string{"draft.js", "main.js"}) {
+		t.Fatalf("Expected ErrDirtyWorkTree for draft.js and main.js, but got %v", err)
+	}
+
+	report, err := Run(context.Background(), Options{
+		RepoPath:     repo.Dir(),
+		Leaderboards: []Leaderboard{LeaderboardAuthors},
+		Sources:      []LintSource{plugin},
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	// The untracked file is not blamed, so only main.js counts
+	if report.Repo.DirtyFiles != 1 {
+		t.Errorf("Expected 1 dirty file, but got %+v", report.Repo)
+	}
+	counts := make(map[string]int)
+	for _, entry := range report.Authors {
+		counts[entry.Email] = entry.Count
+	}
+	if expected := map[string]int{"alice@example.com": 1, "uncommitted": 1}; !reflect.DeepEqual(counts, expected) {
+		t.Errorf("Expected the new TODO to be blamed on uncommitted, but got %v", counts)
+	}
+}
+
+// fakeForge returns fixed pull requests and records the window it was asked
+// for.
+type fakeForge struct {
+	since time.Time
+}
+
+func (f *fakeForge) Name() string { return "fake" }
+func (f *fakeForge) Repo() string { return "owner/repo" }
+func (f *fakeForge) MergedPullRequests(ctx context.Context, since time.Time) ([]forge.PullRequest, error) {
+	f.since = since
+	return []forge.PullRequest{
+		{Number: 1, Author: "alice", Additions: 10, CreatedAt: since, MergedAt: since.Add(time.Hour), Reviews: []forge.Review{{Reviewer: "bob", State: forge.ReviewApproved}}},
+	}, nil
+}
+
+func TestRunForgeStats(t *testing.T) {
+	dir := newFixtureRepo(t).Dir()
+	since := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	// Not requested, so the forge is never asked
+	unused := &fakeForge{}
+	if _, err := Run(context.Background(), Options{
+		RepoPath:     dir,
+		Leaderboards: []Leaderboard{LeaderboardLinesOfCode},
+		Forge:        unused,
+	}); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if !unused.since.IsZero() {
+		t.Errorf("Expected the forge not to be queried unless requested")
+	}
+
+	f := &fakeForge{}
+	report, err := Run(context.Background(), Options{
+		RepoPath:     dir,
+		Leaderboards: []Leaderboard{LeaderboardGitHub},
+		Forge:        f,
+		Since:        since,
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if !f.since.Equal(since) {
+		t.Errorf("Expected the forge to be asked for PRs since %v, but got %v", since, f.since)
+	}
+	if report.Forge == nil || len(report.Forge.Authors) != 1 || len(report.Forge.Reviewers) != 1 {
+		t.Fatalf("Expected forge statistics, but got %+v", report.Forge)
+	}
+	if report.Forge.Authors[0].Login != "alice" || report.Forge.Reviewers[0].Login != "bob" {
+		t.Errorf("Unexpected forge statistics: %+v", report.Forge)
+	}
+}
+
+func TestRunForgeWithoutRemote(t *testing.T) {
+	dir := newFixtureRepo(t).Dir()
+
+	report, err := Run(context.Background(), Options{
+		RepoPath:     dir,
+		Leaderboards: []Leaderboard{LeaderboardGitHub},
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if report.Errors[LeaderboardGitHub] == nil || report.Forge != nil {
+		t.Errorf("Expected the GitHub leaderboard to fail without an origin remote, but got %v", report.Errors)
+	}
+}
+
+func TestRunLeadTime(t *testing.T) {
+	repo := newFixtureRepo(t)
+	repo.Branch("feature").
+		WithAuthor("Bob", "bob@example.com").
+		Commit("feature work", map[string]string{"feature.js": "const feature = 1;\n"}).
+		Checkout("main").
+		Merge("feature", "merge feature")
+
+	report, err := Run(context.Background(), Options{
+		RepoPath:     repo.Dir(),
+		Leaderboards: []Leaderboard{LeaderboardLeadTime},
+		Since:        testutil.StartDate,
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if report.LeadTime == nil || report.LeadTime.Merges != 1 || len(report.LeadTime.Authors) != 1 {
+		t.Fatalf("Expected one merged branch, but got %+v", report.LeadTime)
+	}
+	if report.LeadTime.Authors[0].Name != "Bob" || report.LeadTime.Median != time.Hour {
+		t.Errorf("Expected Bob's branch to take an hour to merge, but got %+v", report.LeadTime)
+	}
+}
+
+func TestRunChangelogReadiness(t *testing.T) {
+	repo := newFixtureRepo(t)
+	repo.Branch("feature").
+		WithAuthor("Bob", "bob@example.com").
+		Commit("feat(util)!: add strings\n\nBREAKING CHANGE: add concatenates strings", map[string]string{"feature.js": "const feature = 1;\n"}).
+		Checkout("main").
+		Merge("feature", "merge feature")
+
+	report, err := Run(context.Background(), Options{
+		RepoPath:     repo.Dir(),
+		Leaderboards: []Leaderboard{LeaderboardChangelog},
+		Since:        testutil.StartDate,
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	// The merge is left out, and the fixture's messages are not conventional
+	changelog := report.Changelog
+	if changelog == nil || changelog.Commits != 3 || changelog.Types["feat"] != 1 || changelog.Types["other"] != 2 {
+		t.Fatalf("Expected one feat and two other commits, but got %+v", changelog)
+	}
+	if len(changelog.Breaking) != 1 || changelog.Breaking[0].Name != "Bob" || changelog.Breaking[0].Note != "add concatenates strings" {
+		t.Errorf("Expected Bob's breaking change, but got %+v", changelog.Breaking)
+	}
+}
+
+func TestCompareRefs(t *testing.T) {
+	repo := newFixtureRepo(t)
+	repo.Branch("feature").
+		Commit("add more debt", map[string]string{"feature.js": "// TODO: one\n// HACK: two\nconst feature = 1;\n"}).
+		Checkout("main")
+	// Uncommitted changes are not part of either ref
+	repo.Write(map[string]string{"main.js": "console.log('changed');\n"})
+
+	compare := func(refA, refB string) []MetricDelta {
+		t.Helper()
+		comparison, err := CompareRefs(context.Background(), refA, refB, Options{RepoPath: repo.Dir()})
+		if err != nil {
+			t.Fatalf("CompareRefs failed: %v", err)
+		}
+		return comparison.Deltas
+	}
+
+	// Without a coverage report, coverage cannot be compared
+	expected := []MetricDelta{
+		{Metric: "coverage", HigherIsBetter: true, Missing: true},
+		{Metric: "issues"},
+		{Metric: "debt", A: 2, B: 2},
+		{Metric: "loc", A: 8, B: 8},
+	}
+	if deltas := compare("main", "main"); !reflect.DeepEqual(deltas, expected) {
+		t.Errorf("Expected no change comparing main to itself, got %+v", deltas)
+	}
+
+	deltas := compare("main", "feature")
+	if deltas[2].Delta != 2 || deltas[3].Delta != 3 {
+		t.Errorf("Expected the feature branch to add 2 debt and 3 lines, got %+v", deltas)
+	}
+
+	if _, err := CompareRefs(context.Background(), "main", "no-such-branch", Options{RepoPath: repo.Dir()}); err == nil {
+		t.Errorf("Expected an error for an unknown ref")
+	}
+
+	// The temporary worktrees are removed
+	if worktrees := repo.Git("worktree", "list"); strings.Count(worktrees, "\n") != 0 {
+		t.Errorf("Expected only the main worktree to remain, got:\n%s", worktrees)
+	}
+}
+
+func TestRunMulti(t *testing.T) {
+	api := newFixtureRepo(t).Dir()
+	// The web repository shares an author with api under another case
+	web := testutil.NewRepo(t).
+		WithAuthor("Alice", "Alice@Example.com").
+		Commit("initial commit", map[string]string{"app.js": "// TODO: one\n// TODO: two\nconst app = 1;\n"}).
+		Dir()
+	missing := filepath.Join(t.TempDir(), "missing")
+
+	var progress []int
+	repos := []string{api, "file://" + web, missing}
+	report, err := RunMulti(context.Background(), repos, 2, Options{
+		Leaderboards: []Leaderboard{LeaderboardCommits},
+		Sources:      []LintSource{lint.NewPlugin(filepath.Join("..", "..", "examples", "plugins", "codecompass-lint-todo"))},
+		Progress: func(phase string, done, total int) {
+			if phase != "repos" || total != 3 {
+				t.Errorf("Unexpected progress %s %d/%d", phase, done, total)
+			}
+			progress = append(progress, done)
+		},
+	})
+	if err != nil {
+		t.Fatalf("RunMulti failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(progress, []int{0, 1, 2, 3}) {
+		t.Errorf("Expected progress for each repository, got %v", progress)
+	}
+
+	// The missing repository is a failure of its own
+	if len(report.RepoErrors) != 1 || report.RepoErrors[missing] == nil || report.RepoFailures[missing] == "" {
+		t.Errorf("Expected only %s to fail, got %v", missing, report.RepoErrors)
+	}
+
+	if len(report.Repos) != 2 || report.Repos[0].Repo != api || report.Repos[1].Repo != "file://"+web {
+		t.Fatalf("Expected entries for api and web in order, got %+v", report.Repos)
+	}
+	if entry := report.Repos[1]; entry.Files != 1 || entry.LinesOfCode != 3 || entry.Issues != 2 || entry.Debt != 2 || entry.IssuesPerKLOC != 2000.0/3 {
+		t.Errorf("Unexpected web entry %+v", entry)
+	}
+
+	// Requested leaderboards are computed for each repository
+	if len(report.RepoReports) != 2 || len(report.RepoReports[0].Commits) == 0 || report.RepoReports[1].Repo.Path != "file://"+web {
+		t.Errorf("Expected the report of each repository, got %+v", report.RepoReports)
+	}
+
+	if len(report.Authors) != 1 || report.Authors[0].Count != 3 || !reflect.DeepEqual(report.Authors[0].Repos, []string{api, "file://" + web}) {
+		t.Errorf("Expected Alice's issues merged across repositories, got %+v", report.Authors)
+	}
+	if err := report.Validate(); err != nil {
+		t.Errorf("Expected a valid report, got %v", err)
+	}
+
+	if _, err := RunMulti(context.Background(), nil, 2, Options{}); err == nil {
+		t.Errorf("Expected an error without repositories")
+	}
+}
+
+func TestRunSkipsTrackedSymlinks(t *testing.T) {
+	repo := newFixtureRepo(t)
+	dir := repo.Dir()
+
+	outside := filepath.Join(t.TempDir(), "outside.js")
+	if err := os.WriteFile(outside, []byte("// TODO: outside the repository\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, target := range map[string]string{
+		"outside-link.js": outside,
+		"broken-link.js":  "missing.js",
+		"inside-link.js":  "main.js",
+	} {
+		if err := os.Symlink(target, filepath.Join(dir, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	repo.WithAuthor("Alice", "alice@example.com").Commit("add links", nil)
+
+	report, err := Run(context.Background(), Options{
+		RepoPath:     dir,
+		Leaderboards: []Leaderboard{LeaderboardLinesOfCode, LeaderboardDebt},
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if report.Repo.TrackedFiles != 5 || report.Repo.AnalyzedFiles != 2 {
+		t.Errorf("Expected 5 tracked and 2 analyzed files, but got %d and %d", report.Repo.TrackedFiles, report.Repo.AnalyzedFiles)
+	}
+
+	for _, entry := range report.LinesOfCode {
+		if strings.HasSuffix(entry.Path, "-link.js") {
+			t.Errorf("Expected symlink %s to be skipped", entry.Path)
+		}
+	}
+
+	if len(report.TechnicalDebt) != 2 {
+		t.Errorf("Expected debt only from the 2 regular files, but got %+v", report.TechnicalDebt)
+	}
+
+	if len(report.Warnings) != 3 {
+		t.Errorf("Expected a warning per skipped symlink, but got %v", report.Warnings)
+	}
+}
+
+func TestRunDefaultSourcesSkipPlugins(t *testing.T) {
+	// A plugin on PATH must not run unless the caller lists it
+	bin := t.TempDir()
+	marker := filepath.Join(t.TempDir(), "ran")
+	script := "#!/bin/sh\ntouch " + marker + "\nexit 0\n"
+	if err := os.WriteFile(filepath.Join(bin, "codecompass-lint-marker"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	report, err := Run(context.Background(), Options{
+		RepoPath:     newFixtureRepo(t).Dir(),
+		Leaderboards: []Leaderboard{LeaderboardAuthors},
+		DisableRuff:  true,
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if _, err := os.Stat(marker); err == nil {
+		t.Errorf("Expected the plugin on PATH not to run")
+	}
+
+	// The fixture has no package.json, so ESLint is skipped with a warning
+	found := false
+	for _, warning := range report.Warnings {
+		if strings.Contains(warning.Message, "Skipped ESLint") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected a warning that ESLint was skipped, but got %v", report.Warnings)
+	}
+}
+
+func TestRunReportCardGradesSpelling(t *testing.T) {
+	report, err := Run(context.Background(), Options{
+		RepoPath:     newFixtureRepo(t).Dir(),
+		Leaderboards: []Leaderboard{LeaderboardReportCard},
+		Sources:      []LintSource{},
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	graded := make(map[string]bool)
+	for _, category := range report.ReportCard.Categories {
+		graded[category.Category] = true
+	}
+	if !graded["spelling"] || !graded["bus-factor"] || !graded["debt"] {
+		t.Errorf("Expected spelling, bus factor and debt to be graded, but got %+v", report.ReportCard.Categories)
+	}
+}
+
+func TestRunReportCardSkipsIssuesWithoutLinting(t *testing.T) {
+	// Ruff has no Python files to lint in the fixture repository
+	report, err := Run(context.Background(), Options{
+		RepoPath:     newFixtureRepo(t).Dir(),
+		Leaderboards: []Leaderboard{LeaderboardReportCard},
+		Sources:      []LintSource{ruff.Source{}},
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	for _, category := range report.ReportCard.Categories {
+		if category.Category == "issues" {
+			t.Errorf("Expected issues not to be graded, but got %+v", category)
+		}
+	}
+	if len(report.LintSources) != 0 {
+		t.Errorf("Expected no lint source to run, but got %+v", report.LintSources)
+	}
+}
+
+func TestRunScore(t *testing.T) {
+	plugin := lint.NewPlugin(filepath.Join("..", "..", "examples", "plugins", "codecompass-lint-todo"))
+	report, err := Run(context.Background(), Options{
+		RepoPath:     newFixtureRepo(t).Dir(),
+		Leaderboards: []Leaderboard{LeaderboardScore},
+		Sources:      []LintSource{plugin},
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if report.Score == nil {
+		t.Fatal("Expected a quality score")
+	}
+
+	var components []string
+	for _, component := range report.Score.Components {
+		components = append(components, component.Component)
+	}
+	// There is no coverage report, so coverage is left out
+	if expected := []string{"issues", "debt", "churn", "complexity"}; !reflect.DeepEqual(components, expected) {
+		t.Errorf("Expected components %v, but got %+v", expected, report.Score.Components)
+	}
+
+	// The TODO in main.js is both an issue and a debt marker
+	if len(report.Score.Files) != 2 || report.Score.Files[0].Path != "main.js" || report.Score.Files[0].Score >= report.Score.Files[1].Score {
+		t.Errorf("Expected main.js to score worst, but got %+v", report.Score.Files)
+	}
+	if report.Score.Score <= 0 || report.Score.Score >= 100 {
+		t.Errorf("Expected a repository score between 0 and 100, but got %v", report.Score.Score)
+	}
+}
+
+func TestRunScoreSkipsIssuesWithoutLinting(t *testing.T) {
+	// Ruff has no Python files to lint in the fixture repository
+	report, err := Run(context.Background(), Options{
+		RepoPath:     newFixtureRepo(t).Dir(),
+		Leaderboards: []Leaderboard{LeaderboardScore},
+		Sources:      []LintSource{ruff.Source{}},
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if report.Score == nil {
+		t.Fatal("Expected a quality score")
+	}
+
+	for _, component := range report.Score.Components {
+		if component.Component == "issues" {
+			t.Errorf("Expected issues not to be scored, but got %+v", report.Score.Components)
+		}
+	}
+}
+
+func TestRunResumesFromState(t *testing.T) {
+	repo := newFixtureRepo(t)
+	path := filepath.Join(t.TempDir(), "state.json")
+	leaderboards := []Leaderboard{LeaderboardLinesOfCode, LeaderboardCommits}
+
+	run := func() *Report {
+		t.Helper()
+		state, err := LoadRunState(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		report, err := Run(context.Background(), Options{RepoPath: repo.Dir(), Leaderboards: leaderboards, State: state})
+		if err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		return report
+	}
+
+	first := run()
+	if len(first.Reused) != 0 || len(first.LinesOfCode) != 2 {
+		t.Fatalf("Expected the first run to compute everything, but reused %v", first.Reused)
+	}
+
+	// Mark the saved lines of code so a reused section can be told apart
+	state, err := LoadRunState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	section := state.Sections[string(LeaderboardLinesOfCode)]
+	if section == nil {
+		t.Fatal("Expected the lines of code to be saved to the state file")
+	}
+	saved := []LinesOfCodeEntry{{Rank: 1, Path: "from-state.js", Lines: 1}}
+	if err := state.Complete(string(LeaderboardLinesOfCode), section.InputHash, saved); err != nil {
+		t.Fatal(err)
+	}
+
+	second := run()
+	if len(second.Reused) != 2 {
+		t.Errorf("Expected both leaderboards to be reused, but got %v", second.Reused)
+	}
+	if len(second.LinesOfCode) != 1 || second.LinesOfCode[0].Path != "from-state.js" {
+		t.Errorf("Expected the lines of code from the state file, but got %+v", second.LinesOfCode)
+	}
+	if len(second.Commits) != 2 {
+		t.Errorf("Expected the reused commit counts, but got %+v", second.Commits)
+	}
+
+	// A new commit changes the inputs, so everything is computed again
+	repo.Commit("add readme", map[string]string{"README.md": "hello\n"})
+	third := run()
+	if len(third.Reused) != 0 || len(third.LinesOfCode) != 3 {
+		t.Errorf("Expected a new commit to invalidate the state, but reused %v with %+v", third.Reused, third.LinesOfCode)
+	}
+}
+
+func TestRunRepositoryWithoutCommits(t *testing.T) {
+	dir := testutil.NewRepo(t).
+		Write(map[string]string{"main.js": "// TODO: write the app\nconsole.log('hello');\n"}).
+		Dir()
+
+	report, err := Run(context.Background(), Options{
+		RepoPath:     dir,
+		Leaderboards: []Leaderboard{LeaderboardLinesOfCode, LeaderboardDebt, LeaderboardCommits, LeaderboardChurn},
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if !report.Repo.NoCommits || report.Repo.AnalyzedFiles != 1 {
+		t.Errorf("Expected the uncommitted work tree to be analyzed, but got %+v", report.Repo)
+	}
+
+	// File-based leaderboards read the work tree
+	if len(report.LinesOfCode) != 1 || len(report.TechnicalDebt) != 1 {
+		t.Errorf("Expected lines of code and debt for main.js, but got %+v and %+v", report.LinesOfCode, report.TechnicalDebt)
+	}
+
+	for _, lb := range []Leaderboard{LeaderboardCommits, LeaderboardChurn} {
+		if !errors.Is(report.Errors[lb], ErrNoCommits) {
+			t.Errorf("Expected %s to be skipped for lack of commits, but got %v", lb, report.Errors[lb])
+		}
+	}
+	if report.Errors[LeaderboardLinesOfCode] != nil || report.Errors[LeaderboardDebt] != nil {
+		t.Errorf("Expected the file-based leaderboards to succeed, but got %v", report.Errors)
+	}
+}
+
+func TestRunByWorkspace(t *testing.T) {
+	dir := testutil.NewRepo(t).
+		Commit("Add workspace", map[string]string{
+			"go.work":            "go 1.22\n\nuse (\n\t./api\n\t./cli\n)\n",
+			"api/go.mod":         "module example.com/api\n",
+			"api/api.go":         "package api\n\n// TODO: paginate\nfunc List() {}\n",
+			"cli/go.mod":         "module example.com/cli\n",
+			"cli/main.go":        "package main\n\nfunc main() {}\n",
+			"scripts/release.sh": "echo release\n",
+		}).
+		Dir()
+
+	report, err := Run(context.Background(), Options{
+		RepoPath:     dir,
+		Leaderboards: []Leaderboard{LeaderboardWorkspaces},
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if err := report.Errors[LeaderboardWorkspaces]; err != nil {
+		t.Fatalf("Expected the workspace roll-up to succeed, but got %v", err)
+	}
+
+	byPath := make(map[string]WorkspaceEntry)
+	for _, entry := range report.Workspaces {
+		byPath[entry.Path] = entry
+	}
+	if api := byPath["api"]; api.Name != "example.com/api" || api.Files != 2 || api.Debt != 1 || api.LinesOfCode == 0 {
+		t.Errorf("Expected api to roll up its two files and TODO, but got %+v", api)
+	}
+	if cli := byPath["cli"]; cli.Name != "example.com/cli" || cli.Files != 2 || cli.Debt != 0 {
+		t.Errorf("Expected cli to roll up its two files, but got %+v", cli)
+	}
+	if root := byPath["."]; root.Files != 2 {
+		t.Errorf("Expected go.work and scripts/release.sh outside every package, but got %+v", root)
+	}
+
+	// The leaderboards the roll-up is built from are not reported on their own
+	if report.Requested(string(LeaderboardLinesOfCode)) {
+		t.Errorf("Expected only the roll-up to be requested, but got %v", report.Leaderboards)
+	}
+}
+
+func TestRunIncludeUntracked(t *testing.T) {
+	dir := testutil.NewRepo(t).
+		Commit("initial commit", map[string]string{"main.js": "console.log('hello');\n", ".gitignore": "dist/\n"}).
+		Write(map[string]string{"draft.js": "// TODO: finish the draft\n", "dist/bundle.js": "// TODO: generated\n"}).
+		Dir()
+
+	run := func(includeUntracked bool) *Report {
+		report, err := Run(context.Background(), Options{
+			RepoPath:         dir,
+			Leaderboards:     []Leaderboard{LeaderboardLinesOfCode, LeaderboardDebt, LeaderboardChurn},
+			IncludeUntracked: includeUntracked,
+		})
+		if err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		return report
+	}
+
+	report := run(false)
+	if len(report.LinesOfCode) != 2 || len(report.TechnicalDebt) != 0 {
+		t.Errorf("Expected only tracked files by default, but got %+v and %+v", report.LinesOfCode, report.TechnicalDebt)
+	}
+
+	report = run(true)
+	if report.Repo.UntrackedFiles != 1 || report.Repo.AnalyzedFiles != 2 {
+		t.Errorf("Expected draft.js to be added without counting as analyzed, but got %+v", report.Repo)
+	}
+	untracked := make(map[string]bool)
+	for _, entry := range report.LinesOfCode {
+		untracked[entry.Path] = entry.Untracked
+	}
+	if len(untracked) != 3 || !untracked["draft.js"] || untracked["main.js"] {
+		t.Errorf("Expected draft.js to be marked untracked among the lines of code, but got %+v", report.LinesOfCode)
+	}
+	if len(report.TechnicalDebt) != 1 || report.TechnicalDebt[0].Path != "draft.js" || !report.TechnicalDebt[0].Untracked {
+		t.Errorf("Expected the TODO in draft.js, but got %+v", report.TechnicalDebt)
+	}
+
+	// Leaderboards built from history never see the untracked file
+	for _, entry := range report.Churn {
+		if entry.Path == "draft.js" {
+			t.Errorf("Expected no churn for an untracked file, but got %+v", entry)
+		}
+	}
+}
+
+func TestRunExcludesVendoredFiles(t *testing.T) {
+	dir := testutil.NewRepo(t).
+		Commit("initial commit", map[string]string{
+			"main.js":            "// TODO: ship it\nconsole.log('hello');\n",
+			"vendor/lib.js":      "// TODO: upstream bug\nvar lib = {};\n",
+			"static/app.min.js":  "var a=1;\n",
+			"api/client.pb.go":   "package api\n",
+			"assets/ours.min.js": "// TODO: ours\n",
+			".gitattributes":     "assets/ours.min.js -linguist-vendored\n",
+		}).
+		Dir()
+
+	run := func(includeVendored bool) *Report {
+		report, err := Run(context.Background(), Options{
+			RepoPath:        dir,
+			Leaderboards:    []Leaderboard{LeaderboardLinesOfCode, LeaderboardDebt},
+			IncludeVendored: includeVendored,
+		})
+		if err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		return report
+	}
+
+	report := run(false)
+	if report.Repo.VendoredFiles != 2 || report.Repo.GeneratedFiles != 1 {
+		t.Errorf("Expected 2 vendored and 1 generated file, but got %+v", report.Repo)
+	}
+	var paths []string
+	for _, entry := range report.LinesOfCode {
+		paths = append(paths, entry.Path)
+	}
+	sort.Strings(paths)
+	if strings.Join(paths, ",") != ".gitattributes,assets/ours.min.js,main.js" {
+		t.Errorf("Expected only first-party files in the lines of code, but got %v", paths)
+	}
+	if len(report.TechnicalDebt) != 2 {
+		t.Errorf("Expected the TODOs outside vendor/, but got %+v", report.TechnicalDebt)
+	}
+
+	// The lines of code leaderboard always skips vendor/ itself
+	report = run(true)
+	if len(report.LinesOfCode) != 5 || report.Repo.VendoredFiles != 0 {
+		t.Errorf("Expected --include-vendored to keep every file, but got %+v with %+v", report.LinesOfCode, report.Repo)
+	}
+}
+
+func TestRunRestrictsLanguages(t *testing.T) {
+	dir := testutil.NewRepo(t).
+		Commit("initial commit", map[string]string{
+			"web/app.ts":   "// TODO: types\nexport const a = 1;\nexport const b = 2;\n",
+			"tools/run.py": "# TODO: flags\nprint('run')\n",
+			"bin/deploy":   "#!/usr/bin/env python3\nprint('deploy')\n",
+			"notes.txt":    "notes\n",
+		}).
+		Dir()
+
+	report, err := Run(context.Background(), Options{
+		RepoPath:     dir,
+		Leaderboards: []Leaderboard{LeaderboardLinesOfCode, LeaderboardDebt},
+		Languages:    []string{"Python"},
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	var paths []string
+	for _, entry := range report.LinesOfCode {
+		paths = append(paths, entry.Path)
+	}
+	sort.Strings(paths)
+	if strings.Join(paths, ",") != "bin/deploy,tools/run.py" {
+		t.Errorf("Expected only the Python files in the lines of code, but got %v", paths)
+	}
+	if len(report.TechnicalDebt) != 1 || report.TechnicalDebt[0].Path != "tools/run.py" {
+		t.Errorf("Expected only the Python TODO, but got %+v", report.TechnicalDebt)
+	}
+	if report.Repo.AnalyzedFiles != 2 {
+		t.Errorf("Expected 2 analyzed files, but got %d", report.Repo.AnalyzedFiles)
+	}
+
+	// The composition still counts the files left out
+	expected := []types.LanguageEntry{
+		{Name: "Python", Files: 2, Lines: 4, Percent: 50},
+		{Name: "TypeScript", Files: 1, Lines: 3, Percent: 37.5},
+		{Name: "Other", Files: 1, Lines: 1, Percent: 12.5},
+	}
+	if !reflect.DeepEqual(report.Repo.Languages, expected) {
+		t.Errorf("Expected languages %+v, but got %+v", expected, report.Repo.Languages)
+	}
+}
+
+func TestRunRestrictsExtensions(t *testing.T) {
+	dir := testutil.NewRepo(t).
+		Commit("initial commit", map[string]string{
+			"web/app.ts":    "// TODO: types\nexport const a = 1;\n",
+			"web/App.TSX":   "// TODO: props\n",
+			"web/legacy.js": "// TODO: port\n",
+			"tsconfig.json": "{}\n",
+			"Makefile":      "all:\n",
+		}).
+		Dir()
+
+	run := func(only, exclude []string) []string {
+		report, err := Run(context.Background(), Options{
+			RepoPath:          dir,
+			Leaderboards:      []Leaderboard{LeaderboardLinesOfCode},
+			OnlyExtensions:    only,
+			ExcludeExtensions: exclude,
+		})
+		if err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		var paths []string
+		for _, entry := range report.LinesOfCode {
+			paths = append(paths, entry.Path)
+		}
+		sort.Strings(paths)
+		return paths
+	}
+
+	if paths := strings.Join(run([]string{".ts", ".tsx"}, nil), ","); paths != "web/App.TSX,web/app.ts" {
+		t.Errorf("Expected only the TypeScript files, but got %v", paths)
+	}
+	if paths := strings.Join(run(nil, []string{".json", ".js"}), ","); paths != "Makefile,web/App.TSX,web/app.ts" {
+		t.Errorf("Expected every file but the JSON and JavaScript ones, but got %v", paths)
+	}
+	// Exclude wins over only
+	if paths := strings.Join(run([]string{".ts", ".tsx"}, []string{".tsx"}), ","); paths != "web/app.ts" {
+		t.Errorf("Expected the .tsx file to be excluded, but got %v", paths)
+	}
+}
+
+func TestRunChangedSince(t *testing.T) {
+	repo := testutil.NewRepo(t).
+		WithAuthor("Alice", "alice@example.com").
+		Commit("initial commit", map[string]string{
+			"old.js":  "// TODO: old\n",
+			"keep.js": "// TODO: keep\n",
+		})
+	repo.Git("tag", "v1.0.0")
+	repo.WithAuthor("Bob", "bob@example.com").
+		Commit("feat: new", map[string]string{"new.js": "// TODO: new\n"}).
+		Commit("fix: keep", map[string]string{"keep.js": "// TODO: keep\nconst a = 1;\n"})
+
+	tag, err := LatestTag(context.Background(), repo.Dir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := Run(context.Background(), Options{
+		RepoPath:     repo.Dir(),
+		Leaderboards: []Leaderboard{LeaderboardDebt, LeaderboardCommits},
+		ChangedSince: tag,
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	var paths []string
+	for _, entry := range report.TechnicalDebt {
+		paths = append(paths, entry.Path)
+	}
+	sort.Strings(paths)
+	if strings.Join(paths, ",") != "keep.js,new.js" {
+		t.Errorf("Expected only the files changed since the tag, but got %v", paths)
+	}
+	if report.Repo.ChangedSince != "v1.0.0" || report.Repo.ChangedFiles != 2 {
+		t.Errorf("Expected 2 files changed since v1.0.0, but got %q and %d", report.Repo.ChangedSince, report.Repo.ChangedFiles)
+	}
+
+	// Only Bob committed since the tag
+	if len(report.Commits) != 1 || report.Commits[0].Name != "Bob" || report.Commits[0].Commits != 2 {
+		t.Errorf("Expected Bob's 2 commits, but got %+v", report.Commits)
+	}
+}
+
+func TestRunLeaderboardIgnorePaths(t *testing.T) {
+	repo := newFixtureRepo(t).
+		Commit("add tests", map[string]string{
+			"main.test.js": "// TODO: cover the error path\ntest('hello', () => {});\n",
+		})
+
+	cfg := NewConfig()
+	cfg.DebtIgnorePaths = []string{"@tests"}
+	report, err := Run(context.Background(), Options{
+		RepoPath:     repo.Dir(),
+		Config:       cfg,
+		Leaderboards: []Leaderboard{LeaderboardLinesOfCode, LeaderboardDebt},
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	// The test still counts in the lines of code, but its TODO is no debt
+	if len(report.LinesOfCode) != 3 {
+		t.Errorf("Expected the lines of code of the 3 files, but got %+v", report.LinesOfCode)
+	}
+	for _, entry := range report.TechnicalDebt {
+		if entry.Path == "main.test.js" {
+			t.Errorf("Expected main.test.js left out of the debt, but got %+v", report.TechnicalDebt)
+		}
+	}
+	if len(report.TechnicalDebt) != 2 {
+		t.Errorf("Expected the debt of main.js and lib/util.js, but got %+v", report.TechnicalDebt)
+	}
+	expected := map[string]int{"debt": 1}
+	if !reflect.DeepEqual(report.Repo.ExcludedFiles, expected) {
+		t.Errorf("Expected %v excluded, but got %v", expected, report.Repo.ExcludedFiles)
+	}
+}
+
+func TestRunGracePeriod(t *testing.T) {
+	joined := testutil.StartDate.AddDate(0, 0, 60)
+	repo := newFixtureRepo(t).
+		WithAuthor("Carol", "carol@example.com").
+		At(joined).
+		Commit("first contribution", map[string]string{"new.js": "// TODO: validate input\n"}).
+		At(joined.AddDate(0, 0, 60)).
+		Commit("second contribution", map[string]string{"later.js": "// TODO: cache this\n"})
+
+	cfg := NewConfig()
+	cfg.GracePeriodDays = 30
+	report, err := Run(context.Background(), Options{
+		RepoPath:     repo.Dir(),
+		Config:       cfg,
+		Leaderboards: []Leaderboard{LeaderboardAuthors, LeaderboardNewContrib, LeaderboardSummary},
+		Sources:      []LintSource{lint.NewPlugin(filepath.Join("..", "..", "examples", "plugins", "codecompass-lint-todo"))},
+		Since:        testutil.StartDate.AddDate(0, 0, 30),
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	// Carol's first TODO is in her grace period, her second is not
+	counts := make(map[string]int)
+	for _, entry := range report.Authors {
+		counts[entry.Email] = entry.Count
+	}
+	expected := map[string]int{"alice@example.com": 1, "carol@example.com": 1}
+	if !reflect.DeepEqual(counts, expected) {
+		t.Errorf("Expected %v on the author leaderboard, but got %v", expected, counts)
+	}
+	if report.NewContributorIssues != 1 {
+		t.Errorf("Expected 1 new contributor issue, but got %d", report.NewContributorIssues)
+	}
+	if report.Summary.TotalIssues != 3 {
+		t.Errorf("Expected the summary to count all 3 issues, but got %d", report.Summary.TotalIssues)
+	}
+
+	// Alice and Bob joined before the window
+	stats := report.NewContributors
+	if stats == nil || stats.GracePeriodDays != 30 || len(stats.Authors) != 1 {
+		t.Fatalf("Expected Carol as the only new contributor, but got %+v", stats)
+	}
+	carol := stats.Authors[0]
+	if carol.Email != "carol@example.com" || carol.Commits != 2 || carol.FilesTouched != 2 || carol.GracedIssues != 1 || !carol.FirstCommit.Equal(joined) {
+		t.Errorf("Expected Carol with 2 commits, 2 files and 1 graced issue, but got %+v", carol)
+	}
+
+	// Without a grace period every issue counts
+	report, err = Run(context.Background(), Options{
+		RepoPath:     repo.Dir(),
+		Leaderboards: []Leaderboard{LeaderboardAuthors},
+		Sources:      []LintSource{lint.NewPlugin(filepath.Join("..", "..", "examples", "plugins", "codecompass-lint-todo"))},
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	for _, entry := range report.Authors {
+		if entry.Email == "carol@example.com" && entry.Count != 2 {
+			t.Errorf("Expected both of Carol's issues counted, but got %+v", entry)
+		}
+	}
+	if report.NewContributorIssues != 0 {
+		t.Errorf("Expected no new contributor issues, but got %d", report.NewContributorIssues)
+	}
+}
@@ -0,0 +1,197 @@
+package compass
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/xeon-zolt/codecompass/internal/vulns"
+)
+
+// Gate is a --fail-on condition on a metric of a report, such as score<70.
+// The run fails when the condition holds.
+type Gate struct {
+	Metric    string
+	Op        string // <, <=, > or >=
+	Threshold float64
+
+	// FromConfig is set for a metric given without a condition, such as
+	// coverage. ResolveGates reads its threshold from the configuration.
+	FromConfig bool
+}
+
+// ErrNotMeasured is returned by Gate.Check when the report lacks the metric
+// of the gate, for example because its leaderboard failed.
+var ErrNotMeasured = errors.New("metric was not measured")
+
+// gateMetric is a metric gates can check, with the leaderboard it is read
+// from.
+type gateMetric struct {
+	leaderboard Leaderboard
+	value       func(r *Report) (float64, bool)
+
+	// threshold, if set, lets the metric be given without a condition,
+	// failing when it is below the threshold from the configuration, or
+	// above it when bareOp is ">".
+	threshold func(cfg *Config) float64
+	bareOp    string
+}
+
+var gateMetrics = func() map[string]gateMetric {
+	metrics := map[string]gateMetric{
+		"score": {leaderboard: LeaderboardScore, value: func(r *Report) (float64, bool) {
+			if r.Score == nil || len(r.Score.Components) == 0 {
+				return 0, false
+			}
+			return r.Score.Score, true
+		}},
+		"coverage": {
+			leaderboard: LeaderboardCoverage,
+			value: func(r *Report) (float64, bool) {
+				measured := r.Errors[LeaderboardCoverage] == nil && r.Requested(string(LeaderboardCoverage)) && len(r.Coverage) > 0
+				return r.OverallCoverage, measured
+			},
+			threshold: func(cfg *Config) float64 { return cfg.MinCoverageThreshold },
+		},
+		// Days the coverage report is older than the code
+		"coverage-age": {
+			leaderboard: LeaderboardCoverage,
+			value: func(r *Report) (float64, bool) {
+				measured := r.Errors[LeaderboardCoverage] == nil && r.Requested(string(LeaderboardCoverage)) && r.CoverageFreshness != nil
+				if !measured {
+					return 0, false
+				}
+				return r.CoverageFreshness.StaleDays, true
+			},
+			threshold: func(cfg *Config) float64 { return float64(cfg.CoverageMaxAge) },
+			bareOp:    ">",
+		},
+		// Issues weighted by severity and rule, measured with --weighted
+		"weighted-issues": {
+			leaderboard: LeaderboardSummary,
+			value: func(r *Report) (float64, bool) {
+				if r.Summary == nil {
+					return 0, false
+				}
+				// Zero unless issues were weighted, or there are none
+				return r.Summary.WeightedIssues, r.Summary.WeightedIssues > 0 || r.Summary.TotalIssues == 0
+			},
+		},
+		// Without a condition, any new issue fails
+		"new-issues": {
+			leaderboard: LeaderboardSummary,
+			value: func(r *Report) (float64, bool) {
+				if r.IssueBaseline == nil {
+					return 0, false
+				}
+				return float64(r.IssueBaseline.New), true
+			},
+			threshold: func(*Config) float64 { return 0 },
+			bareOp:    ">",
+		},
+	}
+	for _, severity := range vulns.Severities {
+		metrics["vulns-"+severity] = gateMetric{leaderboard: LeaderboardVulns, value: func(r *Report) (float64, bool) {
+			return float64(vulns.Counts(r.Vulnerabilities)[severity]), r.Errors[LeaderboardVulns] == nil && r.Requested(string(LeaderboardVulns))
+		}}
+	}
+	return metrics
+}()
+
+// GateMetrics returns the names of the metrics gates can check, sorted.
+func GateMetrics() []string {
+	names := make([]string, 0, len(gateMetrics))
+	for name := range gateMetrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var gateCondition = regexp.MustCompile(`^([a-z-]+)(?:\s*(<=|>=|<|>)\s*(-?[0-9]+(?:\.[0-9]+)?))?$`)
+
+// ParseGates parses a comma-separated list of conditions such as
+// "score<70,vulns-critical>0". Metrics with a threshold in the configuration,
+// such as coverage, can be given without a condition; ResolveGates fills in
+// their thresholds. So can new-issues, which then fails on any new issue.
+func ParseGates(spec string) ([]Gate, error) {
+	var gates []Gate
+	for _, condition := range strings.Split(spec, ",") {
+		condition = strings.TrimSpace(condition)
+		match := gateCondition.FindStringSubmatch(condition)
+		if match == nil {
+			return nil, fmt.Errorf("invalid condition %q: expected METRIC<N, <=, > or >=, such as score<70", condition)
+		}
+		metric, ok := gateMetrics[match[1]]
+		if !ok {
+			return nil, fmt.Errorf("unknown metric %q: expected one of %s", match[1], strings.Join(GateMetrics(), ", "))
+		}
+		if match[2] == "" {
+			if metric.threshold == nil {
+				return nil, fmt.Errorf("invalid condition %q: expected METRIC<N, <=, > or >=, such as score<70", condition)
+			}
+			op := metric.bareOp
+			if op == "" {
+				op = "<"
+			}
+			gates = append(gates, Gate{Metric: match[1], Op: op, FromConfig: true})
+			continue
+		}
+		threshold, err := strconv.ParseFloat(match[3], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid condition %q: %w", condition, err)
+		}
+		gates = append(gates, Gate{Metric: match[1], Op: match[2], Threshold: threshold})
+	}
+	return gates, nil
+}
+
+// ResolveGates sets the thresholds of gates given without a condition from
+// cfg, such as coverage from min-coverage-threshold and coverage-age from
+// coverage-max-age.
+func ResolveGates(gates []Gate, cfg *Config) {
+	for i, gate := range gates {
+		if gate.FromConfig {
+			gates[i].Threshold = gateMetrics[gate.Metric].threshold(cfg)
+		}
+	}
+}
+
+func (g Gate) String() string {
+	return g.Metric + g.Op + strconv.FormatFloat(g.Threshold, 'f', -1, 64)
+}
+
+// Leaderboard returns the leaderboard the metric of g is read from, which
+// must be requested for g to be checked.
+func (g Gate) Leaderboard() Leaderboard {
+	return gateMetrics[g.Metric].leaderboard
+}
+
+// Check returns the value of the metric of g in report and whether the
+// condition holds. It returns ErrNotMeasured when the report lacks the
+// metric, which callers should treat as a failure rather than a pass.
+func (g Gate) Check(report *Report) (float64, bool, error) {
+	metric, ok := gateMetrics[g.Metric]
+	if !ok {
+		return 0, false, fmt.Errorf("unknown metric %q", g.Metric)
+	}
+	value, measured := metric.value(report)
+	if !measured {
+		return 0, false, ErrNotMeasured
+	}
+
+	switch g.Op {
+	case "<":
+		return value, value < g.Threshold, nil
+	case "<=":
+		return value, value <= g.Threshold, nil
+	case ">":
+		return value, value > g.Threshold, nil
+	case ">=":
+		return value, value >= g.Threshold, nil
+	}
+	return value, false, fmt.Errorf("unknown operator %q", g.Op)
+}
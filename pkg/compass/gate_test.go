@@ -0,0 +1,200 @@
+package compass
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+func TestParseGates(t *testing.T) {
+	gates, err := ParseGates("score<70, vulns-critical > 0,score>=99.5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Gate{
+		{Metric: "score", Op: "<", Threshold: 70},
+		{Metric: "vulns-critical", Op: ">", Threshold: 0},
+		{Metric: "score", Op: ">=", Threshold: 99.5},
+	}
+	if !reflect.DeepEqual(gates, expected) {
+		t.Errorf("Expected %+v, but got %+v", expected, gates)
+	}
+	if gates[0].String() != "score<70" || gates[0].Leaderboard() != LeaderboardScore || gates[1].Leaderboard() != LeaderboardVulns {
+		t.Errorf("Unexpected name or leaderboard for %+v", gates)
+	}
+
+	for _, spec := range []string{"", "score", "vulns-high", "score=70", "score<", "loc<10", "score<70,"} {
+		if _, err := ParseGates(spec); err == nil {
+			t.Errorf("Expected an error for %q", spec)
+		}
+	}
+}
+
+func TestGateCheck(t *testing.T) {
+	report := &Report{Errors: map[Leaderboard]error{}}
+	report.Score = &types.ScoreStats{Score: 65, Components: []types.ScoreComponent{{Component: "issues", Score: 65, Weight: 100}}}
+
+	for _, tt := range []struct {
+		gate   Gate
+		failed bool
+	}{
+		{Gate{Metric: "score", Op: "<", Threshold: 70}, true},
+		{Gate{Metric: "score", Op: "<", Threshold: 65}, false},
+		{Gate{Metric: "score", Op: "<=", Threshold: 65}, true},
+		{Gate{Metric: "score", Op: ">", Threshold: 65}, false},
+		{Gate{Metric: "score", Op: ">=", Threshold: 65}, true},
+	} {
+		value, failed, err := tt.gate.Check(report)
+		if err != nil || value != 65 || failed != tt.failed {
+			t.Errorf("%s: expected failed=%v, but got value=%v failed=%v err=%v", tt.gate, tt.failed, value, failed, err)
+		}
+	}
+
+	// Vulnerabilities were not audited, and a score without components was
+	// not measured either
+	if _, _, err := (Gate{Metric: "vulns-critical", Op: ">", Threshold: 0}).Check(report); !errors.Is(err, ErrNotMeasured) {
+		t.Errorf("Expected ErrNotMeasured for vulnerabilities, but got %v", err)
+	}
+	report.Score = &types.ScoreStats{}
+	if _, _, err := (Gate{Metric: "score", Op: "<", Threshold: 70}).Check(report); !errors.Is(err, ErrNotMeasured) {
+		t.Errorf("Expected ErrNotMeasured for an empty score, but got %v", err)
+	}
+
+	report.Leaderboards = []string{string(LeaderboardVulns)}
+	report.Vulnerabilities = []types.VulnEntry{{Package: "lodash", Severity: "critical"}}
+	if value, failed, err := (Gate{Metric: "vulns-critical", Op: ">", Threshold: 0}).Check(report); err != nil || !failed || value != 1 {
+		t.Errorf("Expected one critical vulnerability to fail the gate, but got value=%v failed=%v err=%v", value, failed, err)
+	}
+}
+
+func TestNewIssuesGate(t *testing.T) {
+	gates, err := ParseGates("new-issues,new-issues>5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ResolveGates(gates, NewConfig())
+	if gates[0].String() != "new-issues>0" || gates[1].String() != "new-issues>5" || gates[0].Leaderboard() != LeaderboardSummary {
+		t.Fatalf("Unexpected gates %+v", gates)
+	}
+
+	// Without a baseline to compare with, nothing is new or old
+	report := &Report{Errors: map[Leaderboard]error{}}
+	if _, _, err := gates[0].Check(report); !errors.Is(err, ErrNotMeasured) {
+		t.Errorf("Expected ErrNotMeasured without a baseline, but got %v", err)
+	}
+
+	report.IssueBaseline = &types.IssueBaseline{New: 2, Existing: 40}
+	if value, failed, err := gates[0].Check(report); err != nil || !failed || value != 2 {
+		t.Errorf("Expected new issues to fail the gate, but got value=%v failed=%v err=%v", value, failed, err)
+	}
+	if _, failed, err := gates[1].Check(report); err != nil || failed {
+		t.Errorf("Expected 2 new issues to pass new-issues>5, but got failed=%v err=%v", failed, err)
+	}
+
+	report.IssueBaseline.New = 0
+	if _, failed, err := gates[0].Check(report); err != nil || failed {
+		t.Errorf("Expected only pre-existing issues to pass the gate, but got failed=%v err=%v", failed, err)
+	}
+}
+
+func TestWeightedIssuesGate(t *testing.T) {
+	gates, err := ParseGates("weighted-issues>100")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gates[0].Leaderboard() != LeaderboardSummary {
+		t.Fatalf("Unexpected gates %+v", gates)
+	}
+
+	// Issues counted without weights do not measure it
+	report := &Report{Errors: map[Leaderboard]error{}}
+	report.Summary = &types.SummaryStats{TotalIssues: 40}
+	if _, _, err := gates[0].Check(report); !errors.Is(err, ErrNotMeasured) {
+		t.Errorf("Expected ErrNotMeasured without weights, but got %v", err)
+	}
+
+	report.Summary.WeightedIssues = 200
+	if value, failed, err := gates[0].Check(report); err != nil || !failed || value != 200 {
+		t.Errorf("Expected 200 weighted issues to fail the gate, but got value=%v failed=%v err=%v", value, failed, err)
+	}
+
+	report.Summary = &types.SummaryStats{}
+	if _, failed, err := gates[0].Check(report); err != nil || failed {
+		t.Errorf("Expected no issues to pass the gate, but got failed=%v err=%v", failed, err)
+	}
+}
+
+func TestCoverageGateUsesMinCoverageThreshold(t *testing.T) {
+	gates, err := ParseGates("coverage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := NewConfig()
+	cfg.MinCoverageThreshold = 75
+	ResolveGates(gates, cfg)
+	if expected := []Gate{{Metric: "coverage", Op: "<", Threshold: 75, FromConfig: true}}; !reflect.DeepEqual(gates, expected) {
+		t.Fatalf("Expected %+v, but got %+v", expected, gates)
+	}
+	if gates[0].String() != "coverage<75" || gates[0].Leaderboard() != LeaderboardCoverage {
+		t.Errorf("Unexpected name or leaderboard for %+v", gates[0])
+	}
+
+	report := &Report{Errors: map[Leaderboard]error{}}
+	report.Leaderboards = []string{string(LeaderboardCoverage)}
+	report.Coverage = []types.CoverageEntry{{Path: "a.go", LinesCovered: 62, LinesTotal: 100, CoveragePercent: 62}}
+	report.OverallCoverage = 62
+	if value, failed, err := gates[0].Check(report); err != nil || !failed || value != 62 {
+		t.Errorf("Expected overall coverage below the threshold to fail the gate, but got value=%v failed=%v err=%v", value, failed, err)
+	}
+
+	report.OverallCoverage = 80
+	if _, failed, err := gates[0].Check(report); err != nil || failed {
+		t.Errorf("Expected overall coverage above the threshold to pass the gate, but got failed=%v err=%v", failed, err)
+	}
+
+	// Without a coverage report there is nothing to check
+	report.Coverage = nil
+	if _, _, err := gates[0].Check(report); !errors.Is(err, ErrNotMeasured) {
+		t.Errorf("Expected ErrNotMeasured without coverage data, but got %v", err)
+	}
+
+	// An explicit condition keeps its own threshold
+	gates, err = ParseGates("coverage<50")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ResolveGates(gates, cfg)
+	if gates[0].Threshold != 50 || gates[0].FromConfig {
+		t.Errorf("Expected coverage<50 to keep its threshold, but got %+v", gates[0])
+	}
+}
+
+func TestCoverageAgeGateUsesCoverageMaxAge(t *testing.T) {
+	gates, err := ParseGates("coverage-age")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := NewConfig()
+	cfg.CoverageMaxAge = 14
+	ResolveGates(gates, cfg)
+	if gates[0].String() != "coverage-age>14" || gates[0].Leaderboard() != LeaderboardCoverage {
+		t.Fatalf("Unexpected gate %+v", gates[0])
+	}
+
+	report := &Report{Errors: map[Leaderboard]error{}}
+	report.Leaderboards = []string{string(LeaderboardCoverage)}
+	if _, _, err := gates[0].Check(report); !errors.Is(err, ErrNotMeasured) {
+		t.Errorf("Expected ErrNotMeasured without a coverage report, but got %v", err)
+	}
+
+	report.CoverageFreshness = &types.CoverageFreshness{StaleDays: 23}
+	if value, failed, err := gates[0].Check(report); err != nil || !failed || value != 23 {
+		t.Errorf("Expected a report 23 days old to fail the gate, but got value=%v failed=%v err=%v", value, failed, err)
+	}
+	report.CoverageFreshness.StaleDays = 0
+	if _, failed, err := gates[0].Check(report); err != nil || failed {
+		t.Errorf("Expected a fresh report to pass the gate, but got failed=%v err=%v", failed, err)
+	}
+}
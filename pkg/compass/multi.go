@@ -0,0 +1,133 @@
+package compass
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/xeon-zolt/codecompass/internal/git"
+	"github.com/xeon-zolt/codecompass/internal/leaderboard"
+	"github.com/xeon-zolt/codecompass/internal/types"
+	"github.com/xeon-zolt/codecompass/internal/utils"
+)
+
+// multiLeaderboards are the leaderboards a report of several repositories is
+// combined from, computed for each repository whether requested or not.
+var multiLeaderboards = []Leaderboard{LeaderboardAuthors, LeaderboardFiles, LeaderboardLinesOfCode, LeaderboardCoverage, LeaderboardDebt}
+
+// RunMulti analyzes each of repos, a local path or a URL to clone, and
+// combines their reports into one: the authors merged across repositories
+// by email in Authors, with the repositories of each, and an entry per
+// repository in Repos. The report of each repository is kept in
+// RepoReports, with its path as listed in repos.
+//
+// At most parallel repositories are analyzed at once. One that cannot be
+// cloned or analyzed does not stop the others: its error is recorded in
+// RepoErrors and RepoFailures. URLs are cloned into a temporary directory
+// that is removed afterwards. opts applies to every repository, except that
+// opts.RepoPath and opts.State are ignored and opts.Progress is only called
+// for the phase "repos", counting the repositories done.
+func RunMulti(ctx context.Context, repos []string, parallel int, opts Options) (*Report, error) {
+	if len(repos) == 0 {
+		return nil, errors.New("no repositories to analyze")
+	}
+	start := time.Now()
+
+	tmp, err := os.MkdirTemp("", "codecompass-multi-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a directory for the clones: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	repoOpts := opts
+	repoOpts.Leaderboards = slices.Clone(opts.Leaderboards)
+	for _, lb := range multiLeaderboards {
+		if !slices.Contains(repoOpts.Leaderboards, lb) {
+			repoOpts.Leaderboards = append(repoOpts.Leaderboards, lb)
+		}
+	}
+	repoOpts.State = nil
+	repoOpts.Progress = nil
+
+	reports := make([]*Report, len(repos))
+	errs := make([]error, len(repos))
+	semaphore := utils.NewSemaphore(max(parallel, 1))
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	done := 0
+	opts.progress("repos", done, len(repos))
+	for i, repo := range repos {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			semaphore.Acquire()
+			defer semaphore.Release()
+
+			reports[i], errs[i] = runRepo(ctx, repo, filepath.Join(tmp, fmt.Sprintf("repo%d", i+1)), repoOpts)
+
+			mu.Lock()
+			defer mu.Unlock()
+			done++
+			opts.progress("repos", done, len(repos))
+		}()
+	}
+	wg.Wait()
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	combined := &Report{
+		Report:     types.NewReport(fmt.Sprintf("%d repositories", len(repos))),
+		Errors:     make(map[Leaderboard]error),
+		RepoErrors: make(map[string]error),
+	}
+	combined.Leaderboards = []string{string(LeaderboardAuthors)}
+	var analyzed []string
+	var authors [][]types.LeaderboardEntry
+	var languages [][]types.LanguageEntry
+	for i, repo := range repos {
+		if errs[i] != nil {
+			combined.RepoErrors[repo] = errs[i]
+			if combined.RepoFailures == nil {
+				combined.RepoFailures = make(map[string]string)
+			}
+			combined.RepoFailures[repo] = errs[i].Error()
+			continue
+		}
+
+		report := reports[i].Report
+		report.Repo.Path = repo
+		combined.Repos = append(combined.Repos, leaderboard.SummarizeRepo(repo, &report))
+		combined.RepoReports = append(combined.RepoReports, report)
+		analyzed = append(analyzed, repo)
+		authors = append(authors, report.Authors)
+		combined.Repo.TrackedFiles += report.Repo.TrackedFiles
+		combined.Repo.AnalyzedFiles += report.Repo.AnalyzedFiles
+		languages = append(languages, report.Repo.Languages)
+		for _, warning := range report.Warnings {
+			warning.Params = append([]types.WarningParam{{Key: "repo", Value: repo}}, warning.Params...)
+			combined.Warnings = append(combined.Warnings, warning)
+		}
+	}
+	combined.Authors = leaderboard.MergeAuthorLeaderboards(analyzed, authors)
+	combined.Repo.Languages = leaderboard.MergeLanguageCompositions(languages...)
+	combined.Timings["total"] = time.Since(start)
+	return combined, nil
+}
+
+// runRepo analyzes repo, cloning it to path first when it is a URL.
+func runRepo(ctx context.Context, repo, path string, opts Options) (*Report, error) {
+	opts.RepoPath = repo
+	if git.IsCloneURL(repo) {
+		if err := git.Clone(ctx, repo, path); err != nil {
+			return nil, err
+		}
+		opts.RepoPath = path
+	}
+	return Run(ctx, opts)
+}
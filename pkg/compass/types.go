@@ -0,0 +1,83 @@
+package compass
+
+import (
+	"github.com/xeon-zolt/codecompass/internal/forge"
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+// Result types reachable from Report, re-exported so callers outside this
+// module can name them.
+type (
+	RepoInfo               = types.RepoInfo
+	LeaderboardEntry       = types.LeaderboardEntry
+	FileLeaderboardEntry   = types.FileLeaderboardEntry
+	AuthorCount            = types.AuthorCount
+	RuleLeaderboardEntry   = types.RuleLeaderboardEntry
+	RulePluginEntry        = types.RulePluginEntry
+	RuleGroupEntry         = types.RuleGroupEntry
+	RuleAuthorCell         = types.RuleAuthorCell
+	LinesOfCodeEntry       = types.LinesOfCodeEntry
+	CommitCountEntry       = types.CommitCountEntry
+	RecentContributorEntry = types.RecentContributorEntry
+	CoverageEntry          = types.CoverageEntry
+	ChurnEntry             = types.ChurnEntry
+	HotspotEntry           = types.HotspotEntry
+	BugDensityEntry        = types.BugDensityEntry
+	TechnicalDebtEntry     = types.TechnicalDebtEntry
+	SpellCheckEntry        = types.SpellCheckEntry
+	SpellCheckAuthorStats  = types.SpellCheckAuthorStats
+	SpellIssue             = types.SpellIssue
+	EncodingEntry          = types.EncodingEntry
+	LongFunctionEntry      = types.LongFunctionEntry
+	FormatDriftStats       = types.FormatDriftStats
+	FormatDriftEntry       = types.FormatDriftEntry
+	FormatDriftAuthorEntry = types.FormatDriftAuthorEntry
+	ForgeStats             = types.ForgeStats
+	PullRequestAuthorEntry = types.PullRequestAuthorEntry
+	ReviewerEntry          = types.ReviewerEntry
+	LeadTimeStats          = types.LeadTimeStats
+	LeadTimeAuthorEntry    = types.LeadTimeAuthorEntry
+	ChangelogStats         = types.ChangelogStats
+	ChangelogAuthorEntry   = types.ChangelogAuthorEntry
+	ChangelogCommit        = types.ChangelogCommit
+	MetricDelta            = types.MetricDelta
+	RunTrend               = types.RunTrend
+	ToolVersionChange      = types.ToolVersionChange
+	WeeklyMerges           = types.WeeklyMerges
+	TimezoneStats          = types.TimezoneStats
+	UTCOffsetEntry         = types.UTCOffsetEntry
+	TimezoneAuthorEntry    = types.TimezoneAuthorEntry
+	DeletionStats          = types.DeletionStats
+	DeletionAuthorEntry    = types.DeletionAuthorEntry
+	CleanupCommit          = types.CleanupCommit
+	WorkspaceEntry         = types.WorkspaceEntry
+	RepoEntry              = types.RepoEntry
+	VulnEntry              = types.VulnEntry
+	DependencyEntry        = types.DependencyEntry
+	LFSStats               = types.LFSStats
+	LFSPatternEntry        = types.LFSPatternEntry
+	LFSViolation           = types.LFSViolation
+	SummaryStats           = types.SummaryStats
+	ReportCard             = types.ReportCard
+	CategoryGrade          = types.CategoryGrade
+	ScoreStats             = types.ScoreStats
+	ScoreComponent         = types.ScoreComponent
+	FileScore              = types.FileScore
+	IssueBaseline          = types.IssueBaseline
+	Suppression            = types.Suppression
+	StaleSuppression       = types.StaleSuppression
+	Warning                = types.Warning
+	WarningParam           = types.WarningParam
+)
+
+// SerializableReport is the JSON round-trippable part of a Report.
+type SerializableReport = types.Report
+
+// Issue is a single linter issue returned by a LintSource.
+type Issue = types.Issue
+
+// PullRequest and Review are returned by a Forge.
+type (
+	PullRequest = forge.PullRequest
+	Review      = forge.Review
+)
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/schollz/progressbar/v3"
+)
+
+// progressInterval is how often a counted phase prints how far it got when
+// progress is not shown on a terminal.
+const progressInterval = 10 * time.Second
+
+// phaseLabels describe the phases of a run in progress output. Other
+// phases, such as lint plugins and most leaderboards, show their name.
+var phaseLabels = map[string]string{
+	"files":        "Listing files",
+	"eslint":       "Running ESLint",
+	"ruff":         "Running Ruff",
+	"checkstyle":   "Reading checkstyle report",
+	"issues":       "Attributing issues",
+	"repos":        "Analyzing repositories",
+	"loc":          "Counting lines of code",
+	"coverage":     "Parsing coverage",
+	"churn":        "Scanning git log for churn",
+	"bugs":         "Scanning git log for bug fixes",
+	"debt":         "Scanning for technical debt",
+	"spellcheck":   "Spell checking",
+	"format-drift": "Checking Python formatting",
+	"vulns":        "Auditing dependencies",
+}
+
+func phaseLabel(phase string) string {
+	if label, ok := phaseLabels[phase]; ok {
+		return label
+	}
+	return "Running " + phase
+}
+
+// progressReporter shows the phases of a run as compass reports them. On a
+// terminal, a phase that is not counted, such as ESLint or the churn scan,
+// gets a spinner with its elapsed time, and a counted phase a bar of the
+// files or issues done. Elsewhere, where those would fill logs with control
+// codes, it prints a line as each phase starts and every progressInterval
+// while a counted phase runs.
+type progressReporter struct {
+	w   io.Writer
+	tty bool
+	now func() time.Time
+
+	phase   string
+	started time.Time
+	printed time.Time
+	bar     *progressbar.ProgressBar
+	counted bool
+}
+
+func newProgressReporter(w io.Writer, tty bool) *progressReporter {
+	return &progressReporter{w: w, tty: tty, now: time.Now}
+}
+
+// update is a compass.ProgressFunc.
+func (p *progressReporter) update(phase string, done, total int) {
+	if phase != p.phase {
+		p.finish()
+		p.phase = phase
+		p.started = p.now()
+		p.printed = p.started
+		if !p.tty {
+			fmt.Fprintf(p.w, "%s...\n", phaseLabel(phase))
+		}
+	}
+
+	if !p.tty {
+		if total > 0 && p.now().Sub(p.printed) >= progressInterval {
+			p.printed = p.now()
+			fmt.Fprintf(p.w, "%s: %d of %d (%s)\n", phaseLabel(phase), done, total, p.printed.Sub(p.started).Round(time.Second))
+		}
+		return
+	}
+
+	// A phase starts uncounted and may turn out to be counted
+	if total > 0 && !p.counted {
+		p.clear()
+		p.counted = true
+		p.bar = progressbar.NewOptions(total,
+			progressbar.OptionSetWriter(p.w),
+			progressbar.OptionSetDescription(phaseLabel(phase)),
+			progressbar.OptionSetWidth(20),
+			progressbar.OptionShowCount(),
+			progressbar.OptionSetElapsedTime(true),
+			progressbar.OptionThrottle(65*time.Millisecond),
+			progressbar.OptionClearOnFinish(),
+			progressbar.OptionSetRenderBlankState(true),
+		)
+	}
+	if p.bar == nil {
+		p.bar = progressbar.NewOptions(-1,
+			progressbar.OptionSetWriter(p.w),
+			progressbar.OptionSetDescription(phaseLabel(phase)),
+			progressbar.OptionSpinnerType(14),
+			progressbar.OptionClearOnFinish(),
+			progressbar.OptionSetRenderBlankState(true),
+		)
+	}
+	if p.counted {
+		p.bar.Set(done)
+	}
+}
+
+// finish ends the phase shown, clearing its spinner or bar.
+func (p *progressReporter) finish() {
+	p.clear()
+	p.phase = ""
+	p.counted = false
+}
+
+func (p *progressReporter) clear() {
+	if p.bar != nil {
+		p.bar.Finish()
+		p.bar = nil
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProgressReporterWithoutTerminal(t *testing.T) {
+	var out bytes.Buffer
+	reporter := newProgressReporter(&out, false)
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	reporter.now = func() time.Time { return now }
+
+	reporter.update("eslint", 0, 0)
+	now = now.Add(time.Minute)
+	reporter.update("debt", 0, 0)
+	reporter.update("debt", 10, 400)
+	now = now.Add(4 * time.Second)
+	reporter.update("debt", 20, 400)
+	now = now.Add(8 * time.Second)
+	reporter.update("debt", 150, 400)
+	reporter.update("debt", 160, 400)
+	reporter.finish()
+
+	// Opaque phases only say they started; counted ones print at most
+	// every progressInterval
+	expected := "Running ESLint...\nScanning for technical debt...\nScanning for technical debt: 150 of 400 (12s)\n"
+	if out.String() != expected {
+		t.Errorf("Expected progress lines\n%s\nbut got\n%s", expected, out.String())
+	}
+}
+
+func TestProgressReporterOnTerminal(t *testing.T) {
+	var out bytes.Buffer
+	reporter := newProgressReporter(&out, true)
+
+	reporter.update("churn", 0, 0)
+	reporter.update("loc", 0, 0)
+	reporter.update("loc", 3, 3)
+	reporter.finish()
+
+	if !strings.Contains(out.String(), "Scanning git log for churn") || !strings.Contains(out.String(), "Counting lines of code") {
+		t.Errorf("Expected a spinner and a bar for the phases, but got %q", out.String())
+	}
+	if reporter.bar != nil {
+		t.Errorf("Expected no bar after the run finished")
+	}
+	if phaseLabel("todo") != "Running todo" {
+		t.Errorf("Expected plugins to be labeled by name, but got %q", phaseLabel("todo"))
+	}
+}
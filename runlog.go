@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/xeon-zolt/codecompass/internal/config"
+	"github.com/xeon-zolt/codecompass/internal/git"
+	"github.com/xeon-zolt/codecompass/internal/history"
+	reporting "github.com/xeon-zolt/codecompass/internal/report"
+	"github.com/xeon-zolt/codecompass/internal/types"
+)
+
+// runLog records this run in the run log, nil when runs are not logged.
+// Its methods do nothing on nil, so the run does not check.
+var runLog *runLogger
+
+// runLogger appends the record of a run to the run log when it ends.
+type runLogger struct {
+	logger *slog.Logger
+	path   string
+	record history.RunRecord
+	done   bool
+}
+
+// startRunLog starts the record of a run analyzing the repository in dir,
+// to be appended to the run log at path.
+func startRunLog(logger *slog.Logger, path, dir string) *runLogger {
+	l := &runLogger{logger: logger, path: path, record: history.RunRecord{Time: time.Now(), Flags: setFlags()}}
+
+	// The run may fail before the analysis tells what it analyzed
+	ctx := context.Background()
+	if dir == "" {
+		dir = "."
+	}
+	if head, err := git.GetHead(ctx, dir); err == nil {
+		l.record.Head = head
+	}
+	// Untracked files are left out, or the history the run logs would
+	// make the next one dirty
+	if status, err := git.GetWorkTreeStatus(ctx, dir); err == nil {
+		l.record.Dirty = len(status.Modified) > 0
+	}
+	return l
+}
+
+// setFlags maps the command-line flags that were set to their values, with
+// those of secrets such as --webhook-token hidden.
+func setFlags() map[string]string {
+	flags := make(map[string]string)
+	flag.Visit(func(f *flag.Flag) {
+		value := f.Value.String()
+		if strings.Contains(f.Name, "token") {
+			value = "redacted"
+		}
+		flags[f.Name] = value
+	})
+	return flags
+}
+
+// setConfig records a fingerprint of the effective configuration of the
+// run.
+func (l *runLogger) setConfig(cfg *config.Config) {
+	if l == nil {
+		return
+	}
+	var settings strings.Builder
+	if err := cfg.WriteEffective(&settings); err == nil {
+		l.record.ConfigFingerprint = reporting.InputHash(settings.String())
+	}
+}
+
+// addReport records what the analysis of the run found.
+func (l *runLogger) addReport(report *types.Report) {
+	if l == nil {
+		return
+	}
+	l.record.AddReport(report)
+}
+
+// finish appends the record of the run, which exited with status, and
+// with message when it failed. Only the first call appends it.
+func (l *runLogger) finish(status int, message string) {
+	if l == nil || l.done {
+		return
+	}
+	l.done = true
+
+	l.record.Duration = time.Since(l.record.Time)
+	l.record.ExitStatus = status
+	l.record.Error = message
+	if err := history.AppendRunLog(l.path, l.record); err != nil {
+		l.logger.Warn("Failed to append to the run log", "file", l.path, "error", err)
+	}
+}
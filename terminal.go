@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// decorationMode is the --decorations setting: whether the compass art and
+// the compass on section headings are printed.
+type decorationMode string
+
+const (
+	decorationsAuto decorationMode = "auto" // only on a terminal
+	decorationsOn   decorationMode = "on"
+	decorationsOff  decorationMode = "off"
+)
+
+func parseDecorationMode(value string) (decorationMode, error) {
+	switch mode := decorationMode(value); mode {
+	case decorationsAuto, decorationsOn, decorationsOff:
+		return mode, nil
+	}
+	return "", fmt.Errorf("invalid decorations %q: expected auto, on or off", value)
+}
+
+// isTerminal reports whether f is a terminal rather than a pipe, a file or
+// the log of a CI job.
+func isTerminal(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd()))
+}
+
+// decorate reports whether output written to f gets decorations under mode.
+// Decorations only clutter piped output and CI logs, so auto leaves them
+// out there.
+func decorate(mode decorationMode, f *os.File) bool {
+	switch mode {
+	case decorationsOn:
+		return true
+	case decorationsOff:
+		return false
+	}
+	return isTerminal(f)
+}